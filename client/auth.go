@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"connectrpc.com/connect"
+	authv1 "github.com/yourorg/anonymous-support/gen/auth/v1"
+	"github.com/yourorg/anonymous-support/gen/auth/v1/authv1connect"
+)
+
+var errNoRefreshToken = errors.New("access token expired and there is no refresh token")
+
+// Tokens holds a user's access and refresh tokens. When the API rejects the
+// access token, the call refreshes it with AuthService.RefreshToken and is
+// sent once more. Tokens is safe for concurrent use, and calls that find the
+// same token expired share one refresh.
+type Tokens struct {
+	mu        sync.Mutex
+	access    string
+	refresh   string
+	refresher func(ctx context.Context, refreshToken string) (string, error)
+
+	// OnRefresh, if set, is called with each refreshed access token, e.g. to
+	// persist it
+	OnRefresh func(accessToken string)
+}
+
+// NewTokens creates tokens from a login or registration response
+func NewTokens(accessToken, refreshToken string) *Tokens {
+	return &Tokens{access: accessToken, refresh: refreshToken}
+}
+
+// Set replaces both tokens, e.g. after logging in again
+func (t *Tokens) Set(accessToken, refreshToken string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.access, t.refresh = accessToken, refreshToken
+}
+
+// AccessToken returns the current access token
+func (t *Tokens) AccessToken() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.access
+}
+
+// refreshAfter refreshes the access token unless another call already
+// replaced stale, and returns the current one
+func (t *Tokens) refreshAfter(ctx context.Context, stale string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.access != stale {
+		return t.access, nil
+	}
+	if t.refresh == "" {
+		return "", errNoRefreshToken
+	}
+
+	access, err := t.refresher(ctx, t.refresh)
+	if err != nil {
+		return "", err
+	}
+	t.access = access
+	if t.OnRefresh != nil {
+		t.OnRefresh(access)
+	}
+	return access, nil
+}
+
+func refreshWith(auth authv1connect.AuthServiceClient) func(context.Context, string) (string, error) {
+	return func(ctx context.Context, refreshToken string) (string, error) {
+		resp, err := auth.RefreshToken(ctx, connect.NewRequest(&authv1.RefreshTokenRequest{RefreshToken: refreshToken}))
+		if err != nil {
+			return "", fmt.Errorf("failed to refresh access token: %w", err)
+		}
+		return resp.Msg.AccessToken, nil
+	}
+}
+
+// authInterceptor sends the user's access token or the service API key
+type authInterceptor struct {
+	tokens *Tokens
+	apiKey string
+}
+
+func newAuthInterceptor(tokens *Tokens, apiKey string) *authInterceptor {
+	return &authInterceptor{tokens: tokens, apiKey: apiKey}
+}
+
+func (i *authInterceptor) setCredentials(header http.Header, accessToken string) {
+	if accessToken != "" {
+		header.Set("Authorization", "Bearer "+accessToken)
+	}
+	if i.apiKey != "" {
+		header.Set("X-API-Key", i.apiKey)
+	}
+}
+
+// WrapUnary authenticates a call, refreshing the access token and sending the
+// call again if the API rejects it
+func (i *authInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if i.tokens == nil {
+			i.setCredentials(req.Header(), "")
+			return next(ctx, req)
+		}
+
+		token := i.tokens.AccessToken()
+		i.setCredentials(req.Header(), token)
+		resp, err := next(ctx, req)
+		if connect.CodeOf(err) != connect.CodeUnauthenticated || token == "" {
+			return resp, err
+		}
+
+		token, refreshErr := i.tokens.refreshAfter(ctx, token)
+		if refreshErr != nil {
+			return nil, connect.NewError(connect.CodeUnauthenticated, errors.Join(err, refreshErr))
+		}
+		i.setCredentials(req.Header(), token)
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient authenticates a stream with the current access token,
+// without refreshing it
+func (i *authInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		token := ""
+		if i.tokens != nil {
+			token = i.tokens.AccessToken()
+		}
+		i.setCredentials(conn.RequestHeader(), token)
+		return conn
+	}
+}
+
+// WrapStreamingHandler is a no-op; the interceptor is only used by clients
+func (i *authInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
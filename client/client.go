@@ -0,0 +1,94 @@
+// Package client is a typed Go client for the API. It wraps the generated
+// Connect clients with bearer authentication that refreshes expired access
+// tokens, retries with backoff, and idempotency keys, so internal tools don't
+// hand-roll HTTP calls.
+package client
+
+import (
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/yourorg/anonymous-support/gen/admin/v1/adminv1connect"
+	"github.com/yourorg/anonymous-support/gen/analytics/v1/analyticsv1connect"
+	"github.com/yourorg/anonymous-support/gen/auth/v1/authv1connect"
+	"github.com/yourorg/anonymous-support/gen/circle/v1/circlev1connect"
+	"github.com/yourorg/anonymous-support/gen/home/v1/homev1connect"
+	"github.com/yourorg/anonymous-support/gen/journal/v1/journalv1connect"
+	"github.com/yourorg/anonymous-support/gen/moderation/v1/moderationv1connect"
+	"github.com/yourorg/anonymous-support/gen/notification/v1/notificationv1connect"
+	"github.com/yourorg/anonymous-support/gen/post/v2/postv2connect"
+	"github.com/yourorg/anonymous-support/gen/progress/v1/progressv1connect"
+	"github.com/yourorg/anonymous-support/gen/resources/v1/resourcesv1connect"
+	"github.com/yourorg/anonymous-support/gen/support/v1/supportv1connect"
+	"github.com/yourorg/anonymous-support/gen/user/v1/userv1connect"
+)
+
+// Options configures a Client. The zero value makes unauthenticated calls
+// with the default retry policy.
+type Options struct {
+	// HTTPClient sends requests; http.DefaultClient when nil. Pass a client
+	// with TLS client certificates to authenticate as a service principal.
+	HTTPClient connect.HTTPClient
+	// Tokens authenticates calls as a user. Set at most one of Tokens and APIKey.
+	Tokens *Tokens
+	// APIKey authenticates calls as a service principal with X-API-Key
+	APIKey string
+	// Retry controls retries of failed unary calls
+	Retry RetryPolicy
+	// ConnectOptions are passed to every generated client, e.g. connect.WithGRPC()
+	ConnectOptions []connect.ClientOption
+}
+
+// Client holds a typed client for each API service. Posts are served by
+// post.v2; post.v1 is deprecated.
+type Client struct {
+	Auth         authv1connect.AuthServiceClient
+	User         userv1connect.UserServiceClient
+	Post         postv2connect.PostServiceClient
+	Support      supportv1connect.SupportServiceClient
+	Circle       circlev1connect.CircleServiceClient
+	Moderation   moderationv1connect.ModerationServiceClient
+	Notification notificationv1connect.NotificationServiceClient
+	Journal      journalv1connect.JournalServiceClient
+	Progress     progressv1connect.ProgressServiceClient
+	Analytics    analyticsv1connect.AnalyticsServiceClient
+	Admin        adminv1connect.AdminServiceClient
+	Home         homev1connect.HomeServiceClient
+	Resources    resourcesv1connect.ResourcesServiceClient
+}
+
+// New creates a client for the API at baseURL, e.g. "https://api.example.com"
+func New(baseURL string, opts Options) *Client {
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	// Token refreshes go through their own client, so they aren't themselves
+	// authenticated or refreshed
+	if opts.Tokens != nil && opts.Tokens.refresher == nil {
+		auth := authv1connect.NewAuthServiceClient(httpClient, baseURL, opts.ConnectOptions...)
+		opts.Tokens.refresher = refreshWith(auth)
+	}
+
+	// Retries wrap authentication, so each attempt sends the latest token
+	clientOpts := append([]connect.ClientOption{
+		connect.WithInterceptors(newRetryInterceptor(opts.Retry), newAuthInterceptor(opts.Tokens, opts.APIKey)),
+	}, opts.ConnectOptions...)
+
+	return &Client{
+		Auth:         authv1connect.NewAuthServiceClient(httpClient, baseURL, clientOpts...),
+		User:         userv1connect.NewUserServiceClient(httpClient, baseURL, clientOpts...),
+		Post:         postv2connect.NewPostServiceClient(httpClient, baseURL, clientOpts...),
+		Support:      supportv1connect.NewSupportServiceClient(httpClient, baseURL, clientOpts...),
+		Circle:       circlev1connect.NewCircleServiceClient(httpClient, baseURL, clientOpts...),
+		Moderation:   moderationv1connect.NewModerationServiceClient(httpClient, baseURL, clientOpts...),
+		Notification: notificationv1connect.NewNotificationServiceClient(httpClient, baseURL, clientOpts...),
+		Journal:      journalv1connect.NewJournalServiceClient(httpClient, baseURL, clientOpts...),
+		Progress:     progressv1connect.NewProgressServiceClient(httpClient, baseURL, clientOpts...),
+		Analytics:    analyticsv1connect.NewAnalyticsServiceClient(httpClient, baseURL, clientOpts...),
+		Admin:        adminv1connect.NewAdminServiceClient(httpClient, baseURL, clientOpts...),
+		Home:         homev1connect.NewHomeServiceClient(httpClient, baseURL, clientOpts...),
+		Resources:    resourcesv1connect.NewResourcesServiceClient(httpClient, baseURL, clientOpts...),
+	}
+}
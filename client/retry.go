@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/yourorg/anonymous-support/internal/pkg/retry"
+)
+
+// IdempotencyKeyHeader carries a key that's the same for every attempt of a
+// call, so the server can recognise a retried write
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxRetryAfter is the longest Retry-After a call waits out; calls asked to
+// wait longer fail instead
+const maxRetryAfter = 30 * time.Second
+
+// RetryPolicy controls how failed unary calls are retried with exponential
+// backoff. Zero fields use the defaults of the retry package.
+type RetryPolicy struct {
+	MaxAttempts    int // Including the first; 1 disables retries
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	defaults := retry.DefaultConfig()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaults.InitialDelay
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaults.MaxDelay
+	}
+	return p
+}
+
+// retryInterceptor retries unary calls that failed transiently. Reads and
+// other idempotent procedures are retried on any transient failure; writes
+// only when the server turned them away before handling them.
+type retryInterceptor struct {
+	policy RetryPolicy
+}
+
+func newRetryInterceptor(policy RetryPolicy) *retryInterceptor {
+	return &retryInterceptor{policy: policy.withDefaults()}
+}
+
+// WrapUnary retries a call with backoff, sending the same idempotency key on
+// every attempt
+func (i *retryInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Header().Get(IdempotencyKeyHeader) == "" {
+			req.Header().Set(IdempotencyKeyHeader, newIdempotencyKey())
+		}
+		idempotent := req.Spec().IdempotencyLevel != connect.IdempotencyUnknown
+
+		backoff := i.policy.InitialBackoff
+		for attempt := 1; ; attempt++ {
+			resp, err := next(ctx, req)
+			if err == nil || attempt >= i.policy.MaxAttempts || !retryable(err, idempotent) {
+				return resp, err
+			}
+
+			wait := backoff
+			if retryAfter, ok := retryAfter(err); ok {
+				if retryAfter > maxRetryAfter {
+					return resp, err
+				}
+				wait = retryAfter
+			}
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, err
+			}
+			backoff = min(2*backoff, i.policy.MaxBackoff)
+		}
+	}
+}
+
+// WrapStreamingClient leaves streams unchanged; they can't be replayed
+func (i *retryInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler is a no-op; the interceptor is only used by clients
+func (i *retryInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// retryable reports whether a failed call can be sent again. Rate-limited and
+// shed requests never reached a handler, so writes can be retried too.
+func retryable(err error, idempotent bool) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeResourceExhausted, connect.CodeUnavailable:
+		return true
+	case connect.CodeDeadlineExceeded, connect.CodeAborted, connect.CodeUnknown:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// retryAfter returns the wait the server asked for with a Retry-After header
+// in seconds
+func retryAfter(err error) (time.Duration, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return 0, false
+	}
+	seconds, parseErr := strconv.Atoi(connectErr.Meta().Get("Retry-After"))
+	if parseErr != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
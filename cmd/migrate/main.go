@@ -0,0 +1,188 @@
+// Command migrate applies PostgreSQL and MongoDB schema migrations. It reads
+// the same configuration as the server, so it can run as a CI step or a
+// deploy init container with the server's environment.
+//
+//	migrate [flags] up            apply pending migrations
+//	migrate [flags] down [N]      roll back the last N migrations (default 1)
+//	migrate [flags] status        list migrations as applied, pending or modified since applied
+//	migrate [flags] force VERSION record VERSION as current without running anything
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/yourorg/anonymous-support/internal/config"
+	"github.com/yourorg/anonymous-support/internal/pkg/migrations"
+	sqlmigrations "github.com/yourorg/anonymous-support/migrations"
+)
+
+// migrator is implemented by the PostgreSQL and MongoDB migrators
+type migrator interface {
+	Up(ctx context.Context) error
+	Down(ctx context.Context, steps int) error
+	Status(ctx context.Context) ([]migrations.MigrationStatus, error)
+	Force(ctx context.Context, version int) error
+}
+
+func main() {
+	configFile := flag.String("config", "", "YAML, TOML or JSON config file; defaults to $CONFIG_FILE. Environment variables override it.")
+	store := flag.String("store", "all", "Store to migrate: postgres, mongo or all")
+	path := flag.String("path", "", "Directory of PostgreSQL migration files; defaults to the ones built in")
+	timeout := flag.Duration("timeout", 10*time.Minute, "Give up after this long")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: migrate [flags] up | down [N] | status | force VERSION\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	command, args := flag.Arg(0), flag.Args()
+	if command == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *store != "postgres" && *store != "mongo" && *store != "all" {
+		log.Fatalf("Unknown store %q; use postgres, mongo or all", *store)
+	}
+	if (command == "down" || command == "force") && *store == "all" {
+		log.Fatalf("%s needs -store postgres or -store mongo", command)
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("Failed to create logger:", err)
+	}
+	defer func() { _ = logger.Sync() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// PostgreSQL first, then MongoDB
+	var stores []string
+	if *store == "postgres" || *store == "all" {
+		stores = append(stores, "postgres")
+	}
+	if *store == "mongo" || *store == "all" {
+		stores = append(stores, "mongo")
+	}
+
+	for _, name := range stores {
+		m, closeStore, err := openMigrator(ctx, name, cfg, *path, logger)
+		if err != nil {
+			logger.Fatal("Failed to open store", zap.String("store", name), zap.Error(err))
+		}
+		err = run(ctx, m, name, command, args[1:])
+		closeStore()
+		if err != nil {
+			logger.Fatal("Migration failed", zap.String("store", name), zap.String("command", command), zap.Error(err))
+		}
+	}
+}
+
+// openMigrator connects to a store and returns its migrator and a function closing the connection
+func openMigrator(ctx context.Context, store string, cfg *config.Config, path string, logger *zap.Logger) (migrator, func(), error) {
+	if store == "postgres" {
+		db, err := sqlx.ConnectContext(ctx, "postgres", cfg.Postgres.DSN())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+		}
+		files := sqlmigrations.Postgres
+		if path != "" {
+			files = os.DirFS(path)
+		}
+		m, err := migrations.NewPostgresMigrator(db.DB, files, logger)
+		if err != nil {
+			_ = db.Close()
+			return nil, nil, err
+		}
+		return m, func() { _ = db.Close() }, nil
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoDB.URI))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+	m := migrations.NewMongoMigrator(client.Database(cfg.MongoDB.Database), logger)
+	for _, migration := range migrations.GetMongoMigrations() {
+		m.Register(migration)
+	}
+	return m, func() { _ = client.Disconnect(context.Background()) }, nil
+}
+
+// run executes one command against one store
+func run(ctx context.Context, m migrator, store, command string, args []string) error {
+	switch command {
+	case "up":
+		return m.Up(ctx)
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("down takes a positive number of migrations, got %q", args[0])
+			}
+			steps = n
+		}
+		return m.Down(ctx, steps)
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if pg, ok := m.(*migrations.PostgresMigrator); ok {
+			if version, dirty, err := pg.Version(ctx); err == nil && dirty {
+				fmt.Printf("postgres: version %d is dirty; repair it, then force the version\n", version)
+			}
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, status := range statuses {
+			state := "pending"
+			if status.Modified {
+				state = "modified"
+			} else if status.Applied {
+				state = "applied"
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", store, status.Version, state, status.Description)
+		}
+		return w.Flush()
+
+	case "force":
+		if len(args) == 0 {
+			return fmt.Errorf("force needs a version")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < 0 {
+			return fmt.Errorf("force takes a version, got %q", args[0])
+		}
+		return m.Force(ctx, version)
+	}
+
+	return fmt.Errorf("unknown command %q", command)
+}
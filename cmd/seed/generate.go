@@ -0,0 +1,320 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sizes is how much data to generate
+type sizes struct {
+	Users   int
+	Circles int
+	Posts   int
+}
+
+// membership is a user's place in a circle
+type membership struct {
+	CircleID uuid.UUID
+	UserID   uuid.UUID
+	Role     domain.CircleRole
+	JoinedAt time.Time
+}
+
+// dataset is everything one seed run writes
+type dataset struct {
+	Users       []*domain.User
+	Circles     []*domain.Circle
+	Memberships []membership
+	Posts       []*domain.Post
+	Responses   []*domain.SupportResponse
+	Trackers    []*domain.UserTracker
+}
+
+var (
+	usernameAdjectives = []string{"quiet", "brave", "steady", "hopeful", "gentle", "bright", "calm", "patient", "kind", "strong", "early", "honest"}
+	usernameNouns      = []string{"river", "oak", "harbor", "lantern", "sparrow", "meadow", "summit", "ember", "tide", "compass", "willow", "pine"}
+
+	circleTemplates = []struct {
+		name, description, category string
+		maxMembers                  int
+	}{
+		{"Daily Check-In", "Daily accountability and support", "general", 1000},
+		{"Evening Warriors", "Support for evening triggers", "alcohol", 500},
+		{"Early Recovery", "For those in their first 90 days", "general", 200},
+		{"Long-term Sobriety", "For those with a year or more", "milestone", 300},
+		{"Smoke Free Together", "Quitting cigarettes and vaping", "nicotine", 300},
+		{"Beyond the Bet", "Recovery from gambling", "gambling", 200},
+		{"Calm Minds", "Working through anxiety one day at a time", "anxiety", 400},
+		{"Parents in Recovery", "Balancing family life and recovery", "general", 150},
+		{"Weekend Plan", "Staying on track through weekends", "alcohol", 250},
+		{"Screen Time Reset", "Cutting back on compulsive scrolling", "digital", 200},
+	}
+
+	categories = []string{"alcohol", "nicotine", "gambling", "anxiety", "digital", "general"}
+
+	postTemplates = map[domain.PostType][]string{
+		domain.PostTypeSOS: {
+			"Having strong cravings right now. Could really use some support.",
+			"Rough day at work and all I can think about is using. Please talk me through this.",
+			"Friends invited me out tonight and I don't trust myself. What should I do?",
+			"I'm alone this weekend and the urge is loud. Anyone around?",
+		},
+		domain.PostTypeCheckIn: {
+			"Day %d. Tired but holding on.",
+			"Checking in on day %d. Went for a walk instead of giving in.",
+			"Day %d and feeling grateful for this community.",
+			"Day %d. Slept well for the first time in a while.",
+		},
+		domain.PostTypeVictory: {
+			"Made it through my first weekend in years without slipping!",
+			"Said no at a party tonight and it felt amazing.",
+			"Hit %d days today. Didn't think I'd get here.",
+			"Told my family about my recovery and they were so supportive.",
+		},
+		domain.PostTypeQuestion: {
+			"How do you handle social situations where everyone else is drinking?",
+			"What do you do in the first ten minutes of a craving?",
+			"Any tips for sleeping better in early recovery?",
+			"How did you rebuild trust with people you'd let down?",
+		},
+	}
+
+	textResponses = []string{
+		"You reached out, and that's a huge step. Stay with us for the next hour and it will pass.",
+		"I've been there. Drinking a glass of cold water and stepping outside helped me a lot.",
+		"Proud of you for posting instead of giving in. What's one small thing you can do right now?",
+		"Cravings peak and fade. Set a timer for fifteen minutes and check back in with us.",
+		"That's a real win. Take a moment to notice how good it feels.",
+		"Great question. Having an exit plan and a drink in hand that isn't alcohol made parties easier for me.",
+	}
+	quickResponses = []string{"Sending strength", "You've got this", "Proud of you", "Here with you"}
+
+	timeContexts = []string{"morning", "afternoon", "evening", "night"}
+)
+
+// generate builds a dataset from rng. The same seed, sizes and now always
+// produce the same dataset.
+func generate(rng *rand.Rand, n sizes, now time.Time) *dataset {
+	d := &dataset{}
+	newID := func() uuid.UUID {
+		id, err := uuid.NewRandomFromReader(rng)
+		if err != nil {
+			panic(err) // rand.Rand never fails to read
+		}
+		return id
+	}
+	newObjectID := func(at time.Time) primitive.ObjectID {
+		id := primitive.NewObjectIDFromTimestamp(at)
+		_, _ = rng.Read(id[4:])
+		return id
+	}
+	// between returns a time from the later of from and now-maxDays until now
+	between := func(from time.Time, maxDays int) time.Time {
+		if earliest := now.AddDate(0, 0, -maxDays); from.Before(earliest) {
+			from = earliest
+		}
+		return from.Add(time.Duration(rng.Int63n(int64(now.Sub(from)) + 1)))
+	}
+
+	// Users, the first two an admin and a moderator for trying out staff tools
+	for i := 0; i < n.Users; i++ {
+		username := fmt.Sprintf("%s_%s_%d",
+			usernameAdjectives[rng.Intn(len(usernameAdjectives))],
+			usernameNouns[rng.Intn(len(usernameNouns))],
+			i+1)
+		role := domain.RoleUser
+		switch i {
+		case 0:
+			username, role = "demo_admin", domain.RoleAdmin
+		case 1:
+			username, role = "demo_moderator", domain.RoleModerator
+		}
+		createdAt := between(time.Time{}, 180)
+		d.Users = append(d.Users, &domain.User{
+			ID:           newID(),
+			Username:     username,
+			AvatarID:     rng.Intn(12) + 1,
+			Role:         role,
+			IsAnonymous:  false,
+			CreatedAt:    createdAt,
+			LastActiveAt: between(createdAt, 180),
+		})
+	}
+	if len(d.Users) == 0 {
+		return d
+	}
+
+	// Circles, each with its creator as owner and a share of the users as members
+	for i := 0; i < n.Circles; i++ {
+		template := circleTemplates[i%len(circleTemplates)]
+		name := template.name
+		if i >= len(circleTemplates) {
+			name = fmt.Sprintf("%s %d", template.name, i/len(circleTemplates)+1)
+		}
+		owner := d.Users[rng.Intn(len(d.Users))]
+		circle := &domain.Circle{
+			ID:          newID(),
+			Name:        name,
+			Description: template.description,
+			Category:    template.category,
+			MaxMembers:  template.maxMembers,
+			IsPrivate:   rng.Intn(5) == 0,
+			CreatedBy:   owner.ID,
+			CreatedAt:   between(owner.CreatedAt, 120),
+		}
+		d.Circles = append(d.Circles, circle)
+		d.Memberships = append(d.Memberships, membership{CircleID: circle.ID, UserID: owner.ID, Role: domain.CircleRoleOwner, JoinedAt: circle.CreatedAt})
+		circle.MemberCount = 1
+
+		for _, user := range d.Users {
+			if user.ID == owner.ID || rng.Intn(4) != 0 || circle.MemberCount >= circle.MaxMembers {
+				continue
+			}
+			role := domain.CircleRoleMember
+			if rng.Intn(10) == 0 {
+				role = domain.CircleRoleModerator
+			}
+			joinedAt := between(circle.CreatedAt, 120)
+			if joinedAt.Before(user.CreatedAt) {
+				joinedAt = between(user.CreatedAt, 120)
+			}
+			d.Memberships = append(d.Memberships, membership{CircleID: circle.ID, UserID: user.ID, Role: role, JoinedAt: joinedAt})
+			circle.MemberCount++
+		}
+	}
+
+	// Trackers, with streaks the check-in posts refer to
+	trackers := make(map[string]*domain.UserTracker, len(d.Users))
+	for _, user := range d.Users {
+		streak := rng.Intn(120)
+		relapses := rng.Intn(6)
+		checkIn := now.Add(-time.Duration(rng.Intn(48)) * time.Hour)
+		tracker := &domain.UserTracker{
+			UserID:               user.ID.String(),
+			StreakDays:           streak,
+			LongestStreak:        streak + rng.Intn(60),
+			TotalDaysClean:       streak + rng.Intn(200),
+			TotalRelapses:        relapses,
+			LastCheckInAt:        &checkIn,
+			TotalCravings:        rng.Intn(40),
+			VulnerabilityPattern: map[string]int{fmt.Sprintf("%02d", 18+rng.Intn(6)): rng.Intn(10) + 1},
+			Categories:           []string{categories[rng.Intn(len(categories))]},
+			Goals:                []domain.Goal{},
+			Milestones:           []domain.Milestone{},
+		}
+		tracker.CravingsResisted = tracker.TotalCravings * (rng.Intn(40) + 60) / 100
+		if relapses > 0 {
+			relapse := now.AddDate(0, 0, -streak)
+			tracker.LastRelapseDate = &relapse
+		}
+		trackers[tracker.UserID] = tracker
+		d.Trackers = append(d.Trackers, tracker)
+	}
+
+	// Posts over the last month, some in circles, each with a few responses
+	postTypes := []domain.PostType{domain.PostTypeCheckIn, domain.PostTypeCheckIn, domain.PostTypeVictory, domain.PostTypeQuestion, domain.PostTypeSOS}
+	for i := 0; i < n.Posts; i++ {
+		author := d.Users[rng.Intn(len(d.Users))]
+		tracker := trackers[author.ID.String()]
+		postType := postTypes[rng.Intn(len(postTypes))]
+		content := postTemplates[postType][rng.Intn(len(postTemplates[postType]))]
+		if strings.Contains(content, "%d") {
+			content = fmt.Sprintf(content, tracker.StreakDays+1)
+		}
+
+		urgency := 1 + rng.Intn(2)
+		if postType == domain.PostTypeSOS {
+			urgency = 4 + rng.Intn(2)
+		}
+		createdAt := between(author.CreatedAt, 30)
+		expiresAt := createdAt.Add(30 * 24 * time.Hour)
+		post := &domain.Post{
+			ID:           newObjectID(createdAt),
+			UserID:       author.ID.String(),
+			Username:     author.Username,
+			Type:         postType,
+			Content:      content,
+			Categories:   []string{tracker.Categories[0]},
+			UrgencyLevel: urgency,
+			Context: domain.PostContext{
+				DaysSinceRelapse: tracker.StreakDays,
+				TimeContext:      timeContexts[rng.Intn(len(timeContexts))],
+				Tags:             []string{},
+			},
+			Visibility:      "public",
+			CreatedAt:       createdAt,
+			ExpiresAt:       &expiresAt,
+			ModerationState: domain.ModerationStateVisible,
+		}
+		if circle := circleOf(rng, d, author.ID); circle != nil && rng.Intn(3) == 0 {
+			circleID := circle.ID.String()
+			post.CircleID = &circleID
+			post.Visibility = "circle"
+		}
+		d.Posts = append(d.Posts, post)
+
+		for j, count := 0, rng.Intn(5); j < count; j++ {
+			responder := d.Users[rng.Intn(len(d.Users))]
+			if responder.ID == author.ID {
+				continue
+			}
+			response := &domain.SupportResponse{
+				PostID:    post.ID.Hex(),
+				UserID:    responder.ID.String(),
+				Username:  responder.Username,
+				CreatedAt: createdAt.Add(time.Duration(rng.Intn(180)+1) * time.Minute),
+			}
+			if response.CreatedAt.After(now) {
+				response.CreatedAt = now
+			}
+			if rng.Intn(2) == 0 {
+				response.Type = domain.ResponseTypeQuick
+				response.Content = quickResponses[rng.Intn(len(quickResponses))]
+				response.StrengthPoints = 1
+				post.SupportCount++
+			} else {
+				response.Type = domain.ResponseTypeText
+				response.Content = textResponses[rng.Intn(len(textResponses))]
+				response.StrengthPoints = 3
+				if len(response.Content) > 100 {
+					response.StrengthPoints = 5
+				}
+				post.ResponseCount++
+			}
+			response.ID = newObjectID(response.CreatedAt)
+			d.Responses = append(d.Responses, response)
+
+			responder.StrengthPoints += response.StrengthPoints
+			trackers[responder.ID.String()].SupportGiven++
+			tracker.SupportReceived++
+		}
+	}
+
+	return d
+}
+
+// circleOf returns one of the user's circles, or nil if they're in none
+func circleOf(rng *rand.Rand, d *dataset, userID uuid.UUID) *domain.Circle {
+	var circleIDs []uuid.UUID
+	for _, m := range d.Memberships {
+		if m.UserID == userID {
+			circleIDs = append(circleIDs, m.CircleID)
+		}
+	}
+	if len(circleIDs) == 0 {
+		return nil
+	}
+	id := circleIDs[rng.Intn(len(circleIDs))]
+	for _, circle := range d.Circles {
+		if circle.ID == id {
+			return circle
+		}
+	}
+	return nil
+}
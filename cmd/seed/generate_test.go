@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	n := sizes{Users: 20, Circles: 4, Posts: 50}
+
+	a := generate(rand.New(rand.NewSource(7)), n, now)
+	b := generate(rand.New(rand.NewSource(7)), n, now)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("the same seed generated different data")
+	}
+
+	c := generate(rand.New(rand.NewSource(8)), n, now)
+	if reflect.DeepEqual(a.Users, c.Users) {
+		t.Fatal("different seeds generated the same users")
+	}
+}
+
+func TestGenerateIsConsistent(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	d := generate(rand.New(rand.NewSource(1)), sizes{Users: 30, Circles: 12, Posts: 100}, now)
+
+	if len(d.Users) != 30 || len(d.Circles) != 12 || len(d.Posts) != 100 || len(d.Trackers) != 30 {
+		t.Fatalf("got %d users, %d circles, %d posts, %d trackers", len(d.Users), len(d.Circles), len(d.Posts), len(d.Trackers))
+	}
+
+	usernames := make(map[string]bool)
+	for _, user := range d.Users {
+		if usernames[user.Username] {
+			t.Errorf("duplicate username %s", user.Username)
+		}
+		usernames[user.Username] = true
+	}
+
+	circleNames := make(map[string]bool)
+	for _, circle := range d.Circles {
+		if circleNames[circle.Name] {
+			t.Errorf("duplicate circle name %s", circle.Name)
+		}
+		circleNames[circle.Name] = true
+
+		members := 0
+		for _, m := range d.Memberships {
+			if m.CircleID == circle.ID {
+				members++
+			}
+		}
+		if members != circle.MemberCount {
+			t.Errorf("circle %s has %d memberships but a member count of %d", circle.Name, members, circle.MemberCount)
+		}
+	}
+
+	posts := make(map[string]time.Time)
+	for _, post := range d.Posts {
+		if post.CreatedAt.After(now) {
+			t.Errorf("post %s is in the future", post.ID.Hex())
+		}
+		posts[post.ID.Hex()] = post.CreatedAt
+	}
+	for _, response := range d.Responses {
+		createdAt, ok := posts[response.PostID]
+		if !ok {
+			t.Fatalf("response %s answers unknown post %s", response.ID.Hex(), response.PostID)
+		}
+		if response.CreatedAt.Before(createdAt) || response.CreatedAt.After(now) {
+			t.Errorf("response %s is outside its post's lifetime", response.ID.Hex())
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Command seed fills the databases with realistic users, circles, posts,
+// responses and trackers so development and demo environments don't start
+// empty. The same -seed always generates the same data.
+//
+// Every seeded account logs in with its username and the -password flag;
+// demo_admin and demo_moderator have staff roles.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yourorg/anonymous-support/internal/config"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/encryption"
+	"github.com/yourorg/anonymous-support/internal/service"
+)
+
+func main() {
+	configFile := flag.String("config", "", "YAML, TOML or JSON config file; defaults to $CONFIG_FILE. Environment variables override it.")
+	seed := flag.Int64("seed", 1, "Random seed; the same seed generates the same data")
+	users := flag.Int("users", 50, "Number of users")
+	circles := flag.Int("circles", 8, "Number of circles")
+	posts := flag.Int("posts", 200, "Number of posts")
+	password := flag.String("password", "demo-password", "Password for every seeded account")
+	allowProduction := flag.Bool("allow-production", false, "Seed even when ENV is production")
+	timeout := flag.Duration("timeout", 5*time.Minute, "Give up after this long")
+	flag.Parse()
+
+	if *users < 2 || *circles < 0 || *posts < 0 {
+		log.Fatal("-users must be at least 2 and -circles and -posts must not be negative")
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	if cfg.Server.Env == "production" && !*allowProduction {
+		log.Fatal("Refusing to seed a production environment; pass -allow-production if you really mean it")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	encManager, err := encryption.NewKeyring(encryption.KeyringConfig{
+		Primary:    encryption.Key{ID: cfg.Encryption.KeyID, Secret: cfg.Encryption.Key},
+		IndexKeyID: cfg.Encryption.IndexKeyID,
+	})
+	if err != nil {
+		log.Fatal("Failed to create encryption keyring:", err)
+	}
+	sensitive := service.NewSensitiveContent(encManager, cfg.Encryption.SensitiveCategories)
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Fatal("Failed to hash password:", err)
+	}
+
+	d := generate(rand.New(rand.NewSource(*seed)), sizes{Users: *users, Circles: *circles, Posts: *posts}, time.Now().UTC())
+	for _, user := range d.Users {
+		user.PasswordHash = string(passwordHash)
+	}
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", cfg.Postgres.DSN())
+	if err != nil {
+		log.Fatal("Failed to connect to PostgreSQL:", err)
+	}
+	defer db.Close()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoDB.URI))
+	if err != nil {
+		log.Fatal("Failed to connect to MongoDB:", err)
+	}
+	defer func() { _ = client.Disconnect(context.Background()) }()
+	if err := client.Ping(ctx, nil); err != nil {
+		log.Fatal("Failed to ping MongoDB:", err)
+	}
+
+	if err := seedPostgres(ctx, db, d); err != nil {
+		log.Fatal("Failed to seed PostgreSQL:", err)
+	}
+	if err := seedMongo(ctx, client.Database(cfg.MongoDB.Database), d, sensitive); err != nil {
+		log.Fatal("Failed to seed MongoDB:", err)
+	}
+
+	fmt.Printf("Seeded %d users, %d circles, %d memberships, %d posts, %d responses and %d trackers (seed %d)\n",
+		len(d.Users), len(d.Circles), len(d.Memberships), len(d.Posts), len(d.Responses), len(d.Trackers), *seed)
+	fmt.Printf("Log in as demo_admin, demo_moderator or %s with password %q\n", d.Users[2%len(d.Users)].Username, *password)
+}
+
+// seedPostgres writes users, circles and memberships in one transaction, so a
+// failed run leaves nothing behind
+func seedPostgres(ctx context.Context, db *sqlx.DB, d *dataset) error {
+	var exists bool
+	if err := db.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM users WHERE username = $1)`, d.Users[0].Username); err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("user %s already exists; the database has been seeded before", d.Users[0].Username)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, user := range d.Users {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO users (id, username, password_hash, avatar_id, is_anonymous, strength_points, role, created_at, last_active_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, user.ID, user.Username, user.PasswordHash, user.AvatarID, user.IsAnonymous,
+			user.StrengthPoints, user.Role, user.CreatedAt, user.LastActiveAt,
+		); err != nil {
+			return fmt.Errorf("failed to insert user %s: %w", user.Username, err)
+		}
+	}
+
+	for _, circle := range d.Circles {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO circles (id, name, description, category, max_members, member_count, is_private, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, circle.ID, circle.Name, circle.Description, circle.Category, circle.MaxMembers,
+			circle.MemberCount, circle.IsPrivate, circle.CreatedBy, circle.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to insert circle %s: %w", circle.Name, err)
+		}
+	}
+
+	for _, m := range d.Memberships {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO circle_memberships (circle_id, user_id, role, joined_at)
+			VALUES ($1, $2, $3, $4)
+		`, m.CircleID, m.UserID, m.Role, m.JoinedAt); err != nil {
+			return fmt.Errorf("failed to insert circle membership: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// seedMongo writes trackers, posts and responses, encrypting content in
+// sensitive categories the way the services do
+func seedMongo(ctx context.Context, db *mongo.Database, d *dataset, sensitive *service.SensitiveContent) error {
+	postsByID := make(map[string]*domain.Post, len(d.Posts))
+	for _, post := range d.Posts {
+		postsByID[post.ID.Hex()] = post
+	}
+	for _, response := range d.Responses {
+		if err := sensitive.SealResponse(response, postsByID[response.PostID]); err != nil {
+			return err
+		}
+	}
+	for _, post := range d.Posts {
+		if err := sensitive.SealPost(post); err != nil {
+			return err
+		}
+	}
+
+	if err := insertMany(ctx, db.Collection("user_trackers"), d.Trackers); err != nil {
+		return fmt.Errorf("failed to insert trackers: %w", err)
+	}
+	if err := insertMany(ctx, db.Collection("posts"), d.Posts); err != nil {
+		return fmt.Errorf("failed to insert posts: %w", err)
+	}
+	if err := insertMany(ctx, db.Collection("support_responses"), d.Responses); err != nil {
+		return fmt.Errorf("failed to insert responses: %w", err)
+	}
+	return nil
+}
+
+func insertMany[T any](ctx context.Context, collection *mongo.Collection, docs []T) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	batch := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		batch[i] = doc
+	}
+	_, err := collection.InsertMany(ctx, batch)
+	return err
+}
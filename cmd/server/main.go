@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -10,7 +12,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -18,11 +20,17 @@ import (
 
 	"github.com/yourorg/anonymous-support/internal/app"
 	"github.com/yourorg/anonymous-support/internal/config"
+	"github.com/yourorg/anonymous-support/internal/pkg/poolstats"
+	"github.com/yourorg/anonymous-support/internal/pkg/redact"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 )
 
 func main() {
+	configFile := flag.String("config", "", "YAML, TOML or JSON config file; defaults to $CONFIG_FILE. Environment variables override it.")
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(*configFile)
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
@@ -34,27 +42,43 @@ func main() {
 	}
 	defer func() { _ = logger.Sync() }()
 
-	// Initialize database connections
-	postgresDB, err := initPostgres(cfg, logger)
+	// Initialize database connections, waiting for ones that are still
+	// starting, as they often are under docker-compose
+	var (
+		postgresDB      *sqlx.DB
+		mongoDB         *mongo.Database
+		mongoDisconnect func()
+		redisClient     *redis.Client
+	)
+	mongoPool := poolstats.NewMongoPoolMonitor()
+
+	startupCtx, stopStartup := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err = waitForDependencies(startupCtx, logger, cfg.Server.StartupMaxWait,
+		dependency{"postgres", func(ctx context.Context) (err error) {
+			postgresDB, err = initPostgres(ctx, cfg, logger)
+			return err
+		}},
+		dependency{"mongodb", func(ctx context.Context) (err error) {
+			mongoDB, mongoDisconnect, err = initMongoDB(ctx, cfg, mongoPool, logger)
+			return err
+		}},
+		dependency{"redis", func(ctx context.Context) (err error) {
+			redisClient, err = initRedis(ctx, cfg, logger)
+			return err
+		}},
+	)
+	stopStartup()
 	if err != nil {
-		logger.Fatal("Failed to initialize PostgreSQL", zap.Error(err))
+		logger.Fatal("Dependencies unavailable at startup", zap.Error(err))
 	}
 	defer postgresDB.Close()
-
-	mongoDB, mongoDisconnect, err := initMongoDB(cfg, logger)
-	if err != nil {
-		logger.Fatal("Failed to initialize MongoDB", zap.Error(err))
-	}
 	defer mongoDisconnect()
-
-	redisClient, err := initRedis(cfg, logger)
-	if err != nil {
-		logger.Fatal("Failed to initialize Redis", zap.Error(err))
-	}
 	defer redisClient.Close()
 
+	postgresReplicas := initPostgresReplicas(cfg, logger)
+
 	// Create application with all wired dependencies
-	application, err := app.New(cfg, logger, postgresDB, mongoDB, redisClient)
+	application, err := app.New(cfg, logger, postgresDB, postgresReplicas, mongoDB, redisClient)
 	if err != nil {
 		logger.Fatal("Failed to create application", zap.Error(err))
 	}
@@ -63,6 +87,9 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Export connection pool metrics
+	go poolstats.NewSampler(postgresDB.DB, mongoPool, redisClient).Run(ctx, 15*time.Second)
+
 	// Listen for shutdown signals
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -96,54 +123,94 @@ func main() {
 	logger.Info("Server exited successfully")
 }
 
-// initLogger creates a logger based on environment
+// initLogger creates a logger based on environment. Production logs have
+// emails, tokens, IP addresses and credentials redacted.
 func initLogger(env string) (*zap.Logger, error) {
 	if env == "production" {
-		return zap.NewProduction()
+		return zap.NewProduction(zap.WrapCore(redact.NewCore))
 	}
 	return zap.NewDevelopment()
 }
 
 // initPostgres initializes PostgreSQL connection with proper pooling
-func initPostgres(cfg *config.Config, logger *zap.Logger) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", cfg.Postgres.DSN())
+func initPostgres(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*sqlx.DB, error) {
+	db, err := connectPostgres(ctx, cfg.Postgres.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
-	db.SetConnMaxIdleTime(time.Minute)
+	configurePostgresPool(db, cfg.Postgres)
 
-	// Verify connection
-	if err := db.Ping(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to ping: %w", err)
+	logger.Info("PostgreSQL connected successfully")
+	return db, nil
+}
+
+// connectPostgres opens a traced PostgreSQL connection pool for dsn and
+// verifies it, like sqlx.Connect
+func connectPostgres(ctx context.Context, dsn string) (*sqlx.DB, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
 	}
 
-	logger.Info("PostgreSQL connected successfully")
+	db := sqlx.NewDb(sql.OpenDB(tracing.WrapConnector(connector, "postgresql")), "postgres")
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
 	return db, nil
 }
 
+// configurePostgresPool applies the configured pool limits to db
+func configurePostgresPool(db *sqlx.DB, cfg config.PostgresConfig) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
+// initPostgresReplicas connects to the configured read replicas. Replicas are
+// optional, so one that cannot be reached is logged and left out.
+func initPostgresReplicas(cfg *config.Config, logger *zap.Logger) []*sqlx.DB {
+	var replicas []*sqlx.DB
+	for i, dsn := range cfg.Postgres.ReplicaDSNs {
+		db, err := connectPostgres(context.Background(), dsn)
+		if err != nil {
+			logger.Warn("Skipping unreachable PostgreSQL replica", zap.Int("replica", i), zap.Error(err))
+			continue
+		}
+
+		configurePostgresPool(db, cfg.Postgres)
+
+		replicas = append(replicas, db)
+	}
+
+	if len(replicas) > 0 {
+		logger.Info("PostgreSQL replicas connected", zap.Int("count", len(replicas)))
+	}
+	return replicas
+}
+
 // initMongoDB initializes MongoDB connection with proper configuration
-func initMongoDB(cfg *config.Config, logger *zap.Logger) (*mongo.Database, func(), error) {
+func initMongoDB(ctx context.Context, cfg *config.Config, poolMonitor *poolstats.MongoPoolMonitor, logger *zap.Logger) (*mongo.Database, func(), error) {
 	opts := options.Client().
 		ApplyURI(cfg.MongoDB.URI).
-		SetMaxPoolSize(100).
-		SetMinPoolSize(10).
+		SetPoolMonitor(poolMonitor.Monitor()).
+		SetMonitor(tracing.NewMongoCommandMonitor()).
+		SetMaxPoolSize(uint64(cfg.MongoDB.MaxPoolSize)). //nolint:gosec // Validated positive
+		SetMinPoolSize(uint64(cfg.MongoDB.MinPoolSize)). //nolint:gosec // Validated non-negative
 		SetMaxConnIdleTime(5 * time.Minute).
 		SetConnectTimeout(10 * time.Second).
 		SetServerSelectionTimeout(5 * time.Second)
 
-	client, err := mongo.Connect(context.Background(), opts)
+	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to connect: %w", err)
 	}
 
 	// Verify connection
-	if err := client.Ping(context.Background(), nil); err != nil {
+	if err := client.Ping(ctx, nil); err != nil {
 		_ = client.Disconnect(context.Background())
 		return nil, nil, fmt.Errorf("failed to ping: %w", err)
 	}
@@ -162,22 +229,23 @@ func initMongoDB(cfg *config.Config, logger *zap.Logger) (*mongo.Database, func(
 }
 
 // initRedis initializes Redis connection with proper configuration
-func initRedis(cfg *config.Config, logger *zap.Logger) (*redis.Client, error) {
+func initRedis(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*redis.Client, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
 		Password:     cfg.Redis.Password,
 		DB:           cfg.Redis.DB,
-		PoolSize:     50,
-		MinIdleConns: 10,
+		PoolSize:     cfg.Redis.PoolSize,
+		MinIdleConns: cfg.Redis.MinIdleConns,
 		MaxRetries:   3,
 		DialTimeout:  5 * time.Second,
 		ReadTimeout:  3 * time.Second,
 		WriteTimeout: 3 * time.Second,
 		PoolTimeout:  4 * time.Second,
 	})
+	client.AddHook(tracing.NewRedisHook())
 
 	// Verify connection
-	if err := client.Ping(context.Background()).Err(); err != nil {
+	if err := client.Ping(ctx).Err(); err != nil {
 		client.Close()
 		return nil, fmt.Errorf("failed to ping: %w", err)
 	}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/retry"
+)
+
+// dependency is a backing service the server can't start without. connect
+// is retried until it succeeds, so it must clean up after a failed attempt.
+type dependency struct {
+	name    string
+	connect func(ctx context.Context) error
+}
+
+// waitForDependencies connects to the dependencies in parallel, retrying each
+// with backoff until it is up or maxWait has passed. Every failed attempt and
+// each dependency's outcome is logged, so a slow boot shows what it waits on.
+func waitForDependencies(ctx context.Context, logger *zap.Logger, maxWait time.Duration, deps ...dependency) error {
+	ctx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+
+	var g errgroup.Group
+	for _, dep := range deps {
+		g.Go(func() error {
+			depLogger := logger.With(zap.String("dependency", dep.name))
+			retrier := retry.NewRetrier(retry.Config{
+				MaxAttempts:  -1,
+				InitialDelay: 500 * time.Millisecond,
+				MaxDelay:     5 * time.Second,
+			}, depLogger)
+
+			start := time.Now()
+			attempts := 0
+			err := retrier.Do(ctx, func(ctx context.Context) error {
+				attempts++
+				return dep.connect(ctx)
+			})
+			if err != nil {
+				depLogger.Error("Dependency unavailable",
+					zap.Int("attempts", attempts),
+					zap.Duration("waited", time.Since(start)),
+					zap.Error(err))
+				return fmt.Errorf("%s: %w", dep.name, err)
+			}
+
+			depLogger.Info("Dependency ready",
+				zap.Int("attempts", attempts),
+				zap.Duration("waited", time.Since(start)))
+			return nil
+		})
+	}
+	return g.Wait()
+}
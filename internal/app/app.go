@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"time"
 
+	"connectrpc.com/connect"
 	"github.com/gorilla/websocket"
 	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,24 +17,50 @@ import (
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
+	adminv1connect "github.com/yourorg/anonymous-support/gen/admin/v1/adminv1connect"
+	analyticsv1connect "github.com/yourorg/anonymous-support/gen/analytics/v1/analyticsv1connect"
 	authv1connect "github.com/yourorg/anonymous-support/gen/auth/v1/authv1connect"
 	circlev1connect "github.com/yourorg/anonymous-support/gen/circle/v1/circlev1connect"
+	homev1connect "github.com/yourorg/anonymous-support/gen/home/v1/homev1connect"
+	journalv1connect "github.com/yourorg/anonymous-support/gen/journal/v1/journalv1connect"
 	moderationv1connect "github.com/yourorg/anonymous-support/gen/moderation/v1/moderationv1connect"
+	notificationv1connect "github.com/yourorg/anonymous-support/gen/notification/v1/notificationv1connect"
 	postv1connect "github.com/yourorg/anonymous-support/gen/post/v1/postv1connect"
+	postv2connect "github.com/yourorg/anonymous-support/gen/post/v2/postv2connect"
+	progressv1connect "github.com/yourorg/anonymous-support/gen/progress/v1/progressv1connect"
+	resourcesv1connect "github.com/yourorg/anonymous-support/gen/resources/v1/resourcesv1connect"
 	supportv1connect "github.com/yourorg/anonymous-support/gen/support/v1/supportv1connect"
 	userv1connect "github.com/yourorg/anonymous-support/gen/user/v1/userv1connect"
 	"github.com/yourorg/anonymous-support/internal/config"
+	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/handler"
+	"github.com/yourorg/anonymous-support/internal/handler/rest"
 	"github.com/yourorg/anonymous-support/internal/handler/rpc"
 	wsHandler "github.com/yourorg/anonymous-support/internal/handler/websocket"
 	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/pkg/audit"
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
 	"github.com/yourorg/anonymous-support/internal/pkg/cache"
+	"github.com/yourorg/anonymous-support/internal/pkg/counters"
 	"github.com/yourorg/anonymous-support/internal/pkg/encryption"
+	reporting "github.com/yourorg/anonymous-support/internal/pkg/errors"
+	"github.com/yourorg/anonymous-support/internal/pkg/events"
+	"github.com/yourorg/anonymous-support/internal/pkg/feed"
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"github.com/yourorg/anonymous-support/internal/pkg/geo"
 	"github.com/yourorg/anonymous-support/internal/pkg/jwt"
 	"github.com/yourorg/anonymous-support/internal/pkg/migrations"
 	"github.com/yourorg/anonymous-support/internal/pkg/moderator"
+	"github.com/yourorg/anonymous-support/internal/pkg/notifications"
+	"github.com/yourorg/anonymous-support/internal/pkg/objectstore"
+	"github.com/yourorg/anonymous-support/internal/pkg/openapi"
+	"github.com/yourorg/anonymous-support/internal/pkg/ratelimit"
+	"github.com/yourorg/anonymous-support/internal/pkg/secrets"
+	"github.com/yourorg/anonymous-support/internal/pkg/servertls"
+	"github.com/yourorg/anonymous-support/internal/pkg/slo"
 	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/pkg/transaction"
+	"github.com/yourorg/anonymous-support/internal/pkg/workqueue"
 	"github.com/yourorg/anonymous-support/internal/repository"
 	"github.com/yourorg/anonymous-support/internal/repository/mongodb"
 	"github.com/yourorg/anonymous-support/internal/repository/postgres"
@@ -42,12 +70,6 @@ import (
 
 const version = "1.0.0"
 
-var wsUpgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin:     func(r *http.Request) bool { return true },
-}
-
 // Application represents the entire application with all its dependencies
 type Application struct {
 	Config *config.Config
@@ -55,29 +77,71 @@ type Application struct {
 
 	// Database clients
 	PostgresDB  *sqlx.DB
+	Postgres    *postgres.DB // Routes lag-tolerant reads to replicas
 	MongoDB     *mongo.Database
+	Mongo       *mongodb.DB // Collections behind the MongoDB circuit breaker
 	RedisClient *redis.Client
 
 	// Repositories
-	UserRepo       repository.UserRepository
-	PostRepo       repository.PostRepository
-	SupportRepo    repository.SupportRepository
-	CircleRepo     repository.CircleRepository
-	ModerationRepo repository.ModerationRepository
-	SessionRepo    repository.SessionRepository
-	RealtimeRepo   repository.RealtimeRepository
-	CacheRepo      repository.CacheRepository
-	AnalyticsRepo  repository.AnalyticsRepository
-	AuditRepo      repository.AuditRepository
+	UserRepo                  repository.UserRepository
+	PostRepo                  repository.PostRepository
+	ReactionRepo              repository.ReactionRepository
+	SupportRepo               repository.SupportRepository
+	CircleRepo                repository.CircleRepository
+	ModerationRepo            repository.ModerationRepository
+	SessionRepo               repository.SessionRepository
+	RealtimeRepo              repository.RealtimeRepository
+	CacheRepo                 repository.CacheRepository
+	BlockCacheRepo            repository.BlockCacheRepository
+	CircleMembershipCacheRepo repository.CircleMembershipCacheRepository
+	UserSummaryCacheRepo      repository.UserSummaryCacheRepository
+	AnalyticsRepo             repository.AnalyticsRepository
+	AuditRepo                 repository.AuditRepository
+	FingerprintRepo           repository.FingerprintRepository
+	LoginLocationRepo         repository.LoginLocationRepository
+	LoginRiskRepo             repository.LoginRiskRepository
+	DeviceTokenRepo           repository.DeviceTokenRepository
+	NotificationRepo          repository.NotificationRepository
+	NotificationPrefsRepo     repository.NotificationPreferencesRepository
+	CategorySubRepo           repository.CategorySubscriptionRepository
+	JournalRepo               repository.JournalRepository
+	MetricsRepo               repository.MetricsRepository
+	ContentRetentionRepo      repository.RetentionRepository // Posts, responses and analytics events
+	AuditRetentionRepo        repository.RetentionRepository
+	FeatureFlagRepo           repository.FeatureFlagRepository
+	SubscriptionRepo          repository.SubscriptionRepository
+	ExportRepo                repository.ExportRepository
+	RuntimeSettingRepo        repository.RuntimeSettingRepository
+	RuntimeSettingNotifier    repository.RuntimeSettingNotifier
 
 	// Services
-	AuthService       service.AuthServiceInterface
-	UserService       service.UserServiceInterface
-	PostService       service.PostServiceInterface
-	SupportService    service.SupportServiceInterface
-	CircleService     service.CircleServiceInterface
-	ModerationService service.ModerationServiceInterface
-	AnalyticsService  service.AnalyticsServiceInterface
+	AuthService         service.AuthServiceInterface
+	UserService         service.UserServiceInterface
+	PostService         service.PostServiceInterface
+	ReactionService     service.ReactionServiceInterface
+	SupportService      service.SupportServiceInterface
+	CircleService       service.CircleServiceInterface
+	ModerationService   service.ModerationServiceInterface
+	AnalyticsService    service.AnalyticsServiceInterface
+	NotificationService *service.NotificationService
+	ProgressService     *service.ProgressService
+	ReminderService     *service.ReminderService
+	SOSService          *service.SOSService
+	Entitlements        *service.EntitlementService
+	CrisisResources     *service.CrisisResourceService
+	PresenceService     *service.PresenceService
+	BlockService        *service.BlockService
+	JournalService      service.JournalServiceInterface
+	AdminAnalytics      *service.AdminAnalyticsService
+	CommunityStats      service.CommunityStatsServiceInterface
+	EventSubscribers    *service.EventSubscribers
+	KeyRotation         *service.KeyRotationService
+	Retention           *service.RetentionService
+	Exports             *service.ExportService
+	RuntimeSettings     *service.RuntimeSettingsService
+	FeatureFlags        *service.FeatureFlagService
+	AdminService        service.AdminServiceInterface
+	HomeService         service.HomeServiceInterface
 
 	// Infrastructure
 	JWTManager        *jwt.JWTManager
@@ -85,19 +149,44 @@ type Application struct {
 	TxManager         *transaction.Manager
 	Cache             *cache.Cache
 	WSHub             *wsHandler.Hub
+	WSUpgrader        *websocket.Upgrader
 	TracerProvider    *tracing.TracerProvider
+	SecretManager     secrets.SecretManager
+	SecretSources     *secrets.MultiSourceSecretManager
+	PushService       *notifications.MultiProviderNotificationService
+	PushDispatcher    *notifications.Dispatcher
+	EmailSender       service.EmailSender
+	EventBus          events.EventBus
+	Counters          *counters.Buffer
+	WorkQueue         *workqueue.Queue
+	Audit             *audit.Writer
+	ErrorReporter     *reporting.ErrorReporter
+	SLO               *slo.Tracker
+
+	// Checks role and circle-role permissions for handlers, services and the WebSocket hub
+	Authorizer *authz.Authorizer
 
 	// HTTP Server
-	HTTPServer *http.Server
+	HTTPServer     *http.Server
+	AdminServer    *http.Server
+	RedirectServer *http.Server
+	Health         *handler.HealthHandler
+	InFlight       *middleware.InFlightTracker
 }
 
 // New creates and wires up all application dependencies
-func New(cfg *config.Config, logger *zap.Logger, postgresDB *sqlx.DB, mongoDB *mongo.Database, redisClient *redis.Client) (*Application, error) {
+func New(cfg *config.Config, logger *zap.Logger, postgresDB *sqlx.DB, postgresReplicas []*sqlx.DB, mongoDB *mongo.Database, redisClient *redis.Client) (*Application, error) {
+	// Every Redis command goes through the Redis circuit breaker, as queries
+	// through Postgres and Mongo go through theirs
+	redisClient.AddHook(redisrepo.NewBreakerHook(logger))
+
 	app := &Application{
 		Config:      cfg,
 		Logger:      logger,
 		PostgresDB:  postgresDB,
+		Postgres:    postgres.NewDB(postgresDB, postgresReplicas, cfg.Postgres.ReplicaMaxLag, logger),
 		MongoDB:     mongoDB,
+		Mongo:       mongodb.NewDB(mongoDB, logger),
 		RedisClient: redisClient,
 	}
 
@@ -107,13 +196,24 @@ func New(cfg *config.Config, logger *zap.Logger, postgresDB *sqlx.DB, mongoDB *m
 	// Initialize JWT manager
 	app.JWTManager = jwt.NewJWTManager(cfg.JWT.Secret, cfg.JWT.AccessExpiry, cfg.JWT.RefreshExpiry)
 
-	// Initialize encryption manager
-	encManager, err := encryption.NewManager(cfg.Encryption.Key)
+	// Initialize secret manager; configured secret stores take precedence over the environment
+	secretSources, err := secrets.NewChain(context.Background(), cfg.Secrets.Sources(), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret manager: %w", err)
+	}
+	app.SecretSources = secretSources
+	app.SecretManager = secretSources
+
+	// Initialize encryption manager with the current and retired keys
+	encManager, err := app.loadEncryptionKeys(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create encryption manager: %w", err)
 	}
 	app.EncryptionManager = encManager
 
+	// Initialize push notification providers
+	app.wirePushProviders(context.Background())
+
 	// Initialize transaction manager
 	app.TxManager = transaction.NewManager(postgresDB, logger)
 
@@ -121,11 +221,9 @@ func New(cfg *config.Config, logger *zap.Logger, postgresDB *sqlx.DB, mongoDB *m
 	app.Cache = cache.NewCache(redisClient, logger, cache.Config{
 		Prefix:     "app",
 		DefaultTTL: 5 * time.Minute,
+		TTLJitter:  0.1,
 	})
 
-	// Initialize WebSocket hub
-	app.WSHub = wsHandler.NewHub(app.JWTManager, logger)
-
 	// Initialize tracing
 	tracerProvider, err := tracing.NewTracerProvider(context.Background(), tracing.Config{
 		Enabled:     cfg.Server.Env == "production" || cfg.Server.Env == "staging",
@@ -138,6 +236,39 @@ func New(cfg *config.Config, logger *zap.Logger, postgresDB *sqlx.DB, mongoDB *m
 	}
 	app.TracerProvider = tracerProvider
 
+	// Initialize error reporting
+	release := cfg.Errors.Release
+	if release == "" {
+		release = version
+	}
+	app.ErrorReporter = reporting.NewErrorReporter(logger, cfg.Server.Env, release, cfg.Errors.SentryDSN)
+
+	// Initialize SLO tracking; compliance gauges are exported on /metrics
+	app.SLO = slo.NewTracker(slo.Objectives{
+		Availability: cfg.SLO.AvailabilityTarget,
+		Latency:      cfg.SLO.LatencyTarget,
+		Window:       cfg.SLO.Window,
+	})
+	prometheus.MustRegister(app.SLO)
+
+	// Initialize domain event bus
+	eventBus, err := events.New(events.Config{
+		Driver:        cfg.Events.Driver,
+		MaxDeliveries: cfg.Events.MaxDeliveries,
+		NATSURL:       cfg.Events.NATSURL,
+		KafkaBrokers:  cfg.Events.KafkaBrokers,
+	}, redisClient, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event bus: %w", err)
+	}
+	app.EventBus = eventBus
+
+	// Buffer hot counter increments; services register how each kind is written
+	app.Counters = counters.NewBuffer(logger)
+
+	// Background jobs, SOS work first
+	app.WorkQueue = workqueue.NewQueue(cfg.WorkQueue.Workers, app.ErrorReporter, logger)
+
 	// Run MongoDB migrations
 	if err := migrations.RunMongoDBMigrations(context.Background(), mongoDB); err != nil {
 		logger.Warn("Failed to run MongoDB migrations", zap.Error(err))
@@ -148,57 +279,342 @@ func New(cfg *config.Config, logger *zap.Logger, postgresDB *sqlx.DB, mongoDB *m
 		return nil, fmt.Errorf("failed to wire services: %w", err)
 	}
 
+	// Initialize WebSocket hub
+	app.WSHub = wsHandler.NewHub(app.JWTManager, app.BlockService, app.CircleService, app.PresenceService, app.RealtimeRepo, cfg.WebSocket.AuthTimeout, wsHandler.BackpressureConfig{
+		SendBufferSize: cfg.WebSocket.SendBufferSize,
+		Policy:         wsHandler.SlowClientPolicy(cfg.WebSocket.SlowClientPolicy),
+	}, logger)
+	app.WSUpgrader = wsHandler.NewUpgrader(wsHandler.UpgraderConfig{
+		ReadBufferSize:  cfg.WebSocket.ReadBufferSize,
+		WriteBufferSize: cfg.WebSocket.WriteBufferSize,
+		AllowedOrigins:  cfg.WebSocket.AllowedOrigins,
+	})
+
 	return app, nil
 }
 
 // wireRepositories initializes all repository implementations
 func (a *Application) wireRepositories() {
 	// Postgres repositories
-	a.UserRepo = postgres.NewUserRepository(a.PostgresDB)
-	a.CircleRepo = postgres.NewCircleRepository(a.PostgresDB)
-	a.ModerationRepo = postgres.NewModerationRepository(a.PostgresDB)
-	a.AuditRepo = postgres.NewAuditRepository(a.PostgresDB)
+	a.UserRepo = postgres.NewUserRepository(a.Postgres)
+	a.CircleRepo = postgres.NewCircleRepository(a.Postgres)
+	a.ModerationRepo = postgres.NewModerationRepository(a.Postgres)
+	a.AuditRepo = postgres.NewAuditRepository(a.Postgres)
+	a.FingerprintRepo = postgres.NewFingerprintRepository(a.Postgres)
+	a.LoginLocationRepo = postgres.NewLoginLocationRepository(a.Postgres)
+	a.DeviceTokenRepo = postgres.NewDeviceTokenRepository(a.Postgres)
+	a.NotificationPrefsRepo = postgres.NewNotificationPreferencesRepository(a.Postgres)
+	a.CategorySubRepo = postgres.NewCategorySubscriptionRepository(a.Postgres)
+	a.AuditRetentionRepo = postgres.NewRetentionRepository(a.Postgres)
+	a.FeatureFlagRepo = postgres.NewFeatureFlagRepository(a.Postgres)
+	a.SubscriptionRepo = postgres.NewSubscriptionRepository(a.Postgres)
+	a.ExportRepo = postgres.NewExportRepository(a.Postgres)
+	a.RuntimeSettingRepo = postgres.NewRuntimeSettingRepository(a.Postgres)
 
 	// MongoDB repositories
-	a.PostRepo = mongodb.NewPostRepository(a.MongoDB)
-	a.SupportRepo = mongodb.NewSupportRepository(a.MongoDB)
-	a.AnalyticsRepo = mongodb.NewAnalyticsRepository(a.MongoDB)
+	a.PostRepo = mongodb.NewPostRepository(a.Mongo)
+	a.ReactionRepo = mongodb.NewReactionRepository(a.Mongo)
+	a.SupportRepo = mongodb.NewSupportRepository(a.Mongo)
+	a.AnalyticsRepo = mongodb.NewAnalyticsRepository(a.Mongo)
+	a.NotificationRepo = mongodb.NewNotificationRepository(a.Mongo)
+	a.JournalRepo = mongodb.NewJournalRepository(a.Mongo)
+	a.MetricsRepo = mongodb.NewMetricsRepository(a.Mongo)
+	a.ContentRetentionRepo = mongodb.NewRetentionRepository(a.Mongo)
 
 	// Redis repositories
 	a.SessionRepo = redisrepo.NewSessionRepository(a.RedisClient)
 	a.RealtimeRepo = redisrepo.NewRealtimeRepository(a.RedisClient)
 	a.CacheRepo = redisrepo.NewCacheRepository(a.RedisClient)
+	a.BlockCacheRepo = redisrepo.NewBlockCacheRepository(a.RedisClient)
+	a.CircleMembershipCacheRepo = redisrepo.NewCircleMembershipCacheRepository(a.RedisClient)
+	a.UserSummaryCacheRepo = redisrepo.NewUserSummaryCacheRepository(a.RedisClient)
+	a.LoginRiskRepo = redisrepo.NewLoginRiskRepository(a.RedisClient)
+	a.RuntimeSettingNotifier = redisrepo.NewRuntimeSettingNotifier(a.RedisClient)
+}
+
+// loadEncryptionKeys builds the encryption keyring. Retired keys are only
+// needed until the stored values are re-encrypted, so they come from the
+// secret manager rather than the config.
+func (a *Application) loadEncryptionKeys(ctx context.Context) (*encryption.Manager, error) {
+	keyring := encryption.KeyringConfig{
+		Primary:    encryption.Key{ID: a.Config.Encryption.KeyID, Secret: a.Config.Encryption.Key},
+		IndexKeyID: a.Config.Encryption.IndexKeyID,
+	}
+	for _, id := range a.Config.Encryption.PreviousKeyIDs {
+		secret, err := a.SecretManager.GetSecret(ctx, "ENCRYPTION_KEY_"+id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load encryption key %s: %w", id, err)
+		}
+		keyring.Previous = append(keyring.Previous, encryption.Key{ID: id, Secret: secret})
+	}
+	return encryption.NewKeyring(keyring)
+}
+
+// wirePushProviders registers the push providers whose credentials are available.
+// Push is optional, so a missing provider is logged rather than failing startup.
+func (a *Application) wirePushProviders(ctx context.Context) {
+	a.PushService = notifications.NewMultiProviderNotificationService(a.Logger)
+
+	fcm, err := notifications.NewFCMProvider(ctx, notifications.FCMConfig{
+		ProjectID:         a.Config.Push.FCMProjectID,
+		CredentialsSecret: a.Config.Push.FCMCredentialsSecret,
+	}, a.SecretManager, a.DeviceTokenRepo, a.Logger)
+	if err != nil {
+		a.Logger.Warn("FCM push notifications disabled", zap.Error(err))
+	} else {
+		a.PushService.RegisterProvider("fcm", fcm)
+	}
+
+	apns, err := notifications.NewAPNSProvider(ctx, notifications.APNSConfig{
+		KeyID:      a.Config.Push.APNSKeyID,
+		TeamID:     a.Config.Push.APNSTeamID,
+		BundleID:   a.Config.Push.APNSBundleID,
+		KeySecret:  a.Config.Push.APNSKeySecret,
+		Production: a.Config.Push.APNSProduction,
+	}, a.SecretManager, a.DeviceTokenRepo, a.Logger)
+	if err != nil {
+		a.Logger.Warn("APNS push notifications disabled", zap.Error(err))
+	} else {
+		a.PushService.RegisterProvider("apns", apns)
+	}
+
+	a.PushDispatcher = notifications.NewDispatcher(a.RedisClient, a.PushService, notifications.DispatcherConfig{
+		Workers:        a.Config.Push.DispatchWorkers,
+		MaxAttempts:    a.Config.Push.DispatchMaxAttempts,
+		InitialDelay:   a.Config.Push.DispatchRetryDelay,
+		MaxDelay:       a.Config.Push.DispatchMaxDelay,
+		CollapseWindow: a.Config.Push.CollapseWindow,
+	}, a.Logger)
+
+	if a.Config.Email.SMTPHost != "" {
+		email, err := notifications.NewSMTPSender(ctx, notifications.SMTPConfig{
+			Host:           a.Config.Email.SMTPHost,
+			Port:           a.Config.Email.SMTPPort,
+			Username:       a.Config.Email.SMTPUsername,
+			PasswordSecret: a.Config.Email.SMTPPasswordSecret,
+			From:           a.Config.Email.From,
+		}, a.SecretManager)
+		if err != nil {
+			a.Logger.Warn("Email notifications disabled", zap.Error(err))
+		} else {
+			a.EmailSender = email
+		}
+	}
+}
+
+// purgeInvalidDeviceTokens periodically deletes device tokens that push
+// providers reported as dead more than a week ago
+func (a *Application) purgeInvalidDeviceTokens(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := a.DeviceTokenRepo.DeleteInvalidTokens(ctx, time.Now().Add(-7*24*time.Hour))
+			if err != nil {
+				a.Logger.Warn("Failed to purge invalid device tokens", zap.Error(err))
+			} else if deleted > 0 {
+				a.Logger.Info("Purged invalid device tokens", zap.Int64("count", deleted))
+			}
+		}
+	}
 }
 
 // wireServices initializes all service implementations
 func (a *Application) wireServices() error {
 	// Auth service
+	// Security events are written to the audit log in the background
+	a.Audit = audit.NewWriter(a.AuditRepo, a.Config.Audit.BufferSize, a.Logger)
+
+	hasher := fingerprint.NewHasher(a.Config.Encryption.Key)
+	banEvasion := service.NewBanEvasionService(
+		a.FingerprintRepo,
+		a.ModerationRepo,
+		hasher,
+		a.Config.Moderation.BanEvasionWindow,
+	)
+	loginRisk := service.NewLoginRiskService(
+		a.LoginLocationRepo,
+		a.LoginRiskRepo,
+		hasher,
+		a.Config.LoginRisk.MaxTravelSpeedKMH,
+		a.Config.LoginRisk.IPFailureThreshold,
+		a.Config.LoginRisk.IPFailureWindow,
+	)
 	a.AuthService = service.NewAuthService(
 		a.UserRepo,
 		a.SessionRepo,
 		a.JWTManager,
 		a.EncryptionManager,
-		a.AuditRepo,
+		a.Audit,
+		banEvasion,
+		loginRisk,
+		a.EmailSender,
+	)
+
+	// Plans, granted by admins or synced from Stripe, and the features they unlock
+	stripePlans, err := service.ParseStripePricePlans(a.Config.Billing.StripePricePlans)
+	if err != nil {
+		return err
+	}
+	a.Entitlements = service.NewEntitlementService(a.SubscriptionRepo, a.Config.Billing.EnforceEntitlements, stripePlans, a.Audit, a.Logger)
+
+	// Progress and user services
+	achievements, err := service.LoadAchievements(a.Config.Progress.AchievementsFile)
+	if err != nil {
+		return err
+	}
+	checkInQuestions, err := service.LoadCheckInQuestions(a.Config.Progress.CheckInQuestionsFile)
+	if err != nil {
+		return err
+	}
+	a.ProgressService = service.NewProgressService(a.AnalyticsRepo, a.PostRepo, a.SupportRepo, achievements, a.Config.Progress.StreakFreezesPerMonth, checkInQuestions, a.Entitlements, a.EventBus)
+	a.UserService = service.NewUserService(a.UserRepo, a.AnalyticsRepo, a.ProgressService, a.UserSummaryCacheRepo)
+	a.JournalService = service.NewJournalService(a.JournalRepo, a.EncryptionManager)
+	a.KeyRotation = service.NewKeyRotationService(a.UserRepo, a.EncryptionManager, a.Logger)
+
+	// Data retention
+	retentionRules, err := service.ParseRetentionRules(a.Config.Retention.Rules)
+	if err != nil {
+		return err
+	}
+	a.Retention = service.NewRetentionService(map[domain.DataClass]repository.RetentionRepository{
+		domain.DataClassPosts:           a.ContentRetentionRepo,
+		domain.DataClassResponses:       a.ContentRetentionRepo,
+		domain.DataClassAnalyticsEvents: a.ContentRetentionRepo,
+		domain.DataClassAuditLogs:       a.AuditRetentionRepo,
+	}, retentionRules, !a.Config.Retention.Enforce)
+
+	// Exports of moderation and analytics data for offline analysis
+	exportExclusions, err := service.ParseExportExclusions(a.Config.Export.ExcludedColumns)
+	if err != nil {
+		return err
+	}
+	dailyExports, err := service.ParseExportDatasets(a.Config.Export.DailyDatasets)
+	if err != nil {
+		return err
+	}
+	exportStore, err := a.newExportStore(context.Background())
+	if err != nil {
+		return err
+	}
+	a.Exports = service.NewExportService(a.ExportRepo, a.ModerationRepo, a.AuditRepo, a.MetricsRepo, exportStore, exportExclusions, dailyExports, a.WorkQueue, a.Audit, a.Logger)
+
+	// Block service
+	a.BlockService = service.NewBlockService(a.ModerationRepo, a.BlockCacheRepo)
+
+	// Notification service
+	a.NotificationService = service.NewNotificationService(
+		a.NotificationRepo,
+		a.NotificationPrefsRepo,
+		a.DeviceTokenRepo,
+		a.UserRepo,
+		a.BlockService,
+		a.PushDispatcher,
+		a.EmailSender,
+		a.EncryptionManager,
+	)
+	a.ReminderService = service.NewReminderService(a.NotificationPrefsRepo, a.AnalyticsRepo, a.ProgressService, a.NotificationService, a.Logger)
+
+	// Auto-moderation
+	contentFilter := moderator.NewContentFilter(a.Config.Moderation.ProfanityFilterLevel, a.Config.Moderation.Languages...)
+	autoModCfg := a.Config.Moderation.AutoModeration
+	autoModActions := make([]moderator.Action, len(autoModCfg.Actions))
+	for i, action := range autoModCfg.Actions {
+		autoModActions[i] = moderator.Action(action)
+	}
+	autoModRules := moderator.Rules{
+		Enabled:                a.Config.Moderation.EnableAutoModeration,
+		ToxicityThreshold:      autoModCfg.ToxicityThreshold,
+		ReportThreshold:        autoModCfg.ReportThreshold,
+		AbuseSeverityThreshold: autoModCfg.AbuseSeverityThreshold,
+		Actions:                autoModActions,
+	}
+	rulesEngine := moderator.NewRulesEngine(autoModRules)
+	feedRanker := feed.NewFeedRanker()
+
+	// Rate limits, moderation thresholds and feed weights admins can change
+	// without a restart. Overrides apply on top of the static config.
+	a.RuntimeSettings = service.NewRuntimeSettingsService(a.RuntimeSettingRepo, a.RuntimeSettingNotifier, service.RuntimeSettings{
+		RequestsPerMinute:     a.Config.RateLimit.RequestsPerMinute,
+		AuthRequestsPerMinute: a.Config.RateLimit.AuthRequestsPerMinute,
+		PostsPerHour:          a.Config.RateLimit.PostsPerHour,
+		ResponsesPerHour:      a.Config.RateLimit.ResponsesPerHour,
+		ToxicityThreshold:     autoModCfg.ToxicityThreshold,
+		ReportThreshold:       autoModCfg.ReportThreshold,
+		FeedWeights:           feed.DefaultWeights(),
+	}, a.Audit, a.Logger)
+	a.RuntimeSettings.OnChange(func(settings service.RuntimeSettings) {
+		rules := autoModRules
+		rules.ToxicityThreshold = settings.ToxicityThreshold
+		rules.ReportThreshold = settings.ReportThreshold
+		rulesEngine.SetRules(rules)
+		feedRanker.SetWeights(settings.FeedWeights)
+	})
+	if err := a.RuntimeSettings.Reload(context.Background()); err != nil {
+		return err
+	}
+
+	autoModerator := service.NewAutoModerator(rulesEngine, contentFilter, a.PostRepo, a.ModerationRepo, a.NotificationService, a.EventBus)
+
+	// Online presence, maintained by WebSocket connections
+	a.PresenceService = service.NewPresenceService(a.SessionRepo, a.CircleRepo, a.RealtimeRepo, a.Logger)
+
+	// SOS helper targeting
+	a.SOSService = service.NewSOSService(
+		a.CategorySubRepo,
+		a.PresenceService,
+		a.BlockService,
+		a.NotificationService,
+		a.Config.SOS.MaxRecipients,
+		a.Config.SOS.CandidatePool,
+		a.Logger,
 	)
+	crisisResources, err := service.LoadCrisisResources(a.Config.SOS.CrisisResourcesFile)
+	if err != nil {
+		return err
+	}
+	a.CrisisResources = service.NewCrisisResourceService(crisisResources, a.LoginLocationRepo, a.NotificationService)
 
-	// User service
-	a.UserService = service.NewUserService(a.UserRepo, a.AnalyticsRepo)
+	// Content in sensitive categories is encrypted at rest
+	sensitiveContent := service.NewSensitiveContent(a.EncryptionManager, a.Config.Encryption.SensitiveCategories)
 
 	// Post service
-	contentFilter := moderator.NewContentFilter(a.Config.Moderation.ProfanityFilterLevel)
-	a.PostService = service.NewPostService(a.PostRepo, a.RealtimeRepo, contentFilter, a.Cache)
+	postService := service.NewPostService(a.PostRepo, a.RealtimeRepo, contentFilter, sensitiveContent, a.Cache, feedRanker, autoModerator, a.BlockService, a.EventBus, a.Counters)
+	a.PostService = postService
+	a.ReactionService = service.NewReactionService(a.PostRepo, a.ReactionRepo)
+
+	a.HomeService = service.NewHomeService(postService, a.NotificationService, a.UserService)
 
 	// Support service
-	a.SupportService = service.NewSupportService(a.SupportRepo, a.PostRepo, a.UserRepo, a.RealtimeRepo)
+	a.SupportService = service.NewSupportService(a.SupportRepo, a.PostRepo, a.UserRepo, a.RealtimeRepo, sensitiveContent, a.BlockService, a.EventBus, a.Counters)
+
+	// Side effects of domain events: realtime fan-out, notifications, SOS alerts, crisis resources and milestone celebrations
+	milestones := service.NewMilestoneService(a.UserRepo, a.CircleRepo, postService, a.NotificationService, a.Logger)
+	a.EventSubscribers = service.NewEventSubscribers(a.RealtimeRepo, a.Config.WebSocket.RealtimeSource == "events", postService, sensitiveContent, a.NotificationService, a.SOSService, a.CrisisResources, milestones, a.WorkQueue, a.Logger)
 
 	// Circle service
-	a.CircleService = service.NewCircleService(a.CircleRepo, a.PostRepo, a.TxManager)
+	a.Authorizer = authz.NewAuthorizer()
+	a.CircleService = service.NewCircleService(a.CircleRepo, a.PostRepo, sensitiveContent, a.CircleMembershipCacheRepo, a.RealtimeRepo, a.TxManager, a.PresenceService, a.Authorizer, a.Entitlements, a.Audit)
 
 	// Moderation service
-	a.ModerationService = service.NewModerationService(a.ModerationRepo)
+	a.ModerationService = service.NewModerationService(a.ModerationRepo, a.PostRepo, sensitiveContent, autoModerator, a.NotificationService, a.Audit, a.EventBus, a.Config.Moderation.SLA.BySeverity())
 
 	// Analytics service
-	a.AnalyticsService = service.NewAnalyticsService(a.AnalyticsRepo)
+	a.AnalyticsService = service.NewAnalyticsService(a.AnalyticsRepo, a.Config.Progress.StreakFreezesPerMonth)
+
+	// Admin platform metrics
+	a.AdminAnalytics = service.NewAdminAnalyticsService(a.UserRepo, a.MetricsRepo, a.CacheRepo)
+
+	// Public community stats
+	a.CommunityStats = service.NewCommunityStatsService(a.UserRepo, a.MetricsRepo, a.CacheRepo)
+
+	// Admin API and feature flags
+	a.FeatureFlags = service.NewFeatureFlagService(a.FeatureFlagRepo, a.CacheRepo)
+	a.AdminService = service.NewAdminService(a.UserRepo, a.SessionRepo, a.CircleRepo, a.ModerationRepo, a.FeatureFlags, a.Audit)
 
 	return nil
 }
@@ -207,13 +623,315 @@ func (a *Application) wireServices() error {
 func (a *Application) Start(ctx context.Context) error {
 	a.Logger.Info("Starting application components")
 
+	// Run background jobs; started first since event subscribers queue SOS alerts
+	a.WorkQueue.Start(ctx)
+	go a.Audit.Run()
+
+	// Keep secret store credentials such as Vault tokens from expiring
+	a.SecretSources.RunRenewal(ctx)
+
+	// Handle domain events published by services
+	if err := a.EventSubscribers.Subscribe(ctx, a.EventBus); err != nil {
+		return fmt.Errorf("failed to subscribe to domain events: %w", err)
+	}
+
 	// Start WebSocket hub
 	go a.WSHub.Run()
 
+	// Relay events published by any replica to this replica's WebSocket clients
+	go a.WSHub.RunRelay(ctx)
+
+	// Keep WebSocket clients' block sets fresh without blocking delivery
+	go a.WSHub.RunBlockRefresh(ctx)
+
+	// Announce posts and responses straight from MongoDB, whoever wrote them
+	if a.Config.WebSocket.RealtimeSource == "change_streams" {
+		go a.WSHub.RunChangeStreams(ctx, mongodb.NewChangeStreamRepository(a.Mongo))
+	}
+
+	// Take lagging or unreachable Postgres replicas out of read rotation
+	go a.Postgres.MonitorReplicas(ctx, 10*time.Second)
+
+	// Keep moderation SLA gauges fresh for alerting
+	go a.monitorModerationSLA(ctx, time.Minute)
+
+	// Remove device tokens that push providers reported as dead
+	go a.purgeInvalidDeviceTokens(ctx, time.Hour)
+
+	// Send daily check-in reminders as users' local reminder times pass
+	go a.sendCheckInReminders(ctx, time.Minute)
+
+	// Send weekly progress digests to users whose last one is a week old
+	go a.sendWeeklyDigests(ctx, time.Hour)
+
+	// Nudge users shortly before their personal high-risk hours
+	go a.sendRiskWindowNudges(ctx, 10*time.Minute)
+
+	// Write buffered post view, response and support counts
+	go a.Counters.Run(ctx, 5*time.Second)
+
+	// Recompute cached admin platform metrics
+	go a.refreshPlatformMetrics(ctx, time.Hour)
+
+	// Move stored emails onto the current encryption key
+	go a.reencryptEmails(ctx, 24*time.Hour)
+
+	// Delete or anonymize data past its retention period, or report what would be
+	go a.enforceRetention(ctx, a.Config.Retention.Interval)
+
+	// Apply runtime setting changes made on any instance
+	go a.RuntimeSettings.Watch(ctx)
+	go a.reloadRuntimeSettings(ctx, a.Config.Runtime.RefreshInterval)
+
+	// Export the previous day's data for offline analysis
+	if len(a.Config.Export.DailyDatasets) > 0 {
+		go a.exportDaily(ctx, 24*time.Hour)
+	}
+
+	// Deliver queued push notifications
+	go func() {
+		if err := a.PushDispatcher.Run(ctx); err != nil {
+			a.Logger.Error("Notification dispatcher stopped", zap.Error(err))
+		}
+	}()
+
 	a.Logger.Info("All application components started successfully")
 	return nil
 }
 
+// sendCheckInReminders periodically queues a job sending check-in reminders that have come due
+func (a *Application) sendCheckInReminders(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = a.WorkQueue.Submit("check_in_reminders", workqueue.PriorityNormal, func(ctx context.Context) error {
+				sent, err := a.ReminderService.SendCheckInReminders(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to send check-in reminders: %w", err)
+				}
+				if sent > 0 {
+					a.Logger.Info("Sent check-in reminders", zap.Int("count", sent))
+				}
+				return nil
+			})
+		}
+	}
+}
+
+// sendRiskWindowNudges periodically queues a job nudging users whose risk window is about to start
+func (a *Application) sendRiskWindowNudges(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = a.WorkQueue.Submit("risk_window_nudges", workqueue.PriorityHigh, func(ctx context.Context) error {
+				sent, err := a.ReminderService.SendRiskWindowNudges(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to send risk window nudges: %w", err)
+				}
+				if sent > 0 {
+					a.Logger.Info("Sent risk window nudges", zap.Int("count", sent))
+				}
+				return nil
+			})
+		}
+	}
+}
+
+// sendWeeklyDigests periodically queues a job sending weekly progress digests that have come due
+func (a *Application) sendWeeklyDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = a.WorkQueue.Submit("weekly_digests", workqueue.PriorityLow, func(ctx context.Context) error {
+				sent, err := a.ReminderService.SendWeeklyDigests(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to send weekly digests: %w", err)
+				}
+				if sent > 0 {
+					a.Logger.Info("Sent weekly digests", zap.Int("count", sent))
+				}
+				return nil
+			})
+		}
+	}
+}
+
+// refreshPlatformMetrics periodically queues a job recomputing the cached admin platform metrics
+func (a *Application) refreshPlatformMetrics(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = a.WorkQueue.Submit("platform_metrics", workqueue.PriorityLow, func(ctx context.Context) error {
+			if _, err := a.AdminAnalytics.RefreshPlatformMetrics(ctx); err != nil {
+				return fmt.Errorf("failed to refresh platform metrics: %w", err)
+			}
+			return nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reencryptEmails queues a job re-encrypting stored emails under the current
+// key at startup and then periodically, picking up rows missed by earlier runs
+func (a *Application) reencryptEmails(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = a.WorkQueue.Submit("email_reencryption", workqueue.PriorityLow, func(ctx context.Context) error {
+			rotated, err := a.KeyRotation.ReencryptEmails(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to re-encrypt emails: %w", err)
+			}
+			if rotated > 0 {
+				a.Logger.Info("Re-encrypted emails under the current key",
+					zap.Int("count", rotated),
+					zap.String("key_id", a.EncryptionManager.PrimaryKeyID()))
+			}
+			return nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enforceRetention queues a job applying the retention rules at startup and
+// then periodically. In dry-run mode the job only logs what each rule would change.
+func (a *Application) enforceRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = a.WorkQueue.Submit("data_retention", workqueue.PriorityLow, func(ctx context.Context) error {
+			results, err := a.Retention.Enforce(ctx)
+			for _, result := range results {
+				if result.Records == 0 {
+					continue
+				}
+				message := "Applied retention rule"
+				if result.DryRun {
+					message = "Retention dry run: records would be changed"
+				}
+				a.Logger.Info(message,
+					zap.String("class", string(result.Class)),
+					zap.String("action", string(result.Action)),
+					zap.Time("cutoff", result.Cutoff),
+					zap.Int64("records", result.Records))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to enforce retention: %w", err)
+			}
+			return nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newExportStore returns the S3 bucket exports are written to, or the local
+// export directory when no bucket is configured
+func (a *Application) newExportStore(ctx context.Context) (objectstore.Store, error) {
+	if a.Config.Export.S3Bucket == "" {
+		return objectstore.NewLocalStore(a.Config.Export.Dir), nil
+	}
+	store, err := objectstore.NewS3Store(ctx, objectstore.S3Config{
+		Bucket:   a.Config.Export.S3Bucket,
+		Region:   a.Config.Export.S3Region,
+		Endpoint: a.Config.Export.S3Endpoint,
+		Prefix:   a.Config.Export.S3Prefix,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export store: %w", err)
+	}
+	return store, nil
+}
+
+// exportDaily queues a job exporting the configured datasets for the previous
+// UTC day at startup and then periodically. Reruns for the same day write a
+// new file rather than replacing the earlier one.
+func (a *Application) exportDaily(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = a.WorkQueue.Submit("daily_data_export", workqueue.PriorityLow, func(ctx context.Context) error {
+			return a.Exports.ExportDaily(ctx, time.Now())
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reloadRuntimeSettings periodically reloads runtime settings, catching
+// changes whose announcement this instance missed
+func (a *Application) reloadRuntimeSettings(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := a.RuntimeSettings.Reload(ctx); err != nil && ctx.Err() == nil {
+			a.Logger.Warn("Failed to reload runtime settings", zap.Error(err))
+		}
+	}
+}
+
+// monitorModerationSLA periodically refreshes the moderation queue and SLA metrics
+func (a *Application) monitorModerationSLA(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.ModerationService.GetStats(ctx); err != nil && ctx.Err() == nil {
+			a.Logger.Warn("Failed to refresh moderation SLA metrics", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // Stop gracefully shuts down all application components
 func (a *Application) Stop(ctx context.Context) error {
 	a.Logger.Info("Stopping application components")
@@ -222,11 +940,44 @@ func (a *Application) Stop(ctx context.Context) error {
 	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	// Fail readiness and keep serving until load balancers stop routing here
+	if a.Health != nil {
+		a.Health.StartDraining()
+		a.Logger.Info("Waiting for load balancers to stop routing", zap.Duration("delay", a.Config.Server.ShutdownDelay))
+		select {
+		case <-time.After(a.Config.Server.ShutdownDelay):
+		case <-shutdownCtx.Done():
+		}
+	}
+
+	// Drain WebSocket clients first: HTTP shutdown neither waits for nor closes
+	// upgraded connections, and RPCs keep being served while clients move over
+	if a.WSHub != nil {
+		a.WSHub.Drain(shutdownCtx, a.Config.WebSocket.DrainWindow)
+	}
+
 	// Stop HTTP server
 	if a.HTTPServer != nil {
-		a.Logger.Info("Shutting down HTTP server")
+		a.Logger.Info("Shutting down HTTP server", zap.Int64("in_flight", a.InFlight.Count()))
+		reportCtx, stopReport := context.WithCancel(shutdownCtx)
+		go a.InFlight.Report(reportCtx, a.Logger, time.Second)
 		if err := a.HTTPServer.Shutdown(shutdownCtx); err != nil {
-			a.Logger.Error("Error shutting down HTTP server", zap.Error(err))
+			a.Logger.Error("Error shutting down HTTP server", zap.Error(err), zap.Int64("in_flight", a.InFlight.Count()))
+		}
+		stopReport()
+	}
+
+	// Stop HTTPS redirect listener
+	if a.RedirectServer != nil {
+		if err := a.RedirectServer.Close(); err != nil {
+			a.Logger.Error("Error closing redirect server", zap.Error(err))
+		}
+	}
+
+	// Stop admin server; in-progress profiles are cut short
+	if a.AdminServer != nil {
+		if err := a.AdminServer.Close(); err != nil {
+			a.Logger.Error("Error closing admin server", zap.Error(err))
 		}
 	}
 
@@ -236,6 +987,43 @@ func (a *Application) Stop(ctx context.Context) error {
 		a.WSHub.Stop()
 	}
 
+	// Finish handling domain events raised by the last requests
+	if a.EventBus != nil {
+		a.Logger.Info("Closing event bus")
+		if err := a.EventBus.Close(); err != nil {
+			a.Logger.Error("Error closing event bus", zap.Error(err))
+		}
+	}
+
+	// Finish queued background jobs, including SOS alerts raised by the last events
+	if a.WorkQueue != nil {
+		a.Logger.Info("Closing work queue")
+		if err := a.WorkQueue.Close(shutdownCtx); err != nil {
+			a.Logger.Error("Error closing work queue", zap.Error(err))
+		}
+	}
+
+	// Write queued audit entries before the databases close
+	if a.Audit != nil {
+		a.Logger.Info("Closing audit writer")
+		if err := a.Audit.Close(shutdownCtx); err != nil {
+			a.Logger.Error("Error closing audit writer", zap.Error(err))
+		}
+	}
+
+	// Write buffered counters before the databases close
+	if a.Counters != nil {
+		a.Logger.Info("Flushing buffered counters")
+		if err := a.Counters.Flush(shutdownCtx); err != nil {
+			a.Logger.Error("Error flushing buffered counters", zap.Error(err))
+		}
+	}
+
+	// Send reported errors still in flight
+	if a.ErrorReporter != nil {
+		a.ErrorReporter.Flush(2 * time.Second)
+	}
+
 	// Shutdown tracing
 	if a.TracerProvider != nil {
 		a.Logger.Info("Shutting down tracing")
@@ -244,7 +1032,20 @@ func (a *Application) Stop(ctx context.Context) error {
 		}
 	}
 
+	// Close secret store clients
+	if a.SecretSources != nil {
+		if err := a.SecretSources.Close(); err != nil {
+			a.Logger.Error("Error closing secret manager", zap.Error(err))
+		}
+	}
+
 	// Close database connections
+	if a.Postgres != nil {
+		if err := a.Postgres.CloseReplicas(); err != nil {
+			a.Logger.Error("Error closing PostgreSQL replica connections", zap.Error(err))
+		}
+	}
+
 	if a.PostgresDB != nil {
 		a.Logger.Info("Closing PostgreSQL connection")
 		if err := a.PostgresDB.Close(); err != nil {
@@ -270,92 +1071,304 @@ func (a *Application) Stop(ctx context.Context) error {
 	return nil
 }
 
+// newGraphQLHandler builds the /graphql handler. It is set by graphql.go,
+// which is only compiled with -tags graphql once gqlgen has generated the
+// executable schema.
+var newGraphQLHandler func(a *App) http.Handler
+
+// publicProcedures can be called without an access token. A valid token is
+// still used when present, e.g. to show a viewer their own hidden posts.
+var publicProcedures = []string{
+	authv1connect.AuthServiceRegisterAnonymousProcedure,
+	authv1connect.AuthServiceRegisterWithEmailProcedure,
+	authv1connect.AuthServiceLoginProcedure,
+	authv1connect.AuthServiceVerifyLoginChallengeProcedure,
+	authv1connect.AuthServiceRefreshTokenProcedure,
+	postv1connect.PostServiceGetPostProcedure,
+	postv1connect.PostServiceBatchGetPostsProcedure,
+	postv1connect.PostServiceGetFeedProcedure,
+	postv1connect.PostServiceStreamFeedProcedure,
+	postv2connect.PostServiceGetPostProcedure,
+	postv2connect.PostServiceListFeedProcedure,
+	analyticsv1connect.AnalyticsServiceGetCommunityStatsProcedure,
+	resourcesv1connect.ResourcesServiceListCrisisResourcesProcedure,
+}
+
+// postV1DeprecatedAt is when post.v2 replaced post.v1, announced in v1's
+// Deprecation header
+var postV1DeprecatedAt = time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+
+// cacheableProcedures get ETags, so clients polling them can revalidate with
+// If-None-Match instead of downloading an unchanged response
+var cacheableProcedures = []string{
+	postv1connect.PostServiceGetFeedProcedure,
+	postv2connect.PostServiceListFeedProcedure,
+	circlev1connect.CircleServiceGetCircleFeedProcedure,
+	userv1connect.UserServiceGetProfileProcedure,
+}
+
+// rateLimitPolicy builds the RPC rate limits from config, with limits
+// overridden by runtime settings
+func (a *Application) rateLimitPolicy() middleware.RateLimitPolicy {
+	cfg := a.Config.RateLimit
+	return middleware.RateLimitPolicy{
+		Limits: func(name string) int {
+			settings := a.RuntimeSettings.Current()
+			switch name {
+			case "requests":
+				return settings.RequestsPerMinute
+			case "auth":
+				return settings.AuthRequestsPerMinute
+			case "posts":
+				return settings.PostsPerHour
+			case "responses":
+				return settings.ResponsesPerHour
+			}
+			return 0
+		},
+		Default: ratelimit.Rule{Name: "requests", Limit: cfg.RequestsPerMinute, Window: time.Minute},
+		Procedures: map[string]ratelimit.Rule{
+			"/" + authv1connect.AuthServiceName + "/":              {Name: "auth", Limit: cfg.AuthRequestsPerMinute, Window: time.Minute},
+			postv1connect.PostServiceCreatePostProcedure:           {Name: "posts", Limit: cfg.PostsPerHour, Window: time.Hour},
+			postv2connect.PostServiceCreatePostProcedure:           {Name: "posts", Limit: cfg.PostsPerHour, Window: time.Hour},
+			supportv1connect.SupportServiceCreateResponseProcedure: {Name: "responses", Limit: cfg.ResponsesPerHour, Window: time.Hour},
+		},
+	}
+}
+
 // SetupHTTPServer creates and configures the HTTP server with all handlers and middleware
 func (a *Application) SetupHTTPServer() error {
 	mux := http.NewServeMux()
 
-	// Setup RPC handlers
+	// Setup RPC handlers. Handlers check the caller's role, loaded into the
+	// context by the auth interceptor, against the authorizer's role permissions.
+	authorizer := a.Authorizer
 	authHandler := rpc.NewAuthHandler(a.AuthService)
-	userHandler := rpc.NewUserHandler(a.UserService)
-	postHandler := rpc.NewPostHandler(a.PostService)
+	userHandler := rpc.NewUserHandler(a.UserService, a.BlockService, a.Entitlements)
+	postV2Handler := rpc.NewPostHandlerV2(a.PostService, a.ReactionService)
+	postHandler := rpc.NewPostHandler(postV2Handler, a.PostService)
 	supportHandler := rpc.NewSupportHandler(a.SupportService)
-	circleHandler := rpc.NewCircleHandler(a.CircleService)
-	moderationHandler := rpc.NewModerationHandler(a.ModerationService)
+	circleHandler := rpc.NewCircleHandler(a.CircleService, a.UserService, authorizer)
+	moderationHandler := rpc.NewModerationHandler(a.ModerationService, authorizer)
+	notificationHandler := rpc.NewNotificationHandler(a.NotificationService, a.SOSService)
+	journalHandler := rpc.NewJournalHandler(a.JournalService)
+	progressHandler := rpc.NewProgressHandler(a.ProgressService)
+	analyticsHandler := rpc.NewAnalyticsHandler(a.AdminAnalytics, a.CommunityStats, authorizer)
+	adminHandler := rpc.NewAdminHandler(a.AdminService, a.CircleService, a.Entitlements, a.Exports, a.RuntimeSettings, authorizer)
+	homeHandler := rpc.NewHomeHandler(a.HomeService)
+	resourcesHandler := rpc.NewResourcesHandler(a.CrisisResources)
+
+	limiter := ratelimit.NewLimiter(a.RedisClient)
+
+	// Interceptors run in order around every RPC. Authentication runs before
+	// rate limiting so limits are counted per user rather than per IP.
+	interceptors := connect.WithInterceptors(
+		middleware.NewRPCTracingInterceptor(),
+		middleware.NewRPCMetricsInterceptor(a.SLO),
+		middleware.NewRPCErrorInterceptor(a.ErrorReporter, a.Logger),
+		middleware.NewRPCRecoveryInterceptor(a.ErrorReporter, a.Logger),
+		middleware.NewRPCAuthInterceptor(a.JWTManager, publicProcedures...),
+		middleware.NewRateLimitInterceptor(limiter, a.rateLimitPolicy(), a.Logger),
+	)
 
 	// Register Connect RPC routes
-	authPath, authHTTPHandler := authv1connect.NewAuthServiceHandler(authHandler)
-	userPath, userHTTPHandler := userv1connect.NewUserServiceHandler(userHandler)
-	postPath, postHTTPHandler := postv1connect.NewPostServiceHandler(postHandler)
-	supportPath, supportHTTPHandler := supportv1connect.NewSupportServiceHandler(supportHandler)
-	circlePath, circleHTTPHandler := circlev1connect.NewCircleServiceHandler(circleHandler)
-	moderationPath, moderationHTTPHandler := moderationv1connect.NewModerationServiceHandler(moderationHandler)
+	authPath, authHTTPHandler := authv1connect.NewAuthServiceHandler(authHandler, interceptors)
+	userPath, userHTTPHandler := userv1connect.NewUserServiceHandler(userHandler, interceptors)
+	postPath, postHTTPHandler := postv1connect.NewPostServiceHandler(postHandler, interceptors)
+	postV2Path, postV2HTTPHandler := postv2connect.NewPostServiceHandler(postV2Handler, interceptors)
+	supportPath, supportHTTPHandler := supportv1connect.NewSupportServiceHandler(supportHandler, interceptors)
+	circlePath, circleHTTPHandler := circlev1connect.NewCircleServiceHandler(circleHandler, interceptors)
+	moderationPath, moderationHTTPHandler := moderationv1connect.NewModerationServiceHandler(moderationHandler, interceptors)
+	notificationPath, notificationHTTPHandler := notificationv1connect.NewNotificationServiceHandler(notificationHandler, interceptors)
+	journalPath, journalHTTPHandler := journalv1connect.NewJournalServiceHandler(journalHandler, interceptors)
+	progressPath, progressHTTPHandler := progressv1connect.NewProgressServiceHandler(progressHandler, interceptors)
+	analyticsPath, analyticsHTTPHandler := analyticsv1connect.NewAnalyticsServiceHandler(analyticsHandler, interceptors)
+	adminPath, adminHTTPHandler := adminv1connect.NewAdminServiceHandler(adminHandler, interceptors)
+	homePath, homeHTTPHandler := homev1connect.NewHomeServiceHandler(homeHandler, interceptors)
+	resourcesPath, resourcesHTTPHandler := resourcesv1connect.NewResourcesServiceHandler(resourcesHandler, interceptors)
+
+	// ETags wrap the service handlers rather than the server, so REST routes
+	// for the same procedures get them too
+	etag := middleware.ETagMiddleware(cacheableProcedures...)
+	postV1Deprecation := middleware.DeprecationMiddleware(middleware.Deprecation{
+		Since:     postV1DeprecatedAt,
+		Sunset:    a.Config.HTTP.PostV1Sunset,
+		Successor: postV2Path,
+	})
 
 	mux.Handle(authPath, authHTTPHandler)
-	mux.Handle(userPath, userHTTPHandler)
-	mux.Handle(postPath, postHTTPHandler)
+	mux.Handle(userPath, etag(userHTTPHandler))
+	mux.Handle(postPath, postV1Deprecation(etag(postHTTPHandler)))
+	mux.Handle(postV2Path, etag(postV2HTTPHandler))
 	mux.Handle(supportPath, supportHTTPHandler)
-	mux.Handle(circlePath, circleHTTPHandler)
+	mux.Handle(circlePath, etag(circleHTTPHandler))
 	mux.Handle(moderationPath, moderationHTTPHandler)
+	mux.Handle(notificationPath, notificationHTTPHandler)
+	mux.Handle(journalPath, journalHTTPHandler)
+	mux.Handle(progressPath, progressHTTPHandler)
+	mux.Handle(analyticsPath, analyticsHTTPHandler)
+	mux.Handle(adminPath, adminHTTPHandler)
+	mux.Handle(homePath, homeHTTPHandler)
+	mux.Handle(resourcesPath, resourcesHTTPHandler)
+
+	// REST routes for clients that can't speak Connect. They call the
+	// handlers above in-process, and are documented at /openapi.json.
+	gateway := rest.NewGateway(mux, restRoutes(), publicProcedures...)
+	if err := gateway.Register(mux); err != nil {
+		return fmt.Errorf("invalid REST routes: %w", err)
+	}
+	mux.Handle("GET /openapi.json", gateway.OpenAPIHandler(openapi.Info{
+		Title:   "Anonymous Support API",
+		Version: version,
+	}))
+
+	// Stripe keeps subscriptions in sync; it authenticates with its signature
+	if a.Config.Billing.StripeWebhookSecret != "" {
+		mux.Handle("POST /webhooks/stripe", handler.NewStripeWebhookHandler(a.Entitlements, a.Config.Billing.StripeWebhookSecret, a.Logger))
+	}
+
+	// Read-only GraphQL for the web frontend, composing the same services the
+	// RPC handlers use. Writes stay on Connect.
+	if a.Config.GraphQL.Enabled {
+		if newGraphQLHandler == nil {
+			return fmt.Errorf("GraphQL gateway is not compiled in; run task graphql and build with -tags graphql")
+		}
+		mux.Handle("/graphql", middleware.Chain(
+			newGraphQLHandler(a),
+			middleware.OptionalAuthMiddleware(a.JWTManager),
+			middleware.RateLimitMiddleware(limiter, a.rateLimitPolicy(), a.Logger),
+		))
+	}
 
 	// WebSocket endpoint with auth middleware
-	mux.Handle("/ws", middleware.AuthMiddleware(a.JWTManager)(http.HandlerFunc(a.handleWebSocket)))
+	// WebSocket clients authenticate with a header, the token subprotocol, or a first auth message
+	mux.HandleFunc("/ws", a.handleWebSocket)
 
 	// Health check endpoints
-	healthHandler := handler.NewHealthHandler(a.Logger, a.PostgresDB, a.MongoDB, a.RedisClient, version, a.Config.Server.Env)
-	mux.HandleFunc("/health", healthHandler.Check)
-	mux.HandleFunc("/health/ready", healthHandler.Ready)
-	mux.HandleFunc("/health/live", healthHandler.Live)
+	a.Health = handler.NewHealthHandler(a.Logger, a.PostgresDB, a.MongoDB, a.RedisClient, version, a.Config.Server.Env)
+	mux.HandleFunc("/health", a.Health.Check)
+	mux.HandleFunc("/health/startup", a.Health.Startup)
+	mux.HandleFunc("/health/ready", a.Health.Ready)
+	mux.HandleFunc("/health/live", a.Health.Live)
 
 	// Metrics endpoint
 	mux.Handle("/metrics", promhttp.Handler())
 
+	// Internal clients authenticate with API keys or client certificates
+	serviceAuth, err := authz.NewServiceAuthenticator(a.Config.Service.APIKeys, a.Config.Service.ClientCerts)
+	if err != nil {
+		return fmt.Errorf("invalid service credentials: %w", err)
+	}
+
+	trustedProxies, err := middleware.ParseTrustedProxies(a.Config.Server.TrustedProxies)
+	if err != nil {
+		return err
+	}
+
 	// Setup middleware chain
+	a.InFlight = middleware.NewInFlightTracker()
 	httpHandler := middleware.Chain(
 		mux,
-		middleware.RecoveryMiddleware(a.Logger),
-		middleware.SecurityMiddleware(),
+		a.InFlight.Middleware(),
+		middleware.RecoveryMiddleware(a.ErrorReporter, a.Logger),
+		middleware.SecurityMiddleware(middleware.SecurityHeadersConfig{
+			HSTSMaxAge:     a.Config.HTTP.HSTSMaxAge,
+			FrameAncestors: a.Config.HTTP.FrameAncestors,
+		}),
 		middleware.RequestIDMiddleware(),
+		middleware.LocaleMiddleware(),
+		middleware.ClientInfoMiddleware(geo.Headers{
+			Country:   a.Config.LoginRisk.GeoCountryHeader,
+			Latitude:  a.Config.LoginRisk.GeoLatitudeHeader,
+			Longitude: a.Config.LoginRisk.GeoLongitudeHeader,
+		}, trustedProxies),
+		middleware.ServiceAuthMiddleware(serviceAuth),
 		middleware.TracingMiddleware(),
-		middleware.MetricsMiddleware(),
-		middleware.CORSMiddleware(),
+		middleware.MetricsMiddleware(mux),
+		middleware.CORSMiddleware(middleware.CORSConfig{
+			AllowedOrigins: a.Config.HTTP.CORSAllowedOrigins,
+			AllowedMethods: a.Config.HTTP.CORSAllowedMethods,
+			MaxAge:         a.Config.HTTP.CORSMaxAge,
+		}),
 		middleware.LoggingMiddleware(a.Logger),
 	)
 
-	// Wrap with h2c for HTTP/2 support without TLS (Connect-RPC)
-	h2cHandler := h2c.NewHandler(httpHandler, &http2.Server{})
-
 	// Create HTTP server
 	a.HTTPServer = &http.Server{
 		Addr:         fmt.Sprintf(":%d", a.Config.Server.Port),
-		Handler:      h2cHandler,
 		ReadTimeout:  a.Config.Server.ReadTimeout,
 		WriteTimeout: a.Config.Server.WriteTimeout,
 		IdleTimeout:  a.Config.Server.IdleTimeout,
 	}
 
-	a.Logger.Info("HTTP server configured", zap.Int("port", a.Config.Server.Port))
+	tlsCfg := servertls.Config{
+		CertFile:         a.Config.Server.TLSCertFile,
+		KeyFile:          a.Config.Server.TLSKeyFile,
+		AutocertDomains:  a.Config.Server.TLSAutocertDomains,
+		AutocertCacheDir: a.Config.Server.TLSAutocertCacheDir,
+		AutocertEmail:    a.Config.Server.TLSAutocertEmail,
+		ClientCAFile:     a.Config.Server.TLSClientCAFile,
+	}
+	if tlsCfg.Enabled() {
+		// HTTP/2 is negotiated over TLS by ALPN
+		tlsServer, err := servertls.New(tlsCfg)
+		if err != nil {
+			return err
+		}
+		a.HTTPServer.Handler = httpHandler
+		a.HTTPServer.TLSConfig = tlsServer.TLSConfig
+
+		if a.Config.Server.TLSRedirectAddr != "" {
+			a.RedirectServer = &http.Server{
+				Addr:              a.Config.Server.TLSRedirectAddr,
+				Handler:           tlsServer.RedirectHandler(a.Config.Server.Port),
+				ReadHeaderTimeout: 5 * time.Second,
+			}
+		}
+	} else {
+		// Wrap with h2c for HTTP/2 support without TLS (Connect-RPC)
+		a.HTTPServer.Handler = h2c.NewHandler(httpHandler, &http2.Server{})
+	}
+
+	a.Logger.Info("HTTP server configured",
+		zap.Int("port", a.Config.Server.Port),
+		zap.Bool("tls", a.HTTPServer.TLSConfig != nil))
+
+	// Profiling and runtime diagnostics on a separate, internal-only listener.
+	// CPU profiles and traces stream for up to their requested duration.
+	if a.Config.Server.AdminAddr != "" {
+		a.AdminServer = &http.Server{
+			Addr:              a.Config.Server.AdminAddr,
+			Handler:           handler.NewDebugHandler(a.Config.Server.AdminToken),
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      2 * time.Minute,
+		}
+		a.Logger.Info("Admin server configured",
+			zap.String("address", a.Config.Server.AdminAddr),
+			zap.Bool("token_required", a.Config.Server.AdminToken != ""))
+	}
 	return nil
 }
 
 // handleWebSocket handles WebSocket upgrade and client management
 func (a *Application) handleWebSocket(w http.ResponseWriter, r *http.Request) {
-	userID := middleware.GetUserIDFromContext(r.Context())
-	username := middleware.GetUsernameFromContext(r.Context())
+	if a.WSHub.Draining() {
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, "server restarting", http.StatusServiceUnavailable)
+		return
+	}
 
-	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	conn, err := a.WSUpgrader.Upgrade(w, r, nil)
 	if err != nil {
-		a.Logger.Error("Failed to upgrade WebSocket connection", zap.Error(err))
+		a.Logger.Warn("Failed to upgrade WebSocket connection", zap.Error(err))
 		return
 	}
 
-	client := wsHandler.NewClient(a.WSHub, conn, userID, username)
-	a.WSHub.Register <- client
-
-	go client.WritePump()
-	go client.ReadPump()
+	a.WSHub.ServeConn(conn, wsHandler.RequestToken(r), wsHandler.NegotiateVersion(r))
 }
 
-// Run starts the HTTP server and blocks until shutdown
+// Run starts the HTTP server and blocks until ctx is done or the server
+// fails. The caller shuts the application down with Stop.
 func (a *Application) Run(ctx context.Context) error {
 	// Start background components
 	if err := a.Start(ctx); err != nil {
@@ -368,12 +1381,37 @@ func (a *Application) Run(ctx context.Context) error {
 	}
 
 	// Start HTTP server in a goroutine
-	serverErrors := make(chan error, 1)
+	serverErrors := make(chan error, 2)
 	go func() {
 		a.Logger.Info("Starting HTTP server", zap.String("address", a.HTTPServer.Addr))
+		if a.HTTPServer.TLSConfig != nil {
+			// Certificates come from TLSConfig
+			serverErrors <- a.HTTPServer.ListenAndServeTLS("", "")
+			return
+		}
 		serverErrors <- a.HTTPServer.ListenAndServe()
 	}()
 
+	if a.RedirectServer != nil {
+		go func() {
+			a.Logger.Info("Starting HTTPS redirect server", zap.String("address", a.RedirectServer.Addr))
+			if err := a.RedirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				serverErrors <- fmt.Errorf("redirect server: %w", err)
+			}
+		}()
+	}
+
+	// The admin server is for diagnostics only, so its failure is logged
+	// rather than stopping the application
+	if a.AdminServer != nil {
+		go func() {
+			a.Logger.Info("Starting admin server", zap.String("address", a.AdminServer.Addr))
+			if err := a.AdminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				a.Logger.Error("Admin server failed", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for shutdown signal or server error
 	select {
 	case err := <-serverErrors:
@@ -381,10 +1419,6 @@ func (a *Application) Run(ctx context.Context) error {
 			return fmt.Errorf("server error: %w", err)
 		}
 	case <-ctx.Done():
-		a.Logger.Info("Shutdown signal received")
-		if err := a.Stop(ctx); err != nil {
-			return fmt.Errorf("graceful shutdown failed: %w", err)
-		}
 	}
 
 	return nil
@@ -0,0 +1,16 @@
+//go:build graphql
+
+package app
+
+import (
+	"net/http"
+
+	"github.com/yourorg/anonymous-support/internal/graph"
+)
+
+func init() {
+	newGraphQLHandler = func(a *App) http.Handler {
+		resolver := graph.NewResolver(a.PostService, a.SupportService, a.UserService, a.ProgressService, a.ReactionService)
+		return graph.NewHandler(resolver, a.Config.GraphQL.MaxComplexity, a.Config.Server.Env != "production", a.Logger)
+	}
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"net/http"
+
+	adminv1connect "github.com/yourorg/anonymous-support/gen/admin/v1/adminv1connect"
+	analyticsv1connect "github.com/yourorg/anonymous-support/gen/analytics/v1/analyticsv1connect"
+	authv1connect "github.com/yourorg/anonymous-support/gen/auth/v1/authv1connect"
+	circlev1connect "github.com/yourorg/anonymous-support/gen/circle/v1/circlev1connect"
+	homev1connect "github.com/yourorg/anonymous-support/gen/home/v1/homev1connect"
+	journalv1connect "github.com/yourorg/anonymous-support/gen/journal/v1/journalv1connect"
+	moderationv1connect "github.com/yourorg/anonymous-support/gen/moderation/v1/moderationv1connect"
+	notificationv1connect "github.com/yourorg/anonymous-support/gen/notification/v1/notificationv1connect"
+	postv1connect "github.com/yourorg/anonymous-support/gen/post/v1/postv1connect"
+	postv2connect "github.com/yourorg/anonymous-support/gen/post/v2/postv2connect"
+	progressv1connect "github.com/yourorg/anonymous-support/gen/progress/v1/progressv1connect"
+	resourcesv1connect "github.com/yourorg/anonymous-support/gen/resources/v1/resourcesv1connect"
+	supportv1connect "github.com/yourorg/anonymous-support/gen/support/v1/supportv1connect"
+	userv1connect "github.com/yourorg/anonymous-support/gen/user/v1/userv1connect"
+	"github.com/yourorg/anonymous-support/internal/handler/rest"
+)
+
+// restRoutes maps REST paths onto the unary RPCs. Path wildcards are request
+// field names; other fields come from the query string for GET and DELETE,
+// and from the JSON body otherwise.
+func restRoutes() []rest.Route {
+	return []rest.Route{
+		{Method: http.MethodPost, Pattern: "/v1/auth/anonymous", Procedure: authv1connect.AuthServiceRegisterAnonymousProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/auth/register", Procedure: authv1connect.AuthServiceRegisterWithEmailProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/auth/login", Procedure: authv1connect.AuthServiceLoginProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/auth/login/verify", Procedure: authv1connect.AuthServiceVerifyLoginChallengeProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/auth/refresh", Procedure: authv1connect.AuthServiceRefreshTokenProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/auth/logout", Procedure: authv1connect.AuthServiceLogoutProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/home", Procedure: homev1connect.HomeServiceGetHomeScreenProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/crisis-resources", Procedure: resourcesv1connect.ResourcesServiceListCrisisResourcesProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/users/batch", Procedure: userv1connect.UserServiceBatchGetUsersProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/users/{user_id}", Procedure: userv1connect.UserServiceGetProfileProcedure},
+		{Method: http.MethodPatch, Pattern: "/v1/users/{user_id}", Procedure: userv1connect.UserServiceUpdateProfileProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/users/{user_id}/streak", Procedure: userv1connect.UserServiceGetStreakProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/users/{user_id}/streak", Procedure: userv1connect.UserServiceUpdateStreakProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/users/{user_id}/support-stats", Procedure: supportv1connect.SupportServiceGetSupportStatsProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/me/moods", Procedure: userv1connect.UserServiceGetMoodHistoryProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/me/relapses", Procedure: userv1connect.UserServiceRecordRelapseProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/me/streak-freezes", Procedure: userv1connect.UserServiceGetStreakFreezesProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/me/streak-freezes/use", Procedure: userv1connect.UserServiceUseStreakFreezeProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/me/savings-baseline", Procedure: userv1connect.UserServiceSetSavingsBaselineProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/me/blocks/{user_id}", Procedure: userv1connect.UserServiceBlockUserProcedure},
+		{Method: http.MethodDelete, Pattern: "/v1/me/blocks/{user_id}", Procedure: userv1connect.UserServiceUnblockUserProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/me/entitlements", Procedure: userv1connect.UserServiceGetEntitlementsProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/me/help-categories", Procedure: notificationv1connect.NotificationServiceGetHelpCategoriesProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/me/help-categories", Procedure: notificationv1connect.NotificationServiceUpdateHelpCategoriesProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/posts", Procedure: postv1connect.PostServiceGetFeedProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/posts", Procedure: postv1connect.PostServiceCreatePostProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/posts/batch", Procedure: postv1connect.PostServiceBatchGetPostsProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/posts/{post_id}", Procedure: postv1connect.PostServiceGetPostProcedure},
+		{Method: http.MethodDelete, Pattern: "/v1/posts/{post_id}", Procedure: postv1connect.PostServiceDeletePostProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/posts/{post_id}/urgency", Procedure: postv1connect.PostServiceUpdatePostUrgencyProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/posts/{post_id}/responses", Procedure: supportv1connect.SupportServiceGetResponsesProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/posts/{post_id}/responses", Procedure: supportv1connect.SupportServiceCreateResponseProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/posts/{post_id}/quick-support", Procedure: supportv1connect.SupportServiceQuickSupportProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v2/posts", Procedure: postv2connect.PostServiceListFeedProcedure},
+		{Method: http.MethodPost, Pattern: "/v2/posts", Procedure: postv2connect.PostServiceCreatePostProcedure},
+		{Method: http.MethodGet, Pattern: "/v2/posts/{post_id}", Procedure: postv2connect.PostServiceGetPostProcedure},
+		{Method: http.MethodDelete, Pattern: "/v2/posts/{post_id}", Procedure: postv2connect.PostServiceDeletePostProcedure},
+		{Method: http.MethodPut, Pattern: "/v2/posts/{post_id}/reaction", Procedure: postv2connect.PostServiceAddReactionProcedure},
+		{Method: http.MethodDelete, Pattern: "/v2/posts/{post_id}/reaction", Procedure: postv2connect.PostServiceRemoveReactionProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/circles", Procedure: circlev1connect.CircleServiceGetCirclesProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/circles", Procedure: circlev1connect.CircleServiceCreateCircleProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/circles/{circle_id}/join", Procedure: circlev1connect.CircleServiceJoinCircleProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/circles/{circle_id}/leave", Procedure: circlev1connect.CircleServiceLeaveCircleProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/circles/{circle_id}/feed", Procedure: circlev1connect.CircleServiceGetCircleFeedProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/circles/{circle_id}/members", Procedure: circlev1connect.CircleServiceGetCircleMembersProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/circles/{circle_id}/members/{user_id}/role", Procedure: circlev1connect.CircleServiceSetMemberRoleProcedure},
+		{Method: http.MethodDelete, Pattern: "/v1/circles/{circle_id}/members/{user_id}", Procedure: circlev1connect.CircleServiceRemoveMemberProcedure},
+
+		{Method: http.MethodPost, Pattern: "/v1/reports", Procedure: moderationv1connect.ModerationServiceReportContentProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/reports", Procedure: moderationv1connect.ModerationServiceGetReportsProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/reports/{report_id}", Procedure: moderationv1connect.ModerationServiceGetReportProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/reports/{report_id}/actions", Procedure: moderationv1connect.ModerationServiceModerateContentProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/reports/{report_id}/notes", Procedure: moderationv1connect.ModerationServiceAddModeratorNoteProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/moderation/stats", Procedure: moderationv1connect.ModerationServiceGetModerationStatsProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/notifications", Procedure: notificationv1connect.NotificationServiceListNotificationsProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/notifications/read", Procedure: notificationv1connect.NotificationServiceMarkReadProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/notifications/read-all", Procedure: notificationv1connect.NotificationServiceMarkAllReadProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/notifications/unread-count", Procedure: notificationv1connect.NotificationServiceGetUnreadCountProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/notifications/preferences", Procedure: notificationv1connect.NotificationServiceGetPreferencesProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/notifications/preferences", Procedure: notificationv1connect.NotificationServiceUpdatePreferencesProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/journal", Procedure: journalv1connect.JournalServiceListJournalEntriesProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/journal", Procedure: journalv1connect.JournalServiceCreateJournalEntryProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/progress/dashboard", Procedure: progressv1connect.ProgressServiceGetDashboardProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/progress/check-ins", Procedure: progressv1connect.ProgressServiceRecordCheckInProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/progress/check-in-questions", Procedure: progressv1connect.ProgressServiceListCheckInQuestionsProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/progress/cravings", Procedure: progressv1connect.ProgressServiceRecordCravingProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/progress/relapses", Procedure: progressv1connect.ProgressServiceRecordRelapseProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/progress/achievements", Procedure: progressv1connect.ProgressServiceGetAchievementsProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/progress/coping-strategies", Procedure: progressv1connect.ProgressServiceListCopingStrategiesProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/progress/coping-strategies/{strategy_id}/uses", Procedure: progressv1connect.ProgressServiceRecordCopingStrategyUseProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/community/stats", Procedure: analyticsv1connect.AnalyticsServiceGetCommunityStatsProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/analytics/platform", Procedure: analyticsv1connect.AnalyticsServiceGetPlatformMetricsProcedure},
+
+		{Method: http.MethodGet, Pattern: "/v1/admin/users", Procedure: adminv1connect.AdminServiceSearchUsersProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/admin/users/{user_id}/ban", Procedure: adminv1connect.AdminServiceBanUserProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/admin/users/{user_id}/unban", Procedure: adminv1connect.AdminServiceUnbanUserProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/admin/users/{user_id}/role", Procedure: adminv1connect.AdminServiceSetUserRoleProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/admin/users/{user_id}/plan", Procedure: adminv1connect.AdminServiceSetUserPlanProcedure},
+		{Method: http.MethodDelete, Pattern: "/v1/admin/circles/{circle_id}", Procedure: adminv1connect.AdminServiceDeleteCircleProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/admin/circles/{circle_id}/members/{user_id}/role", Procedure: adminv1connect.AdminServiceSetCircleMemberRoleProcedure},
+		{Method: http.MethodDelete, Pattern: "/v1/admin/circles/{circle_id}/members/{user_id}", Procedure: adminv1connect.AdminServiceRemoveCircleMemberProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/admin/feature-flags", Procedure: adminv1connect.AdminServiceListFeatureFlagsProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/admin/feature-flags/{key}", Procedure: adminv1connect.AdminServiceSetFeatureFlagProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/admin/stats", Procedure: adminv1connect.AdminServiceGetSystemStatsProcedure},
+		{Method: http.MethodPost, Pattern: "/v1/admin/exports", Procedure: adminv1connect.AdminServiceStartDataExportProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/admin/exports", Procedure: adminv1connect.AdminServiceListDataExportsProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/admin/exports/{export_id}", Procedure: adminv1connect.AdminServiceGetDataExportProcedure},
+		{Method: http.MethodGet, Pattern: "/v1/admin/settings", Procedure: adminv1connect.AdminServiceListRuntimeSettingsProcedure},
+		{Method: http.MethodPut, Pattern: "/v1/admin/settings/{key}", Procedure: adminv1connect.AdminServiceSetRuntimeSettingProcedure},
+	}
+}
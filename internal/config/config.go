@@ -1,14 +1,25 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+	"github.com/yourorg/anonymous-support/internal/pkg/secrets"
+	"go.uber.org/zap"
 )
 
 type Config struct {
 	Server     ServerConfig
+	HTTP       HTTPSecurityConfig
+	Service    ServiceAuthConfig
 	Postgres   PostgresConfig
 	MongoDB    MongoDBConfig
 	Redis      RedisConfig
@@ -17,6 +28,22 @@ type Config struct {
 	RateLimit  RateLimitConfig
 	WebSocket  WebSocketConfig
 	Moderation ModerationConfig
+	Push       PushConfig
+	Email      EmailConfig
+	SOS        SOSConfig
+	Progress   ProgressConfig
+	GraphQL    GraphQLConfig
+	Billing    BillingConfig
+	Events     EventsConfig
+	WorkQueue  WorkQueueConfig
+	Audit      AuditConfig
+	Retention  RetentionConfig
+	Export     ExportConfig
+	Runtime    RuntimeSettingsConfig
+	LoginRisk  LoginRiskConfig
+	Errors     ErrorReportingConfig
+	Secrets    SecretsConfig
+	SLO        SLOConfig
 	Timeouts   TimeoutConfig
 }
 
@@ -26,6 +53,50 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+
+	// ShutdownDelay keeps serving with readiness failing before the listener
+	// closes, giving load balancers time to stop routing to the instance
+	ShutdownDelay time.Duration
+
+	// StartupMaxWait is how long the server retries PostgreSQL, MongoDB and
+	// Redis at boot before giving up
+	StartupMaxWait time.Duration
+
+	// AdminAddr is the listen address of the pprof and expvar endpoints,
+	// off when empty. Keep it on loopback or a port the Service doesn't expose.
+	AdminAddr  string
+	AdminToken string // Bearer token required by the admin listener when set
+
+	// TLS is terminated by the server when a certificate or autocert domains
+	// are set; otherwise it serves h2c behind a proxy
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSAutocertDomains  []string // Let's Encrypt certificates are issued for these hosts
+	TLSAutocertCacheDir string
+	TLSAutocertEmail    string
+	TLSRedirectAddr     string // Plain HTTP listener redirecting to HTTPS, off when empty
+	TLSClientCAFile     string // CAs of internal clients' certificates, optional
+
+	// TrustedProxies lists the CIDRs of proxies whose X-Forwarded-For,
+	// X-Real-IP and geo headers are believed. When empty, the client IP is
+	// the connection's peer address and those headers are ignored.
+	TrustedProxies []string
+}
+
+// ServiceAuthConfig lists the credentials of trusted internal clients
+type ServiceAuthConfig struct {
+	APIKeys     []string // name:role:sha256-hex-of-key
+	ClientCerts []string // common-name:role, verified against SERVER_TLS_CLIENT_CA_FILE
+}
+
+// HTTPSecurityConfig configures CORS and the headers the API adds to responses
+type HTTPSecurityConfig struct {
+	CORSAllowedOrigins []string      // Browser origins allowed to call the API; "*" allows any outside production
+	CORSAllowedMethods []string      // Methods cross-origin requests may use
+	CORSMaxAge         time.Duration // How long browsers may cache preflight results
+	HSTSMaxAge         time.Duration // Strict-Transport-Security max-age; off in development
+	FrameAncestors     []string      // CSP frame-ancestors sources; empty forbids framing
+	PostV1Sunset       time.Time     // When post.v1 stops being served, sent in its Sunset header; zero until scheduled
 }
 
 type TimeoutConfig struct {
@@ -41,11 +112,23 @@ type PostgresConfig struct {
 	Password string
 	Database string
 	SSLMode  string
+
+	ReplicaDSNs   []string      // Read replicas for lag-tolerant queries; empty reads from the primary
+	ReplicaMaxLag time.Duration // Replicas lagging further behind stop serving reads
+
+	// Connection pool, applied to the primary and each replica
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 }
 
 type MongoDBConfig struct {
 	URI      string
 	Database string
+
+	MaxPoolSize int
+	MinPoolSize int
 }
 
 type RedisConfig struct {
@@ -53,6 +136,24 @@ type RedisConfig struct {
 	Port     int
 	Password string
 	DB       int
+
+	PoolSize     int
+	MinIdleConns int
+}
+
+// poolDefaults are the connection pool sizes used when none are configured
+type poolDefaults struct {
+	postgresOpen, postgresIdle int
+	mongoMax, mongoMin         int
+	redisSize, redisIdle       int
+}
+
+// defaultPools keeps development pools small so local databases aren't
+// exhausted by several running instances
+var defaultPools = map[string]poolDefaults{
+	"development": {postgresOpen: 10, postgresIdle: 2, mongoMax: 20, mongoMin: 2, redisSize: 10, redisIdle: 2},
+	"staging":     {postgresOpen: 25, postgresIdle: 5, mongoMax: 100, mongoMin: 10, redisSize: 50, redisIdle: 10},
+	"production":  {postgresOpen: 25, postgresIdle: 5, mongoMax: 100, mongoMin: 10, redisSize: 50, redisIdle: 10},
 }
 
 type JWTConfig struct {
@@ -62,32 +163,251 @@ type JWTConfig struct {
 }
 
 type EncryptionConfig struct {
-	Key string
+	Key   string
+	KeyID string // ID stored in ciphertexts written with Key
+
+	// PreviousKeyIDs are retired keys kept for decryption until stored values
+	// are re-encrypted; each is read from the secret ENCRYPTION_KEY_<ID>
+	PreviousKeyIDs []string
+	IndexKeyID     string // Key blind indexes are derived from; must not change once indexes are stored
+
+	// SensitiveCategories are post categories whose post and response content
+	// is encrypted at rest
+	SensitiveCategories []string
 }
 
+// RateLimitConfig holds the limits enforced across all instances through Redis
 type RateLimitConfig struct {
-	PostsPerHour     int
-	ResponsesPerHour int
+	PostsPerHour          int
+	ResponsesPerHour      int
+	RequestsPerMinute     int // Every RPC, per user or, when signed out, per client IP
+	AuthRequestsPerMinute int // Auth RPCs (register, login, refresh) per client IP
 }
 
 type WebSocketConfig struct {
-	ReadBufferSize  int
-	WriteBufferSize int
-	MaxMessageSize  int
+	ReadBufferSize   int
+	WriteBufferSize  int
+	MaxMessageSize   int
+	AllowedOrigins   []string      // Browser origins allowed to connect besides the API's own; "*" allows any
+	AuthTimeout      time.Duration // How long a client without a token has to send its auth message
+	DrainWindow      time.Duration // Clients are disconnected gradually over this window on shutdown
+	SendBufferSize   int           // Outbound messages queued per client before SlowClientPolicy applies
+	SlowClientPolicy string        // drop_oldest or disconnect
+	RealtimeSource   string        // events (domain events from the services) or change_streams (MongoDB inserts)
+}
+
+// PushConfig configures push notification providers
+type PushConfig struct {
+	FCMProjectID         string // Defaults to the project in the service account credentials
+	FCMCredentialsSecret string // Secret key holding the FCM service account JSON
+	APNSKeyID            string
+	APNSTeamID           string
+	APNSBundleID         string
+	APNSKeySecret        string        // Secret key holding the APNS .p8 key contents
+	APNSProduction       bool          // Use the production gateway instead of the sandbox
+	DispatchWorkers      int           // Concurrent delivery workers per instance
+	DispatchMaxAttempts  int           // Deliveries are dead-lettered after this many failures
+	DispatchRetryDelay   time.Duration // Initial retry backoff, doubled on each attempt
+	DispatchMaxDelay     time.Duration // Upper bound on retry backoff
+	CollapseWindow       time.Duration // Pushes sharing a collapse key within this window are summarised
+}
+
+// EmailConfig configures outbound email through an SMTP relay
+type EmailConfig struct {
+	SMTPHost           string // Email delivery is disabled when empty
+	SMTPPort           int
+	SMTPUsername       string
+	SMTPPasswordSecret string // Secret key holding the SMTP password
+	From               string
+}
+
+// SOSConfig bounds how many helpers are alerted for each SOS post
+type SOSConfig struct {
+	MaxRecipients       int    // Online subscribed helpers notified per SOS post
+	CandidatePool       int    // Random subscribers sampled before filtering to those online
+	CrisisResourcesFile string // JSON list of crisis resources added to the built-in ones
+}
+
+// ProgressConfig configures recovery progress tracking
+type ProgressConfig struct {
+	AchievementsFile      string // JSON list of achievement definitions added to the built-in ones
+	StreakFreezesPerMonth int    // Missed days a month a streak survives; negative disables freezes
+	CheckInQuestionsFile  string // JSON list of check-in questions replacing the built-in ones
+}
+
+// BillingConfig controls plans and the Stripe webhook that keeps them in sync
+type BillingConfig struct {
+	EnforceEntitlements bool     // When false, every user has every entitlement
+	StripeWebhookSecret string   // Signing secret of the Stripe webhook endpoint; the webhook is off when empty
+	StripePricePlans    []string // price_id:plan entries mapping Stripe prices to plans
+}
+
+// GraphQLConfig controls the read-only GraphQL endpoint at /graphql
+type GraphQLConfig struct {
+	Enabled       bool
+	MaxComplexity int // Most fields a query may select; 0 uses the default
+}
+
+// EventsConfig selects the broker that carries domain events between services
+type EventsConfig struct {
+	Driver        string   // memory, redis, nats or kafka; nats and kafka need their build tag
+	MaxDeliveries int      // Attempts at handling an event before it is dropped
+	NATSURL       string   // NATS server URL for the nats driver
+	KafkaBrokers  []string // Broker addresses for the kafka driver
+}
+
+// WorkQueueConfig configures the background job queue
+type WorkQueueConfig struct {
+	Workers int // Jobs run concurrently per instance, highest priority first
+}
+
+// ErrorReportingConfig configures the external error tracker
+type ErrorReportingConfig struct {
+	SentryDSN string // Reporting is off when empty, and outside production and staging
+	Release   string // Release tag on reported events; defaults to the build version
+}
+
+// SecretsConfig configures where secrets are read from besides the
+// environment. Configured backends are consulted in the order Vault, AWS
+// Secrets Manager, GCP Secret Manager, then the environment.
+type SecretsConfig struct {
+	VaultAddr      string
+	VaultNamespace string
+	VaultToken     string
+	VaultRoleID    string // AppRole login is used instead of VaultToken when set
+	VaultSecretID  string
+	VaultMount     string // KV v2 mount
+	VaultPath      string // KV v2 secret whose fields are the secrets, e.g. JWT_SECRET
+
+	AWSRegion  string // AWS Secrets Manager is used when set
+	AWSPrefix  string
+	GCPProject string // GCP Secret Manager is used when set
+	GCPPrefix  string
+	CacheTTL   time.Duration
+}
+
+// Sources returns the secret backend settings
+func (c SecretsConfig) Sources() secrets.Config {
+	return secrets.Config{
+		Vault: secrets.VaultConfig{
+			Address:   c.VaultAddr,
+			Namespace: c.VaultNamespace,
+			Token:     c.VaultToken,
+			RoleID:    c.VaultRoleID,
+			SecretID:  c.VaultSecretID,
+			Mount:     c.VaultMount,
+			Path:      c.VaultPath,
+		},
+		AWS:      secrets.AWSConfig{Region: c.AWSRegion, Prefix: c.AWSPrefix},
+		GCP:      secrets.GCPConfig{ProjectID: c.GCPProject, Prefix: c.GCPPrefix},
+		CacheTTL: c.CacheTTL,
+	}
+}
+
+// SLOConfig sets the objectives RPC services are measured against
+type SLOConfig struct {
+	AvailabilityTarget float64       // Fraction of RPCs that must not fail server-side
+	LatencyTarget      time.Duration // Unary RPCs slower than this miss the latency objective
+	Window             time.Duration // Rolling window of the in-process compliance gauges
+}
+
+// AuditConfig configures the audit log writer
+type AuditConfig struct {
+	BufferSize int // Entries queued for writing before new ones are dropped
+}
+
+// RetentionConfig configures how long each class of data is kept
+type RetentionConfig struct {
+	// Rules are "class:max_age:action" entries. Classes are posts, responses,
+	// audit_logs and analytics_events; actions are delete and anonymize.
+	Rules    []string
+	Enforce  bool          // Rules only report what they would change until set
+	Interval time.Duration // How often rules run
+}
+
+// ExportConfig configures admin exports of moderation and analytics data.
+// Exports go to S3 when a bucket is set, otherwise to a local directory.
+type ExportConfig struct {
+	Dir        string // Local directory exports are written under
+	S3Bucket   string
+	S3Region   string
+	S3Endpoint string // For S3-compatible stores such as MinIO; empty for AWS
+	S3Prefix   string // Prepended to object keys
+	// ExcludedColumns are "dataset.column" entries left out of every export,
+	// on top of the PII columns that are never exported
+	ExcludedColumns []string
+	DailyDatasets   []string // Datasets exported each day for the previous UTC day; none when empty
+}
+
+// RuntimeSettingsConfig configures how instances pick up runtime setting
+// changes. Changes are announced over Redis; the refresh catches any missed.
+type RuntimeSettingsConfig struct {
+	RefreshInterval time.Duration
+}
+
+// LoginRiskConfig configures login anomaly detection. Locations come from
+// headers the edge proxy sets; the ingress must overwrite any sent by clients.
+type LoginRiskConfig struct {
+	GeoCountryHeader   string        // ISO country code header; geo checks are off when empty
+	GeoLatitudeHeader  string        // Coordinate headers; impossible travel
+	GeoLongitudeHeader string        // checks are off unless both are set
+	MaxTravelSpeedKMH  float64       // Faster travel between logins is impossible
+	IPFailureThreshold int           // Failed logins from an IP that mark it as risky
+	IPFailureWindow    time.Duration // How long failed logins count against an IP
 }
 
 type ModerationConfig struct {
 	EnableAutoModeration bool
 	ProfanityFilterLevel string
+	Languages            []string      // Languages with dedicated term lists (en, es, fr, de, pt)
+	BanEvasionWindow     time.Duration // How long a banned account's fingerprints are matched against new accounts
+	AutoModeration       AutoModerationConfig
+	SLA                  ModerationSLAConfig
 }
 
-func Load() (*Config, error) {
-	viper.SetConfigFile(".env")
-	viper.AutomaticEnv()
+// ModerationSLAConfig holds the target time to resolve a report for each severity
+type ModerationSLAConfig struct {
+	Critical time.Duration
+	High     time.Duration
+	Medium   time.Duration
+	Low      time.Duration
+}
+
+// BySeverity returns the SLAs keyed by report severity
+func (c ModerationSLAConfig) BySeverity() map[string]time.Duration {
+	return map[string]time.Duration{
+		"critical": c.Critical,
+		"high":     c.High,
+		"medium":   c.Medium,
+		"low":      c.Low,
+	}
+}
+
+// AutoModerationConfig holds the thresholds and actions used by the auto-moderation rules engine
+type AutoModerationConfig struct {
+	ToxicityThreshold      float64  // Toxicity score (0.0 to 1.0) at or above which content is actioned
+	ReportThreshold        int      // Number of reports on a single piece of content that triggers action
+	AbuseSeverityThreshold string   // Minimum abuse detection severity: low, medium, high, critical
+	Actions                []string // Actions taken when a rule triggers: hide, notify_author, open_case
+}
+
+// DefaultEnvFile is read from the working directory, when present, beneath
+// any config file
+const DefaultEnvFile = ".env"
 
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to read config: %w", err)
+// Load reads settings from, lowest to highest precedence: DefaultEnvFile,
+// configFile (or CONFIG_FILE when configFile is empty), and environment
+// variables. Config files are YAML, TOML, JSON or .env and use the same
+// keys as the environment. Neither file is required, so deployments can be
+// configured by the environment alone, but a named config file must exist.
+func Load(configFile string) (*Config, error) {
+	if configFile == "" {
+		configFile = os.Getenv("CONFIG_FILE")
 	}
+	if err := readConfigFiles(configFile); err != nil {
+		return nil, err
+	}
+	viper.AutomaticEnv()
 
 	accessExpiry, err := time.ParseDuration(viper.GetString("JWT_ACCESS_EXPIRY"))
 	if err != nil {
@@ -102,6 +422,33 @@ func Load() (*Config, error) {
 	readTimeout, _ := time.ParseDuration(viper.GetString("SERVER_READ_TIMEOUT"))
 	writeTimeout, _ := time.ParseDuration(viper.GetString("SERVER_WRITE_TIMEOUT"))
 	idleTimeout, _ := time.ParseDuration(viper.GetString("SERVER_IDLE_TIMEOUT"))
+	shutdownDelay, _ := time.ParseDuration(viper.GetString("SERVER_SHUTDOWN_DELAY"))
+	startupMaxWait, _ := time.ParseDuration(viper.GetString("SERVER_STARTUP_MAX_WAIT"))
+	corsMaxAge, _ := time.ParseDuration(viper.GetString("CORS_MAX_AGE"))
+	hstsMaxAge, _ := time.ParseDuration(viper.GetString("HSTS_MAX_AGE"))
+	postV1Sunset, _ := time.Parse(time.DateOnly, viper.GetString("POST_V1_SUNSET"))
+	secretsCacheTTL, err := time.ParseDuration(viper.GetString("SECRETS_CACHE_TTL"))
+	if err != nil {
+		secretsCacheTTL = 5 * time.Minute
+	}
+	sloLatencyTarget, _ := time.ParseDuration(viper.GetString("SLO_LATENCY_TARGET"))
+	sloWindow, _ := time.ParseDuration(viper.GetString("SLO_WINDOW"))
+	banEvasionWindow, _ := time.ParseDuration(viper.GetString("BAN_EVASION_WINDOW"))
+	ipFailureWindow, _ := time.ParseDuration(viper.GetString("LOGIN_IP_FAILURE_WINDOW"))
+	retentionInterval, _ := time.ParseDuration(viper.GetString("RETENTION_INTERVAL"))
+	runtimeSettingsRefresh, _ := time.ParseDuration(viper.GetString("RUNTIME_SETTINGS_REFRESH_INTERVAL"))
+	slaCritical, _ := time.ParseDuration(viper.GetString("MODERATION_SLA_CRITICAL"))
+	slaHigh, _ := time.ParseDuration(viper.GetString("MODERATION_SLA_HIGH"))
+	slaMedium, _ := time.ParseDuration(viper.GetString("MODERATION_SLA_MEDIUM"))
+	slaLow, _ := time.ParseDuration(viper.GetString("MODERATION_SLA_LOW"))
+	dispatchRetryDelay, _ := time.ParseDuration(viper.GetString("NOTIFICATION_DISPATCH_RETRY_DELAY"))
+	dispatchMaxDelay, _ := time.ParseDuration(viper.GetString("NOTIFICATION_DISPATCH_MAX_DELAY"))
+	collapseWindow, _ := time.ParseDuration(viper.GetString("NOTIFICATION_COLLAPSE_WINDOW"))
+	wsAuthTimeout, _ := time.ParseDuration(viper.GetString("WS_AUTH_TIMEOUT"))
+	wsDrainWindow, _ := time.ParseDuration(viper.GetString("WS_DRAIN_WINDOW"))
+	replicaMaxLag, _ := time.ParseDuration(viper.GetString("POSTGRES_REPLICA_MAX_LAG"))
+	postgresConnMaxLifetime, _ := time.ParseDuration(viper.GetString("POSTGRES_CONN_MAX_LIFETIME"))
+	postgresConnMaxIdleTime, _ := time.ParseDuration(viper.GetString("POSTGRES_CONN_MAX_IDLE_TIME"))
 	dbTimeout, _ := time.ParseDuration(viper.GetString("DB_TIMEOUT"))
 	httpTimeout, _ := time.ParseDuration(viper.GetString("HTTP_TIMEOUT"))
 	contextTimeout, _ := time.ParseDuration(viper.GetString("CONTEXT_TIMEOUT"))
@@ -113,6 +460,33 @@ func Load() (*Config, error) {
 			ReadTimeout:  readTimeout,
 			WriteTimeout: writeTimeout,
 			IdleTimeout:  idleTimeout,
+
+			ShutdownDelay:  shutdownDelay,
+			StartupMaxWait: startupMaxWait,
+
+			AdminAddr:  viper.GetString("SERVER_ADMIN_ADDR"),
+			AdminToken: viper.GetString("SERVER_ADMIN_TOKEN"),
+
+			TLSCertFile:         viper.GetString("SERVER_TLS_CERT_FILE"),
+			TLSKeyFile:          viper.GetString("SERVER_TLS_KEY_FILE"),
+			TLSAutocertDomains:  getList("SERVER_TLS_AUTOCERT_DOMAINS"),
+			TLSAutocertCacheDir: viper.GetString("SERVER_TLS_AUTOCERT_CACHE_DIR"),
+			TLSAutocertEmail:    viper.GetString("SERVER_TLS_AUTOCERT_EMAIL"),
+			TLSRedirectAddr:     viper.GetString("SERVER_TLS_REDIRECT_ADDR"),
+			TLSClientCAFile:     viper.GetString("SERVER_TLS_CLIENT_CA_FILE"),
+			TrustedProxies:      getList("SERVER_TRUSTED_PROXIES"),
+		},
+		Service: ServiceAuthConfig{
+			APIKeys:     getList("SERVICE_API_KEYS"),
+			ClientCerts: getList("SERVICE_CLIENT_CERTS"),
+		},
+		HTTP: HTTPSecurityConfig{
+			CORSAllowedOrigins: getList("CORS_ALLOWED_ORIGINS"),
+			CORSAllowedMethods: getList("CORS_ALLOWED_METHODS"),
+			CORSMaxAge:         corsMaxAge,
+			HSTSMaxAge:         hstsMaxAge,
+			FrameAncestors:     getList("FRAME_ANCESTORS"),
+			PostV1Sunset:       postV1Sunset,
 		},
 		Postgres: PostgresConfig{
 			Host:     viper.GetString("POSTGRES_HOST"),
@@ -121,16 +495,30 @@ func Load() (*Config, error) {
 			Password: viper.GetString("POSTGRES_PASSWORD"),
 			Database: viper.GetString("POSTGRES_DB"),
 			SSLMode:  viper.GetString("POSTGRES_SSL_MODE"),
+
+			ReplicaDSNs:   getList("POSTGRES_REPLICA_DSNS"),
+			ReplicaMaxLag: replicaMaxLag,
+
+			MaxOpenConns:    viper.GetInt("POSTGRES_MAX_OPEN_CONNS"),
+			MaxIdleConns:    viper.GetInt("POSTGRES_MAX_IDLE_CONNS"),
+			ConnMaxLifetime: postgresConnMaxLifetime,
+			ConnMaxIdleTime: postgresConnMaxIdleTime,
 		},
 		MongoDB: MongoDBConfig{
 			URI:      viper.GetString("MONGODB_URI"),
 			Database: viper.GetString("MONGODB_DB"),
+
+			MaxPoolSize: viper.GetInt("MONGODB_MAX_POOL_SIZE"),
+			MinPoolSize: viper.GetInt("MONGODB_MIN_POOL_SIZE"),
 		},
 		Redis: RedisConfig{
 			Host:     viper.GetString("REDIS_HOST"),
 			Port:     viper.GetInt("REDIS_PORT"),
 			Password: viper.GetString("REDIS_PASSWORD"),
 			DB:       viper.GetInt("REDIS_DB"),
+
+			PoolSize:     viper.GetInt("REDIS_POOL_SIZE"),
+			MinIdleConns: viper.GetInt("REDIS_MIN_IDLE_CONNS"),
 		},
 		JWT: JWTConfig{
 			Secret:        viper.GetString("JWT_SECRET"),
@@ -138,20 +526,147 @@ func Load() (*Config, error) {
 			RefreshExpiry: refreshExpiry,
 		},
 		Encryption: EncryptionConfig{
-			Key: viper.GetString("ENCRYPTION_KEY"),
+			Key:            viper.GetString("ENCRYPTION_KEY"),
+			KeyID:          viper.GetString("ENCRYPTION_KEY_ID"),
+			PreviousKeyIDs: getList("ENCRYPTION_PREVIOUS_KEY_IDS"),
+			IndexKeyID:     viper.GetString("ENCRYPTION_INDEX_KEY_ID"),
+
+			SensitiveCategories: getList("ENCRYPTION_SENSITIVE_CATEGORIES"),
 		},
 		RateLimit: RateLimitConfig{
-			PostsPerHour:     viper.GetInt("RATE_LIMIT_POSTS_PER_HOUR"),
-			ResponsesPerHour: viper.GetInt("RATE_LIMIT_RESPONSES_PER_HOUR"),
+			PostsPerHour:          viper.GetInt("RATE_LIMIT_POSTS_PER_HOUR"),
+			ResponsesPerHour:      viper.GetInt("RATE_LIMIT_RESPONSES_PER_HOUR"),
+			RequestsPerMinute:     viper.GetInt("RATE_LIMIT_REQUESTS_PER_MINUTE"),
+			AuthRequestsPerMinute: viper.GetInt("RATE_LIMIT_AUTH_PER_MINUTE"),
 		},
 		WebSocket: WebSocketConfig{
-			ReadBufferSize:  viper.GetInt("WS_READ_BUFFER_SIZE"),
-			WriteBufferSize: viper.GetInt("WS_WRITE_BUFFER_SIZE"),
-			MaxMessageSize:  viper.GetInt("WS_MAX_MESSAGE_SIZE"),
+			ReadBufferSize:   viper.GetInt("WS_READ_BUFFER_SIZE"),
+			WriteBufferSize:  viper.GetInt("WS_WRITE_BUFFER_SIZE"),
+			MaxMessageSize:   viper.GetInt("WS_MAX_MESSAGE_SIZE"),
+			AllowedOrigins:   getList("WS_ALLOWED_ORIGINS"),
+			AuthTimeout:      wsAuthTimeout,
+			DrainWindow:      wsDrainWindow,
+			SendBufferSize:   viper.GetInt("WS_SEND_BUFFER_SIZE"),
+			SlowClientPolicy: viper.GetString("WS_SLOW_CLIENT_POLICY"),
+			RealtimeSource:   viper.GetString("WS_REALTIME_SOURCE"),
 		},
 		Moderation: ModerationConfig{
 			EnableAutoModeration: viper.GetBool("ENABLE_AUTO_MODERATION"),
 			ProfanityFilterLevel: viper.GetString("PROFANITY_FILTER_LEVEL"),
+			Languages:            getList("MODERATION_LANGUAGES"),
+			BanEvasionWindow:     banEvasionWindow,
+			AutoModeration: AutoModerationConfig{
+				ToxicityThreshold:      viper.GetFloat64("AUTO_MOD_TOXICITY_THRESHOLD"),
+				ReportThreshold:        viper.GetInt("AUTO_MOD_REPORT_THRESHOLD"),
+				AbuseSeverityThreshold: viper.GetString("AUTO_MOD_ABUSE_SEVERITY"),
+				Actions:                getList("AUTO_MOD_ACTIONS"),
+			},
+			SLA: ModerationSLAConfig{
+				Critical: slaCritical,
+				High:     slaHigh,
+				Medium:   slaMedium,
+				Low:      slaLow,
+			},
+		},
+		Push: PushConfig{
+			FCMProjectID:         viper.GetString("FCM_PROJECT_ID"),
+			FCMCredentialsSecret: viper.GetString("FCM_CREDENTIALS_SECRET"),
+			APNSKeyID:            viper.GetString("APNS_KEY_ID"),
+			APNSTeamID:           viper.GetString("APNS_TEAM_ID"),
+			APNSBundleID:         viper.GetString("APNS_BUNDLE_ID"),
+			APNSKeySecret:        viper.GetString("APNS_KEY_SECRET"),
+			APNSProduction:       viper.GetBool("APNS_PRODUCTION"),
+			DispatchWorkers:      viper.GetInt("NOTIFICATION_DISPATCH_WORKERS"),
+			DispatchMaxAttempts:  viper.GetInt("NOTIFICATION_DISPATCH_MAX_ATTEMPTS"),
+			DispatchRetryDelay:   dispatchRetryDelay,
+			DispatchMaxDelay:     dispatchMaxDelay,
+			CollapseWindow:       collapseWindow,
+		},
+		Email: EmailConfig{
+			SMTPHost:           viper.GetString("SMTP_HOST"),
+			SMTPPort:           viper.GetInt("SMTP_PORT"),
+			SMTPUsername:       viper.GetString("SMTP_USERNAME"),
+			SMTPPasswordSecret: viper.GetString("SMTP_PASSWORD_SECRET"),
+			From:               viper.GetString("EMAIL_FROM"),
+		},
+		SOS: SOSConfig{
+			MaxRecipients:       viper.GetInt("SOS_MAX_RECIPIENTS"),
+			CandidatePool:       viper.GetInt("SOS_CANDIDATE_POOL"),
+			CrisisResourcesFile: viper.GetString("CRISIS_RESOURCES_FILE"),
+		},
+		Progress: ProgressConfig{
+			AchievementsFile:      viper.GetString("ACHIEVEMENTS_FILE"),
+			StreakFreezesPerMonth: viper.GetInt("STREAK_FREEZES_PER_MONTH"),
+			CheckInQuestionsFile:  viper.GetString("CHECKIN_QUESTIONS_FILE"),
+		},
+		Billing: BillingConfig{
+			EnforceEntitlements: viper.GetBool("ENFORCE_ENTITLEMENTS"),
+			StripeWebhookSecret: viper.GetString("STRIPE_WEBHOOK_SECRET"),
+			StripePricePlans:    getList("STRIPE_PRICE_PLANS"),
+		},
+		GraphQL: GraphQLConfig{
+			Enabled:       viper.GetBool("GRAPHQL_ENABLED"),
+			MaxComplexity: viper.GetInt("GRAPHQL_MAX_COMPLEXITY"),
+		},
+		Events: EventsConfig{
+			Driver:        viper.GetString("EVENT_BUS_DRIVER"),
+			MaxDeliveries: viper.GetInt("EVENT_BUS_MAX_DELIVERIES"),
+			NATSURL:       viper.GetString("NATS_URL"),
+			KafkaBrokers:  getList("KAFKA_BROKERS"),
+		},
+		WorkQueue: WorkQueueConfig{
+			Workers: viper.GetInt("WORK_QUEUE_WORKERS"),
+		},
+		Audit: AuditConfig{
+			BufferSize: viper.GetInt("AUDIT_BUFFER_SIZE"),
+		},
+		Retention: RetentionConfig{
+			Rules:    getList("RETENTION_RULES"),
+			Enforce:  viper.GetBool("RETENTION_ENFORCE"),
+			Interval: retentionInterval,
+		},
+		Export: ExportConfig{
+			Dir:             viper.GetString("EXPORT_DIR"),
+			S3Bucket:        viper.GetString("EXPORT_S3_BUCKET"),
+			S3Region:        viper.GetString("EXPORT_S3_REGION"),
+			S3Endpoint:      viper.GetString("EXPORT_S3_ENDPOINT"),
+			S3Prefix:        viper.GetString("EXPORT_S3_PREFIX"),
+			ExcludedColumns: getList("EXPORT_EXCLUDED_COLUMNS"),
+			DailyDatasets:   getList("EXPORT_DAILY_DATASETS"),
+		},
+		Runtime: RuntimeSettingsConfig{
+			RefreshInterval: runtimeSettingsRefresh,
+		},
+		LoginRisk: LoginRiskConfig{
+			GeoCountryHeader:   viper.GetString("GEO_COUNTRY_HEADER"),
+			GeoLatitudeHeader:  viper.GetString("GEO_LATITUDE_HEADER"),
+			GeoLongitudeHeader: viper.GetString("GEO_LONGITUDE_HEADER"),
+			MaxTravelSpeedKMH:  viper.GetFloat64("LOGIN_MAX_TRAVEL_SPEED_KMH"),
+			IPFailureThreshold: viper.GetInt("LOGIN_IP_FAILURE_THRESHOLD"),
+			IPFailureWindow:    ipFailureWindow,
+		},
+		Errors: ErrorReportingConfig{
+			SentryDSN: viper.GetString("SENTRY_DSN"),
+			Release:   viper.GetString("SENTRY_RELEASE"),
+		},
+		Secrets: SecretsConfig{
+			VaultAddr:      viper.GetString("VAULT_ADDR"),
+			VaultNamespace: viper.GetString("VAULT_NAMESPACE"),
+			VaultToken:     viper.GetString("VAULT_TOKEN"),
+			VaultRoleID:    viper.GetString("VAULT_ROLE_ID"),
+			VaultSecretID:  viper.GetString("VAULT_SECRET_ID"),
+			VaultMount:     viper.GetString("VAULT_KV_MOUNT"),
+			VaultPath:      viper.GetString("VAULT_SECRET_PATH"),
+			AWSRegion:      viper.GetString("AWS_SECRETS_REGION"),
+			AWSPrefix:      viper.GetString("AWS_SECRETS_PREFIX"),
+			GCPProject:     viper.GetString("GCP_SECRETS_PROJECT"),
+			GCPPrefix:      viper.GetString("GCP_SECRETS_PREFIX"),
+			CacheTTL:       secretsCacheTTL,
+		},
+		SLO: SLOConfig{
+			AvailabilityTarget: viper.GetFloat64("SLO_AVAILABILITY_TARGET"),
+			LatencyTarget:      sloLatencyTarget,
+			Window:             sloWindow,
 		},
 		Timeouts: TimeoutConfig{
 			DB:      dbTimeout,
@@ -161,6 +676,10 @@ func Load() (*Config, error) {
 	}
 
 	// Validate and apply defaults
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
@@ -168,6 +687,82 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// resolveSecrets reads JWT_SECRET, ENCRYPTION_KEY and STRIPE_WEBHOOK_SECRET
+// through the secret manager chain when a backend is configured, keeping the
+// configured values for secrets no backend holds
+func (c *Config) resolveSecrets() error {
+	if !c.Secrets.Sources().Enabled() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	manager, err := secrets.NewChain(ctx, c.Secrets.Sources(), zap.NewNop())
+	if err != nil {
+		return fmt.Errorf("failed to connect to secret manager: %w", err)
+	}
+	defer func() { _ = manager.Close() }()
+
+	c.JWT.Secret = manager.GetSecretWithDefault(ctx, "JWT_SECRET", c.JWT.Secret)
+	c.Encryption.Key = manager.GetSecretWithDefault(ctx, "ENCRYPTION_KEY", c.Encryption.Key)
+	c.Billing.StripeWebhookSecret = manager.GetSecretWithDefault(ctx, "STRIPE_WEBHOOK_SECRET", c.Billing.StripeWebhookSecret)
+	return nil
+}
+
+// readConfigFiles loads DefaultEnvFile if it exists, then merges configFile
+// over it
+func readConfigFiles(configFile string) error {
+	if _, err := os.Stat(DefaultEnvFile); err == nil {
+		viper.SetConfigFile(DefaultEnvFile)
+		if err := viper.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read %s: %w", DefaultEnvFile, err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", DefaultEnvFile, err)
+	}
+
+	if configFile == "" {
+		return nil
+	}
+	switch strings.ToLower(filepath.Ext(configFile)) {
+	case ".yaml", ".yml", ".toml", ".json", ".env":
+	default:
+		return fmt.Errorf("config file %s must be YAML, TOML, JSON or .env", configFile)
+	}
+	viper.SetConfigFile(configFile)
+	if err := viper.MergeInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", configFile, err)
+	}
+	return nil
+}
+
+// getList reads a list setting, given as a comma-separated string or, in
+// YAML, TOML and JSON config files, as an array
+func getList(key string) []string {
+	if values, ok := viper.Get(key).([]interface{}); ok {
+		items := []string{}
+		for _, value := range values {
+			if item := strings.TrimSpace(fmt.Sprint(value)); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return splitList(viper.GetString(key))
+}
+
+// splitList parses a comma-separated config value, dropping empty entries
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func (p PostgresConfig) DSN() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		p.Host, p.Port, p.User, p.Password, p.Database, p.SSLMode)
@@ -188,6 +783,42 @@ func (c *Config) Validate() error {
 	if c.Server.Env != "development" && c.Server.Env != "staging" && c.Server.Env != "production" {
 		return fmt.Errorf("SERVER_ENV must be one of: development, staging, production")
 	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		return fmt.Errorf("SERVER_TLS_CERT_FILE and SERVER_TLS_KEY_FILE must be set together")
+	}
+	if c.Server.TLSCertFile != "" && len(c.Server.TLSAutocertDomains) > 0 {
+		return fmt.Errorf("SERVER_TLS_CERT_FILE and SERVER_TLS_AUTOCERT_DOMAINS are mutually exclusive")
+	}
+	if len(c.Server.TLSAutocertDomains) > 0 && c.Server.TLSAutocertCacheDir == "" {
+		c.Server.TLSAutocertCacheDir = "autocert-cache"
+	}
+	if c.Server.TLSRedirectAddr != "" && c.Server.TLSCertFile == "" && len(c.Server.TLSAutocertDomains) == 0 {
+		return fmt.Errorf("SERVER_TLS_REDIRECT_ADDR requires TLS to be configured")
+	}
+	if c.Server.TLSClientCAFile != "" && c.Server.TLSCertFile == "" && len(c.Server.TLSAutocertDomains) == 0 {
+		return fmt.Errorf("SERVER_TLS_CLIENT_CA_FILE requires TLS to be configured")
+	}
+	if len(c.Service.ClientCerts) > 0 && c.Server.TLSClientCAFile == "" {
+		return fmt.Errorf("SERVICE_CLIENT_CERTS requires SERVER_TLS_CLIENT_CA_FILE")
+	}
+
+	// CORS and security header defaults. Development accepts any origin and
+	// skips HSTS; elsewhere only the listed origins may call the API.
+	if len(c.HTTP.CORSAllowedOrigins) == 0 && c.Server.Env == "development" {
+		c.HTTP.CORSAllowedOrigins = []string{"*"}
+	}
+	if c.Server.Env == "production" && slices.Contains(c.HTTP.CORSAllowedOrigins, "*") {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS must list explicit origins in production")
+	}
+	if len(c.HTTP.CORSAllowedMethods) == 0 {
+		c.HTTP.CORSAllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	}
+	if c.HTTP.CORSMaxAge == 0 {
+		c.HTTP.CORSMaxAge = 10 * time.Minute
+	}
+	if c.HTTP.HSTSMaxAge == 0 && c.Server.Env != "development" {
+		c.HTTP.HSTSMaxAge = 365 * 24 * time.Hour
+	}
 
 	// Postgres validation
 	if c.Postgres.Host == "" {
@@ -208,6 +839,9 @@ func (c *Config) Validate() error {
 	if c.Postgres.SSLMode == "" {
 		c.Postgres.SSLMode = "disable"
 	}
+	if c.Postgres.ReplicaMaxLag == 0 {
+		c.Postgres.ReplicaMaxLag = 5 * time.Second
+	}
 
 	// MongoDB validation
 	if c.MongoDB.URI == "" {
@@ -225,6 +859,11 @@ func (c *Config) Validate() error {
 		c.Redis.Port = 6379
 	}
 
+	// Connection pool validation
+	if err := c.validatePools(); err != nil {
+		return err
+	}
+
 	// JWT validation (critical security settings)
 	if c.JWT.Secret == "" {
 		return fmt.Errorf("JWT_SECRET is required and must not be empty")
@@ -249,6 +888,15 @@ func (c *Config) Validate() error {
 	if len(c.Encryption.Key) != 32 {
 		return fmt.Errorf("ENCRYPTION_KEY must be exactly 32 bytes for AES-256")
 	}
+	if c.Encryption.KeyID == "" {
+		c.Encryption.KeyID = "1"
+	}
+	if c.Encryption.IndexKeyID == "" {
+		c.Encryption.IndexKeyID = "1"
+	}
+	if c.Encryption.IndexKeyID != c.Encryption.KeyID && !slices.Contains(c.Encryption.PreviousKeyIDs, c.Encryption.IndexKeyID) {
+		return fmt.Errorf("ENCRYPTION_INDEX_KEY_ID must be ENCRYPTION_KEY_ID or one of ENCRYPTION_PREVIOUS_KEY_IDS")
+	}
 
 	// Rate limit defaults
 	if c.RateLimit.PostsPerHour == 0 {
@@ -257,6 +905,12 @@ func (c *Config) Validate() error {
 	if c.RateLimit.ResponsesPerHour == 0 {
 		c.RateLimit.ResponsesPerHour = 50
 	}
+	if c.RateLimit.RequestsPerMinute == 0 {
+		c.RateLimit.RequestsPerMinute = 300
+	}
+	if c.RateLimit.AuthRequestsPerMinute == 0 {
+		c.RateLimit.AuthRequestsPerMinute = 20
+	}
 
 	// WebSocket defaults
 	if c.WebSocket.ReadBufferSize == 0 {
@@ -268,11 +922,206 @@ func (c *Config) Validate() error {
 	if c.WebSocket.MaxMessageSize == 0 {
 		c.WebSocket.MaxMessageSize = 8192
 	}
+	if c.WebSocket.AuthTimeout == 0 {
+		c.WebSocket.AuthTimeout = 10 * time.Second
+	}
+	if c.WebSocket.DrainWindow == 0 {
+		c.WebSocket.DrainWindow = 20 * time.Second
+	}
+	if c.WebSocket.SendBufferSize == 0 {
+		c.WebSocket.SendBufferSize = 256
+	}
+	if c.WebSocket.SlowClientPolicy == "" {
+		c.WebSocket.SlowClientPolicy = "drop_oldest"
+	}
+	if c.WebSocket.SlowClientPolicy != "drop_oldest" && c.WebSocket.SlowClientPolicy != "disconnect" {
+		return fmt.Errorf("WS_SLOW_CLIENT_POLICY must be one of: drop_oldest, disconnect")
+	}
+	if c.WebSocket.RealtimeSource == "" {
+		c.WebSocket.RealtimeSource = "events"
+	}
+	if c.WebSocket.RealtimeSource != "events" && c.WebSocket.RealtimeSource != "change_streams" {
+		return fmt.Errorf("WS_REALTIME_SOURCE must be one of: events, change_streams")
+	}
+
+	// Event bus defaults
+	if c.Events.Driver == "" {
+		c.Events.Driver = "memory"
+	}
+	if c.Events.MaxDeliveries == 0 {
+		c.Events.MaxDeliveries = 5
+	}
+	switch c.Events.Driver {
+	case "memory", "redis":
+	case "nats":
+		if c.Events.NATSURL == "" {
+			return fmt.Errorf("NATS_URL is required when EVENT_BUS_DRIVER is nats")
+		}
+	case "kafka":
+		if len(c.Events.KafkaBrokers) == 0 {
+			return fmt.Errorf("KAFKA_BROKERS is required when EVENT_BUS_DRIVER is kafka")
+		}
+	default:
+		return fmt.Errorf("EVENT_BUS_DRIVER must be one of: memory, redis, nats, kafka")
+	}
+
+	// Work queue defaults
+	if c.WorkQueue.Workers == 0 {
+		c.WorkQueue.Workers = 4
+	}
+
+	// Audit defaults
+	if c.Audit.BufferSize == 0 {
+		c.Audit.BufferSize = 1000
+	}
+
+	// Retention defaults
+	if c.Retention.Interval == 0 {
+		c.Retention.Interval = 24 * time.Hour
+	}
+
+	// Export defaults
+	if c.Export.Dir == "" {
+		c.Export.Dir = "exports"
+	}
+	if c.Export.S3Bucket != "" && c.Export.S3Region == "" {
+		return fmt.Errorf("EXPORT_S3_REGION is required when EXPORT_S3_BUCKET is set")
+	}
+
+	// Runtime settings defaults
+	if c.Runtime.RefreshInterval == 0 {
+		c.Runtime.RefreshInterval = time.Minute
+	}
+
+	// SLO defaults
+	if c.SLO.AvailabilityTarget == 0 {
+		c.SLO.AvailabilityTarget = 0.999
+	}
+	if c.SLO.AvailabilityTarget <= 0 || c.SLO.AvailabilityTarget >= 1 {
+		return fmt.Errorf("SLO_AVAILABILITY_TARGET must be between 0 and 1")
+	}
+	if c.SLO.LatencyTarget == 0 {
+		c.SLO.LatencyTarget = 500 * time.Millisecond
+	}
+	if c.SLO.Window == 0 {
+		c.SLO.Window = time.Hour
+	}
+
+	// Progress defaults
+	if c.Progress.StreakFreezesPerMonth == 0 {
+		c.Progress.StreakFreezesPerMonth = 1
+	}
+	if c.Progress.StreakFreezesPerMonth < 0 {
+		c.Progress.StreakFreezesPerMonth = 0
+	}
+
+	// Push defaults
+	if c.Push.FCMCredentialsSecret == "" {
+		c.Push.FCMCredentialsSecret = "FCM_CREDENTIALS_JSON"
+	}
+	if c.Push.APNSKeySecret == "" {
+		c.Push.APNSKeySecret = "APNS_AUTH_KEY"
+	}
+	if c.Push.DispatchWorkers == 0 {
+		c.Push.DispatchWorkers = 8
+	}
+	if c.Push.DispatchMaxAttempts == 0 {
+		c.Push.DispatchMaxAttempts = 5
+	}
+	if c.Push.DispatchRetryDelay == 0 {
+		c.Push.DispatchRetryDelay = time.Second
+	}
+	if c.Push.DispatchMaxDelay == 0 {
+		c.Push.DispatchMaxDelay = 5 * time.Minute
+	}
+	if c.Push.CollapseWindow == 0 {
+		c.Push.CollapseWindow = 30 * time.Second
+	}
+
+	// Email defaults
+	if c.Email.SMTPPort == 0 {
+		c.Email.SMTPPort = 587
+	}
+	if c.Email.SMTPPasswordSecret == "" {
+		c.Email.SMTPPasswordSecret = "SMTP_PASSWORD"
+	}
+
+	// SOS defaults
+	if c.SOS.MaxRecipients == 0 {
+		c.SOS.MaxRecipients = 20
+	}
+	if c.SOS.CandidatePool == 0 {
+		c.SOS.CandidatePool = 200
+	}
 
 	// Moderation defaults
 	if c.Moderation.ProfanityFilterLevel == "" {
 		c.Moderation.ProfanityFilterLevel = "medium"
 	}
+	if c.Moderation.BanEvasionWindow == 0 {
+		c.Moderation.BanEvasionWindow = 30 * 24 * time.Hour
+	}
+	if len(c.Moderation.Languages) == 0 {
+		c.Moderation.Languages = []string{"en"}
+	}
+	for _, lang := range c.Moderation.Languages {
+		switch lang {
+		case "en", "es", "fr", "de", "pt":
+		default:
+			return fmt.Errorf("MODERATION_LANGUAGES contains unsupported language %q", lang)
+		}
+	}
+	if c.Moderation.AutoModeration.ToxicityThreshold == 0 {
+		c.Moderation.AutoModeration.ToxicityThreshold = 0.8
+	}
+	if c.Moderation.AutoModeration.ToxicityThreshold < 0 || c.Moderation.AutoModeration.ToxicityThreshold > 1 {
+		return fmt.Errorf("AUTO_MOD_TOXICITY_THRESHOLD must be between 0 and 1")
+	}
+	if c.Moderation.AutoModeration.ReportThreshold == 0 {
+		c.Moderation.AutoModeration.ReportThreshold = 3
+	}
+	if c.Moderation.AutoModeration.AbuseSeverityThreshold == "" {
+		c.Moderation.AutoModeration.AbuseSeverityThreshold = "high"
+	}
+	switch c.Moderation.AutoModeration.AbuseSeverityThreshold {
+	case "low", "medium", "high", "critical":
+	default:
+		return fmt.Errorf("AUTO_MOD_ABUSE_SEVERITY must be one of: low, medium, high, critical")
+	}
+	if c.Moderation.SLA.Critical == 0 {
+		c.Moderation.SLA.Critical = time.Hour
+	}
+	if c.Moderation.SLA.High == 0 {
+		c.Moderation.SLA.High = 4 * time.Hour
+	}
+	if c.Moderation.SLA.Medium == 0 {
+		c.Moderation.SLA.Medium = 24 * time.Hour
+	}
+	if c.Moderation.SLA.Low == 0 {
+		c.Moderation.SLA.Low = 72 * time.Hour
+	}
+	if len(c.Moderation.AutoModeration.Actions) == 0 {
+		c.Moderation.AutoModeration.Actions = []string{"hide", "notify_author", "open_case"}
+	}
+	for _, action := range c.Moderation.AutoModeration.Actions {
+		if action != "hide" && action != "notify_author" && action != "open_case" {
+			return fmt.Errorf("AUTO_MOD_ACTIONS contains unknown action %q", action)
+		}
+	}
+
+	// Login risk defaults
+	if c.LoginRisk.MaxTravelSpeedKMH == 0 {
+		c.LoginRisk.MaxTravelSpeedKMH = 1000 // Faster than a commercial flight
+	}
+	if c.LoginRisk.IPFailureThreshold == 0 {
+		c.LoginRisk.IPFailureThreshold = 20
+	}
+	if c.LoginRisk.IPFailureWindow == 0 {
+		c.LoginRisk.IPFailureWindow = time.Hour
+	}
+	if c.LoginRisk.MaxTravelSpeedKMH < 0 || c.LoginRisk.IPFailureThreshold < 0 {
+		return fmt.Errorf("LOGIN_MAX_TRAVEL_SPEED_KMH and LOGIN_IP_FAILURE_THRESHOLD must not be negative")
+	}
 
 	// Server timeout defaults
 	if c.Server.ReadTimeout == 0 {
@@ -284,6 +1133,12 @@ func (c *Config) Validate() error {
 	if c.Server.IdleTimeout == 0 {
 		c.Server.IdleTimeout = 60 * time.Second
 	}
+	if c.Server.ShutdownDelay == 0 {
+		c.Server.ShutdownDelay = 5 * time.Second
+	}
+	if c.Server.StartupMaxWait <= 0 {
+		c.Server.StartupMaxWait = 60 * time.Second
+	}
 
 	// Timeout defaults
 	if c.Timeouts.DB == 0 {
@@ -298,3 +1153,56 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+// validatePools fills unset pool sizes with the environment's defaults and
+// rejects sizes that cannot work
+func (c *Config) validatePools() error {
+	defaults := defaultPools[c.Server.Env]
+
+	if c.Postgres.MaxOpenConns == 0 {
+		c.Postgres.MaxOpenConns = defaults.postgresOpen
+	}
+	if c.Postgres.MaxIdleConns == 0 {
+		c.Postgres.MaxIdleConns = min(defaults.postgresIdle, c.Postgres.MaxOpenConns)
+	}
+	if c.Postgres.ConnMaxLifetime == 0 {
+		c.Postgres.ConnMaxLifetime = 5 * time.Minute
+	}
+	if c.Postgres.ConnMaxIdleTime == 0 {
+		c.Postgres.ConnMaxIdleTime = time.Minute
+	}
+	if c.Postgres.MaxOpenConns < 1 || c.Postgres.MaxIdleConns < 0 {
+		return fmt.Errorf("POSTGRES_MAX_OPEN_CONNS must be positive and POSTGRES_MAX_IDLE_CONNS non-negative")
+	}
+	if c.Postgres.MaxIdleConns > c.Postgres.MaxOpenConns {
+		return fmt.Errorf("POSTGRES_MAX_IDLE_CONNS cannot exceed POSTGRES_MAX_OPEN_CONNS")
+	}
+
+	if c.MongoDB.MaxPoolSize == 0 {
+		c.MongoDB.MaxPoolSize = defaults.mongoMax
+	}
+	if c.MongoDB.MinPoolSize == 0 {
+		c.MongoDB.MinPoolSize = min(defaults.mongoMin, c.MongoDB.MaxPoolSize)
+	}
+	if c.MongoDB.MaxPoolSize < 1 || c.MongoDB.MinPoolSize < 0 {
+		return fmt.Errorf("MONGODB_MAX_POOL_SIZE must be positive and MONGODB_MIN_POOL_SIZE non-negative")
+	}
+	if c.MongoDB.MinPoolSize > c.MongoDB.MaxPoolSize {
+		return fmt.Errorf("MONGODB_MIN_POOL_SIZE cannot exceed MONGODB_MAX_POOL_SIZE")
+	}
+
+	if c.Redis.PoolSize == 0 {
+		c.Redis.PoolSize = defaults.redisSize
+	}
+	if c.Redis.MinIdleConns == 0 {
+		c.Redis.MinIdleConns = min(defaults.redisIdle, c.Redis.PoolSize)
+	}
+	if c.Redis.PoolSize < 1 || c.Redis.MinIdleConns < 0 {
+		return fmt.Errorf("REDIS_POOL_SIZE must be positive and REDIS_MIN_IDLE_CONNS non-negative")
+	}
+	if c.Redis.MinIdleConns > c.Redis.PoolSize {
+		return fmt.Errorf("REDIS_MIN_IDLE_CONNS cannot exceed REDIS_POOL_SIZE")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestReadConfigFiles_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Cleanup(viper.Reset)
+	viper.Reset()
+
+	writeFile(t, filepath.Join(dir, DefaultEnvFile), "SERVER_PORT=8080\nSERVER_ENV=development\nREDIS_HOST=localhost\n")
+	configFile := filepath.Join(dir, "config.yaml")
+	writeFile(t, configFile, "SERVER_ENV: staging\nredis_host: redis.internal\nCORS_ALLOWED_ORIGINS:\n  - https://a.example\n  - https://b.example\n")
+	t.Setenv("REDIS_HOST", "redis.env")
+
+	if err := readConfigFiles(configFile); err != nil {
+		t.Fatalf("readConfigFiles: %v", err)
+	}
+	viper.AutomaticEnv()
+
+	if got := viper.GetInt("SERVER_PORT"); got != 8080 {
+		t.Errorf("SERVER_PORT = %d, want the .env value", got)
+	}
+	if got := viper.GetString("SERVER_ENV"); got != "staging" {
+		t.Errorf("SERVER_ENV = %q, want the config file to override .env", got)
+	}
+	if got := viper.GetString("REDIS_HOST"); got != "redis.env" {
+		t.Errorf("REDIS_HOST = %q, want the environment to override the config file", got)
+	}
+	if got := getList("CORS_ALLOWED_ORIGINS"); !slices.Equal(got, []string{"https://a.example", "https://b.example"}) {
+		t.Errorf("CORS_ALLOWED_ORIGINS = %v, want the YAML array", got)
+	}
+}
+
+func TestReadConfigFiles_TOML(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Cleanup(viper.Reset)
+	viper.Reset()
+
+	configFile := filepath.Join(dir, "config.toml")
+	writeFile(t, configFile, "SERVER_PORT = 9090\nMODERATION_LANGUAGES = \"en, es\"\n")
+
+	if err := readConfigFiles(configFile); err != nil {
+		t.Fatalf("readConfigFiles: %v", err)
+	}
+	if got := viper.GetInt("SERVER_PORT"); got != 9090 {
+		t.Errorf("SERVER_PORT = %d", got)
+	}
+	if got := getList("MODERATION_LANGUAGES"); !slices.Equal(got, []string{"en", "es"}) {
+		t.Errorf("MODERATION_LANGUAGES = %v, want the comma-separated string split", got)
+	}
+}
+
+func TestReadConfigFiles_Optional(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Cleanup(viper.Reset)
+	viper.Reset()
+
+	if err := readConfigFiles(""); err != nil {
+		t.Errorf("readConfigFiles without any files = %v, want nil", err)
+	}
+	if err := readConfigFiles("missing.yaml"); err == nil {
+		t.Error("readConfigFiles with a missing config file succeeded")
+	}
+	if err := readConfigFiles("config.ini"); err == nil {
+		t.Error("readConfigFiles with an unsupported format succeeded")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
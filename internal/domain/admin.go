@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserSearch filters an admin user search. Query matches a user ID exactly
+// or part of a username; emails are encrypted, so they can't be searched.
+type UserSearch struct {
+	Query  string
+	Role   *Role
+	Banned *bool
+	Limit  int
+	Offset int
+}
+
+// UserCounts breaks down the platform's accounts
+type UserCounts struct {
+	Total      int `db:"total" json:"total"` // Not deleted, including banned
+	Banned     int `db:"banned" json:"banned"`
+	Moderators int `db:"moderators" json:"moderators"`
+	Admins     int `db:"admins" json:"admins"`
+}
+
+// SystemStats is a current snapshot of the platform for admins
+type SystemStats struct {
+	GeneratedAt time.Time  `json:"generated_at"`
+	Users       UserCounts `json:"users"`
+	Circles     int        `json:"circles"`
+	OpenReports int        `json:"open_reports"`
+}
+
+// FeatureFlag turns a feature on or off platform-wide without a deploy
+type FeatureFlag struct {
+	Key       string     `db:"key" json:"key"`
+	Enabled   bool       `db:"enabled" json:"enabled"`
+	UpdatedBy *uuid.UUID `db:"updated_by" json:"updated_by,omitempty"` // Nil when set by a service client
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// RuntimeSetting is an admin override of a tunable, applied without a restart
+type RuntimeSetting struct {
+	Key       string     `db:"key" json:"key"`
+	Value     string     `db:"value" json:"value"`
+	UpdatedBy *uuid.UUID `db:"updated_by" json:"updated_by,omitempty"` // Nil when set by a service client
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
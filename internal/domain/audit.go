@@ -17,6 +17,7 @@ const (
 	AuditEventLoginFailed     AuditEventType = "auth.login_failed"
 	AuditEventTokenRevoked    AuditEventType = "auth.token_revoked"
 	AuditEventPasswordChanged AuditEventType = "auth.password_changed"
+	AuditEventSuspiciousLogin AuditEventType = "auth.suspicious_login"
 
 	AuditEventUserCreated  AuditEventType = "user.created"
 	AuditEventUserUpdated  AuditEventType = "user.updated"
@@ -34,14 +35,24 @@ const (
 	AuditEventContentRemoved AuditEventType = "moderation.content_removed"
 	AuditEventUserWarned     AuditEventType = "moderation.user_warned"
 
-	AuditEventCircleCreated AuditEventType = "circle.created"
-	AuditEventCircleJoined  AuditEventType = "circle.joined"
-	AuditEventCircleLeft    AuditEventType = "circle.left"
-	AuditEventCircleDeleted AuditEventType = "circle.deleted"
+	AuditEventCircleCreated       AuditEventType = "circle.created"
+	AuditEventCircleJoined        AuditEventType = "circle.joined"
+	AuditEventCircleLeft          AuditEventType = "circle.left"
+	AuditEventCircleDeleted       AuditEventType = "circle.deleted"
+	AuditEventCircleOwnerChanged  AuditEventType = "circle.owner_changed"
+	AuditEventCircleRoleChanged   AuditEventType = "circle.role_changed"
+	AuditEventCircleMemberRemoved AuditEventType = "circle.member_removed"
+
+	AuditEventDataExported AuditEventType = "user.data_exported"
 
 	AuditEventPermissionGranted AuditEventType = "admin.permission_granted"
 	AuditEventPermissionRevoked AuditEventType = "admin.permission_revoked"
 	AuditEventRoleChanged       AuditEventType = "admin.role_changed"
+	AuditEventFeatureFlagSet    AuditEventType = "admin.feature_flag_set"
+	AuditEventDataExportStarted AuditEventType = "admin.data_export_started"
+	AuditEventRuntimeSettingSet AuditEventType = "admin.runtime_setting_set"
+
+	AuditEventSubscriptionChanged AuditEventType = "billing.subscription_changed"
 )
 
 // AuditLog represents an audit log entry
@@ -7,23 +7,34 @@ import (
 )
 
 type Circle struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	Category    string    `db:"category" json:"category"`
-	MaxMembers  int       `db:"max_members" json:"max_members"`
-	MemberCount int       `db:"member_count" json:"member_count"`
-	IsPrivate   bool      `db:"is_private" json:"is_private"`
-	CreatedBy   uuid.UUID `db:"created_by" json:"created_by"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ID          uuid.UUID  `db:"id" json:"id"`
+	Name        string     `db:"name" json:"name"`
+	Description string     `db:"description" json:"description"`
+	Category    string     `db:"category" json:"category"`
+	MaxMembers  int        `db:"max_members" json:"max_members"`
+	MemberCount int        `db:"member_count" json:"member_count"`
+	IsPrivate   bool       `db:"is_private" json:"is_private"`
+	CreatedBy   uuid.UUID  `db:"created_by" json:"created_by"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	DeletedAt   *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 }
 
+// CircleRole is a member's role within one circle
+type CircleRole string
+
+const (
+	CircleRoleMember    CircleRole = "member"
+	CircleRoleModerator CircleRole = "moderator"
+	CircleRoleOwner     CircleRole = "owner"
+)
+
 type CircleMembership struct {
 	ID       uuid.UUID `db:"id" json:"id"`
 	CircleID uuid.UUID `db:"circle_id" json:"circle_id"`
 	UserID   uuid.UUID `db:"user_id" json:"user_id"`
 	JoinedAt time.Time `db:"joined_at" json:"joined_at"`
 	Role     string    `db:"role" json:"role"`
+	Online   bool      `db:"-" json:"online"`
 }
 
 // Invite represents a circle invitation
@@ -0,0 +1,40 @@
+package domain
+
+import "strings"
+
+// CrisisResourceType is how a crisis resource is reached
+type CrisisResourceType string
+
+const (
+	CrisisResourceTypeHotline      CrisisResourceType = "hotline"       // Phone line
+	CrisisResourceTypeTextLine     CrisisResourceType = "text_line"     // SMS or chat
+	CrisisResourceTypeLocalService CrisisResourceType = "local_service" // In-person service
+)
+
+// CrisisResource is a hotline, text line or local service someone in crisis
+// can contact. Resources without a country are shown everywhere.
+type CrisisResource struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Type        CrisisResourceType `json:"type"`
+	Country     string             `json:"country,omitempty"` // ISO 3166-1 alpha-2 code
+	Region      string             `json:"region,omitempty"`  // State or province within the country, e.g. "CA"
+	Phone       string             `json:"phone,omitempty"`
+	SMS         string             `json:"sms,omitempty"` // Number to text, with the keyword in Description
+	URL         string             `json:"url,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Hours       string             `json:"hours,omitempty"` // e.g. "24/7"
+	Languages   []string           `json:"languages,omitempty"`
+}
+
+// Serves reports whether the resource is meant for someone in country and
+// region. Resources without a region serve the whole country.
+func (r *CrisisResource) Serves(country, region string) bool {
+	if r.Country == "" {
+		return true
+	}
+	if !strings.EqualFold(r.Country, country) {
+		return false
+	}
+	return r.Region == "" || strings.EqualFold(r.Region, region)
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Plan is a subscription tier
+type Plan string
+
+const (
+	PlanFree    Plan = "free"
+	PlanPremium Plan = "premium"
+)
+
+// Entitlement is a feature a plan unlocks
+type Entitlement string
+
+const (
+	EntitlementLargeCircles     Entitlement = "large_circles"     // Circles over FreeCircleMaxMembers
+	EntitlementAdvancedInsights Entitlement = "advanced_insights" // Risk windows and coping effectiveness on the dashboard
+)
+
+// FreeCircleMaxMembers is the largest circle a user without
+// EntitlementLargeCircles can create
+const FreeCircleMaxMembers = 50
+
+// PlanEntitlements lists what each plan unlocks
+var PlanEntitlements = map[Plan][]Entitlement{
+	PlanFree:    {},
+	PlanPremium: {EntitlementLargeCircles, EntitlementAdvancedInsights},
+}
+
+// AllEntitlements are every entitlement, granted to everyone when
+// entitlements aren't enforced
+var AllEntitlements = []Entitlement{EntitlementLargeCircles, EntitlementAdvancedInsights}
+
+// SubscriptionStatus is where a subscription is in its lifecycle
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive   SubscriptionStatus = "active"
+	SubscriptionStatusTrialing SubscriptionStatus = "trialing"
+	SubscriptionStatusPastDue  SubscriptionStatus = "past_due" // Payment failed; kept while the provider retries
+	SubscriptionStatusInactive SubscriptionStatus = "inactive" // Not paid for yet, or paused
+	SubscriptionStatusCanceled SubscriptionStatus = "canceled"
+)
+
+// SubscriptionSource is who manages a subscription
+type SubscriptionSource string
+
+const (
+	SubscriptionSourceManual SubscriptionSource = "manual" // Granted by an admin
+	SubscriptionSourceStripe SubscriptionSource = "stripe" // Kept in sync by Stripe webhooks
+)
+
+// Subscription is a user's plan. Users without one are on PlanFree.
+type Subscription struct {
+	UserID             uuid.UUID          `db:"user_id" json:"user_id"`
+	Plan               Plan               `db:"plan" json:"plan"`
+	Status             SubscriptionStatus `db:"status" json:"status"`
+	Source             SubscriptionSource `db:"source" json:"source"`
+	ExternalID         *string            `db:"external_id" json:"external_id,omitempty"`                   // Stripe subscription ID
+	ExternalCustomerID *string            `db:"external_customer_id" json:"external_customer_id,omitempty"` // Stripe customer ID
+	CurrentPeriodEnd   *time.Time         `db:"current_period_end" json:"current_period_end,omitempty"`     // Nil for subscriptions that don't expire
+	CreatedAt          time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time          `db:"updated_at" json:"updated_at"`
+}
+
+// EffectivePlan returns the plan the subscription grants at now: its plan
+// while it's active or being retried and not past its period end, otherwise
+// PlanFree
+func (s *Subscription) EffectivePlan(now time.Time) Plan {
+	if s == nil {
+		return PlanFree
+	}
+	switch s.Status {
+	case SubscriptionStatusActive, SubscriptionStatusTrialing, SubscriptionStatusPastDue:
+	default:
+		return PlanFree
+	}
+	if s.CurrentPeriodEnd != nil && !now.Before(*s.CurrentPeriodEnd) {
+		return PlanFree
+	}
+	return s.Plan
+}
+
+// Grants reports whether plan includes entitlement
+func (p Plan) Grants(entitlement Entitlement) bool {
+	return slices.Contains(PlanEntitlements[p], entitlement)
+}
@@ -0,0 +1,35 @@
+package domain
+
+// Domain event types published on the event bus
+const (
+	EventPostPublished       = "post.published"
+	EventPostWithdrawn       = "post.withdrawn"
+	EventResponseCreated     = "response.created"
+	EventMilestoneReached    = "progress.milestone_reached"
+	EventAchievementUnlocked = "progress.achievement_unlocked"
+)
+
+// PostPublishedEvent is published when a new post becomes visible to others
+type PostPublishedEvent struct {
+	Post Post `json:"post"`
+}
+
+// PostWithdrawnEvent is published when moderation hides or removes a post
+type PostWithdrawnEvent struct {
+	PostID string `json:"post_id"`
+}
+
+// ResponseCreatedEvent is published when someone responds to a post
+type ResponseCreatedEvent struct {
+	PostID       string       `json:"post_id"`
+	PostAuthorID string       `json:"post_author_id"`
+	ResponseID   string       `json:"response_id"`
+	UserID       string       `json:"user_id"`
+	Username     string       `json:"username"`
+	Type         ResponseType `json:"type"`
+	Content      string       `json:"content"`
+
+	// ContentEncrypted is set when Content is ciphertext because the post is
+	// in a sensitive category
+	ContentEncrypted bool `json:"content_encrypted,omitempty"`
+}
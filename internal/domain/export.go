@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportDataset is a table of moderation or analytics data admins can export
+type ExportDataset string
+
+const (
+	ExportDatasetReports       ExportDataset = "reports"
+	ExportDatasetAuditLogs     ExportDataset = "audit_logs"
+	ExportDatasetContentVolume ExportDataset = "content_volume" // Posts and responses per day
+)
+
+// ExportFormat is the file format an export is written in
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+// ExportStatus is where an export job is in its lifecycle
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// DataExport is a job writing one dataset's rows created in [Since, Until)
+// to a file in object storage
+type DataExport struct {
+	ID          uuid.UUID     `db:"id"`
+	Dataset     ExportDataset `db:"dataset"`
+	Format      ExportFormat  `db:"format"`
+	Since       time.Time     `db:"since"`
+	Until       time.Time     `db:"until"`
+	Columns     string        `db:"columns"` // Comma-separated columns written, after exclusions
+	Status      ExportStatus  `db:"status"`
+	ObjectKey   string        `db:"object_key"` // Set once completed
+	Rows        int64         `db:"row_count"`
+	Error       *string       `db:"error"`
+	RequestedBy *uuid.UUID    `db:"requested_by"` // Nil for scheduled exports and service clients
+	CreatedAt   time.Time     `db:"created_at"`
+	CompletedAt *time.Time    `db:"completed_at"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JournalEntry is a private journal entry, only ever visible to its author.
+// Content is stored encrypted; SearchTokens holds blind-indexed words so the
+// author can search entries without the server storing plaintext.
+type JournalEntry struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       string             `bson:"user_id" json:"user_id"`
+	Content      string             `bson:"content" json:"content"`
+	MoodScore    *int               `bson:"mood_score,omitempty" json:"mood_score,omitempty"` // 1-10
+	Triggers     []RelapseTrigger   `bson:"triggers,omitempty" json:"triggers,omitempty"`
+	SearchTokens []string           `bson:"search_tokens" json:"-"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// ContentVolume counts the posts and responses created on one UTC day
+type ContentVolume struct {
+	Day       time.Time `json:"day"` // Midnight UTC
+	Posts     int       `json:"posts"`
+	Responses int       `json:"responses"`
+}
+
+// SOSResponse records when an SOS post first got a response
+type SOSResponse struct {
+	PostID          string     `bson:"_id" json:"post_id"`
+	CreatedAt       time.Time  `bson:"created_at" json:"created_at"`
+	FirstResponseAt *time.Time `bson:"first_response_at,omitempty" json:"first_response_at,omitempty"` // Nil while unanswered
+}
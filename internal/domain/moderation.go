@@ -6,17 +6,106 @@ import (
 	"github.com/google/uuid"
 )
 
+// Report sources
+const (
+	ReportSourceUser = "user"
+	ReportSourceAuto = "auto"
+)
+
+// Report severities, from least to most urgent
+const (
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Severities lists every report severity, most urgent first
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+
+// ReportSeverity maps a report reason to the severity used for SLA tracking
+func ReportSeverity(reason string) string {
+	switch reason {
+	case "self_harm", "harmful_content", "threat":
+		return SeverityCritical
+	case "harassment", "hate_speech", "ban_evasion", "auto_moderation":
+		return SeverityHigh
+	case "spam":
+		return SeverityLow
+	default:
+		return SeverityMedium
+	}
+}
+
 type ContentReport struct {
-	ID          uuid.UUID  `db:"id" json:"id"`
-	ReporterID  uuid.UUID  `db:"reporter_id" json:"reporter_id"`
-	ContentType string     `db:"content_type" json:"content_type"`
-	ContentID   string     `db:"content_id" json:"content_id"`
-	Reason      string     `db:"reason" json:"reason"`
-	Description string     `db:"description" json:"description"`
-	Status      string     `db:"status" json:"status"`
-	ReviewedBy  *uuid.UUID `db:"reviewed_by" json:"reviewed_by,omitempty"`
-	ReviewedAt  *time.Time `db:"reviewed_at" json:"reviewed_at,omitempty"`
-	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	ID            uuid.UUID  `db:"id" json:"id"`
+	ReporterID    *uuid.UUID `db:"reporter_id" json:"reporter_id,omitempty"` // nil for cases opened by auto-moderation
+	Source        string     `db:"source" json:"source"`
+	ContentType   string     `db:"content_type" json:"content_type"`
+	ContentID     string     `db:"content_id" json:"content_id"`
+	Reason        string     `db:"reason" json:"reason"`
+	Description   string     `db:"description" json:"description"`
+	Status        string     `db:"status" json:"status"`
+	Severity      string     `db:"severity" json:"severity"`
+	ReporterCount int        `db:"reporter_count" json:"reporter_count"`
+	ReportScore   float64    `db:"report_score" json:"report_score"` // Sum of reporter reputation weights
+	ReviewedBy    *uuid.UUID `db:"reviewed_by" json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time `db:"reviewed_at" json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	DeletedAt     *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+}
+
+// ModerationSeverityStats summarises the moderation queue for one severity
+type ModerationSeverityStats struct {
+	Severity             string        `db:"severity" json:"severity"`
+	OpenCount            int           `db:"open_count" json:"open_count"`
+	OldestOpenAt         *time.Time    `db:"oldest_open_at" json:"oldest_open_at,omitempty"`
+	ResolvedCount        int           `db:"resolved_count" json:"resolved_count"`
+	AvgResolutionSeconds float64       `db:"avg_resolution_seconds" json:"avg_resolution_seconds"`
+	SLA                  time.Duration `db:"-" json:"sla"`
+	SLABreaches          int           `db:"-" json:"sla_breaches"` // Open reports older than the SLA
+}
+
+// OldestOpenAge returns how long the oldest open report has been waiting
+func (s *ModerationSeverityStats) OldestOpenAge(now time.Time) time.Duration {
+	if s.OldestOpenAt == nil {
+		return 0
+	}
+	return now.Sub(*s.OldestOpenAt)
+}
+
+// ReporterReputation tracks how often a user's reports are upheld by moderators
+type ReporterReputation struct {
+	UserID           uuid.UUID `db:"user_id" json:"user_id"`
+	ReportsSubmitted int       `db:"reports_submitted" json:"reports_submitted"`
+	ReportsUpheld    int       `db:"reports_upheld" json:"reports_upheld"`
+	ReportsRejected  int       `db:"reports_rejected" json:"reports_rejected"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Weight returns how much a report from this user counts towards a case.
+// New reporters count fully; reporters whose reports are consistently
+// rejected are downweighted, never below 0.1.
+func (r *ReporterReputation) Weight() float64 {
+	weight := float64(r.ReportsUpheld+1) / float64(r.ReportsUpheld+r.ReportsRejected+1)
+	if weight > 1 {
+		return 1
+	}
+	if weight < 0.1 {
+		return 0.1
+	}
+	return weight
+}
+
+// ModeratorNote is an internal comment on a report case. Notes may reply to
+// another note via ParentID to form a thread.
+type ModeratorNote struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	ReportID  uuid.UUID  `db:"report_id" json:"report_id"`
+	AuthorID  uuid.UUID  `db:"author_id" json:"author_id"`
+	ParentID  *uuid.UUID `db:"parent_id" json:"parent_id,omitempty"`
+	Body      string     `db:"body" json:"body"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 }
 
 type UserBlock struct {
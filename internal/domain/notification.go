@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Device platforms that can receive push notifications
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+	PlatformWeb     = "web"
+)
+
+// DeviceToken is a push notification token registered by a user's device
+type DeviceToken struct {
+	Token         string     `db:"token" json:"token"`
+	UserID        uuid.UUID  `db:"user_id" json:"user_id"`
+	Platform      string     `db:"platform" json:"platform"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+	InvalidatedAt *time.Time `db:"invalidated_at" json:"invalidated_at,omitempty"`
+	InvalidReason *string    `db:"invalid_reason" json:"invalid_reason,omitempty"`
+}
+
+// NotificationType identifies the event that generated an in-app notification
+type NotificationType string
+
+const (
+	NotificationTypeResponse          NotificationType = "response"
+	NotificationTypeMention           NotificationType = "mention"
+	NotificationTypeCircleInvite      NotificationType = "circle_invite"
+	NotificationTypeModerationOutcome NotificationType = "moderation_outcome"
+	NotificationTypeSystem            NotificationType = "system"
+	NotificationTypeCheckInReminder   NotificationType = "check_in_reminder"
+	NotificationTypeWeeklyDigest      NotificationType = "weekly_digest"
+	NotificationTypeMilestone         NotificationType = "milestone"
+	NotificationTypeAchievement       NotificationType = "achievement"
+	NotificationTypeSOS               NotificationType = "sos_request"
+	NotificationTypeRiskWindow        NotificationType = "risk_window"
+	NotificationTypeCrisisResources   NotificationType = "crisis_resources"
+)
+
+// Notification is an entry in a user's in-app notification inbox
+type Notification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	Type      NotificationType   `bson:"type" json:"type"`
+	Title     string             `bson:"title" json:"title"`
+	Body      string             `bson:"body" json:"body"`
+	Data      map[string]string  `bson:"data,omitempty" json:"data,omitempty"` // e.g. post_id, report_id
+	ReadAt    *time.Time         `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// IsRead reports whether the user has read the notification
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+// NotificationChannel is how a user wants to receive one type of notification
+type NotificationChannel string
+
+const (
+	NotificationChannelPush  NotificationChannel = "push"   // Push to devices and add to the inbox
+	NotificationChannelInApp NotificationChannel = "in_app" // Inbox only
+	NotificationChannelOff   NotificationChannel = "off"
+	NotificationChannelEmail NotificationChannel = "email" // Email and add to the inbox
+)
+
+// EmailNotificationTypes are the notification types that may be delivered by email
+var EmailNotificationTypes = map[NotificationType]bool{
+	NotificationTypeWeeklyDigest: true,
+}
+
+// DefaultNotificationChannels are used for event types a user has not configured
+var DefaultNotificationChannels = map[NotificationType]NotificationChannel{
+	NotificationTypeResponse:          NotificationChannelPush,
+	NotificationTypeMention:           NotificationChannelPush,
+	NotificationTypeCircleInvite:      NotificationChannelInApp,
+	NotificationTypeModerationOutcome: NotificationChannelInApp,
+	NotificationTypeSystem:            NotificationChannelPush,
+	NotificationTypeCheckInReminder:   NotificationChannelPush,
+	NotificationTypeWeeklyDigest:      NotificationChannelPush,
+	NotificationTypeMilestone:         NotificationChannelPush,
+	NotificationTypeAchievement:       NotificationChannelPush,
+	NotificationTypeSOS:               NotificationChannelPush,
+	NotificationTypeRiskWindow:        NotificationChannelPush,
+	NotificationTypeCrisisResources:   NotificationChannelPush,
+}
+
+// NotificationPreferences controls which notifications a user receives and how
+type NotificationPreferences struct {
+	UserID            uuid.UUID
+	Channels          map[NotificationType]NotificationChannel
+	QuietHoursEnabled bool
+	QuietHoursStart   string // Local time as HH:MM
+	QuietHoursEnd     string // Local time as HH:MM; may be earlier than start to span midnight
+	Timezone          string // IANA time zone name
+	// Daily reminder to check in, sent at CheckInReminderTime local time
+	CheckInReminderEnabled bool
+	CheckInReminderTime    string // Local time as HH:MM
+	UpdatedAt              time.Time
+}
+
+// DefaultNotificationPreferences returns the preferences used before a user changes anything
+func DefaultNotificationPreferences(userID uuid.UUID) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:              userID,
+		Channels:            map[NotificationType]NotificationChannel{},
+		QuietHoursStart:     "22:00",
+		QuietHoursEnd:       "07:00",
+		Timezone:            "UTC",
+		CheckInReminderTime: "20:00",
+	}
+}
+
+// ChannelFor returns the delivery channel for a notification type
+func (p *NotificationPreferences) ChannelFor(notificationType NotificationType) NotificationChannel {
+	if channel, ok := p.Channels[notificationType]; ok {
+		return channel
+	}
+	if channel, ok := DefaultNotificationChannels[notificationType]; ok {
+		return channel
+	}
+	return NotificationChannelInApp
+}
+
+// Location returns the user's time zone, falling back to UTC
+func (p *NotificationPreferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// InQuietHours reports whether the given instant falls inside the user's quiet hours
+func (p *NotificationPreferences) InQuietHours(now time.Time) bool {
+	if !p.QuietHoursEnabled {
+		return false
+	}
+
+	start, errStart := time.Parse("15:04", p.QuietHoursStart)
+	end, errEnd := time.Parse("15:04", p.QuietHoursEnd)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	local := now.In(p.Location())
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	if startMinute <= endMinute {
+		return minute >= startMinute && minute < endMinute
+	}
+	// Window spans midnight, e.g. 22:00-07:00
+	return minute >= startMinute || minute < endMinute
+}
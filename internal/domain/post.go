@@ -15,23 +15,105 @@ const (
 	PostTypeQuestion PostType = "question"
 )
 
+// ModerationState controls where a post is shown
+type ModerationState string
+
+const (
+	// ModerationStateVisible posts appear in feeds for everyone
+	ModerationStateVisible ModerationState = "visible"
+	// ModerationStateQuarantined posts are hidden from feeds pending review but still visible to their author
+	ModerationStateQuarantined ModerationState = "quarantined"
+	// ModerationStateRemoved posts have been taken down by a moderator
+	ModerationStateRemoved ModerationState = "removed"
+)
+
 type Post struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID          string             `bson:"user_id" json:"user_id"`
-	Username        string             `bson:"username" json:"username"`
-	Type            PostType           `bson:"type" json:"type"`
-	Content         string             `bson:"content" json:"content"`
-	Categories      []string           `bson:"categories" json:"categories"`
-	UrgencyLevel    int                `bson:"urgency_level" json:"urgency_level"`
-	Context         PostContext        `bson:"context" json:"context"`
-	Visibility      string             `bson:"visibility" json:"visibility"`
-	CircleID        *string            `bson:"circle_id,omitempty" json:"circle_id,omitempty"`
-	ResponseCount   int                `bson:"response_count" json:"response_count"`
-	SupportCount    int                `bson:"support_count" json:"support_count"`
-	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
-	ExpiresAt       *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
-	IsModerated     bool               `bson:"is_moderated" json:"is_moderated"`
-	ModerationFlags []string           `bson:"moderation_flags,omitempty" json:"moderation_flags,omitempty"`
+	ID              primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	UserID          string                 `bson:"user_id" json:"user_id"`
+	Username        string                 `bson:"username" json:"username"`
+	Type            PostType               `bson:"type" json:"type"`
+	Content         string                 `bson:"content" json:"content"`
+	Categories      []string               `bson:"categories" json:"categories"`
+	UrgencyLevel    int                    `bson:"urgency_level" json:"urgency_level"`
+	Context         PostContext            `bson:"context" json:"context"`
+	Visibility      string                 `bson:"visibility" json:"visibility"`
+	CircleID        *string                `bson:"circle_id,omitempty" json:"circle_id,omitempty"`
+	ResponseCount   int                    `bson:"response_count" json:"response_count"`
+	SupportCount    int                    `bson:"support_count" json:"support_count"`
+	CreatedAt       time.Time              `bson:"created_at" json:"created_at"`
+	ExpiresAt       *time.Time             `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	ModerationState ModerationState        `bson:"moderation_state" json:"moderation_state"`
+	ModerationFlags []string               `bson:"moderation_flags,omitempty" json:"moderation_flags,omitempty"`
+	Attachments     []Attachment           `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	ReactionCounts  map[ReactionType]int64 `bson:"reaction_counts,omitempty" json:"reaction_counts,omitempty"`
+
+	// ContentEncrypted is set while Content holds ciphertext, for posts in
+	// sensitive categories
+	ContentEncrypted bool `bson:"content_encrypted,omitempty" json:"content_encrypted,omitempty"`
+}
+
+// FeedPage is one page of a feed with totals for the whole result set.
+// CategoryCounts and TypeCounts ignore their own filter so clients can show
+// how many posts each alternative category or type would return.
+type FeedPage struct {
+	Posts          []*Post            `json:"posts"`
+	TotalCount     int64              `json:"total_count"`
+	CategoryCounts map[string]int64   `json:"category_counts"`
+	TypeCounts     map[PostType]int64 `json:"type_counts"`
+}
+
+// FeedCursorPage is one page of a feed read with a cursor. NextCursor is
+// empty on the last page.
+type FeedCursorPage struct {
+	Posts      []*Post `json:"posts"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// MaxPostAttachments bounds the attachments on one post
+const MaxPostAttachments = 4
+
+type AttachmentType string
+
+const (
+	AttachmentTypeImage AttachmentType = "image"
+	AttachmentTypeLink  AttachmentType = "link"
+)
+
+// Attachment is an image or link shown with a post. Files are uploaded
+// elsewhere; posts only hold their URLs.
+type Attachment struct {
+	Type  AttachmentType `bson:"type" json:"type"`
+	URL   string         `bson:"url" json:"url"`
+	Title string         `bson:"title,omitempty" json:"title,omitempty"`
+}
+
+// ReactionType is a one-tap reaction to a post
+type ReactionType string
+
+const (
+	ReactionTypeHeart    ReactionType = "heart"
+	ReactionTypeHug      ReactionType = "hug"
+	ReactionTypeStrength ReactionType = "strength"
+	ReactionTypeRelate   ReactionType = "relate"
+)
+
+// Valid reports whether r is a known reaction type
+func (r ReactionType) Valid() bool {
+	switch r {
+	case ReactionTypeHeart, ReactionTypeHug, ReactionTypeStrength, ReactionTypeRelate:
+		return true
+	default:
+		return false
+	}
+}
+
+// PostReaction is a user's reaction to a post. Users have at most one
+// reaction per post; reacting again replaces it.
+type PostReaction struct {
+	PostID    string       `bson:"post_id" json:"post_id"`
+	UserID    string       `bson:"user_id" json:"user_id"`
+	Type      ReactionType `bson:"type" json:"type"`
+	CreatedAt time.Time    `bson:"created_at" json:"created_at"`
 }
 
 type PostContext struct {
@@ -39,3 +121,28 @@ type PostContext struct {
 	TimeContext      string   `bson:"time_context" json:"time_context"`
 	Tags             []string `bson:"tags" json:"tags"`
 }
+
+// IsQuarantined reports whether the post is hidden pending moderator review
+func (p *Post) IsQuarantined() bool {
+	return p.ModerationState == ModerationStateQuarantined
+}
+
+// VisibleTo reports whether the given viewer may see the post
+func (p *Post) VisibleTo(viewerID string) bool {
+	switch p.ModerationState {
+	case ModerationStateRemoved:
+		return false
+	case ModerationStateQuarantined:
+		return viewerID != "" && viewerID == p.UserID
+	default:
+		return true
+	}
+}
+
+// ModerationBanner returns the explanation shown to the author of a quarantined post
+func (p *Post) ModerationBanner() string {
+	if !p.IsQuarantined() {
+		return ""
+	}
+	return "This post is hidden from others while our moderators review it. Only you can see it right now."
+}
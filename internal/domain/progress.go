@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Mood scores are self-reported on a 1-10 scale
+const (
+	MinMoodScore = 1
+	MaxMoodScore = 10
+)
+
+// MoodEntry is a user's self-reported mood for one day. A later check-in on
+// the same day replaces the earlier score.
+type MoodEntry struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     string             `bson:"user_id" json:"user_id"`
+	Day        time.Time          `bson:"day" json:"day"` // Midnight UTC of the day the entry covers
+	Score      int                `bson:"score" json:"score"`
+	RecordedAt time.Time          `bson:"recorded_at" json:"recorded_at"`
+}
+
+// SavingsBaseline is what the tracked behavior used to cost a user each day,
+// used to estimate the money and time reclaimed by each clean day
+type SavingsBaseline struct {
+	DailySpendCents int64  `bson:"daily_spend_cents" json:"daily_spend_cents"`
+	Currency        string `bson:"currency" json:"currency"` // ISO 4217 code, e.g. USD
+	DailyMinutes    int    `bson:"daily_minutes" json:"daily_minutes"`
+}
+
+// CopingStat counts how often a user tried a coping strategy during a craving
+// and how often it helped
+type CopingStat struct {
+	Uses       int       `bson:"uses" json:"uses"`
+	Helped     int       `bson:"helped" json:"helped"`
+	LastUsedAt time.Time `bson:"last_used_at" json:"last_used_at"`
+}
+
+// DailyCheckIn records a user's check-in and cravings on one day
+type DailyCheckIn struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID           string             `bson:"user_id" json:"user_id"`
+	Day              time.Time          `bson:"day" json:"day"` // Midnight UTC
+	CheckedIn        bool               `bson:"checked_in" json:"checked_in"`
+	HadRelapse       bool               `bson:"had_relapse" json:"had_relapse"`
+	Cravings         int                `bson:"cravings" json:"cravings"`
+	CravingsResisted int                `bson:"cravings_resisted" json:"cravings_resisted"`
+	Answers          []CheckInAnswer    `bson:"answers,omitempty" json:"answers,omitempty"` // From the day's latest check-in
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// CheckInAnswer is a user's answer to one check-in question; exactly one
+// value is set, matching the question's type
+type CheckInAnswer struct {
+	QuestionID string  `bson:"question_id" json:"question_id"`
+	Scale      *int    `bson:"scale,omitempty" json:"scale,omitempty"`
+	Boolean    *bool   `bson:"boolean,omitempty" json:"boolean,omitempty"`
+	Choice     *string `bson:"choice,omitempty" json:"choice,omitempty"`
+}
+
+// RelapseTrigger is what a user says led to a relapse
+type RelapseTrigger string
+
+const (
+	RelapseTriggerStress     RelapseTrigger = "stress"
+	RelapseTriggerBoredom    RelapseTrigger = "boredom"
+	RelapseTriggerLoneliness RelapseTrigger = "loneliness"
+	RelapseTriggerSocial     RelapseTrigger = "social"
+	RelapseTriggerEmotional  RelapseTrigger = "emotional"
+	RelapseTriggerCraving    RelapseTrigger = "craving"
+	RelapseTriggerOther      RelapseTrigger = "other"
+)
+
+// ValidRelapseTriggers lists the triggers a relapse may be logged with
+var ValidRelapseTriggers = map[RelapseTrigger]bool{
+	RelapseTriggerStress:     true,
+	RelapseTriggerBoredom:    true,
+	RelapseTriggerLoneliness: true,
+	RelapseTriggerSocial:     true,
+	RelapseTriggerEmotional:  true,
+	RelapseTriggerCraving:    true,
+	RelapseTriggerOther:      true,
+}
+
+// RelapseRecord is a logged relapse. TimeOfDay and DayOfWeek are taken from
+// the user's local time when it was recorded.
+type RelapseRecord struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     string             `bson:"user_id" json:"user_id"`
+	OccurredAt time.Time          `bson:"occurred_at" json:"occurred_at"`
+	TimeOfDay  string             `bson:"time_of_day" json:"time_of_day"` // morning, afternoon, evening, night
+	DayOfWeek  string             `bson:"day_of_week" json:"day_of_week"`
+	Trigger    RelapseTrigger     `bson:"trigger" json:"trigger"`
+	Note       string             `bson:"note,omitempty" json:"note,omitempty"`
+	DaysClean  int                `bson:"days_clean" json:"days_clean"` // Streak length the relapse ended
+	RecordedAt time.Time          `bson:"recorded_at" json:"recorded_at"`
+}
+
+// TimeOfDay buckets a local time into morning, afternoon, evening or night
+func TimeOfDay(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour >= 5 && hour < 12:
+		return "morning"
+	case hour >= 12 && hour < 17:
+		return "afternoon"
+	case hour >= 17 && hour < 21:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+// ProgressDay returns midnight UTC of the day containing t; daily progress is
+// bucketed by UTC day
+func ProgressDay(t time.Time) time.Time {
+	return LocalDay(t, time.UTC)
+}
+
+// LocalDay returns the calendar day containing t in loc as midnight UTC of
+// that date, so a user's days can be stored and compared like ProgressDay.
+// Days are stepped as dates, so a DST change never makes one 23 or 25 hours.
+func LocalDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Weights of events counted towards a user's vulnerability pattern; a relapse
+// is a stronger signal of a risky hour than a craving
+const (
+	CravingRiskWeight = 1
+	RelapseRiskWeight = 2
+)
+
+const (
+	riskWindowMinEvents = 3 // Hours with fewer weighted events are never flagged
+	maxRiskWindows      = 3
+)
+
+// RiskWindow is a run of hours (UTC) in which a user often craves or relapses
+type RiskWindow struct {
+	StartHour int `bson:"start_hour" json:"start_hour"` // 0-23, UTC
+	EndHour   int `bson:"end_hour" json:"end_hour"`     // Exclusive; below StartHour when the window spans midnight
+	Events    int `bson:"events" json:"events"`         // Weighted cravings and relapses
+}
+
+// VulnerabilityHour is the vulnerability pattern key for the UTC hour of t
+func VulnerabilityHour(t time.Time) string {
+	return fmt.Sprintf("%02d", t.UTC().Hour())
+}
+
+// RiskWindowsFromPattern finds the user's riskiest windows, most events first.
+// An hour is risky when it has at least twice the average hour's events;
+// adjacent risky hours, including across midnight, form one window.
+func RiskWindowsFromPattern(pattern map[string]int) []RiskWindow {
+	var counts [24]int
+	total := 0
+	for hour := range counts {
+		counts[hour] = pattern[fmt.Sprintf("%02d", hour)]
+		total += counts[hour]
+	}
+
+	risky := func(hour int) bool {
+		count := counts[hour%24]
+		return count >= riskWindowMinEvents && count*24 >= 2*total
+	}
+
+	// Start scanning after a calm hour so a window spanning midnight isn't split.
+	// At twice the average, at most half the hours can be risky.
+	start := 0
+	for risky(start) {
+		start++
+	}
+
+	windows := []RiskWindow{}
+	for offset := 1; offset <= 24; offset++ {
+		hour := (start + offset) % 24
+		if !risky(hour) {
+			continue
+		}
+		if len(windows) > 0 && windows[len(windows)-1].EndHour == hour {
+			windows[len(windows)-1].EndHour = (hour + 1) % 24
+			windows[len(windows)-1].Events += counts[hour]
+			continue
+		}
+		windows = append(windows, RiskWindow{StartHour: hour, EndHour: (hour + 1) % 24, Events: counts[hour]})
+	}
+
+	sort.SliceStable(windows, func(i, j int) bool { return windows[i].Events > windows[j].Events })
+	if len(windows) > maxRiskWindows {
+		windows = windows[:maxRiskWindows]
+	}
+	return windows
+}
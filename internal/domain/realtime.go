@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Realtime event types relayed to WebSocket clients
+const (
+	RealtimeEventNewPost     = "new_post"
+	RealtimeEventNewResponse = "new_response"
+	RealtimeEventPresence    = "presence"
+	// RealtimeEventSubscriptionRevoked removes a user's subscription to Channel,
+	// e.g. after they leave a circle
+	RealtimeEventSubscriptionRevoked = "subscription_revoked"
+)
+
+// PresenceEvent reports a user coming online or going offline in a circle
+type PresenceEvent struct {
+	UserID   string `json:"user_id"`
+	CircleID string `json:"circle_id"`
+	Online   bool   `json:"online"`
+}
+
+// RealtimeEvent is a live update fanned out to every WebSocket hub instance,
+// each of which relays it to its locally connected clients
+type RealtimeEvent struct {
+	Type      string          `json:"type"`
+	UserID    string          `json:"user_id,omitempty"`   // Recipient; empty broadcasts to everyone
+	Channel   string          `json:"channel,omitempty"`   // Limits a broadcast to subscribers, e.g. circle:{id}
+	SenderID  string          `json:"sender_id,omitempty"` // Originating user; used to enforce blocks
+	Data      json.RawMessage `json:"data,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// NewPostRealtimeEvent announces a new post to everyone
+func NewPostRealtimeEvent(postID, authorID, postType string, categories []string) (*RealtimeEvent, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"post_id":    postID,
+		"type":       postType,
+		"categories": categories,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RealtimeEvent{Type: RealtimeEventNewPost, SenderID: authorID, Data: data}, nil
+}
+
+// NewResponseRealtimeEvent announces a new response to everyone
+func NewResponseRealtimeEvent(postID, responseID, responderID string) (*RealtimeEvent, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"post_id":     postID,
+		"response_id": responseID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RealtimeEvent{Type: RealtimeEventNewResponse, SenderID: responderID, Data: data}, nil
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// DataClass is a kind of stored data with its own retention rule
+type DataClass string
+
+const (
+	DataClassPosts           DataClass = "posts"
+	DataClassResponses       DataClass = "responses"
+	DataClassAuditLogs       DataClass = "audit_logs"
+	DataClassAnalyticsEvents DataClass = "analytics_events" // Mood entries and relapse events
+)
+
+// RetentionAction is what happens to records older than their retention period
+type RetentionAction string
+
+const (
+	RetentionActionDelete RetentionAction = "delete"
+	// RetentionActionAnonymize keeps the record but removes who it belongs to
+	RetentionActionAnonymize RetentionAction = "anonymize"
+)
+
+// RetentionRule keeps records of a data class for MaxAge, then applies Action
+type RetentionRule struct {
+	Class  DataClass
+	MaxAge time.Duration
+	Action RetentionAction
+}
+
+// RetentionResult reports the records one rule affected, or would affect in a dry run
+type RetentionResult struct {
+	Class   DataClass
+	Action  RetentionAction
+	Cutoff  time.Time // Records created before it are past retention
+	Records int64
+	DryRun  bool
+}
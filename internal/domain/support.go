@@ -24,25 +24,159 @@ type SupportResponse struct {
 	VoiceNoteURL   *string            `bson:"voice_note_url,omitempty" json:"voice_note_url,omitempty"`
 	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
 	StrengthPoints int                `bson:"strength_points" json:"strength_points"`
+
+	// ContentEncrypted is set while Content holds ciphertext, for responses to
+	// posts in sensitive categories
+	ContentEncrypted bool `bson:"content_encrypted,omitempty" json:"content_encrypted,omitempty"`
 }
 
 type UserTracker struct {
-	ID                   primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID               string             `bson:"user_id" json:"user_id"`
-	StreakDays           int                `bson:"streak_days" json:"streak_days"`
-	LongestStreak        int                `bson:"longest_streak" json:"longest_streak"`
-	TotalDaysClean       int                `bson:"total_days_clean" json:"total_days_clean"`
-	TotalRelapses        int                `bson:"total_relapses" json:"total_relapses"`
-	LastRelapseDate      *time.Time         `bson:"last_relapse_date,omitempty" json:"last_relapse_date,omitempty"`
-	TotalCravings        int                `bson:"total_cravings" json:"total_cravings"`
-	CravingsResisted     int                `bson:"cravings_resisted" json:"cravings_resisted"`
-	SupportGiven         int                `bson:"support_given" json:"support_given"`
-	SupportReceived      int                `bson:"support_received" json:"support_received"`
-	VulnerabilityPattern map[string]int     `bson:"vulnerability_pattern" json:"vulnerability_pattern"`
-	Categories           []string           `bson:"categories" json:"categories"`
-	Goals                []Goal             `bson:"goals" json:"goals"`
-	Milestones           []Milestone        `bson:"milestones" json:"milestones"`
-	UpdatedAt            time.Time          `bson:"updated_at" json:"updated_at"`
+	ID                   primitive.ObjectID    `bson:"_id,omitempty" json:"id"`
+	UserID               string                `bson:"user_id" json:"user_id"`
+	StreakDays           int                   `bson:"streak_days" json:"streak_days"`
+	LongestStreak        int                   `bson:"longest_streak" json:"longest_streak"`
+	TotalDaysClean       int                   `bson:"total_days_clean" json:"total_days_clean"`
+	TotalRelapses        int                   `bson:"total_relapses" json:"total_relapses"`
+	LastRelapseDate      *time.Time            `bson:"last_relapse_date,omitempty" json:"last_relapse_date,omitempty"`
+	LastCheckInAt        *time.Time            `bson:"last_check_in_at,omitempty" json:"last_check_in_at,omitempty"`
+	LastDigest           *DigestSnapshot       `bson:"last_digest,omitempty" json:"last_digest,omitempty"`
+	TotalCravings        int                   `bson:"total_cravings" json:"total_cravings"`
+	CravingsResisted     int                   `bson:"cravings_resisted" json:"cravings_resisted"`
+	SupportGiven         int                   `bson:"support_given" json:"support_given"`
+	SupportReceived      int                   `bson:"support_received" json:"support_received"`
+	VulnerabilityPattern map[string]int        `bson:"vulnerability_pattern" json:"vulnerability_pattern"` // Weighted cravings and relapses by UTC hour, "00" to "23"
+	Categories           []string              `bson:"categories" json:"categories"`
+	Goals                []Goal                `bson:"goals" json:"goals"`
+	Milestones           []Milestone           `bson:"milestones" json:"milestones"`
+	Achievements         []UnlockedAchievement `bson:"achievements,omitempty" json:"achievements,omitempty"`
+	StreakFreezes        []time.Time           `bson:"streak_freezes,omitempty" json:"streak_freezes,omitempty"` // Days covered by a freeze, as LocalDay dates
+	Timezone             string                `bson:"timezone,omitempty" json:"timezone,omitempty"`             // IANA zone the user's days are counted in; empty is UTC
+	SavingsBaseline      *SavingsBaseline      `bson:"savings_baseline,omitempty" json:"savings_baseline,omitempty"`
+	RiskWindows          []RiskWindow          `bson:"risk_windows,omitempty" json:"risk_windows,omitempty"` // Derived from VulnerabilityPattern
+	LastRiskNudgeAt      *time.Time            `bson:"last_risk_nudge_at,omitempty" json:"last_risk_nudge_at,omitempty"`
+	CopingStats          map[string]CopingStat `bson:"coping_stats,omitempty" json:"coping_stats,omitempty"` // Keyed by coping strategy ID
+	UpdatedAt            time.Time             `bson:"updated_at" json:"updated_at"`
+}
+
+// CheckedInOn reports whether the user checked in on the same local calendar day as now
+func (t *UserTracker) CheckedInOn(now time.Time, loc *time.Location) bool {
+	if t.LastCheckInAt == nil {
+		return false
+	}
+	y1, m1, d1 := t.LastCheckInAt.In(loc).Date()
+	y2, m2, d2 := now.In(loc).Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// Location returns the time zone the user's days are counted in, UTC when
+// none is set or it's unknown
+func (t *UserTracker) Location() *time.Location {
+	if t.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Today returns the user's current day at now, as a LocalDay date
+func (t *UserTracker) Today(now time.Time) time.Time {
+	return LocalDay(now, t.Location())
+}
+
+// FreezesUsed returns the days frozen in the calendar month containing day, a
+// LocalDay date
+func (t *UserTracker) FreezesUsed(day time.Time) []time.Time {
+	year, month, _ := day.UTC().Date()
+	used := []time.Time{}
+	for _, day := range t.StreakFreezes {
+		if y, m, _ := day.UTC().Date(); y == year && m == month {
+			used = append(used, day)
+		}
+	}
+	return used
+}
+
+// FreezesAvailable returns how many streak freezes are left in the month containing day
+func (t *UserTracker) FreezesAvailable(day time.Time, perMonth int) int {
+	return max(perMonth-len(t.FreezesUsed(day)), 0)
+}
+
+// IsFrozen reports whether day is covered by a streak freeze
+func (t *UserTracker) IsFrozen(day time.Time) bool {
+	for _, frozen := range t.StreakFreezes {
+		if frozen.Equal(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyCheckIn updates the streak for a check-in at now. Checking in again on
+// the same day changes nothing, and a relapse resets the streak. Missed days
+// break the streak unless each is covered by a freeze; a single uncovered
+// missed day uses one of that month's freezes automatically when one is left.
+// It reports whether a freeze was used.
+func (t *UserTracker) ApplyCheckIn(now time.Time, hadRelapse bool, freezesPerMonth int) bool {
+	if hadRelapse {
+		t.LastCheckInAt = &now
+		t.LastRelapseDate = &now
+		t.TotalRelapses++
+		t.StreakDays = 0
+		return false
+	}
+
+	// Days are counted in the user's time zone, so the streak rolls over at
+	// their midnight rather than the server's
+	today := t.Today(now)
+	froze := false
+	if t.LastCheckInAt != nil {
+		last := t.Today(*t.LastCheckInAt)
+		if !today.After(last) {
+			t.LastCheckInAt = &now
+			return false
+		}
+
+		var missed []time.Time
+		for day := last.AddDate(0, 0, 1); day.Before(today); day = day.AddDate(0, 0, 1) {
+			if !t.IsFrozen(day) {
+				missed = append(missed, day)
+			}
+		}
+
+		switch {
+		case len(missed) == 0:
+		case len(missed) == 1 && t.FreezesAvailable(missed[0], freezesPerMonth) > 0:
+			t.StreakFreezes = append(t.StreakFreezes, missed[0])
+			froze = true
+		default:
+			t.StreakDays = 0
+		}
+	}
+
+	t.LastCheckInAt = &now
+	t.StreakDays++
+	t.TotalDaysClean++
+	t.LongestStreak = max(t.LongestStreak, t.StreakDays)
+	return froze
+}
+
+// UnlockedAchievement records when a user first earned an achievement
+type UnlockedAchievement struct {
+	ID         string    `bson:"id" json:"id"`
+	UnlockedAt time.Time `bson:"unlocked_at" json:"unlocked_at"`
+}
+
+// DigestSnapshot records the counters reported in a user's last weekly digest
+// so the next one can report what changed
+type DigestSnapshot struct {
+	SentAt          time.Time `bson:"sent_at" json:"sent_at"`
+	StreakDays      int       `bson:"streak_days" json:"streak_days"`
+	SupportGiven    int       `bson:"support_given" json:"support_given"`
+	SupportReceived int       `bson:"support_received" json:"support_received"`
+	Achievements    []string  `bson:"achievements" json:"achievements"` // Achievement IDs unlocked so far
 }
 
 type Goal struct {
@@ -27,6 +27,21 @@ type User struct {
 	IsBanned       bool      `db:"is_banned" json:"is_banned"`
 	IsPremium      bool      `db:"is_premium" json:"is_premium"`
 	StrengthPoints int       `db:"strength_points" json:"strength_points"`
+	// Auto-post a Victory in the user's circles when they reach a streak milestone
+	ShareMilestones bool       `db:"share_milestones" json:"share_milestones"`
+	DeletedAt       *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+}
+
+// UserSummary is the public identity shown next to a user in lists
+type UserSummary struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	AvatarID int       `json:"avatar_id"`
+}
+
+// Summary returns the user's public identity
+func (u *User) Summary() *UserSummary {
+	return &UserSummary{ID: u.ID, Username: u.Username, AvatarID: u.AvatarID}
 }
 
 type UserClaims struct {
@@ -34,3 +49,20 @@ type UserClaims struct {
 	Username    string `json:"username"`
 	IsAnonymous bool   `json:"is_anonymous"`
 }
+
+// LoginLocation is the last place a user logged in from within a country
+type LoginLocation struct {
+	UserID     uuid.UUID `db:"user_id"`
+	Country    string    `db:"country"`
+	Latitude   *float64  `db:"latitude"`
+	Longitude  *float64  `db:"longitude"`
+	LastSeenAt time.Time `db:"last_seen_at"`
+}
+
+// LoginChallenge is a pending step-up verification for a risky login
+type LoginChallenge struct {
+	ID       string
+	UserID   uuid.UUID
+	CodeHash string
+	Reasons  []string
+}
@@ -42,7 +42,8 @@ func (r *RegisterWithEmailRequest) Validate() error {
 	return nil
 }
 
-// LoginRequest represents a login request
+// LoginRequest represents a login request. Email holds either the account's
+// email or its username.
 type LoginRequest struct {
 	Email    string
 	Password string
@@ -50,8 +51,10 @@ type LoginRequest struct {
 
 // Validate validates the request
 func (r *LoginRequest) Validate() error {
-	if err := validator.ValidateEmail(r.Email); err != nil {
-		return apperrors.NewValidationError("Invalid email", err)
+	if validator.ValidateEmail(r.Email) != nil {
+		if err := validator.ValidateUsername(r.Email); err != nil {
+			return apperrors.NewValidationError("Invalid email or username", err)
+		}
 	}
 	if r.Password == "" {
 		return apperrors.NewValidationError("Password is required", nil)
@@ -78,6 +81,11 @@ type AuthResponse struct {
 	RefreshToken string
 	User         *UserDTO
 	ExpiresIn    int64 // Token expiration in seconds
+
+	// StepUpRequired is set instead of the tokens when a risky login must be
+	// confirmed with the code sent to the user's email
+	StepUpRequired bool
+	ChallengeID    string
 }
 
 // UserDTO represents user data for responses
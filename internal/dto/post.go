@@ -102,7 +102,7 @@ type PostDTO struct {
 	SupportCount     int32
 	CreatedAt        string
 	ExpiresAt        string
-	IsModerated      bool
+	ModerationState  string
 }
 
 // NewPostDTO creates a PostDTO from a domain.Post
@@ -132,7 +132,7 @@ func NewPostDTO(post *domain.Post) *PostDTO {
 		SupportCount:     int32(post.SupportCount),  //nolint:gosec // Support counts won't overflow int32
 		CreatedAt:        post.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		ExpiresAt:        expiresAt,
-		IsModerated:      post.IsModerated,
+		ModerationState:  string(post.ModerationState),
 	}
 }
 
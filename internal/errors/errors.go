@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"connectrpc.com/connect"
 	"go.uber.org/zap"
@@ -23,6 +25,8 @@ type AppError struct {
 	Internal error
 	// Fields contains additional context fields for logging
 	Fields map[string]interface{}
+	// RetryAfter tells clients when to retry, in a Retry-After header, if set
+	RetryAfter time.Duration
 }
 
 // Error implements the error interface
@@ -44,6 +48,10 @@ func (e *AppError) ToConnectError() *connect.Error {
 	if e.Code != "" {
 		err.Meta().Set("code", e.Code)
 	}
+	if e.RetryAfter > 0 {
+		seconds := (e.RetryAfter + time.Second - 1) / time.Second
+		err.Meta().Set("Retry-After", strconv.Itoa(int(seconds)))
+	}
 	return err
 }
 
@@ -0,0 +1,56 @@
+//go:build graphql
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	apperrors "github.com/yourorg/anonymous-support/internal/errors"
+	"github.com/yourorg/anonymous-support/internal/graph/generated"
+	"github.com/yourorg/anonymous-support/internal/pkg/redact"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxComplexity bounds query cost when none is configured. Each field
+// costs one, so it allows a full feed page with authors and a few responses.
+const DefaultMaxComplexity = 300
+
+// NewHandler serves GraphQL queries over GET and POST. Queries costing more
+// than maxComplexity are rejected before they run.
+func NewHandler(resolver *Resolver, maxComplexity int, introspection bool, logger *zap.Logger) http.Handler {
+	if maxComplexity <= 0 {
+		maxComplexity = DefaultMaxComplexity
+	}
+
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.FixedComplexityLimit(maxComplexity))
+	if introspection {
+		srv.Use(extension.Introspection{})
+	}
+	srv.SetErrorPresenter(func(ctx context.Context, err error) *gqlerror.Error {
+		gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			gqlErr.Message = appErr.Message
+			return gqlErr
+		}
+		if redact.LeaksInternals(gqlErr.Message) {
+			tracing.Logger(ctx, logger).Error("GraphQL resolver failed", zap.Error(err))
+			gqlErr.Message = "internal error"
+		}
+		return gqlErr
+	})
+
+	return resolver.withLoaders(srv)
+}
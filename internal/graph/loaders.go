@@ -0,0 +1,58 @@
+//go:build graphql
+
+package graph
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/pkg/dataloader"
+)
+
+// loaderWait is how long loaders collect keys before fetching. gqlgen
+// resolves the fields of a list's items concurrently, so they all land in
+// one batch.
+const loaderWait = 2 * time.Millisecond
+
+// maxUserBatch is the most users BatchGetUsers returns at once
+const maxUserBatch = 100
+
+// loaders batch the lookups field resolvers make for each item of a list
+type loaders struct {
+	users     *dataloader.Loader[string, *domain.User]
+	reactions *dataloader.Loader[string, domain.ReactionType] // The viewer's reaction, by post ID
+}
+
+type loadersKey struct{}
+
+// withLoaders gives each request its own loaders, so nothing is cached
+// between requests or viewers
+func (r *Resolver) withLoaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		viewerID, _ := middleware.GetUserID(req.Context())
+		l := &loaders{
+			users: dataloader.New(func(ctx context.Context, userIDs []string) (map[string]*domain.User, error) {
+				users, err := r.userService.BatchGetUsers(ctx, userIDs)
+				if err != nil {
+					return nil, err
+				}
+				byID := make(map[string]*domain.User, len(users))
+				for _, user := range users {
+					byID[user.ID.String()] = user
+				}
+				return byID, nil
+			}, loaderWait, maxUserBatch),
+			reactions: dataloader.New(func(ctx context.Context, postIDs []string) (map[string]domain.ReactionType, error) {
+				return r.reactionService.ViewerReactions(ctx, viewerID, postIDs)
+			}, loaderWait, 0),
+		}
+		next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), loadersKey{}, l)))
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,202 @@
+// Package model holds the Go types the GraphQL schema is bound to. Fields
+// the schema has but a type lacks, such as Post.author, have resolvers.
+package model
+
+import (
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/service"
+)
+
+// reactionOrder is the order reaction counts are listed in
+var reactionOrder = []domain.ReactionType{domain.ReactionTypeHeart, domain.ReactionTypeHug, domain.ReactionTypeStrength, domain.ReactionTypeRelate}
+
+type Viewer struct {
+	UserID string
+}
+
+type User struct {
+	ID             string
+	Username       string
+	AvatarID       int
+	StrengthPoints int
+	CreatedAt      time.Time
+}
+
+func NewUser(user *domain.User) *User {
+	return &User{
+		ID:             user.ID.String(),
+		Username:       user.Username,
+		AvatarID:       user.AvatarID,
+		StrengthPoints: user.StrengthPoints,
+		CreatedAt:      user.CreatedAt,
+	}
+}
+
+type Streak struct {
+	StreakDays       int
+	LongestStreak    int
+	TotalDaysClean   int
+	CravingsResisted int
+	TotalCravings    int
+	Timezone         string
+}
+
+func NewStreak(tracker *domain.UserTracker) *Streak {
+	return &Streak{
+		StreakDays:       tracker.StreakDays,
+		LongestStreak:    tracker.LongestStreak,
+		TotalDaysClean:   tracker.TotalDaysClean,
+		CravingsResisted: tracker.CravingsResisted,
+		TotalCravings:    tracker.TotalCravings,
+		Timezone:         tracker.Timezone,
+	}
+}
+
+type Progress struct {
+	CurrentStreak    int
+	LongestStreak    int
+	TotalDaysClean   int
+	Milestones       []string
+	CravingsResisted int
+	TotalCravings    int
+	SupportGiven     int
+	SupportReceived  int
+	Achievements     []*Achievement
+	WeeklyProgress   []*DayProgress
+}
+
+type Achievement struct {
+	ID          string
+	Title       string
+	Description string
+	Icon        string
+	Rarity      string
+	UnlockedAt  time.Time
+}
+
+type DayProgress struct {
+	Date          time.Time
+	CheckedIn     bool
+	CravingsCount int
+	SupportGiven  int
+	MoodScore     *int
+}
+
+func NewProgress(dashboard *service.ProgressDashboard) *Progress {
+	achievements := make([]*Achievement, len(dashboard.Achievements))
+	for i, achievement := range dashboard.Achievements {
+		achievements[i] = &Achievement{
+			ID:          achievement.ID,
+			Title:       achievement.Title,
+			Description: achievement.Description,
+			Icon:        achievement.Icon,
+			Rarity:      achievement.Rarity,
+			UnlockedAt:  achievement.UnlockedAt,
+		}
+	}
+
+	weeklyProgress := make([]*DayProgress, len(dashboard.WeeklyProgress))
+	for i, day := range dashboard.WeeklyProgress {
+		weeklyProgress[i] = &DayProgress{
+			Date:          day.Date,
+			CheckedIn:     day.CheckedIn,
+			CravingsCount: day.CravingsCount,
+			SupportGiven:  day.SupportGiven,
+		}
+		if day.MoodScore != 0 {
+			weeklyProgress[i].MoodScore = &day.MoodScore
+		}
+	}
+
+	return &Progress{
+		CurrentStreak:    dashboard.CurrentStreak,
+		LongestStreak:    dashboard.LongestStreak,
+		TotalDaysClean:   dashboard.TotalDaysClean,
+		Milestones:       dashboard.Milestones,
+		CravingsResisted: dashboard.CravingsResisted,
+		TotalCravings:    dashboard.TotalCravings,
+		SupportGiven:     dashboard.SupportGiven,
+		SupportReceived:  dashboard.SupportReceived,
+		Achievements:     achievements,
+		WeeklyProgress:   weeklyProgress,
+	}
+}
+
+type PostConnection struct {
+	Posts      []*Post
+	NextCursor *string
+}
+
+type Post struct {
+	ID            string
+	Type          string
+	Content       string
+	Categories    []string
+	UrgencyLevel  int
+	ResponseCount int
+	SupportCount  int
+	CreatedAt     time.Time
+	CircleID      *string
+	Reactions     []*ReactionCount
+
+	AuthorID string // Resolved to Post.author through the user loader
+}
+
+type ReactionCount struct {
+	Type  string
+	Count int
+}
+
+func NewPost(post *domain.Post) *Post {
+	reactions := make([]*ReactionCount, 0, len(post.ReactionCounts))
+	for _, reaction := range reactionOrder {
+		if count := post.ReactionCounts[reaction]; count > 0 {
+			reactions = append(reactions, &ReactionCount{Type: string(reaction), Count: int(count)})
+		}
+	}
+
+	return &Post{
+		ID:            post.ID.Hex(),
+		Type:          string(post.Type),
+		Content:       post.Content,
+		Categories:    post.Categories,
+		UrgencyLevel:  post.UrgencyLevel,
+		ResponseCount: post.ResponseCount,
+		SupportCount:  post.SupportCount,
+		CreatedAt:     post.CreatedAt,
+		CircleID:      post.CircleID,
+		Reactions:     reactions,
+		AuthorID:      post.UserID,
+	}
+}
+
+func NewPosts(posts []*domain.Post) []*Post {
+	models := make([]*Post, len(posts))
+	for i, post := range posts {
+		models[i] = NewPost(post)
+	}
+	return models
+}
+
+type SupportResponse struct {
+	ID             string
+	Type           string
+	Content        string
+	StrengthPoints int
+	CreatedAt      time.Time
+
+	AuthorID string // Resolved to SupportResponse.author through the user loader
+}
+
+func NewSupportResponse(response *domain.SupportResponse) *SupportResponse {
+	return &SupportResponse{
+		ID:             response.ID.Hex(),
+		Type:           string(response.Type),
+		Content:        response.Content,
+		StrengthPoints: response.StrengthPoints,
+		CreatedAt:      response.CreatedAt,
+		AuthorID:       response.UserID,
+	}
+}
@@ -0,0 +1,38 @@
+//go:build graphql
+
+// Package graph serves a read-only GraphQL view of posts, support responses,
+// users and progress for the web frontend. Code in generated/ comes from
+// schema.graphqls by running gqlgen with gqlgen.yml. The package needs gqlgen
+// and its generated code, so it is only compiled with -tags graphql.
+package graph
+
+import (
+	"github.com/yourorg/anonymous-support/internal/service"
+)
+
+//go:generate go tool gqlgen generate --config ../../gqlgen.yml
+
+// Resolver resolves queries through the same services the Connect handlers use
+type Resolver struct {
+	postService     service.PostServiceInterface
+	supportService  service.SupportServiceInterface
+	userService     service.UserServiceInterface
+	progressService service.ProgressServiceInterface
+	reactionService service.ReactionServiceInterface
+}
+
+func NewResolver(
+	postService service.PostServiceInterface,
+	supportService service.SupportServiceInterface,
+	userService service.UserServiceInterface,
+	progressService service.ProgressServiceInterface,
+	reactionService service.ReactionServiceInterface,
+) *Resolver {
+	return &Resolver{
+		postService:     postService,
+		supportService:  supportService,
+		userService:     userService,
+		progressService: progressService,
+		reactionService: reactionService,
+	}
+}
@@ -0,0 +1,188 @@
+//go:build graphql
+
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/graph/generated"
+	"github.com/yourorg/anonymous-support/internal/graph/model"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/pkg/pagination"
+)
+
+// Page sizes of a post's responses
+const (
+	defaultResponses = 10
+	maxResponses     = 50
+)
+
+// Author is the resolver for the author field.
+func (r *postResolver) Author(ctx context.Context, obj *model.Post) (*model.User, error) {
+	return loadUser(ctx, obj.AuthorID)
+}
+
+// ViewerReaction is the resolver for the viewerReaction field.
+func (r *postResolver) ViewerReaction(ctx context.Context, obj *model.Post) (*string, error) {
+	reaction, found, err := loadersFrom(ctx).reactions.Load(ctx, obj.ID)
+	if err != nil || !found {
+		return nil, err
+	}
+	value := string(reaction)
+	return &value, nil
+}
+
+// Responses is the resolver for the responses field.
+func (r *postResolver) Responses(ctx context.Context, obj *model.Post, first *int) ([]*model.SupportResponse, error) {
+	limit := defaultResponses
+	if first != nil {
+		limit = *first
+	}
+	if limit < 1 || limit > maxResponses {
+		return nil, fmt.Errorf("first must be between 1 and %d", maxResponses)
+	}
+
+	viewerID, _ := middleware.GetUserID(ctx)
+	responses, err := r.supportService.GetResponses(ctx, obj.ID, viewerID, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]*model.SupportResponse, len(responses))
+	for i, response := range responses {
+		models[i] = model.NewSupportResponse(response)
+	}
+	return models, nil
+}
+
+// Viewer is the resolver for the viewer field.
+func (r *queryResolver) Viewer(ctx context.Context) (*model.Viewer, error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, nil
+	}
+	return &model.Viewer{UserID: userID}, nil
+}
+
+// Post is the resolver for the post field.
+func (r *queryResolver) Post(ctx context.Context, id string) (*model.Post, error) {
+	viewerID, _ := middleware.GetUserID(ctx)
+	post, err := r.postService.GetPost(ctx, id, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewPost(post), nil
+}
+
+// Posts is the resolver for the posts field.
+func (r *queryResolver) Posts(ctx context.Context, ids []string) ([]*model.Post, error) {
+	viewerID, _ := middleware.GetUserID(ctx)
+	posts, err := r.postService.BatchGetPosts(ctx, ids, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewPosts(posts), nil
+}
+
+// Feed is the resolver for the feed field.
+func (r *queryResolver) Feed(ctx context.Context, first *int, after *string, categories []string, circleID *string) (*model.PostConnection, error) {
+	limit := pagination.DefaultLimit
+	if first != nil {
+		limit = *first
+	}
+	cursor := ""
+	if after != nil {
+		cursor = *after
+	}
+
+	viewerID, _ := middleware.GetUserID(ctx)
+	page, err := r.postService.GetFeedAfter(ctx, viewerID, categories, circleID, nil, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &model.PostConnection{Posts: model.NewPosts(page.Posts)}
+	if page.NextCursor != "" {
+		connection.NextCursor = &page.NextCursor
+	}
+	return connection, nil
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+	return loadUser(ctx, id)
+}
+
+// Author is the resolver for the author field.
+func (r *supportResponseResolver) Author(ctx context.Context, obj *model.SupportResponse) (*model.User, error) {
+	return loadUser(ctx, obj.AuthorID)
+}
+
+// User is the resolver for the user field.
+func (r *viewerResolver) User(ctx context.Context, obj *model.Viewer) (*model.User, error) {
+	user, err := r.userService.GetProfile(ctx, obj.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewUser(user), nil
+}
+
+// Streak is the resolver for the streak field.
+func (r *viewerResolver) Streak(ctx context.Context, obj *model.Viewer) (*model.Streak, error) {
+	tracker, err := r.userService.GetStreak(ctx, obj.UserID)
+	if err != nil && err.Error() == "tracker not found" {
+		// Users who haven't checked in yet have no streak
+		tracker, err = &domain.UserTracker{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.NewStreak(tracker), nil
+}
+
+// Progress is the resolver for the progress field.
+func (r *viewerResolver) Progress(ctx context.Context, obj *model.Viewer) (*model.Progress, error) {
+	dashboard, err := r.progressService.GetDashboard(ctx, obj.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewProgress(dashboard), nil
+}
+
+// Post returns generated.PostResolver implementation.
+func (r *Resolver) Post() generated.PostResolver { return &postResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+// SupportResponse returns generated.SupportResponseResolver implementation.
+func (r *Resolver) SupportResponse() generated.SupportResponseResolver {
+	return &supportResponseResolver{r}
+}
+
+// Viewer returns generated.ViewerResolver implementation.
+func (r *Resolver) Viewer() generated.ViewerResolver { return &viewerResolver{r} }
+
+type postResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type supportResponseResolver struct{ *Resolver }
+type viewerResolver struct{ *Resolver }
+
+// loadUser returns a user through the request's user loader, or nil for
+// unknown and banned users
+func loadUser(ctx context.Context, userID string) (*model.User, error) {
+	user, found, err := loadersFrom(ctx).users.Load(ctx, userID)
+	if err != nil || !found {
+		return nil, err
+	}
+	return model.NewUser(user), nil
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/pkg/redact"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
@@ -19,6 +21,9 @@ type HealthHandler struct {
 	redis       *redis.Client
 	version     string
 	environment string
+
+	started  atomic.Bool // Set once every dependency has been reachable
+	draining atomic.Bool // Set on shutdown so readiness fails
 }
 
 type HealthResponse struct {
@@ -50,19 +55,7 @@ func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	deps := make(map[string]DependencyHealth)
-
-	// Check PostgreSQL
-	pgStatus := h.checkPostgres(ctx)
-	deps["postgres"] = pgStatus
-
-	// Check MongoDB
-	mongoStatus := h.checkMongo(ctx)
-	deps["mongodb"] = mongoStatus
-
-	// Check Redis
-	redisStatus := h.checkRedis(ctx)
-	deps["redis"] = redisStatus
+	deps := h.checkDependencies(ctx)
 
 	// Determine overall status
 	overallStatus := "healthy"
@@ -88,6 +81,14 @@ func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+func (h *HealthHandler) checkDependencies(ctx context.Context) map[string]DependencyHealth {
+	return map[string]DependencyHealth{
+		"postgres": h.checkPostgres(ctx),
+		"mongodb":  h.checkMongo(ctx),
+		"redis":    h.checkRedis(ctx),
+	}
+}
+
 func (h *HealthHandler) checkPostgres(ctx context.Context) DependencyHealth {
 	start := time.Now()
 	err := h.postgres.PingContext(ctx)
@@ -98,7 +99,7 @@ func (h *HealthHandler) checkPostgres(ctx context.Context) DependencyHealth {
 		return DependencyHealth{
 			Status:       "unhealthy",
 			ResponseTime: duration.String(),
-			Error:        err.Error(),
+			Error:        redact.String(err.Error()),
 		}
 	}
 
@@ -118,7 +119,7 @@ func (h *HealthHandler) checkMongo(ctx context.Context) DependencyHealth {
 		return DependencyHealth{
 			Status:       "unhealthy",
 			ResponseTime: duration.String(),
-			Error:        err.Error(),
+			Error:        redact.String(err.Error()),
 		}
 	}
 
@@ -138,7 +139,7 @@ func (h *HealthHandler) checkRedis(ctx context.Context) DependencyHealth {
 		return DependencyHealth{
 			Status:       "unhealthy",
 			ResponseTime: duration.String(),
-			Error:        err.Error(),
+			Error:        redact.String(err.Error()),
 		}
 	}
 
@@ -148,8 +149,42 @@ func (h *HealthHandler) checkRedis(ctx context.Context) DependencyHealth {
 	}
 }
 
-// Ready returns a simple readiness check (lighter than full health check)
+// StartDraining makes readiness fail so load balancers stop routing new
+// requests to this instance before it shuts down
+func (h *HealthHandler) StartDraining() {
+	h.draining.Store(true)
+}
+
+// Startup is the startup probe: it fails until every dependency has been
+// reachable once, and is not rechecked afterwards
+func (h *HealthHandler) Startup(w http.ResponseWriter, r *http.Request) {
+	if !h.started.Load() {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		for name, dep := range h.checkDependencies(ctx) {
+			if dep.Status != "healthy" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte("waiting for " + name))
+				return
+			}
+		}
+		h.started.Store(true)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+}
+
+// Ready returns a simple readiness check (lighter than full health check).
+// It fails once the instance starts draining.
 func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("draining"))
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("OK"))
 }
@@ -0,0 +1,238 @@
+// Package rest serves REST routes over the Connect services, for clients that
+// can't speak Connect. Each route is translated into a Connect JSON call, so
+// requests go through the same interceptors, and errors come back in the
+// Connect JSON error format.
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/openapi"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// maxBodyBytes bounds REST request bodies
+const maxBodyBytes = 1 << 20
+
+// Route maps a REST method and path onto a unary RPC's Connect procedure.
+// Path wildcards name request fields, e.g. /v1/posts/{post_id}.
+type Route struct {
+	Method    string
+	Pattern   string
+	Procedure string
+}
+
+// Gateway serves routes by calling the RPC handler in-process
+type Gateway struct {
+	rpc    http.Handler
+	routes []Route
+	public map[string]bool
+
+	methods []protoreflect.MethodDescriptor // Set by Register, in route order
+}
+
+// NewGateway creates a gateway over rpcHandler, which serves the Connect
+// procedures. publicProcedures are documented as callable without a token.
+func NewGateway(rpcHandler http.Handler, routes []Route, publicProcedures ...string) *Gateway {
+	public := make(map[string]bool, len(publicProcedures))
+	for _, procedure := range publicProcedures {
+		public[procedure] = true
+	}
+	return &Gateway{
+		rpc:    rpcHandler,
+		routes: routes,
+		public: public,
+	}
+}
+
+// Register adds the routes to mux. Procedures are looked up in the protobuf
+// registry, so their generated packages must be linked in.
+func (g *Gateway) Register(mux *http.ServeMux) error {
+	g.methods = make([]protoreflect.MethodDescriptor, len(g.routes))
+	for i, route := range g.routes {
+		method, err := findMethod(route.Procedure)
+		if err != nil {
+			return fmt.Errorf("route %s %s: %w", route.Method, route.Pattern, err)
+		}
+		if method.IsStreamingClient() || method.IsStreamingServer() {
+			return fmt.Errorf("route %s %s maps to streaming procedure %s", route.Method, route.Pattern, route.Procedure)
+		}
+		fields := method.Input().Fields()
+		for _, name := range openapi.PathParams(route.Pattern) {
+			if fields.ByName(protoreflect.Name(name)) == nil {
+				return fmt.Errorf("route %s %s: %s has no field %s", route.Method, route.Pattern, method.Input().FullName(), name)
+			}
+		}
+		g.methods[i] = method
+		mux.Handle(route.Method+" "+route.Pattern, g.handler(route, method))
+	}
+	return nil
+}
+
+// OpenAPIHandler serves the OpenAPI document for the registered routes
+func (g *Gateway) OpenAPIHandler(info openapi.Info) http.Handler {
+	operations := make([]openapi.Operation, len(g.methods))
+	for i, method := range g.methods {
+		route := g.routes[i]
+		service := method.Parent().Name()
+		operations[i] = openapi.Operation{
+			Method:   route.Method,
+			Path:     route.Pattern,
+			ID:       fmt.Sprintf("%s_%s", service, method.Name()),
+			Tag:      string(service),
+			Public:   g.public[route.Procedure],
+			Request:  method.Input(),
+			Response: method.Output(),
+		}
+	}
+	// The document only changes with the routes, so it's encoded once
+	body, err := json.Marshal(openapi.Generate(info, operations))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+}
+
+func (g *Gateway) handler(route Route, method protoreflect.MethodDescriptor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := requestBody(w, r, route, method)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		call := r.Clone(r.Context())
+		call.Method = http.MethodPost
+		call.URL = &url.URL{Path: route.Procedure}
+		call.RequestURI = route.Procedure
+		call.Body = io.NopCloser(bytes.NewReader(body))
+		call.ContentLength = int64(len(body))
+		call.Header.Set("Content-Type", "application/json")
+		call.Header.Del("Content-Encoding")
+		call.Header.Del("Content-Length")
+		g.rpc.ServeHTTP(w, call)
+	})
+}
+
+// requestBody builds the RPC's JSON request from the REST request's body,
+// query parameters and path. Path values win over the body.
+func requestBody(w http.ResponseWriter, r *http.Request, route Route, method protoreflect.MethodDescriptor) ([]byte, error) {
+	fields := method.Input().Fields()
+	msg := map[string]any{}
+
+	if openapi.HasBody(route.Method) {
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		decoder.UseNumber()
+		if err := decoder.Decode(&msg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("request body must be a JSON object: %w", err)
+		}
+		if msg == nil {
+			msg = map[string]any{}
+		}
+	}
+
+	for key, values := range r.URL.Query() {
+		field := fields.ByJSONName(key)
+		if field == nil {
+			field = fields.ByName(protoreflect.Name(key))
+		}
+		if field == nil || field.IsMap() {
+			return nil, fmt.Errorf("unknown query parameter %q", key)
+		}
+		value, err := fieldValue(field, values)
+		if err != nil {
+			return nil, err
+		}
+		setField(msg, field, value)
+	}
+
+	for _, name := range openapi.PathParams(route.Pattern) {
+		field := fields.ByName(protoreflect.Name(name))
+		value, err := fieldValue(field, []string{r.PathValue(name)})
+		if err != nil {
+			return nil, err
+		}
+		setField(msg, field, value)
+	}
+
+	return json.Marshal(msg)
+}
+
+// setField sets the field by its JSON name, replacing any value the body
+// gave under its proto name
+func setField(msg map[string]any, field protoreflect.FieldDescriptor, value any) {
+	delete(msg, string(field.Name()))
+	msg[field.JSONName()] = value
+}
+
+// fieldValue converts text values to the field's JSON form. Protobuf JSON
+// accepts numbers, enum names and well-known types as strings, so only
+// booleans need converting.
+func fieldValue(field protoreflect.FieldDescriptor, values []string) (any, error) {
+	convert := func(value string) (any, error) {
+		if field.Kind() != protoreflect.BoolKind {
+			return value, nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", field.JSONName())
+		}
+		return b, nil
+	}
+
+	if !field.IsList() {
+		return convert(values[len(values)-1])
+	}
+	list := make([]any, len(values))
+	for i, value := range values {
+		v, err := convert(value)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = v
+	}
+	return list, nil
+}
+
+// writeError writes a request the gateway couldn't translate as a Connect
+// invalid_argument error
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"code":    "invalid_argument",
+		"message": err.Error(),
+	})
+}
+
+// findMethod looks up the method a Connect procedure path, e.g.
+// /post.v1.PostService/GetPost, names
+func findMethod(procedure string) (protoreflect.MethodDescriptor, error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(procedure, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid procedure %q", procedure)
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service + "." + method))
+	if err != nil {
+		return nil, fmt.Errorf("procedure %s: %w", procedure, err)
+	}
+	md, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("procedure %s is not a method", procedure)
+	}
+	return md, nil
+}
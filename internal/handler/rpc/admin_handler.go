@@ -0,0 +1,460 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	adminv1 "github.com/yourorg/anonymous-support/gen/admin/v1"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
+	"github.com/yourorg/anonymous-support/internal/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AdminHandler serves admin.v1. Callers are users with a staff role or
+// service clients; service clients have no user ID, so their actions are
+// audited without an actor.
+type AdminHandler struct {
+	adminService       service.AdminServiceInterface
+	circleService      service.CircleServiceInterface
+	entitlementService service.EntitlementServiceInterface
+	exportService      service.ExportServiceInterface
+	runtimeSettings    service.RuntimeSettingsServiceInterface
+	authorizer         *authz.Authorizer
+}
+
+func NewAdminHandler(
+	adminService service.AdminServiceInterface,
+	circleService service.CircleServiceInterface,
+	entitlementService service.EntitlementServiceInterface,
+	exportService service.ExportServiceInterface,
+	runtimeSettings service.RuntimeSettingsServiceInterface,
+	authorizer *authz.Authorizer,
+) *AdminHandler {
+	return &AdminHandler{
+		adminService:       adminService,
+		circleService:      circleService,
+		entitlementService: entitlementService,
+		exportService:      exportService,
+		runtimeSettings:    runtimeSettings,
+		authorizer:         authorizer,
+	}
+}
+
+func (h *AdminHandler) SearchUsers(
+	ctx context.Context,
+	req *connect.Request[adminv1.SearchUsersRequest],
+) (*connect.Response[adminv1.SearchUsersResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageUsers); err != nil {
+		return nil, err
+	}
+
+	filter := domain.UserSearch{
+		Query:  req.Msg.Query,
+		Banned: req.Msg.Banned,
+		Limit:  int(req.Msg.Limit),
+		Offset: int(req.Msg.Offset),
+	}
+	if req.Msg.Role != nil {
+		role := domain.Role(*req.Msg.Role)
+		filter.Role = &role
+	}
+
+	users, total, err := h.adminService.SearchUsers(ctx, filter)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoUsers := make([]*adminv1.AdminUser, len(users))
+	for i, user := range users {
+		protoUsers[i] = &adminv1.AdminUser{
+			Id:           user.ID.String(),
+			Username:     user.Username,
+			Role:         string(user.Role),
+			IsAnonymous:  user.IsAnonymous,
+			IsBanned:     user.IsBanned,
+			CreatedAt:    timestamppb.New(user.CreatedAt),
+			LastActiveAt: timestamppb.New(user.LastActiveAt),
+		}
+	}
+
+	return connect.NewResponse(&adminv1.SearchUsersResponse{
+		Users:      protoUsers,
+		TotalCount: int32(total), //nolint:gosec // User count won't overflow int32
+	}), nil
+}
+
+func (h *AdminHandler) BanUser(
+	ctx context.Context,
+	req *connect.Request[adminv1.BanUserRequest],
+) (*connect.Response[adminv1.BanUserResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionBanUser); err != nil {
+		return nil, err
+	}
+
+	actorID, actorRole := adminActor(ctx)
+	if err := h.adminService.BanUser(ctx, actorID, actorRole, req.Msg.UserId, req.Msg.Reason); err != nil {
+		return nil, adminError(err)
+	}
+
+	return connect.NewResponse(&adminv1.BanUserResponse{Success: true}), nil
+}
+
+func (h *AdminHandler) UnbanUser(
+	ctx context.Context,
+	req *connect.Request[adminv1.UnbanUserRequest],
+) (*connect.Response[adminv1.UnbanUserResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionUnbanUser); err != nil {
+		return nil, err
+	}
+
+	actorID, actorRole := adminActor(ctx)
+	if err := h.adminService.UnbanUser(ctx, actorID, actorRole, req.Msg.UserId, req.Msg.Reason); err != nil {
+		return nil, adminError(err)
+	}
+
+	return connect.NewResponse(&adminv1.UnbanUserResponse{Success: true}), nil
+}
+
+func (h *AdminHandler) SetUserRole(
+	ctx context.Context,
+	req *connect.Request[adminv1.SetUserRoleRequest],
+) (*connect.Response[adminv1.SetUserRoleResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageUsers); err != nil {
+		return nil, err
+	}
+
+	actorID, _ := adminActor(ctx)
+	if err := h.adminService.SetUserRole(ctx, actorID, req.Msg.UserId, domain.Role(req.Msg.Role)); err != nil {
+		return nil, adminError(err)
+	}
+
+	return connect.NewResponse(&adminv1.SetUserRoleResponse{Success: true}), nil
+}
+
+func (h *AdminHandler) DeleteCircle(
+	ctx context.Context,
+	req *connect.Request[adminv1.DeleteCircleRequest],
+) (*connect.Response[adminv1.DeleteCircleResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageCircle); err != nil {
+		return nil, err
+	}
+
+	actorID, actorRole := adminActor(ctx)
+	if err := h.circleService.DeleteCircle(ctx, actorID, actorRole, req.Msg.CircleId, req.Msg.Reason); err != nil {
+		return nil, circleError(err)
+	}
+
+	return connect.NewResponse(&adminv1.DeleteCircleResponse{Success: true}), nil
+}
+
+func (h *AdminHandler) SetCircleMemberRole(
+	ctx context.Context,
+	req *connect.Request[adminv1.SetCircleMemberRoleRequest],
+) (*connect.Response[adminv1.SetCircleMemberRoleResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageCircle); err != nil {
+		return nil, err
+	}
+
+	actorID, actorRole := adminActor(ctx)
+	err := h.circleService.SetMemberRole(ctx, actorID, actorRole, req.Msg.CircleId, req.Msg.UserId, domain.CircleRole(req.Msg.Role))
+	if err != nil {
+		return nil, circleError(err)
+	}
+
+	return connect.NewResponse(&adminv1.SetCircleMemberRoleResponse{Success: true}), nil
+}
+
+func (h *AdminHandler) RemoveCircleMember(
+	ctx context.Context,
+	req *connect.Request[adminv1.RemoveCircleMemberRequest],
+) (*connect.Response[adminv1.RemoveCircleMemberResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageCircle); err != nil {
+		return nil, err
+	}
+
+	actorID, actorRole := adminActor(ctx)
+	if err := h.circleService.RemoveMember(ctx, actorID, actorRole, req.Msg.CircleId, req.Msg.UserId); err != nil {
+		return nil, circleError(err)
+	}
+
+	return connect.NewResponse(&adminv1.RemoveCircleMemberResponse{Success: true}), nil
+}
+
+func (h *AdminHandler) ListFeatureFlags(
+	ctx context.Context,
+	req *connect.Request[adminv1.ListFeatureFlagsRequest],
+) (*connect.Response[adminv1.ListFeatureFlagsResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageSystem); err != nil {
+		return nil, err
+	}
+
+	flags, err := h.adminService.ListFeatureFlags(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoFlags := make([]*adminv1.FeatureFlag, len(flags))
+	for i, flag := range flags {
+		protoFlags[i] = mapFeatureFlagToProto(flag)
+	}
+
+	return connect.NewResponse(&adminv1.ListFeatureFlagsResponse{Flags: protoFlags}), nil
+}
+
+func (h *AdminHandler) SetFeatureFlag(
+	ctx context.Context,
+	req *connect.Request[adminv1.SetFeatureFlagRequest],
+) (*connect.Response[adminv1.SetFeatureFlagResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageSystem); err != nil {
+		return nil, err
+	}
+
+	actorID, _ := adminActor(ctx)
+	flag, err := h.adminService.SetFeatureFlag(ctx, actorID, req.Msg.Key, req.Msg.Enabled)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&adminv1.SetFeatureFlagResponse{Flag: mapFeatureFlagToProto(flag)}), nil
+}
+
+func (h *AdminHandler) GetSystemStats(
+	ctx context.Context,
+	req *connect.Request[adminv1.GetSystemStatsRequest],
+) (*connect.Response[adminv1.GetSystemStatsResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionViewMetrics); err != nil {
+		return nil, err
+	}
+
+	stats, err := h.adminService.GetSystemStats(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	//nolint:gosec // User, circle and report counts won't overflow int32
+	return connect.NewResponse(&adminv1.GetSystemStatsResponse{
+		GeneratedAt: timestamppb.New(stats.GeneratedAt),
+		TotalUsers:  int32(stats.Users.Total),
+		BannedUsers: int32(stats.Users.Banned),
+		Moderators:  int32(stats.Users.Moderators),
+		Admins:      int32(stats.Users.Admins),
+		Circles:     int32(stats.Circles),
+		OpenReports: int32(stats.OpenReports),
+	}), nil
+}
+
+func (h *AdminHandler) SetUserPlan(
+	ctx context.Context,
+	req *connect.Request[adminv1.SetUserPlanRequest],
+) (*connect.Response[adminv1.SetUserPlanResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageUsers); err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	if req.Msg.ExpiresAt != nil {
+		t := req.Msg.ExpiresAt.AsTime()
+		expiresAt = &t
+	}
+
+	actorID, _ := adminActor(ctx)
+	if _, err := h.entitlementService.SetPlan(ctx, actorID, req.Msg.UserId, domain.Plan(req.Msg.Plan), expiresAt); err != nil {
+		return nil, adminError(err)
+	}
+
+	return connect.NewResponse(&adminv1.SetUserPlanResponse{Success: true}), nil
+}
+
+func (h *AdminHandler) StartDataExport(
+	ctx context.Context,
+	req *connect.Request[adminv1.StartDataExportRequest],
+) (*connect.Response[adminv1.StartDataExportResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageSystem); err != nil {
+		return nil, err
+	}
+
+	actorID, _ := adminActor(ctx)
+	export, err := h.exportService.StartExport(ctx, actorID, service.ExportRequest{
+		Dataset:        domain.ExportDataset(req.Msg.Dataset),
+		Format:         domain.ExportFormat(req.Msg.Format),
+		Since:          req.Msg.Since.AsTime(),
+		Until:          req.Msg.Until.AsTime(),
+		ExcludeColumns: req.Msg.ExcludeColumns,
+	})
+	if err != nil {
+		return nil, exportError(err)
+	}
+
+	return connect.NewResponse(&adminv1.StartDataExportResponse{Export: mapDataExportToProto(export)}), nil
+}
+
+func (h *AdminHandler) GetDataExport(
+	ctx context.Context,
+	req *connect.Request[adminv1.GetDataExportRequest],
+) (*connect.Response[adminv1.GetDataExportResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageSystem); err != nil {
+		return nil, err
+	}
+
+	export, err := h.exportService.GetExport(ctx, req.Msg.ExportId)
+	if err != nil {
+		return nil, exportError(err)
+	}
+
+	return connect.NewResponse(&adminv1.GetDataExportResponse{Export: mapDataExportToProto(export)}), nil
+}
+
+func (h *AdminHandler) ListDataExports(
+	ctx context.Context,
+	req *connect.Request[adminv1.ListDataExportsRequest],
+) (*connect.Response[adminv1.ListDataExportsResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageSystem); err != nil {
+		return nil, err
+	}
+
+	exports, err := h.exportService.ListExports(ctx, int(req.Msg.Limit), int(req.Msg.Offset))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoExports := make([]*adminv1.DataExport, len(exports))
+	for i, export := range exports {
+		protoExports[i] = mapDataExportToProto(export)
+	}
+
+	return connect.NewResponse(&adminv1.ListDataExportsResponse{Exports: protoExports}), nil
+}
+
+func (h *AdminHandler) ListRuntimeSettings(
+	ctx context.Context,
+	req *connect.Request[adminv1.ListRuntimeSettingsRequest],
+) (*connect.Response[adminv1.ListRuntimeSettingsResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageSystem); err != nil {
+		return nil, err
+	}
+
+	settings, err := h.runtimeSettings.List(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoSettings := make([]*adminv1.RuntimeSetting, len(settings))
+	for i, setting := range settings {
+		protoSettings[i] = mapRuntimeSettingToProto(setting)
+	}
+
+	return connect.NewResponse(&adminv1.ListRuntimeSettingsResponse{Settings: protoSettings}), nil
+}
+
+func (h *AdminHandler) SetRuntimeSetting(
+	ctx context.Context,
+	req *connect.Request[adminv1.SetRuntimeSettingRequest],
+) (*connect.Response[adminv1.SetRuntimeSettingResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionManageSystem); err != nil {
+		return nil, err
+	}
+
+	actorID, _ := adminActor(ctx)
+	setting, err := h.runtimeSettings.Set(ctx, actorID, req.Msg.Key, req.Msg.Value)
+	if err != nil {
+		return nil, runtimeSettingError(err)
+	}
+
+	return connect.NewResponse(&adminv1.SetRuntimeSettingResponse{Setting: mapRuntimeSettingToProto(setting)}), nil
+}
+
+// adminActor returns the calling user's ID, empty for service clients, and role
+func adminActor(ctx context.Context) (string, domain.Role) {
+	return middleware.GetUserIDFromContext(ctx), domain.Role(middleware.GetUserRoleFromContext(ctx))
+}
+
+// adminError maps a denied admin action to permission_denied
+func adminError(err error) error {
+	if errors.Is(err, service.ErrAdminActionDenied) {
+		return connect.NewError(connect.CodePermissionDenied, err)
+	}
+	return connect.NewError(connect.CodeInvalidArgument, err)
+}
+
+// exportError maps export failures to not_found or unimplemented, and
+// anything else to invalid_argument
+func exportError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrExportNotFound):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, service.ErrExportFormatUnsupported):
+		return connect.NewError(connect.CodeUnimplemented, err)
+	}
+	return connect.NewError(connect.CodeInvalidArgument, err)
+}
+
+// runtimeSettingError maps an unknown key to not_found and an invalid value to
+// invalid_argument
+func runtimeSettingError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrUnknownRuntimeSetting):
+		return connect.NewError(connect.CodeNotFound, err)
+	case errors.Is(err, service.ErrInvalidRuntimeSetting):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return connect.NewError(connect.CodeInternal, err)
+}
+
+func mapRuntimeSettingToProto(setting *service.RuntimeSettingInfo) *adminv1.RuntimeSetting {
+	protoSetting := &adminv1.RuntimeSetting{
+		Key:          setting.Key,
+		Description:  setting.Description,
+		Value:        setting.Value,
+		DefaultValue: setting.Default,
+		Overridden:   setting.Override != nil,
+	}
+	if setting.Override != nil {
+		if setting.Override.UpdatedBy != nil {
+			protoSetting.UpdatedBy = setting.Override.UpdatedBy.String()
+		}
+		protoSetting.UpdatedAt = timestamppb.New(setting.Override.UpdatedAt)
+	}
+	return protoSetting
+}
+
+func mapDataExportToProto(export *domain.DataExport) *adminv1.DataExport {
+	protoExport := &adminv1.DataExport{
+		Id:        export.ID.String(),
+		Dataset:   string(export.Dataset),
+		Format:    string(export.Format),
+		Since:     timestamppb.New(export.Since),
+		Until:     timestamppb.New(export.Until),
+		Columns:   strings.Split(export.Columns, ","),
+		Status:    string(export.Status),
+		ObjectKey: export.ObjectKey,
+		Rows:      export.Rows,
+		CreatedAt: timestamppb.New(export.CreatedAt),
+	}
+	if export.Error != nil {
+		protoExport.Error = *export.Error
+	}
+	if export.RequestedBy != nil {
+		protoExport.RequestedBy = export.RequestedBy.String()
+	}
+	if export.CompletedAt != nil {
+		protoExport.CompletedAt = timestamppb.New(*export.CompletedAt)
+	}
+	return protoExport
+}
+
+func mapFeatureFlagToProto(flag *domain.FeatureFlag) *adminv1.FeatureFlag {
+	protoFlag := &adminv1.FeatureFlag{
+		Key:       flag.Key,
+		Enabled:   flag.Enabled,
+		UpdatedAt: timestamppb.New(flag.UpdatedAt),
+	}
+	if flag.UpdatedBy != nil {
+		protoFlag.UpdatedBy = flag.UpdatedBy.String()
+	}
+	return protoFlag
+}
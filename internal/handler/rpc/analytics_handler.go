@@ -0,0 +1,95 @@
+package rpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	analyticsv1 "github.com/yourorg/anonymous-support/gen/analytics/v1"
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
+	"github.com/yourorg/anonymous-support/internal/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AnalyticsHandler struct {
+	analyticsService service.AdminAnalyticsServiceInterface
+	communityStats   service.CommunityStatsServiceInterface
+	authorizer       *authz.Authorizer
+}
+
+func NewAnalyticsHandler(analyticsService service.AdminAnalyticsServiceInterface, communityStats service.CommunityStatsServiceInterface, authorizer *authz.Authorizer) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+		communityStats:   communityStats,
+		authorizer:       authorizer,
+	}
+}
+
+func (h *AnalyticsHandler) GetPlatformMetrics(
+	ctx context.Context,
+	req *connect.Request[analyticsv1.GetPlatformMetricsRequest],
+) (*connect.Response[analyticsv1.GetPlatformMetricsResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionViewMetrics); err != nil {
+		return nil, err
+	}
+
+	metrics, err := h.analyticsService.GetPlatformMetrics(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	//nolint:gosec // User and content counts won't overflow int32
+	resp := &analyticsv1.GetPlatformMetricsResponse{
+		GeneratedAt:       timestamppb.New(metrics.GeneratedAt),
+		DailyActiveUsers:  int32(metrics.DailyActiveUsers),
+		WeeklyActiveUsers: int32(metrics.WeeklyActiveUsers),
+		SosResponseTimes: &analyticsv1.SOSResponseTimes{
+			Posts:         int32(metrics.SOSResponseTimes.Posts),
+			Answered:      int32(metrics.SOSResponseTimes.Answered),
+			MedianSeconds: metrics.SOSResponseTimes.MedianSeconds,
+			P90Seconds:    metrics.SOSResponseTimes.P90Seconds,
+		},
+	}
+	for _, cohort := range metrics.RetentionCohorts {
+		resp.RetentionCohorts = append(resp.RetentionCohorts, &analyticsv1.RetentionCohort{
+			WeekStart: timestamppb.New(cohort.WeekStart),
+			Users:     int32(cohort.Users), //nolint:gosec // Cohort sizes won't overflow int32
+			Retained:  cohort.Retained,
+		})
+	}
+	for _, volume := range metrics.ContentVolume {
+		//nolint:gosec // Daily counts won't overflow int32
+		resp.ContentVolume = append(resp.ContentVolume, &analyticsv1.ContentVolume{
+			Day:       timestamppb.New(volume.Day),
+			Posts:     int32(volume.Posts),
+			Responses: int32(volume.Responses),
+		})
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// GetCommunityStats is public; it only returns aggregates that meet the k-anonymity threshold
+func (h *AnalyticsHandler) GetCommunityStats(
+	ctx context.Context,
+	req *connect.Request[analyticsv1.GetCommunityStatsRequest],
+) (*connect.Response[analyticsv1.GetCommunityStatsResponse], error) {
+	stats, err := h.communityStats.GetCommunityStats(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &analyticsv1.GetCommunityStatsResponse{
+		TotalDaysClean: stats.TotalDaysClean,
+		GeneratedAt:    timestamppb.New(stats.GeneratedAt),
+	}
+	if stats.TotalMembers != nil {
+		members := int32(*stats.TotalMembers) //nolint:gosec // Member count won't overflow int32
+		resp.TotalMembers = &members
+	}
+	if stats.SupportsGivenToday != nil {
+		supports := int32(*stats.SupportsGivenToday) //nolint:gosec // Daily count won't overflow int32
+		resp.SupportsGivenToday = &supports
+	}
+
+	return connect.NewResponse(resp), nil
+}
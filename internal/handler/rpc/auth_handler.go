@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"errors"
 
 	"connectrpc.com/connect"
 	"github.com/google/uuid"
@@ -86,14 +87,40 @@ func (h *AuthHandler) Login(
 		return nil, connect.NewError(connect.CodeUnauthenticated, err)
 	}
 
-	res := connect.NewResponse(&authv1.LoginResponse{
+	return connect.NewResponse(loginResponse(authResp)), nil
+}
+
+func (h *AuthHandler) VerifyLoginChallenge(
+	ctx context.Context,
+	req *connect.Request[authv1.VerifyLoginChallengeRequest],
+) (*connect.Response[authv1.LoginResponse], error) {
+	if req.Msg.ChallengeId == "" || req.Msg.Code == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("challenge_id and code are required"))
+	}
+
+	authResp, err := h.authService.VerifyLoginChallenge(ctx, req.Msg.ChallengeId, req.Msg.Code)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+	}
+
+	return connect.NewResponse(loginResponse(authResp)), nil
+}
+
+// loginResponse converts a login result, which carries either tokens or a
+// step-up challenge
+func loginResponse(authResp *dto.AuthResponse) *authv1.LoginResponse {
+	if authResp.StepUpRequired {
+		return &authv1.LoginResponse{
+			StepUpRequired: true,
+			ChallengeId:    authResp.ChallengeID,
+		}
+	}
+	return &authv1.LoginResponse{
 		UserId:       authResp.User.ID,
 		Username:     authResp.User.Username,
 		AccessToken:  authResp.AccessToken,
 		RefreshToken: authResp.RefreshToken,
-	})
-
-	return res, nil
+	}
 }
 
 func (h *AuthHandler) RefreshToken(
@@ -2,22 +2,30 @@ package rpc
 
 import (
 	"context"
+	"errors"
 
 	"connectrpc.com/connect"
+	"github.com/google/uuid"
 	circlev1 "github.com/yourorg/anonymous-support/gen/circle/v1"
 	postv1 "github.com/yourorg/anonymous-support/gen/post/v1"
+	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
 	"github.com/yourorg/anonymous-support/internal/service"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type CircleHandler struct {
 	circleService service.CircleServiceInterface
+	userService   service.UserServiceInterface
+	authorizer    *authz.Authorizer
 }
 
-func NewCircleHandler(circleService service.CircleServiceInterface) *CircleHandler {
+func NewCircleHandler(circleService service.CircleServiceInterface, userService service.UserServiceInterface, authorizer *authz.Authorizer) *CircleHandler {
 	return &CircleHandler{
 		circleService: circleService,
+		userService:   userService,
+		authorizer:    authorizer,
 	}
 }
 
@@ -29,6 +37,9 @@ func (h *CircleHandler) CreateCircle(
 	if !ok {
 		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
 	}
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionCreateCircle); err != nil {
+		return nil, err
+	}
 
 	circleID, err := h.circleService.CreateCircle(
 		ctx,
@@ -40,7 +51,7 @@ func (h *CircleHandler) CreateCircle(
 		req.Msg.IsPrivate,
 	)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, circleError(err)
 	}
 
 	res := connect.NewResponse(&circlev1.CreateCircleResponse{
@@ -58,6 +69,9 @@ func (h *CircleHandler) JoinCircle(
 	if !ok {
 		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
 	}
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionJoinCircle); err != nil {
+		return nil, err
+	}
 
 	err := h.circleService.JoinCircle(ctx, userID, req.Msg.CircleId)
 	if err != nil {
@@ -79,6 +93,9 @@ func (h *CircleHandler) LeaveCircle(
 	if !ok {
 		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
 	}
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionLeaveCircle); err != nil {
+		return nil, err
+	}
 
 	err := h.circleService.LeaveCircle(ctx, userID, req.Msg.CircleId)
 	if err != nil {
@@ -106,14 +123,27 @@ func (h *CircleHandler) GetCircleMembers(
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
+	// Look up every member's name and avatar at once rather than per member
+	memberIDs := make([]uuid.UUID, len(members))
+	for i, member := range members {
+		memberIDs[i] = member.UserID
+	}
+	summaries, err := h.userService.GetUserSummaries(ctx, memberIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
 	protoMembers := make([]*circlev1.CircleMember, len(members))
 	for i, member := range members {
 		protoMembers[i] = &circlev1.CircleMember{
 			UserId:   member.UserID.String(),
-			Username: "", // TODO: fetch username
-			AvatarId: 0,  // TODO: fetch avatar
 			JoinedAt: timestamppb.New(member.JoinedAt),
 			Role:     member.Role,
+			Online:   member.Online,
+		}
+		if summary, ok := summaries[member.UserID]; ok {
+			protoMembers[i].Username = summary.Username
+			protoMembers[i].AvatarId = int32(summary.AvatarID) //nolint:gosec // Avatar IDs are small
 		}
 	}
 
@@ -156,6 +186,11 @@ func (h *CircleHandler) GetCircles(
 	ctx context.Context,
 	req *connect.Request[circlev1.GetCirclesRequest],
 ) (*connect.Response[circlev1.GetCirclesResponse], error) {
+	mask, err := readMask(req.Msg.ReadMask, &circlev1.Circle{})
+	if err != nil {
+		return nil, err
+	}
+
 	var category *string
 	if req.Msg.Category != nil {
 		category = req.Msg.Category
@@ -183,6 +218,7 @@ func (h *CircleHandler) GetCircles(
 			IsPrivate:   circle.IsPrivate,
 			CreatedAt:   timestamppb.New(circle.CreatedAt),
 		}
+		mask.Apply(protoCircles[i])
 	}
 
 	res := connect.NewResponse(&circlev1.GetCirclesResponse{
@@ -192,3 +228,47 @@ func (h *CircleHandler) GetCircles(
 
 	return res, nil
 }
+
+func (h *CircleHandler) SetMemberRole(
+	ctx context.Context,
+	req *connect.Request[circlev1.SetMemberRoleRequest],
+) (*connect.Response[circlev1.SetMemberRoleResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	role := domain.Role(middleware.GetUserRoleFromContext(ctx))
+	err := h.circleService.SetMemberRole(ctx, userID, role, req.Msg.CircleId, req.Msg.UserId, domain.CircleRole(req.Msg.Role))
+	if err != nil {
+		return nil, circleError(err)
+	}
+
+	return connect.NewResponse(&circlev1.SetMemberRoleResponse{Success: true}), nil
+}
+
+func (h *CircleHandler) RemoveMember(
+	ctx context.Context,
+	req *connect.Request[circlev1.RemoveMemberRequest],
+) (*connect.Response[circlev1.RemoveMemberResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	role := domain.Role(middleware.GetUserRoleFromContext(ctx))
+	if err := h.circleService.RemoveMember(ctx, userID, role, req.Msg.CircleId, req.Msg.UserId); err != nil {
+		return nil, circleError(err)
+	}
+
+	return connect.NewResponse(&circlev1.RemoveMemberResponse{Success: true}), nil
+}
+
+// circleError maps a denied circle-scoped permission, or a plan without the
+// needed entitlement, to permission_denied
+func circleError(err error) error {
+	if errors.Is(err, service.ErrCirclePermissionDenied) || errors.Is(err, service.ErrEntitlementRequired) {
+		return connect.NewError(connect.CodePermissionDenied, err)
+	}
+	return connect.NewError(connect.CodeInvalidArgument, err)
+}
@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	homev1 "github.com/yourorg/anonymous-support/gen/home/v1"
+	notificationv1 "github.com/yourorg/anonymous-support/gen/notification/v1"
+	postv1 "github.com/yourorg/anonymous-support/gen/post/v1"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type HomeHandler struct {
+	homeService service.HomeServiceInterface
+}
+
+func NewHomeHandler(homeService service.HomeServiceInterface) *HomeHandler {
+	return &HomeHandler{
+		homeService: homeService,
+	}
+}
+
+func (h *HomeHandler) GetHomeScreen(
+	ctx context.Context,
+	req *connect.Request[homev1.GetHomeScreenRequest],
+) (*connect.Response[homev1.GetHomeScreenResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	home, err := h.homeService.GetHomeScreen(ctx, userID, int(req.Msg.FeedLimit), int(req.Msg.NotificationLimit))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	feed := make([]*postv1.Post, len(home.Feed.Posts))
+	for i, post := range home.Feed.Posts {
+		feed[i] = mapDomainPostToProto(post)
+	}
+
+	notifications := make([]*notificationv1.Notification, len(home.Notifications))
+	for i, notification := range home.Notifications {
+		notifications[i] = mapDomainNotificationToProto(notification)
+	}
+
+	streak := &homev1.Streak{
+		StreakDays:       int32(home.Streak.StreakDays),
+		TotalCravings:    int32(home.Streak.TotalCravings),
+		CravingsResisted: int32(home.Streak.CravingsResisted),
+	}
+	if home.Streak.LastRelapseDate != nil {
+		streak.LastRelapseDate = timestamppb.New(*home.Streak.LastRelapseDate)
+	}
+
+	return connect.NewResponse(&homev1.GetHomeScreenResponse{
+		Feed:                feed,
+		UnreadNotifications: notifications,
+		UnreadCount:         home.UnreadCount,
+		Streak:              streak,
+	}), nil
+}
@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	journalv1 "github.com/yourorg/anonymous-support/gen/journal/v1"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type JournalHandler struct {
+	journalService service.JournalServiceInterface
+}
+
+func NewJournalHandler(journalService service.JournalServiceInterface) *JournalHandler {
+	return &JournalHandler{
+		journalService: journalService,
+	}
+}
+
+func (h *JournalHandler) CreateJournalEntry(
+	ctx context.Context,
+	req *connect.Request[journalv1.CreateJournalEntryRequest],
+) (*connect.Response[journalv1.CreateJournalEntryResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	var moodScore *int
+	if req.Msg.MoodScore != nil {
+		score := int(*req.Msg.MoodScore)
+		moodScore = &score
+	}
+
+	triggers := make([]domain.RelapseTrigger, len(req.Msg.Triggers))
+	for i, trigger := range req.Msg.Triggers {
+		triggers[i] = domain.RelapseTrigger(trigger)
+	}
+
+	entry, err := h.journalService.CreateEntry(ctx, userID, req.Msg.Content, moodScore, triggers)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	res := connect.NewResponse(&journalv1.CreateJournalEntryResponse{
+		Entry: mapDomainJournalEntryToProto(entry),
+	})
+
+	return res, nil
+}
+
+func (h *JournalHandler) ListJournalEntries(
+	ctx context.Context,
+	req *connect.Request[journalv1.ListJournalEntriesRequest],
+) (*connect.Response[journalv1.ListJournalEntriesResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	limit := int(req.Msg.Limit)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	entries, err := h.journalService.ListEntries(ctx, userID, req.Msg.Query, limit, int(req.Msg.Offset))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	protoEntries := make([]*journalv1.JournalEntry, len(entries))
+	for i, entry := range entries {
+		protoEntries[i] = mapDomainJournalEntryToProto(entry)
+	}
+
+	res := connect.NewResponse(&journalv1.ListJournalEntriesResponse{
+		Entries: protoEntries,
+	})
+
+	return res, nil
+}
+
+func mapDomainJournalEntryToProto(entry *domain.JournalEntry) *journalv1.JournalEntry {
+	protoEntry := &journalv1.JournalEntry{
+		Id:        entry.ID.Hex(),
+		Content:   entry.Content,
+		Triggers:  make([]string, len(entry.Triggers)),
+		CreatedAt: timestamppb.New(entry.CreatedAt),
+	}
+	if entry.MoodScore != nil {
+		score := int32(*entry.MoodScore)
+		protoEntry.MoodScore = &score
+	}
+	for i, trigger := range entry.Triggers {
+		protoEntry.Triggers[i] = string(trigger)
+	}
+	return protoEntry
+}
@@ -2,22 +2,26 @@ package rpc
 
 import (
 	"context"
+	"time"
 
 	"connectrpc.com/connect"
 	moderationv1 "github.com/yourorg/anonymous-support/gen/moderation/v1"
 	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
 	"github.com/yourorg/anonymous-support/internal/service"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type ModerationHandler struct {
 	moderationService service.ModerationServiceInterface
+	authorizer        *authz.Authorizer
 }
 
-func NewModerationHandler(moderationService service.ModerationServiceInterface) *ModerationHandler {
+func NewModerationHandler(moderationService service.ModerationServiceInterface, authorizer *authz.Authorizer) *ModerationHandler {
 	return &ModerationHandler{
 		moderationService: moderationService,
+		authorizer:        authorizer,
 	}
 }
 
@@ -53,10 +57,8 @@ func (h *ModerationHandler) GetReports(
 	ctx context.Context,
 	req *connect.Request[moderationv1.GetReportsRequest],
 ) (*connect.Response[moderationv1.GetReportsResponse], error) {
-	// RBAC: Require moderator or higher
-	role := middleware.GetUserRoleFromContext(ctx)
-	if !hasPermission(domain.Role(role), domain.RoleModerator) {
-		return nil, connect.NewError(connect.CodePermissionDenied, nil)
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionViewReports); err != nil {
+		return nil, err
 	}
 
 	var status *string
@@ -76,16 +78,7 @@ func (h *ModerationHandler) GetReports(
 
 	protoReports := make([]*moderationv1.Report, len(reports))
 	for i, report := range reports {
-		protoReports[i] = &moderationv1.Report{
-			Id:          report.ID.String(),
-			ReporterId:  report.ReporterID.String(),
-			ContentType: report.ContentType,
-			ContentId:   report.ContentID,
-			Reason:      report.Reason,
-			Description: report.Description,
-			Status:      report.Status,
-			CreatedAt:   timestamppb.New(report.CreatedAt),
-		}
+		protoReports[i] = mapDomainReportToProto(report)
 	}
 
 	res := connect.NewResponse(&moderationv1.GetReportsResponse{
@@ -105,10 +98,8 @@ func (h *ModerationHandler) ModerateContent(
 		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
 	}
 
-	// RBAC: Require moderator or higher
-	role := middleware.GetUserRoleFromContext(ctx)
-	if !hasPermission(domain.Role(role), domain.RoleModerator) {
-		return nil, connect.NewError(connect.CodePermissionDenied, nil)
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionModerateContent); err != nil {
+		return nil, err
 	}
 
 	err := h.moderationService.ModerateContent(
@@ -128,16 +119,134 @@ func (h *ModerationHandler) ModerateContent(
 	return res, nil
 }
 
-// hasPermission checks if user role has permission for required role
-func hasPermission(userRole, requiredRole domain.Role) bool {
-	roleHierarchy := map[domain.Role]int{
-		domain.RoleUser:      1,
-		domain.RoleModerator: 2,
-		domain.RoleAdmin:     3,
+func (h *ModerationHandler) GetReport(
+	ctx context.Context,
+	req *connect.Request[moderationv1.GetReportRequest],
+) (*connect.Response[moderationv1.GetReportResponse], error) {
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionViewReports); err != nil {
+		return nil, err
+	}
+
+	report, notes, err := h.moderationService.GetReport(ctx, req.Msg.ReportId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	protoNotes := make([]*moderationv1.ModeratorNote, len(notes))
+	for i, note := range notes {
+		protoNotes[i] = mapDomainNoteToProto(note)
+	}
+
+	res := connect.NewResponse(&moderationv1.GetReportResponse{
+		Report: mapDomainReportToProto(report),
+		Notes:  protoNotes,
+	})
+
+	return res, nil
+}
+
+func (h *ModerationHandler) AddModeratorNote(
+	ctx context.Context,
+	req *connect.Request[moderationv1.AddModeratorNoteRequest],
+) (*connect.Response[moderationv1.AddModeratorNoteResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
 	}
 
-	userLevel := roleHierarchy[userRole]
-	requiredLevel := roleHierarchy[requiredRole]
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionModerateContent); err != nil {
+		return nil, err
+	}
 
-	return userLevel >= requiredLevel
+	note, err := h.moderationService.AddNote(ctx, req.Msg.ReportId, userID, req.Msg.Body, req.Msg.ParentId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	res := connect.NewResponse(&moderationv1.AddModeratorNoteResponse{
+		Note: mapDomainNoteToProto(note),
+	})
+
+	return res, nil
+}
+
+func (h *ModerationHandler) GetModerationStats(
+	ctx context.Context,
+	req *connect.Request[moderationv1.GetModerationStatsRequest],
+) (*connect.Response[moderationv1.GetModerationStatsResponse], error) {
+	// The dashboard is for admins
+	if err := requirePermission(ctx, h.authorizer, authz.PermissionViewMetrics); err != nil {
+		return nil, err
+	}
+
+	stats, err := h.moderationService.GetStats(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	now := time.Now()
+	resp := &moderationv1.GetModerationStatsResponse{
+		Severities: make([]*moderationv1.SeverityStats, len(stats)),
+	}
+	for i, stat := range stats {
+		//nolint:gosec // Report counts won't overflow int32
+		resp.Severities[i] = &moderationv1.SeverityStats{
+			Severity:             stat.Severity,
+			OpenCount:            int32(stat.OpenCount),
+			OldestOpenAgeSeconds: int64(stat.OldestOpenAge(now).Seconds()),
+			ResolvedCount:        int32(stat.ResolvedCount),
+			AvgResolutionSeconds: stat.AvgResolutionSeconds,
+			SlaSeconds:           int64(stat.SLA.Seconds()),
+			SlaBreaches:          int32(stat.SLABreaches),
+		}
+		resp.TotalOpen += int32(stat.OpenCount)          //nolint:gosec // Report counts won't overflow int32
+		resp.TotalSlaBreaches += int32(stat.SLABreaches) //nolint:gosec // Report counts won't overflow int32
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func mapDomainReportToProto(report *domain.ContentReport) *moderationv1.Report {
+	var reporterID string
+	if report.ReporterID != nil {
+		reporterID = report.ReporterID.String()
+	}
+
+	return &moderationv1.Report{
+		Id:            report.ID.String(),
+		ReporterId:    reporterID,
+		ContentType:   report.ContentType,
+		ContentId:     report.ContentID,
+		Reason:        report.Reason,
+		Description:   report.Description,
+		Status:        report.Status,
+		CreatedAt:     timestamppb.New(report.CreatedAt),
+		ReporterCount: int32(report.ReporterCount), //nolint:gosec // Reporter count won't overflow int32
+		Severity:      report.Severity,
+	}
+}
+
+func mapDomainNoteToProto(note *domain.ModeratorNote) *moderationv1.ModeratorNote {
+	protoNote := &moderationv1.ModeratorNote{
+		Id:        note.ID.String(),
+		ReportId:  note.ReportID.String(),
+		AuthorId:  note.AuthorID.String(),
+		Body:      note.Body,
+		CreatedAt: timestamppb.New(note.CreatedAt),
+	}
+	if note.ParentID != nil {
+		parentID := note.ParentID.String()
+		protoNote.ParentId = &parentID
+	}
+	return protoNote
+}
+
+// requirePermission fails with PermissionDenied unless the caller's role, taken
+// from their access token or service principal, grants permission
+func requirePermission(ctx context.Context, authorizer *authz.Authorizer, permission authz.Permission) error {
+	role := domain.Role(middleware.GetUserRoleFromContext(ctx))
+	if err := authorizer.RequirePermission(role, permission); err != nil {
+		return connect.NewError(connect.CodePermissionDenied, err)
+	}
+	return nil
 }
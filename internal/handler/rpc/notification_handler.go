@@ -0,0 +1,283 @@
+package rpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	notificationv1 "github.com/yourorg/anonymous-support/gen/notification/v1"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationServiceInterface
+	sosService          service.SOSServiceInterface
+}
+
+func NewNotificationHandler(notificationService service.NotificationServiceInterface, sosService service.SOSServiceInterface) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		sosService:          sosService,
+	}
+}
+
+func (h *NotificationHandler) ListNotifications(
+	ctx context.Context,
+	req *connect.Request[notificationv1.ListNotificationsRequest],
+) (*connect.Response[notificationv1.ListNotificationsResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	limit := int(req.Msg.Limit)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	notifications, unread, err := h.notificationService.ListNotifications(ctx, userID, req.Msg.UnreadOnly, limit, int(req.Msg.Offset))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoNotifications := make([]*notificationv1.Notification, len(notifications))
+	for i, notification := range notifications {
+		protoNotifications[i] = mapDomainNotificationToProto(notification)
+	}
+
+	res := connect.NewResponse(&notificationv1.ListNotificationsResponse{
+		Notifications: protoNotifications,
+		UnreadCount:   unread,
+	})
+
+	return res, nil
+}
+
+func (h *NotificationHandler) MarkRead(
+	ctx context.Context,
+	req *connect.Request[notificationv1.MarkReadRequest],
+) (*connect.Response[notificationv1.MarkReadResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	updated, err := h.notificationService.MarkRead(ctx, userID, req.Msg.NotificationIds)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	res := connect.NewResponse(&notificationv1.MarkReadResponse{
+		UpdatedCount: updated,
+	})
+
+	return res, nil
+}
+
+func (h *NotificationHandler) MarkAllRead(
+	ctx context.Context,
+	req *connect.Request[notificationv1.MarkAllReadRequest],
+) (*connect.Response[notificationv1.MarkAllReadResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	updated, err := h.notificationService.MarkAllRead(ctx, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := connect.NewResponse(&notificationv1.MarkAllReadResponse{
+		UpdatedCount: updated,
+	})
+
+	return res, nil
+}
+
+func (h *NotificationHandler) GetUnreadCount(
+	ctx context.Context,
+	req *connect.Request[notificationv1.GetUnreadCountRequest],
+) (*connect.Response[notificationv1.GetUnreadCountResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	unread, err := h.notificationService.GetUnreadCount(ctx, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := connect.NewResponse(&notificationv1.GetUnreadCountResponse{
+		UnreadCount: unread,
+	})
+
+	return res, nil
+}
+
+func mapDomainNotificationToProto(notification *domain.Notification) *notificationv1.Notification {
+	return &notificationv1.Notification{
+		Id:        notification.ID.Hex(),
+		Type:      string(notification.Type),
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Data:      notification.Data,
+		Read:      notification.IsRead(),
+		CreatedAt: timestamppb.New(notification.CreatedAt),
+	}
+}
+
+func (h *NotificationHandler) GetPreferences(
+	ctx context.Context,
+	req *connect.Request[notificationv1.GetPreferencesRequest],
+) (*connect.Response[notificationv1.GetPreferencesResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	prefs, err := h.notificationService.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := connect.NewResponse(&notificationv1.GetPreferencesResponse{
+		Preferences: mapDomainPreferencesToProto(prefs),
+	})
+
+	return res, nil
+}
+
+func (h *NotificationHandler) UpdatePreferences(
+	ctx context.Context,
+	req *connect.Request[notificationv1.UpdatePreferencesRequest],
+) (*connect.Response[notificationv1.UpdatePreferencesResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnauthenticated, err)
+	}
+
+	msg := req.Msg.GetPreferences()
+	prefs := domain.DefaultNotificationPreferences(uid)
+	if msg.GetTimezone() != "" {
+		prefs.Timezone = msg.GetTimezone()
+	}
+	if quiet := msg.GetQuietHours(); quiet != nil {
+		prefs.QuietHoursEnabled = quiet.Enabled
+		if quiet.Start != "" {
+			prefs.QuietHoursStart = quiet.Start
+		}
+		if quiet.End != "" {
+			prefs.QuietHoursEnd = quiet.End
+		}
+	}
+	if reminder := msg.GetCheckInReminder(); reminder != nil {
+		prefs.CheckInReminderEnabled = reminder.Enabled
+		if reminder.Time != "" {
+			prefs.CheckInReminderTime = reminder.Time
+		}
+	}
+	for notificationType, channel := range msg.GetChannels() {
+		switch channel {
+		case notificationv1.Channel_CHANNEL_PUSH:
+			prefs.Channels[domain.NotificationType(notificationType)] = domain.NotificationChannelPush
+		case notificationv1.Channel_CHANNEL_IN_APP:
+			prefs.Channels[domain.NotificationType(notificationType)] = domain.NotificationChannelInApp
+		case notificationv1.Channel_CHANNEL_OFF:
+			prefs.Channels[domain.NotificationType(notificationType)] = domain.NotificationChannelOff
+		case notificationv1.Channel_CHANNEL_EMAIL:
+			prefs.Channels[domain.NotificationType(notificationType)] = domain.NotificationChannelEmail
+		}
+	}
+
+	if err := h.notificationService.UpdatePreferences(ctx, prefs); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	res := connect.NewResponse(&notificationv1.UpdatePreferencesResponse{
+		Preferences: mapDomainPreferencesToProto(prefs),
+	})
+
+	return res, nil
+}
+
+func (h *NotificationHandler) GetHelpCategories(
+	ctx context.Context,
+	req *connect.Request[notificationv1.GetHelpCategoriesRequest],
+) (*connect.Response[notificationv1.GetHelpCategoriesResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	categories, err := h.sosService.GetSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := connect.NewResponse(&notificationv1.GetHelpCategoriesResponse{
+		Categories: categories,
+	})
+
+	return res, nil
+}
+
+func (h *NotificationHandler) UpdateHelpCategories(
+	ctx context.Context,
+	req *connect.Request[notificationv1.UpdateHelpCategoriesRequest],
+) (*connect.Response[notificationv1.UpdateHelpCategoriesResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	categories, err := h.sosService.UpdateSubscriptions(ctx, userID, req.Msg.Categories)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	res := connect.NewResponse(&notificationv1.UpdateHelpCategoriesResponse{
+		Categories: categories,
+	})
+
+	return res, nil
+}
+
+func mapDomainPreferencesToProto(prefs *domain.NotificationPreferences) *notificationv1.NotificationPreferences {
+	channels := make(map[string]notificationv1.Channel, len(domain.DefaultNotificationChannels))
+	for notificationType := range domain.DefaultNotificationChannels {
+		switch prefs.ChannelFor(notificationType) {
+		case domain.NotificationChannelPush:
+			channels[string(notificationType)] = notificationv1.Channel_CHANNEL_PUSH
+		case domain.NotificationChannelInApp:
+			channels[string(notificationType)] = notificationv1.Channel_CHANNEL_IN_APP
+		case domain.NotificationChannelOff:
+			channels[string(notificationType)] = notificationv1.Channel_CHANNEL_OFF
+		case domain.NotificationChannelEmail:
+			channels[string(notificationType)] = notificationv1.Channel_CHANNEL_EMAIL
+		}
+	}
+
+	return &notificationv1.NotificationPreferences{
+		Channels: channels,
+		QuietHours: &notificationv1.QuietHours{
+			Enabled: prefs.QuietHoursEnabled,
+			Start:   prefs.QuietHoursStart,
+			End:     prefs.QuietHoursEnd,
+		},
+		Timezone: prefs.Timezone,
+		CheckInReminder: &notificationv1.CheckInReminder{
+			Enabled: prefs.CheckInReminderEnabled,
+			Time:    prefs.CheckInReminderTime,
+		},
+	}
+}
@@ -5,18 +5,27 @@ import (
 
 	"connectrpc.com/connect"
 	postv1 "github.com/yourorg/anonymous-support/gen/post/v1"
+	postv2 "github.com/yourorg/anonymous-support/gen/post/v2"
 	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/pkg/fieldmask"
 	"github.com/yourorg/anonymous-support/internal/service"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// PostHandler serves the deprecated post.v1. Creating, reading and deleting
+// posts is adapted onto post.v2; the offset feed, batch reads, streaming and
+// urgency updates have no v2 counterpart and call the service directly.
 type PostHandler struct {
+	v2          *PostHandlerV2
 	postService service.PostServiceInterface
 }
 
-func NewPostHandler(postService service.PostServiceInterface) *PostHandler {
+func NewPostHandler(v2 *PostHandlerV2, postService service.PostServiceInterface) *PostHandler {
 	return &PostHandler{
+		v2:          v2,
 		postService: postService,
 	}
 }
@@ -25,69 +34,82 @@ func (h *PostHandler) CreatePost(
 	ctx context.Context,
 	req *connect.Request[postv1.CreatePostRequest],
 ) (*connect.Response[postv1.CreatePostResponse], error) {
-	userID, ok := middleware.GetUserID(ctx)
-	if !ok {
-		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
-	}
-
-	username, ok := middleware.GetUsername(ctx)
-	if !ok {
-		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	res, err := h.v2.CreatePost(ctx, connect.NewRequest(&postv2.CreatePostRequest{
+		Type:             postv2.PostType(req.Msg.Type),
+		Content:          req.Msg.Content,
+		Categories:       req.Msg.Categories,
+		UrgencyLevel:     req.Msg.UrgencyLevel,
+		TimeContext:      req.Msg.TimeContext,
+		DaysSinceRelapse: req.Msg.DaysSinceRelapse,
+		Tags:             req.Msg.Tags,
+		Visibility:       req.Msg.Visibility,
+		CircleId:         req.Msg.CircleId,
+	}))
+	if err != nil {
+		return nil, err
 	}
 
-	postType := mapProtoPostTypeToDomain(req.Msg.Type)
+	return connect.NewResponse(&postv1.CreatePostResponse{
+		PostId:    res.Msg.Post.Id,
+		CreatedAt: res.Msg.Post.CreatedAt,
+	}), nil
+}
 
-	var circleID *string
-	if req.Msg.CircleId != nil {
-		circleID = req.Msg.CircleId
+func (h *PostHandler) GetPost(
+	ctx context.Context,
+	req *connect.Request[postv1.GetPostRequest],
+) (*connect.Response[postv1.GetPostResponse], error) {
+	mask, err := readMask(req.Msg.ReadMask, &postv1.Post{})
+	if err != nil {
+		return nil, err
 	}
 
-	post, err := h.postService.CreatePost(
-		ctx,
-		userID,
-		username,
-		postType,
-		req.Msg.Content,
-		req.Msg.Categories,
-		int(req.Msg.UrgencyLevel),
-		req.Msg.TimeContext,
-		int(req.Msg.DaysSinceRelapse),
-		req.Msg.Tags,
-		req.Msg.Visibility,
-		circleID,
-	)
+	v2, err := h.v2.GetPost(ctx, connect.NewRequest(&postv2.GetPostRequest{PostId: req.Msg.PostId}))
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, err
 	}
 
-	res := connect.NewResponse(&postv1.CreatePostResponse{
-		PostId:    post.ID.Hex(),
-		CreatedAt: timestamppb.New(post.CreatedAt),
+	protoPost := mapV2PostToV1(v2.Msg.Post)
+	mask.Apply(protoPost)
+
+	res := connect.NewResponse(&postv1.GetPostResponse{
+		Post: protoPost,
 	})
 
 	return res, nil
 }
 
-func (h *PostHandler) GetPost(
+func (h *PostHandler) BatchGetPosts(
 	ctx context.Context,
-	req *connect.Request[postv1.GetPostRequest],
-) (*connect.Response[postv1.GetPostResponse], error) {
-	post, err := h.postService.GetPost(ctx, req.Msg.PostId)
+	req *connect.Request[postv1.BatchGetPostsRequest],
+) (*connect.Response[postv1.BatchGetPostsResponse], error) {
+	viewerID, _ := middleware.GetUserID(ctx)
+
+	posts, err := h.postService.BatchGetPosts(ctx, req.Msg.PostIds, viewerID)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeNotFound, err)
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
-	res := connect.NewResponse(&postv1.GetPostResponse{
-		Post: mapDomainPostToProto(post),
-	})
+	protoPosts := make([]*postv1.Post, len(posts))
+	for i, post := range posts {
+		protoPosts[i] = mapDomainPostToProto(post)
+		if banner := post.ModerationBanner(); banner != "" {
+			protoPosts[i].ModerationBanner = &banner
+		}
+	}
 
-	return res, nil
+	return connect.NewResponse(&postv1.BatchGetPostsResponse{Posts: protoPosts}), nil
 }
 
 func (h *PostHandler) GetFeed(
 	ctx context.Context,
 	req *connect.Request[postv1.GetFeedRequest],
 ) (*connect.Response[postv1.GetFeedResponse], error) {
+	mask, err := readMask(req.Msg.ReadMask, &postv1.Post{})
+	if err != nil {
+		return nil, err
+	}
+
 	var circleID *string
 	if req.Msg.CircleId != nil {
 		circleID = req.Msg.CircleId
@@ -99,8 +121,12 @@ func (h *PostHandler) GetFeed(
 		postType = &pt
 	}
 
-	posts, err := h.postService.GetFeed(
+	// Anonymous viewers get an unfiltered feed
+	viewerID, _ := middleware.GetUserID(ctx)
+
+	page, err := h.postService.GetFeed(
 		ctx,
+		viewerID,
 		req.Msg.Categories,
 		circleID,
 		postType,
@@ -111,34 +137,70 @@ func (h *PostHandler) GetFeed(
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
-	protoPosts := make([]*postv1.Post, len(posts))
-	for i, post := range posts {
+	protoPosts := make([]*postv1.Post, len(page.Posts))
+	for i, post := range page.Posts {
 		protoPosts[i] = mapDomainPostToProto(post)
+		mask.Apply(protoPosts[i])
+	}
+
+	categoryCounts := make(map[string]int32, len(page.CategoryCounts))
+	for category, count := range page.CategoryCounts {
+		categoryCounts[category] = int32(count) //nolint:gosec // Bounded by collection size
+	}
+
+	typeCounts := make([]*postv1.PostTypeCount, 0, len(page.TypeCounts))
+	for postType, count := range page.TypeCounts {
+		typeCounts = append(typeCounts, &postv1.PostTypeCount{
+			Type:  mapDomainPostTypeToProto(postType),
+			Count: int32(count), //nolint:gosec // Bounded by collection size
+		})
 	}
 
 	res := connect.NewResponse(&postv1.GetFeedResponse{
-		Posts:      protoPosts,
-		TotalCount: int32(len(protoPosts)),
+		Posts:          protoPosts,
+		TotalCount:     int32(page.TotalCount), //nolint:gosec // Bounded by collection size
+		CategoryCounts: categoryCounts,
+		TypeCounts:     typeCounts,
 	})
 
 	return res, nil
 }
 
+// StreamFeed streams new posts matching the request's filters until the
+// client disconnects. Stream lifetime is bounded by the server write timeout,
+// so clients should reconnect when a stream ends.
+func (h *PostHandler) StreamFeed(
+	ctx context.Context,
+	req *connect.Request[postv1.StreamFeedRequest],
+	stream *connect.ServerStream[postv1.StreamFeedResponse],
+) error {
+	filter := service.FeedFilter{
+		Categories: req.Msg.Categories,
+		CircleID:   req.Msg.CircleId,
+	}
+	if req.Msg.TypeFilter != nil {
+		pt := mapProtoPostTypeToDomain(*req.Msg.TypeFilter)
+		filter.PostType = &pt
+	}
+
+	// Anonymous viewers get an unfiltered stream
+	viewerID, _ := middleware.GetUserID(ctx)
+
+	err := h.postService.StreamFeed(ctx, viewerID, filter, func(post *domain.Post) error {
+		return stream.Send(&postv1.StreamFeedResponse{Post: mapDomainPostToProto(post)})
+	})
+	if err != nil && ctx.Err() == nil {
+		return connect.NewError(connect.CodeUnavailable, err)
+	}
+	return nil
+}
+
 func (h *PostHandler) DeletePost(
 	ctx context.Context,
 	req *connect.Request[postv1.DeletePostRequest],
 ) (*connect.Response[postv1.DeletePostResponse], error) {
-	// Auth check
-	userID, ok := middleware.GetUserID(ctx)
-	if !ok {
-		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
-	}
-
-	// Ownership verification is done in service layer
-	err := h.postService.DeletePost(ctx, req.Msg.PostId, userID)
-	if err != nil {
-		// Service already checks ownership - return appropriate error
-		return nil, connect.NewError(connect.CodePermissionDenied, err)
+	if _, err := h.v2.DeletePost(ctx, connect.NewRequest(&postv2.DeletePostRequest{PostId: req.Msg.PostId})); err != nil {
+		return nil, err
 	}
 
 	res := connect.NewResponse(&postv1.DeletePostResponse{
@@ -169,6 +231,15 @@ func (h *PostHandler) UpdatePostUrgency(
 	return res, nil
 }
 
+// readMask validates a read request's field mask against the message it trims
+func readMask(mask *fieldmaskpb.FieldMask, like proto.Message) (*fieldmask.Mask, error) {
+	m, err := fieldmask.New(mask, like)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return m, nil
+}
+
 func mapProtoPostTypeToDomain(pt postv1.PostType) domain.PostType {
 	switch pt {
 	case postv1.PostType_POST_TYPE_SOS:
@@ -216,5 +287,43 @@ func mapDomainPostToProto(post *domain.Post) *postv1.Post {
 			TimeContext:      post.Context.TimeContext,
 			Tags:             post.Context.Tags,
 		},
+		ModerationState: mapDomainModerationStateToProto(post.ModerationState),
+	}
+}
+
+// mapV2PostToV1 drops the fields v1 doesn't have: attachments, reactions and
+// the circle ID
+func mapV2PostToV1(post *postv2.Post) *postv1.Post {
+	return &postv1.Post{
+		Id:            post.Id,
+		UserId:        post.UserId,
+		Username:      post.Username,
+		Type:          postv1.PostType(post.Type),
+		Content:       post.Content,
+		Categories:    post.Categories,
+		UrgencyLevel:  post.UrgencyLevel,
+		ResponseCount: post.ResponseCount,
+		SupportCount:  post.SupportCount,
+		CreatedAt:     post.CreatedAt,
+		Context: &postv1.PostContext{
+			DaysSinceRelapse: post.Context.GetDaysSinceRelapse(),
+			TimeContext:      post.Context.GetTimeContext(),
+			Tags:             post.Context.GetTags(),
+		},
+		ModerationState:  postv1.ModerationState(post.ModerationState),
+		ModerationBanner: post.ModerationBanner,
+	}
+}
+
+func mapDomainModerationStateToProto(state domain.ModerationState) postv1.ModerationState {
+	switch state {
+	case domain.ModerationStateVisible:
+		return postv1.ModerationState_MODERATION_STATE_VISIBLE
+	case domain.ModerationStateQuarantined:
+		return postv1.ModerationState_MODERATION_STATE_QUARANTINED
+	case domain.ModerationStateRemoved:
+		return postv1.ModerationState_MODERATION_STATE_REMOVED
+	default:
+		return postv1.ModerationState_MODERATION_STATE_UNSPECIFIED
 	}
 }
@@ -0,0 +1,302 @@
+package rpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	postv1 "github.com/yourorg/anonymous-support/gen/post/v1"
+	postv2 "github.com/yourorg/anonymous-support/gen/post/v2"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PostHandlerV2 serves post.v2. The v1 PostHandler delegates post reads and
+// writes to it.
+type PostHandlerV2 struct {
+	postService     service.PostServiceInterface
+	reactionService service.ReactionServiceInterface
+}
+
+func NewPostHandlerV2(postService service.PostServiceInterface, reactionService service.ReactionServiceInterface) *PostHandlerV2 {
+	return &PostHandlerV2{
+		postService:     postService,
+		reactionService: reactionService,
+	}
+}
+
+func (h *PostHandlerV2) CreatePost(
+	ctx context.Context,
+	req *connect.Request[postv2.CreatePostRequest],
+) (*connect.Response[postv2.CreatePostResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	username, ok := middleware.GetUsername(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	attachments := make([]domain.Attachment, len(req.Msg.Attachments))
+	for i, attachment := range req.Msg.Attachments {
+		attachments[i] = domain.Attachment{
+			Type:  mapProtoAttachmentTypeToDomain(attachment.Type),
+			URL:   attachment.Url,
+			Title: attachment.Title,
+		}
+	}
+
+	post, err := h.postService.CreatePost(
+		ctx,
+		userID,
+		username,
+		mapV2PostTypeToDomain(req.Msg.Type),
+		req.Msg.Content,
+		req.Msg.Categories,
+		int(req.Msg.UrgencyLevel),
+		req.Msg.TimeContext,
+		int(req.Msg.DaysSinceRelapse),
+		req.Msg.Tags,
+		req.Msg.Visibility,
+		req.Msg.CircleId,
+		attachments,
+	)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&postv2.CreatePostResponse{
+		Post: mapDomainPostToV2(post, ""),
+	}), nil
+}
+
+func (h *PostHandlerV2) GetPost(
+	ctx context.Context,
+	req *connect.Request[postv2.GetPostRequest],
+) (*connect.Response[postv2.GetPostResponse], error) {
+	// Anonymous viewers are allowed; the viewer is only used to show quarantined posts to their author
+	viewerID, _ := middleware.GetUserID(ctx)
+
+	post, err := h.postService.GetPost(ctx, req.Msg.PostId, viewerID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	reactions, err := h.reactionService.ViewerReactions(ctx, viewerID, []string{req.Msg.PostId})
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoPost := mapDomainPostToV2(post, reactions[req.Msg.PostId])
+	if banner := post.ModerationBanner(); banner != "" {
+		protoPost.ModerationBanner = &banner
+	}
+
+	return connect.NewResponse(&postv2.GetPostResponse{
+		Post: protoPost,
+	}), nil
+}
+
+func (h *PostHandlerV2) ListFeed(
+	ctx context.Context,
+	req *connect.Request[postv2.ListFeedRequest],
+) (*connect.Response[postv2.ListFeedResponse], error) {
+	var postType *domain.PostType
+	if req.Msg.TypeFilter != nil {
+		pt := mapV2PostTypeToDomain(*req.Msg.TypeFilter)
+		postType = &pt
+	}
+
+	// Anonymous viewers get an unfiltered feed
+	viewerID, _ := middleware.GetUserID(ctx)
+
+	page, err := h.postService.GetFeedAfter(
+		ctx,
+		viewerID,
+		req.Msg.Categories,
+		req.Msg.CircleId,
+		postType,
+		req.Msg.PageToken,
+		int(req.Msg.PageSize),
+	)
+	if err != nil {
+		if req.Msg.PageToken != "" {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	postIDs := make([]string, len(page.Posts))
+	for i, post := range page.Posts {
+		postIDs[i] = post.ID.Hex()
+	}
+	reactions, err := h.reactionService.ViewerReactions(ctx, viewerID, postIDs)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoPosts := make([]*postv2.Post, len(page.Posts))
+	for i, post := range page.Posts {
+		protoPosts[i] = mapDomainPostToV2(post, reactions[postIDs[i]])
+	}
+
+	return connect.NewResponse(&postv2.ListFeedResponse{
+		Posts:         protoPosts,
+		NextPageToken: page.NextCursor,
+	}), nil
+}
+
+func (h *PostHandlerV2) DeletePost(
+	ctx context.Context,
+	req *connect.Request[postv2.DeletePostRequest],
+) (*connect.Response[postv2.DeletePostResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	// Ownership verification is done in service layer
+	if err := h.postService.DeletePost(ctx, req.Msg.PostId, userID); err != nil {
+		return nil, connect.NewError(connect.CodePermissionDenied, err)
+	}
+
+	return connect.NewResponse(&postv2.DeletePostResponse{}), nil
+}
+
+func (h *PostHandlerV2) AddReaction(
+	ctx context.Context,
+	req *connect.Request[postv2.AddReactionRequest],
+) (*connect.Response[postv2.AddReactionResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	err := h.reactionService.React(ctx, userID, req.Msg.PostId, mapProtoReactionTypeToDomain(req.Msg.Type))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&postv2.AddReactionResponse{}), nil
+}
+
+func (h *PostHandlerV2) RemoveReaction(
+	ctx context.Context,
+	req *connect.Request[postv2.RemoveReactionRequest],
+) (*connect.Response[postv2.RemoveReactionResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	if err := h.reactionService.Unreact(ctx, userID, req.Msg.PostId); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&postv2.RemoveReactionResponse{}), nil
+}
+
+// Post type and moderation state values are the same in post.v1 and post.v2,
+// so v2 enums are converted through the v1 mappers
+func mapV2PostTypeToDomain(pt postv2.PostType) domain.PostType {
+	return mapProtoPostTypeToDomain(postv1.PostType(pt))
+}
+
+func mapDomainPostToV2(post *domain.Post, viewerReaction domain.ReactionType) *postv2.Post {
+	attachments := make([]*postv2.Attachment, len(post.Attachments))
+	for i, attachment := range post.Attachments {
+		attachments[i] = &postv2.Attachment{
+			Type:  mapDomainAttachmentTypeToProto(attachment.Type),
+			Url:   attachment.URL,
+			Title: attachment.Title,
+		}
+	}
+
+	reactions := make([]*postv2.ReactionCount, 0, len(post.ReactionCounts))
+	for _, reaction := range []domain.ReactionType{domain.ReactionTypeHeart, domain.ReactionTypeHug, domain.ReactionTypeStrength, domain.ReactionTypeRelate} {
+		if count := post.ReactionCounts[reaction]; count > 0 {
+			reactions = append(reactions, &postv2.ReactionCount{
+				Type:  mapDomainReactionTypeToProto(reaction),
+				Count: count,
+			})
+		}
+	}
+
+	return &postv2.Post{
+		Id:            post.ID.Hex(),
+		UserId:        post.UserID,
+		Username:      post.Username,
+		Type:          postv2.PostType(mapDomainPostTypeToProto(post.Type)),
+		Content:       post.Content,
+		Categories:    post.Categories,
+		UrgencyLevel:  int32(post.UrgencyLevel),
+		ResponseCount: int32(post.ResponseCount),
+		SupportCount:  int32(post.SupportCount),
+		CreatedAt:     timestamppb.New(post.CreatedAt),
+		Context: &postv2.PostContext{
+			DaysSinceRelapse: int32(post.Context.DaysSinceRelapse),
+			TimeContext:      post.Context.TimeContext,
+			Tags:             post.Context.Tags,
+		},
+		ModerationState: postv2.ModerationState(mapDomainModerationStateToProto(post.ModerationState)),
+		CircleId:        post.CircleID,
+		Attachments:     attachments,
+		Reactions:       reactions,
+		ViewerReaction:  mapDomainReactionTypeToProto(viewerReaction),
+	}
+}
+
+func mapProtoAttachmentTypeToDomain(t postv2.AttachmentType) domain.AttachmentType {
+	switch t {
+	case postv2.AttachmentType_ATTACHMENT_TYPE_IMAGE:
+		return domain.AttachmentTypeImage
+	case postv2.AttachmentType_ATTACHMENT_TYPE_LINK:
+		return domain.AttachmentTypeLink
+	default:
+		return ""
+	}
+}
+
+func mapDomainAttachmentTypeToProto(t domain.AttachmentType) postv2.AttachmentType {
+	switch t {
+	case domain.AttachmentTypeImage:
+		return postv2.AttachmentType_ATTACHMENT_TYPE_IMAGE
+	case domain.AttachmentTypeLink:
+		return postv2.AttachmentType_ATTACHMENT_TYPE_LINK
+	default:
+		return postv2.AttachmentType_ATTACHMENT_TYPE_UNSPECIFIED
+	}
+}
+
+func mapProtoReactionTypeToDomain(t postv2.ReactionType) domain.ReactionType {
+	switch t {
+	case postv2.ReactionType_REACTION_TYPE_HEART:
+		return domain.ReactionTypeHeart
+	case postv2.ReactionType_REACTION_TYPE_HUG:
+		return domain.ReactionTypeHug
+	case postv2.ReactionType_REACTION_TYPE_STRENGTH:
+		return domain.ReactionTypeStrength
+	case postv2.ReactionType_REACTION_TYPE_RELATE:
+		return domain.ReactionTypeRelate
+	default:
+		return ""
+	}
+}
+
+func mapDomainReactionTypeToProto(t domain.ReactionType) postv2.ReactionType {
+	switch t {
+	case domain.ReactionTypeHeart:
+		return postv2.ReactionType_REACTION_TYPE_HEART
+	case domain.ReactionTypeHug:
+		return postv2.ReactionType_REACTION_TYPE_HUG
+	case domain.ReactionTypeStrength:
+		return postv2.ReactionType_REACTION_TYPE_STRENGTH
+	case domain.ReactionTypeRelate:
+		return postv2.ReactionType_REACTION_TYPE_RELATE
+	default:
+		return postv2.ReactionType_REACTION_TYPE_UNSPECIFIED
+	}
+}
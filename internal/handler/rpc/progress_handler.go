@@ -0,0 +1,324 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	progressv1 "github.com/yourorg/anonymous-support/gen/progress/v1"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ProgressHandler struct {
+	progressService service.ProgressServiceInterface
+}
+
+func NewProgressHandler(progressService service.ProgressServiceInterface) *ProgressHandler {
+	return &ProgressHandler{
+		progressService: progressService,
+	}
+}
+
+func (h *ProgressHandler) GetDashboard(
+	ctx context.Context,
+	req *connect.Request[progressv1.GetDashboardRequest],
+) (*connect.Response[progressv1.GetDashboardResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	dashboard, err := h.progressService.GetDashboard(ctx, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	return connect.NewResponse(&progressv1.GetDashboardResponse{
+		Dashboard: mapDashboardToProto(dashboard),
+	}), nil
+}
+
+func (h *ProgressHandler) RecordCheckIn(
+	ctx context.Context,
+	req *connect.Request[progressv1.RecordCheckInRequest],
+) (*connect.Response[progressv1.RecordCheckInResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	moodScore := int(req.Msg.GetMoodScore())
+	if req.Msg.MoodScore != nil && (moodScore < domain.MinMoodScore || moodScore > domain.MaxMoodScore) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("mood score must be between %d and %d", domain.MinMoodScore, domain.MaxMoodScore))
+	}
+
+	answers := make([]domain.CheckInAnswer, len(req.Msg.Answers))
+	for i, answer := range req.Msg.Answers {
+		answers[i] = domain.CheckInAnswer{QuestionID: answer.QuestionId, Boolean: answer.Boolean, Choice: answer.Choice}
+		if answer.Scale != nil {
+			scale := int(*answer.Scale)
+			answers[i].Scale = &scale
+		}
+	}
+
+	if err := h.progressService.RecordCheckIn(ctx, userID, req.Msg.HadRelapse, moodScore, answers); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&progressv1.RecordCheckInResponse{
+		Success: true,
+	}), nil
+}
+
+func (h *ProgressHandler) RecordCraving(
+	ctx context.Context,
+	req *connect.Request[progressv1.RecordCravingRequest],
+) (*connect.Response[progressv1.RecordCravingResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	if err := h.progressService.RecordCraving(ctx, userID, req.Msg.Resisted); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&progressv1.RecordCravingResponse{
+		Success: true,
+	}), nil
+}
+
+func (h *ProgressHandler) RecordRelapse(
+	ctx context.Context,
+	req *connect.Request[progressv1.RecordRelapseRequest],
+) (*connect.Response[progressv1.RecordRelapseResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	loc, err := time.LoadLocation(req.Msg.Timezone)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid timezone %q", req.Msg.Timezone))
+	}
+
+	var occurredAt time.Time
+	if req.Msg.OccurredAt != nil {
+		occurredAt = req.Msg.OccurredAt.AsTime()
+	}
+
+	daysClean, err := h.progressService.RecordRelapse(ctx, userID, domain.RelapseTrigger(req.Msg.Trigger), occurredAt, loc, req.Msg.GetNote())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&progressv1.RecordRelapseResponse{
+		DaysClean: int32(daysClean),
+	}), nil
+}
+
+func (h *ProgressHandler) GetAchievements(
+	ctx context.Context,
+	req *connect.Request[progressv1.GetAchievementsRequest],
+) (*connect.Response[progressv1.GetAchievementsResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	achievements, err := h.progressService.GetAchievements(ctx, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	return connect.NewResponse(&progressv1.GetAchievementsResponse{
+		Achievements: mapAchievementsToProto(achievements),
+	}), nil
+}
+
+func (h *ProgressHandler) ListCopingStrategies(
+	ctx context.Context,
+	req *connect.Request[progressv1.ListCopingStrategiesRequest],
+) (*connect.Response[progressv1.ListCopingStrategiesResponse], error) {
+	if _, ok := middleware.GetUserID(ctx); !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	resp := &progressv1.ListCopingStrategiesResponse{}
+	for _, strategy := range h.progressService.ListCopingStrategies() {
+		resp.Strategies = append(resp.Strategies, &progressv1.CopingStrategy{
+			Id:          strategy.ID,
+			Title:       strategy.Title,
+			Description: strategy.Description,
+			Category:    strategy.Category,
+			Minutes:     int32(strategy.Minutes),
+			Steps:       strategy.Steps,
+		})
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func (h *ProgressHandler) RecordCopingStrategyUse(
+	ctx context.Context,
+	req *connect.Request[progressv1.RecordCopingStrategyUseRequest],
+) (*connect.Response[progressv1.RecordCopingStrategyUseResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	effectiveness, err := h.progressService.RecordCopingUse(ctx, userID, req.Msg.StrategyId, req.Msg.Helped)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&progressv1.RecordCopingStrategyUseResponse{
+		Effectiveness: mapCopingEffectivenessToProto(*effectiveness),
+	}), nil
+}
+
+func (h *ProgressHandler) ListCheckInQuestions(
+	ctx context.Context,
+	req *connect.Request[progressv1.ListCheckInQuestionsRequest],
+) (*connect.Response[progressv1.ListCheckInQuestionsResponse], error) {
+	if _, ok := middleware.GetUserID(ctx); !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	resp := &progressv1.ListCheckInQuestionsResponse{}
+	for _, question := range h.progressService.ListCheckInQuestions() {
+		resp.Questions = append(resp.Questions, &progressv1.CheckInQuestion{
+			Id:      question.ID,
+			Prompt:  question.Prompt,
+			Type:    question.Type,
+			Min:     int32(question.Min),
+			Max:     int32(question.Max),
+			Choices: question.Choices,
+		})
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func mapCopingEffectivenessToProto(effectiveness service.CopingEffectiveness) *progressv1.CopingEffectiveness {
+	return &progressv1.CopingEffectiveness{
+		StrategyId:    effectiveness.StrategyID,
+		Title:         effectiveness.Title,
+		Uses:          int32(effectiveness.Uses),
+		Helped:        int32(effectiveness.Helped),
+		Effectiveness: effectiveness.Effectiveness,
+	}
+}
+
+func mapDashboardToProto(dashboard *service.ProgressDashboard) *progressv1.Dashboard {
+	pb := &progressv1.Dashboard{
+		CurrentStreak:    int32(dashboard.CurrentStreak),
+		LongestStreak:    int32(dashboard.LongestStreak),
+		TotalDaysClean:   int32(dashboard.TotalDaysClean),
+		Milestones:       dashboard.Milestones,
+		CravingsResisted: int32(dashboard.CravingsResisted),
+		TotalCravings:    int32(dashboard.TotalCravings),
+		SupportGiven:     int32(dashboard.SupportGiven),
+		SupportReceived:  int32(dashboard.SupportReceived),
+		Achievements:     mapAchievementsToProto(dashboard.Achievements),
+	}
+
+	if pattern := dashboard.RelapsePattern; pattern != nil {
+		pb.RelapsePattern = &progressv1.RelapsePattern{
+			TotalRelapses:     int32(pattern.TotalRelapses),
+			AverageTimeClean:  pattern.AverageTimeClean,
+			HighRiskTimeOfDay: pattern.HighRiskTimeOfDay,
+			HighRiskDayOfWeek: pattern.HighRiskDayOfWeek,
+			CommonTriggers:    pattern.CommonTriggers,
+		}
+		for _, relapse := range pattern.RecentRelapses {
+			pb.RelapsePattern.RecentRelapses = append(pb.RelapsePattern.RecentRelapses, &progressv1.RelapseEvent{
+				Date:      timestamppb.New(relapse.Date),
+				DaysClean: int32(relapse.DaysClean),
+				Trigger:   relapse.Trigger,
+				TimeOfDay: relapse.TimeOfDay,
+			})
+		}
+	}
+
+	for _, day := range dashboard.WeeklyProgress {
+		pb.WeeklyProgress = append(pb.WeeklyProgress, &progressv1.DayProgress{
+			Date:          timestamppb.New(day.Date),
+			CheckedIn:     day.CheckedIn,
+			CravingsCount: int32(day.CravingsCount),
+			SupportGiven:  int32(day.SupportGiven),
+			MoodScore:     int32(day.MoodScore),
+		})
+	}
+
+	for _, trend := range dashboard.MoodTrends {
+		pb.MoodTrends = append(pb.MoodTrends, &progressv1.MoodTrend{
+			Days:    int32(trend.Days),
+			Entries: int32(trend.Entries),
+			Average: trend.Average,
+			Change:  trend.Change,
+		})
+	}
+
+	for _, insight := range dashboard.RiskInsights {
+		pb.RiskInsights = append(pb.RiskInsights, &progressv1.RiskInsight{
+			StartHour:    int32(insight.Window.StartHour),
+			EndHour:      int32(insight.Window.EndHour),
+			Events:       int32(insight.Window.Events),
+			NextStartsAt: timestamppb.New(insight.NextStartsAt),
+			Suggestion:   insight.Suggestion,
+		})
+	}
+
+	for _, effectiveness := range dashboard.CopingStrategies {
+		pb.CopingStrategies = append(pb.CopingStrategies, mapCopingEffectivenessToProto(effectiveness))
+	}
+
+	for _, trend := range dashboard.CheckInTrends {
+		choices := make(map[string]int32, len(trend.Choices))
+		for choice, count := range trend.Choices {
+			choices[choice] = int32(count)
+		}
+		pb.CheckInTrends = append(pb.CheckInTrends, &progressv1.CheckInTrend{
+			QuestionId: trend.QuestionID,
+			Prompt:     trend.Prompt,
+			Type:       trend.Type,
+			Responses:  int32(trend.Responses),
+			Average:    trend.Average,
+			YesShare:   trend.YesShare,
+			Choices:    choices,
+		})
+	}
+
+	if savings := dashboard.Savings; savings != nil {
+		pb.Savings = &progressv1.Savings{
+			Currency:             savings.Currency,
+			DailySpendCents:      savings.DailySpendCents,
+			DailyMinutes:         int32(savings.DailyMinutes),
+			MoneySavedCents:      savings.MoneySavedCents,
+			TimeReclaimedMinutes: savings.TimeReclaimedMinutes,
+		}
+	}
+
+	return pb
+}
+
+func mapAchievementsToProto(achievements []service.Achievement) []*progressv1.Achievement {
+	protos := make([]*progressv1.Achievement, len(achievements))
+	for i, achievement := range achievements {
+		protos[i] = &progressv1.Achievement{
+			Id:          achievement.ID,
+			Title:       achievement.Title,
+			Description: achievement.Description,
+			UnlockedAt:  timestamppb.New(achievement.UnlockedAt),
+			Icon:        achievement.Icon,
+			Rarity:      achievement.Rarity,
+		}
+	}
+	return protos
+}
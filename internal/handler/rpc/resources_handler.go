@@ -0,0 +1,89 @@
+package rpc
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	resourcesv1 "github.com/yourorg/anonymous-support/gen/resources/v1"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/service"
+)
+
+type ResourcesHandler struct {
+	crisisResources service.CrisisResourceServiceInterface
+}
+
+func NewResourcesHandler(crisisResources service.CrisisResourceServiceInterface) *ResourcesHandler {
+	return &ResourcesHandler{
+		crisisResources: crisisResources,
+	}
+}
+
+func (h *ResourcesHandler) ListCrisisResources(
+	ctx context.Context,
+	req *connect.Request[resourcesv1.ListCrisisResourcesRequest],
+) (*connect.Response[resourcesv1.ListCrisisResourcesResponse], error) {
+	// Anyone can ask for help; signed-in callers fall back to their login country
+	userID, _ := middleware.GetUserID(ctx)
+
+	types := make([]domain.CrisisResourceType, 0, len(req.Msg.Types))
+	for _, t := range req.Msg.Types {
+		if domainType := mapProtoCrisisResourceTypeToDomain(t); domainType != "" {
+			types = append(types, domainType)
+		}
+	}
+
+	country, resources, err := h.crisisResources.ListCrisisResources(ctx, userID, req.Msg.Country, req.Msg.Region, types)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	protoResources := make([]*resourcesv1.CrisisResource, len(resources))
+	for i, resource := range resources {
+		protoResources[i] = &resourcesv1.CrisisResource{
+			Id:          resource.ID,
+			Name:        resource.Name,
+			Type:        mapDomainCrisisResourceTypeToProto(resource.Type),
+			Country:     resource.Country,
+			Region:      resource.Region,
+			Phone:       resource.Phone,
+			Sms:         resource.SMS,
+			Url:         resource.URL,
+			Description: resource.Description,
+			Hours:       resource.Hours,
+			Languages:   resource.Languages,
+		}
+	}
+
+	return connect.NewResponse(&resourcesv1.ListCrisisResourcesResponse{
+		Resources: protoResources,
+		Country:   country,
+	}), nil
+}
+
+func mapProtoCrisisResourceTypeToDomain(t resourcesv1.CrisisResourceType) domain.CrisisResourceType {
+	switch t {
+	case resourcesv1.CrisisResourceType_CRISIS_RESOURCE_TYPE_HOTLINE:
+		return domain.CrisisResourceTypeHotline
+	case resourcesv1.CrisisResourceType_CRISIS_RESOURCE_TYPE_TEXT_LINE:
+		return domain.CrisisResourceTypeTextLine
+	case resourcesv1.CrisisResourceType_CRISIS_RESOURCE_TYPE_LOCAL_SERVICE:
+		return domain.CrisisResourceTypeLocalService
+	default:
+		return ""
+	}
+}
+
+func mapDomainCrisisResourceTypeToProto(t domain.CrisisResourceType) resourcesv1.CrisisResourceType {
+	switch t {
+	case domain.CrisisResourceTypeHotline:
+		return resourcesv1.CrisisResourceType_CRISIS_RESOURCE_TYPE_HOTLINE
+	case domain.CrisisResourceTypeTextLine:
+		return resourcesv1.CrisisResourceType_CRISIS_RESOURCE_TYPE_TEXT_LINE
+	case domain.CrisisResourceTypeLocalService:
+		return resourcesv1.CrisisResourceType_CRISIS_RESOURCE_TYPE_LOCAL_SERVICE
+	default:
+		return resourcesv1.CrisisResourceType_CRISIS_RESOURCE_TYPE_UNSPECIFIED
+	}
+}
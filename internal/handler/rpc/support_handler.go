@@ -67,9 +67,12 @@ func (h *SupportHandler) GetResponses(
 	ctx context.Context,
 	req *connect.Request[supportv1.GetResponsesRequest],
 ) (*connect.Response[supportv1.GetResponsesResponse], error) {
+	viewerID, _ := middleware.GetUserID(ctx)
+
 	responses, err := h.supportService.GetResponses(
 		ctx,
 		req.Msg.PostId,
+		viewerID,
 		int(req.Msg.Limit),
 		int(req.Msg.Offset),
 	)
@@ -2,20 +2,32 @@ package rpc
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"connectrpc.com/connect"
 	userv1 "github.com/yourorg/anonymous-support/gen/user/v1"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/middleware"
 	"github.com/yourorg/anonymous-support/internal/service"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type UserHandler struct {
-	userService service.UserServiceInterface
+	userService        service.UserServiceInterface
+	blockService       service.BlockServiceInterface
+	entitlementService service.EntitlementServiceInterface
 }
 
-func NewUserHandler(userService service.UserServiceInterface) *UserHandler {
+func NewUserHandler(
+	userService service.UserServiceInterface,
+	blockService service.BlockServiceInterface,
+	entitlementService service.EntitlementServiceInterface,
+) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:        userService,
+		blockService:       blockService,
+		entitlementService: entitlementService,
 	}
 }
 
@@ -29,21 +41,43 @@ func (h *UserHandler) GetProfile(
 	}
 
 	res := connect.NewResponse(&userv1.GetProfileResponse{
-		Profile: &userv1.UserProfile{
-			Id:             user.ID.String(),
-			Username:       user.Username,
-			AvatarId:       int32(user.AvatarID),
-			CreatedAt:      timestamppb.New(user.CreatedAt),
-			LastActiveAt:   timestamppb.New(user.LastActiveAt),
-			IsAnonymous:    user.IsAnonymous,
-			IsPremium:      user.IsPremium,
-			StrengthPoints: int32(user.StrengthPoints),
-		},
+		Profile: mapDomainUserToProfile(user),
 	})
 
 	return res, nil
 }
 
+func (h *UserHandler) BatchGetUsers(
+	ctx context.Context,
+	req *connect.Request[userv1.BatchGetUsersRequest],
+) (*connect.Response[userv1.BatchGetUsersResponse], error) {
+	users, err := h.userService.BatchGetUsers(ctx, req.Msg.UserIds)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	profiles := make([]*userv1.UserProfile, len(users))
+	for i, user := range users {
+		profiles[i] = mapDomainUserToProfile(user)
+	}
+
+	return connect.NewResponse(&userv1.BatchGetUsersResponse{Users: profiles}), nil
+}
+
+func mapDomainUserToProfile(user *domain.User) *userv1.UserProfile {
+	return &userv1.UserProfile{
+		Id:              user.ID.String(),
+		Username:        user.Username,
+		AvatarId:        int32(user.AvatarID),
+		CreatedAt:       timestamppb.New(user.CreatedAt),
+		LastActiveAt:    timestamppb.New(user.LastActiveAt),
+		IsAnonymous:     user.IsAnonymous,
+		IsPremium:       user.IsPremium,
+		StrengthPoints:  int32(user.StrengthPoints),
+		ShareMilestones: user.ShareMilestones,
+	}
+}
+
 func (h *UserHandler) UpdateProfile(
 	ctx context.Context,
 	req *connect.Request[userv1.UpdateProfileRequest],
@@ -60,7 +94,7 @@ func (h *UserHandler) UpdateProfile(
 		avatarID = &aid
 	}
 
-	err := h.userService.UpdateProfile(ctx, req.Msg.UserId, username, avatarID)
+	err := h.userService.UpdateProfile(ctx, req.Msg.UserId, username, avatarID, req.Msg.ShareMilestones, req.Msg.Timezone)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
@@ -85,6 +119,7 @@ func (h *UserHandler) GetStreak(
 		StreakDays:       int32(tracker.StreakDays),
 		TotalCravings:    int32(tracker.TotalCravings),
 		CravingsResisted: int32(tracker.CravingsResisted),
+		Timezone:         tracker.Timezone,
 	}
 
 	if tracker.LastRelapseDate != nil {
@@ -99,7 +134,12 @@ func (h *UserHandler) UpdateStreak(
 	ctx context.Context,
 	req *connect.Request[userv1.UpdateStreakRequest],
 ) (*connect.Response[userv1.UpdateStreakResponse], error) {
-	newStreak, err := h.userService.UpdateStreak(ctx, req.Msg.UserId, req.Msg.HadRelapse)
+	moodScore := int(req.Msg.GetMoodScore())
+	if req.Msg.MoodScore != nil && (moodScore < domain.MinMoodScore || moodScore > domain.MaxMoodScore) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("mood score must be between %d and %d", domain.MinMoodScore, domain.MaxMoodScore))
+	}
+
+	newStreak, err := h.userService.UpdateStreak(ctx, req.Msg.UserId, req.Msg.HadRelapse, moodScore)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
@@ -111,3 +151,206 @@ func (h *UserHandler) UpdateStreak(
 
 	return res, nil
 }
+
+func (h *UserHandler) GetMoodHistory(
+	ctx context.Context,
+	req *connect.Request[userv1.GetMoodHistoryRequest],
+) (*connect.Response[userv1.GetMoodHistoryResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	entries, trends, err := h.userService.GetMoodHistory(ctx, userID, int(req.Msg.Days))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	response := &userv1.GetMoodHistoryResponse{}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, &userv1.MoodEntry{
+			Day:   timestamppb.New(entry.Day),
+			Score: int32(entry.Score),
+		})
+	}
+	for _, trend := range trends {
+		response.Trends = append(response.Trends, &userv1.MoodTrend{
+			Days:    int32(trend.Days),
+			Entries: int32(trend.Entries),
+			Average: trend.Average,
+			Change:  trend.Change,
+		})
+	}
+
+	return connect.NewResponse(response), nil
+}
+
+func (h *UserHandler) RecordRelapse(
+	ctx context.Context,
+	req *connect.Request[userv1.RecordRelapseRequest],
+) (*connect.Response[userv1.RecordRelapseResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	var occurredAt time.Time
+	if req.Msg.OccurredAt != nil {
+		occurredAt = req.Msg.OccurredAt.AsTime()
+	}
+
+	daysClean, err := h.userService.RecordRelapse(ctx, userID, domain.RelapseTrigger(req.Msg.Trigger), occurredAt, req.Msg.Timezone, req.Msg.GetNote())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&userv1.RecordRelapseResponse{
+		DaysClean: int32(daysClean),
+	}), nil
+}
+
+func (h *UserHandler) GetStreakFreezes(
+	ctx context.Context,
+	req *connect.Request[userv1.GetStreakFreezesRequest],
+) (*connect.Response[userv1.GetStreakFreezesResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	status, err := h.userService.GetStreakFreezes(ctx, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	return connect.NewResponse(&userv1.GetStreakFreezesResponse{
+		Freezes: toProtoStreakFreezes(status),
+	}), nil
+}
+
+func (h *UserHandler) UseStreakFreeze(
+	ctx context.Context,
+	req *connect.Request[userv1.UseStreakFreezeRequest],
+) (*connect.Response[userv1.UseStreakFreezeResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	status, err := h.userService.UseStreakFreeze(ctx, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+
+	return connect.NewResponse(&userv1.UseStreakFreezeResponse{
+		Freezes: toProtoStreakFreezes(status),
+	}), nil
+}
+
+func toProtoStreakFreezes(status *service.StreakFreezeStatus) *userv1.StreakFreezes {
+	freezes := &userv1.StreakFreezes{
+		PerMonth:  int32(status.PerMonth),
+		Available: int32(status.Available),
+		ResetsAt:  timestamppb.New(status.ResetsAt),
+	}
+	for _, day := range status.UsedThisMonth {
+		freezes.UsedThisMonth = append(freezes.UsedThisMonth, timestamppb.New(day))
+	}
+	return freezes
+}
+
+func (h *UserHandler) SetSavingsBaseline(
+	ctx context.Context,
+	req *connect.Request[userv1.SetSavingsBaselineRequest],
+) (*connect.Response[userv1.SetSavingsBaselineResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	savings, err := h.userService.SetSavingsBaseline(ctx, userID, req.Msg.DailySpendCents, req.Msg.Currency, int(req.Msg.DailyMinutes))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&userv1.SetSavingsBaselineResponse{
+		Savings: &userv1.Savings{
+			Currency:             savings.Currency,
+			DailySpendCents:      savings.DailySpendCents,
+			DailyMinutes:         int32(savings.DailyMinutes),
+			MoneySavedCents:      savings.MoneySavedCents,
+			TimeReclaimedMinutes: savings.TimeReclaimedMinutes,
+		},
+	}), nil
+}
+
+func (h *UserHandler) BlockUser(
+	ctx context.Context,
+	req *connect.Request[userv1.BlockUserRequest],
+) (*connect.Response[userv1.BlockUserResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	if err := h.blockService.BlockUser(ctx, userID, req.Msg.UserId); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	res := connect.NewResponse(&userv1.BlockUserResponse{
+		Success: true,
+	})
+
+	return res, nil
+}
+
+func (h *UserHandler) UnblockUser(
+	ctx context.Context,
+	req *connect.Request[userv1.UnblockUserRequest],
+) (*connect.Response[userv1.UnblockUserResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	if err := h.blockService.UnblockUser(ctx, userID, req.Msg.UserId); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	res := connect.NewResponse(&userv1.UnblockUserResponse{
+		Success: true,
+	})
+
+	return res, nil
+}
+
+func (h *UserHandler) GetEntitlements(
+	ctx context.Context,
+	req *connect.Request[userv1.GetEntitlementsRequest],
+) (*connect.Response[userv1.GetEntitlementsResponse], error) {
+	userID, ok := middleware.GetUserID(ctx)
+	if !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	entitlements, err := h.entitlementService.GetEntitlements(ctx, userID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	res := &userv1.GetEntitlementsResponse{
+		Plan:         string(entitlements.Plan),
+		Entitlements: make([]string, len(entitlements.Entitlements)),
+	}
+	for i, entitlement := range entitlements.Entitlements {
+		res.Entitlements[i] = string(entitlement)
+	}
+	if subscription := entitlements.Subscription; subscription != nil {
+		res.Status = string(subscription.Status)
+		if subscription.CurrentPeriodEnd != nil {
+			res.CurrentPeriodEnd = timestamppb.New(*subscription.CurrentPeriodEnd)
+		}
+	}
+
+	return connect.NewResponse(res), nil
+}
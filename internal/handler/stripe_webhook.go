@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/stripe"
+	"go.uber.org/zap"
+)
+
+// maxWebhookBody bounds webhook payloads; Stripe events are a few KB
+const maxWebhookBody = 64 << 10
+
+// StripeEventHandler applies verified Stripe events
+type StripeEventHandler interface {
+	HandleStripeEvent(ctx context.Context, event *stripe.Event) error
+}
+
+// NewStripeWebhookHandler serves Stripe's webhook. Requests must be signed
+// with secret. Failures to apply an event return 500 so Stripe retries it.
+func NewStripeWebhookHandler(events StripeEventHandler, secret string, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+		if err != nil {
+			http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		event, err := stripe.ConstructEvent(payload, r.Header.Get(stripe.SignatureHeader), secret, stripe.DefaultTolerance, time.Now())
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, stripe.ErrInvalidSignature) {
+				status = http.StatusUnauthorized
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		if err := events.HandleStripeEvent(r.Context(), event); err != nil {
+			logger.Error("Failed to apply Stripe event", zap.String("event_id", event.ID), zap.String("type", event.Type), zap.Error(err))
+			http.Error(w, "failed to apply event", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
 	"go.uber.org/zap"
 )
 
@@ -39,6 +42,9 @@ func (h *Hub) AuthorizeConnection(client *Client, authMsg *AuthMessage) error {
 	// Store user info in client
 	client.UserID = &userID
 	client.Username = claims.Username
+	client.userID = userID.String()
+	client.username = claims.Username
+	client.Role = domain.Role(claims.Role)
 	client.IsAuthenticated = true
 
 	h.logger.Info("WebSocket client authenticated",
@@ -67,8 +73,8 @@ func (h *Hub) AuthorizeChannelSubscription(ctx context.Context, client *Client,
 	case len(channel) > 7 && channel[:7] == "circle:":
 		// Circle-specific channel: circle:{circleID}
 		circleID := channel[7:]
-		// Verify user is a member of the circle
-		return h.verifyCircleMembership(ctx, client, circleID)
+		// Verify user's circle role, or platform role, lets them read the circle
+		return h.verifyCircleAccess(ctx, client, circleID)
 
 	case len(channel) > 5 && channel[:5] == "user:":
 		// User-specific channel: user:{userID}
@@ -89,22 +95,17 @@ func (h *Hub) AuthorizeChannelSubscription(ctx context.Context, client *Client,
 	}
 }
 
-// verifyCircleMembership checks if a user is a member of a circle
-func (h *Hub) verifyCircleMembership(ctx context.Context, client *Client, circleID string) error {
+// verifyCircleAccess checks if a user may read a circle's channel
+func (h *Hub) verifyCircleAccess(ctx context.Context, client *Client, circleID string) error {
 	// Parse circle ID
 	cID, err := uuid.Parse(circleID)
 	if err != nil {
 		return fmt.Errorf("invalid circle ID")
 	}
 
-	// Check membership via repository (assumes circle repo is available)
-	// In real implementation, inject CircleRepository into Hub
-	// For now, we'll assume all subscriptions are allowed
-	// TODO: Implement actual membership check
-	h.logger.Debug("Circle membership check",
-		zap.String("circle_id", cID.String()),
-		zap.String("user_id", client.UserID.String()))
-
+	if err := h.circles.AuthorizeCircle(ctx, client.UserID.String(), client.Role, cID.String(), authz.PermissionReadCircle); err != nil {
+		return fmt.Errorf("failed to verify circle access: %w", err)
+	}
 	return nil
 }
 
@@ -117,9 +118,14 @@ func (h *Hub) HandleClientMessage(client *Client, message []byte) error {
 	if err := json.Unmarshal(message, &baseMsg); err != nil {
 		return fmt.Errorf("invalid message format")
 	}
+	metrics.WSMessagesTotal.WithLabelValues("in", inboundMessageType(baseMsg.Type)).Inc()
 
 	switch baseMsg.Type {
 	case "auth":
+		// The hub tracks clients by user, so identity cannot change once registered
+		if client.IsAuthenticated {
+			return fmt.Errorf("already authenticated")
+		}
 		var authMsg AuthMessage
 		if err := json.Unmarshal(message, &authMsg); err != nil {
 			return fmt.Errorf("invalid auth message")
@@ -146,7 +152,7 @@ func (h *Hub) HandleClientMessage(client *Client, message []byte) error {
 				continue
 			}
 
-			client.Channels[channel] = true
+			client.Subscribe(channel)
 			h.logger.Info("Client subscribed to channel",
 				zap.String("channel", channel),
 				zap.String("user_id", client.UserID.String()))
@@ -162,7 +168,7 @@ func (h *Hub) HandleClientMessage(client *Client, message []byte) error {
 		}
 
 		for _, channel := range subMsg.Channels {
-			delete(client.Channels, channel)
+			client.Unsubscribe(channel)
 			h.logger.Info("Client unsubscribed from channel",
 				zap.String("channel", channel),
 				zap.String("user_id", client.UserID.String()))
@@ -170,6 +176,9 @@ func (h *Hub) HandleClientMessage(client *Client, message []byte) error {
 
 		return nil
 
+	case "typing":
+		return h.handleTyping(client, message)
+
 	default:
 		return fmt.Errorf("unknown message type: %s", baseMsg.Type)
 	}
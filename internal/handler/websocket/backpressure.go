@@ -0,0 +1,89 @@
+package websocket
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// defaultSendBufferSize is the number of messages queued per client when unconfigured
+const defaultSendBufferSize = 256
+
+// SlowClientPolicy decides what happens when a client's send buffer is full
+type SlowClientPolicy string
+
+const (
+	// SlowClientDropOldest discards the oldest queued message to make room
+	SlowClientDropOldest SlowClientPolicy = "drop_oldest"
+	// SlowClientDisconnect closes the connection; the client catches up after reconnecting
+	SlowClientDisconnect SlowClientPolicy = "disconnect"
+)
+
+// BackpressureConfig bounds how far a slow client may fall behind
+type BackpressureConfig struct {
+	SendBufferSize int // Messages queued per client before Policy applies
+	Policy         SlowClientPolicy
+}
+
+// enqueue queues data for the write pump, applying the hub's slow client
+// policy when the buffer is full. It reports whether data was queued.
+func (c *Client) enqueue(data []byte) bool {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.sendClosed {
+		return false
+	}
+
+	select {
+	case c.send <- data:
+		return true
+	default:
+	}
+
+	if c.hub.backpressure.Policy == SlowClientDisconnect {
+		metrics.WSMessagesDropped.WithLabelValues(string(SlowClientDisconnect)).Inc()
+		c.evict()
+		return false
+	}
+
+	// Only the write pump competes for the buffer while sendMu is held, so
+	// after discarding one message there is room for this one
+	select {
+	case <-c.send:
+		metrics.WSMessagesDropped.WithLabelValues(string(SlowClientDropOldest)).Inc()
+	default:
+	}
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// evict disconnects a client that cannot keep up. The close happens off the
+// caller's goroutine so a stalled connection never holds up delivery to others.
+func (c *Client) evict() {
+	c.evictOnce.Do(func() {
+		metrics.WSSlowClientsEvicted.Inc()
+		c.hub.logger.Info("Disconnecting slow WebSocket client", zap.String("user_id", c.userID))
+
+		go func() {
+			c.closeWith(websocket.CloseTryAgainLater, "client too slow")
+			c.conn.Close()
+		}()
+	})
+}
+
+// closeSend closes the send buffer, ending the write pump. Messages sent
+// afterwards are discarded.
+func (c *Client) closeSend() {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if !c.sendClosed {
+		c.sendClosed = true
+		close(c.send)
+	}
+}
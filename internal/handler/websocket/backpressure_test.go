@@ -0,0 +1,26 @@
+package websocket
+
+import "testing"
+
+func TestEnqueue_DropOldest(t *testing.T) {
+	hub := &Hub{backpressure: BackpressureConfig{SendBufferSize: 2, Policy: SlowClientDropOldest}}
+	client := &Client{hub: hub, send: make(chan []byte, 2)}
+
+	for _, msg := range []string{"a", "b", "c"} {
+		if !client.enqueue([]byte(msg)) {
+			t.Fatalf("enqueue(%q) = false, want true", msg)
+		}
+	}
+
+	for _, want := range []string{"b", "c"} {
+		if got := string(<-client.send); got != want {
+			t.Errorf("queued message = %q, want %q", got, want)
+		}
+	}
+
+	client.closeSend()
+	client.closeSend()
+	if client.enqueue([]byte("d")) {
+		t.Error("enqueue after closeSend = true, want false")
+	}
+}
@@ -2,10 +2,15 @@ package websocket
 
 import (
 	"encoding/json"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"go.uber.org/zap"
 )
 
 const (
@@ -23,47 +28,164 @@ type Client struct {
 	username        string
 	UserID          *uuid.UUID
 	Username        string
+	Role            domain.Role
 	IsAuthenticated bool
 	Channels        map[string]bool
+	version         MessageVersion
+
+	sendMu       sync.Mutex
+	sendClosed   bool
+	evictOnce    sync.Once
+	channelsMu   sync.RWMutex
+	typingMu     sync.Mutex
+	typingSentAt map[string]time.Time
+	blockMu      sync.Mutex
+	blockSet     map[string]bool // Nil until loaded; read by the hub under its lock
 }
 
 func NewClient(hub *Hub, conn *websocket.Conn, userID, username string) *Client {
 	return &Client{
 		hub:             hub,
 		conn:            conn,
-		send:            make(chan []byte, 256),
+		send:            make(chan []byte, hub.backpressure.SendBufferSize),
 		userID:          userID,
 		username:        username,
 		IsAuthenticated: false,
 		Channels:        make(map[string]bool),
+		version:         CurrentMessageVersion,
+		typingSentAt:    make(map[string]time.Time),
 	}
 }
 
+// ReadPump authenticates the client if it did not present a token on upgrade,
+// registers it with the hub, starts its write pump, and then reads messages
+// until the connection closes
 func (c *Client) ReadPump() {
+	defer c.conn.Close()
+	c.conn.SetReadLimit(maxMessageSize)
+
+	if !c.IsAuthenticated {
+		if err := c.authenticate(); err != nil {
+			c.hub.logger.Debug("WebSocket client failed to authenticate", zap.Error(err))
+			c.closeWith(websocket.ClosePolicyViolation, "authentication required")
+			return
+		}
+	}
+
+	// Load the block set before registering, so the hub never waits on it
+	c.hub.loadBlockSet(c)
+
+	select {
+	case c.hub.Register <- c:
+	case <-c.hub.done:
+		return
+	}
+	go c.WritePump()
+
+	c.hub.trackPresence(c, PresenceTracker.Connected)
 	defer func() {
-		c.hub.Unregister <- c
-		c.conn.Close()
+		select {
+		case c.hub.Unregister <- c:
+		case <-c.hub.done:
+		}
+		c.unsubscribeAll()
+		c.hub.trackPresence(c, PresenceTracker.Disconnected)
 	}()
 
 	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.conn.SetPongHandler(func(string) error {
 		_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.hub.trackPresence(c, PresenceTracker.Heartbeat)
 		return nil
 	})
 
 	for {
-		_, message, err := c.conn.ReadMessage()
+		message, err := c.readMessage()
 		if err != nil {
 			break
 		}
 
-		var wsMsg WSMessage
-		if err := json.Unmarshal(message, &wsMsg); err != nil {
-			continue
+		if err := c.hub.HandleClientMessage(c, message); err != nil {
+			c.hub.logger.Debug("Rejected WebSocket message", zap.String("user_id", c.userID), zap.Error(err))
 		}
 	}
 }
 
+// authenticate waits up to the hub's auth timeout for an auth message
+func (c *Client) authenticate() error {
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.hub.authTimeout))
+
+	message, err := c.readMessage()
+	if err != nil {
+		return err
+	}
+
+	var authMsg AuthMessage
+	if err := json.Unmarshal(message, &authMsg); err != nil || authMsg.Type != "auth" {
+		return fmt.Errorf("first message must be an auth message")
+	}
+	return c.hub.AuthorizeConnection(c, &authMsg)
+}
+
+// readMessage reads the next client request as JSON, converting v2 binary frames
+func (c *Client) readMessage() ([]byte, error) {
+	messageType, message, err := c.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	if messageType == websocket.BinaryMessage {
+		return clientMessageFromFrame(message)
+	}
+	return message, nil
+}
+
+// closeWith sends a close frame with the given code and reason
+func (c *Client) closeWith(code int, reason string) {
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
+}
+
+// Subscribe adds a channel to the client's subscriptions
+func (c *Client) Subscribe(channel string) {
+	c.channelsMu.Lock()
+	defer c.channelsMu.Unlock()
+
+	if c.Channels[channel] {
+		return
+	}
+	c.Channels[channel] = true
+	metrics.WSChannelSubscribers.WithLabelValues(channelType(channel)).Inc()
+}
+
+// Unsubscribe removes a channel from the client's subscriptions
+func (c *Client) Unsubscribe(channel string) {
+	c.channelsMu.Lock()
+	defer c.channelsMu.Unlock()
+
+	if !c.Channels[channel] {
+		return
+	}
+	delete(c.Channels, channel)
+	metrics.WSChannelSubscribers.WithLabelValues(channelType(channel)).Dec()
+}
+
+// unsubscribeAll drops every subscription when the client disconnects
+func (c *Client) unsubscribeAll() {
+	c.channelsMu.Lock()
+	defer c.channelsMu.Unlock()
+
+	for channel := range c.Channels {
+		metrics.WSChannelSubscribers.WithLabelValues(channelType(channel)).Dec()
+	}
+	c.Channels = make(map[string]bool)
+}
+
+// IsSubscribed reports whether the client receives events for channel
+func (c *Client) IsSubscribed(channel string) bool {
+	c.channelsMu.RLock()
+	defer c.channelsMu.RUnlock()
+	return c.Channels[channel]
+}
+
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
@@ -80,6 +202,14 @@ func (c *Client) WritePump() {
 				return
 			}
 
+			// Binary frames carry one protobuf message each and cannot be batched
+			if c.version == MessageV2 {
+				if err := c.conn.WriteMessage(websocket.BinaryMessage, message); err != nil {
+					return
+				}
+				continue
+			}
+
 			w, err := c.conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				return
@@ -110,12 +240,13 @@ func (c *Client) SendMessage(msg WSMessage) error {
 	if err != nil {
 		return err
 	}
-
-	select {
-	case c.send <- data:
-	default:
-		close(c.send)
+	if c.version != MessageV1 {
+		if data, err = MigrateMessage(MessageV1, c.version, data); err != nil {
+			return err
+		}
 	}
+	metrics.WSMessagesTotal.WithLabelValues("out", string(msg.Type)).Inc()
 
+	c.enqueue(data)
 	return nil
 }
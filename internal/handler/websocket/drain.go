@@ -0,0 +1,68 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// reconnectJitter bounds the random reconnect delay suggested to drained clients
+const reconnectJitter = 5 * time.Second
+
+// ServerRestartingEvent tells a client the server is going away and when to reconnect
+type ServerRestartingEvent struct {
+	ReconnectAfterMs int64 `json:"reconnect_after_ms"`
+}
+
+// Draining reports whether the hub has stopped accepting connections
+func (h *Hub) Draining() bool {
+	return h.draining.Load()
+}
+
+// Drain stops accepting connections, warns every connected client that the
+// server is restarting, and then closes the clients one by one spread across
+// window so they don't all reconnect to the remaining replicas at once. Any
+// clients still open when ctx ends are closed immediately.
+func (h *Hub) Drain(ctx context.Context, window time.Duration) {
+	h.draining.Store(true)
+
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	if len(clients) == 0 {
+		return
+	}
+	h.logger.Info("Draining WebSocket clients", zap.Int("clients", len(clients)), zap.Duration("window", window))
+
+	for _, client := range clients {
+		data, err := json.Marshal(ServerRestartingEvent{
+			ReconnectAfterMs: rand.Int63n(reconnectJitter.Milliseconds()), //nolint:gosec // Jitter doesn't need a secure source
+		})
+		if err != nil {
+			continue
+		}
+		_ = client.SendMessage(WSMessage{
+			Type:      WSMessageTypeServerRestarting,
+			Data:      data,
+			Timestamp: time.Now(),
+		})
+	}
+
+	interval := window / time.Duration(len(clients))
+	for _, client := range clients {
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+		client.closeWith(websocket.CloseServiceRestart, "server restarting")
+		client.conn.Close()
+	}
+}
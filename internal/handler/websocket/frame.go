@@ -0,0 +1,153 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProtocolV2 is the subprotocol clients offer to receive binary protobuf frames
+const ProtocolV2 = "anonymous-support.v2"
+
+// Field numbers of realtime.v2.Frame (proto/realtime/v2/frame.proto)
+const (
+	frameFieldType      protowire.Number = 1
+	frameFieldData      protowire.Number = 2
+	frameFieldTimestamp protowire.Number = 3
+	frameFieldSenderID  protowire.Number = 4
+	frameFieldChannel   protowire.Number = 5
+)
+
+// NegotiateVersion returns v2 when the client offered the v2 subprotocol and v1 otherwise
+func NegotiateVersion(r *http.Request) MessageVersion {
+	for _, protocol := range websocket.Subprotocols(r) {
+		if protocol == ProtocolV2 {
+			return MessageV2
+		}
+	}
+	return MessageV1
+}
+
+// encodeFrame encodes a message as a realtime.v2.Frame
+func encodeFrame(msg WSMessage) ([]byte, error) {
+	var b []byte
+	b = appendString(b, frameFieldType, string(msg.Type))
+
+	if len(msg.Data) > 0 {
+		var payload interface{}
+		if err := json.Unmarshal(msg.Data, &payload); err != nil {
+			return nil, fmt.Errorf("invalid message data: %w", err)
+		}
+		value, err := structpb.NewValue(payload)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := proto.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, frameFieldData, protowire.BytesType)
+		b = protowire.AppendBytes(b, encoded)
+	}
+
+	if !msg.Timestamp.IsZero() {
+		encoded, err := proto.Marshal(timestamppb.New(msg.Timestamp))
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, frameFieldTimestamp, protowire.BytesType)
+		b = protowire.AppendBytes(b, encoded)
+	}
+
+	b = appendString(b, frameFieldSenderID, msg.SenderID)
+	b = appendString(b, frameFieldChannel, msg.Channel)
+	return b, nil
+}
+
+// decodeFrame decodes a realtime.v2.Frame, skipping unknown fields
+func decodeFrame(b []byte) (WSMessage, error) {
+	var msg WSMessage
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return msg, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return msg, protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+
+		field, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return msg, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch num {
+		case frameFieldType:
+			msg.Type = WSMessageType(field)
+		case frameFieldData:
+			var value structpb.Value
+			if err := proto.Unmarshal(field, &value); err != nil {
+				return msg, err
+			}
+			data, err := json.Marshal(value.AsInterface())
+			if err != nil {
+				return msg, err
+			}
+			msg.Data = data
+		case frameFieldTimestamp:
+			var ts timestamppb.Timestamp
+			if err := proto.Unmarshal(field, &ts); err != nil {
+				return msg, err
+			}
+			msg.Timestamp = ts.AsTime()
+		case frameFieldSenderID:
+			msg.SenderID = string(field)
+		case frameFieldChannel:
+			msg.Channel = string(field)
+		}
+	}
+	return msg, nil
+}
+
+func appendString(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+// clientMessageFromFrame converts a v2 client frame to the flat JSON request
+// HandleClientMessage expects: the data object's fields plus type and channel
+func clientMessageFromFrame(b []byte) ([]byte, error) {
+	frame, err := decodeFrame(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid frame: %w", err)
+	}
+
+	request := map[string]interface{}{}
+	if len(frame.Data) > 0 {
+		if err := json.Unmarshal(frame.Data, &request); err != nil {
+			return nil, fmt.Errorf("frame data must be an object")
+		}
+	}
+	request["type"] = string(frame.Type)
+	if frame.Channel != "" {
+		request["channel"] = frame.Channel
+	}
+	return json.Marshal(request)
+}
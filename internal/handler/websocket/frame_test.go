@@ -0,0 +1,75 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMigrateMessage_RoundTrip(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		msg  WSMessage
+	}{
+		{name: "object data", msg: WSMessage{Type: WSMessageTypeNewPost, Data: json.RawMessage(`{"post_id":"p1","urgency":3}`), Timestamp: ts}},
+		{name: "channel and sender", msg: WSMessage{Type: WSMessageTypePresence, Data: json.RawMessage(`{"online":true}`), Timestamp: ts, SenderID: "u1", Channel: "circle:c1"}},
+		{name: "no data", msg: WSMessage{Type: WSMessageTypePong, Data: json.RawMessage(`null`), Timestamp: ts}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v1, err := json.Marshal(tt.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			frame, err := MigrateMessage(MessageV1, MessageV2, v1)
+			if err != nil {
+				t.Fatalf("v1 -> v2: %v", err)
+			}
+			back, err := MigrateMessage(MessageV2, MessageV1, frame)
+			if err != nil {
+				t.Fatalf("v2 -> v1: %v", err)
+			}
+
+			var got WSMessage
+			if err := json.Unmarshal(back, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Type != tt.msg.Type || got.SenderID != tt.msg.SenderID || got.Channel != tt.msg.Channel || !got.Timestamp.Equal(tt.msg.Timestamp) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.msg)
+			}
+			if string(got.Data) != string(tt.msg.Data) {
+				t.Errorf("round trip data = %s, want %s", got.Data, tt.msg.Data)
+			}
+		})
+	}
+}
+
+func TestClientMessageFromFrame(t *testing.T) {
+	frame, err := encodeFrame(WSMessage{
+		Type:    "subscribe",
+		Data:    json.RawMessage(`{"channels":["posts"]}`),
+		Channel: "post:p1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := clientMessageFromFrame(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["type"] != "subscribe" || got["channel"] != "post:p1" {
+		t.Errorf("clientMessageFromFrame() = %s", data)
+	}
+	if channels, ok := got["channels"].([]interface{}); !ok || len(channels) != 1 || channels[0] != "posts" {
+		t.Errorf("clientMessageFromFrame() channels = %v", got["channels"])
+	}
+}
@@ -1,91 +1,405 @@
 package websocket
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
 	"github.com/yourorg/anonymous-support/internal/pkg/jwt"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
 	"go.uber.org/zap"
 )
 
+// blockSetRefreshInterval bounds how stale a client's in-memory block set may get
+const blockSetRefreshInterval = time.Minute
+
+// blockSetLoadTimeout bounds each block set load made on behalf of a client
+const blockSetLoadTimeout = 5 * time.Second
+
+// BlockChecker provides the set of users a user has blocked or been blocked by
+type BlockChecker interface {
+	GetBlockSet(ctx context.Context, userID string) (map[string]bool, error)
+}
+
+// CircleAuthorizer checks a circle-scoped permission against the user's role
+// in the circle and their platform role
+type CircleAuthorizer interface {
+	AuthorizeCircle(ctx context.Context, userID string, role domain.Role, circleID string, permission authz.Permission) error
+}
+
+// PresenceTracker records users connecting, staying connected, and disconnecting
+type PresenceTracker interface {
+	Connected(ctx context.Context, userID string) error
+	Heartbeat(ctx context.Context, userID string) error
+	Disconnected(ctx context.Context, userID string) error
+}
+
+// presenceTimeout bounds each presence update made on behalf of a client
+const presenceTimeout = 5 * time.Second
+
+// EventBus fans realtime events out to every hub instance
+type EventBus interface {
+	PublishEvent(ctx context.Context, event *domain.RealtimeEvent) error
+	SubscribeEvents(ctx context.Context, handler func(*domain.RealtimeEvent)) error
+}
+
+// ChangeSource streams posts and responses inserted into the database by any writer
+type ChangeSource interface {
+	WatchPosts(ctx context.Context, handler func(*domain.Post)) error
+	WatchResponses(ctx context.Context, handler func(*domain.SupportResponse)) error
+}
+
+// Hub tracks the clients connected to this instance. When an event bus is
+// configured, broadcasts and direct messages are published to it and every
+// instance, including this one, delivers them to its own clients.
 type Hub struct {
-	clients    map[string]*Client
-	broadcast  chan WSMessage
-	Register   chan *Client
-	Unregister chan *Client
-	mu         sync.RWMutex
-	jwtManager *jwt.Manager
-	logger     *zap.Logger
+	clients      map[string]*Client
+	broadcast    chan WSMessage
+	Register     chan *Client
+	Unregister   chan *Client
+	mu           sync.RWMutex
+	jwtManager   *jwt.Manager
+	blockChecker BlockChecker
+	circles      CircleAuthorizer
+	presence     PresenceTracker
+	eventBus     EventBus
+	authTimeout  time.Duration
+	backpressure BackpressureConfig
+	draining     atomic.Bool
+	done         chan struct{}
+	logger       *zap.Logger
 }
 
-func NewHub(jwtManager *jwt.Manager, logger *zap.Logger) *Hub {
+func NewHub(
+	jwtManager *jwt.Manager,
+	blockChecker BlockChecker,
+	circles CircleAuthorizer,
+	presence PresenceTracker,
+	eventBus EventBus,
+	authTimeout time.Duration,
+	backpressure BackpressureConfig,
+	logger *zap.Logger,
+) *Hub {
+	if backpressure.SendBufferSize <= 0 {
+		backpressure.SendBufferSize = defaultSendBufferSize
+	}
+	if backpressure.Policy == "" {
+		backpressure.Policy = SlowClientDropOldest
+	}
 	return &Hub{
-		clients:    make(map[string]*Client),
-		broadcast:  make(chan WSMessage, 256),
-		Register:   make(chan *Client),
-		Unregister: make(chan *Client),
-		jwtManager: jwtManager,
-		logger:     logger,
+		clients:      make(map[string]*Client),
+		broadcast:    make(chan WSMessage, 256),
+		Register:     make(chan *Client),
+		Unregister:   make(chan *Client),
+		jwtManager:   jwtManager,
+		blockChecker: blockChecker,
+		circles:      circles,
+		presence:     presence,
+		eventBus:     eventBus,
+		authTimeout:  authTimeout,
+		backpressure: backpressure,
+		done:         make(chan struct{}),
+		logger:       logger,
 	}
 }
 
+// ServeConn takes over an upgraded connection. A client that presented a
+// token is authenticated immediately; otherwise its first message must be an
+// auth message sent within the auth timeout, or the connection is closed.
+// Outbound messages are encoded in the negotiated protocol version.
+func (h *Hub) ServeConn(conn *websocket.Conn, token string, version MessageVersion) {
+	client := NewClient(h, conn, "", "")
+	client.version = version
+
+	if h.draining.Load() {
+		client.closeWith(websocket.CloseTryAgainLater, "server restarting")
+		conn.Close()
+		return
+	}
+
+	if token != "" {
+		if err := h.AuthorizeConnection(client, &AuthMessage{Type: "auth", Token: token}); err != nil {
+			client.closeWith(websocket.ClosePolicyViolation, "invalid authentication token")
+			conn.Close()
+			return
+		}
+	}
+
+	go client.ReadPump()
+}
+
+// RunRelay delivers events published by any instance to this instance's
+// clients until ctx is done
+func (h *Hub) RunRelay(ctx context.Context) {
+	if h.eventBus == nil {
+		return
+	}
+
+	for ctx.Err() == nil {
+		err := h.eventBus.SubscribeEvents(ctx, h.deliverLocal)
+		if err != nil {
+			h.logger.Warn("Realtime event subscription failed", zap.Error(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// RunChangeStreams announces posts and responses inserted into the database,
+// by the services or anything else, to this instance's clients until ctx is
+// done. Every instance watches, so events are delivered locally rather than
+// published to the other instances.
+func (h *Hub) RunChangeStreams(ctx context.Context, source ChangeSource) {
+	go h.watchChanges(ctx, "posts", func(ctx context.Context) error {
+		return source.WatchPosts(ctx, func(post *domain.Post) {
+			event, err := domain.NewPostRealtimeEvent(post.ID.Hex(), post.UserID, string(post.Type), post.Categories)
+			if err == nil {
+				h.deliverLocal(event)
+			}
+		})
+	})
+
+	h.watchChanges(ctx, "responses", func(ctx context.Context) error {
+		return source.WatchResponses(ctx, func(response *domain.SupportResponse) {
+			event, err := domain.NewResponseRealtimeEvent(response.PostID, response.ID.Hex(), response.UserID)
+			if err == nil {
+				h.deliverLocal(event)
+			}
+		})
+	})
+}
+
+// watchChanges runs watch until ctx is done, restarting it whenever the stream ends
+func (h *Hub) watchChanges(ctx context.Context, collection string, watch func(context.Context) error) {
+	for ctx.Err() == nil {
+		if err := watch(ctx); err != nil {
+			h.logger.Warn("Change stream failed", zap.String("collection", collection), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// deliverLocal delivers an event to this instance's clients
+func (h *Hub) deliverLocal(event *domain.RealtimeEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	msg := WSMessage{
+		Type:      WSMessageType(event.Type),
+		Data:      event.Data,
+		Timestamp: event.Timestamp,
+		SenderID:  event.SenderID,
+		Channel:   event.Channel,
+	}
+	if event.Type == domain.RealtimeEventSubscriptionRevoked {
+		h.revokeLocal(event.UserID, msg)
+		return
+	}
+	if event.UserID != "" {
+		h.sendLocal(event.UserID, msg)
+		return
+	}
+	h.broadcast <- msg
+}
+
 func (h *Hub) Run() {
 	for {
 		select {
+		case <-h.done:
+			return
+
 		case client := <-h.Register:
 			h.mu.Lock()
+			if _, ok := h.clients[client.userID]; !ok {
+				metrics.WSConnectionsActive.Inc()
+			}
 			h.clients[client.userID] = client
 			h.mu.Unlock()
 
-			h.BroadcastUserOnline(client.userID, client.username)
-
 		case client := <-h.Unregister:
 			h.mu.Lock()
-			if _, ok := h.clients[client.userID]; ok {
+			// A reconnect may already have replaced this client
+			if existing, ok := h.clients[client.userID]; ok && existing == client {
 				delete(h.clients, client.userID)
-				close(client.send)
+				client.closeSend()
+				metrics.WSConnectionsActive.Dec()
 			}
 			h.mu.Unlock()
 
-			h.BroadcastUserOffline(client.userID)
-
 		case message := <-h.broadcast:
 			h.mu.RLock()
 			for _, client := range h.clients {
+				if message.Channel != "" && !client.IsSubscribed(message.Channel) {
+					continue
+				}
+				if h.isBlocked(client, message.SenderID) {
+					continue
+				}
 				_ = client.SendMessage(message)
 			}
 			h.mu.RUnlock()
+
+			if !message.Timestamp.IsZero() {
+				metrics.WSBroadcastLatency.WithLabelValues(string(message.Type)).Observe(time.Since(message.Timestamp).Seconds())
+			}
 		}
 	}
 }
 
+// SendToUser delivers a message to a user on whichever instance they are connected to
 func (h *Hub) SendToUser(userID string, msg WSMessage) {
+	if h.publish(userID, msg) {
+		return
+	}
+	h.sendLocal(userID, msg)
+}
+
+func (h *Hub) sendLocal(userID string, msg WSMessage) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if client, ok := h.clients[userID]; ok && !h.isBlocked(client, msg.SenderID) {
+		_ = client.SendMessage(msg)
+	}
+}
+
+// revokeLocal removes a local client's subscription to msg.Channel and tells it why
+func (h *Hub) revokeLocal(userID string, msg WSMessage) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	if client, ok := h.clients[userID]; ok {
+	if client, ok := h.clients[userID]; ok && client.IsSubscribed(msg.Channel) {
+		client.Unsubscribe(msg.Channel)
 		_ = client.SendMessage(msg)
 	}
 }
 
+// isBlocked reports whether a message from senderID must not be delivered to
+// client. It only reads the client's cached block set, since it runs under the
+// hub lock; until a set has loaded, messages from other users are withheld.
+func (h *Hub) isBlocked(client *Client, senderID string) bool {
+	if senderID == "" || h.blockChecker == nil || senderID == client.userID {
+		return false
+	}
+
+	client.blockMu.Lock()
+	defer client.blockMu.Unlock()
+
+	if client.blockSet == nil {
+		return true
+	}
+	return client.blockSet[senderID]
+}
+
+// loadBlockSet replaces a client's cached block set. On failure the previous
+// set, if any, is kept until the next refresh.
+func (h *Hub) loadBlockSet(client *Client) {
+	if h.blockChecker == nil || client.userID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), blockSetLoadTimeout)
+	defer cancel()
+
+	blockSet, err := h.blockChecker.GetBlockSet(ctx, client.userID)
+	if err != nil {
+		h.logger.Warn("Failed to load block set", zap.String("user_id", client.userID), zap.Error(err))
+		return
+	}
+	if blockSet == nil {
+		blockSet = map[string]bool{}
+	}
+
+	client.blockMu.Lock()
+	client.blockSet = blockSet
+	client.blockMu.Unlock()
+}
+
+// RunBlockRefresh reloads every connected client's block set each
+// blockSetRefreshInterval until ctx is done, outside the hub's event loop
+func (h *Hub) RunBlockRefresh(ctx context.Context) {
+	if h.blockChecker == nil {
+		return
+	}
+
+	ticker := time.NewTicker(blockSetRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		h.mu.RLock()
+		clients := make([]*Client, 0, len(h.clients))
+		for _, client := range h.clients {
+			clients = append(clients, client)
+		}
+		h.mu.RUnlock()
+
+		for _, client := range clients {
+			if ctx.Err() != nil {
+				return
+			}
+			h.loadBlockSet(client)
+		}
+	}
+}
+
+// Broadcast delivers a message to every connected client on every instance
 func (h *Hub) Broadcast(msg WSMessage) {
+	if h.publish("", msg) {
+		return
+	}
 	h.broadcast <- msg
 }
 
-func (h *Hub) BroadcastUserOnline(userID, username string) {
-	msg := WSMessage{
-		Type:      WSMessageTypeUserOnline,
-		Timestamp: time.Now(),
+// publish hands a message to the event bus, reporting whether it was accepted.
+// Without a bus, or if publishing fails, delivery falls back to local clients.
+func (h *Hub) publish(userID string, msg WSMessage) bool {
+	if h.eventBus == nil {
+		return false
 	}
-	h.Broadcast(msg)
+
+	err := h.eventBus.PublishEvent(context.Background(), &domain.RealtimeEvent{
+		Type:      string(msg.Type),
+		UserID:    userID,
+		Channel:   msg.Channel,
+		SenderID:  msg.SenderID,
+		Data:      msg.Data,
+		Timestamp: msg.Timestamp,
+	})
+	if err != nil {
+		h.logger.Warn("Failed to publish realtime event", zap.String("type", string(msg.Type)), zap.Error(err))
+		return false
+	}
+	return true
 }
 
-func (h *Hub) BroadcastUserOffline(userID string) {
-	msg := WSMessage{
-		Type:      WSMessageTypeUserOffline,
-		Timestamp: time.Now(),
+// trackPresence applies a presence update for an identified client
+func (h *Hub) trackPresence(client *Client, update func(PresenceTracker, context.Context, string) error) {
+	if h.presence == nil || client.userID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+	defer cancel()
+
+	if err := update(h.presence, ctx, client.userID); err != nil {
+		h.logger.Warn("Failed to update presence", zap.String("user_id", client.userID), zap.Error(err))
 	}
-	h.Broadcast(msg)
 }
 
 func (h *Hub) GetOnlineCount() int {
@@ -101,7 +415,7 @@ func (h *Hub) IsUserOnline(userID string) bool {
 	return ok
 }
 
+// Stop ends the hub's event loop; call Drain first to disconnect clients cleanly
 func (h *Hub) Stop() {
-	close(h.Register)
-	close(h.Unregister)
+	close(h.done)
 }
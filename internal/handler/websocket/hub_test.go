@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type fakeBlockChecker struct {
+	blockSet map[string]bool
+	err      error
+	calls    int
+}
+
+func (f *fakeBlockChecker) GetBlockSet(ctx context.Context, userID string) (map[string]bool, error) {
+	f.calls++
+	return f.blockSet, f.err
+}
+
+func TestIsBlockedReadsOnlyTheCachedBlockSet(t *testing.T) {
+	checker := &fakeBlockChecker{err: errors.New("redis timeout")}
+	hub := &Hub{blockChecker: checker, logger: zap.NewNop()}
+	client := &Client{hub: hub, userID: "viewer"}
+
+	hub.loadBlockSet(client)
+	if !hub.isBlocked(client, "stranger") {
+		t.Error("message delivered before the block set loaded")
+	}
+	if hub.isBlocked(client, "") || hub.isBlocked(client, "viewer") {
+		t.Error("system and own messages withheld")
+	}
+
+	checker.blockSet, checker.err = map[string]bool{"blocked": true}, nil
+	hub.loadBlockSet(client)
+	if !hub.isBlocked(client, "blocked") || hub.isBlocked(client, "stranger") {
+		t.Error("cached block set not applied")
+	}
+
+	checker.err = errors.New("redis timeout")
+	hub.loadBlockSet(client)
+	if !hub.isBlocked(client, "blocked") {
+		t.Error("failed refresh dropped the previous block set")
+	}
+
+	if checker.calls != 3 {
+		t.Errorf("GetBlockSet calls = %d, want 3 (isBlocked must not load)", checker.calls)
+	}
+}
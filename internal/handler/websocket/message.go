@@ -8,21 +8,24 @@ import (
 type WSMessageType string
 
 const (
-	WSMessageTypeNewPost         WSMessageType = "new_post"
-	WSMessageTypeNewResponse     WSMessageType = "new_response"
-	WSMessageTypeSupporterCount  WSMessageType = "supporter_count"
-	WSMessageTypeNotification    WSMessageType = "notification"
-	WSMessageTypeUserOnline      WSMessageType = "user_online"
-	WSMessageTypeUserOffline     WSMessageType = "user_offline"
-	WSMessageTypeTypingIndicator WSMessageType = "typing"
-	WSMessageTypePing            WSMessageType = "ping"
-	WSMessageTypePong            WSMessageType = "pong"
+	WSMessageTypeNewPost             WSMessageType = "new_post"
+	WSMessageTypeNewResponse         WSMessageType = "new_response"
+	WSMessageTypeSupporterCount      WSMessageType = "supporter_count"
+	WSMessageTypeNotification        WSMessageType = "notification"
+	WSMessageTypePresence            WSMessageType = "presence"
+	WSMessageTypeTypingIndicator     WSMessageType = "typing"
+	WSMessageTypePing                WSMessageType = "ping"
+	WSMessageTypePong                WSMessageType = "pong"
+	WSMessageTypeServerRestarting    WSMessageType = "server_restarting"
+	WSMessageTypeSubscriptionRevoked WSMessageType = "subscription_revoked"
 )
 
 type WSMessage struct {
 	Type      WSMessageType   `json:"type"`
 	Data      json.RawMessage `json:"data"`
 	Timestamp time.Time       `json:"timestamp"`
+	SenderID  string          `json:"sender_id,omitempty"` // Originating user; used to enforce blocks
+	Channel   string          `json:"channel,omitempty"`   // Set for events scoped to a subscribed channel
 }
 
 type SupporterCountEvent struct {
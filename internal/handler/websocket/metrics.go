@@ -0,0 +1,28 @@
+package websocket
+
+import "strings"
+
+// clientMessageTypes are the message types clients may send; anything else is
+// recorded as "unknown" to keep metric cardinality bounded
+var clientMessageTypes = map[string]bool{
+	"auth":        true,
+	"subscribe":   true,
+	"unsubscribe": true,
+	"typing":      true,
+}
+
+// inboundMessageType returns the metric label for a client message type
+func inboundMessageType(messageType string) string {
+	if clientMessageTypes[messageType] {
+		return messageType
+	}
+	return "unknown"
+}
+
+// channelType returns the metric label for a channel, dropping its ID
+func channelType(channel string) string {
+	if name, _, ok := strings.Cut(channel, ":"); ok {
+		return name
+	}
+	return channel
+}
@@ -11,7 +11,7 @@ type MessageVersion string
 
 const (
 	MessageV1 MessageVersion = "v1"
-	MessageV2 MessageVersion = "v2" // Future version
+	MessageV2 MessageVersion = "v2" // Binary protobuf frames, negotiated with the ProtocolV2 subprotocol
 )
 
 // CurrentMessageVersion is the current schema version
@@ -154,12 +154,19 @@ func MigrateMessage(from MessageVersion, to MessageVersion, data []byte) ([]byte
 		return data, nil
 	}
 
-	// Future: Implement version-specific migration logic
-	// For now, only v1 exists
 	switch {
 	case from == MessageV1 && to == MessageV2:
-		// Migrate from v1 to v2
-		return nil, fmt.Errorf("migration from v1 to v2 not yet implemented")
+		var msg WSMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to decode v1 message: %w", err)
+		}
+		return encodeFrame(msg)
+	case from == MessageV2 && to == MessageV1:
+		msg, err := decodeFrame(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode v2 frame: %w", err)
+		}
+		return json.Marshal(msg)
 	default:
 		return nil, fmt.Errorf("unsupported migration: %s -> %s", from, to)
 	}
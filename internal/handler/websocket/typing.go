@@ -0,0 +1,86 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// typingThrottle is the minimum gap between relayed typing events per client and channel
+	typingThrottle = 3 * time.Second
+	// typingTTL is how long clients show an indicator without a fresh typing event
+	typingTTL = 5 * time.Second
+)
+
+// TypingMessage is sent by a client while its user is composing on a channel
+type TypingMessage struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+}
+
+// TypingEvent is relayed to the other subscribers of the channel
+type TypingEvent struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Channel   string    `json:"channel"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// handleTyping relays a typing indicator to a channel the client is subscribed to.
+// Repeats inside the throttle window are dropped; indicators expire on their own.
+func (h *Hub) handleTyping(client *Client, message []byte) error {
+	if !client.IsAuthenticated {
+		return fmt.Errorf("must authenticate before sending typing indicators")
+	}
+
+	var typingMsg TypingMessage
+	if err := json.Unmarshal(message, &typingMsg); err != nil {
+		return fmt.Errorf("invalid typing message")
+	}
+
+	channel := typingMsg.Channel
+	if !strings.HasPrefix(channel, "post:") && !strings.HasPrefix(channel, "circle:") {
+		return fmt.Errorf("typing indicators are not supported on channel %s", channel)
+	}
+	if !client.IsSubscribed(channel) {
+		return fmt.Errorf("not subscribed to channel %s", channel)
+	}
+
+	now := time.Now()
+	if !client.allowTyping(channel, now) {
+		return nil
+	}
+
+	data, err := json.Marshal(TypingEvent{
+		UserID:    client.UserID.String(),
+		Username:  client.Username,
+		Channel:   channel,
+		ExpiresAt: now.Add(typingTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	h.Broadcast(WSMessage{
+		Type:      WSMessageTypeTypingIndicator,
+		Data:      data,
+		Timestamp: now,
+		SenderID:  client.UserID.String(),
+		Channel:   channel,
+	})
+	return nil
+}
+
+// allowTyping reports whether a typing event on channel may be relayed now
+func (c *Client) allowTyping(channel string, now time.Time) bool {
+	c.typingMu.Lock()
+	defer c.typingMu.Unlock()
+
+	if last, ok := c.typingSentAt[channel]; ok && now.Sub(last) < typingThrottle {
+		return false
+	}
+	c.typingSentAt[channel] = now
+	return true
+}
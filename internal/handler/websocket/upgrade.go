@@ -0,0 +1,71 @@
+package websocket
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// TokenSubprotocol is offered by clients that cannot set headers on the
+// upgrade request (browsers): Sec-WebSocket-Protocol: access_token, <jwt>
+const TokenSubprotocol = "access_token"
+
+// UpgraderConfig configures WebSocket upgrades
+type UpgraderConfig struct {
+	ReadBufferSize  int
+	WriteBufferSize int
+	AllowedOrigins  []string // "*" allows any origin
+}
+
+// NewUpgrader creates an upgrader that only accepts the configured origins
+func NewUpgrader(cfg UpgraderConfig) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:  cfg.ReadBufferSize,
+		WriteBufferSize: cfg.WriteBufferSize,
+		Subprotocols:    []string{ProtocolV2, TokenSubprotocol},
+		CheckOrigin:     originChecker(cfg.AllowedOrigins),
+	}
+}
+
+// originChecker allows requests without an Origin header (native clients),
+// same-origin requests, and origins on the allow list
+func originChecker(allowed []string) func(r *http.Request) bool {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, origin := range allowed {
+		allowedSet[strings.ToLower(strings.TrimRight(origin, "/"))] = true
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || allowedSet["*"] || allowedSet[strings.ToLower(origin)] {
+			return true
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		return strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// RequestToken extracts an access token from the Authorization header or the
+// token subprotocol. An empty token means the client must authenticate with
+// an auth message after connecting.
+func RequestToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+			return token
+		}
+	}
+
+	protocols := websocket.Subprotocols(r)
+	for i, protocol := range protocols {
+		if protocol == TokenSubprotocol && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
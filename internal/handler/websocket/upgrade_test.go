@@ -0,0 +1,62 @@
+package websocket
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{name: "no origin header", allowed: nil, origin: "", want: true},
+		{name: "same origin", allowed: nil, origin: "https://api.example.com", want: true},
+		{name: "cross origin denied", allowed: nil, origin: "https://evil.example", want: false},
+		{name: "allow listed", allowed: []string{"https://app.example.com/"}, origin: "https://APP.example.com", want: true},
+		{name: "wildcard", allowed: []string{"*"}, origin: "https://evil.example", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "https://api.example.com/ws", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := originChecker(tt.allowed)(r); got != tt.want {
+				t.Errorf("originChecker() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		auth     string
+		protocol string
+		want     string
+	}{
+		{name: "bearer header", auth: "Bearer header-token", want: "header-token"},
+		{name: "subprotocol", protocol: "access_token, proto-token", want: "proto-token"},
+		{name: "subprotocol without token", protocol: "access_token", want: ""},
+		{name: "none", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/ws", nil)
+			if tt.auth != "" {
+				r.Header.Set("Authorization", tt.auth)
+			}
+			if tt.protocol != "" {
+				r.Header.Set("Sec-WebSocket-Protocol", tt.protocol)
+			}
+			if got := RequestToken(r); got != tt.want {
+				t.Errorf("RequestToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
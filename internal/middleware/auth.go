@@ -13,6 +13,7 @@ type contextKey string
 const UserIDKey contextKey = "user_id"
 const UsernameKey contextKey = "username"
 const IsAnonymousKey contextKey = "is_anonymous"
+const UserRoleKey contextKey = "user_role"
 
 func AuthMiddleware(jwtManager *jwt.Manager) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -23,28 +24,57 @@ func AuthMiddleware(jwtManager *jwt.Manager) func(http.Handler) http.Handler {
 				return
 			}
 
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
+			token, ok := bearerToken(authHeader)
+			if !ok {
 				http.Error(w, "invalid authorization header format", http.StatusUnauthorized)
 				return
 			}
 
-			token := parts[1]
 			claims, err := jwtManager.ValidateAccessToken(token)
 			if err != nil {
 				http.Error(w, "invalid or expired token", http.StatusUnauthorized)
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-			ctx = context.WithValue(ctx, UsernameKey, claims.Username)
-			ctx = context.WithValue(ctx, IsAnonymousKey, claims.IsAnonymous)
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
 
-			next.ServeHTTP(w, r.WithContext(ctx))
+// OptionalAuthMiddleware authenticates requests that carry a bearer token and
+// lets those without one through anonymously. An invalid token is rejected
+// rather than treated as anonymous, so clients notice it has expired.
+func OptionalAuthMiddleware(jwtManager *jwt.Manager) func(http.Handler) http.Handler {
+	required := AuthMiddleware(jwtManager)
+	return func(next http.Handler) http.Handler {
+		authenticated := required(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authenticated.ServeHTTP(w, r)
 		})
 	}
 }
 
+// bearerToken returns the token of a "Bearer <token>" Authorization header
+func bearerToken(authHeader string) (string, bool) {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// WithClaims returns ctx carrying the identity and role of an access token
+func WithClaims(ctx context.Context, claims *jwt.Claims) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, UsernameKey, claims.Username)
+	ctx = context.WithValue(ctx, IsAnonymousKey, claims.IsAnonymous)
+	return context.WithValue(ctx, UserRoleKey, claims.Role)
+}
+
 func GetUserIDFromContext(ctx context.Context) string {
 	if userID, ok := ctx.Value(UserIDKey).(string); ok {
 		return userID
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"github.com/yourorg/anonymous-support/internal/pkg/geo"
+)
+
+// TrustedProxies are the proxies whose forwarding and geo headers describe
+// the client. Headers from any other peer are ignored, since the client could
+// have set them.
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses CIDRs or single addresses
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+			}
+			proxies = append(proxies, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+func (t TrustedProxies) contains(addr netip.Addr) bool {
+	for _, prefix := range t {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientInfoMiddleware records the client IP, device fingerprint header and
+// edge-resolved location in the request context. X-Forwarded-For, X-Real-IP
+// and the geo headers are only read when the peer is a trusted proxy.
+func ClientInfoMiddleware(geoHeaders geo.Headers, proxies TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip, proxied := proxies.clientIP(r)
+			info := fingerprint.ClientInfo{
+				IP:       ip,
+				DeviceID: r.Header.Get("X-Device-Fingerprint"),
+			}
+			if proxied {
+				info.Location = geoHeaders.Lookup(r.Header)
+			}
+
+			ctx := fingerprint.WithClientInfo(r.Context(), info)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// clientIP returns the originating client IP for a request, and whether the
+// peer was a trusted proxy. X-Forwarded-For is read right to left, past the
+// trusted proxies, so entries the client prepended are skipped.
+func (t TrustedProxies) clientIP(r *http.Request) (string, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil || !t.contains(peer.Unmap()) {
+		return host, false
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	client := ""
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap().String()
+		if !t.contains(addr.Unmap()) {
+			break
+		}
+	}
+	if client != "" {
+		return client, true
+	}
+
+	if realIP, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return realIP.Unmap().String(), true
+	}
+	return host, true
+}
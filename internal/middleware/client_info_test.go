@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"github.com/yourorg/anonymous-support/internal/pkg/geo"
+)
+
+func TestClientIPTrustsOnlyConfiguredProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		remoteAddr  string
+		forwarded   string
+		realIP      string
+		wantIP      string
+		wantProxied bool
+	}{
+		{"direct client ignores headers", "203.0.113.9:4000", "198.51.100.1", "198.51.100.2", "203.0.113.9", false},
+		{"proxy forwards client", "10.1.2.3:4000", "198.51.100.1", "", "198.51.100.1", true},
+		{"spoofed entries left of the client are skipped", "10.1.2.3:4000", "1.1.1.1, 198.51.100.1, 10.9.9.9", "", "198.51.100.1", true},
+		{"single trusted address", "192.0.2.1:4000", "198.51.100.1", "", "198.51.100.1", true},
+		{"X-Real-IP from a proxy", "10.1.2.3:4000", "", "198.51.100.2", "198.51.100.2", true},
+		{"proxy without headers", "10.1.2.3:4000", "", "", "10.1.2.3", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			ip, proxied := proxies.clientIP(r)
+			if ip != tt.wantIP || proxied != tt.wantProxied {
+				t.Errorf("clientIP = %q, %v; want %q, %v", ip, proxied, tt.wantIP, tt.wantProxied)
+			}
+		})
+	}
+}
+
+func TestClientInfoIgnoresHeadersWithoutTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var info fingerprint.ClientInfo
+	handler := ClientInfoMiddleware(geo.Headers{Country: "CF-IPCountry"}, proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info = fingerprint.FromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "203.0.113.9:4000"
+	r.Header.Set("X-Forwarded-For", "198.51.100.1")
+	r.Header.Set("X-Real-IP", "198.51.100.2")
+	r.Header.Set("CF-IPCountry", "NZ")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if info.IP != "203.0.113.9" || info.Location.Country != "" {
+		t.Errorf("client info = %+v; want the peer address and no location", info)
+	}
+}
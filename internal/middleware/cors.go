@@ -2,21 +2,97 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func CORSMiddleware() func(http.Handler) http.Handler {
+// corsAllowedHeaders are the request headers browsers may send cross-origin:
+// auth, the Connect and gRPC-Web protocol headers, and our own client headers
+var corsAllowedHeaders = []string{
+	"Authorization",
+	"Content-Type",
+	"Connect-Protocol-Version",
+	"Connect-Timeout-Ms",
+	"Connect-Accept-Encoding",
+	"Connect-Content-Encoding",
+	"Grpc-Timeout",
+	"If-None-Match",
+	"X-Grpc-Web",
+	"X-User-Agent",
+	"X-Request-ID",
+	"X-Device-Fingerprint",
+}
+
+// corsExposedHeaders are the response headers browser clients may read
+var corsExposedHeaders = []string{
+	"Grpc-Status",
+	"Grpc-Message",
+	"Grpc-Status-Details-Bin",
+	"ETag",
+	"Deprecation",
+	"Sunset",
+	"Link",
+	"X-Request-ID",
+	"X-Total-Count",
+	"Retry-After",
+}
+
+// CORSConfig configures cross-origin requests
+type CORSConfig struct {
+	AllowedOrigins []string      // "*" allows any origin
+	AllowedMethods []string      // Methods a preflight may ask for
+	MaxAge         time.Duration // How long browsers may cache a preflight result
+}
+
+// CORSMiddleware answers preflight requests and adds CORS headers for the
+// configured origins. Requests from other origins get no CORS headers, so
+// browsers refuse to hand them the response.
+func CORSMiddleware(cfg CORSConfig) Middleware {
+	allowedOrigins := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		allowedOrigins[strings.ToLower(strings.TrimRight(origin, "/"))] = true
+	}
+	allowedMethods := make(map[string]bool, len(cfg.AllowedMethods))
+	for _, method := range cfg.AllowedMethods {
+		allowedMethods[strings.ToUpper(method)] = true
+	}
+
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	allowHeaders := strings.Join(corsAllowedHeaders, ", ")
+	exposeHeaders := strings.Join(corsExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
-			w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count")
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
+			w.Header().Add("Vary", "Origin")
+			allowed := allowedOrigins["*"] || allowedOrigins[strings.ToLower(origin)]
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				if allowed && allowedMethods[strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))] {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Set("Access-Control-Allow-Methods", methods)
+					w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+					w.Header().Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
 				return
 			}
 
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+
 			next.ServeHTTP(w, r)
 		})
 	}
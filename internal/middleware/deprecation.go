@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecation describes a deprecated API version
+type Deprecation struct {
+	Since     time.Time // When the version was deprecated
+	Sunset    time.Time // When it stops being served; zero until scheduled
+	Successor string    // Path of the version replacing it
+}
+
+// DeprecationMiddleware announces a deprecated API version on every response
+// with the Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers. Connect
+// clients see them as response metadata.
+func DeprecationMiddleware(d Deprecation) Middleware {
+	deprecation := "@" + strconv.FormatInt(d.Since.Unix(), 10)
+	sunset := ""
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+	link := ""
+	if d.Successor != "" {
+		link = "<" + d.Successor + `>; rel="successor-version"`
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			if sunset != "" {
+				w.Header().Set("Sunset", sunset)
+			}
+			if link != "" {
+				w.Header().Add("Link", link)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
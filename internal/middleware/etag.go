@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// etagResponseWriter buffers a response so its ETag can be computed before
+// anything is sent
+type etagResponseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *etagResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *etagResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+}
+
+func (rw *etagResponseWriter) Write(b []byte) (int, error) {
+	return rw.body.Write(b)
+}
+
+// ETagMiddleware adds an ETag to successful responses of the given unary
+// Connect procedures, and answers requests whose If-None-Match matches it with
+// 304 Not Modified and no body. Responses depend on the caller, so they're
+// marked private and must be revalidated. gRPC calls are passed through, as
+// their status is sent in trailers.
+func ETagMiddleware(procedures ...string) Middleware {
+	cacheable := make(map[string]bool, len(procedures))
+	for _, procedure := range procedures {
+		cacheable[procedure] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cacheable[r.URL.Path] || strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := &etagResponseWriter{header: http.Header{}, statusCode: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			header := w.Header()
+			for key, values := range rw.header {
+				header[key] = values
+			}
+			if rw.statusCode != http.StatusOK {
+				w.WriteHeader(rw.statusCode)
+				_, _ = w.Write(rw.body.Bytes())
+				return
+			}
+
+			etag := computeETag(header.Get("Content-Encoding"), rw.body.Bytes())
+			header.Set("ETag", etag)
+			if header.Get("Cache-Control") == "" {
+				header.Set("Cache-Control", "private, no-cache")
+			}
+			header.Add("Vary", "Authorization")
+
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				header.Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(rw.body.Bytes())
+		})
+	}
+}
+
+// computeETag returns a weak ETag for the encoded body. It's weak because a
+// response compressed differently has another tag but the same content.
+func computeETag(contentEncoding string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(contentEncoding))
+	h.Write([]byte{0})
+	h.Write(body)
+	return `W/"` + base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using
+// weak comparison as RFC 9110 requires
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
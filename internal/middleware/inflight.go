@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// InFlightTracker counts HTTP requests that are still being served, so
+// shutdown can report what it is waiting for
+type InFlightTracker struct {
+	count atomic.Int64
+}
+
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{}
+}
+
+// Middleware tracks each request until its handler returns
+func (t *InFlightTracker) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.count.Add(1)
+			metrics.HTTPRequestsInFlight.Inc()
+			defer func() {
+				t.count.Add(-1)
+				metrics.HTTPRequestsInFlight.Dec()
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Count returns the number of requests being served
+func (t *InFlightTracker) Count() int64 {
+	return t.count.Load()
+}
+
+// Report logs the in-flight count every interval while requests remain,
+// until ctx is done
+func (t *InFlightTracker) Report(ctx context.Context, logger *zap.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n := t.Count(); n > 0 {
+			logger.Info("Waiting for in-flight requests", zap.Int64("in_flight", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/i18n"
+)
+
+// LocaleMiddleware negotiates the response language from the Accept-Language
+// header and stores it in the request context for i18n.FromContext
+func LocaleMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tag := i18n.Match(r.Header.Get("Accept-Language"))
+
+			w.Header().Set("Content-Language", tag.String())
+			w.Header().Add("Vary", "Accept-Language")
+
+			next.ServeHTTP(w, r.WithContext(i18n.NewContext(r.Context(), tag)))
+		})
+	}
+}
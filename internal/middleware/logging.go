@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"go.uber.org/zap"
 )
 
@@ -29,7 +30,8 @@ func LoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 
 			duration := time.Since(start)
 
-			logger.Info("request",
+			// Trace and span IDs link the line to the request's trace
+			tracing.Logger(r.Context(), logger).Info("request",
 				zap.String("request_id", requestID),
 				zap.String("method", r.Method),
 				zap.String("path", r.URL.Path),
@@ -8,8 +8,14 @@ import (
 	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
 )
 
-// MetricsMiddleware records HTTP request metrics
-func MetricsMiddleware() func(http.Handler) http.Handler {
+// unmatchedRoute labels requests no route handles, so scanners probing random
+// paths cannot grow the metric's label set
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware records HTTP request metrics labelled by the mux pattern
+// that serves the request, e.g. "/post.v1.PostService/" or "/health/ready",
+// rather than the raw path
+func MetricsMiddleware(routes *http.ServeMux) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -19,9 +25,18 @@ func MetricsMiddleware() func(http.Handler) http.Handler {
 			next.ServeHTTP(rw, r)
 
 			duration := time.Since(start).Seconds()
+			route := routeOf(routes, r)
 
-			metrics.HTTPRequestsTotal.WithLabelValues(r.Method, r.URL.Path, strconv.Itoa(rw.statusCode)).Inc()
-			metrics.HTTPRequestDuration.WithLabelValues(r.Method, r.URL.Path).Observe(duration)
+			metrics.HTTPRequestsTotal.WithLabelValues(r.Method, route, strconv.Itoa(rw.statusCode)).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(r.Method, route).Observe(duration)
 		})
 	}
 }
+
+// routeOf returns the pattern routes would serve r with
+func routeOf(routes *http.ServeMux, r *http.Request) string {
+	if _, pattern := routes.Handler(r); pattern != "" {
+		return pattern
+	}
+	return unmatchedRoute
+}
@@ -1,29 +1,97 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/yourorg/anonymous-support/internal/repository/redis"
+	"connectrpc.com/connect"
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"github.com/yourorg/anonymous-support/internal/pkg/ratelimit"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"go.uber.org/zap"
 )
 
-func RateLimitMiddleware(realtimeRepo *redis.RealtimeRepository, action string, limit int, window time.Duration) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			userID := GetUserIDFromContext(r.Context())
-			if userID == "" {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
-				return
+// RateLimitPolicy decides which limits apply to a request. Limits are keyed by
+// the caller's user ID, or by client IP for unauthenticated callers.
+type RateLimitPolicy struct {
+	Default ratelimit.Rule // Applies to every request
+	// Procedures adds limits for a Connect procedure ("/post.v1.PostService/CreatePost")
+	// or every procedure of a service ("/auth.v1.AuthService/")
+	Procedures map[string]ratelimit.Rule
+	// Limits, when set, returns the current limit of a rule by name, so limits
+	// can change without a restart. Rules it returns 0 for keep their own limit.
+	Limits func(name string) int
+}
+
+// rulesFor returns the limits that apply to a procedure or path
+func (p RateLimitPolicy) rulesFor(procedure string) []ratelimit.Rule {
+	rules := []ratelimit.Rule{p.Default}
+	if rule, ok := p.Procedures[procedure]; ok {
+		rules = append(rules, rule)
+	}
+	if i := strings.LastIndex(procedure, "/"); i > 0 {
+		if rule, ok := p.Procedures[procedure[:i+1]]; ok {
+			rules = append(rules, rule)
+		}
+	}
+	if p.Limits != nil {
+		for i := range rules {
+			if limit := p.Limits(rules[i].Name); limit > 0 {
+				rules[i].Limit = limit
 			}
+		}
+	}
+	return rules
+}
 
-			allowed, err := realtimeRepo.CheckRateLimit(r.Context(), userID, action, limit, window)
-			if err != nil {
-				http.Error(w, "rate limit check failed", http.StatusInternalServerError)
-				return
+// rateLimitKey identifies the caller a limit is counted against
+func rateLimitKey(ctx context.Context) string {
+	if userID := GetUserIDFromContext(ctx); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + fingerprint.FromContext(ctx).IP
+}
+
+// checkRateLimits returns the longest wait among the limits the request
+// exceeds, or zero if it is allowed. Limiter errors fail open so an unhealthy
+// Redis degrades protection rather than availability.
+func checkRateLimits(ctx context.Context, limiter *ratelimit.Limiter, policy RateLimitPolicy, procedure string, logger *zap.Logger) time.Duration {
+	key := rateLimitKey(ctx)
+	var retryAfter time.Duration
+	for _, rule := range policy.rulesFor(procedure) {
+		result, err := limiter.Allow(ctx, rule, key)
+		if err != nil {
+			tracing.Logger(ctx, logger).Warn("Rate limit check failed", zap.String("rule", rule.Name), zap.Error(err))
+			continue
+		}
+		if !result.Allowed {
+			metrics.RateLimitedRequestsTotal.WithLabelValues(rule.Name).Inc()
+			if result.RetryAfter > retryAfter {
+				retryAfter = result.RetryAfter
 			}
+		}
+	}
+	return retryAfter
+}
+
+// retryAfterSeconds formats a wait for the Retry-After header, rounding up
+func retryAfterSeconds(wait time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(wait.Seconds())))
+}
 
-			if !allowed {
-				w.Header().Set("Retry-After", window.String())
+// RateLimitMiddleware rejects HTTP requests over the policy's limits with 429
+// and a Retry-After header. It must run after ClientInfoMiddleware.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, policy RateLimitPolicy, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if wait := checkRateLimits(r.Context(), limiter, policy, r.URL.Path, logger); wait > 0 {
+				w.Header().Set("Retry-After", retryAfterSeconds(wait))
 				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
 				return
 			}
@@ -32,3 +100,57 @@ func RateLimitMiddleware(realtimeRepo *redis.RealtimeRepository, action string,
 		})
 	}
 }
+
+// RateLimitInterceptor rejects Connect calls over the policy's limits with
+// CodeResourceExhausted and Retry-After metadata
+type RateLimitInterceptor struct {
+	limiter *ratelimit.Limiter
+	policy  RateLimitPolicy
+	logger  *zap.Logger
+}
+
+// NewRateLimitInterceptor creates a rate limit interceptor
+func NewRateLimitInterceptor(limiter *ratelimit.Limiter, policy RateLimitPolicy, logger *zap.Logger) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		limiter: limiter,
+		policy:  policy,
+		logger:  logger,
+	}
+}
+
+// WrapUnary rate limits unary calls
+func (i *RateLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.check(ctx, req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves outgoing streams alone
+func (i *RateLimitInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler rate limits opening a stream
+func (i *RateLimitInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.check(ctx, conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (i *RateLimitInterceptor) check(ctx context.Context, procedure string) error {
+	wait := checkRateLimits(ctx, i.limiter, i.policy, procedure, i.logger)
+	if wait == 0 {
+		return nil
+	}
+
+	err := connect.NewError(connect.CodeResourceExhausted,
+		fmt.Errorf("rate limit exceeded, retry in %s seconds", retryAfterSeconds(wait)))
+	err.Meta().Set("Retry-After", retryAfterSeconds(wait))
+	return err
+}
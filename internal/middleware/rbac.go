@@ -30,7 +30,7 @@ func RBACMiddleware(requiredRole domain.Role) func(http.Handler) http.Handler {
 
 // GetUserRoleFromContext retrieves user role from context
 func GetUserRoleFromContext(ctx context.Context) string {
-	if role, ok := ctx.Value("user_role").(string); ok {
+	if role, ok := ctx.Value(UserRoleKey).(string); ok {
 		return role
 	}
 	return ""
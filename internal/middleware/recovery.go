@@ -1,14 +1,18 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"runtime/debug"
 
+	reporting "github.com/yourorg/anonymous-support/internal/pkg/errors"
 	"go.uber.org/zap"
 )
 
-// RecoveryMiddleware recovers from panics and logs the error
-func RecoveryMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+// RecoveryMiddleware recovers from panics, logs the error and sends it to the
+// error reporter
+func RecoveryMiddleware(reporter reporting.Reporter, logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
@@ -22,6 +26,10 @@ func RecoveryMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 						zap.Any("error", err),
 						zap.String("stack", string(debug.Stack())),
 					)
+					reportError(r.Context(), reporter, fmt.Errorf("panic: %v", err), map[string]string{
+						"method": r.Method,
+						"path":   r.URL.Path,
+					})
 
 					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 				}
@@ -31,3 +39,18 @@ func RecoveryMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// reportError sends err to reporter, if any, tagged with the request and
+// caller in ctx
+func reportError(ctx context.Context, reporter reporting.Reporter, err error, tags map[string]string) {
+	if reporter == nil {
+		return
+	}
+	if requestID := GetRequestID(ctx); requestID != "" {
+		tags["request_id"] = requestID
+	}
+	if userID := GetUserIDFromContext(ctx); userID != "" {
+		tags["user_id"] = userID
+	}
+	reporter.ReportError(ctx, err, tags)
+}
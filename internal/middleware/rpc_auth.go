@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/yourorg/anonymous-support/internal/pkg/jwt"
+)
+
+var (
+	errMissingToken = errors.New("missing or malformed authorization header")
+	errInvalidToken = errors.New("invalid or expired token")
+)
+
+// RPCAuthInterceptor validates the bearer token of every RPC and stores the
+// caller's identity and role in the context. Public procedures also accept
+// callers without a valid token, who reach the handler unauthenticated.
+// Service principals authenticated by ServiceAuthMiddleware need no token.
+type RPCAuthInterceptor struct {
+	jwtManager *jwt.Manager
+	public     map[string]bool
+}
+
+// NewRPCAuthInterceptor creates a new RPC auth interceptor. publicProcedures
+// are full procedure names such as "/auth.v1.AuthService/Login".
+func NewRPCAuthInterceptor(jwtManager *jwt.Manager, publicProcedures ...string) *RPCAuthInterceptor {
+	public := make(map[string]bool, len(publicProcedures))
+	for _, procedure := range publicProcedures {
+		public[procedure] = true
+	}
+	return &RPCAuthInterceptor{jwtManager: jwtManager, public: public}
+}
+
+// WrapUnary authenticates a unary RPC before calling the handler
+func (i *RPCAuthInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx, err := i.authenticate(ctx, req.Spec().Procedure, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves client streams unchanged
+func (i *RPCAuthInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler authenticates a streaming RPC before calling the handler
+func (i *RPCAuthInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.authenticate(ctx, conn.Spec().Procedure, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (i *RPCAuthInterceptor) authenticate(ctx context.Context, procedure string, header http.Header) (context.Context, error) {
+	if _, ok := GetServicePrincipal(ctx); ok {
+		return ctx, nil
+	}
+
+	token, ok := bearerToken(header.Get("Authorization"))
+	if !ok {
+		if i.public[procedure] {
+			return ctx, nil
+		}
+		return ctx, connect.NewError(connect.CodeUnauthenticated, errMissingToken)
+	}
+
+	claims, err := i.jwtManager.ValidateAccessToken(token)
+	if err != nil {
+		if i.public[procedure] {
+			return ctx, nil
+		}
+		return ctx, connect.NewError(connect.CodeUnauthenticated, errInvalidToken)
+	}
+
+	return WithClaims(ctx, claims), nil
+}
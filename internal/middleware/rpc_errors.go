@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"connectrpc.com/connect"
+	apperrors "github.com/yourorg/anonymous-support/internal/errors"
+	reporting "github.com/yourorg/anonymous-support/internal/pkg/errors"
+	"github.com/yourorg/anonymous-support/internal/pkg/i18n"
+	"github.com/yourorg/anonymous-support/internal/pkg/redact"
+	"github.com/yourorg/anonymous-support/internal/pkg/retry"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"go.uber.org/zap"
+)
+
+// RPCErrorInterceptor maps handler errors onto Connect errors in one place.
+// An AppError anywhere in the chain, even one a handler wrapped in its own
+// connect.Error, is returned with its Connect code and client-safe message,
+// and errors that are not Connect errors become Internal. Calls a circuit
+// breaker rejected become Unavailable with a Retry-After header. Internal
+// details such as driver errors, DSNs and stack traces are logged, never returned.
+// Server-side failures are also sent to the error reporter. Translatable
+// errors are returned in the locale negotiated by LocaleMiddleware.
+type RPCErrorInterceptor struct {
+	reporter reporting.Reporter
+	logger   *zap.Logger
+}
+
+// NewRPCErrorInterceptor creates a new RPC error interceptor
+func NewRPCErrorInterceptor(reporter reporting.Reporter, logger *zap.Logger) *RPCErrorInterceptor {
+	return &RPCErrorInterceptor{reporter: reporter, logger: logger}
+}
+
+// WrapUnary converts the error of a unary RPC handler
+func (i *RPCErrorInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		if err != nil {
+			return nil, i.convert(ctx, req.Spec().Procedure, err)
+		}
+		return resp, nil
+	}
+}
+
+// WrapStreamingClient leaves client streams unchanged
+func (i *RPCErrorInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler converts the error of a streaming RPC handler
+func (i *RPCErrorInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := next(ctx, conn); err != nil {
+			return i.convert(ctx, conn.Spec().Procedure, err)
+		}
+		return nil
+	}
+}
+
+func (i *RPCErrorInterceptor) convert(ctx context.Context, procedure string, err error) error {
+	logger := tracing.Logger(ctx, i.logger).With(zap.String("procedure", procedure))
+
+	// An open breaker already logged the outage, so rejections aren't reported
+	var openErr *retry.OpenError
+	if errors.As(err, &openErr) {
+		logger.Debug("RPC rejected by open circuit breaker", zap.String("breaker", openErr.Breaker))
+		appErr := apperrors.NewUnavailableError("Service", err)
+		appErr.RetryAfter = openErr.RetryAfter
+		return appErr.ToConnectError()
+	}
+
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		if appErr.ConnectCode == connect.CodeInternal || appErr.ConnectCode == connect.CodeUnavailable {
+			logger.Error("RPC failed", appErr.LogFields()...)
+			reportError(ctx, i.reporter, err, map[string]string{"procedure": procedure, "code": appErr.Code})
+		}
+		return appErr.ToConnectError()
+	}
+
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		if !exposesInternals(connectErr) {
+			return localized(ctx, connectErr)
+		}
+		logger.Error("RPC failed", zap.String("code", connectErr.Code().String()), zap.Error(connectErr))
+		reportError(ctx, i.reporter, err, map[string]string{"procedure": procedure, "code": connectErr.Code().String()})
+		return withoutDetails(connectErr)
+	}
+
+	logger.Error("RPC failed", zap.Error(err))
+	reportError(ctx, i.reporter, err, map[string]string{"procedure": procedure})
+	return apperrors.WrapError(err).ToConnectError()
+}
+
+// exposesInternals reports whether err's message must not reach the client.
+// Server-side failures never explain themselves to clients; other codes keep
+// their message unless it carries infrastructure details.
+func exposesInternals(err *connect.Error) bool {
+	switch err.Code() {
+	case connect.CodeInternal, connect.CodeUnknown, connect.CodeDataLoss:
+		return err.Message() != errInternal.Error()
+	}
+	return redact.LeaksInternals(err.Message())
+}
+
+// localized returns err with its message in the request's locale when it
+// wraps an *i18n.Error, keeping its metadata
+func localized(ctx context.Context, err *connect.Error) *connect.Error {
+	var i18nErr *i18n.Error
+	if !errors.As(err, &i18nErr) {
+		return err
+	}
+
+	translated := connect.NewError(err.Code(), errors.New(i18nErr.Localize(i18n.FromContext(ctx))))
+	for key, values := range err.Meta() {
+		translated.Meta()[key] = values
+	}
+	return translated
+}
+
+// withoutDetails returns err with a generic message for its code, keeping its
+// metadata
+func withoutDetails(err *connect.Error) *connect.Error {
+	message := strings.ReplaceAll(err.Code().String(), "_", " ")
+	switch err.Code() {
+	case connect.CodeInternal, connect.CodeUnknown, connect.CodeDataLoss:
+		message = errInternal.Error()
+	case connect.CodeUnavailable:
+		message = "service temporarily unavailable"
+	}
+
+	safe := connect.NewError(err.Code(), errors.New(message))
+	for key, values := range err.Meta() {
+		safe.Meta()[key] = values
+	}
+	return safe
+}
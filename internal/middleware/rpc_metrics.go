@@ -6,14 +6,18 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"github.com/yourorg/anonymous-support/internal/pkg/slo"
 )
 
-// RPCMetricsInterceptor captures metrics for all RPC calls
-type RPCMetricsInterceptor struct{}
+// RPCMetricsInterceptor captures metrics for all RPC calls and feeds the SLO
+// tracker, if any
+type RPCMetricsInterceptor struct {
+	slo *slo.Tracker
+}
 
 // NewRPCMetricsInterceptor creates a new RPC metrics interceptor
-func NewRPCMetricsInterceptor() *RPCMetricsInterceptor {
-	return &RPCMetricsInterceptor{}
+func NewRPCMetricsInterceptor(tracker *slo.Tracker) *RPCMetricsInterceptor {
+	return &RPCMetricsInterceptor{slo: tracker}
 }
 
 // WrapUnary wraps a unary RPC handler with metrics collection
@@ -30,20 +34,12 @@ func (i *RPCMetricsInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryF
 		resp, err := next(ctx, req)
 
 		// Record metrics
-		duration := time.Since(start).Seconds()
-		code := "OK"
-		if err != nil {
-			if connectErr, ok := err.(*connect.Error); ok {
-				code = connectErr.Code().String()
-			} else {
-				code = "Unknown"
-			}
-			metrics.RPCErrorsTotal.WithLabelValues(service, method, code).Inc()
+		duration := time.Since(start)
+		code := recordRPC(service, method, duration, err)
+		if i.slo != nil {
+			i.slo.Observe(service, isServerError(code), duration)
 		}
 
-		metrics.RPCRequestsTotal.WithLabelValues(service, method, code).Inc()
-		metrics.RPCRequestDuration.WithLabelValues(service, method).Observe(duration)
-
 		return resp, err
 	}
 }
@@ -70,24 +66,43 @@ func (i *RPCMetricsInterceptor) WrapStreamingHandler(next connect.StreamingHandl
 
 		err := next(ctx, conn)
 
-		duration := time.Since(start).Seconds()
-		code := "OK"
-		if err != nil {
-			if connectErr, ok := err.(*connect.Error); ok {
-				code = connectErr.Code().String()
-			} else {
-				code = "Unknown"
-			}
-			metrics.RPCErrorsTotal.WithLabelValues(service, method, code).Inc()
+		code := recordRPC(service, method, time.Since(start), err)
+		if i.slo != nil {
+			i.slo.ObserveStream(service, isServerError(code))
 		}
 
-		metrics.RPCRequestsTotal.WithLabelValues(service, method, code).Inc()
-		metrics.RPCRequestDuration.WithLabelValues(service, method).Observe(duration)
-
 		return err
 	}
 }
 
+// recordRPC records the request, error and duration metrics of an RPC and
+// returns its status code label
+func recordRPC(service, method string, duration time.Duration, err error) connect.Code {
+	codeLabel := "OK"
+	var code connect.Code
+	if err != nil {
+		code = connect.CodeOf(err)
+		codeLabel = code.String()
+		metrics.RPCErrorsTotal.WithLabelValues(service, method, codeLabel).Inc()
+	}
+
+	metrics.RPCRequestsTotal.WithLabelValues(service, method, codeLabel).Inc()
+	metrics.RPCRequestDuration.WithLabelValues(service, method).Observe(duration.Seconds())
+	return code
+}
+
+// isServerError reports whether an RPC status counts against availability.
+// Client mistakes such as invalid arguments or missing auth do not. The
+// recording rules in k8s/prometheus-rules.yaml use the same codes.
+func isServerError(code connect.Code) bool {
+	switch code {
+	case connect.CodeUnknown, connect.CodeInternal, connect.CodeUnavailable,
+		connect.CodeDataLoss, connect.CodeDeadlineExceeded:
+		return true
+	}
+	return false
+}
+
 // extractServiceName extracts the service name from the procedure path
 // e.g., "/auth.v1.AuthService/Login" -> "auth.v1.AuthService"
 func extractServiceName(procedure string) string {
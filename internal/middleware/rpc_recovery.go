@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"connectrpc.com/connect"
+	reporting "github.com/yourorg/anonymous-support/internal/pkg/errors"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"go.uber.org/zap"
+)
+
+var errInternal = errors.New("internal server error")
+
+// RPCRecoveryInterceptor turns a panicking RPC handler into an Internal error
+// instead of dropping the connection, and logs and reports the panic
+type RPCRecoveryInterceptor struct {
+	reporter reporting.Reporter
+	logger   *zap.Logger
+}
+
+// NewRPCRecoveryInterceptor creates a new RPC recovery interceptor
+func NewRPCRecoveryInterceptor(reporter reporting.Reporter, logger *zap.Logger) *RPCRecoveryInterceptor {
+	return &RPCRecoveryInterceptor{reporter: reporter, logger: logger}
+}
+
+// WrapUnary recovers panics in a unary RPC handler
+func (i *RPCRecoveryInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (resp connect.AnyResponse, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				i.logPanic(ctx, req.Spec().Procedure, r)
+				resp, err = nil, connect.NewError(connect.CodeInternal, errInternal)
+			}
+		}()
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves client streams unchanged
+func (i *RPCRecoveryInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler recovers panics in a streaming RPC handler
+func (i *RPCRecoveryInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				i.logPanic(ctx, conn.Spec().Procedure, r)
+				err = connect.NewError(connect.CodeInternal, errInternal)
+			}
+		}()
+		return next(ctx, conn)
+	}
+}
+
+func (i *RPCRecoveryInterceptor) logPanic(ctx context.Context, procedure string, recovered any) {
+	tracing.Logger(ctx, i.logger).Error("panic recovered",
+		zap.String("request_id", GetRequestID(ctx)),
+		zap.String("procedure", procedure),
+		zap.Any("error", recovered),
+		zap.String("stack", string(debug.Stack())),
+	)
+	reportError(ctx, i.reporter, fmt.Errorf("panic: %v", recovered), map[string]string{"procedure": procedure})
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const rpcTracerName = "connectrpc.com/connect"
+
+// RPCTracingInterceptor records a server span for every RPC as a child of
+// the HTTP request span, tagged with the Connect error code on failure
+type RPCTracingInterceptor struct{}
+
+// NewRPCTracingInterceptor creates a new RPC tracing interceptor
+func NewRPCTracingInterceptor() *RPCTracingInterceptor {
+	return &RPCTracingInterceptor{}
+}
+
+// WrapUnary wraps a unary RPC handler with a span
+func (i *RPCTracingInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx, span := startRPCSpan(ctx, req.Spec().Procedure)
+		resp, err := next(ctx, req)
+		endRPCSpan(span, err)
+		return resp, err
+	}
+}
+
+// WrapStreamingClient leaves client streams untraced
+func (i *RPCTracingInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler wraps a streaming server RPC handler with a span
+func (i *RPCTracingInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, span := startRPCSpan(ctx, conn.Spec().Procedure)
+		err := next(ctx, conn)
+		endRPCSpan(span, err)
+		return err
+	}
+}
+
+func startRPCSpan(ctx context.Context, procedure string) (context.Context, trace.Span) {
+	return tracing.StartSpan(ctx, rpcTracerName, procedure,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			tracing.AttrRPCSystem.String("connect_rpc"),
+			tracing.AttrRPCService.String(extractServiceName(procedure)),
+			tracing.AttrRPCMethod.String(extractMethodName(procedure)),
+		))
+}
+
+func endRPCSpan(span trace.Span, err error) {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		span.SetAttributes(attribute.String("rpc.connect_rpc.error_code", connectErr.Code().String()))
+	}
+	tracing.EndSpan(span, err)
+}
@@ -1,35 +1,49 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
+// SecurityHeadersConfig configures the security headers added to responses
+type SecurityHeadersConfig struct {
+	HSTSMaxAge     time.Duration // Strict-Transport-Security max-age; 0 omits the header
+	FrameAncestors []string      // Sources allowed to frame responses; none when empty
+}
+
 // SecurityMiddleware adds security headers to responses
-func SecurityMiddleware() Middleware {
+func SecurityMiddleware(cfg SecurityHeadersConfig) Middleware {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", int(cfg.HSTSMaxAge.Seconds()))
+	}
+
+	// The API only serves RPC payloads, so nothing needs to load sub-resources
+	frameAncestors := "'none'"
+	if len(cfg.FrameAncestors) > 0 {
+		frameAncestors = strings.Join(cfg.FrameAncestors, " ")
+	}
+	csp := "default-src 'none'; frame-ancestors " + frameAncestors
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Prevent clickjacking attacks
-			w.Header().Set("X-Frame-Options", "DENY")
+			// Prevent clickjacking attacks; frame-ancestors supersedes this in
+			// browsers that support CSP
+			if len(cfg.FrameAncestors) == 0 {
+				w.Header().Set("X-Frame-Options", "DENY")
+			}
 
 			// Prevent MIME type sniffing
 			w.Header().Set("X-Content-Type-Options", "nosniff")
 
-			// Enable browser XSS protection
-			w.Header().Set("X-XSS-Protection", "1; mode=block")
-
 			// Strict Transport Security (HSTS)
-			// max-age=31536000 (1 year), includeSubDomains
-			w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+			if hsts != "" {
+				w.Header().Set("Strict-Transport-Security", hsts)
+			}
 
 			// Content Security Policy
-			// Adjust based on your application needs
-			csp := "default-src 'self'; " +
-				"script-src 'self' 'unsafe-inline' 'unsafe-eval'; " +
-				"style-src 'self' 'unsafe-inline'; " +
-				"img-src 'self' data: https:; " +
-				"font-src 'self' data:; " +
-				"connect-src 'self'; " +
-				"frame-ancestors 'none'"
 			w.Header().Set("Content-Security-Policy", csp)
 
 			// Referrer Policy
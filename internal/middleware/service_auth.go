@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+)
+
+const ServicePrincipalKey contextKey = "service_principal"
+
+// ServiceAuthMiddleware authenticates trusted internal clients by the
+// X-API-Key header or a verified TLS client certificate, and stores their
+// principal and role in the context so RBAC checks apply to them as to users.
+// Requests with neither pass through unchanged; invalid credentials are
+// rejected rather than silently downgraded to an anonymous caller.
+func ServiceAuthMiddleware(auth *authz.ServiceAuthenticator) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				principal authz.ServicePrincipal
+				ok        bool
+				method    string
+			)
+			switch {
+			case r.Header.Get("X-API-Key") != "":
+				method = "service_api_key"
+				principal, ok = auth.AuthenticateAPIKey(r.Header.Get("X-API-Key"))
+			case r.TLS != nil && len(r.TLS.VerifiedChains) > 0:
+				method = "service_certificate"
+				principal, ok = auth.AuthenticateCertificate(r.TLS.VerifiedChains[0][0])
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !ok {
+				metrics.AuthAttemptsTotal.WithLabelValues(method, "failure").Inc()
+				http.Error(w, "invalid service credentials", http.StatusUnauthorized)
+				return
+			}
+			metrics.AuthAttemptsTotal.WithLabelValues(method, "success").Inc()
+
+			next.ServeHTTP(w, r.WithContext(WithServicePrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// WithServicePrincipal returns ctx carrying a service principal and its role
+func WithServicePrincipal(ctx context.Context, principal authz.ServicePrincipal) context.Context {
+	ctx = context.WithValue(ctx, ServicePrincipalKey, principal)
+	return context.WithValue(ctx, UserRoleKey, string(principal.Role))
+}
+
+// GetServicePrincipal returns the internal client making the request, if any
+func GetServicePrincipal(ctx context.Context) (authz.ServicePrincipal, bool) {
+	principal, ok := ctx.Value(ServicePrincipalKey).(authz.ServicePrincipal)
+	return principal, ok
+}
@@ -4,18 +4,23 @@ import (
 	"net/http"
 
 	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
-// TracingMiddleware adds OpenTelemetry tracing to HTTP requests
+// TracingMiddleware adds OpenTelemetry tracing to HTTP requests, continuing
+// the caller's trace when the request carries W3C trace context headers
 func TracingMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
 			// Start a new span for this request
 			ctx, span := tracing.StartSpan(
-				r.Context(),
+				ctx,
 				"http-server",
 				r.Method+" "+r.URL.Path,
 				trace.WithSpanKind(trace.SpanKindServer),
@@ -2,7 +2,6 @@ package abuse
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
@@ -203,47 +202,3 @@ type UserHistory struct {
 	FailedLoginCount   int
 	AccountAge         time.Duration
 }
-
-// RateLimiter manages rate limiting for abuse prevention
-type RateLimiter struct {
-	limits map[string]*RateLimit
-}
-
-// RateLimit tracks rate limit state for a resource
-type RateLimit struct {
-	Count       int
-	ResetAt     time.Time
-	MaxRequests int
-	Window      time.Duration
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter() *RateLimiter {
-	return &RateLimiter{
-		limits: make(map[string]*RateLimit),
-	}
-}
-
-// CheckLimit checks if a request is within rate limits
-func (r *RateLimiter) CheckLimit(key string, maxRequests int, window time.Duration) (bool, error) {
-	limit, exists := r.limits[key]
-	now := time.Now()
-
-	if !exists || now.After(limit.ResetAt) {
-		// Initialize new limit window
-		r.limits[key] = &RateLimit{
-			Count:       1,
-			ResetAt:     now.Add(window),
-			MaxRequests: maxRequests,
-			Window:      window,
-		}
-		return true, nil
-	}
-
-	if limit.Count >= limit.MaxRequests {
-		return false, fmt.Errorf("rate limit exceeded, resets at %s", limit.ResetAt.Format(time.RFC3339))
-	}
-
-	limit.Count++
-	return true, nil
-}
@@ -0,0 +1,162 @@
+// Package audit records security-relevant events to the audit log off the
+// request path
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// writeTimeout bounds a single audit log insert
+const writeTimeout = 5 * time.Second
+
+// Store persists audit log entries
+type Store interface {
+	CreateAuditLog(ctx context.Context, log *domain.AuditLog) error
+}
+
+// Event is a security-relevant action to record
+type Event struct {
+	Type       domain.AuditEventType
+	ActorID    string // Empty for unauthenticated callers and system events
+	TargetID   string
+	TargetType string
+	Action     string
+	Reason     string
+	Err        error // Set when the action failed
+}
+
+// Writer queues audit entries and writes them in the background, so a slow
+// audit store never slows down logins or moderation. Entries are dropped,
+// and counted, when the buffer is full.
+type Writer struct {
+	store   Store
+	entries chan *domain.AuditLog
+	logger  *zap.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+// NewWriter creates a writer buffering up to bufferSize entries
+func NewWriter(store Store, bufferSize int, logger *zap.Logger) *Writer {
+	return &Writer{
+		store:   store,
+		entries: make(chan *domain.AuditLog, bufferSize),
+		logger:  logger,
+		done:    make(chan struct{}),
+	}
+}
+
+// Record queues event, taking the actor's IP from the client info in ctx. A
+// nil writer records nothing.
+func (w *Writer) Record(ctx context.Context, event Event) {
+	if w == nil {
+		return
+	}
+
+	entry, err := newEntry(ctx, event)
+	if err != nil {
+		w.logger.Warn("Failed to build audit entry", zap.String("event_type", string(event.Type)), zap.Error(err))
+		return
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		metrics.AuditEventsTotal.WithLabelValues("dropped").Inc()
+		return
+	}
+
+	select {
+	case w.entries <- entry:
+	default:
+		metrics.AuditEventsTotal.WithLabelValues("dropped").Inc()
+		w.logger.Warn("Audit buffer full, dropping event", zap.String("event_type", string(event.Type)))
+	}
+}
+
+// Run writes queued entries until Close is called and the queue is drained
+func (w *Writer) Run() {
+	defer close(w.done)
+	for entry := range w.entries {
+		w.write(entry)
+	}
+}
+
+// Close stops accepting entries and waits for Run to write those already
+// queued, or for ctx to be done
+func (w *Writer) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.entries)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Writer) write(entry *domain.AuditLog) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if err := w.store.CreateAuditLog(ctx, entry); err != nil {
+		metrics.AuditEventsTotal.WithLabelValues("failed").Inc()
+		w.logger.Error("Failed to write audit entry", zap.String("event_type", string(entry.EventType)), zap.Error(err))
+		return
+	}
+	metrics.AuditEventsTotal.WithLabelValues("written").Inc()
+}
+
+func newEntry(ctx context.Context, event Event) (*domain.AuditLog, error) {
+	metadata := domain.AuditLogMetadata{Reason: event.Reason}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		metadata.Extra = map[string]interface{}{"trace_id": spanContext.TraceID().String()}
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &domain.AuditLog{
+		EventType:  event.Type,
+		ActorID:    parseID(event.ActorID),
+		ActorIP:    fingerprint.FromContext(ctx).IP,
+		TargetID:   parseID(event.TargetID),
+		TargetType: event.TargetType,
+		Action:     event.Action,
+		Metadata:   string(metadataJSON),
+		Success:    event.Err == nil,
+		CreatedAt:  time.Now(),
+	}
+	if event.Err != nil {
+		message := event.Err.Error()
+		entry.ErrorMessage = &message
+	}
+	return entry, nil
+}
+
+// parseID returns nil for an empty or malformed ID rather than failing the entry
+func parseID(id string) *uuid.UUID {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"go.uber.org/zap"
+)
+
+type memoryStore struct {
+	mu   sync.Mutex
+	logs []*domain.AuditLog
+}
+
+func (s *memoryStore) CreateAuditLog(_ context.Context, log *domain.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs = append(s.logs, log)
+	return nil
+}
+
+func TestWriter_RecordAndClose(t *testing.T) {
+	store := &memoryStore{}
+	writer := NewWriter(store, 10, zap.NewNop())
+
+	ctx := fingerprint.WithClientInfo(context.Background(), fingerprint.ClientInfo{IP: "203.0.113.7"})
+	actorID := "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	writer.Record(ctx, Event{Type: domain.AuditEventLogin, ActorID: actorID, Action: "login"})
+	writer.Record(ctx, Event{Type: domain.AuditEventLoginFailed, Action: "login", Err: errors.New("wrong password")})
+
+	go writer.Run()
+	if err := writer.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Entries recorded after Close are dropped, not written or panicking
+	writer.Record(ctx, Event{Type: domain.AuditEventLogout})
+
+	if len(store.logs) != 2 {
+		t.Fatalf("wrote %d entries, want 2", len(store.logs))
+	}
+
+	login := store.logs[0]
+	if login.ActorID == nil || login.ActorID.String() != actorID {
+		t.Errorf("ActorID = %v, want %s", login.ActorID, actorID)
+	}
+	if login.ActorIP != "203.0.113.7" || !login.Success || login.CreatedAt.IsZero() {
+		t.Errorf("login entry = %+v", login)
+	}
+
+	failed := store.logs[1]
+	if failed.ActorID != nil || failed.Success || failed.ErrorMessage == nil || *failed.ErrorMessage != "wrong password" {
+		t.Errorf("failed login entry = %+v", failed)
+	}
+}
+
+func TestWriter_DropsWhenFull(t *testing.T) {
+	store := &memoryStore{}
+	writer := NewWriter(store, 1, zap.NewNop())
+
+	writer.Record(context.Background(), Event{Type: domain.AuditEventLogin})
+	writer.Record(context.Background(), Event{Type: domain.AuditEventLogout})
+
+	go writer.Run()
+	if err := writer.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if len(store.logs) != 1 || store.logs[0].EventType != domain.AuditEventLogin {
+		t.Fatalf("wrote %+v, want only the first entry", store.logs)
+	}
+}
@@ -0,0 +1,86 @@
+package authz
+
+import (
+	"fmt"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+const (
+	// Circle-scoped permissions, granted by a member's role in that circle
+	PermissionPostInCircle   Permission = "circle:post"
+	PermissionModerateCircle Permission = "circle:moderate"
+)
+
+// CircleRolePermissions maps circle roles to what they allow within their
+// circle. Managing a circle includes changing member roles and handing over
+// ownership.
+var CircleRolePermissions = map[domain.CircleRole][]Permission{
+	domain.CircleRoleMember: {
+		PermissionReadCircle,
+		PermissionPostInCircle,
+	},
+	domain.CircleRoleModerator: {
+		PermissionReadCircle,
+		PermissionPostInCircle,
+		PermissionModerateCircle,
+	},
+	domain.CircleRoleOwner: {
+		PermissionReadCircle,
+		PermissionPostInCircle,
+		PermissionModerateCircle,
+		PermissionManageCircle,
+	},
+}
+
+// circleRoleRank orders circle roles from least to most privileged
+var circleRoleRank = map[domain.CircleRole]int{
+	domain.CircleRoleMember:    1,
+	domain.CircleRoleModerator: 2,
+	domain.CircleRoleOwner:     3,
+}
+
+// IsCircleRole reports whether role is a known circle role
+func IsCircleRole(role domain.CircleRole) bool {
+	_, ok := circleRoleRank[role]
+	return ok
+}
+
+// HasCirclePermission checks if a user may perform a circle-scoped action.
+// circleRole is the user's role in the circle, empty when they are not a
+// member. Platform roles holding PermissionManageCircle may act in every
+// circle.
+func (a *Authorizer) HasCirclePermission(role domain.Role, circleRole domain.CircleRole, permission Permission) bool {
+	if a.HasPermission(role, PermissionManageCircle) {
+		return true
+	}
+
+	for _, p := range CircleRolePermissions[circleRole] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireCirclePermission returns an error if the user may not perform the
+// circle-scoped action
+func (a *Authorizer) RequireCirclePermission(role domain.Role, circleRole domain.CircleRole, permission Permission) error {
+	if !a.HasCirclePermission(role, circleRole, permission) {
+		return fmt.Errorf("permission denied in circle: %s", permission)
+	}
+	return nil
+}
+
+// CanActOnMember checks if a user may remove, promote or demote a member with
+// targetRole. Members can only be acted on by someone with a higher circle
+// role, or by platform staff, and nobody can act on the owner.
+func (a *Authorizer) CanActOnMember(role domain.Role, circleRole, targetRole domain.CircleRole) bool {
+	if targetRole == domain.CircleRoleOwner {
+		return false
+	}
+	if a.HasPermission(role, PermissionManageCircle) {
+		return true
+	}
+	return circleRoleRank[circleRole] > circleRoleRank[targetRole]
+}
@@ -0,0 +1,59 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+func TestAuthorizerHasCirclePermission(t *testing.T) {
+	authorizer := NewAuthorizer()
+
+	tests := []struct {
+		role       domain.Role
+		circleRole domain.CircleRole
+		permission Permission
+		allowed    bool
+	}{
+		{domain.RoleUser, domain.CircleRoleMember, PermissionReadCircle, true},
+		{domain.RoleUser, domain.CircleRoleMember, PermissionModerateCircle, false},
+		{domain.RoleUser, domain.CircleRoleModerator, PermissionModerateCircle, true},
+		{domain.RoleUser, domain.CircleRoleModerator, PermissionManageCircle, false},
+		{domain.RoleUser, domain.CircleRoleOwner, PermissionManageCircle, true},
+		{domain.RoleUser, "", PermissionReadCircle, false}, // Not a member
+		{domain.RoleModerator, "", PermissionModerateCircle, true},
+		{domain.RoleAdmin, "", PermissionManageCircle, true},
+	}
+
+	for _, tt := range tests {
+		allowed := authorizer.HasCirclePermission(tt.role, tt.circleRole, tt.permission)
+		if allowed != tt.allowed {
+			t.Errorf("HasCirclePermission(%q, %q, %q) = %v, want %v", tt.role, tt.circleRole, tt.permission, allowed, tt.allowed)
+		}
+	}
+}
+
+func TestAuthorizerCanActOnMember(t *testing.T) {
+	authorizer := NewAuthorizer()
+
+	tests := []struct {
+		role       domain.Role
+		circleRole domain.CircleRole
+		targetRole domain.CircleRole
+		allowed    bool
+	}{
+		{domain.RoleUser, domain.CircleRoleModerator, domain.CircleRoleMember, true},
+		{domain.RoleUser, domain.CircleRoleModerator, domain.CircleRoleModerator, false},
+		{domain.RoleUser, domain.CircleRoleOwner, domain.CircleRoleModerator, true},
+		{domain.RoleUser, domain.CircleRoleMember, domain.CircleRoleMember, false},
+		{domain.RoleAdmin, "", domain.CircleRoleModerator, true},
+		{domain.RoleAdmin, "", domain.CircleRoleOwner, false},
+	}
+
+	for _, tt := range tests {
+		allowed := authorizer.CanActOnMember(tt.role, tt.circleRole, tt.targetRole)
+		if allowed != tt.allowed {
+			t.Errorf("CanActOnMember(%q, %q, %q) = %v, want %v", tt.role, tt.circleRole, tt.targetRole, allowed, tt.allowed)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+func TestAuthorizerRequirePermission(t *testing.T) {
+	authorizer := NewAuthorizer()
+
+	tests := []struct {
+		role       domain.Role
+		permission Permission
+		allowed    bool
+	}{
+		{domain.RoleUser, PermissionJoinCircle, true},
+		{domain.RoleUser, PermissionViewReports, false},
+		{domain.RoleModerator, PermissionViewReports, true},
+		{domain.RoleModerator, PermissionModerateContent, true},
+		{domain.RoleModerator, PermissionViewMetrics, false},
+		{domain.RoleAdmin, PermissionViewMetrics, true},
+		{"", PermissionJoinCircle, false}, // Tokens without a role get nothing
+	}
+
+	for _, tt := range tests {
+		err := authorizer.RequirePermission(tt.role, tt.permission)
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("RequirePermission(%q, %q) allowed = %v, want %v", tt.role, tt.permission, allowed, tt.allowed)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package authz
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+// ServicePrincipal is a trusted internal client, such as admin tooling or a
+// worker. It is granted the permissions of its role.
+type ServicePrincipal struct {
+	Name string
+	Role domain.Role
+}
+
+// ServiceAuthenticator maps API keys and client certificates to service
+// principals
+type ServiceAuthenticator struct {
+	apiKeys map[string]ServicePrincipal // By SHA-256 of the key
+	certs   map[string]ServicePrincipal // By certificate common name
+}
+
+// NewServiceAuthenticator parses the configured credentials. apiKeys entries
+// are "name:role:sha256-hex-of-key", so the keys themselves are never in the
+// config. clientCerts entries are "common-name:role".
+func NewServiceAuthenticator(apiKeys, clientCerts []string) (*ServiceAuthenticator, error) {
+	a := &ServiceAuthenticator{
+		apiKeys: make(map[string]ServicePrincipal, len(apiKeys)),
+		certs:   make(map[string]ServicePrincipal, len(clientCerts)),
+	}
+
+	for _, entry := range apiKeys {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("service API key %q must be name:role:sha256", entry)
+		}
+		principal, err := newServicePrincipal(parts[0], parts[1])
+		if err != nil {
+			return nil, err
+		}
+		hash := strings.ToLower(parts[2])
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("service API key hash for %s must be a hex SHA-256", principal.Name)
+		}
+		if _, exists := a.apiKeys[hash]; exists {
+			return nil, fmt.Errorf("duplicate service API key for %s", principal.Name)
+		}
+		a.apiKeys[hash] = principal
+	}
+
+	for _, entry := range clientCerts {
+		name, role, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("service client certificate %q must be common-name:role", entry)
+		}
+		principal, err := newServicePrincipal(name, role)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := a.certs[principal.Name]; exists {
+			return nil, fmt.Errorf("duplicate service client certificate %s", principal.Name)
+		}
+		a.certs[principal.Name] = principal
+	}
+
+	return a, nil
+}
+
+func newServicePrincipal(name, role string) (ServicePrincipal, error) {
+	if name == "" {
+		return ServicePrincipal{}, fmt.Errorf("service principal name must not be empty")
+	}
+	switch domain.Role(role) {
+	case domain.RoleUser, domain.RoleModerator, domain.RoleAdmin:
+	default:
+		return ServicePrincipal{}, fmt.Errorf("service principal %s has unknown role %q", name, role)
+	}
+	return ServicePrincipal{Name: name, Role: domain.Role(role)}, nil
+}
+
+// AuthenticateAPIKey returns the principal the API key belongs to
+func (a *ServiceAuthenticator) AuthenticateAPIKey(key string) (ServicePrincipal, bool) {
+	if key == "" {
+		return ServicePrincipal{}, false
+	}
+	principal, ok := a.apiKeys[HashAPIKey(key)]
+	return principal, ok
+}
+
+// AuthenticateCertificate returns the principal of a client certificate. The
+// certificate must already have been verified against the client CA.
+func (a *ServiceAuthenticator) AuthenticateCertificate(cert *x509.Certificate) (ServicePrincipal, bool) {
+	principal, ok := a.certs[cert.Subject.CommonName]
+	return principal, ok
+}
+
+// HashAPIKey returns the hex SHA-256 of an API key, the form it is configured in
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,59 @@
+package authz
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+func TestServiceAuthenticator(t *testing.T) {
+	auth, err := NewServiceAuthenticator(
+		[]string{"admin-cli:admin:" + HashAPIKey("s3cret")},
+		[]string{"reminder-worker:moderator"},
+	)
+	if err != nil {
+		t.Fatalf("NewServiceAuthenticator() error = %v", err)
+	}
+
+	principal, ok := auth.AuthenticateAPIKey("s3cret")
+	if !ok || principal != (ServicePrincipal{Name: "admin-cli", Role: domain.RoleAdmin}) {
+		t.Fatalf("AuthenticateAPIKey() = %+v, %v", principal, ok)
+	}
+	if _, ok := auth.AuthenticateAPIKey("wrong"); ok {
+		t.Fatal("AuthenticateAPIKey() accepted an unknown key")
+	}
+	if _, ok := auth.AuthenticateAPIKey(""); ok {
+		t.Fatal("AuthenticateAPIKey() accepted an empty key")
+	}
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "reminder-worker"}}
+	principal, ok = auth.AuthenticateCertificate(cert)
+	if !ok || principal.Role != domain.RoleModerator {
+		t.Fatalf("AuthenticateCertificate() = %+v, %v", principal, ok)
+	}
+	cert.Subject.CommonName = "someone-else"
+	if _, ok := auth.AuthenticateCertificate(cert); ok {
+		t.Fatal("AuthenticateCertificate() accepted an unknown common name")
+	}
+}
+
+func TestNewServiceAuthenticatorRejectsInvalidEntries(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		apiKeys     []string
+		clientCerts []string
+	}{
+		{"missing hash", []string{"admin-cli:admin"}, nil},
+		{"plaintext key", []string{"admin-cli:admin:s3cret"}, nil},
+		{"unknown role", []string{"admin-cli:root:" + HashAPIKey("s3cret")}, nil},
+		{"duplicate key", []string{"a:admin:" + HashAPIKey("k"), "b:user:" + HashAPIKey("k")}, nil},
+		{"certificate without role", nil, []string{"worker"}},
+		{"duplicate certificate", nil, []string{"worker:user", "worker:admin"}},
+	} {
+		if _, err := NewServiceAuthenticator(tc.apiKeys, tc.clientCerts); err == nil {
+			t.Errorf("%s: NewServiceAuthenticator() error = nil", tc.name)
+		}
+	}
+}
@@ -4,17 +4,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
+// revalidateTimeout bounds a background stale-while-revalidate refresh
+const revalidateTimeout = 30 * time.Second
+
 // Cache provides a high-level caching interface
 type Cache struct {
 	client *redis.Client
 	logger *zap.Logger
 	prefix string
+	jitter float64
+
+	// group collapses concurrent computations of the same key in this process
+	group singleflight.Group
 }
 
 // Config holds cache configuration
@@ -22,6 +31,9 @@ type Config struct {
 	Prefix        string
 	DefaultTTL    time.Duration
 	EnableLogging bool
+	// TTLJitter randomly shortens each TTL by up to this fraction (0-1) so keys
+	// written together don't all expire together
+	TTLJitter float64
 }
 
 // NewCache creates a new cache instance
@@ -32,12 +44,24 @@ func NewCache(client *redis.Client, logger *zap.Logger, config Config) *Cache {
 	if config.Prefix == "" {
 		config.Prefix = "cache"
 	}
+	if config.TTLJitter < 0 || config.TTLJitter >= 1 {
+		config.TTLJitter = 0
+	}
 
 	return &Cache{
 		client: client,
 		logger: logger,
 		prefix: config.Prefix,
+		jitter: config.TTLJitter,
+	}
+}
+
+// jitteredTTL shortens ttl by a random fraction of up to the configured jitter
+func (c *Cache) jitteredTTL(ttl time.Duration) time.Duration {
+	if c.jitter == 0 || ttl <= 0 {
+		return ttl
 	}
+	return ttl - time.Duration(rand.Float64()*c.jitter*float64(ttl))
 }
 
 // key generates a cache key with prefix
@@ -70,10 +94,8 @@ func (c *Cache) Get(ctx context.Context, key string, dest interface{}) (bool, er
 	return true, nil
 }
 
-// Set stores a value in cache with the given TTL
+// Set stores a value in cache with the given TTL, jittered if configured
 func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
-	cacheKey := c.key(key)
-
 	// Marshal the value to JSON
 	data, err := json.Marshal(value)
 	if err != nil {
@@ -81,8 +103,12 @@ func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time
 		return err
 	}
 
-	// Store in cache
-	if err := c.client.Set(ctx, cacheKey, data, ttl).Err(); err != nil {
+	return c.setRaw(ctx, key, data, c.jitteredTTL(ttl))
+}
+
+// setRaw stores already-marshaled data with exactly the given TTL
+func (c *Cache) setRaw(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if err := c.client.Set(ctx, c.key(key), data, ttl).Err(); err != nil {
 		c.logger.Error("Cache set error", zap.String("key", key), zap.Error(err))
 		return err
 	}
@@ -148,7 +174,9 @@ func (c *Cache) Exists(ctx context.Context, key string) (bool, error) {
 	return result > 0, nil
 }
 
-// GetOrSet retrieves a value from cache or computes it using the provided function
+// GetOrSet retrieves a value from cache or computes it using the provided
+// function. Concurrent misses for the same key in this process share a single
+// computation.
 func (c *Cache) GetOrSet(ctx context.Context, key string, dest interface{}, ttl time.Duration, compute func() (interface{}, error)) error {
 	// Try to get from cache
 	hit, err := c.Get(ctx, key, dest)
@@ -161,25 +189,111 @@ func (c *Cache) GetOrSet(ctx context.Context, key string, dest interface{}, ttl
 		return nil
 	}
 
-	// Cache miss, compute value
-	value, err := compute()
+	// Cache miss, compute value once for all waiting callers
+	data, err, _ := c.group.Do(key, func() (interface{}, error) {
+		value, err := compute()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		// Store in cache; log errors but don't fail the operation
+		if err := c.setRaw(ctx, key, data, c.jitteredTTL(ttl)); err != nil {
+			c.logger.Warn("Failed to cache computed value", zap.String("key", key), zap.Error(err))
+		}
+		return data, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	// Store in cache
-	if err := c.Set(ctx, key, value, ttl); err != nil {
-		// Log error but don't fail the operation
-		c.logger.Warn("Failed to cache computed value", zap.String("key", key), zap.Error(err))
+	// Copy computed value to destination
+	return json.Unmarshal(data.([]byte), dest)
+}
+
+// staleEntry wraps a value cached by GetOrSetStale with the time it goes stale
+type staleEntry struct {
+	Value      json.RawMessage `json:"value"`
+	FreshUntil time.Time       `json:"fresh_until"`
+}
+
+// GetOrSetStale is GetOrSet with stale-while-revalidate for hot keys. A value
+// is fresh for ttl and then served stale for up to staleTTL more while a
+// single background refresh recomputes it, so readers only wait on compute
+// when the key is missing entirely. Keys written this way must only be read
+// through GetOrSetStale.
+func (c *Cache) GetOrSetStale(ctx context.Context, key string, dest interface{}, ttl, staleTTL time.Duration, compute func(ctx context.Context) (interface{}, error)) error {
+	var entry staleEntry
+	hit, err := c.Get(ctx, key, &entry)
+	if err != nil {
+		// Serve from the source while the cache is unavailable
+		c.logger.Warn("Cache unavailable; computing value directly", zap.String("key", key), zap.Error(err))
+		hit = false
 	}
 
-	// Copy computed value to destination
-	data, err := json.Marshal(value)
+	if hit {
+		// An entry that no longer decodes, e.g. after its type changed, is recomputed
+		if err := json.Unmarshal(entry.Value, dest); err == nil {
+			if time.Now().After(entry.FreshUntil) {
+				c.revalidate(ctx, key, ttl, staleTTL, compute)
+			}
+			return nil
+		}
+		c.logger.Warn("Discarding undecodable cache entry", zap.String("key", key))
+	}
+
+	data, err, _ := c.group.Do(key, func() (interface{}, error) {
+		return c.computeStale(ctx, key, ttl, staleTTL, compute)
+	})
 	if err != nil {
 		return err
 	}
+	return json.Unmarshal(data.([]byte), dest)
+}
+
+// revalidate refreshes a stale key in the background. The refresh outlives the
+// request that noticed the key was stale, and is skipped if one is already running.
+func (c *Cache) revalidate(ctx context.Context, key string, ttl, staleTTL time.Duration, compute func(ctx context.Context) (interface{}, error)) {
+	refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), revalidateTimeout)
+	done := c.group.DoChan(key, func() (interface{}, error) {
+		return c.computeStale(refreshCtx, key, ttl, staleTTL, compute)
+	})
+
+	go func() {
+		defer cancel()
+		if result := <-done; result.Err != nil {
+			c.logger.Warn("Failed to revalidate stale cache entry", zap.String("key", key), zap.Error(result.Err))
+		}
+	}()
+}
+
+// computeStale computes a value, stores it wrapped in a staleEntry and returns
+// the marshaled value
+func (c *Cache) computeStale(ctx context.Context, key string, ttl, staleTTL time.Duration, compute func(ctx context.Context) (interface{}, error)) ([]byte, error) {
+	value, err := compute(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	return json.Unmarshal(data, dest)
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl = c.jitteredTTL(ttl)
+	entry, err := json.Marshal(staleEntry{Value: data, FreshUntil: time.Now().Add(ttl)})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.setRaw(ctx, key, entry, ttl+staleTTL); err != nil {
+		c.logger.Warn("Failed to cache computed value", zap.String("key", key), zap.Error(err))
+	}
+	return data, nil
 }
 
 // TTL returns the remaining time to live of a key
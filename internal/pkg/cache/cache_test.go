@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitteredTTL(t *testing.T) {
+	c := &Cache{jitter: 0.2}
+	ttl := 10 * time.Minute
+
+	for i := 0; i < 1000; i++ {
+		got := c.jitteredTTL(ttl)
+		if got > ttl || got < 8*time.Minute {
+			t.Fatalf("jitteredTTL(%v) = %v, want within [8m, 10m]", ttl, got)
+		}
+	}
+
+	if got := (&Cache{}).jitteredTTL(ttl); got != ttl {
+		t.Errorf("jitteredTTL without jitter = %v, want %v", got, ttl)
+	}
+	if got := c.jitteredTTL(0); got != 0 {
+		t.Errorf("jitteredTTL(0) = %v, want 0 (no expiry)", got)
+	}
+}
@@ -0,0 +1,106 @@
+// Package counters buffers hot counter increments in memory and writes them
+// in batches, so a busy post costs one write per flush instead of one per event
+package counters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Flusher applies accumulated increments, keyed by entity ID, in one batch
+type Flusher func(ctx context.Context, increments map[string]int64) error
+
+// Buffer accumulates increments per counter kind until the next flush.
+// Increments that fail to flush are kept for the next attempt; those still
+// buffered when the process is killed without a final Flush are lost, which
+// is acceptable for engagement counters.
+type Buffer struct {
+	mu       sync.Mutex
+	pending  map[string]map[string]int64
+	flushers map[string]Flusher
+	logger   *zap.Logger
+}
+
+// NewBuffer creates an empty buffer
+func NewBuffer(logger *zap.Logger) *Buffer {
+	return &Buffer{
+		pending:  make(map[string]map[string]int64),
+		flushers: make(map[string]Flusher),
+		logger:   logger,
+	}
+}
+
+// Register sets how increments of kind are written
+func (b *Buffer) Register(kind string, flush Flusher) {
+	b.mu.Lock()
+	b.flushers[kind] = flush
+	b.mu.Unlock()
+}
+
+// Add buffers an increment of the kind counter for id
+func (b *Buffer) Add(kind, id string, delta int64) {
+	b.mu.Lock()
+	counts, ok := b.pending[kind]
+	if !ok {
+		counts = make(map[string]int64)
+		b.pending[kind] = counts
+	}
+	counts[id] += delta
+	b.mu.Unlock()
+}
+
+// Flush writes every buffered increment, returning the errors of kinds that
+// failed; their increments are put back for the next flush
+func (b *Buffer) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = make(map[string]map[string]int64)
+	flushers := make(map[string]Flusher, len(b.flushers))
+	for kind, flush := range b.flushers {
+		flushers[kind] = flush
+	}
+	b.mu.Unlock()
+
+	var errs []error
+	for kind, increments := range pending {
+		flush, ok := flushers[kind]
+		if !ok {
+			errs = append(errs, fmt.Errorf("no flusher registered for %q counters", kind))
+			continue
+		}
+		if err := flush(ctx, increments); err != nil {
+			errs = append(errs, fmt.Errorf("flush %q counters: %w", kind, err))
+			b.restore(kind, increments)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Buffer) restore(kind string, increments map[string]int64) {
+	for id, delta := range increments {
+		b.Add(kind, id, delta)
+	}
+}
+
+// Run flushes every interval until ctx is cancelled. Callers flush once more
+// on shutdown, before closing the stores the flushers write to.
+func (b *Buffer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.Flush(ctx); err != nil {
+				b.logger.Warn("Failed to flush counters", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package counters
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestBuffer_Flush(t *testing.T) {
+	ctx := context.Background()
+	buffer := NewBuffer(zap.NewNop())
+
+	flushed := map[string]int64{}
+	fail := true
+	buffer.Register("views", func(_ context.Context, increments map[string]int64) error {
+		if fail {
+			return errors.New("store down")
+		}
+		for id, delta := range increments {
+			flushed[id] += delta
+		}
+		return nil
+	})
+
+	buffer.Add("views", "a", 1)
+	buffer.Add("views", "a", 1)
+	buffer.Add("views", "b", 1)
+
+	// Increments from a failed flush are kept for the next one
+	if err := buffer.Flush(ctx); err == nil {
+		t.Fatal("Flush() succeeded with a failing flusher")
+	}
+
+	fail = false
+	buffer.Add("views", "a", 1)
+	if err := buffer.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	want := map[string]int64{"a": 3, "b": 1}
+	if !reflect.DeepEqual(flushed, want) {
+		t.Errorf("flushed %v, want %v", flushed, want)
+	}
+
+	// Flushed increments are not written again
+	if err := buffer.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if !reflect.DeepEqual(flushed, want) {
+		t.Errorf("flushed %v after second flush, want %v", flushed, want)
+	}
+}
+
+func TestBuffer_FlushUnregisteredKind(t *testing.T) {
+	buffer := NewBuffer(zap.NewNop())
+	buffer.Add("unknown", "a", 1)
+
+	if err := buffer.Flush(context.Background()); err == nil {
+		t.Error("Flush() succeeded for a kind without a flusher")
+	}
+}
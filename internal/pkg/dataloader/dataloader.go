@@ -0,0 +1,152 @@
+// Package dataloader batches and caches lookups made while serving one
+// request, so resolving a list of items fetches what they refer to in one
+// call instead of one call per item.
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc fetches the values for keys. Keys without a value are left out of
+// the map.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// result is the outcome of loading one key, ready once done is closed
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	ctx     context.Context // Of the first load, which the fetch runs under
+	keys    []K
+	results []*result[V]
+	sent    bool
+}
+
+// Loader collects the keys loaded within wait of the first into one batch,
+// fetching early once maxBatch keys are waiting. Results, including errors,
+// are cached for the loader's lifetime, so create one per request.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending *batch[K, V]
+}
+
+// New creates a loader. A maxBatch of zero doesn't limit batch size.
+func New[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value for key and whether there is one
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	l.mu.Lock()
+	r, ok := l.cache[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.cache[key] = r
+		l.enqueue(ctx, key, r)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.found, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, false, ctx.Err()
+	}
+}
+
+// LoadMany returns the values for keys, in order, leaving out keys without one
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, error) {
+	type loaded struct {
+		value V
+		found bool
+		err   error
+	}
+	results := make([]loaded, len(keys))
+
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, found, err := l.Load(ctx, key)
+			results[i] = loaded{value, found, err}
+		}()
+	}
+	wg.Wait()
+
+	values := make([]V, 0, len(keys))
+	for _, r := range results {
+		if r.err != nil {
+			return nil, r.err
+		}
+		if r.found {
+			values = append(values, r.value)
+		}
+	}
+	return values, nil
+}
+
+// enqueue adds key to the pending batch, starting one if needed. Called with
+// l.mu held.
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, r *result[V]) {
+	if l.pending == nil {
+		b := &batch[K, V]{ctx: ctx}
+		l.pending = b
+		time.AfterFunc(l.wait, func() { l.send(b) })
+	}
+
+	b := l.pending
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, r)
+	if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+		l.pending = nil
+		b.sent = true
+		go l.run(b)
+	}
+}
+
+// send fetches b unless it was already sent for being full
+func (l *Loader[K, V]) send(b *batch[K, V]) {
+	l.mu.Lock()
+	if b.sent {
+		l.mu.Unlock()
+		return
+	}
+	b.sent = true
+	if l.pending == b {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+func (l *Loader[K, V]) run(b *batch[K, V]) {
+	values, err := l.fetch(b.ctx, b.keys)
+	for i, key := range b.keys {
+		r := b.results[i]
+		if err != nil {
+			r.err = err
+		} else {
+			r.value, r.found = values[key]
+		}
+		close(r.done)
+	}
+}
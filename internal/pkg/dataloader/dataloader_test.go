@@ -0,0 +1,95 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a BatchFunc that doubles keys above zero and records its batches
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (r *recorder) fetch(_ context.Context, keys []int) (map[int]int, error) {
+	r.mu.Lock()
+	sorted := append([]int{}, keys...)
+	sort.Ints(sorted)
+	r.batches = append(r.batches, sorted)
+	r.mu.Unlock()
+
+	values := make(map[int]int, len(keys))
+	for _, key := range keys {
+		if key > 0 {
+			values[key] = 2 * key
+		}
+	}
+	return values, nil
+}
+
+func TestLoaderBatchesAndCaches(t *testing.T) {
+	r := &recorder{}
+	loader := New(r.fetch, 10*time.Millisecond, 0)
+	ctx := context.Background()
+
+	values, err := loader.LoadMany(ctx, []int{3, 1, 0, 2, 1})
+	if err != nil {
+		t.Fatalf("LoadMany() error = %v", err)
+	}
+	if want := []int{6, 2, 4, 2}; !reflect.DeepEqual(values, want) {
+		t.Errorf("LoadMany() = %v, want %v", values, want)
+	}
+
+	value, found, err := loader.Load(ctx, 2)
+	if err != nil || !found || value != 4 {
+		t.Errorf("Load(2) = %d, %v, %v", value, found, err)
+	}
+	if _, found, _ := loader.Load(ctx, 0); found {
+		t.Errorf("Load(0) found a value")
+	}
+
+	if len(r.batches) != 1 || len(r.batches[0]) != 4 {
+		t.Errorf("batches = %v, want one batch of the 4 distinct keys", r.batches)
+	}
+}
+
+func TestLoaderMaxBatch(t *testing.T) {
+	r := &recorder{}
+	loader := New(r.fetch, time.Hour, 2)
+
+	if _, err := loader.LoadMany(context.Background(), []int{1, 2, 3, 4}); err != nil {
+		t.Fatalf("LoadMany() error = %v", err)
+	}
+	if len(r.batches) != 2 {
+		t.Errorf("batches = %v, want 2 full batches sent without waiting", r.batches)
+	}
+}
+
+func TestLoaderError(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	loader := New(func(context.Context, []string) (map[string]int, error) {
+		return nil, errFetch
+	}, time.Millisecond, 0)
+
+	if _, _, err := loader.Load(context.Background(), "a"); !errors.Is(err, errFetch) {
+		t.Errorf("Load() error = %v, want %v", err, errFetch)
+	}
+	if _, err := loader.LoadMany(context.Background(), []string{"a", "b"}); !errors.Is(err, errFetch) {
+		t.Errorf("LoadMany() error = %v, want %v", err, errFetch)
+	}
+}
+
+func TestLoaderContextCanceled(t *testing.T) {
+	loader := New((&recorder{}).fetch, time.Hour, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := loader.Load(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() error = %v, want context.Canceled", err)
+	}
+}
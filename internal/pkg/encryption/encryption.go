@@ -3,30 +3,91 @@ package encryption
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 )
 
+// DefaultKeyID identifies the key of deployments that have never rotated.
+// Ciphertexts written before versioning carry no key ID and were encrypted
+// with it.
+const DefaultKeyID = "1"
+
+// Key is an AES-256 key and the ID stored in the ciphertexts it encrypts
+type Key struct {
+	ID     string
+	Secret string
+}
+
+// KeyringConfig lists the keys a Manager uses
+type KeyringConfig struct {
+	Primary  Key   // Encrypts new values
+	Previous []Key // Decrypt values written before a rotation
+
+	// IndexKeyID is the key blind indexes are derived from, the primary key
+	// when empty. Stored indexes stop matching if it changes, so it stays on
+	// the original key across rotations.
+	IndexKeyID string
+}
+
+// Manager encrypts values with the primary key and decrypts values written
+// with any key on its keyring. Ciphertexts are "v<key id>:<base64>"; values
+// from before versioning are plain base64.
 type Manager struct {
-	key []byte
+	primaryID string
+	keys      map[string][]byte
+	order     []string // Key IDs, primary first
+	indexKey  []byte
 }
 
+// NewManager creates a manager with a single key
 func NewManager(key string) (*Manager, error) {
-	if len(key) != 32 {
-		return nil, fmt.Errorf("encryption key must be exactly 32 bytes for AES-256")
-	}
-	return &Manager{key: []byte(key)}, nil
+	return NewKeyring(KeyringConfig{Primary: Key{ID: DefaultKeyID, Secret: key}})
 }
 
-func (m *Manager) Encrypt(plaintext string) (string, error) {
-	block, err := aes.NewCipher(m.key)
-	if err != nil {
-		return "", err
+// NewKeyring creates a manager that encrypts with the primary key and can
+// decrypt with every configured key
+func NewKeyring(cfg KeyringConfig) (*Manager, error) {
+	m := &Manager{primaryID: cfg.Primary.ID, keys: make(map[string][]byte)}
+	for _, key := range append([]Key{cfg.Primary}, cfg.Previous...) {
+		if key.ID == "" || strings.Contains(key.ID, ":") {
+			return nil, fmt.Errorf("encryption key ID %q must be non-empty and must not contain ':'", key.ID)
+		}
+		if len(key.Secret) != 32 {
+			return nil, fmt.Errorf("encryption key %s must be exactly 32 bytes for AES-256", key.ID)
+		}
+		if _, exists := m.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate encryption key ID %s", key.ID)
+		}
+		m.keys[key.ID] = []byte(key.Secret)
+		m.order = append(m.order, key.ID)
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	indexKeyID := cfg.IndexKeyID
+	if indexKeyID == "" {
+		indexKeyID = cfg.Primary.ID
+	}
+	indexSecret, ok := m.keys[indexKeyID]
+	if !ok {
+		return nil, fmt.Errorf("blind index key %s is not configured", indexKeyID)
+	}
+	m.indexKey = deriveIndexKey(indexSecret)
+
+	return m, nil
+}
+
+// PrimaryKeyID returns the ID of the key new values are encrypted with
+func (m *Manager) PrimaryKeyID() string {
+	return m.primaryID
+}
+
+func (m *Manager) Encrypt(plaintext string) (string, error) {
+	gcm, err := newGCM(m.keys[m.primaryID])
 	if err != nil {
 		return "", err
 	}
@@ -37,28 +98,93 @@ func (m *Manager) Encrypt(plaintext string) (string, error) {
 	}
 
 	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
+	return "v" + m.primaryID + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
 }
 
 func (m *Manager) Decrypt(ciphertext string) (string, error) {
-	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	keyID, payload := splitCiphertext(ciphertext)
+	data, err := base64.StdEncoding.DecodeString(payload)
 	if err != nil {
 		return "", err
 	}
 
-	block, err := aes.NewCipher(m.key)
+	if keyID != "" {
+		key, ok := m.keys[keyID]
+		if !ok {
+			return "", fmt.Errorf("unknown encryption key %s", keyID)
+		}
+		return open(key, data)
+	}
+
+	// Unversioned values predate key IDs, so try each key, primary first
+	var lastErr error
+	for _, id := range m.order {
+		plaintext, err := open(m.keys[id], data)
+		if err == nil {
+			return plaintext, nil
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+// NeedsRotation reports whether ciphertext was not written with the
+// primary key
+func (m *Manager) NeedsRotation(ciphertext string) bool {
+	keyID, _ := splitCiphertext(ciphertext)
+	return keyID != m.primaryID
+}
+
+// Reencrypt returns ciphertext encrypted with the primary key
+func (m *Manager) Reencrypt(ciphertext string) (string, error) {
+	plaintext, err := m.Decrypt(ciphertext)
 	if err != nil {
 		return "", err
 	}
+	return m.Encrypt(plaintext)
+}
+
+// BlindIndex returns a keyed hash of value for exact-match lookups on
+// encrypted data. Equal values always produce the same index.
+func (m *Manager) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, m.indexKey)
+	mac.Write([]byte(value))
+	return base64.RawStdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// deriveIndexKey derives the blind index key so it differs from the encryption key
+func deriveIndexKey(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("blind-index"))
+	return mac.Sum(nil)
+}
+
+// splitCiphertext separates the key ID header from the base64 payload. The
+// base64 alphabet has no ':', so unversioned values have no header.
+func splitCiphertext(ciphertext string) (keyID, payload string) {
+	if header, rest, ok := strings.Cut(ciphertext, ":"); ok && strings.HasPrefix(header, "v") {
+		return header[1:], rest
+	}
+	return "", ciphertext
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
 
-	gcm, err := cipher.NewGCM(block)
+func open(key, data []byte) (string, error) {
+	gcm, err := newGCM(key)
 	if err != nil {
 		return "", err
 	}
 
 	nonceSize := gcm.NonceSize()
 	if len(data) < nonceSize {
-		return "", fmt.Errorf("ciphertext too short")
+		return "", errors.New("ciphertext too short")
 	}
 
 	nonce, ciphertextBytes := data[:nonceSize], data[nonceSize:]
@@ -0,0 +1,100 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+const (
+	oldKey = "0123456789abcdef0123456789abcdef"
+	newKey = "fedcba9876543210fedcba9876543210"
+)
+
+// legacyEncrypt produces a ciphertext in the unversioned format used before key IDs
+func legacyEncrypt(t *testing.T, key, plaintext string) string {
+	t.Helper()
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), nil))
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	before, err := NewManager(oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := NewKeyring(KeyringConfig{
+		Primary:    Key{ID: "2", Secret: newKey},
+		Previous:   []Key{{ID: DefaultKeyID, Secret: oldKey}},
+		IndexKeyID: DefaultKeyID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := before.Encrypt("user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(written, "v1:") {
+		t.Fatalf("ciphertext %q has no key ID header", written)
+	}
+
+	for name, ciphertext := range map[string]string{
+		"versioned":   written,
+		"unversioned": legacyEncrypt(t, oldKey, "user@example.com"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if !after.NeedsRotation(ciphertext) {
+				t.Fatal("expected ciphertext under the previous key to need rotation")
+			}
+			rotated, err := after.Reencrypt(ciphertext)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if after.NeedsRotation(rotated) || !strings.HasPrefix(rotated, "v2:") {
+				t.Fatalf("rotated ciphertext %q is not under the primary key", rotated)
+			}
+			if got, err := after.Decrypt(rotated); err != nil || got != "user@example.com" {
+				t.Fatalf("Decrypt = %q, %v", got, err)
+			}
+			if _, err := before.Decrypt(rotated); err == nil {
+				t.Error("expected the old keyring to reject a ciphertext under the new key")
+			}
+		})
+	}
+
+	if before.BlindIndex("hope") != after.BlindIndex("hope") {
+		t.Error("blind index changed across the rotation")
+	}
+}
+
+func TestNewKeyring_RejectsInvalidKeys(t *testing.T) {
+	tests := map[string]KeyringConfig{
+		"short key":         {Primary: Key{ID: "1", Secret: "short"}},
+		"colon in ID":       {Primary: Key{ID: "a:b", Secret: oldKey}},
+		"duplicate ID":      {Primary: Key{ID: "1", Secret: oldKey}, Previous: []Key{{ID: "1", Secret: newKey}}},
+		"unknown index key": {Primary: Key{ID: "2", Secret: newKey}, IndexKeyID: "1"},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewKeyring(cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -2,21 +2,35 @@ package errors
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/getsentry/sentry-go"
+	"github.com/yourorg/anonymous-support/internal/pkg/redact"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
-// ErrorReporter handles error reporting to external services
+// Reporter forwards unexpected errors to an error tracker. Tags may include
+// request_id and user_id, which are attached to the event.
+type Reporter interface {
+	ReportError(ctx context.Context, err error, tags map[string]string)
+}
+
+// Compile-time check to ensure ErrorReporter implements Reporter
+var _ Reporter = (*ErrorReporter)(nil)
+
+// ErrorReporter reports errors to Sentry. Without a DSN, or outside
+// production and staging, it only logs at debug level.
 type ErrorReporter struct {
 	logger      *zap.Logger
 	environment string
 	enabled     bool
 }
 
-// NewErrorReporter creates a new error reporter with Sentry integration
-func NewErrorReporter(logger *zap.Logger, environment string, sentryDSN string) *ErrorReporter {
+// NewErrorReporter creates a new error reporter with Sentry integration.
+// Events are tagged with environment and release.
+func NewErrorReporter(logger *zap.Logger, environment, release, sentryDSN string) *ErrorReporter {
 	enabled := sentryDSN != "" && (environment == "production" || environment == "staging")
 
 	// Initialize Sentry SDK when DSN is provided
@@ -24,6 +38,7 @@ func NewErrorReporter(logger *zap.Logger, environment string, sentryDSN string)
 		err := sentry.Init(sentry.ClientOptions{
 			Dsn:              sentryDSN,
 			Environment:      environment,
+			Release:          release,
 			TracesSampleRate: 0.1, // Sample 10% of transactions
 			EnableTracing:    true,
 			Debug:            environment != "production",
@@ -34,6 +49,10 @@ func NewErrorReporter(logger *zap.Logger, environment string, sentryDSN string)
 					event.Request.Cookies = ""
 					event.Request.Headers = filterSensitiveHeaders(event.Request.Headers)
 				}
+				event.Message = redact.String(event.Message)
+				for i := range event.Exception {
+					event.Exception[i].Value = redact.String(event.Exception[i].Value)
+				}
 				return event
 			},
 		})
@@ -42,7 +61,7 @@ func NewErrorReporter(logger *zap.Logger, environment string, sentryDSN string)
 			logger.Error("Failed to initialize Sentry", zap.Error(err))
 			enabled = false
 		} else {
-			logger.Info("Sentry error reporting enabled", zap.String("environment", environment))
+			logger.Info("Sentry error reporting enabled", zap.String("environment", environment), zap.String("release", release))
 		}
 	}
 
@@ -73,17 +92,13 @@ func (r *ErrorReporter) ReportError(ctx context.Context, err error, tags map[str
 		for k, v := range tags {
 			scope.SetTag(k, v)
 		}
+		if userID := tags["user_id"]; userID != "" {
+			scope.SetUser(sentry.User{ID: userID})
+		}
 
-		// Add context data if available
-		if ctx != nil {
-			if userID := ctx.Value("user_id"); userID != nil {
-				scope.SetUser(sentry.User{
-					ID: userID.(string),
-				})
-			}
-			if requestID := ctx.Value("request_id"); requestID != nil {
-				scope.SetTag("request_id", requestID.(string))
-			}
+		// Link the event to the request's trace
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+			scope.SetTag("trace_id", spanContext.TraceID().String())
 		}
 
 		// Capture the exception
@@ -103,12 +118,8 @@ func (r *ErrorReporter) ReportMessage(ctx context.Context, message string, level
 		}
 		scope.SetLevel(level)
 
-		if ctx != nil {
-			if userID := ctx.Value("user_id"); userID != nil {
-				scope.SetUser(sentry.User{
-					ID: userID.(string),
-				})
-			}
+		if userID := tags["user_id"]; userID != "" {
+			scope.SetUser(sentry.User{ID: userID})
 		}
 
 		sentry.CaptureMessage(message)
@@ -138,8 +149,7 @@ func filterSensitiveHeaders(headers map[string]string) map[string]string {
 	}
 
 	for k, v := range headers {
-		lowerKey := k
-		if sensitiveKeys[lowerKey] {
+		if sensitiveKeys[strings.ToLower(k)] {
 			filtered[k] = "[REDACTED]"
 		} else {
 			filtered[k] = v
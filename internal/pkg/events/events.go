@@ -0,0 +1,159 @@
+// Package events decouples services from the side effects of what they do:
+// a service publishes a domain event and subscribers such as notifications,
+// realtime fan-out and analytics react to it independently.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// Event bus drivers
+const (
+	DriverMemory = "memory" // In-process only; events are lost on restart
+	DriverRedis  = "redis"  // Redis streams with consumer groups
+	DriverNATS   = "nats"   // Requires the nats build tag
+	DriverKafka  = "kafka"  // Requires the kafka build tag
+)
+
+// Event is a domain event with a JSON payload
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data,omitempty"`
+}
+
+// NewEvent creates an event of eventType carrying payload
+func NewEvent(eventType string, payload interface{}) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Data:       data,
+	}, nil
+}
+
+// Decode unmarshals the event payload into v
+func (e Event) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", e.Type, err)
+	}
+	return nil
+}
+
+// Handler reacts to an event. Drivers that support redelivery retry events
+// whose handler returns an error, so handlers should be idempotent.
+type Handler func(ctx context.Context, event Event) error
+
+// EventBus delivers published events to subscribers. Every subscriber group
+// receives each event of the types it subscribes to, and within a group each
+// event is handled once: replicas of a consumer share a group, independent
+// consumers use their own.
+type EventBus interface {
+	// Publish sends an event to every group subscribed to its type
+	Publish(ctx context.Context, event Event) error
+	// Subscribe handles events of eventType in the background until ctx is cancelled
+	Subscribe(ctx context.Context, eventType, group string, handler Handler) error
+	// Close stops delivery and releases the driver's connections
+	Close() error
+}
+
+// Publish creates an event from payload and publishes it on bus. A nil bus
+// drops the event, so services can run without subscribers in tests.
+func Publish(ctx context.Context, bus EventBus, eventType string, payload interface{}) error {
+	if bus == nil {
+		return nil
+	}
+	event, err := NewEvent(eventType, payload)
+	if err != nil {
+		return err
+	}
+	if err := bus.Publish(ctx, event); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", eventType, err)
+	}
+	metrics.EventsPublishedTotal.WithLabelValues(eventType).Inc()
+	return nil
+}
+
+// Config selects and configures the event bus driver
+type Config struct {
+	Driver        string
+	MaxDeliveries int // Attempts at handling an event before it is dropped
+	NATSURL       string
+	KafkaBrokers  []string
+}
+
+// driverFactory creates a bus for a driver compiled in with a build tag
+type driverFactory func(cfg Config, logger *zap.Logger) (EventBus, error)
+
+// drivers holds the optional brokers registered by their build-tagged files
+var drivers = map[string]driverFactory{}
+
+// New creates the configured event bus. The memory and redis drivers are
+// always available; nats and kafka must be compiled in with their build tag.
+func New(cfg Config, redisClient *redis.Client, logger *zap.Logger) (EventBus, error) {
+	if cfg.MaxDeliveries <= 0 {
+		cfg.MaxDeliveries = 5
+	}
+
+	switch cfg.Driver {
+	case "", DriverMemory:
+		return NewMemoryBus(cfg.MaxDeliveries, logger), nil
+	case DriverRedis:
+		return NewRedisBus(redisClient, cfg.MaxDeliveries, logger), nil
+	}
+
+	factory, ok := drivers[cfg.Driver]
+	if !ok {
+		return nil, fmt.Errorf("event bus driver %q is not compiled in; build with -tags %s", cfg.Driver, cfg.Driver)
+	}
+	return factory(cfg, logger)
+}
+
+// handle runs handler for one event, recording the outcome
+func handle(ctx context.Context, handler Handler, event Event, group string) error {
+	err := handler(ctx, event)
+	result := "handled"
+	if err != nil {
+		result = "failed"
+	}
+	metrics.EventsHandledTotal.WithLabelValues(event.Type, group, result).Inc()
+	return err
+}
+
+// retryDelay is the pause before an event's second attempt, growing linearly after that
+const retryDelay = 200 * time.Millisecond
+
+// handleWithRetries runs handler until it succeeds or has been attempted
+// maxDeliveries times, for drivers without broker-side redelivery
+func handleWithRetries(ctx context.Context, handler Handler, event Event, group string, maxDeliveries int, logger *zap.Logger) {
+	for attempt := 1; ; attempt++ {
+		err := handle(ctx, handler, event, group)
+		if err == nil {
+			return
+		}
+		if attempt >= maxDeliveries {
+			logger.Error("Dropping event after repeated handler failures",
+				zap.String("type", event.Type), zap.String("group", group), zap.Int("attempts", attempt), zap.Error(err))
+			return
+		}
+
+		select {
+		case <-time.After(time.Duration(attempt) * retryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,128 @@
+//go:build kafka
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+const kafkaTopicPrefix = "events."
+
+func init() {
+	drivers[DriverKafka] = func(cfg Config, logger *zap.Logger) (EventBus, error) {
+		if len(cfg.KafkaBrokers) == 0 {
+			return nil, fmt.Errorf("kafka event bus needs at least one broker")
+		}
+		return NewKafkaBus(cfg.KafkaBrokers, cfg.MaxDeliveries, logger), nil
+	}
+}
+
+// KafkaBus delivers events through one Kafka topic per event type, with a
+// consumer group per subscriber group. Offsets are committed once an event
+// has been handled or has used up its deliveries.
+type KafkaBus struct {
+	brokers       []string
+	writer        *kafka.Writer
+	maxDeliveries int
+	mu            sync.Mutex
+	readers       []*kafka.Reader
+	wg            sync.WaitGroup
+	logger        *zap.Logger
+}
+
+// NewKafkaBus creates an event bus on the given Kafka brokers
+func NewKafkaBus(brokers []string, maxDeliveries int, logger *zap.Logger) *KafkaBus {
+	return &KafkaBus{
+		brokers: brokers,
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+		},
+		maxDeliveries: maxDeliveries,
+		logger:        logger,
+	}
+}
+
+// Publish writes the event to its type's topic
+func (b *KafkaBus) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.writer.WriteMessages(ctx, kafka.Message{
+		Topic: kafkaTopicPrefix + event.Type,
+		Key:   []byte(event.ID),
+		Value: payload,
+	})
+}
+
+// Subscribe joins the group's consumer group on eventType's topic
+func (b *KafkaBus) Subscribe(ctx context.Context, eventType, group string, handler Handler) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     b.brokers,
+		GroupID:     group,
+		Topic:       kafkaTopicPrefix + eventType,
+		StartOffset: kafka.LastOffset,
+		MaxWait:     time.Second,
+	})
+
+	b.mu.Lock()
+	b.readers = append(b.readers, reader)
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.consume(ctx, reader, group, handler)
+	}()
+	return nil
+}
+
+// consume handles messages in order until ctx is cancelled or the reader is closed
+func (b *KafkaBus) consume(ctx context.Context, reader *kafka.Reader, group string, handler Handler) {
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return
+			}
+			b.logger.Warn("Failed to read event topic", zap.String("topic", reader.Config().Topic), zap.String("group", group), zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			b.logger.Error("Dropping malformed event", zap.String("topic", msg.Topic), zap.Int64("offset", msg.Offset))
+		} else {
+			handleWithRetries(ctx, handler, event, group, b.maxDeliveries, b.logger)
+		}
+
+		if err := reader.CommitMessages(ctx, msg); err != nil && ctx.Err() == nil {
+			b.logger.Warn("Failed to commit event offset", zap.String("topic", msg.Topic), zap.Error(err))
+		}
+	}
+}
+
+// Close stops the consumers and flushes pending writes
+func (b *KafkaBus) Close() error {
+	b.mu.Lock()
+	for _, reader := range b.readers {
+		_ = reader.Close()
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return b.writer.Close()
+}
@@ -0,0 +1,122 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// memoryQueueSize is how many events each in-memory subscriber group buffers
+const memoryQueueSize = 1024
+
+// ErrBusClosed is returned when publishing to or subscribing on a closed bus
+var ErrBusClosed = errors.New("event bus is closed")
+
+// MemoryBus delivers events within the process. Each subscriber group has a
+// buffered queue drained by one goroutine per Subscribe call, so handlers
+// never delay the publisher. Failed events are retried in place and dropped
+// once they run out of deliveries.
+type MemoryBus struct {
+	mu            sync.RWMutex
+	groups        map[string]map[string]chan Event // event type -> group -> queue
+	closed        bool
+	maxDeliveries int
+	wg            sync.WaitGroup
+	logger        *zap.Logger
+}
+
+// NewMemoryBus creates an in-process event bus that attempts each event up
+// to maxDeliveries times
+func NewMemoryBus(maxDeliveries int, logger *zap.Logger) *MemoryBus {
+	return &MemoryBus{
+		groups:        make(map[string]map[string]chan Event),
+		maxDeliveries: maxDeliveries,
+		logger:        logger,
+	}
+}
+
+// Publish queues the event for every group subscribed to its type, waiting
+// for room when a group's queue is full
+func (b *MemoryBus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrBusClosed
+	}
+
+	for _, queue := range b.groups[event.Type] {
+		select {
+		case queue <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe starts a worker handling the group's events of eventType
+func (b *MemoryBus) Subscribe(ctx context.Context, eventType, group string, handler Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrBusClosed
+	}
+
+	if b.groups[eventType] == nil {
+		b.groups[eventType] = make(map[string]chan Event)
+	}
+	queue, ok := b.groups[eventType][group]
+	if !ok {
+		queue = make(chan Event, memoryQueueSize)
+		b.groups[eventType][group] = queue
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			select {
+			case event, ok := <-queue:
+				if !ok {
+					return
+				}
+				handleWithRetries(context.WithoutCancel(ctx), handler, event, group, b.maxDeliveries, b.logger)
+			case <-ctx.Done():
+				// Handle what was already queued before stopping
+				for {
+					select {
+					case event, ok := <-queue:
+						if !ok {
+							return
+						}
+						handleWithRetries(context.WithoutCancel(ctx), handler, event, group, b.maxDeliveries, b.logger)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops accepting events and waits for queued ones to be handled
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	for _, groups := range b.groups {
+		for _, queue := range groups {
+			close(queue)
+		}
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
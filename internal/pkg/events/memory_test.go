@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMemoryBus(t *testing.T) {
+	bus := NewMemoryBus(3, zap.NewNop())
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	handled := map[string]int{}
+	record := func(group string) Handler {
+		return func(ctx context.Context, event Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled[group]++
+			return nil
+		}
+	}
+
+	attempts := 0
+	flaky := func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts < 2 {
+			return errors.New("temporary failure")
+		}
+		handled["flaky"]++
+		return nil
+	}
+
+	// Two workers in one group share its events; each group sees every event
+	for _, sub := range []struct {
+		group   string
+		handler Handler
+	}{
+		{"realtime", record("realtime")},
+		{"realtime", record("realtime")},
+		{"notifications", record("notifications")},
+		{"flaky", flaky},
+	} {
+		if err := bus.Subscribe(ctx, "post.published", sub.group, sub.handler); err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := Publish(ctx, bus, "post.published", map[string]int{"n": i}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if err := Publish(ctx, bus, "response.created", nil); err != nil {
+		t.Fatalf("Publish() without subscribers error = %v", err)
+	}
+
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := map[string]int{"realtime": 3, "notifications": 3, "flaky": 3}
+	for group, count := range want {
+		if handled[group] != count {
+			t.Errorf("group %s handled %d events, want %d", group, handled[group], count)
+		}
+	}
+	if err := bus.Publish(ctx, Event{Type: "post.published"}); !errors.Is(err, ErrBusClosed) {
+		t.Errorf("Publish() after Close error = %v, want ErrBusClosed", err)
+	}
+}
@@ -0,0 +1,74 @@
+//go:build nats
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+const natsSubjectPrefix = "events."
+
+func init() {
+	drivers[DriverNATS] = func(cfg Config, logger *zap.Logger) (EventBus, error) {
+		return NewNATSBus(cfg.NATSURL, cfg.MaxDeliveries, logger)
+	}
+}
+
+// NATSBus delivers events over core NATS, with a queue group per subscriber
+// group. Core NATS does not persist messages, so events published while a
+// group has no subscribers are lost and failed events are retried in place.
+type NATSBus struct {
+	conn          *nats.Conn
+	maxDeliveries int
+	logger        *zap.Logger
+}
+
+// NewNATSBus connects to the NATS server at url
+func NewNATSBus(url string, maxDeliveries int, logger *zap.Logger) (*NATSBus, error) {
+	conn, err := nats.Connect(url, nats.Name("anonymous-support"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	return &NATSBus{conn: conn, maxDeliveries: maxDeliveries, logger: logger}, nil
+}
+
+// Publish sends the event on its type's subject
+func (b *NATSBus) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.conn.Publish(natsSubjectPrefix+event.Type, payload)
+}
+
+// Subscribe joins the group's queue group on eventType's subject
+func (b *NATSBus) Subscribe(ctx context.Context, eventType, group string, handler Handler) error {
+	subject := natsSubjectPrefix + eventType
+	sub, err := b.conn.QueueSubscribe(subject, group, func(msg *nats.Msg) {
+		var event Event
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			b.logger.Error("Dropping malformed event", zap.String("subject", subject))
+			return
+		}
+		handleWithRetries(ctx, handler, event, group, b.maxDeliveries, b.logger)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe %s to %s: %w", group, eventType, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = sub.Unsubscribe()
+	}()
+	return nil
+}
+
+// Close lets in-flight handlers finish and closes the connection
+func (b *NATSBus) Close() error {
+	return b.conn.Drain()
+}
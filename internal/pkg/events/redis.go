@@ -0,0 +1,181 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	redisStreamPrefix  = "events:"
+	redisStreamMaxLen  = 100000
+	redisClaimIdle     = time.Minute
+	redisPollTimeout   = 5 * time.Second
+	redisReclaimPeriod = 30 * time.Second
+)
+
+// RedisBus delivers events through one Redis stream per event type, with a
+// consumer group per subscriber group. Events are acknowledged once handled;
+// failed ones stay pending and are redelivered after redisClaimIdle until
+// they have been attempted maxDeliveries times.
+type RedisBus struct {
+	client        *redis.Client
+	maxDeliveries int
+	consumer      string
+	wg            sync.WaitGroup
+	logger        *zap.Logger
+}
+
+// NewRedisBus creates an event bus on Redis streams
+func NewRedisBus(client *redis.Client, maxDeliveries int, logger *zap.Logger) *RedisBus {
+	return &RedisBus{
+		client:        client,
+		maxDeliveries: maxDeliveries,
+		consumer:      uuid.New().String(),
+		logger:        logger,
+	}
+}
+
+// Publish appends the event to its type's stream
+func (b *RedisBus) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: redisStreamPrefix + event.Type,
+		MaxLen: redisStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": payload},
+	}).Err()
+}
+
+// Subscribe joins the group's consumer group on eventType's stream, creating
+// it at the end of the stream so a new group only sees events published from now on
+func (b *RedisBus) Subscribe(ctx context.Context, eventType, group string, handler Handler) error {
+	stream := redisStreamPrefix + eventType
+	err := b.client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && !redis.HasErrorPrefix(err, "BUSYGROUP") {
+		return fmt.Errorf("failed to create %s consumer group for %s: %w", group, eventType, err)
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.consume(ctx, stream, group, handler)
+	}()
+	return nil
+}
+
+// consume reads new events and periodically reclaims stale pending ones until ctx is cancelled
+func (b *RedisBus) consume(ctx context.Context, stream, group string, handler Handler) {
+	lastReclaim := time.Now()
+	for ctx.Err() == nil {
+		if time.Since(lastReclaim) >= redisReclaimPeriod {
+			b.reclaim(ctx, stream, group, handler)
+			lastReclaim = time.Now()
+		}
+
+		streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: b.consumer,
+			Streams:  []string{stream, ">"},
+			Count:    10,
+			Block:    redisPollTimeout,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			b.logger.Warn("Failed to read event stream", zap.String("stream", stream), zap.String("group", group), zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				b.deliver(ctx, stream, group, handler, msg)
+			}
+		}
+	}
+}
+
+// deliver handles one stream message, acknowledging it unless the handler failed
+func (b *RedisBus) deliver(ctx context.Context, stream, group string, handler Handler, msg redis.XMessage) {
+	raw, _ := msg.Values["event"].(string)
+
+	var event Event
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		b.logger.Error("Dropping malformed event", zap.String("stream", stream), zap.String("message_id", msg.ID))
+		b.ack(ctx, stream, group, msg.ID)
+		return
+	}
+
+	if err := handle(ctx, handler, event, group); err != nil {
+		b.logger.Warn("Event handler failed; it will be retried",
+			zap.String("type", event.Type), zap.String("group", group), zap.Error(err))
+		return
+	}
+	b.ack(ctx, stream, group, msg.ID)
+}
+
+// reclaim takes over events left pending by failed handlers or dead consumers,
+// dropping those that have used up their deliveries
+func (b *RedisBus) reclaim(ctx context.Context, stream, group string, handler Handler) {
+	pending, err := b.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Idle:   redisClaimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  100,
+	}).Result()
+	if err != nil {
+		return
+	}
+
+	for _, entry := range pending {
+		if entry.RetryCount >= int64(b.maxDeliveries) {
+			b.logger.Error("Dropping event after repeated handler failures",
+				zap.String("stream", stream), zap.String("group", group),
+				zap.String("message_id", entry.ID), zap.Int64("deliveries", entry.RetryCount))
+			b.ack(ctx, stream, group, entry.ID)
+			continue
+		}
+
+		msgs, err := b.client.XClaim(ctx, &redis.XClaimArgs{
+			Stream:   stream,
+			Group:    group,
+			Consumer: b.consumer,
+			MinIdle:  redisClaimIdle,
+			Messages: []string{entry.ID},
+		}).Result()
+		if err != nil {
+			continue
+		}
+		for _, msg := range msgs {
+			b.deliver(ctx, stream, group, handler, msg)
+		}
+	}
+}
+
+func (b *RedisBus) ack(ctx context.Context, stream, group, messageID string) {
+	if err := b.client.XAck(ctx, stream, group, messageID).Err(); err != nil {
+		b.logger.Warn("Failed to acknowledge event", zap.String("stream", stream), zap.String("message_id", messageID), zap.Error(err))
+	}
+}
+
+// Close waits for consumers to stop; they stop when their Subscribe context is
+// cancelled. The Redis client is owned by the caller and left open.
+func (b *RedisBus) Close() error {
+	b.wg.Wait()
+	return nil
+}
@@ -3,14 +3,16 @@ package feed
 import (
 	"context"
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/yourorg/anonymous-support/internal/domain"
 )
 
-// FeedRanker implements personalized feed ranking algorithm
+// FeedRanker implements personalized feed ranking algorithm. Its weights can
+// be changed while it's in use.
 type FeedRanker struct {
-	weights RankingWeights
+	weights atomic.Pointer[RankingWeights]
 }
 
 // RankingWeights defines the weights for different ranking factors
@@ -37,9 +39,19 @@ func DefaultWeights() RankingWeights {
 
 // NewFeedRanker creates a new feed ranker with default weights
 func NewFeedRanker() *FeedRanker {
-	return &FeedRanker{
-		weights: DefaultWeights(),
-	}
+	r := &FeedRanker{}
+	r.SetWeights(DefaultWeights())
+	return r
+}
+
+// Weights returns the current ranking weights
+func (r *FeedRanker) Weights() RankingWeights {
+	return *r.weights.Load()
+}
+
+// SetWeights replaces the ranking weights used by later RankPosts calls
+func (r *FeedRanker) SetWeights(weights RankingWeights) {
+	r.weights.Store(&weights)
 }
 
 // RankedPost represents a post with its calculated score
@@ -56,9 +68,10 @@ func (r *FeedRanker) RankPosts(ctx context.Context, posts []*domain.Post, userPr
 
 	ranked := make([]*RankedPost, len(posts))
 	now := time.Now()
+	weights := r.Weights()
 
 	for i, post := range posts {
-		score := r.calculateScore(post, userPrefs, now)
+		score := r.calculateScore(post, userPrefs, weights, now)
 		ranked[i] = &RankedPost{
 			Post:  post,
 			Score: score,
@@ -75,7 +88,7 @@ func (r *FeedRanker) RankPosts(ctx context.Context, posts []*domain.Post, userPr
 	}
 
 	// Apply diversity penalty to consecutive similar posts
-	r.applyDiversityPenalty(ranked)
+	r.applyDiversityPenalty(ranked, weights.DiversityPenalty)
 
 	// Re-sort after diversity penalty
 	for i := 0; i < len(ranked); i++ {
@@ -90,29 +103,29 @@ func (r *FeedRanker) RankPosts(ctx context.Context, posts []*domain.Post, userPr
 }
 
 // calculateScore computes the ranking score for a single post
-func (r *FeedRanker) calculateScore(post *domain.Post, prefs *UserPreferences, now time.Time) float64 {
+func (r *FeedRanker) calculateScore(post *domain.Post, prefs *UserPreferences, weights RankingWeights, now time.Time) float64 {
 	score := 0.0
 
 	// 1. Recency score (exponential decay)
 	recencyScore := r.calculateRecencyScore(post.CreatedAt, now)
-	score += recencyScore * r.weights.RecencyWeight
+	score += recencyScore * weights.RecencyWeight
 
 	// 2. Urgency score (normalized urgency level)
 	urgencyScore := float64(post.UrgencyLevel) / 10.0
-	score += urgencyScore * r.weights.UrgencyWeight
+	score += urgencyScore * weights.UrgencyWeight
 
 	// 3. Engagement score (responses + support reactions)
 	engagementScore := r.calculateEngagementScore(post.ResponseCount, post.SupportCount)
-	score += engagementScore * r.weights.EngagementWeight
+	score += engagementScore * weights.EngagementWeight
 
 	// 4. Category match score
 	if prefs != nil {
 		categoryScore := r.calculateCategoryScore(post.Categories, prefs.PreferredCategories)
-		score += categoryScore * r.weights.CategoryWeight
+		score += categoryScore * weights.CategoryWeight
 
 		// 5. Circle affinity score
 		circleScore := r.calculateCircleScore(post.CircleID, prefs.UserCircles)
-		score += circleScore * r.weights.CircleWeight
+		score += circleScore * weights.CircleWeight
 	}
 
 	return score
@@ -187,7 +200,7 @@ func (r *FeedRanker) calculateCircleScore(postCircleID *string, userCircles []st
 }
 
 // applyDiversityPenalty penalizes consecutive posts from same category/user
-func (r *FeedRanker) applyDiversityPenalty(ranked []*RankedPost) {
+func (r *FeedRanker) applyDiversityPenalty(ranked []*RankedPost, penalty float64) {
 	if len(ranked) < 2 {
 		return
 	}
@@ -198,13 +211,13 @@ func (r *FeedRanker) applyDiversityPenalty(ranked []*RankedPost) {
 
 		// Penalize if same user
 		if current.Post.UserID == previous.Post.UserID {
-			current.Score *= (1.0 - r.weights.DiversityPenalty)
+			current.Score *= (1.0 - penalty)
 		}
 
 		// Penalize if same primary category
 		if len(current.Post.Categories) > 0 && len(previous.Post.Categories) > 0 {
 			if current.Post.Categories[0] == previous.Post.Categories[0] {
-				current.Score *= (1.0 - r.weights.DiversityPenalty*0.5)
+				current.Score *= (1.0 - penalty*0.5)
 			}
 		}
 	}
@@ -0,0 +1,81 @@
+// Package fieldmask trims messages to the fields a client asked for with a
+// google.protobuf.FieldMask, so read RPCs can send partial responses.
+package fieldmask
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// tree holds mask paths split on dots. A node without children keeps its
+// whole field.
+type tree map[string]tree
+
+// Mask is a validated field mask for one message type
+type Mask struct {
+	paths tree
+}
+
+// New validates mask against messages of type like. A nil or empty mask
+// keeps every field. As in the FieldMask spec, paths can't go into repeated
+// or map fields.
+func New(mask *fieldmaskpb.FieldMask, like proto.Message) (*Mask, error) {
+	if len(mask.GetPaths()) == 0 {
+		return &Mask{}, nil
+	}
+	if !mask.IsValid(like) {
+		return nil, fmt.Errorf("invalid field mask %q for %s", strings.Join(mask.GetPaths(), ","), like.ProtoReflect().Descriptor().FullName())
+	}
+
+	// Normalizing drops paths into fields another path keeps whole, so only
+	// leaves are full paths
+	normalized := proto.Clone(mask).(*fieldmaskpb.FieldMask)
+	normalized.Normalize()
+
+	paths := tree{}
+	for _, path := range normalized.GetPaths() {
+		node := paths
+		for _, name := range strings.Split(path, ".") {
+			child, ok := node[name]
+			if !ok {
+				child = tree{}
+				node[name] = child
+			}
+			node = child
+		}
+	}
+	return &Mask{paths: paths}, nil
+}
+
+// Empty reports whether the mask keeps every field
+func (m *Mask) Empty() bool {
+	return len(m.paths) == 0
+}
+
+// Apply clears the fields of msg outside the mask
+func (m *Mask) Apply(msg proto.Message) {
+	if m.Empty() {
+		return
+	}
+	apply(msg.ProtoReflect(), m.paths)
+}
+
+func apply(msg protoreflect.Message, paths tree) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		children, ok := paths[string(fd.Name())]
+		switch {
+		case !ok:
+			msg.Clear(fd)
+		case len(children) == 0:
+			// Kept whole
+		default:
+			// Valid masks only have paths into singular messages
+			apply(v.Message(), children)
+		}
+		return true
+	})
+}
@@ -0,0 +1,104 @@
+package fieldmask
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+)
+
+func testAPI() *apipb.Api {
+	return &apipb.Api{
+		Name:          "support",
+		Version:       "v1",
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "support.proto"},
+		Mixins:        []*apipb.Mixin{{Name: "health", Root: "/health"}},
+		Methods: []*apipb.Method{
+			{Name: "GetPost", RequestTypeUrl: "GetPostRequest", ResponseTypeUrl: "GetPostResponse"},
+			{Name: "GetFeed", RequestTypeUrl: "GetFeedRequest", ResponseTypeUrl: "GetFeedResponse"},
+		},
+	}
+}
+
+func TestApplyKeepsOnlyMaskedFields(t *testing.T) {
+	mask, err := New(&fieldmaskpb.FieldMask{Paths: []string{"name", "methods"}}, &apipb.Api{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := testAPI()
+	mask.Apply(api)
+
+	if api.Name != "support" || len(api.Methods) != 2 {
+		t.Errorf("masked fields not kept: name %q, %d methods", api.Name, len(api.Methods))
+	}
+	if api.Version != "" || api.SourceContext != nil || api.Mixins != nil {
+		t.Errorf("unmasked fields kept: %v", api)
+	}
+	if api.Methods[0].RequestTypeUrl == "" {
+		t.Error("repeated fields should be kept whole")
+	}
+}
+
+func TestApplyKeepsNestedFields(t *testing.T) {
+	mask, err := New(&fieldmaskpb.FieldMask{Paths: []string{"source_context.file_name"}}, &apipb.Api{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := testAPI()
+	mask.Apply(api)
+
+	if api.GetSourceContext().GetFileName() != "support.proto" {
+		t.Error("nested field not kept")
+	}
+	if api.Name != "" || api.Methods != nil {
+		t.Errorf("unmasked fields kept: %v", api)
+	}
+}
+
+func TestWholeFieldWinsOverSubpaths(t *testing.T) {
+	mask, err := New(&fieldmaskpb.FieldMask{Paths: []string{"source_context.file_name", "source_context"}}, &apipb.Api{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := testAPI()
+	api.SourceContext = &sourcecontextpb.SourceContext{FileName: "support.proto"}
+	mask.Apply(api)
+
+	if api.SourceContext == nil || api.Name != "" {
+		t.Errorf("source_context should be kept whole and the rest cleared: %v", api)
+	}
+}
+
+func TestEmptyMaskKeepsEverything(t *testing.T) {
+	for _, fm := range []*fieldmaskpb.FieldMask{nil, {}} {
+		mask, err := New(fm, &apipb.Api{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !mask.Empty() {
+			t.Error("empty mask should include every field")
+		}
+
+		api := testAPI()
+		mask.Apply(api)
+		if api.Version != "v1" || api.SourceContext == nil {
+			t.Error("empty mask cleared fields")
+		}
+	}
+}
+
+func TestNewRejectsUnknownFields(t *testing.T) {
+	if _, err := New(&fieldmaskpb.FieldMask{Paths: []string{"nope"}}, &apipb.Api{}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	if _, err := New(&fieldmaskpb.FieldMask{Paths: []string{"name.length"}}, &apipb.Api{}); err == nil {
+		t.Error("expected an error for a path into a scalar")
+	}
+	if _, err := New(&fieldmaskpb.FieldMask{Paths: []string{"methods.name"}}, &apipb.Api{}); err == nil {
+		t.Error("expected an error for a path into a repeated field")
+	}
+}
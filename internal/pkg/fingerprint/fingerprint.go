@@ -0,0 +1,63 @@
+package fingerprint
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/geo"
+)
+
+// Kind identifies what a fingerprint was derived from
+type Kind string
+
+const (
+	KindIP     Kind = "ip"
+	KindDevice Kind = "device"
+)
+
+// ClientInfo holds the raw client identifiers seen on a request
+type ClientInfo struct {
+	IP       string
+	DeviceID string
+	Location geo.Location
+}
+
+type contextKey struct{}
+
+// WithClientInfo stores client identifiers in the context
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the client identifiers stored in the context, if any
+func FromContext(ctx context.Context) ClientInfo {
+	if info, ok := ctx.Value(contextKey{}).(ClientInfo); ok {
+		return info
+	}
+	return ClientInfo{}
+}
+
+// Hasher derives keyed, non-reversible fingerprints so raw IPs and device IDs are never stored
+type Hasher struct {
+	key []byte
+}
+
+// NewHasher creates a hasher keyed with the given secret
+func NewHasher(secret string) *Hasher {
+	return &Hasher{key: []byte(secret)}
+}
+
+// Hash returns the hex HMAC-SHA256 of the normalized value, or "" for an empty value
+func (h *Hasher) Hash(kind Kind, value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(string(kind) + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,62 @@
+package geo
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const earthRadiusKM = 6371.0
+
+// Location is where a request came from, as resolved by the edge proxy
+type Location struct {
+	Country        string // ISO 3166-1 alpha-2 code, empty when unknown
+	Latitude       float64
+	Longitude      float64
+	HasCoordinates bool
+}
+
+// Headers names the request headers the edge proxy puts the client location
+// in. An empty name disables that field.
+type Headers struct {
+	Country   string
+	Latitude  string
+	Longitude string
+}
+
+// Lookup reads the client location from the request headers
+func (h Headers) Lookup(header http.Header) Location {
+	var loc Location
+	if h.Country != "" {
+		country := strings.ToUpper(strings.TrimSpace(header.Get(h.Country)))
+		// "XX" is the conventional code for an unresolved address
+		if len(country) == 2 && country != "XX" {
+			loc.Country = country
+		}
+	}
+
+	if h.Latitude == "" || h.Longitude == "" {
+		return loc
+	}
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(header.Get(h.Latitude)), 64)
+	lon, lonErr := strconv.ParseFloat(strings.TrimSpace(header.Get(h.Longitude)), 64)
+	if latErr == nil && lonErr == nil && math.Abs(lat) <= 90 && math.Abs(lon) <= 180 {
+		loc.Latitude, loc.Longitude, loc.HasCoordinates = lat, lon, true
+	}
+	return loc
+}
+
+// Distance returns the great-circle distance between two points in kilometers
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := radians(lat1), radians(lat2)
+	dPhi, dLambda := radians(lat2-lat1), radians(lon2-lon1)
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusKM * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,44 @@
+package geo
+
+import (
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	// London to New York is about 5570 km
+	d := Distance(51.5074, -0.1278, 40.7128, -74.0060)
+	if math.Abs(d-5570) > 20 {
+		t.Fatalf("Distance(London, New York) = %.0f km, want about 5570", d)
+	}
+
+	if d := Distance(10, 20, 10, 20); d != 0 {
+		t.Fatalf("Distance to the same point = %f, want 0", d)
+	}
+}
+
+func TestHeadersLookup(t *testing.T) {
+	headers := Headers{Country: "CF-IPCountry", Latitude: "CF-IPLatitude", Longitude: "CF-IPLongitude"}
+
+	h := http.Header{}
+	h.Set("CF-IPCountry", "de")
+	h.Set("CF-IPLatitude", "52.52")
+	h.Set("CF-IPLongitude", "13.40")
+	loc := headers.Lookup(h)
+	if loc.Country != "DE" || !loc.HasCoordinates || loc.Latitude != 52.52 || loc.Longitude != 13.40 {
+		t.Fatalf("Lookup() = %+v", loc)
+	}
+
+	h = http.Header{}
+	h.Set("CF-IPCountry", "XX")
+	h.Set("CF-IPLatitude", "not-a-number")
+	h.Set("CF-IPLongitude", "13.40")
+	if loc := headers.Lookup(h); loc.Country != "" || loc.HasCoordinates {
+		t.Fatalf("Lookup() of unresolved headers = %+v, want empty", loc)
+	}
+
+	if loc := (Headers{}).Lookup(h); loc != (Location{}) {
+		t.Fatalf("Lookup() with no headers configured = %+v, want empty", loc)
+	}
+}
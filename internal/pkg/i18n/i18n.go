@@ -0,0 +1,184 @@
+// Package i18n translates server-generated text such as milestone names,
+// achievement titles and validation errors. Catalogs are embedded JSON files
+// in locales/, one per BCP 47 tag, mapping message keys to fmt templates.
+// English is the source language and the fallback for unsupported locales
+// and missing keys.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+type catalog struct {
+	tags     []language.Tag // English first, so it's the matcher's default
+	matcher  language.Matcher
+	messages map[language.Tag]map[string]string
+}
+
+var defaultCatalog = mustLoad(localeFiles)
+
+func mustLoad(fsys fs.FS) *catalog {
+	c, err := load(fsys)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func load(fsys fs.FS) (*catalog, error) {
+	files, err := fs.Glob(fsys, "locales/*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &catalog{
+		tags:     []language.Tag{language.English},
+		messages: make(map[language.Tag]map[string]string, len(files)),
+	}
+	for _, file := range files {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(file), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("locale %s: %w", file, err)
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("locale %s: %w", file, err)
+		}
+
+		c.messages[tag] = messages
+		if tag != language.English {
+			c.tags = append(c.tags, tag)
+		}
+	}
+	if _, ok := c.messages[language.English]; !ok {
+		return nil, fmt.Errorf("locales/en.json is missing")
+	}
+
+	c.matcher = language.NewMatcher(c.tags)
+	return c, nil
+}
+
+// Supported returns the supported locales, English first
+func Supported() []language.Tag {
+	return append([]language.Tag{}, defaultCatalog.tags...)
+}
+
+// Match returns the supported locale that best fits an Accept-Language
+// header, or English when none does or the header is malformed
+func Match(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return language.English
+	}
+	_, index, confidence := defaultCatalog.matcher.Match(tags...)
+	if confidence == language.No {
+		return language.English
+	}
+	return defaultCatalog.tags[index]
+}
+
+// Localizer renders messages and numbers in one locale
+type Localizer struct {
+	tag     language.Tag
+	printer *message.Printer
+}
+
+// For returns a localizer for tag, falling back to English when tag isn't a
+// supported locale
+func For(tag language.Tag) *Localizer {
+	if _, ok := defaultCatalog.messages[tag]; !ok {
+		tag = language.English
+	}
+	return &Localizer{tag: tag, printer: message.NewPrinter(tag)}
+}
+
+// Default returns the English localizer, for text that isn't shown to one
+// user, such as stored records and logs
+func Default() *Localizer {
+	return For(language.English)
+}
+
+// Tag returns the localizer's locale
+func (l *Localizer) Tag() language.Tag {
+	return l.tag
+}
+
+// T renders the message for key with args. Numbers are formatted for the
+// locale. Keys missing from the locale use the English message, and unknown
+// keys render as the key itself.
+func (l *Localizer) T(key string, args ...any) string {
+	template, ok := defaultCatalog.messages[l.tag][key]
+	if !ok {
+		template, ok = defaultCatalog.messages[language.English][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return template
+	}
+	return l.printer.Sprintf(template, args...)
+}
+
+// Localize translates english, the text configured for key, only when it is
+// still the English catalog message. Text an operator has overridden, e.g.
+// with a custom achievements file, is returned unchanged.
+func (l *Localizer) Localize(key, english string) string {
+	if defaultCatalog.messages[language.English][key] != english {
+		return english
+	}
+	return l.T(key)
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the locale for tag
+func NewContext(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, contextKey{}, For(tag))
+}
+
+// FromContext returns the localizer stored in ctx, or English if there is none
+func FromContext(ctx context.Context) *Localizer {
+	if l, ok := ctx.Value(contextKey{}).(*Localizer); ok {
+		return l
+	}
+	return Default()
+}
+
+// Error is an error whose message can be translated. Error returns the
+// English message, so logs stay in one language.
+type Error struct {
+	Key  string
+	Args []any
+}
+
+// Errorf returns an error rendering the message for key with args
+func Errorf(key string, args ...any) *Error {
+	return &Error{Key: key, Args: args}
+}
+
+func (e *Error) Error() string {
+	return Default().T(e.Key, e.Args...)
+}
+
+// Localize renders the error message in l's locale
+func (e *Error) Localize(l *Localizer) string {
+	return l.T(e.Key, e.Args...)
+}
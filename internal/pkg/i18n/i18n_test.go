@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   language.Tag
+	}{
+		{"", language.English},
+		{"es-MX,es;q=0.9", language.Spanish},
+		{"fr-CA", language.French},
+		{"pt-BR, es;q=0.5", language.Spanish},
+		{"de", language.English},
+		{"not a;;header", language.English},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.header); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	english := defaultCatalog.messages[language.English]
+	for tag, messages := range defaultCatalog.messages {
+		for key := range english {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("%v is missing %q", tag, key)
+			}
+		}
+		for key := range messages {
+			if _, ok := english[key]; !ok {
+				t.Errorf("%v has %q, which isn't in English", tag, key)
+			}
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	spanish := For(language.Spanish)
+	if got := spanish.T("validation.post_too_long", 5000); got != "el contenido de la publicación no puede superar los 5.000 caracteres" {
+		t.Errorf("T() = %q", got)
+	}
+	if got := spanish.T("unknown.key"); got != "unknown.key" {
+		t.Errorf("T() of an unknown key = %q", got)
+	}
+	if got := For(language.German).Tag(); got != language.English {
+		t.Errorf("For(German).Tag() = %v, want English", got)
+	}
+
+	if got := spanish.Localize("milestone.days_1", "First Day Clean"); got != "Primer día limpio" {
+		t.Errorf("Localize() = %q", got)
+	}
+	if got := spanish.Localize("milestone.days_1", "Day One"); got != "Day One" {
+		t.Errorf("Localize() of overridden text = %q", got)
+	}
+}
+
+func TestContext(t *testing.T) {
+	if got := FromContext(context.Background()).Tag(); got != language.English {
+		t.Errorf("FromContext() without a locale = %v", got)
+	}
+	if got := FromContext(NewContext(context.Background(), language.French)).Tag(); got != language.French {
+		t.Errorf("FromContext() = %v, want French", got)
+	}
+}
+
+func TestError(t *testing.T) {
+	err := Errorf("validation.password_length", 8)
+	if got := err.Error(); got != "password must be at least 8 characters long" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := err.Localize(For(language.French)); got != "le mot de passe doit contenir au moins 8 caractères" {
+		t.Errorf("Localize() = %q", got)
+	}
+}
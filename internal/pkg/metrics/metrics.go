@@ -10,21 +10,44 @@ var (
 	HTTPRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "http_requests_total",
-			Help: "Total number of HTTP requests",
+			Help: "Total number of HTTP requests by route pattern",
 		},
-		[]string{"method", "path", "status"},
+		[]string{"method", "route", "status"},
 	)
 
 	HTTPRequestDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "http_request_duration_seconds",
-			Help:    "HTTP request duration in seconds",
+			Help:    "HTTP request duration in seconds by route pattern",
 			Buckets: prometheus.DefBuckets,
 		},
-		[]string{"method", "path"},
+		[]string{"method", "route"},
+	)
+
+	HTTPRequestsInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served",
+		},
 	)
 
 	// Database metrics
+	DBReadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_reads_total",
+			Help: "Total number of Postgres reads by target (primary, replica)",
+		},
+		[]string{"target"},
+	)
+
+	DBReplicaLagSeconds = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "db_replica_lag_seconds",
+			Help: "Replication lag of each Postgres read replica",
+		},
+		[]string{"replica"},
+	)
+
 	DBQueriesTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "db_queries_total",
@@ -55,9 +78,41 @@ var (
 			Name: "websocket_messages_total",
 			Help: "Total number of WebSocket messages",
 		},
+		[]string{"direction", "type"},
+	)
+
+	WSChannelSubscribers = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "websocket_channel_subscribers",
+			Help: "Number of WebSocket channel subscriptions by channel type",
+		},
+		[]string{"channel_type"},
+	)
+
+	WSBroadcastLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "websocket_broadcast_latency_seconds",
+			Help:    "Time from an event being published to its delivery to local WebSocket clients",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
 		[]string{"type"},
 	)
 
+	WSMessagesDropped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "websocket_messages_dropped_total",
+			Help: "Total number of outbound WebSocket messages dropped because a client's send buffer was full",
+		},
+		[]string{"policy"},
+	)
+
+	WSSlowClientsEvicted = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "websocket_slow_clients_evicted_total",
+			Help: "Total number of WebSocket clients disconnected for not keeping up with their messages",
+		},
+	)
+
 	// Cache metrics
 	CacheHitsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -75,6 +130,14 @@ var (
 		[]string{"cache"},
 	)
 
+	CacheInvalidationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_invalidations_total",
+			Help: "Total number of cache invalidations triggered by writes",
+		},
+		[]string{"cache"},
+	)
+
 	CacheOperationDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "cache_operation_duration_seconds",
@@ -127,6 +190,14 @@ var (
 		[]string{"result"},
 	)
 
+	AuthLoginAnomaliesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_login_anomalies_total",
+			Help: "Total number of logins flagged as anomalous, by reason and action taken",
+		},
+		[]string{"reason", "action"},
+	)
+
 	ActiveSessionsGauge = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "active_sessions",
@@ -143,6 +214,14 @@ var (
 		[]string{"type"},
 	)
 
+	SOSHelpersNotified = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "sos_helpers_notified",
+			Help:    "Number of subscribed online helpers notified per SOS post",
+			Buckets: []float64{0, 1, 2, 5, 10, 20, 50},
+		},
+	)
+
 	SupportResponsesTotal = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "support_responses_total",
@@ -190,6 +269,169 @@ var (
 		[]string{"action"},
 	)
 
+	ModerationQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "moderation_queue_depth",
+			Help: "Number of open content reports",
+		},
+		[]string{"severity"},
+	)
+
+	ModerationOldestOpenReportAge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "moderation_oldest_open_report_age_seconds",
+			Help: "Age of the oldest open content report in seconds",
+		},
+		[]string{"severity"},
+	)
+
+	ModerationSLASeconds = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "moderation_sla_seconds",
+			Help: "Target time to resolve a content report in seconds",
+		},
+		[]string{"severity"},
+	)
+
+	ModerationSLABreaches = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "moderation_sla_breaches",
+			Help: "Number of open content reports older than their SLA",
+		},
+		[]string{"severity"},
+	)
+
+	ModerationResolutionDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "moderation_resolution_duration_seconds",
+			Help:    "Time from a content report being opened to being resolved",
+			Buckets: []float64{300, 900, 1800, 3600, 7200, 14400, 28800, 86400, 259200, 604800},
+		},
+		[]string{"severity", "status"},
+	)
+
+	// Push notification metrics
+	PushNotificationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "push_notifications_total",
+			Help: "Total number of push notifications sent",
+		},
+		[]string{"provider", "result"},
+	)
+
+	PushNotificationDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "push_notification_duration_seconds",
+			Help:    "Push provider request duration in seconds",
+			Buckets: []float64{.025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		},
+		[]string{"provider"},
+	)
+
+	PushInvalidTokensTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "push_invalid_tokens_total",
+			Help: "Total number of device tokens reported dead by a push provider",
+		},
+		[]string{"provider", "reason"},
+	)
+
+	// Notification dispatch metrics
+	NotificationDispatchTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "notification_dispatch_total",
+			Help: "Total number of notification dispatch jobs by outcome",
+		},
+		[]string{"provider", "result"},
+	)
+
+	NotificationDispatchLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "notification_dispatch_latency_seconds",
+			Help:    "Time from a notification being queued to being delivered, including retries",
+			Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300, 900},
+		},
+		[]string{"provider"},
+	)
+
+	NotificationDispatchBacklog = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "notification_dispatch_backlog",
+			Help: "Number of notification jobs waiting in each dispatch queue",
+		},
+		[]string{"queue"},
+	)
+
+	// Rate limiting metrics
+	RateLimitedRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limited_requests_total",
+			Help: "Total number of requests rejected by a rate limit rule",
+		},
+		[]string{"rule"},
+	)
+
+	// Event bus metrics
+	EventsPublishedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "events_published_total",
+			Help: "Total number of domain events published by type",
+		},
+		[]string{"type"},
+	)
+
+	EventsHandledTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "events_handled_total",
+			Help: "Total number of domain events handled by type, subscriber group and outcome",
+		},
+		[]string{"type", "group", "result"},
+	)
+
+	// Work queue metrics
+	WorkQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "work_queue_depth",
+			Help: "Number of background jobs waiting for a worker by priority",
+		},
+		[]string{"priority"},
+	)
+
+	WorkQueueWaitSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "work_queue_wait_seconds",
+			Help:    "Time background jobs spent queued before a worker picked them up, by priority",
+			Buckets: []float64{.001, .01, .1, .5, 1, 5, 15, 60, 300},
+		},
+		[]string{"priority"},
+	)
+
+	// Audit log metrics
+	AuditEventsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "audit_events_total",
+			Help: "Audit events by outcome: written, failed to write, or dropped because the buffer was full",
+		},
+		[]string{"result"},
+	)
+
+	// Data retention metrics
+	RetentionExpiredRecords = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "retention_expired_records",
+			Help: "Records past their retention period at the start of the last retention run",
+		},
+		[]string{"class", "action"},
+	)
+
+	RetentionRecordsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "retention_records_total",
+			Help: "Records deleted or anonymized by retention rules",
+		},
+		[]string{"class", "action"},
+	)
+
 	// System health metrics
 	ConnectionPoolSizeGauge = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -215,4 +457,20 @@ var (
 		},
 		[]string{"database"},
 	)
+
+	CircuitBreakerState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "circuit_breaker_state",
+			Help: "State of each circuit breaker (0 closed, 1 open, 2 half-open)",
+		},
+		[]string{"breaker"},
+	)
+
+	CircuitBreakerRejectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "circuit_breaker_rejections_total",
+			Help: "Total number of calls rejected by an open circuit breaker",
+		},
+		[]string{"breaker"},
+	)
 )
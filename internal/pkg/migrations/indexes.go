@@ -45,7 +45,7 @@ db.posts.createIndex({ "type": 1, "created_at": -1 });
 db.posts.createIndex({ "categories": 1, "created_at": -1 });
 db.posts.createIndex({ "urgency_level": -1, "created_at": -1 });
 db.posts.createIndex({ "circle_id": 1, "created_at": -1 });
-db.posts.createIndex({ "visibility": 1, "is_moderated": 1, "created_at": -1 });
+db.posts.createIndex({ "visibility": 1, "moderation_state": 1, "created_at": -1 });
 db.posts.createIndex({ "deleted_at": 1 }, { sparse: true }); // Soft delete
 
 // TTL index for auto-expiring old posts (optional - 90 days)
@@ -153,8 +153,18 @@ func (m *MongoMigrator) Up(ctx context.Context) error {
 	return nil
 }
 
-// Down rolls back the last applied migration
-func (m *MongoMigrator) Down(ctx context.Context) error {
+// Down rolls back the last steps applied migrations
+func (m *MongoMigrator) Down(ctx context.Context, steps int) error {
+	for range steps {
+		if err := m.downOne(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downOne rolls back the last applied migration
+func (m *MongoMigrator) downOne(ctx context.Context) error {
 	m.logger.Info("Rolling back last MongoDB migration")
 
 	// Get applied versions
@@ -200,12 +210,12 @@ func (m *MongoMigrator) Down(ctx context.Context) error {
 	return nil
 }
 
-// Status returns the current migration status
-func (m *MongoMigrator) Status(ctx context.Context) error {
+// Status lists every migration and whether it has been applied
+func (m *MongoMigrator) Status(ctx context.Context) ([]MigrationStatus, error) {
 	// Get applied versions
 	applied, err := m.getAppliedVersions(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get applied versions: %w", err)
+		return nil, fmt.Errorf("failed to get applied versions: %w", err)
 	}
 
 	// Sort migrations by version
@@ -213,18 +223,52 @@ func (m *MongoMigrator) Status(ctx context.Context) error {
 		return m.migrations[i].Version < m.migrations[j].Version
 	})
 
-	m.logger.Info("MongoDB migration status")
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Applied:     applied[migration.Version],
+		}
+	}
+
+	return statuses, nil
+}
+
+// Force records the migrations up to version as applied and later ones as
+// not applied, without running any of them
+func (m *MongoMigrator) Force(ctx context.Context, version int) error {
+	known := version == 0
 	for _, migration := range m.migrations {
-		status := "pending"
-		if applied[migration.Version] {
-			status = "applied"
+		known = known || migration.Version == version
+	}
+	if !known {
+		return fmt.Errorf("no migration has version %d", version)
+	}
+
+	if err := m.ensureMigrationsCollection(ctx); err != nil {
+		return fmt.Errorf("failed to ensure migrations collection: %w", err)
+	}
+
+	applied, err := m.getAppliedVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get applied versions: %w", err)
+	}
+
+	for _, migration := range m.migrations {
+		switch {
+		case migration.Version <= version && !applied[migration.Version]:
+			if err := m.recordMigration(ctx, migration); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+			}
+		case migration.Version > version && applied[migration.Version]:
+			if err := m.removeMigrationRecord(ctx, migration.Version); err != nil {
+				return fmt.Errorf("failed to remove migration record %d: %w", migration.Version, err)
+			}
 		}
-		m.logger.Info("Migration",
-			zap.Int("version", migration.Version),
-			zap.String("description", migration.Description),
-			zap.String("status", status))
 	}
 
+	m.logger.Info("Forced MongoDB migration version", zap.Int("version", version))
 	return nil
 }
 
@@ -35,6 +35,66 @@ func GetMongoMigrations() []Migration {
 			Up:          addPostsTTLIndex,
 			Down:        removePostsTTLIndex,
 		},
+		{
+			Version:     5,
+			Description: "Replace posts.is_moderated with moderation_state",
+			Up:          addPostsModerationState,
+			Down:        removePostsModerationState,
+		},
+		{
+			Version:     6,
+			Description: "Create notifications collection with indexes",
+			Up:          createNotificationsCollection,
+			Down:        dropNotificationsCollection,
+		},
+		{
+			Version:     7,
+			Description: "Index user_trackers by last weekly digest",
+			Up:          addTrackerDigestIndex,
+			Down:        removeTrackerDigestIndex,
+		},
+		{
+			Version:     8,
+			Description: "Create mood_entries collection with indexes",
+			Up:          createMoodEntriesCollection,
+			Down:        dropMoodEntriesCollection,
+		},
+		{
+			Version:     9,
+			Description: "Create relapse_events collection with indexes",
+			Up:          createRelapseEventsCollection,
+			Down:        dropRelapseEventsCollection,
+		},
+		{
+			Version:     10,
+			Description: "Create journal_entries collection with indexes",
+			Up:          createJournalEntriesCollection,
+			Down:        dropJournalEntriesCollection,
+		},
+		{
+			Version:     11,
+			Description: "Create daily_checkins collection with indexes",
+			Up:          createDailyCheckInsCollection,
+			Down:        dropDailyCheckInsCollection,
+		},
+		{
+			Version:     12,
+			Description: "Index daily_checkins by update time for active user counts",
+			Up:          addCheckInActivityIndex,
+			Down:        removeCheckInActivityIndex,
+		},
+		{
+			Version:     13,
+			Description: "Index user_trackers by risk window start hour",
+			Up:          addRiskWindowIndex,
+			Down:        removeRiskWindowIndex,
+		},
+		{
+			Version:     14,
+			Description: "Create post_reactions collection with indexes",
+			Up:          createPostReactionsCollection,
+			Down:        dropPostReactionsCollection,
+		},
 	}
 }
 
@@ -162,3 +222,203 @@ func removePostsTTLIndex(ctx context.Context, db *mongo.Database) error {
 	_, err := collection.Indexes().DropOne(ctx, "idx_expires_at_ttl")
 	return err
 }
+
+// Migration 5: Replace posts.is_moderated with moderation_state
+func addPostsModerationState(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("posts")
+
+	// Flagged posts become quarantined, everything else stays visible
+	if _, err := collection.UpdateMany(ctx,
+		bson.M{"is_moderated": true},
+		bson.M{"$set": bson.M{"moderation_state": "quarantined"}, "$unset": bson.M{"is_moderated": ""}},
+	); err != nil {
+		return err
+	}
+	if _, err := collection.UpdateMany(ctx,
+		bson.M{"moderation_state": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"moderation_state": "visible"}, "$unset": bson.M{"is_moderated": ""}},
+	); err != nil {
+		return err
+	}
+
+	if _, err := collection.Indexes().DropOne(ctx, "idx_is_moderated"); err != nil {
+		return err
+	}
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "moderation_state", Value: 1}},
+		Options: options.Index().SetName("idx_moderation_state"),
+	})
+	return err
+}
+
+func removePostsModerationState(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("posts")
+
+	if _, err := collection.UpdateMany(ctx,
+		bson.M{"moderation_state": bson.M{"$ne": "visible"}},
+		bson.M{"$set": bson.M{"is_moderated": true}, "$unset": bson.M{"moderation_state": ""}},
+	); err != nil {
+		return err
+	}
+	if _, err := collection.UpdateMany(ctx,
+		bson.M{"moderation_state": "visible"},
+		bson.M{"$set": bson.M{"is_moderated": false}, "$unset": bson.M{"moderation_state": ""}},
+	); err != nil {
+		return err
+	}
+
+	if _, err := collection.Indexes().DropOne(ctx, "idx_moderation_state"); err != nil {
+		return err
+	}
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "is_moderated", Value: 1}},
+		Options: options.Index().SetName("idx_is_moderated"),
+	})
+	return err
+}
+
+// Migration 6: Create notifications collection with indexes
+func createNotificationsCollection(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("notifications")
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_user_inbox"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "read_at", Value: 1}},
+			Options: options.Index().SetName("idx_user_unread"),
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().
+				SetName("idx_created_at_ttl").
+				SetExpireAfterSeconds(90 * 24 * 60 * 60), // Keep the inbox for 90 days
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+func dropNotificationsCollection(ctx context.Context, db *mongo.Database) error {
+	return db.Collection("notifications").Drop(ctx)
+}
+
+// Migration 7: Index user_trackers by when the last weekly digest was sent
+func addTrackerDigestIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("user_trackers").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "last_digest.sent_at", Value: 1}},
+		Options: options.Index().SetName("idx_last_digest_sent_at"),
+	})
+	return err
+}
+
+func removeTrackerDigestIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("user_trackers").Indexes().DropOne(ctx, "idx_last_digest_sent_at")
+	return err
+}
+
+// Migration 8: Create mood_entries collection, one entry per user per day
+func createMoodEntriesCollection(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("mood_entries").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "day", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("idx_user_day_unique"),
+	})
+	return err
+}
+
+func dropMoodEntriesCollection(ctx context.Context, db *mongo.Database) error {
+	return db.Collection("mood_entries").Drop(ctx)
+}
+
+// Migration 9: Create relapse_events collection with indexes
+func createRelapseEventsCollection(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("relapse_events").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "occurred_at", Value: -1}},
+		Options: options.Index().SetName("idx_user_occurred_at"),
+	})
+	return err
+}
+
+func dropRelapseEventsCollection(ctx context.Context, db *mongo.Database) error {
+	return db.Collection("relapse_events").Drop(ctx)
+}
+
+// Migration 10: Create journal_entries collection with indexes
+func createJournalEntriesCollection(ctx context.Context, db *mongo.Database) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_user_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "search_tokens", Value: 1}},
+			Options: options.Index().SetName("idx_user_search_tokens"),
+		},
+	}
+
+	_, err := db.Collection("journal_entries").Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+func dropJournalEntriesCollection(ctx context.Context, db *mongo.Database) error {
+	return db.Collection("journal_entries").Drop(ctx)
+}
+
+// Migration 11: Create daily_checkins collection, one document per user per day
+func createDailyCheckInsCollection(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("daily_checkins").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "day", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("idx_user_day_unique"),
+	})
+	return err
+}
+
+func dropDailyCheckInsCollection(ctx context.Context, db *mongo.Database) error {
+	return db.Collection("daily_checkins").Drop(ctx)
+}
+
+// Migration 12: Index daily_checkins by update time for active user counts
+func addCheckInActivityIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("daily_checkins").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "updated_at", Value: 1}},
+		Options: options.Index().SetName("idx_updated_at"),
+	})
+	return err
+}
+
+func removeCheckInActivityIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("daily_checkins").Indexes().DropOne(ctx, "idx_updated_at")
+	return err
+}
+
+// Migration 13: Index user_trackers by risk window start hour for pre-window nudges
+func addRiskWindowIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("user_trackers").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "risk_windows.start_hour", Value: 1}, {Key: "last_risk_nudge_at", Value: 1}},
+		Options: options.Index().SetName("idx_risk_window_start_hour"),
+	})
+	return err
+}
+
+func removeRiskWindowIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("user_trackers").Indexes().DropOne(ctx, "idx_risk_window_start_hour")
+	return err
+}
+
+// Migration 14: Create post_reactions collection, one reaction per user per post
+func createPostReactionsCollection(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("post_reactions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "post_id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("idx_user_post_unique"),
+	})
+	return err
+}
+
+func dropPostReactionsCollection(ctx context.Context, db *mongo.Database) error {
+	return db.Collection("post_reactions").Drop(ctx)
+}
@@ -0,0 +1,430 @@
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// postgresLockID is the advisory lock held while migrating, so migrators
+// started together by several deploys run one at a time
+const postgresLockID = 4_215_337_092
+
+// sqlMigrationFile matches golang-migrate's file names, e.g. 001_create_users.up.sql
+var sqlMigrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// SQLMigration is a PostgreSQL migration read from a pair of SQL files
+type SQLMigration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // SHA-256 of Up
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version     int
+	Description string
+	Applied     bool
+	Modified    bool // Applied, but the migration has changed since
+}
+
+// PostgresMigrator applies SQL migrations, tracking the current version in the
+// same schema_migrations table as golang-migrate so either tool can take over
+// a database from the other. Each migration runs in a transaction with its
+// version update.
+//
+// The checksum of each applied migration is kept in
+// schema_migration_checksums, and Up refuses to run when an applied migration
+// has since been edited. Migrations applied before checksums were recorded
+// are trusted as they are on the next Up.
+type PostgresMigrator struct {
+	db         *sql.DB
+	migrations []SQLMigration
+	logger     *zap.Logger
+}
+
+// NewPostgresMigrator reads the migrations in files
+func NewPostgresMigrator(db *sql.DB, files fs.FS, logger *zap.Logger) (*PostgresMigrator, error) {
+	migrations, err := LoadSQLMigrations(files)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresMigrator{db: db, migrations: migrations, logger: logger}, nil
+}
+
+// LoadSQLMigrations reads the NNN_name.up.sql and NNN_name.down.sql files at
+// the root of files, sorted by version. Every version needs an up file.
+func LoadSQLMigrations(files fs.FS) ([]SQLMigration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*SQLMigration)
+	for _, entry := range entries {
+		match := sqlMigrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s has an invalid version", entry.Name())
+		}
+		body, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &SQLMigration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]SQLMigration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up applies all pending migrations
+func (m *PostgresMigrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verifyChecksums(ctx, conn, current); err != nil {
+			return err
+		}
+
+		applied := 0
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			m.logger.Info("Applying migration",
+				zap.Int("version", migration.Version),
+				zap.String("name", migration.Name))
+			if err := m.apply(ctx, conn, migration.Up, migration.Version, migration.Checksum); err != nil {
+				return fmt.Errorf("failed to apply migration %d: %w", migration.Version, err)
+			}
+			applied++
+		}
+
+		m.logger.Info("PostgreSQL migrations completed", zap.Int("applied", applied))
+		return nil
+	})
+}
+
+// Down rolls back the last steps applied migrations
+func (m *PostgresMigrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version > current {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+
+			previous := 0
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			m.logger.Info("Rolling back migration",
+				zap.Int("version", migration.Version),
+				zap.String("name", migration.Name))
+			if err := m.apply(ctx, conn, migration.Down, previous, ""); err != nil {
+				return fmt.Errorf("failed to roll back migration %d: %w", migration.Version, err)
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// Force records version as the current one without running any migration,
+// clearing the dirty flag a failed golang-migrate run leaves. Version 0
+// records that no migration has been applied.
+func (m *PostgresMigrator) Force(ctx context.Context, version int) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("no migration has version %d", version)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := setVersion(ctx, tx, version); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// Version returns the current version, 0 when no migration has been applied,
+// and whether a failed migration left the schema dirty
+func (m *PostgresMigrator) Version(ctx context.Context) (int, bool, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Close()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return 0, false, err
+	}
+	return currentVersion(ctx, conn)
+}
+
+// Status lists every migration, whether it has been applied and whether it
+// has changed since
+func (m *PostgresMigrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	current, _, err := currentVersion(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	checksums, err := appliedChecksums(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		applied := migration.Version <= current
+		recorded, ok := checksums[migration.Version]
+		statuses[i] = MigrationStatus{
+			Version:     migration.Version,
+			Description: migration.Name,
+			Applied:     applied,
+			Modified:    applied && ok && recorded != migration.Checksum,
+		}
+	}
+	return statuses, nil
+}
+
+// withLock runs fn on a connection holding the migration lock
+func (m *PostgresMigrator) withLock(ctx context.Context, fn func(*sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, postgresLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, postgresLockID); err != nil {
+			m.logger.Warn("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// cleanVersion returns the current version, failing if the schema is dirty
+func (m *PostgresMigrator) cleanVersion(ctx context.Context, conn *sql.Conn) (int, error) {
+	version, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("migration %d failed part way and left the schema dirty; repair it by hand, then force the version", version)
+	}
+	return version, nil
+}
+
+// verifyChecksums fails if a migration up to current has changed since it was
+// applied, or if current is a version this migrator doesn't have. Applied
+// migrations without a checksum get the current one.
+func (m *PostgresMigrator) verifyChecksums(ctx context.Context, conn *sql.Conn, current int) error {
+	if current != 0 && !m.known(current) {
+		return fmt.Errorf("the schema is at version %d, which isn't among the migrations; this migrator is older than the schema", current)
+	}
+
+	checksums, err := appliedChecksums(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > current {
+			break
+		}
+		recorded, ok := checksums[migration.Version]
+		if !ok {
+			if err := recordChecksum(ctx, conn, migration.Version, migration.Checksum); err != nil {
+				return err
+			}
+			continue
+		}
+		if recorded != migration.Checksum {
+			return fmt.Errorf("migration %d_%s has changed since it was applied; add a new migration instead of editing it", migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+// apply runs a migration's SQL and records version, and the checksum of the
+// migration applied when going up, in one transaction
+func (m *PostgresMigrator) apply(ctx context.Context, conn *sql.Conn, query string, version int, checksum string) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return err
+	}
+	if err := setVersion(ctx, tx, version); err != nil {
+		return err
+	}
+	if checksum != "" {
+		if err := recordChecksum(ctx, tx, version, checksum); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (m *PostgresMigrator) known(version int) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func ensureVersionTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty BOOLEAN NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migration_checksums (
+			version BIGINT NOT NULL PRIMARY KEY,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migration_checksums: %w", err)
+	}
+	return nil
+}
+
+func appliedChecksums(ctx context.Context, conn *sql.Conn) (map[int]string, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, checksum FROM schema_migration_checksums`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration checksums: %w", err)
+	}
+	defer rows.Close()
+
+	checksums := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, err
+		}
+		checksums[version] = checksum
+	}
+	return checksums, rows.Err()
+}
+
+// execer is a connection or transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func recordChecksum(ctx context.Context, db execer, version int, checksum string) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO schema_migration_checksums (version, checksum) VALUES ($1, $2)
+		ON CONFLICT (version) DO UPDATE SET checksum = EXCLUDED.checksum, applied_at = NOW()`,
+		version, checksum)
+	return err
+}
+
+func currentVersion(ctx context.Context, conn *sql.Conn) (int, bool, error) {
+	var version int
+	var dirty bool
+	err := conn.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// setVersion replaces the recorded version, golang-migrate keeping a single
+// row, and forgets the checksums of later migrations
+func setVersion(ctx context.Context, tx *sql.Tx, version int) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migration_checksums WHERE version > $1`, version); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, version)
+	return err
+}
@@ -0,0 +1,105 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	sqlmigrations "github.com/yourorg/anonymous-support/migrations"
+)
+
+func TestLoadSQLMigrations(t *testing.T) {
+	files := fstest.MapFS{
+		"002_add_index.up.sql":        {Data: []byte("CREATE INDEX idx ON users(name);")},
+		"001_create_users.up.sql":     {Data: []byte("CREATE TABLE users (id UUID);")},
+		"001_create_users.down.sql":   {Data: []byte("DROP TABLE users;")},
+		"README.md":                   {Data: []byte("not a migration")},
+		"003_seed.sql":                {Data: []byte("not a migration either")},
+		"nested/004_ignored.up.sql":   {Data: []byte("SELECT 1;")},
+		"002_add_index.down.sql":      {Data: []byte("DROP INDEX idx;")},
+		"010_backfill_names.up.sql":   {Data: []byte("UPDATE users SET name = '';")},
+		"010_backfill_names.down.sql": {Data: []byte("")},
+	}
+
+	migrations, err := LoadSQLMigrations(files)
+	if err != nil {
+		t.Fatalf("LoadSQLMigrations() error = %v", err)
+	}
+
+	var versions []int
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	if len(versions) != 3 || versions[0] != 1 || versions[1] != 2 || versions[2] != 10 {
+		t.Fatalf("versions = %v, want [1 2 10]", versions)
+	}
+	if migrations[0].Name != "create_users" || migrations[0].Down != "DROP TABLE users;" {
+		t.Errorf("migrations[0] = %+v", migrations[0])
+	}
+	if migrations[0].Checksum == "" || migrations[0].Checksum == migrations[1].Checksum {
+		t.Errorf("checksums = %q, %q; want distinct checksums", migrations[0].Checksum, migrations[1].Checksum)
+	}
+}
+
+func TestLoadSQLMigrationsChecksumTracksUpFile(t *testing.T) {
+	load := func(up, down string) string {
+		migrations, err := LoadSQLMigrations(fstest.MapFS{
+			"001_create_users.up.sql":   {Data: []byte(up)},
+			"001_create_users.down.sql": {Data: []byte(down)},
+		})
+		if err != nil {
+			t.Fatalf("LoadSQLMigrations() error = %v", err)
+		}
+		return migrations[0].Checksum
+	}
+
+	original := load("CREATE TABLE users (id UUID);", "DROP TABLE users;")
+	if load("CREATE TABLE users (id UUID);", "DROP TABLE IF EXISTS users;") != original {
+		t.Error("checksum changed with the down file")
+	}
+	if load("CREATE TABLE users (id UUID, name TEXT);", "DROP TABLE users;") == original {
+		t.Error("checksum didn't change with the up file")
+	}
+}
+
+func TestLoadSQLMigrationsRejectsBrokenSets(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"down without up": {
+			"001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		},
+		"version reused": {
+			"001_create_users.up.sql": {Data: []byte("CREATE TABLE users (id UUID);")},
+			"001_create_posts.up.sql": {Data: []byte("CREATE TABLE posts (id UUID);")},
+		},
+		"version zero": {
+			"000_init.up.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadSQLMigrations(files); err == nil {
+				t.Error("LoadSQLMigrations() error = nil, want an error")
+			}
+		})
+	}
+}
+
+// TestEmbeddedSQLMigrations checks the built-in migrations load and can all be rolled back
+func TestEmbeddedSQLMigrations(t *testing.T) {
+	migrations, err := LoadSQLMigrations(sqlmigrations.Postgres)
+	if err != nil {
+		t.Fatalf("LoadSQLMigrations() error = %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations found")
+	}
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("migration %d_%s is out of sequence; want version %d", migration.Version, migration.Name, i+1)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s has no down migration", migration.Version, migration.Name)
+		}
+	}
+}
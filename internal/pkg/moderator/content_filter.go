@@ -4,27 +4,70 @@ import (
 	"strings"
 )
 
-var profanityList = []string{
-	"fuck", "shit", "damn", "ass", "bitch", "bastard",
+// Lexicon holds the terms filtered for a single language
+type Lexicon struct {
+	Profanity []string
+	Harmful   []string
 }
 
-var harmfulKeywords = []string{
-	"suicide", "kill yourself", "end it all", "self-harm",
+// lexicons are the per-language term lists, keyed by ISO 639-1 code
+var lexicons = map[string]Lexicon{
+	"en": {
+		Profanity: []string{"fuck", "shit", "damn", "ass", "bitch", "bastard"},
+		Harmful:   []string{"suicide", "kill yourself", "end it all", "self-harm"},
+	},
+	"es": {
+		Profanity: []string{"mierda", "puta", "joder", "cabrón", "coño", "pendejo"},
+		Harmful:   []string{"suicidio", "suicidarme", "mátate", "quitarme la vida", "autolesión"},
+	},
+	"fr": {
+		Profanity: []string{"merde", "putain", "connard", "salope", "enculé"},
+		Harmful:   []string{"suicide", "me tuer", "tue-toi", "en finir", "automutilation"},
+	},
+	"de": {
+		Profanity: []string{"scheiße", "scheisse", "arschloch", "wichser", "hurensohn"},
+		Harmful:   []string{"selbstmord", "suizid", "mich umbringen", "bring dich um", "selbstverletzung"},
+	},
+	"pt": {
+		Profanity: []string{"merda", "porra", "caralho", "foda-se", "puta"},
+		Harmful:   []string{"suicídio", "me matar", "se mata", "acabar com tudo", "automutilação"},
+	},
 }
 
 type ContentFilter struct {
-	level string
+	level     string
+	languages map[string]bool
 }
 
-func NewContentFilter(level string) *ContentFilter {
-	return &ContentFilter{level: level}
+// NewContentFilter creates a filter for the given languages. English is
+// always checked since it is commonly mixed into other languages.
+func NewContentFilter(level string, languages ...string) *ContentFilter {
+	enabled := map[string]bool{DefaultLanguage: true}
+	for _, lang := range languages {
+		if _, ok := lexicons[lang]; ok {
+			enabled[lang] = true
+		}
+	}
+	return &ContentFilter{level: level, languages: enabled}
+}
+
+// lexiconsFor returns the lexicons to check text against: English plus the
+// detected language when it is enabled.
+func (cf *ContentFilter) lexiconsFor(text string) []Lexicon {
+	result := []Lexicon{lexicons[DefaultLanguage]}
+	if lang := DetectLanguage(text); lang != DefaultLanguage && cf.languages[lang] {
+		result = append(result, lexicons[lang])
+	}
+	return result
 }
 
 func (cf *ContentFilter) ContainsProfanity(text string) bool {
 	lowerText := strings.ToLower(text)
-	for _, word := range profanityList {
-		if strings.Contains(lowerText, word) {
-			return true
+	for _, lexicon := range cf.lexiconsFor(text) {
+		for _, word := range lexicon.Profanity {
+			if strings.Contains(lowerText, word) {
+				return true
+			}
 		}
 	}
 	return false
@@ -32,9 +75,11 @@ func (cf *ContentFilter) ContainsProfanity(text string) bool {
 
 func (cf *ContentFilter) ContainsHarmfulContent(text string) bool {
 	lowerText := strings.ToLower(text)
-	for _, keyword := range harmfulKeywords {
-		if strings.Contains(lowerText, keyword) {
-			return true
+	for _, lexicon := range cf.lexiconsFor(text) {
+		for _, keyword := range lexicon.Harmful {
+			if strings.Contains(lowerText, keyword) {
+				return true
+			}
 		}
 	}
 	return false
@@ -57,3 +102,28 @@ func (cf *ContentFilter) CheckContent(text string) []string {
 func (cf *ContentFilter) ShouldAutoFlag(text string) bool {
 	return len(cf.CheckContent(text)) > 0
 }
+
+// ToxicityScore returns a rough 0.0-1.0 toxicity estimate based on keyword matches
+func (cf *ContentFilter) ToxicityScore(text string) float64 {
+	lowerText := strings.ToLower(text)
+	score := 0.0
+
+	for _, lexicon := range cf.lexiconsFor(text) {
+		for _, word := range lexicon.Profanity {
+			if strings.Contains(lowerText, word) {
+				score += 0.25
+			}
+		}
+
+		for _, keyword := range lexicon.Harmful {
+			if strings.Contains(lowerText, keyword) {
+				score += 0.5
+			}
+		}
+	}
+
+	if score > 1.0 {
+		score = 1.0
+	}
+	return score
+}
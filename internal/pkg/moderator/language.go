@@ -0,0 +1,60 @@
+package moderator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultLanguage is used when detection is inconclusive
+const DefaultLanguage = "en"
+
+// stopwords are high-frequency words used to guess the language of a post
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "you", "to", "it", "my", "of", "that", "this", "with", "have", "i'm", "was"},
+	"es": {"el", "la", "que", "de", "y", "no", "es", "por", "para", "con", "los", "mi", "estoy", "pero"},
+	"fr": {"le", "la", "les", "et", "est", "je", "pas", "que", "des", "une", "pour", "avec", "mon", "suis"},
+	"de": {"der", "die", "das", "und", "ist", "ich", "nicht", "ein", "eine", "mit", "zu", "mein", "bin", "auch"},
+	"pt": {"o", "a", "que", "de", "e", "não", "é", "um", "uma", "para", "com", "eu", "meu", "estou"},
+}
+
+// letterHints are characters that strongly suggest a language
+var letterHints = map[rune]string{
+	'ñ': "es", '¿': "es", '¡': "es",
+	'ß': "de", 'ä': "de", 'ö': "de", 'ü': "de",
+	'ã': "pt", 'õ': "pt",
+	'è': "fr", 'ê': "fr", 'à': "fr", 'ù': "fr", 'œ': "fr",
+}
+
+// DetectLanguage returns a best-guess ISO 639-1 code for the text, falling
+// back to DefaultLanguage when there is not enough signal.
+func DetectLanguage(text string) string {
+	scores := make(map[string]int)
+
+	lowerText := strings.ToLower(text)
+	for _, r := range lowerText {
+		if lang, ok := letterHints[r]; ok {
+			scores[lang] += 2
+		}
+	}
+
+	words := strings.FieldsFunc(lowerText, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, word := range words {
+		for lang, list := range stopwords {
+			for _, stopword := range list {
+				if word == stopword {
+					scores[lang]++
+				}
+			}
+		}
+	}
+
+	best, bestScore := DefaultLanguage, 0
+	for _, lang := range []string{"en", "es", "fr", "de", "pt"} {
+		if scores[lang] > bestScore {
+			best, bestScore = lang, scores[lang]
+		}
+	}
+	return best
+}
@@ -0,0 +1,48 @@
+package moderator
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "I have been sober for ten days and this is hard", "en"},
+		{"spanish", "Estoy muy cansado pero no voy a recaer", "es"},
+		{"french", "Je suis fatigué mais je ne vais pas abandonner", "fr"},
+		{"german", "Ich bin müde aber ich gebe nicht auf", "de"},
+		{"portuguese", "Eu estou cansado mas não vou desistir", "pt"},
+		{"no signal", "12345", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.text); got != tt.want {
+				t.Errorf("DetectLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentFilter_CheckContent_PerLanguage(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages []string
+		text      string
+		wantFlags int
+	}{
+		{"spanish enabled", []string{"es"}, "Estoy harto de esta mierda", 1},
+		{"spanish disabled", nil, "Estoy harto de esta mierda", 0},
+		{"english always checked", []string{"de"}, "this is shit", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := NewContentFilter("medium", tt.languages...).CheckContent(tt.text)
+			if len(flags) != tt.wantFlags {
+				t.Errorf("CheckContent() = %v, want %d flags", flags, tt.wantFlags)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package moderator
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Action is an automatic moderation action taken when a rule triggers
+type Action string
+
+const (
+	ActionHide         Action = "hide"
+	ActionNotifyAuthor Action = "notify_author"
+	ActionOpenCase     Action = "open_case"
+)
+
+var severityRank = map[string]int{
+	"low":      1,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+// Rules configures when the engine triggers and what it does
+type Rules struct {
+	Enabled                bool
+	ToxicityThreshold      float64
+	ReportThreshold        int
+	AbuseSeverityThreshold string
+	Actions                []Action
+}
+
+// Signals are the inputs evaluated against the rules for a single piece of content
+type Signals struct {
+	ToxicityScore float64
+	ReportScore   float64 // Reputation-weighted number of reports
+	AbuseSeverity string  // Empty when no abuse was detected
+}
+
+// Decision is the outcome of evaluating signals against the rules
+type Decision struct {
+	Triggered bool
+	Reasons   []string
+	Actions   []Action
+}
+
+// Has reports whether the decision includes the given action
+func (d *Decision) Has(action Action) bool {
+	for _, a := range d.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// RulesEngine evaluates moderation signals against configured thresholds.
+// Its rules can be changed while it's in use.
+type RulesEngine struct {
+	rules atomic.Pointer[Rules]
+}
+
+// NewRulesEngine creates a rules engine from the given rules
+func NewRulesEngine(rules Rules) *RulesEngine {
+	e := &RulesEngine{}
+	e.SetRules(rules)
+	return e
+}
+
+// Rules returns the current rules
+func (e *RulesEngine) Rules() Rules {
+	return *e.rules.Load()
+}
+
+// SetRules replaces the rules used by later evaluations
+func (e *RulesEngine) SetRules(rules Rules) {
+	e.rules.Store(&rules)
+}
+
+// Enabled reports whether auto-moderation is switched on
+func (e *RulesEngine) Enabled() bool {
+	return e.rules.Load().Enabled
+}
+
+// Evaluate checks the signals against every rule and returns the resulting decision
+func (e *RulesEngine) Evaluate(signals Signals) *Decision {
+	rules := e.rules.Load()
+	decision := &Decision{}
+	if !rules.Enabled {
+		return decision
+	}
+
+	if rules.ToxicityThreshold > 0 && signals.ToxicityScore >= rules.ToxicityThreshold {
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("toxicity score %.2f", signals.ToxicityScore))
+	}
+
+	if rules.ReportThreshold > 0 && signals.ReportScore >= float64(rules.ReportThreshold) {
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("%.1f weighted reports", signals.ReportScore))
+	}
+
+	if rank, ok := severityRank[signals.AbuseSeverity]; ok && rank >= severityRank[rules.AbuseSeverityThreshold] {
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("%s severity abuse", signals.AbuseSeverity))
+	}
+
+	if len(decision.Reasons) > 0 {
+		decision.Triggered = true
+		decision.Actions = rules.Actions
+	}
+
+	return decision
+}
@@ -0,0 +1,40 @@
+package moderator
+
+import "testing"
+
+func TestRulesEngine_Evaluate(t *testing.T) {
+	rules := Rules{
+		Enabled:                true,
+		ToxicityThreshold:      0.8,
+		ReportThreshold:        3,
+		AbuseSeverityThreshold: "high",
+		Actions:                []Action{ActionHide, ActionOpenCase},
+	}
+
+	tests := []struct {
+		name      string
+		rules     Rules
+		signals   Signals
+		triggered bool
+	}{
+		{"clean content", rules, Signals{ToxicityScore: 0.1}, false},
+		{"toxicity over threshold", rules, Signals{ToxicityScore: 0.9}, true},
+		{"report score reached", rules, Signals{ReportScore: 3}, true},
+		{"downweighted reports", rules, Signals{ReportScore: 1.5}, false},
+		{"abuse below severity", rules, Signals{AbuseSeverity: "medium"}, false},
+		{"abuse at severity", rules, Signals{AbuseSeverity: "critical"}, true},
+		{"disabled", Rules{ToxicityThreshold: 0.8}, Signals{ToxicityScore: 1.0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := NewRulesEngine(tt.rules).Evaluate(tt.signals)
+			if decision.Triggered != tt.triggered {
+				t.Errorf("Evaluate() triggered = %v, want %v", decision.Triggered, tt.triggered)
+			}
+			if tt.triggered && !decision.Has(ActionHide) {
+				t.Errorf("Evaluate() actions = %v, want hide", decision.Actions)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+func TestAPNSProvider_SendNotification(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			t.Errorf("proto = %s, want HTTP/2", r.Proto)
+		}
+		if r.Header.Get("apns-topic") != "com.example.app" {
+			t.Errorf("apns-topic = %q", r.Header.Get("apns-topic"))
+		}
+
+		bearer := strings.TrimPrefix(r.Header.Get("authorization"), "bearer ")
+		token, err := jwtlib.Parse(bearer, func(*jwtlib.Token) (interface{}, error) { return &key.PublicKey, nil })
+		if err != nil || token.Header["kid"] != "KEY123" {
+			t.Errorf("invalid provider token: %v", err)
+		}
+
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "dead":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered","timestamp":1700000000000}`))
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"reason":"InternalServerError"}`))
+		}
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	invalidator := &recordingInvalidator{}
+	provider := newAPNSProvider(
+		APNSConfig{KeyID: "KEY123", TeamID: "TEAM123", BundleID: "com.example.app"},
+		server.URL, key, server.Client(), invalidator, zap.NewNop(),
+	)
+
+	tests := []struct {
+		token   string
+		wantErr bool
+	}{
+		{"live", false},
+		{"dead", true},
+		{"broken", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			err := provider.SendNotification(context.Background(), NewNotification().WithToken(tt.token).WithTitle("Hi").Build())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SendNotification() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if len(invalidator.tokens) != 1 || invalidator.tokens[0] != "dead" {
+		t.Errorf("invalidated tokens = %v, want [dead]", invalidator.tokens)
+	}
+}
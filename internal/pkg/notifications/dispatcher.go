@@ -0,0 +1,448 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	dispatchStream      = "notifications:dispatch"
+	dispatchRetrySet    = "notifications:dispatch:retry"
+	dispatchDeadLetter  = "notifications:dispatch:dead"
+	dispatchCollapseSet = "notifications:dispatch:collapse"
+	dispatchGroup       = "notification-dispatchers"
+	dispatchClaimIdle   = time.Minute
+	dispatchPollTimeout = 5 * time.Second
+)
+
+// Sender delivers a notification through a named provider
+type Sender interface {
+	SendNotification(ctx context.Context, providerName string, notification *PushNotification) error
+}
+
+// DispatcherConfig configures the notification dispatch workers
+type DispatcherConfig struct {
+	Workers      int
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	// CollapseWindow is how long notifications sharing a collapse key are
+	// aggregated after the first one is sent; zero disables aggregation
+	CollapseWindow time.Duration
+}
+
+// DispatchJob is a single queued delivery
+type DispatchJob struct {
+	ID           string            `json:"id"`
+	Provider     string            `json:"provider"`
+	Notification *PushNotification `json:"notification"`
+	Attempt      int               `json:"attempt"`
+	EnqueuedAt   time.Time         `json:"enqueued_at"`
+	LastError    string            `json:"last_error,omitempty"`
+}
+
+// Dispatcher queues push notifications on a Redis stream and delivers them from
+// a worker pool. Failed deliveries are retried with exponential backoff and
+// moved to a dead-letter stream once they run out of attempts.
+//
+// Notifications with a collapse key are aggregated per device: the first is
+// sent immediately, and any that follow within the collapse window are folded
+// into one summary sent when the window closes, replacing the first on the device.
+type Dispatcher struct {
+	client   *redis.Client
+	sender   Sender
+	config   DispatcherConfig
+	consumer string
+	logger   *zap.Logger
+}
+
+// NewDispatcher creates a dispatcher that delivers through sender
+func NewDispatcher(client *redis.Client, sender Sender, config DispatcherConfig, logger *zap.Logger) *Dispatcher {
+	if config.Workers <= 0 {
+		config.Workers = 8
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.InitialDelay <= 0 {
+		config.InitialDelay = time.Second
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = 5 * time.Minute
+	}
+
+	return &Dispatcher{
+		client:   client,
+		sender:   sender,
+		config:   config,
+		consumer: uuid.New().String(),
+		logger:   logger,
+	}
+}
+
+// SendNotification queues a notification for delivery through the named provider
+func (d *Dispatcher) SendNotification(ctx context.Context, providerName string, notification *PushNotification) error {
+	job := &DispatchJob{
+		ID:           uuid.New().String(),
+		Provider:     providerName,
+		Notification: notification,
+		EnqueuedAt:   time.Now(),
+	}
+	if notification.CollapseKey != "" && d.config.CollapseWindow > 0 {
+		return d.collapse(ctx, job)
+	}
+	return d.enqueue(ctx, job)
+}
+
+// collapse records a notification in its device's aggregation window. The
+// first notification in a window is sent right away; later ones only update
+// the pending summary.
+func (d *Dispatcher) collapse(ctx context.Context, job *DispatchJob) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s:%s:%s:%s", dispatchCollapseSet, job.Provider, job.Notification.Token, job.Notification.CollapseKey)
+	pipe := d.client.TxPipeline()
+	count := pipe.HIncrBy(ctx, key, "count", 1)
+	pipe.HSet(ctx, key, "job", payload)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to collapse notification: %w", err)
+	}
+
+	if count.Val() > 1 {
+		metrics.NotificationDispatchTotal.WithLabelValues(job.Provider, "collapsed").Inc()
+		return nil
+	}
+
+	// First in the window: close the window later and send this one now
+	due := time.Now().Add(d.config.CollapseWindow)
+	pipe = d.client.TxPipeline()
+	pipe.PExpire(ctx, key, 2*d.config.CollapseWindow)
+	pipe.ZAdd(ctx, dispatchCollapseSet, redis.Z{Score: float64(due.UnixMilli()), Member: key})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to schedule collapse window: %w", err)
+	}
+	return d.enqueue(ctx, job)
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, job *DispatchJob) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	if err := d.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: dispatchStream,
+		Values: map[string]interface{}{"job": payload},
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+
+	metrics.NotificationDispatchTotal.WithLabelValues(job.Provider, "enqueued").Inc()
+	return nil
+}
+
+// Run starts the worker pool and retry scheduler, blocking until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) error {
+	err := d.client.XGroupCreateMkStream(ctx, dispatchStream, dispatchGroup, "0").Err()
+	if err != nil && !redis.HasErrorPrefix(err, "BUSYGROUP") {
+		return fmt.Errorf("failed to create dispatch consumer group: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < d.config.Workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			d.work(ctx, fmt.Sprintf("%s-%d", d.consumer, worker))
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.schedule(ctx)
+	}()
+
+	wg.Wait()
+	return nil
+}
+
+// work reads jobs from the stream and delivers them until ctx is cancelled
+func (d *Dispatcher) work(ctx context.Context, consumer string) {
+	for ctx.Err() == nil {
+		streams, err := d.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    dispatchGroup,
+			Consumer: consumer,
+			Streams:  []string{dispatchStream, ">"},
+			Count:    10,
+			Block:    dispatchPollTimeout,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			d.logger.Warn("Failed to read notification dispatch stream", zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				d.handle(ctx, msg)
+			}
+		}
+	}
+}
+
+// handle delivers one stream message and acknowledges it once it has been
+// delivered, rescheduled or dead-lettered
+func (d *Dispatcher) handle(ctx context.Context, msg redis.XMessage) {
+	raw, _ := msg.Values["job"].(string)
+
+	var job DispatchJob
+	if err := json.Unmarshal([]byte(raw), &job); err != nil || job.Notification == nil {
+		d.logger.Error("Dropping malformed notification job", zap.String("message_id", msg.ID))
+		d.ack(ctx, msg.ID)
+		return
+	}
+
+	job.Attempt++
+	err := d.sender.SendNotification(ctx, job.Provider, job.Notification)
+	switch {
+	case err == nil:
+		metrics.NotificationDispatchTotal.WithLabelValues(job.Provider, "delivered").Inc()
+		metrics.NotificationDispatchLatency.WithLabelValues(job.Provider).Observe(time.Since(job.EnqueuedAt).Seconds())
+	case isPermanentError(err):
+		// Dead tokens are already invalidated by the provider; retrying cannot succeed
+		metrics.NotificationDispatchTotal.WithLabelValues(job.Provider, "dropped").Inc()
+	case job.Attempt >= d.config.MaxAttempts:
+		job.LastError = err.Error()
+		if dlqErr := d.deadLetter(ctx, &job); dlqErr != nil {
+			d.logger.Error("Failed to dead-letter notification", zap.String("job_id", job.ID), zap.Error(dlqErr))
+			return
+		}
+		metrics.NotificationDispatchTotal.WithLabelValues(job.Provider, "dead_lettered").Inc()
+		d.logger.Warn("Notification moved to dead-letter stream",
+			zap.String("job_id", job.ID),
+			zap.String("provider", job.Provider),
+			zap.Int("attempts", job.Attempt),
+			zap.Error(err))
+	default:
+		job.LastError = err.Error()
+		if retryErr := d.scheduleRetry(ctx, &job); retryErr != nil {
+			d.logger.Error("Failed to schedule notification retry", zap.String("job_id", job.ID), zap.Error(retryErr))
+			return
+		}
+		metrics.NotificationDispatchTotal.WithLabelValues(job.Provider, "retried").Inc()
+	}
+
+	d.ack(ctx, msg.ID)
+}
+
+func (d *Dispatcher) ack(ctx context.Context, messageID string) {
+	pipe := d.client.TxPipeline()
+	pipe.XAck(ctx, dispatchStream, dispatchGroup, messageID)
+	pipe.XDel(ctx, dispatchStream, messageID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		d.logger.Warn("Failed to acknowledge notification job", zap.String("message_id", messageID), zap.Error(err))
+	}
+}
+
+func (d *Dispatcher) scheduleRetry(ctx context.Context, job *DispatchJob) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	due := time.Now().Add(retryDelay(d.config.InitialDelay, d.config.MaxDelay, job.Attempt))
+	return d.client.ZAdd(ctx, dispatchRetrySet, redis.Z{
+		Score:  float64(due.UnixMilli()),
+		Member: payload,
+	}).Err()
+}
+
+func (d *Dispatcher) deadLetter(ctx context.Context, job *DispatchJob) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return d.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: dispatchDeadLetter,
+		MaxLen: 100000,
+		Approx: true,
+		Values: map[string]interface{}{"job": payload},
+	}).Err()
+}
+
+// schedule moves due retries back onto the stream and reclaims jobs left
+// pending by workers that died mid-delivery
+func (d *Dispatcher) schedule(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.flushCollapsed(ctx)
+			d.requeueDueRetries(ctx)
+			d.reclaimStale(ctx)
+			d.recordBacklog(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) requeueDueRetries(ctx context.Context) {
+	due, err := d.client.ZRangeByScore(ctx, dispatchRetrySet, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: 100,
+	}).Result()
+	if err != nil {
+		d.logger.Warn("Failed to read notification retries", zap.Error(err))
+		return
+	}
+
+	for _, payload := range due {
+		// Only the instance that removes the entry requeues it
+		removed, err := d.client.ZRem(ctx, dispatchRetrySet, payload).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
+		if err := d.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: dispatchStream,
+			Values: map[string]interface{}{"job": payload},
+		}).Err(); err != nil {
+			d.logger.Error("Failed to requeue notification retry", zap.Error(err))
+		}
+	}
+}
+
+// flushCollapsed closes aggregation windows that have expired, sending one
+// summary for every window that collected more than one notification
+func (d *Dispatcher) flushCollapsed(ctx context.Context) {
+	due, err := d.client.ZRangeByScore(ctx, dispatchCollapseSet, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: 100,
+	}).Result()
+	if err != nil {
+		d.logger.Warn("Failed to read notification collapse windows", zap.Error(err))
+		return
+	}
+
+	for _, key := range due {
+		removed, err := d.client.ZRem(ctx, dispatchCollapseSet, key).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
+
+		pipe := d.client.TxPipeline()
+		fields := pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		if _, err := pipe.Exec(ctx); err != nil {
+			d.logger.Warn("Failed to close notification collapse window", zap.Error(err))
+			continue
+		}
+
+		count, _ := strconv.Atoi(fields.Val()["count"])
+		if count < 2 {
+			continue
+		}
+
+		var job DispatchJob
+		if err := json.Unmarshal([]byte(fields.Val()["job"]), &job); err != nil || job.Notification == nil {
+			continue
+		}
+		if err := d.enqueue(ctx, summarize(&job, count)); err != nil {
+			d.logger.Error("Failed to send collapsed notification", zap.Error(err))
+		}
+	}
+}
+
+// summarize turns the latest notification in a window into a summary of count notifications
+func summarize(job *DispatchJob, count int) *DispatchJob {
+	notification := *job.Notification
+	notification.Data = make(map[string]string, len(job.Notification.Data)+1)
+	for k, v := range job.Notification.Data {
+		notification.Data[k] = v
+	}
+	notification.Data["collapsed_count"] = strconv.Itoa(count)
+	if notification.CollapseTemplate != "" {
+		notification.Body = fmt.Sprintf(notification.CollapseTemplate, count)
+	}
+
+	return &DispatchJob{
+		ID:           uuid.New().String(),
+		Provider:     job.Provider,
+		Notification: &notification,
+		EnqueuedAt:   time.Now(),
+	}
+}
+
+func (d *Dispatcher) reclaimStale(ctx context.Context) {
+	msgs, _, err := d.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   dispatchStream,
+		Group:    dispatchGroup,
+		Consumer: d.consumer,
+		MinIdle:  dispatchClaimIdle,
+		Start:    "0",
+		Count:    100,
+	}).Result()
+	if err != nil {
+		return
+	}
+
+	for _, msg := range msgs {
+		d.handle(ctx, msg)
+	}
+}
+
+func (d *Dispatcher) recordBacklog(ctx context.Context) {
+	if pending, err := d.client.XLen(ctx, dispatchStream).Result(); err == nil {
+		metrics.NotificationDispatchBacklog.WithLabelValues("queued").Set(float64(pending))
+	}
+	if retries, err := d.client.ZCard(ctx, dispatchRetrySet).Result(); err == nil {
+		metrics.NotificationDispatchBacklog.WithLabelValues("retry").Set(float64(retries))
+	}
+	if dead, err := d.client.XLen(ctx, dispatchDeadLetter).Result(); err == nil {
+		metrics.NotificationDispatchBacklog.WithLabelValues("dead_letter").Set(float64(dead))
+	}
+}
+
+// retryDelay returns the exponential backoff before the given attempt is retried
+func retryDelay(initial, max time.Duration, attempt int) time.Duration {
+	delay := initial
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
+
+// isPermanentError reports whether a delivery error cannot be fixed by retrying
+func isPermanentError(err error) bool {
+	var invalid interface{ IsInvalidToken() bool }
+	if errors.As(err, &invalid) {
+		return invalid.IsInvalidToken()
+	}
+	var missing *ProviderNotFoundError
+	return errors.As(err, &missing)
+}
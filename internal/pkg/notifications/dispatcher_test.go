@@ -0,0 +1,82 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 5, want: 16 * time.Second},
+		{attempt: 10, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt %d", tt.attempt), func(t *testing.T) {
+			if got := retryDelay(time.Second, time.Minute, tt.attempt); got != tt.want {
+				t.Errorf("retryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPermanentError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "network error", err: errors.New("connection reset"), want: false},
+		{name: "fcm unavailable", err: &FCMError{Err: errors.New("service unavailable")}, want: false},
+		{name: "fcm unregistered", err: &FCMError{ErrorCode: "UNREGISTERED", Err: errors.New("requested entity was not found")}, want: true},
+		{name: "apns bad token", err: &APNSError{StatusCode: 400, Reason: "BadDeviceToken"}, want: true},
+		{name: "apns throttled", err: &APNSError{StatusCode: 429, Reason: "TooManyRequests"}, want: false},
+		{name: "unknown provider", err: &ProviderNotFoundError{Provider: "sms"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPermanentError(tt.err); got != tt.want {
+				t.Errorf("isPermanentError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	job := &DispatchJob{
+		ID:       "original",
+		Provider: "fcm",
+		Attempt:  2,
+		Notification: NewNotification().
+			WithToken("token").
+			WithTitle("New Response").
+			WithBody("alex responded to your post").
+			WithData("post_id", "p1").
+			WithCollapseKey("responses:p1", "%d new responses to your post").
+			Build(),
+	}
+
+	got := summarize(job, 5)
+
+	if got.ID == job.ID || got.Attempt != 0 || got.Provider != "fcm" {
+		t.Errorf("summarize() job = %+v, want a fresh fcm job", got)
+	}
+	if got.Notification.Body != "5 new responses to your post" {
+		t.Errorf("summarize() body = %q", got.Notification.Body)
+	}
+	if got.Notification.Data["collapsed_count"] != "5" || got.Notification.Data["post_id"] != "p1" {
+		t.Errorf("summarize() data = %v", got.Notification.Data)
+	}
+	if _, ok := job.Notification.Data["collapsed_count"]; ok {
+		t.Errorf("summarize() mutated the original notification data")
+	}
+}
@@ -0,0 +1,75 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/secrets"
+)
+
+// SMTPConfig configures the SMTP email sender
+type SMTPConfig struct {
+	Host           string
+	Port           int
+	Username       string
+	PasswordSecret string // Secret key holding the SMTP password
+	From           string
+}
+
+// SMTPSender sends plain-text email through an SMTP relay using STARTTLS
+type SMTPSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPSender creates an SMTP sender with the password loaded from the secret manager
+func NewSMTPSender(ctx context.Context, cfg SMTPConfig, secretManager secrets.SecretManager) (*SMTPSender, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("SMTP host is not configured")
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("SMTP from address is not configured")
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		password, err := secretManager.GetSecret(ctx, cfg.PasswordSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SMTP password: %w", err)
+		}
+		auth = smtp.PlainAuth("", cfg.Username, password, cfg.Host)
+	}
+
+	return &SMTPSender{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		auth: auth,
+		from: cfg.From,
+	}, nil
+}
+
+// SendEmail sends a plain-text email
+func (s *SMTPSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{to}, buildEmail(s.from, to, subject, body))
+}
+
+// buildEmail formats an RFC 5322 message, stripping line breaks from headers
+func buildEmail(from, to, subject, body string) []byte {
+	header := strings.NewReplacer("\r", "", "\n", "")
+
+	var msg strings.Builder
+	msg.WriteString("From: " + header.Replace(from) + "\r\n")
+	msg.WriteString("To: " + header.Replace(to) + "\r\n")
+	msg.WriteString("Subject: " + header.Replace(subject) + "\r\n")
+	msg.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(msg.String())
+}
@@ -0,0 +1,218 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"github.com/yourorg/anonymous-support/internal/pkg/secrets"
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+)
+
+// FCMMaxBatchSize is the largest number of messages the SDK accepts per SendEach call
+const FCMMaxBatchSize = 500
+
+// FCMConfig configures the FCM provider
+type FCMConfig struct {
+	ProjectID         string // Defaults to the project in the service account credentials
+	CredentialsSecret string // Secret key holding the service account JSON
+}
+
+// FCMProvider implements Firebase Cloud Messaging push notifications using
+// the Firebase Admin SDK
+type FCMProvider struct {
+	logger      *zap.Logger
+	client      *messaging.Client
+	invalidator TokenInvalidator
+}
+
+// NewFCMProvider creates an FCM provider authenticated with service account
+// credentials loaded from the secret manager
+func NewFCMProvider(
+	ctx context.Context,
+	cfg FCMConfig,
+	secretManager secrets.SecretManager,
+	invalidator TokenInvalidator,
+	logger *zap.Logger,
+) (*FCMProvider, error) {
+	credentialsJSON, err := secretManager.GetSecret(ctx, cfg.CredentialsSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load FCM credentials: %w", err)
+	}
+
+	client, err := newFCMClient(ctx, cfg.ProjectID, option.WithCredentialsJSON([]byte(credentialsJSON)))
+	if err != nil {
+		return nil, err
+	}
+	return newFCMProvider(client, invalidator, logger), nil
+}
+
+func newFCMClient(ctx context.Context, projectID string, opts ...option.ClientOption) (*messaging.Client, error) {
+	app, err := firebase.NewApp(ctx, &firebase.Config{ProjectID: projectID}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Firebase app: %w", err)
+	}
+	client, err := app.Messaging(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create FCM client: %w", err)
+	}
+	return client, nil
+}
+
+func newFCMProvider(client *messaging.Client, invalidator TokenInvalidator, logger *zap.Logger) *FCMProvider {
+	return &FCMProvider{
+		logger:      logger,
+		client:      client,
+		invalidator: invalidator,
+	}
+}
+
+// FCMError wraps an error returned by the FCM API
+type FCMError struct {
+	ErrorCode string // Set when the token will never work again, e.g. UNREGISTERED
+	Err       error
+}
+
+func newFCMError(err error) *FCMError {
+	fcmErr := &FCMError{Err: err}
+	switch {
+	case messaging.IsUnregistered(err):
+		fcmErr.ErrorCode = "UNREGISTERED"
+	case messaging.IsSenderIDMismatch(err):
+		fcmErr.ErrorCode = "SENDER_ID_MISMATCH"
+	case messaging.IsInvalidArgument(err) && strings.Contains(strings.ToLower(err.Error()), "registration token"):
+		fcmErr.ErrorCode = "INVALID_ARGUMENT"
+	}
+	return fcmErr
+}
+
+func (e *FCMError) Error() string {
+	return fmt.Sprintf("fcm: %v", e.Err)
+}
+
+func (e *FCMError) Unwrap() error {
+	return e.Err
+}
+
+// IsInvalidToken reports whether the error means the device token will never work again
+func (e *FCMError) IsInvalidToken() bool {
+	return e.ErrorCode != ""
+}
+
+func buildFCMMessage(notification *PushNotification) *messaging.Message {
+	msg := &messaging.Message{
+		Token: notification.Token,
+		Notification: &messaging.Notification{
+			Title: notification.Title,
+			Body:  notification.Body,
+		},
+	}
+	if len(notification.Data) > 0 {
+		msg.Data = notification.Data
+	}
+	if notification.Sound != "" || notification.CollapseKey != "" {
+		msg.Android = &messaging.AndroidConfig{CollapseKey: notification.CollapseKey}
+		if notification.Sound != "" {
+			msg.Android.Notification = &messaging.AndroidNotification{Sound: notification.Sound}
+		}
+	}
+	if notification.Badge != nil || notification.Sound != "" || notification.CollapseKey != "" {
+		msg.APNS = &messaging.APNSConfig{Payload: &messaging.APNSPayload{Aps: &messaging.Aps{Badge: notification.Badge, Sound: notification.Sound}}}
+		if notification.CollapseKey != "" {
+			msg.APNS.Headers = map[string]string{"apns-collapse-id": notification.CollapseKey}
+		}
+	}
+	return msg
+}
+
+// SendNotification sends a single push notification via FCM
+func (p *FCMProvider) SendNotification(ctx context.Context, notification *PushNotification) error {
+	start := time.Now()
+	_, err := p.client.Send(ctx, buildFCMMessage(notification))
+	metrics.PushNotificationDuration.WithLabelValues("fcm").Observe(time.Since(start).Seconds())
+
+	if err == nil {
+		metrics.PushNotificationsTotal.WithLabelValues("fcm", "success").Inc()
+		return nil
+	}
+
+	fcmErr := p.handleError(ctx, notification, err)
+	if fcmErr.IsInvalidToken() {
+		return fcmErr
+	}
+	return fmt.Errorf("failed to send FCM notification: %w", fcmErr)
+}
+
+// handleError records a failed send and marks dead tokens invalid
+func (p *FCMProvider) handleError(ctx context.Context, notification *PushNotification, err error) *FCMError {
+	fcmErr := newFCMError(err)
+	if !fcmErr.IsInvalidToken() {
+		metrics.PushNotificationsTotal.WithLabelValues("fcm", "error").Inc()
+		p.logger.Error("Failed to send FCM notification", zap.Error(err))
+		return fcmErr
+	}
+
+	metrics.PushNotificationsTotal.WithLabelValues("fcm", "invalid_token").Inc()
+	metrics.PushInvalidTokensTotal.WithLabelValues("fcm", fcmErr.ErrorCode).Inc()
+	if p.invalidator != nil {
+		if invErr := p.invalidator.MarkTokenInvalid(ctx, notification.Token, fcmErr.ErrorCode); invErr != nil {
+			p.logger.Warn("Failed to mark FCM token invalid", zap.Error(invErr))
+		}
+	}
+	return fcmErr
+}
+
+// SendBatch sends notifications with SendEach in chunks of FCMMaxBatchSize.
+// Dead tokens are cleaned up and not counted as failures.
+func (p *FCMProvider) SendBatch(ctx context.Context, notifications []*PushNotification) error {
+	var failed, invalid int
+
+	for start := 0; start < len(notifications); start += FCMMaxBatchSize {
+		end := min(start+FCMMaxBatchSize, len(notifications))
+		chunk := notifications[start:end]
+
+		messages := make([]*messaging.Message, len(chunk))
+		for i, notification := range chunk {
+			messages[i] = buildFCMMessage(notification)
+		}
+
+		sendStart := time.Now()
+		resp, err := p.client.SendEach(ctx, messages)
+		metrics.PushNotificationDuration.WithLabelValues("fcm").Observe(time.Since(sendStart).Seconds())
+		if err != nil {
+			metrics.PushNotificationsTotal.WithLabelValues("fcm", "error").Add(float64(len(chunk)))
+			return fmt.Errorf("failed to send FCM batch: %w", err)
+		}
+
+		for i, result := range resp.Responses {
+			if result.Success {
+				metrics.PushNotificationsTotal.WithLabelValues("fcm", "success").Inc()
+				continue
+			}
+			if p.handleError(ctx, chunk[i], result.Error).IsInvalidToken() {
+				invalid++
+			} else {
+				failed++
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	p.logger.Info("FCM batch sent",
+		zap.Int("count", len(notifications)),
+		zap.Int("failure_count", failed),
+		zap.Int("invalid_token_count", invalid))
+
+	if failed > 0 {
+		return fmt.Errorf("failed to send %d of %d FCM notifications", failed, len(notifications))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+)
+
+type recordingInvalidator struct {
+	mu     sync.Mutex
+	tokens []string
+}
+
+func (r *recordingInvalidator) MarkTokenInvalid(ctx context.Context, token, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func TestFCMProvider_SendBatch(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		var req struct {
+			Message struct {
+				Token string `json:"token"`
+			} `json:"message"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+
+		if req.Message.Token == "dead" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND",
+				"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"projects/test/messages/1"}`))
+	}))
+	defer server.Close()
+
+	invalidator := &recordingInvalidator{}
+	client, err := newFCMClient(context.Background(), "test", option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := newFCMProvider(client, invalidator, zap.NewNop())
+
+	notifications := make([]*PushNotification, FCMMaxBatchSize+1)
+	for i := range notifications {
+		notifications[i] = NewNotification().WithToken("live").WithTitle("Hi").Build()
+	}
+	notifications[FCMMaxBatchSize] = NewNotification().WithToken("dead").WithTitle("Hi").Build()
+
+	if err := provider.SendBatch(context.Background(), notifications); err != nil {
+		t.Fatalf("SendBatch() error = %v, want nil for dead tokens", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != int32(len(notifications)) {
+		t.Errorf("requests = %d, want %d", got, len(notifications))
+	}
+	if len(invalidator.tokens) != 1 || invalidator.tokens[0] != "dead" {
+		t.Errorf("invalidated tokens = %v, want [dead]", invalidator.tokens)
+	}
+}
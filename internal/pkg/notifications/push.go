@@ -15,6 +15,13 @@ type PushNotification struct {
 	Data  map[string]string
 	Badge *int
 	Sound string
+
+	// CollapseKey groups notifications that replace each other on the device.
+	// The dispatcher also aggregates notifications sharing a key into one summary.
+	CollapseKey string
+	// CollapseTemplate formats the summary body from the number of collapsed
+	// notifications, e.g. "%d new responses to your post"
+	CollapseTemplate string
 }
 
 // PushNotificationProvider defines the interface for push notification providers
@@ -23,190 +30,19 @@ type PushNotificationProvider interface {
 	SendBatch(ctx context.Context, notifications []*PushNotification) error
 }
 
-// FCMProvider implements Firebase Cloud Messaging push notifications
-type FCMProvider struct {
-	logger    *zap.Logger
-	projectID string
-	// client *messaging.Client
-}
-
-// NewFCMProvider creates a new FCM provider
-func NewFCMProvider(projectID string, logger *zap.Logger) *FCMProvider {
-	return &FCMProvider{
-		logger:    logger,
-		projectID: projectID,
-	}
-}
-
-// SendNotification sends a single push notification via FCM
-func (p *FCMProvider) SendNotification(ctx context.Context, notification *PushNotification) error {
-	// TODO: Implement FCM integration
-	// This requires:
-	// 1. Firebase Admin SDK for Go
-	// 2. Service account credentials
-	// 3. FCM API enabled in Firebase project
-
-	/*
-		message := &messaging.Message{
-			Token: notification.Token,
-			Notification: &messaging.Notification{
-				Title: notification.Title,
-				Body:  notification.Body,
-			},
-			Data: notification.Data,
-			Android: &messaging.AndroidConfig{
-				Notification: &messaging.AndroidNotification{
-					Sound: notification.Sound,
-				},
-			},
-			APNS: &messaging.APNSConfig{
-				Payload: &messaging.APNSPayload{
-					Aps: &messaging.Aps{
-						Badge: notification.Badge,
-						Sound: notification.Sound,
-					},
-				},
-			},
-		}
-
-		response, err := p.client.Send(ctx, message)
-		if err != nil {
-			p.logger.Error("Failed to send FCM notification",
-				zap.Error(err),
-				zap.String("token", notification.Token))
-			return fmt.Errorf("failed to send FCM notification: %w", err)
-		}
-
-		p.logger.Info("FCM notification sent",
-			zap.String("message_id", response))
-		return nil
-	*/
-
-	p.logger.Info("FCM notification (placeholder)",
-		zap.String("title", notification.Title),
-		zap.String("body", notification.Body))
-	return nil
+// TokenInvalidator is told when a provider reports a device token as dead so
+// it can be removed from future sends
+type TokenInvalidator interface {
+	MarkTokenInvalid(ctx context.Context, token, reason string) error
 }
 
-// SendBatch sends multiple notifications in batch
-func (p *FCMProvider) SendBatch(ctx context.Context, notifications []*PushNotification) error {
-	// TODO: Implement batch sending
-	// FCM supports sending up to 500 messages in a single batch
-
-	/*
-		messages := make([]*messaging.Message, len(notifications))
-		for i, notif := range notifications {
-			messages[i] = &messaging.Message{
-				Token: notif.Token,
-				Notification: &messaging.Notification{
-					Title: notif.Title,
-					Body:  notif.Body,
-				},
-				Data: notif.Data,
-			}
-		}
-
-		br, err := p.client.SendAll(ctx, messages)
-		if err != nil {
-			return fmt.Errorf("failed to send batch: %w", err)
-		}
-
-		p.logger.Info("Batch notifications sent",
-			zap.Int("success_count", br.SuccessCount),
-			zap.Int("failure_count", br.FailureCount))
-
-		return nil
-	*/
-
-	p.logger.Info("FCM batch notification (placeholder)",
-		zap.Int("count", len(notifications)))
-	return nil
+// ProviderNotFoundError is returned when no provider is registered under a name
+type ProviderNotFoundError struct {
+	Provider string
 }
 
-// APNSProvider implements Apple Push Notification Service
-type APNSProvider struct {
-	logger     *zap.Logger
-	bundleID   string
-	production bool
-	// client *apns2.Client
-}
-
-// NewAPNSProvider creates a new APNS provider
-func NewAPNSProvider(bundleID string, production bool, logger *zap.Logger) *APNSProvider {
-	return &APNSProvider{
-		logger:     logger,
-		bundleID:   bundleID,
-		production: production,
-	}
-}
-
-// SendNotification sends a single push notification via APNS
-func (p *APNSProvider) SendNotification(ctx context.Context, notification *PushNotification) error {
-	// TODO: Implement APNS integration
-	// This requires:
-	// 1. APNS HTTP/2 client (e.g., github.com/sideshow/apns2)
-	// 2. APNS authentication key or certificate
-	// 3. Apple Developer account with push notification capability
-
-	/*
-		payload := &payload.Payload{
-			Alert: payload.Alert{
-				Title: notification.Title,
-				Body:  notification.Body,
-			},
-			Badge: notification.Badge,
-			Sound: notification.Sound,
-		}
-
-		for k, v := range notification.Data {
-			payload.Custom(k, v)
-		}
-
-		apnsNotification := &apns2.Notification{
-			DeviceToken: notification.Token,
-			Topic:       p.bundleID,
-			Payload:     payload,
-		}
-
-		res, err := p.client.Push(apnsNotification)
-		if err != nil {
-			p.logger.Error("Failed to send APNS notification",
-				zap.Error(err),
-				zap.String("token", notification.Token))
-			return fmt.Errorf("failed to send APNS notification: %w", err)
-		}
-
-		if res.Sent() {
-			p.logger.Info("APNS notification sent",
-				zap.String("apns_id", res.ApnsID))
-		} else {
-			p.logger.Error("APNS notification failed",
-				zap.Int("status_code", res.StatusCode),
-				zap.String("reason", res.Reason))
-			return fmt.Errorf("APNS notification failed: %s", res.Reason)
-		}
-
-		return nil
-	*/
-
-	p.logger.Info("APNS notification (placeholder)",
-		zap.String("title", notification.Title),
-		zap.String("body", notification.Body))
-	return nil
-}
-
-// SendBatch sends multiple notifications (APNS doesn't have native batching)
-func (p *APNSProvider) SendBatch(ctx context.Context, notifications []*PushNotification) error {
-	// APNS doesn't support batch sending, so send individually
-	for _, notif := range notifications {
-		if err := p.SendNotification(ctx, notif); err != nil {
-			p.logger.Error("Failed to send notification in batch",
-				zap.Error(err),
-				zap.String("token", notif.Token))
-			// Continue sending other notifications even if one fails
-		}
-	}
-	return nil
+func (e *ProviderNotFoundError) Error() string {
+	return fmt.Sprintf("provider %s not found", e.Provider)
 }
 
 // MultiProviderNotificationService sends notifications via multiple providers
@@ -233,7 +69,7 @@ func (s *MultiProviderNotificationService) RegisterProvider(name string, provide
 func (s *MultiProviderNotificationService) SendNotification(ctx context.Context, providerName string, notification *PushNotification) error {
 	provider, ok := s.providers[providerName]
 	if !ok {
-		return fmt.Errorf("provider %s not found", providerName)
+		return &ProviderNotFoundError{Provider: providerName}
 	}
 
 	return provider.SendNotification(ctx, notification)
@@ -289,6 +125,14 @@ func (b *NotificationBuilder) WithSound(sound string) *NotificationBuilder {
 	return b
 }
 
+// WithCollapseKey groups notifications so later ones replace earlier ones and
+// are summarised with template when several arrive close together
+func (b *NotificationBuilder) WithCollapseKey(key, template string) *NotificationBuilder {
+	b.notification.CollapseKey = key
+	b.notification.CollapseTemplate = template
+	return b
+}
+
 // Build returns the built notification
 func (b *NotificationBuilder) Build() *PushNotification {
 	return b.notification
@@ -0,0 +1,54 @@
+package objectstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore_Put(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+
+	body := "id,status\n1,pending\n"
+	if err := store.Put(context.Background(), "exports/reports/day.csv", strings.NewReader(body), int64(len(body)), "text/csv"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "exports", "reports", "day.csv"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != body {
+		t.Errorf("object = %q, want %q", got, body)
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(dir, "exports", "reports"))
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want the temporary file removed", len(entries))
+	}
+}
+
+func TestLocalStore_RejectsKeysOutsideDir(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+
+	for _, key := range []string{"../escape.csv", "/etc/passwd", ""} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/csv"); err == nil {
+			t.Errorf("Put(%q) succeeded, want an error", key)
+		}
+	}
+}
+
+func TestLocalStore_ShortBody(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+
+	if err := store.Put(context.Background(), "short.csv", strings.NewReader("abc"), 10, "text/csv"); err == nil {
+		t.Fatal("Put succeeded with fewer bytes than the size given")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "short.csv")); !os.IsNotExist(err) {
+		t.Errorf("partial object was left in place: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// unsignedPayload lets uploads stream without hashing the body first; TLS
+// protects it in transit
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config configures an S3 bucket or an S3-compatible store such as MinIO.
+// Credentials come from the default chain: environment, shared config, or
+// the pod's IAM role.
+type S3Config struct {
+	Bucket   string
+	Region   string
+	Endpoint string // Empty for AWS; otherwise the store's base URL, addressed path-style
+	Prefix   string // Prepended to keys, e.g. "analytics/"
+}
+
+// S3Store writes objects to an S3 bucket with signed PutObject requests
+type S3Store struct {
+	client      *http.Client
+	signer      *v4.Signer
+	credentials aws.CredentialsProvider
+	baseURL     *url.URL // Objects are at baseURL + "/" + key
+	region      string
+	prefix      string
+}
+
+// NewS3Store creates a store writing to the configured bucket
+func NewS3Store(ctx context.Context, cfg S3Config) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is required")
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return newS3Store(cfg, awsCfg.Credentials)
+}
+
+func newS3Store(cfg S3Config, credentials aws.CredentialsProvider) (*S3Store, error) {
+	var baseURL *url.URL
+	if cfg.Endpoint == "" {
+		baseURL = &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)}
+	} else {
+		endpoint, err := url.Parse(cfg.Endpoint)
+		if err != nil || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+		}
+		baseURL = endpoint.JoinPath(cfg.Bucket)
+	}
+
+	return &S3Store{
+		client: &http.Client{Timeout: 10 * time.Minute},
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			// S3 signs the path as sent rather than escaping it a second time
+			o.DisableURIPathEscaping = true
+		}),
+		credentials: credentials,
+		baseURL:     baseURL,
+		region:      cfg.Region,
+		prefix:      cfg.Prefix,
+	}, nil
+}
+
+// Put uploads body in a single request, so objects are limited to the 5 GB
+// S3 allows for one PutObject
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	credentials, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+	if err := s.signer.SignHTTP(ctx, credentials, req, unsignedPayload, "s3", s.region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign S3 request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to upload %s: S3 returned %s: %s", key, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func (s *S3Store) objectURL(key string) string {
+	segments := strings.Split(s.prefix+key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return s.baseURL.String() + "/" + strings.Join(segments, "/")
+}
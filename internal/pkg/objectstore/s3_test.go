@@ -0,0 +1,79 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+var testCredentials = aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+	return aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, nil
+})
+
+func TestS3Store_Put(t *testing.T) {
+	var gotPath, gotAuth, gotBody, gotType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+	}))
+	defer server.Close()
+
+	store, err := newS3Store(S3Config{Bucket: "analytics", Region: "us-east-1", Endpoint: server.URL, Prefix: "prod/"}, testCredentials)
+	if err != nil {
+		t.Fatalf("newS3Store: %v", err)
+	}
+
+	body := "day,posts\n"
+	if err := store.Put(context.Background(), "exports/content_volume/day.csv", strings.NewReader(body), int64(len(body)), "text/csv"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if gotPath != "/analytics/prod/exports/content_volume/day.csv" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(gotAuth, "/us-east-1/s3/aws4_request") {
+		t.Errorf("Authorization = %q, want a SigV4 S3 signature", gotAuth)
+	}
+	if gotBody != body || gotType != "text/csv" {
+		t.Errorf("body = %q, content type = %q", gotBody, gotType)
+	}
+}
+
+func TestS3Store_PutError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+	}))
+	defer server.Close()
+
+	store, err := newS3Store(S3Config{Bucket: "analytics", Region: "us-east-1", Endpoint: server.URL}, testCredentials)
+	if err != nil {
+		t.Fatalf("newS3Store: %v", err)
+	}
+
+	err = store.Put(context.Background(), "day.csv", strings.NewReader("x"), 1, "text/csv")
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Put error = %v, want the S3 error included", err)
+	}
+}
+
+func TestS3Store_AWSURL(t *testing.T) {
+	store, err := newS3Store(S3Config{Bucket: "analytics", Region: "eu-west-1"}, testCredentials)
+	if err != nil {
+		t.Fatalf("newS3Store: %v", err)
+	}
+	if got := store.objectURL("exports/a b.csv"); got != "https://analytics.s3.eu-west-1.amazonaws.com/exports/a%20b.csv" {
+		t.Errorf("objectURL = %q", got)
+	}
+}
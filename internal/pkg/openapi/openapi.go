@@ -0,0 +1,318 @@
+// Package openapi builds OpenAPI 3 documents from protobuf descriptors, so
+// REST routes over the RPC services are described by the same messages the
+// services use. Schemas follow the protobuf JSON mapping.
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// Version is the OpenAPI version documents are written in
+	Version = "3.0.3"
+
+	bearerScheme = "bearerAuth"
+	errorSchema  = "connect.Error"
+)
+
+// pathParamPattern matches {name} segments in an operation path
+var pathParamPattern = regexp.MustCompile(`\{([a-z_][a-z0-9_]*)\}`)
+
+// Operation is one REST route to document
+type Operation struct {
+	Method   string // GET, POST, PATCH, PUT or DELETE
+	Path     string // e.g. /v1/posts/{post_id}; parameters are request field names
+	ID       string
+	Tag      string
+	Summary  string
+	Public   bool // Callable without an access token
+	Request  protoreflect.MessageDescriptor
+	Response protoreflect.MessageDescriptor
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// PathItem holds the operations on one path, keyed by lowercase method
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"` // path or query
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Generate documents the operations. Path parameters are taken from the
+// request message; the rest of its fields are query parameters for GET and
+// DELETE, and the JSON body otherwise.
+func Generate(info Info, operations []Operation) *Document {
+	g := &generator{schemas: map[string]*Schema{
+		errorSchema: {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"code":    {Type: "string"},
+				"message": {Type: "string"},
+				"details": {Type: "array", Items: &Schema{Type: "object"}},
+			},
+		},
+	}}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, op := range operations {
+		item, ok := doc.Paths[op.Path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[op.Path] = item
+		}
+		(*item)[strings.ToLower(op.Method)] = g.operation(op)
+	}
+	return doc
+}
+
+type generator struct {
+	schemas map[string]*Schema
+}
+
+func (g *generator) operation(op Operation) *OperationObject {
+	obj := &OperationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Responses: map[string]Response{
+			"200": {
+				Description: "OK",
+				Content:     jsonContent(g.message(op.Response)),
+			},
+			"default": {
+				Description: "Error",
+				Content:     jsonContent(&Schema{Ref: schemaRef(errorSchema)}),
+			},
+		},
+		// An empty list marks the operation as not needing credentials
+		Security: []map[string][]string{},
+	}
+	if op.Tag != "" {
+		obj.Tags = []string{op.Tag}
+	}
+	if !op.Public {
+		obj.Security = append(obj.Security, map[string][]string{bearerScheme: {}})
+	}
+
+	inPath := map[string]bool{}
+	for _, name := range PathParams(op.Path) {
+		inPath[name] = true
+		param := Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		if field := op.Request.Fields().ByName(protoreflect.Name(name)); field != nil {
+			param.Schema = g.field(field)
+		}
+		obj.Parameters = append(obj.Parameters, param)
+	}
+
+	if !HasBody(op.Method) {
+		fields := op.Request.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			field := fields.Get(i)
+			if inPath[string(field.Name())] || field.IsMap() || (field.Kind() == protoreflect.MessageKind && !isScalarMessage(field.Message())) {
+				continue
+			}
+			obj.Parameters = append(obj.Parameters, Parameter{Name: field.JSONName(), In: "query", Schema: g.field(field)})
+		}
+		return obj
+	}
+
+	if op.Request.Fields().Len() > len(inPath) {
+		obj.RequestBody = &RequestBody{Required: true, Content: jsonContent(g.message(op.Request))}
+	}
+	return obj
+}
+
+// HasBody reports whether requests with the method carry a JSON body
+func HasBody(method string) bool {
+	return method != http.MethodGet && method != http.MethodDelete
+}
+
+// message returns a reference to the message's schema, adding it and the
+// messages it uses to the components
+func (g *generator) message(md protoreflect.MessageDescriptor) *Schema {
+	if schema := wellKnown(md); schema != nil {
+		return schema
+	}
+
+	name := string(md.FullName())
+	if _, ok := g.schemas[name]; !ok {
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		// Registered before the fields so recursive messages terminate
+		g.schemas[name] = schema
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			field := fields.Get(i)
+			schema.Properties[field.JSONName()] = g.field(field)
+		}
+	}
+	return &Schema{Ref: schemaRef(name)}
+}
+
+func (g *generator) field(fd protoreflect.FieldDescriptor) *Schema {
+	if fd.IsMap() {
+		return &Schema{Type: "object", AdditionalProperties: g.singular(fd.MapValue())}
+	}
+	if fd.IsList() {
+		return &Schema{Type: "array", Items: g.singular(fd)}
+	}
+	return g.singular(fd)
+}
+
+// singular maps one value of the field's kind to its protobuf JSON form.
+// 64-bit integers are strings in JSON.
+func (g *generator) singular(fd protoreflect.FieldDescriptor) *Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return &Schema{Type: "string", Enum: names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.message(fd.Message())
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// wellKnown returns the JSON form of well-known types that aren't objects
+func wellKnown(md protoreflect.MessageDescriptor) *Schema {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration", "google.protobuf.FieldMask":
+		return &Schema{Type: "string"}
+	case "google.protobuf.Struct", "google.protobuf.Empty", "google.protobuf.Any":
+		return &Schema{Type: "object"}
+	case "google.protobuf.ListValue":
+		return &Schema{Type: "array", Items: &Schema{}}
+	case "google.protobuf.Value":
+		return &Schema{}
+	case "google.protobuf.StringValue":
+		return &Schema{Type: "string"}
+	case "google.protobuf.BoolValue":
+		return &Schema{Type: "boolean"}
+	case "google.protobuf.Int32Value":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return &Schema{Type: "string", Format: "int64"}
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue":
+		return &Schema{Type: "number"}
+	}
+	return nil
+}
+
+// isScalarMessage reports whether the message is written as a single JSON
+// value, so it can be passed as a query parameter
+func isScalarMessage(md protoreflect.MessageDescriptor) bool {
+	schema := wellKnown(md)
+	return schema != nil && schema.Type != "object" && schema.Type != ""
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func schemaRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+// PathParams returns the parameter names in an operation path, in order
+func PathParams(path string) []string {
+	matches := pathParamPattern.FindAllStringSubmatch(path, -1)
+	names := make([]string, len(matches))
+	for i, match := range matches {
+		names[i] = match[1]
+	}
+	return names
+}
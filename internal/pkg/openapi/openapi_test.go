@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGenerateGetUsesPathAndQueryParameters(t *testing.T) {
+	api := (&apipb.Api{}).ProtoReflect().Descriptor()
+	doc := Generate(Info{Title: "test", Version: "1"}, []Operation{{
+		Method:   "GET",
+		Path:     "/v1/apis/{name}",
+		ID:       "GetApi",
+		Public:   true,
+		Request:  api,
+		Response: api,
+	}})
+
+	op := (*doc.Paths["/v1/apis/{name}"])["get"]
+	if op == nil {
+		t.Fatal("operation not documented")
+	}
+	if op.RequestBody != nil {
+		t.Error("GET should not have a request body")
+	}
+	if len(op.Security) != 0 {
+		t.Errorf("public operation has security %v", op.Security)
+	}
+
+	params := map[string]Parameter{}
+	for _, p := range op.Parameters {
+		params[p.Name] = p
+	}
+	if p := params["name"]; p.In != "path" || !p.Required {
+		t.Errorf("name parameter = %+v, want a required path parameter", p)
+	}
+	if p := params["version"]; p.In != "query" {
+		t.Errorf("version parameter = %+v, want a query parameter", p)
+	}
+	if p := params["syntax"]; p.Schema == nil || len(p.Schema.Enum) == 0 {
+		t.Errorf("syntax parameter = %+v, want enum names", p)
+	}
+	// Lists of messages can't be written as query parameters
+	if _, ok := params["methods"]; ok {
+		t.Error("methods should not be a query parameter")
+	}
+}
+
+func TestGeneratePostReferencesRequestSchema(t *testing.T) {
+	api := (&apipb.Api{}).ProtoReflect().Descriptor()
+	doc := Generate(Info{Title: "test", Version: "1"}, []Operation{{
+		Method:   "POST",
+		Path:     "/v1/apis",
+		ID:       "CreateApi",
+		Request:  api,
+		Response: api,
+	}})
+
+	op := (*doc.Paths["/v1/apis"])["post"]
+	if op.RequestBody == nil || op.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/google.protobuf.Api" {
+		t.Fatalf("request body = %+v, want a reference to google.protobuf.Api", op.RequestBody)
+	}
+	if len(op.Security) != 1 {
+		t.Errorf("security = %v, want bearer auth", op.Security)
+	}
+
+	schema := doc.Components.Schemas["google.protobuf.Api"]
+	if schema == nil {
+		t.Fatal("google.protobuf.Api schema missing")
+	}
+	if methods := schema.Properties["methods"]; methods.Type != "array" || methods.Items.Ref != "#/components/schemas/google.protobuf.Method" {
+		t.Errorf("methods = %+v, want an array of google.protobuf.Method", methods)
+	}
+	if _, ok := schema.Properties["sourceContext"]; !ok {
+		t.Error("properties should use JSON names")
+	}
+	if _, ok := doc.Components.Schemas["google.protobuf.Method"]; !ok {
+		t.Error("nested message schema missing")
+	}
+}
+
+func TestWellKnownTypes(t *testing.T) {
+	schema := wellKnown((&timestamppb.Timestamp{}).ProtoReflect().Descriptor())
+	if schema == nil || schema.Type != "string" || schema.Format != "date-time" {
+		t.Errorf("Timestamp schema = %+v, want a date-time string", schema)
+	}
+	if wellKnown((&apipb.Api{}).ProtoReflect().Descriptor()) != nil {
+		t.Error("Api is not a well-known JSON type")
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	got := PathParams("/v1/circles/{circle_id}/members/{user_id}")
+	if len(got) != 2 || got[0] != "circle_id" || got[1] != "user_id" {
+		t.Errorf("PathParams = %v", got)
+	}
+}
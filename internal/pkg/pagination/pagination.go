@@ -1,9 +1,12 @@
 package pagination
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 const (
@@ -122,3 +125,26 @@ func CalculatePage(offset, limit int) int {
 	}
 	return (offset / limit) + 1
 }
+
+// Cursor marks the last item of a page listed newest first. Items created at
+// the same time are ordered by ID, so pages neither skip nor repeat them.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// EncodeCursor returns c as an opaque token for clients to send back
+func EncodeCursor(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token from EncodeCursor
+func DecodeCursor(token string) (Cursor, error) {
+	var c Cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.ID == "" {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+	return c, nil
+}
@@ -0,0 +1,26 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := Cursor{CreatedAt: time.Date(2025, 3, 1, 12, 30, 0, 123456789, time.UTC), ID: "65e1c0ffee0000000000abcd"}
+
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Errorf("DecodeCursor() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	for _, token := range []string{"", "not base64!", "bm90IGpzb24", "e30"} {
+		if _, err := DecodeCursor(token); err == nil {
+			t.Errorf("DecodeCursor(%q) succeeded, want error", token)
+		}
+	}
+}
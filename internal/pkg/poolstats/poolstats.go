@@ -0,0 +1,124 @@
+// Package poolstats exports connection pool statistics for Postgres, MongoDB
+// and Redis to the connection_pool_* metrics
+package poolstats
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Database labels used on the pool metrics
+const (
+	LabelPostgres = "postgres"
+	LabelMongoDB  = "mongodb"
+	LabelRedis    = "redis"
+)
+
+// MongoPoolMonitor counts MongoDB pool connections from pool events, since the
+// driver has no stats snapshot. Checkout waits are recorded as they happen.
+type MongoPoolMonitor struct {
+	open  atomic.Int64
+	inUse atomic.Int64
+}
+
+// NewMongoPoolMonitor creates a monitor; pass Monitor() to the client options
+func NewMongoPoolMonitor() *MongoPoolMonitor {
+	return &MongoPoolMonitor{}
+}
+
+// Monitor returns the driver pool monitor that feeds m
+func (m *MongoPoolMonitor) Monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: m.handle}
+}
+
+func (m *MongoPoolMonitor) handle(e *event.PoolEvent) {
+	switch e.Type {
+	case event.ConnectionCreated:
+		m.open.Add(1)
+	case event.ConnectionClosed:
+		m.open.Add(-1)
+	case event.GetSucceeded:
+		m.inUse.Add(1)
+		metrics.ConnectionPoolWaitDuration.WithLabelValues(LabelMongoDB).Observe(e.Duration.Seconds())
+	case event.ConnectionReturned:
+		m.inUse.Add(-1)
+	}
+}
+
+// Sampler periodically exports pool size, idle connections and connection
+// waits. Postgres and Redis only report cumulative wait totals, so each sample
+// observes the average wait of the checkouts that waited since the last one.
+type Sampler struct {
+	postgres *sql.DB
+	mongo    *MongoPoolMonitor
+	redis    *redis.Client
+
+	postgresWaits waitTotals
+	redisWaits    waitTotals
+}
+
+type waitTotals struct {
+	count    int64
+	duration time.Duration
+}
+
+// NewSampler creates a sampler; any of the pools may be nil
+func NewSampler(postgres *sql.DB, mongo *MongoPoolMonitor, redis *redis.Client) *Sampler {
+	return &Sampler{postgres: postgres, mongo: mongo, redis: redis}
+}
+
+// Run samples the pools every interval until ctx is cancelled
+func (s *Sampler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.Sample()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sample exports the current pool statistics once
+func (s *Sampler) Sample() {
+	if s.postgres != nil {
+		stats := s.postgres.Stats()
+		metrics.ConnectionPoolSizeGauge.WithLabelValues(LabelPostgres).Set(float64(stats.OpenConnections))
+		metrics.ConnectionPoolIdleGauge.WithLabelValues(LabelPostgres).Set(float64(stats.Idle))
+		s.postgresWaits.observe(LabelPostgres, stats.WaitCount, stats.WaitDuration)
+	}
+
+	if s.mongo != nil {
+		open := s.mongo.open.Load()
+		metrics.ConnectionPoolSizeGauge.WithLabelValues(LabelMongoDB).Set(float64(open))
+		metrics.ConnectionPoolIdleGauge.WithLabelValues(LabelMongoDB).Set(float64(max(open-s.mongo.inUse.Load(), 0)))
+	}
+
+	if s.redis != nil {
+		stats := s.redis.PoolStats()
+		metrics.ConnectionPoolSizeGauge.WithLabelValues(LabelRedis).Set(float64(stats.TotalConns))
+		metrics.ConnectionPoolIdleGauge.WithLabelValues(LabelRedis).Set(float64(stats.IdleConns))
+		s.redisWaits.observe(LabelRedis, int64(stats.WaitCount), time.Duration(stats.WaitDurationNs))
+	}
+}
+
+// observe records the average wait since the previous totals
+func (w *waitTotals) observe(database string, count int64, duration time.Duration) {
+	waits := count - w.count
+	waited := duration - w.duration
+	w.count, w.duration = count, duration
+
+	if waits > 0 {
+		metrics.ConnectionPoolWaitDuration.WithLabelValues(database).Observe((waited / time.Duration(waits)).Seconds())
+	}
+}
@@ -0,0 +1,83 @@
+// Package ratelimit enforces request limits shared by every API instance
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript keeps a sorted set of request timestamps per key. It
+// drops timestamps older than the window, admits the request when fewer than
+// limit remain, and otherwise reports how long until the oldest one expires.
+// Redis time is used so instances with skewed clocks agree on the window.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local member = ARGV[3]
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+if count < limit then
+	redis.call('ZADD', key, now, member)
+	redis.call('PEXPIRE', key, window)
+	return {1, limit - count - 1, 0}
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+return {0, 0, tonumber(oldest[2]) + window - now}
+`)
+
+// Rule allows Limit requests per sliding Window
+type Rule struct {
+	Name   string // Distinguishes rules sharing a key, e.g. posts vs. all requests
+	Limit  int
+	Window time.Duration
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // When not allowed, how long until a request would be
+}
+
+// Limiter checks sliding-window rate limits in Redis
+type Limiter struct {
+	client *redis.Client
+}
+
+// NewLimiter creates a limiter backed by client
+func NewLimiter(client *redis.Client) *Limiter {
+	return &Limiter{client: client}
+}
+
+// Allow records a request for key under rule if it is within the limit
+func (l *Limiter) Allow(ctx context.Context, rule Rule, key string) (*Result, error) {
+	if rule.Limit <= 0 || rule.Window <= 0 {
+		return &Result{Allowed: true}, nil
+	}
+
+	redisKey := fmt.Sprintf("ratelimit:%s:%s", rule.Name, key)
+	values, err := slidingWindowScript.Run(ctx, l.client, []string{redisKey},
+		rule.Window.Milliseconds(), rule.Limit, uuid.New().String()).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if len(values) != 3 {
+		return nil, fmt.Errorf("unexpected rate limit result %v", values)
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
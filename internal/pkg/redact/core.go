@@ -0,0 +1,64 @@
+package redact
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// core redacts the message and fields of every entry before the wrapped core
+// encodes them. Structured values logged with zap.Any are passed through, so
+// they must not hold personal data.
+type core struct {
+	zapcore.Core
+}
+
+// NewCore wraps c so its output never carries emails, tokens, IP addresses
+// or credentials. Use it with zap.WrapCore.
+func NewCore(c zapcore.Core) zapcore.Core {
+	return &core{Core: c}
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = String(entry.Message)
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		out[i] = redactField(field)
+	}
+	return out
+}
+
+func redactField(field zapcore.Field) zapcore.Field {
+	if IsSensitiveKey(field.Key) {
+		return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: redacted}
+	}
+
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = String(field.String)
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok {
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: String(err.Error())}
+		}
+	case zapcore.StringerType:
+		if stringer, ok := field.Interface.(fmt.Stringer); ok {
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: String(stringer.String())}
+		}
+	}
+	return field
+}
@@ -0,0 +1,75 @@
+// Package redact scrubs personal data and credentials from log output and
+// error messages
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// patterns are applied in order; credentials go first so a URL's userinfo is
+// removed before the email pattern could match part of it
+var patterns = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`://[^/\s:@]+:[^@\s]+@`), "://" + redacted + "@"},
+	{regexp.MustCompile(`(?i)\b(password|passwd|pwd|secret|token|api_key)=\S+`), "${1}=" + redacted},
+	{regexp.MustCompile(`(?i)\bbearer\s+\S+`), "Bearer " + redacted},
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), "[token]"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[email]"},
+	{regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), "[ip]"},
+	{regexp.MustCompile(`\b(?:[0-9A-Fa-f]{1,4}:){5,7}[0-9A-Fa-f]{1,4}\b|[0-9A-Fa-f]{0,4}::(?:[0-9A-Fa-f]{1,4}:?)+`), "[ip]"},
+}
+
+// sensitiveKeys are log field keys whose values are dropped whole
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"authorization": true,
+	"secret":        true,
+	"email":         true,
+	"ip":            true,
+	"actor_ip":      true,
+	"client_ip":     true,
+	"remote_addr":   true,
+	"dsn":           true,
+}
+
+// String returns s with emails, tokens, IP addresses and credentials replaced
+func String(s string) string {
+	for _, p := range patterns {
+		s = p.re.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// IsSensitiveKey reports whether a log field with this key holds a value that
+// must never be logged
+func IsSensitiveKey(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// internalMarkers appear in errors from drivers and the runtime, which can
+// name hosts, credentials, queries or source files
+var internalMarkers = []string{
+	"postgres://", "postgresql://", "mongodb://", "mongodb+srv://", "redis://",
+	"host=", "password=", "dbname=",
+	"pq: ", "sql: ", "mongo: ", "redis: ", "dial tcp", "connection refused",
+	"goroutine ", ".go:", "panic:",
+}
+
+// LeaksInternals reports whether msg carries infrastructure details that a
+// client must not see
+func LeaksInternals(msg string) bool {
+	for _, marker := range internalMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
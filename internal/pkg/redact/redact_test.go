@@ -0,0 +1,78 @@
+package redact
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"email", "sent digest to jane.doe@example.com", "sent digest to [email]"},
+		{"ipv4", "rate limited ip:203.0.113.7", "rate limited ip:[ip]"},
+		{"ipv6", "client 2001:db8:85a3:0:0:8a2e:370:7334 and ::1", "client [ip] and [ip]"},
+		{"jwt", "token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig rejected", "token [token] rejected"},
+		{"bearer", "header Bearer abc123", "header Bearer [REDACTED]"},
+		{"dsn url", "dial postgres://app:s3cret@db:5432/app failed", "dial postgres://[REDACTED]@db:5432/app failed"},
+		{"dsn keywords", "host=db user=app password=s3cret dbname=app", "host=db user=app password=[REDACTED] dbname=app"},
+		{"clock time kept", "reminder due at 10:30:45", "reminder due at 10:30:45"},
+		{"uuid kept", "user 7c9e6679-7425-40de-944b-e07fc1f90ae7", "user 7c9e6679-7425-40de-944b-e07fc1f90ae7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := String(tt.in); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCore(t *testing.T) {
+	observed, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(observed).WithOptions(zap.WrapCore(NewCore)).
+		With(zap.String("remote_addr", "203.0.113.7:51234"))
+
+	logger.Info("login by jane@example.com",
+		zap.String("email", "jane@example.com"),
+		zap.Error(errors.New("pq: password=s3cret rejected")),
+		zap.String("path", "/auth.v1.AuthService/Login"),
+	)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	if entries[0].Message != "login by [email]" {
+		t.Errorf("message = %q", entries[0].Message)
+	}
+
+	fields := entries[0].ContextMap()
+	want := map[string]interface{}{
+		"remote_addr": "[REDACTED]",
+		"email":       "[REDACTED]",
+		"error":       "pq: password=[REDACTED] rejected",
+		"path":        "/auth.v1.AuthService/Login",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("%s = %v, want %v", key, fields[key], value)
+		}
+	}
+}
+
+func TestLeaksInternals(t *testing.T) {
+	if !LeaksInternals("failed to connect: dial tcp 10.0.0.5:5432: connection refused") {
+		t.Error("driver error not detected")
+	}
+	if LeaksInternals("circle is full") {
+		t.Error("client-safe message flagged")
+	}
+}
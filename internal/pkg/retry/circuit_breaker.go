@@ -0,0 +1,179 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// ErrCircuitOpen matches, with errors.Is, every call rejected by an open
+// circuit breaker
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// OpenError is returned for a call rejected by an open circuit breaker
+type OpenError struct {
+	Breaker    string
+	RetryAfter time.Duration // Until the breaker lets a trial call through
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("circuit breaker %s is open", e.Breaker)
+}
+
+// Is makes errors.Is(err, ErrCircuitOpen) true
+func (e *OpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// BreakerConfig holds circuit breaker configuration
+type BreakerConfig struct {
+	Name         string        // Names the breaker in logs, metrics and errors
+	MaxFailures  int           // Consecutive failures that open the breaker
+	ResetTimeout time.Duration // How long the breaker stays open before a trial call
+	// IsFailure reports whether an error counts against the dependency; errors
+	// it rejects, such as not found, count as successes. Nil counts every error.
+	IsFailure func(error) bool
+}
+
+// CircuitState represents the state of a circuit breaker
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker implements the circuit breaker pattern and is safe for
+// concurrent use. It opens after MaxFailures consecutive failures and rejects
+// calls with an *OpenError for ResetTimeout. Then a single trial call goes
+// through: its success closes the breaker and its failure opens it again.
+type CircuitBreaker struct {
+	config BreakerConfig
+	logger *zap.Logger
+
+	mu           sync.Mutex
+	state        CircuitState
+	failureCount int
+	openedAt     time.Time
+	trialStarted time.Time // Zero unless a half-open trial call is in flight
+}
+
+// NewCircuitBreaker creates a new circuit breaker
+func NewCircuitBreaker(config BreakerConfig, logger *zap.Logger) *CircuitBreaker {
+	if config.MaxFailures <= 0 {
+		config.MaxFailures = 5
+	}
+	if config.ResetTimeout <= 0 {
+		config.ResetTimeout = 30 * time.Second
+	}
+
+	metrics.CircuitBreakerState.WithLabelValues(config.Name).Set(float64(CircuitClosed))
+	return &CircuitBreaker{
+		config: config,
+		logger: logger.With(zap.String("breaker", config.Name)),
+	}
+}
+
+// Execute executes an operation through the circuit breaker
+func (cb *CircuitBreaker) Execute(ctx context.Context, operation Operation) error {
+	if err := cb.Allow(); err != nil {
+		return err
+	}
+	err := operation(ctx)
+	cb.Record(err)
+	return err
+}
+
+// Allow returns an *OpenError if a call must be rejected. Every call it
+// allows must report its outcome to Record.
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if wait := cb.config.ResetTimeout - time.Since(cb.openedAt); wait > 0 {
+			return cb.reject(wait)
+		}
+		cb.setState(CircuitHalfOpen)
+	case CircuitHalfOpen:
+		// A trial that never reported back doesn't keep the breaker half-open
+		if time.Since(cb.trialStarted) < cb.config.ResetTimeout {
+			return cb.reject(time.Second)
+		}
+	default:
+		return nil
+	}
+
+	cb.trialStarted = time.Now()
+	return nil
+}
+
+// Record reports the outcome of a call Allow let through
+func (cb *CircuitBreaker) Record(err error) {
+	failed := err != nil && (cb.config.IsFailure == nil || cb.config.IsFailure(err))
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !failed {
+		cb.failureCount = 0
+		if cb.state == CircuitHalfOpen {
+			cb.logger.Info("Circuit breaker closing after successful trial call")
+			cb.setState(CircuitClosed)
+		}
+		return
+	}
+
+	cb.failureCount++
+	switch {
+	case cb.state == CircuitHalfOpen:
+		cb.logger.Warn("Circuit breaker reopening after failed trial call", zap.Error(err))
+		cb.open()
+	case cb.state == CircuitClosed && cb.failureCount >= cb.config.MaxFailures:
+		cb.logger.Warn("Circuit breaker opening",
+			zap.Int("failure_count", cb.failureCount),
+			zap.Error(err))
+		cb.open()
+	}
+}
+
+// GetState returns the current circuit state
+func (cb *CircuitBreaker) GetState() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+func (cb *CircuitBreaker) open() {
+	cb.openedAt = time.Now()
+	cb.setState(CircuitOpen)
+}
+
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	cb.state = state
+	cb.trialStarted = time.Time{}
+	metrics.CircuitBreakerState.WithLabelValues(cb.config.Name).Set(float64(state))
+}
+
+func (cb *CircuitBreaker) reject(retryAfter time.Duration) error {
+	metrics.CircuitBreakerRejectionsTotal.WithLabelValues(cb.config.Name).Inc()
+	return &OpenError{Breaker: cb.config.Name, RetryAfter: retryAfter}
+}
@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errUnavailable = errors.New("connection refused")
+
+func fail(context.Context) error    { return errUnavailable }
+func succeed(context.Context) error { return nil }
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	cb := NewCircuitBreaker(BreakerConfig{Name: "test", MaxFailures: 3, ResetTimeout: time.Minute}, zap.NewNop())
+	ctx := context.Background()
+
+	_ = cb.Execute(ctx, fail)
+	_ = cb.Execute(ctx, fail)
+	_ = cb.Execute(ctx, succeed)
+	_ = cb.Execute(ctx, fail)
+	_ = cb.Execute(ctx, fail)
+	if cb.GetState() != CircuitClosed {
+		t.Fatal("a success should reset the failure count")
+	}
+
+	_ = cb.Execute(ctx, fail)
+	err := cb.Execute(ctx, succeed)
+
+	var openErr *OpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want an *OpenError", err)
+	}
+	if openErr.Breaker != "test" || openErr.RetryAfter <= 0 || openErr.RetryAfter > time.Minute {
+		t.Errorf("OpenError = %+v", openErr)
+	}
+}
+
+func TestCircuitBreakerIgnoresErrorsThatAreNotFailures(t *testing.T) {
+	notFound := errors.New("not found")
+	cb := NewCircuitBreaker(BreakerConfig{
+		Name:        "test",
+		MaxFailures: 1,
+		IsFailure:   func(err error) bool { return !errors.Is(err, notFound) },
+	}, zap.NewNop())
+
+	err := cb.Execute(context.Background(), func(context.Context) error { return notFound })
+	if err != notFound || cb.GetState() != CircuitClosed {
+		t.Errorf("err = %v, state = %s; want the error returned and the breaker closed", err, cb.GetState())
+	}
+}
+
+func TestCircuitBreakerHalfOpenAllowsOneTrial(t *testing.T) {
+	cb := NewCircuitBreaker(BreakerConfig{Name: "test", MaxFailures: 1, ResetTimeout: 10 * time.Millisecond}, zap.NewNop())
+	_ = cb.Execute(context.Background(), fail)
+	time.Sleep(20 * time.Millisecond)
+
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("trial call rejected: %v", err)
+	}
+	if err := cb.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second call during the trial: err = %v, want rejection", err)
+	}
+
+	cb.Record(errUnavailable)
+	if cb.GetState() != CircuitOpen {
+		t.Fatalf("state = %s after a failed trial, want open", cb.GetState())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := cb.Execute(context.Background(), succeed); err != nil || cb.GetState() != CircuitClosed {
+		t.Fatalf("err = %v, state = %s after a successful trial, want closed", err, cb.GetState())
+	}
+}
+
+func TestCircuitBreakerConcurrentCalls(t *testing.T) {
+	cb := NewCircuitBreaker(BreakerConfig{Name: "test", MaxFailures: 10, ResetTimeout: time.Minute}, zap.NewNop())
+
+	var calls atomic.Int64
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = cb.Execute(context.Background(), func(context.Context) error {
+				calls.Add(1)
+				return errUnavailable
+			})
+		}()
+	}
+	wg.Wait()
+
+	if cb.GetState() != CircuitOpen {
+		t.Errorf("state = %s, want open", cb.GetState())
+	}
+	if calls.Load() < 10 {
+		t.Errorf("calls = %d, want at least MaxFailures before opening", calls.Load())
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 // Config holds retry configuration
 type Config struct {
-	MaxAttempts     int
+	MaxAttempts     int // Below zero retries until the context is done
 	InitialDelay    time.Duration
 	MaxDelay        time.Duration
 	Multiplier      float64
@@ -38,7 +38,7 @@ type Retrier struct {
 
 // NewRetrier creates a new retrier with the given configuration
 func NewRetrier(config Config, logger *zap.Logger) *Retrier {
-	if config.MaxAttempts <= 0 {
+	if config.MaxAttempts == 0 {
 		config.MaxAttempts = 3
 	}
 	if config.InitialDelay <= 0 {
@@ -62,7 +62,7 @@ func (r *Retrier) Do(ctx context.Context, operation Operation) error {
 	var lastErr error
 	delay := r.config.InitialDelay
 
-	for attempt := 1; attempt <= r.config.MaxAttempts; attempt++ {
+	for attempt := 1; r.config.MaxAttempts < 0 || attempt <= r.config.MaxAttempts; attempt++ {
 		// Execute the operation
 		err := operation(ctx)
 		if err == nil {
@@ -85,7 +85,7 @@ func (r *Retrier) Do(ctx context.Context, operation Operation) error {
 		}
 
 		// Check if we have attempts left
-		if attempt >= r.config.MaxAttempts {
+		if r.config.MaxAttempts > 0 && attempt >= r.config.MaxAttempts {
 			r.logger.Error("Operation failed after max attempts",
 				zap.Error(err),
 				zap.Int("max_attempts", r.config.MaxAttempts))
@@ -103,7 +103,7 @@ func (r *Retrier) Do(ctx context.Context, operation Operation) error {
 		case <-time.After(delay):
 			// Continue to next attempt
 		case <-ctx.Done():
-			return fmt.Errorf("operation canceled: %w", ctx.Err())
+			return fmt.Errorf("operation canceled: %w (last error: %w)", ctx.Err(), err)
 		}
 
 		// Calculate next delay with exponential backoff
@@ -148,83 +148,3 @@ func WithCustomRetry(ctx context.Context, logger *zap.Logger, config Config, ope
 	retrier := NewRetrier(config, logger)
 	return retrier.Do(ctx, operation)
 }
-
-// CircuitBreaker implements the circuit breaker pattern
-type CircuitBreaker struct {
-	maxFailures     int
-	resetTimeout    time.Duration
-	failureCount    int
-	lastFailureTime time.Time
-	state           CircuitState
-	logger          *zap.Logger
-}
-
-// CircuitState represents the state of a circuit breaker
-type CircuitState int
-
-const (
-	CircuitClosed CircuitState = iota
-	CircuitOpen
-	CircuitHalfOpen
-)
-
-// NewCircuitBreaker creates a new circuit breaker
-func NewCircuitBreaker(maxFailures int, resetTimeout time.Duration, logger *zap.Logger) *CircuitBreaker {
-	return &CircuitBreaker{
-		maxFailures:  maxFailures,
-		resetTimeout: resetTimeout,
-		state:        CircuitClosed,
-		logger:       logger,
-	}
-}
-
-// Execute executes an operation through the circuit breaker
-func (cb *CircuitBreaker) Execute(ctx context.Context, operation Operation) error {
-	// Check circuit state
-	if cb.state == CircuitOpen {
-		// Check if we should transition to half-open
-		if time.Since(cb.lastFailureTime) >= cb.resetTimeout {
-			cb.logger.Info("Circuit breaker transitioning to half-open state")
-			cb.state = CircuitHalfOpen
-		} else {
-			return fmt.Errorf("circuit breaker is open")
-		}
-	}
-
-	// Execute operation
-	err := operation(ctx)
-
-	if err != nil {
-		cb.recordFailure()
-		return err
-	}
-
-	cb.recordSuccess()
-	return nil
-}
-
-// recordFailure records a failed operation
-func (cb *CircuitBreaker) recordFailure() {
-	cb.failureCount++
-	cb.lastFailureTime = time.Now()
-
-	if cb.failureCount >= cb.maxFailures {
-		cb.logger.Warn("Circuit breaker opening",
-			zap.Int("failure_count", cb.failureCount))
-		cb.state = CircuitOpen
-	}
-}
-
-// recordSuccess records a successful operation
-func (cb *CircuitBreaker) recordSuccess() {
-	if cb.state == CircuitHalfOpen {
-		cb.logger.Info("Circuit breaker closing after successful operation")
-		cb.state = CircuitClosed
-		cb.failureCount = 0
-	}
-}
-
-// GetState returns the current circuit state
-func (cb *CircuitBreaker) GetState() CircuitState {
-	return cb.state
-}
@@ -0,0 +1,46 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestRetrierUnlimitedAttemptsStopAtContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	unavailable := errors.New("connection refused")
+	attempts := 0
+	retrier := NewRetrier(Config{MaxAttempts: -1, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}, zap.NewNop())
+	err := retrier.Do(ctx, func(context.Context) error {
+		attempts++
+		return unavailable
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, unavailable) {
+		t.Errorf("err = %v, want the deadline and the last failure", err)
+	}
+	if attempts <= 3 {
+		t.Errorf("attempts = %d, want retries past the default limit", attempts)
+	}
+}
+
+func TestRetrierUnlimitedAttemptsReturnOnSuccess(t *testing.T) {
+	attempts := 0
+	retrier := NewRetrier(Config{MaxAttempts: -1, InitialDelay: time.Millisecond}, zap.NewNop())
+	err := retrier.Do(context.Background(), func(context.Context) error {
+		attempts++
+		if attempts < 5 {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+
+	if err != nil || attempts != 5 {
+		t.Errorf("err = %v after %d attempts, want success on the 5th", err, attempts)
+	}
+}
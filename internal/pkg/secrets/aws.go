@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"go.uber.org/zap"
+)
+
+// AWSConfig configures the AWS Secrets Manager backend. Credentials come from
+// the default chain: environment, shared config, or the pod's IAM role.
+type AWSConfig struct {
+	Region string
+	Prefix string // Prepended to keys, e.g. "anonymous-support/" reads "anonymous-support/JWT_SECRET"
+}
+
+// awsSecretsClient is the part of the Secrets Manager API the manager uses
+type awsSecretsClient interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// AWSSecretsManager loads secrets from AWS Secrets Manager
+type AWSSecretsManager struct {
+	client awsSecretsClient
+	prefix string
+	cache  *cache
+	logger *zap.Logger
+}
+
+// NewAWSSecretsManager creates a new AWS Secrets Manager client
+func NewAWSSecretsManager(ctx context.Context, cfg AWSConfig, cacheTTL time.Duration, logger *zap.Logger) (*AWSSecretsManager, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &AWSSecretsManager{
+		client: secretsmanager.NewFromConfig(awsCfg),
+		prefix: cfg.Prefix,
+		cache:  newCache(cacheTTL),
+		logger: logger,
+	}, nil
+}
+
+// GetSecret retrieves the current version of a secret from AWS Secrets Manager
+func (m *AWSSecretsManager) GetSecret(ctx context.Context, key string) (string, error) {
+	if value, ok := m.cache.get(key); ok {
+		return value, nil
+	}
+
+	result, err := m.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(m.prefix + key),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s: %w", key, err)
+	}
+
+	var value string
+	switch {
+	case result.SecretString != nil:
+		value = *result.SecretString
+	case result.SecretBinary != nil:
+		value = string(result.SecretBinary)
+	}
+	if value == "" {
+		return "", fmt.Errorf("secret %s is empty", key)
+	}
+
+	m.cache.set(key, value)
+	return value, nil
+}
+
+// GetSecretWithDefault retrieves a secret with a fallback default
+func (m *AWSSecretsManager) GetSecretWithDefault(ctx context.Context, key, defaultValue string) string {
+	value, err := m.GetSecret(ctx, key)
+	if err != nil {
+		m.logger.Warn("Failed to get secret from AWS, using default",
+			zap.String("key", key),
+			zap.Error(err))
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,39 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"go.uber.org/zap"
+)
+
+type fakeAWSClient struct {
+	calls    int
+	secretID string
+}
+
+func (c *fakeAWSClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	c.calls++
+	c.secretID = aws.ToString(params.SecretId)
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String("from-aws")}, nil
+}
+
+func TestAWSSecretsManager_CachesValues(t *testing.T) {
+	client := &fakeAWSClient{}
+	m := &AWSSecretsManager{client: client, prefix: "anonymous-support/", cache: newCache(time.Minute), logger: zap.NewNop()}
+
+	for i := 0; i < 2; i++ {
+		if got, err := m.GetSecret(context.Background(), "JWT_SECRET"); err != nil || got != "from-aws" {
+			t.Fatalf("GetSecret = %q, %v", got, err)
+		}
+	}
+	if client.calls != 1 {
+		t.Errorf("calls = %d, want the second read served from cache", client.calls)
+	}
+	if client.secretID != "anonymous-support/JWT_SECRET" {
+		t.Errorf("secret ID = %q", client.secretID)
+	}
+}
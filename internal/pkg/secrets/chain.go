@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Config selects the secret backends consulted before the environment. A
+// backend is used when its address, region or project is set.
+type Config struct {
+	Vault    VaultConfig
+	AWS      AWSConfig
+	GCP      GCPConfig
+	CacheTTL time.Duration // How long AWS and GCP values are cached
+}
+
+// Enabled reports whether any backend besides the environment is configured
+func (c Config) Enabled() bool {
+	return c.Vault.Address != "" || c.AWS.Region != "" || c.GCP.ProjectID != ""
+}
+
+// NewChain creates a manager trying Vault, AWS Secrets Manager and GCP Secret
+// Manager, as configured, and then the environment
+func NewChain(ctx context.Context, cfg Config, logger *zap.Logger) (*MultiSourceSecretManager, error) {
+	var sources []SecretManager
+	closeSources := func() {
+		_ = NewMultiSourceSecretManager(logger, sources...).Close()
+	}
+
+	if cfg.Vault.Address != "" {
+		vault, err := NewVaultSecretManager(ctx, cfg.Vault, logger)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, vault)
+	}
+	if cfg.AWS.Region != "" {
+		aws, err := NewAWSSecretsManager(ctx, cfg.AWS, cfg.CacheTTL, logger)
+		if err != nil {
+			closeSources()
+			return nil, err
+		}
+		sources = append(sources, aws)
+	}
+	if cfg.GCP.ProjectID != "" {
+		gcp, err := NewGCPSecretManager(ctx, cfg.GCP, cfg.CacheTTL, logger)
+		if err != nil {
+			closeSources()
+			return nil, err
+		}
+		sources = append(sources, gcp)
+	}
+	sources = append(sources, NewEnvSecretManager(logger))
+
+	return NewMultiSourceSecretManager(logger, sources...), nil
+}
+
+// RunRenewal keeps the credentials of sources that expire, such as Vault
+// tokens, valid until ctx is done
+func (m *MultiSourceSecretManager) RunRenewal(ctx context.Context) {
+	for _, source := range m.sources {
+		if renewer, ok := source.(interface{ RunRenewal(context.Context) }); ok {
+			go renewer.RunRenewal(ctx)
+		}
+	}
+}
+
+// Close releases the connections of sources that hold them
+func (m *MultiSourceSecretManager) Close() error {
+	var errs []error
+	for _, source := range m.sources {
+		if closer, ok := source.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
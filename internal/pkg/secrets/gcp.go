@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/googleapis/gax-go/v2"
+	"go.uber.org/zap"
+)
+
+// GCPConfig configures the Google Cloud Secret Manager backend. Credentials
+// come from Application Default Credentials, e.g. Workload Identity.
+type GCPConfig struct {
+	ProjectID string
+	Prefix    string // Prepended to keys, e.g. "anonymous-support-" reads "anonymous-support-JWT_SECRET"
+}
+
+// gcpSecretsClient is the part of the Secret Manager API the manager uses
+type gcpSecretsClient interface {
+	AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error)
+	Close() error
+}
+
+// GCPSecretManager loads secrets from Google Cloud Secret Manager
+type GCPSecretManager struct {
+	client    gcpSecretsClient
+	projectID string
+	prefix    string
+	cache     *cache
+	logger    *zap.Logger
+}
+
+// NewGCPSecretManager creates a new GCP Secret Manager client
+func NewGCPSecretManager(ctx context.Context, cfg GCPConfig, cacheTTL time.Duration, logger *zap.Logger) (*GCPSecretManager, error) {
+	client, err := secretmanager.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP Secret Manager client: %w", err)
+	}
+	return &GCPSecretManager{
+		client:    client,
+		projectID: cfg.ProjectID,
+		prefix:    cfg.Prefix,
+		cache:     newCache(cacheTTL),
+		logger:    logger,
+	}, nil
+}
+
+// GetSecret retrieves the latest version of a secret from GCP Secret Manager
+func (m *GCPSecretManager) GetSecret(ctx context.Context, key string) (string, error) {
+	if value, ok := m.cache.get(key); ok {
+		return value, nil
+	}
+
+	result, err := m.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s/versions/latest", m.projectID, m.prefix+key),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to access secret %s: %w", key, err)
+	}
+
+	value := string(result.GetPayload().GetData())
+	if value == "" {
+		return "", fmt.Errorf("secret %s is empty", key)
+	}
+
+	m.cache.set(key, value)
+	return value, nil
+}
+
+// GetSecretWithDefault retrieves a secret with a fallback default
+func (m *GCPSecretManager) GetSecretWithDefault(ctx context.Context, key, defaultValue string) string {
+	value, err := m.GetSecret(ctx, key)
+	if err != nil {
+		m.logger.Warn("Failed to get secret from GCP, using default",
+			zap.String("key", key),
+			zap.Error(err))
+		return defaultValue
+	}
+	return value
+}
+
+// Close releases the client's connections
+func (m *GCPSecretManager) Close() error {
+	return m.client.Close()
+}
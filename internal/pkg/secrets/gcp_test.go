@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"github.com/googleapis/gax-go/v2"
+	"go.uber.org/zap"
+)
+
+type fakeGCPClient struct {
+	calls int
+	name  string
+}
+
+func (c *fakeGCPClient) AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	c.calls++
+	c.name = req.GetName()
+	return &secretmanagerpb.AccessSecretVersionResponse{
+		Payload: &secretmanagerpb.SecretPayload{Data: []byte("from-gcp")},
+	}, nil
+}
+
+func (c *fakeGCPClient) Close() error { return nil }
+
+func TestGCPSecretManager_ZeroTTLDisablesCache(t *testing.T) {
+	client := &fakeGCPClient{}
+	m := &GCPSecretManager{client: client, projectID: "support-prod", prefix: "api-", cache: newCache(0), logger: zap.NewNop()}
+
+	for i := 0; i < 2; i++ {
+		if got, err := m.GetSecret(context.Background(), "ENCRYPTION_KEY"); err != nil || got != "from-gcp" {
+			t.Fatalf("GetSecret = %q, %v", got, err)
+		}
+	}
+	if client.calls != 2 {
+		t.Errorf("calls = %d, want every read to reach the API", client.calls)
+	}
+	if client.name != "projects/support-prod/secrets/api-ENCRYPTION_KEY/versions/latest" {
+		t.Errorf("name = %q", client.name)
+	}
+}
@@ -44,114 +44,6 @@ func (m *EnvSecretManager) GetSecretWithDefault(ctx context.Context, key, defaul
 	return value
 }
 
-// AWSSecretsManager loads secrets from AWS Secrets Manager
-// This is a placeholder implementation - requires AWS SDK
-type AWSSecretsManager struct {
-	logger *zap.Logger
-	region string
-	// client *secretsmanager.Client
-}
-
-// NewAWSSecretsManager creates a new AWS Secrets Manager client
-func NewAWSSecretsManager(region string, logger *zap.Logger) *AWSSecretsManager {
-	return &AWSSecretsManager{
-		logger: logger,
-		region: region,
-	}
-}
-
-// GetSecret retrieves a secret from AWS Secrets Manager
-func (m *AWSSecretsManager) GetSecret(ctx context.Context, secretName string) (string, error) {
-	// TODO: Implement AWS Secrets Manager integration
-	// This requires:
-	// 1. AWS SDK for Go v2
-	// 2. IAM permissions for secretsmanager:GetSecretValue
-	// 3. Proper AWS credentials configuration
-
-	/*
-		svc := secretsmanager.NewFromConfig(cfg)
-		result, err := svc.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
-			SecretId: aws.String(secretName),
-		})
-		if err != nil {
-			return "", fmt.Errorf("failed to get secret %s: %w", secretName, err)
-		}
-		return *result.SecretString, nil
-	*/
-
-	return "", fmt.Errorf("AWS Secrets Manager not implemented")
-}
-
-// GetSecretWithDefault retrieves a secret with a fallback default
-func (m *AWSSecretsManager) GetSecretWithDefault(ctx context.Context, key, defaultValue string) string {
-	value, err := m.GetSecret(ctx, key)
-	if err != nil {
-		m.logger.Warn("Failed to get secret from AWS, using default",
-			zap.String("key", key),
-			zap.Error(err))
-		return defaultValue
-	}
-	return value
-}
-
-// GCPSecretManager loads secrets from Google Cloud Secret Manager
-// This is a placeholder implementation - requires GCP SDK
-type GCPSecretManager struct {
-	logger    *zap.Logger
-	projectID string
-	// client *secretmanager.Client
-}
-
-// NewGCPSecretManager creates a new GCP Secret Manager client
-func NewGCPSecretManager(projectID string, logger *zap.Logger) *GCPSecretManager {
-	return &GCPSecretManager{
-		logger:    logger,
-		projectID: projectID,
-	}
-}
-
-// GetSecret retrieves a secret from GCP Secret Manager
-func (m *GCPSecretManager) GetSecret(ctx context.Context, secretName string) (string, error) {
-	// TODO: Implement GCP Secret Manager integration
-	// This requires:
-	// 1. Google Cloud SDK for Go
-	// 2. IAM permissions for secretmanager.versions.access
-	// 3. Proper GCP credentials configuration
-
-	/*
-		client, err := secretmanager.NewClient(ctx)
-		if err != nil {
-			return "", fmt.Errorf("failed to create client: %w", err)
-		}
-		defer client.Close()
-
-		accessRequest := &secretmanagerpb.AccessSecretVersionRequest{
-			Name: fmt.Sprintf("projects/%s/secrets/%s/versions/latest", m.projectID, secretName),
-		}
-
-		result, err := client.AccessSecretVersion(ctx, accessRequest)
-		if err != nil {
-			return "", fmt.Errorf("failed to access secret %s: %w", secretName, err)
-		}
-
-		return string(result.Payload.Data), nil
-	*/
-
-	return "", fmt.Errorf("GCP Secret Manager not implemented")
-}
-
-// GetSecretWithDefault retrieves a secret with a fallback default
-func (m *GCPSecretManager) GetSecretWithDefault(ctx context.Context, key, defaultValue string) string {
-	value, err := m.GetSecret(ctx, key)
-	if err != nil {
-		m.logger.Warn("Failed to get secret from GCP, using default",
-			zap.String("key", key),
-			zap.Error(err))
-		return defaultValue
-	}
-	return value
-}
-
 // MultiSourceSecretManager tries multiple secret sources in order
 type MultiSourceSecretManager struct {
 	sources []SecretManager
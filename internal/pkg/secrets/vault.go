@@ -0,0 +1,283 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	vaultCacheTTL      = time.Minute
+	vaultRetryInterval = 30 * time.Second
+)
+
+// VaultConfig configures the Vault secret manager. Secrets are the fields of
+// one KV v2 secret at Mount/Path. Authenticate with a token, or with an
+// AppRole whose token is renewed and re-issued as it expires.
+type VaultConfig struct {
+	Address   string
+	Namespace string // Enterprise namespace, optional
+	Token     string
+	RoleID    string
+	SecretID  string
+	Mount     string // KV v2 mount, "secret" when empty
+	Path      string
+}
+
+// VaultSecretManager loads secrets from a HashiCorp Vault KV v2 secret
+type VaultSecretManager struct {
+	cfg    VaultConfig
+	client *http.Client
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	token     string
+	ttl       time.Duration // Lease of the current token; 0 never expires
+	renewable bool
+	cached    map[string]string
+	cachedAt  time.Time
+}
+
+// NewVaultSecretManager creates a Vault secret manager and logs in
+func NewVaultSecretManager(ctx context.Context, cfg VaultConfig, logger *zap.Logger) (*VaultSecretManager, error) {
+	if cfg.Address == "" || cfg.Path == "" {
+		return nil, errors.New("vault address and secret path are required")
+	}
+	if cfg.Token == "" && (cfg.RoleID == "" || cfg.SecretID == "") {
+		return nil, errors.New("vault needs a token or an AppRole role ID and secret ID")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+
+	m := &VaultSecretManager{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		logger: logger,
+	}
+	if err := m.login(ctx); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetSecret retrieves a field of the configured KV secret
+func (m *VaultSecretManager) GetSecret(ctx context.Context, key string) (string, error) {
+	data, err := m.secretData(ctx)
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[key]
+	if !ok || value == "" {
+		return "", fmt.Errorf("secret %s not found in vault", key)
+	}
+	return value, nil
+}
+
+// GetSecretWithDefault retrieves a secret with a fallback default
+func (m *VaultSecretManager) GetSecretWithDefault(ctx context.Context, key, defaultValue string) string {
+	value, err := m.GetSecret(ctx, key)
+	if err != nil {
+		m.logger.Warn("Failed to get secret from Vault, using default",
+			zap.String("key", key),
+			zap.Error(err))
+		return defaultValue
+	}
+	return value
+}
+
+// RunRenewal keeps the Vault token valid until ctx is done. Tokens are
+// renewed halfway through their lease; AppRole logins are repeated when
+// renewal fails or the token reaches its maximum TTL.
+func (m *VaultSecretManager) RunRenewal(ctx context.Context) {
+	wait := m.renewalDelay()
+	for wait > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if err := m.refresh(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			m.logger.Error("Failed to renew Vault token", zap.Error(err))
+			wait = vaultRetryInterval
+			continue
+		}
+		wait = m.renewalDelay()
+	}
+}
+
+// renewalDelay returns how long until the token should be refreshed, or 0 if
+// it never needs to be
+func (m *VaultSecretManager) renewalDelay() time.Duration {
+	m.mu.Lock()
+	ttl, renewable := m.ttl, m.renewable
+	m.mu.Unlock()
+
+	if ttl == 0 {
+		return 0 // The token never expires
+	}
+	if !renewable && m.cfg.RoleID == "" {
+		m.logger.Warn("Vault token is not renewable and will expire", zap.Duration("ttl", ttl))
+		return 0
+	}
+	return ttl / 2
+}
+
+// refresh renews the token, falling back to a new AppRole login
+func (m *VaultSecretManager) refresh(ctx context.Context) error {
+	m.mu.Lock()
+	renewable := m.renewable
+	m.mu.Unlock()
+
+	if renewable {
+		var resp vaultAuthResponse
+		err := m.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", struct{}{}, &resp)
+		if err == nil {
+			m.setToken(resp.Auth)
+			return nil
+		}
+		if m.cfg.RoleID == "" {
+			return err
+		}
+		m.logger.Warn("Vault token renewal failed, logging in again", zap.Error(err))
+	}
+	return m.login(ctx)
+}
+
+// login obtains a token through AppRole, or looks up the lease of the
+// configured token
+func (m *VaultSecretManager) login(ctx context.Context) error {
+	if m.cfg.RoleID != "" {
+		var resp vaultAuthResponse
+		body := map[string]string{"role_id": m.cfg.RoleID, "secret_id": m.cfg.SecretID}
+		if err := m.do(ctx, http.MethodPost, "/v1/auth/approle/login", body, &resp); err != nil {
+			return fmt.Errorf("vault approle login failed: %w", err)
+		}
+		m.setToken(resp.Auth)
+		return nil
+	}
+
+	m.mu.Lock()
+	m.token = m.cfg.Token
+	m.mu.Unlock()
+
+	var resp struct {
+		Data struct {
+			TTL       int  `json:"ttl"`
+			Renewable bool `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := m.do(ctx, http.MethodGet, "/v1/auth/token/lookup-self", nil, &resp); err != nil {
+		return fmt.Errorf("vault token lookup failed: %w", err)
+	}
+
+	m.mu.Lock()
+	m.ttl = time.Duration(resp.Data.TTL) * time.Second
+	m.renewable = resp.Data.Renewable
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *VaultSecretManager) setToken(auth vaultAuth) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.token = auth.ClientToken
+	m.ttl = time.Duration(auth.LeaseDuration) * time.Second
+	m.renewable = auth.Renewable
+}
+
+// secretData reads the KV secret, cached briefly since callers read several
+// fields at startup
+func (m *VaultSecretManager) secretData(ctx context.Context) (map[string]string, error) {
+	m.mu.Lock()
+	if m.cached != nil && time.Since(m.cachedAt) < vaultCacheTTL {
+		data := m.cached
+		m.mu.Unlock()
+		return data, nil
+	}
+	m.mu.Unlock()
+
+	var resp struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	path := fmt.Sprintf("/v1/%s/data/%s", strings.Trim(m.cfg.Mount, "/"), strings.Trim(m.cfg.Path, "/"))
+	if err := m.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to read vault secret: %w", err)
+	}
+
+	m.mu.Lock()
+	m.cached, m.cachedAt = resp.Data.Data, time.Now()
+	m.mu.Unlock()
+	return resp.Data.Data, nil
+}
+
+// do sends a request to the Vault API and decodes the JSON response into out
+func (m *VaultSecretManager) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(m.cfg.Address, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	token := m.token
+	m.mu.Unlock()
+	// AppRole logins must not send a possibly expired token
+	if token != "" && !strings.HasPrefix(path, "/v1/auth/approle/") {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if m.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", m.cfg.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Vault error bodies never contain secret values
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&vaultErr)
+		return fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.Join(vaultErr.Errors, "; "))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type vaultAuth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+type vaultAuthResponse struct {
+	Auth vaultAuth `json:"auth"`
+}
@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeVault serves AppRole login, token renewal and one KV v2 secret
+func fakeVault(t *testing.T, logins *int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			http.Error(w, `{"errors":["invalid role or secret ID"]}`, http.StatusBadRequest)
+			return
+		}
+		*logins++
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"s.token","lease_duration":3600,"renewable":true}}`))
+	})
+	mux.HandleFunc("POST /v1/auth/token/renew-self", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errors":["token reached max TTL"]}`, http.StatusForbidden)
+	})
+	mux.HandleFunc("GET /v1/secret/data/anonymous-support", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"JWT_SECRET":"from-vault"},"metadata":{"version":3}}}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVaultSecretManager_AppRole(t *testing.T) {
+	logins := 0
+	server := fakeVault(t, &logins)
+	ctx := context.Background()
+
+	m, err := NewVaultSecretManager(ctx, VaultConfig{
+		Address:  server.URL,
+		RoleID:   "role",
+		SecretID: "secret",
+		Path:     "anonymous-support",
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := m.GetSecret(ctx, "JWT_SECRET"); err != nil || got != "from-vault" {
+		t.Fatalf("GetSecret = %q, %v", got, err)
+	}
+	if _, err := m.GetSecret(ctx, "ENCRYPTION_KEY"); err == nil {
+		t.Error("expected an error for a missing field")
+	}
+	if got := m.renewalDelay(); got.Seconds() != 1800 {
+		t.Errorf("renewalDelay = %v, want half the lease", got)
+	}
+
+	// A token past its max TTL can't be renewed, so the manager logs in again
+	if err := m.refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if logins != 2 {
+		t.Errorf("logins = %d, want 2", logins)
+	}
+}
+
+func TestNewVaultSecretManager_RequiresCredentials(t *testing.T) {
+	_, err := NewVaultSecretManager(context.Background(), VaultConfig{Address: "http://vault:8200", Path: "app"}, zap.NewNop())
+	if err == nil {
+		t.Error("expected an error without a token or AppRole")
+	}
+}
@@ -0,0 +1,137 @@
+package servertls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Config selects where the server's certificate comes from: a certificate
+// and key on disk, or certificates issued by Let's Encrypt for Domains
+type Config struct {
+	CertFile string
+	KeyFile  string
+
+	AutocertDomains  []string // Hosts Let's Encrypt certificates are requested for
+	AutocertCacheDir string   // Issued certificates survive restarts here
+	AutocertEmail    string   // Contact address for expiry notices, optional
+
+	// ClientCAFile is a PEM bundle of the CAs that issue internal clients'
+	// certificates. Client certificates are requested and verified when set,
+	// but stay optional so ordinary clients can still connect.
+	ClientCAFile string
+}
+
+// Enabled reports whether the server should terminate TLS itself
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || len(c.AutocertDomains) > 0
+}
+
+// Server holds the TLS configuration of the API listener
+type Server struct {
+	TLSConfig *tls.Config
+	manager   *autocert.Manager
+}
+
+// New loads the certificate or sets up Let's Encrypt and returns the TLS
+// configuration for the API listener
+func New(cfg Config) (*Server, error) {
+	if !cfg.Enabled() {
+		return nil, errors.New("TLS is not configured")
+	}
+
+	tlsConfig := defaultConfig()
+	if cfg.ClientCAFile != "" {
+		pool, err := loadCertPool(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	if len(cfg.AutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+			Email:      cfg.AutocertEmail,
+		}
+		tlsConfig.GetCertificate = manager.GetCertificate
+		// tls-alpn-01 challenges are answered on the TLS listener itself
+		tlsConfig.NextProtos = append(tlsConfig.NextProtos, "acme-tls/1")
+		return &Server{TLSConfig: tlsConfig, manager: manager}, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return &Server{TLSConfig: tlsConfig}, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", file)
+	}
+	return pool, nil
+}
+
+// RedirectHandler redirects plain HTTP requests to HTTPS on httpsPort. With
+// Let's Encrypt it also answers http-01 challenges.
+func (s *Server) RedirectHandler(httpsPort int) http.Handler {
+	redirect := redirectHandler(httpsPort)
+	if s.manager != nil {
+		return s.manager.HTTPHandler(redirect)
+	}
+	return redirect
+}
+
+func redirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
+
+// defaultConfig allows TLS 1.2 and 1.3 only, with forward-secret AEAD
+// ciphers for TLS 1.2 (TLS 1.3 suites are not configurable)
+func defaultConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{
+			tls.X25519,
+			tls.CurveP256,
+		},
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+}
@@ -0,0 +1,112 @@
+package servertls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		httpsPort int
+		want      string
+	}{
+		{"default port", "api.example.com", 443, "https://api.example.com/auth.v1.AuthService/Login?x=1"},
+		{"drops http port", "api.example.com:80", 443, "https://api.example.com/auth.v1.AuthService/Login?x=1"},
+		{"custom https port", "api.example.com:8080", 8443, "https://api.example.com:8443/auth.v1.AuthService/Login?x=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/auth.v1.AuthService/Login?x=1", nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+
+			(&Server{}).RedirectHandler(tt.httpsPort).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusPermanentRedirect {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusPermanentRedirect)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_RequiresCertificateSource(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("expected an error without a certificate or autocert domains")
+	}
+	if _, err := New(Config{CertFile: "missing.pem", KeyFile: "missing-key.pem"}); err == nil {
+		t.Error("expected an error for a missing certificate")
+	}
+}
+
+func TestNew_ClientCA(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{AutocertDomains: []string{"api.example.com"}, AutocertCacheDir: dir, ClientCAFile: empty}
+	if _, err := New(cfg); err == nil {
+		t.Error("expected an error for a client CA file without certificates")
+	}
+
+	cfg.ClientCAFile = writeTestCA(t, dir)
+	server, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if server.TLSConfig.ClientAuth != tls.VerifyClientCertIfGiven || server.TLSConfig.ClientCAs == nil {
+		t.Error("client certificates should be verified when given")
+	}
+
+	cfg.ClientCAFile = filepath.Join(dir, "missing.pem")
+	if _, err := New(cfg); err == nil {
+		t.Error("expected an error for a missing client CA file")
+	}
+}
+
+// writeTestCA writes a self-signed CA certificate and returns its path
+func writeTestCA(t *testing.T, dir string) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "internal clients CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
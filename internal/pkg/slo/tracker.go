@@ -0,0 +1,180 @@
+// Package slo tracks availability and latency objectives per RPC service over
+// a rolling window and exposes compliance as Prometheus gauges
+package slo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// windowBuckets is how many slices the rolling window is divided into; the
+// window advances one slice at a time
+const windowBuckets = 60
+
+// Objectives are the targets a service is measured against
+type Objectives struct {
+	Availability float64       // Fraction of requests that must not fail server-side, e.g. 0.999
+	Latency      time.Duration // Unary requests slower than this miss the latency objective
+	Window       time.Duration // Rolling window compliance is computed over
+}
+
+type bucket struct {
+	slot   int64 // Window slot the counts belong to
+	total  uint64
+	failed uint64
+	timed  uint64 // Unary requests, the denominator of the latency ratio
+	fast   uint64 // Unary requests at or under the latency objective
+}
+
+// Tracker counts requests per service. It is a prometheus.Collector whose
+// gauges are computed at scrape time, so /metrics always shows the current
+// window.
+type Tracker struct {
+	objectives Objectives
+	slotWidth  time.Duration
+	now        func() time.Time
+
+	mu       sync.Mutex
+	services map[string]*[windowBuckets]bucket
+
+	availability *prometheus.Desc
+	latency      *prometheus.Desc
+	budget       *prometheus.Desc
+	requests     *prometheus.Desc
+}
+
+// NewTracker creates a tracker for objectives
+func NewTracker(objectives Objectives) *Tracker {
+	slotWidth := objectives.Window / windowBuckets
+	if slotWidth <= 0 {
+		slotWidth = time.Second
+	}
+	return &Tracker{
+		objectives: objectives,
+		slotWidth:  slotWidth,
+		now:        time.Now,
+		services:   make(map[string]*[windowBuckets]bucket),
+		availability: prometheus.NewDesc("slo_availability_ratio",
+			"Fraction of RPCs in the SLO window that did not fail server-side, by service", []string{"service"}, nil),
+		latency: prometheus.NewDesc("slo_latency_ratio",
+			"Fraction of unary RPCs in the SLO window that met the latency objective, by service", []string{"service"}, nil),
+		budget: prometheus.NewDesc("slo_error_budget_remaining_ratio",
+			"Share of the availability error budget left in the SLO window, by service; negative when overspent", []string{"service"}, nil),
+		requests: prometheus.NewDesc("slo_window_requests",
+			"RPCs counted in the SLO window, by service", []string{"service"}, nil),
+	}
+}
+
+// Observe counts a unary RPC
+func (t *Tracker) Observe(service string, failed bool, latency time.Duration) {
+	t.record(service, func(b *bucket) {
+		b.total++
+		b.timed++
+		if failed {
+			b.failed++
+		}
+		if latency <= t.objectives.Latency {
+			b.fast++
+		}
+	})
+}
+
+// ObserveStream counts a streaming RPC toward availability only, since a
+// stream's duration is set by the client
+func (t *Tracker) ObserveStream(service string, failed bool) {
+	t.record(service, func(b *bucket) {
+		b.total++
+		if failed {
+			b.failed++
+		}
+	})
+}
+
+func (t *Tracker) record(service string, update func(*bucket)) {
+	slot := t.slot()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	buckets, ok := t.services[service]
+	if !ok {
+		buckets = new([windowBuckets]bucket)
+		t.services[service] = buckets
+	}
+	b := &buckets[slot%windowBuckets]
+	if b.slot != slot {
+		*b = bucket{slot: slot}
+	}
+	update(b)
+}
+
+func (t *Tracker) slot() int64 {
+	return t.now().UnixNano() / int64(t.slotWidth)
+}
+
+// Compliance is a service's standing against its objectives over the window
+type Compliance struct {
+	Requests        uint64
+	Availability    float64
+	Latency         float64
+	BudgetRemaining float64
+}
+
+// Compliance returns the standing of every service seen in the window
+func (t *Tracker) Compliance() map[string]Compliance {
+	current := t.slot()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make(map[string]Compliance, len(t.services))
+	for service, buckets := range t.services {
+		var sum bucket
+		for _, b := range buckets {
+			if b.slot <= current-windowBuckets {
+				continue
+			}
+			sum.total += b.total
+			sum.failed += b.failed
+			sum.timed += b.timed
+			sum.fast += b.fast
+		}
+		if sum.total == 0 {
+			continue
+		}
+		result[service] = t.compliance(sum)
+	}
+	return result
+}
+
+func (t *Tracker) compliance(sum bucket) Compliance {
+	failedRatio := float64(sum.failed) / float64(sum.total)
+	c := Compliance{Requests: sum.total, Availability: 1 - failedRatio, Latency: 1, BudgetRemaining: 1}
+	if sum.timed > 0 {
+		c.Latency = float64(sum.fast) / float64(sum.timed)
+	}
+	if budget := 1 - t.objectives.Availability; budget > 0 {
+		c.BudgetRemaining = 1 - failedRatio/budget
+	}
+	return c
+}
+
+// Describe implements prometheus.Collector
+func (t *Tracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.availability
+	ch <- t.latency
+	ch <- t.budget
+	ch <- t.requests
+}
+
+// Collect implements prometheus.Collector
+func (t *Tracker) Collect(ch chan<- prometheus.Metric) {
+	for service, c := range t.Compliance() {
+		ch <- prometheus.MustNewConstMetric(t.availability, prometheus.GaugeValue, c.Availability, service)
+		ch <- prometheus.MustNewConstMetric(t.latency, prometheus.GaugeValue, c.Latency, service)
+		ch <- prometheus.MustNewConstMetric(t.budget, prometheus.GaugeValue, c.BudgetRemaining, service)
+		ch <- prometheus.MustNewConstMetric(t.requests, prometheus.GaugeValue, float64(c.Requests), service)
+	}
+}
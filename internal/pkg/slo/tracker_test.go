@@ -0,0 +1,61 @@
+package slo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTracker_Compliance(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tracker := NewTracker(Objectives{Availability: 0.99, Latency: 100 * time.Millisecond, Window: time.Hour})
+	tracker.now = func() time.Time { return now }
+
+	for i := 0; i < 98; i++ {
+		tracker.Observe("post.v1.PostService", false, 20*time.Millisecond)
+	}
+	tracker.Observe("post.v1.PostService", true, 20*time.Millisecond)
+	tracker.Observe("post.v1.PostService", false, time.Second)
+	tracker.ObserveStream("post.v1.PostService", false)
+
+	got := tracker.Compliance()["post.v1.PostService"]
+	if got.Requests != 101 {
+		t.Errorf("Requests = %d, want 101", got.Requests)
+	}
+	if want := 100.0 / 101; !almostEqual(got.Availability, want) {
+		t.Errorf("Availability = %v, want %v", got.Availability, want)
+	}
+	// The stream is not part of the latency ratio
+	if want := 99.0 / 100; !almostEqual(got.Latency, want) {
+		t.Errorf("Latency = %v, want %v", got.Latency, want)
+	}
+	// One failure in 101 spends 1/1.01 of a 1% budget
+	if want := 1 - (1.0/101)/0.01; !almostEqual(got.BudgetRemaining, want) {
+		t.Errorf("BudgetRemaining = %v, want %v", got.BudgetRemaining, want)
+	}
+}
+
+func TestTracker_WindowExpires(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tracker := NewTracker(Objectives{Availability: 0.999, Latency: time.Second, Window: time.Hour})
+	tracker.now = func() time.Time { return now }
+
+	tracker.Observe("auth.v1.AuthService", true, time.Millisecond)
+
+	now = now.Add(30 * time.Minute)
+	tracker.Observe("auth.v1.AuthService", false, time.Millisecond)
+	if got := tracker.Compliance()["auth.v1.AuthService"].Requests; got != 2 {
+		t.Errorf("Requests after 30m = %d, want 2", got)
+	}
+
+	// The failure has left the window; only the later success remains
+	now = now.Add(45 * time.Minute)
+	got := tracker.Compliance()["auth.v1.AuthService"]
+	if got.Requests != 1 || got.Availability != 1 {
+		t.Errorf("after 75m = %+v, want 1 request at full availability", got)
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
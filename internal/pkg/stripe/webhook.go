@@ -0,0 +1,121 @@
+// Package stripe verifies and decodes Stripe webhook events. Only the fields
+// the entitlements subsystem needs are decoded.
+package stripe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the header Stripe signs webhook requests with
+const SignatureHeader = "Stripe-Signature"
+
+// DefaultTolerance is how old a signed request may be before it's rejected
+// as a possible replay
+const DefaultTolerance = 5 * time.Minute
+
+// Subscription event types
+const (
+	EventSubscriptionCreated = "customer.subscription.created"
+	EventSubscriptionUpdated = "customer.subscription.updated"
+	EventSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+// ErrInvalidSignature is returned for requests not signed with the endpoint's secret
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Event is a webhook event
+type Event struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// Subscription is the object of customer.subscription.* events
+type Subscription struct {
+	ID               string            `json:"id"`
+	Customer         string            `json:"customer"`
+	Status           string            `json:"status"` // active, trialing, past_due, canceled, unpaid, incomplete, ...
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"`
+	Items            struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// PriceIDs returns the IDs of the prices the subscription is for
+func (s *Subscription) PriceIDs() []string {
+	ids := make([]string, len(s.Items.Data))
+	for i, item := range s.Items.Data {
+		ids[i] = item.Price.ID
+	}
+	return ids
+}
+
+// ConstructEvent verifies payload against its Stripe-Signature header and
+// decodes it. Requests signed more than tolerance before now are rejected.
+func ConstructEvent(payload []byte, header, secret string, tolerance time.Duration, now time.Time) (*Event, error) {
+	if err := VerifySignature(payload, header, secret, tolerance, now); err != nil {
+		return nil, err
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	return &event, nil
+}
+
+// VerifySignature checks a Stripe-Signature header of the form
+// "t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<payload>">". Any of several v1
+// signatures may match, as Stripe sends one per active secret while rolling.
+func VerifySignature(payload []byte, header, secret string, tolerance time.Duration, now time.Time) error {
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if signature, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,66 @@
+package stripe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func sign(payload, secret string, at time.Time) string {
+	timestamp := fmt.Sprint(at.Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestConstructEvent(t *testing.T) {
+	now := time.Unix(1_790_000_000, 0)
+	payload := `{"id":"evt_1","type":"customer.subscription.updated","data":{"object":{"id":"sub_1","status":"active","metadata":{"user_id":"u1"},"items":{"data":[{"price":{"id":"price_1"}}]}}}}`
+
+	event, err := ConstructEvent([]byte(payload), sign(payload, "whsec", now), "whsec", DefaultTolerance, now)
+	if err != nil {
+		t.Fatalf("ConstructEvent() error = %v", err)
+	}
+	if event.Type != EventSubscriptionUpdated {
+		t.Errorf("Type = %q, want %q", event.Type, EventSubscriptionUpdated)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1_790_000_000, 0)
+	payload := `{"id":"evt_1"}`
+
+	tests := []struct {
+		name   string
+		header string
+		valid  bool
+	}{
+		{"valid", sign(payload, "whsec", now), true},
+		{"rolled secret", sign(payload, "old", now) + ",v1=" + sign(payload, "whsec", now)[len("t=1790000000,v1="):], true},
+		{"wrong secret", sign(payload, "other", now), false},
+		{"too old", sign(payload, "whsec", now.Add(-DefaultTolerance-time.Second)), false},
+		{"from the future", sign(payload, "whsec", now.Add(DefaultTolerance+time.Second)), false},
+		{"no signature", "t=1790000000", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature([]byte(payload), tt.header, "whsec", DefaultTolerance, now)
+			if tt.valid && err != nil {
+				t.Errorf("VerifySignature() error = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("VerifySignature() error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	// A tampered payload fails
+	if err := VerifySignature([]byte(`{"id":"evt_2"}`), sign(payload, "whsec", now), "whsec", DefaultTolerance, now); err == nil {
+		t.Error("VerifySignature() accepted a tampered payload")
+	}
+}
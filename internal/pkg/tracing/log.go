@@ -0,0 +1,31 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// LogFields returns trace_id and span_id fields for the span in ctx, or none
+// when ctx is not traced
+func LogFields(ctx context.Context) []zap.Field {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", spanContext.TraceID().String()),
+		zap.String("span_id", spanContext.SpanID().String()),
+	}
+}
+
+// Logger returns logger with the trace and span IDs of ctx attached, so its
+// lines can be matched to the trace
+func Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	fields := LogFields(ctx)
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.With(fields...)
+}
@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const mongoTracerName = "go.mongodb.org/mongo-driver"
+
+// NewMongoCommandMonitor returns a command monitor recording a client span
+// for every MongoDB command. Command documents are not recorded, since they
+// hold user content.
+func NewMongoCommandMonitor() *event.CommandMonitor {
+	type commandKey struct {
+		connectionID string
+		requestID    int64
+	}
+	var spans sync.Map // commandKey -> trace.Span
+
+	end := func(connectionID string, requestID int64, err error) {
+		if span, ok := spans.LoadAndDelete(commandKey{connectionID, requestID}); ok {
+			EndSpan(span.(trace.Span), err)
+		}
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			collection, _ := e.Command.Lookup(e.CommandName).StringValueOK()
+			_, span := StartSpan(ctx, mongoTracerName, "mongodb "+e.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					AttrDBSystem.String("mongodb"),
+					AttrDBOperation.String(e.CommandName),
+					AttrDBTable.String(collection),
+				))
+			spans.Store(commandKey{e.ConnectionID, e.RequestID}, span)
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			end(e.ConnectionID, e.RequestID, nil)
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			end(e.ConnectionID, e.RequestID, errors.New(e.Failure))
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redisTracerName = "github.com/redis/go-redis"
+
+// RedisHook records a client span for every Redis command and pipeline.
+// Command arguments are not recorded.
+type RedisHook struct{}
+
+// NewRedisHook creates a hook to add with redis.Client.AddHook
+func NewRedisHook() RedisHook {
+	return RedisHook{}
+}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := StartSpan(ctx, redisTracerName, "redis "+cmd.Name(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				AttrDBSystem.String("redis"),
+				AttrDBOperation.String(cmd.Name()),
+			))
+		err := next(ctx, cmd)
+		endRedisSpan(span, err)
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := StartSpan(ctx, redisTracerName, "redis pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				AttrDBSystem.String("redis"),
+				AttrDBOperation.String("pipeline"),
+				attribute.Int("db.redis.commands", len(cmds)),
+			))
+		err := next(ctx, cmds)
+		endRedisSpan(span, err)
+		return err
+	}
+}
+
+// endRedisSpan ends span; redis.Nil is a cache miss, not a failure
+func endRedisSpan(span trace.Span, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	EndSpan(span, err)
+}
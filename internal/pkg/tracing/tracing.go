@@ -140,6 +140,15 @@ func RecordError(ctx context.Context, err error, opts ...trace.EventOption) {
 	}
 }
 
+// EndSpan records err on span, if any, and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 // SetSpanStatus sets the status of the current span
 func SetSpanStatus(ctx context.Context, code codes.Code, description string) {
 	span := trace.SpanFromContext(ctx)
@@ -157,8 +166,13 @@ var (
 	AttrHTTPMethod   = attribute.Key("http.method")
 	AttrHTTPRoute    = attribute.Key("http.route")
 	AttrHTTPStatus   = attribute.Key("http.status_code")
+	AttrDBSystem     = attribute.Key("db.system")
 	AttrDBOperation  = attribute.Key("db.operation")
 	AttrDBTable      = attribute.Key("db.table")
+	AttrDBStatement  = attribute.Key("db.statement")
+	AttrRPCSystem    = attribute.Key("rpc.system")
+	AttrRPCService   = attribute.Key("rpc.service")
+	AttrRPCMethod    = attribute.Key("rpc.method")
 	AttrCacheHit     = attribute.Key("cache.hit")
 	AttrErrorCode    = attribute.Key("error.code")
 	AttrErrorMessage = attribute.Key("error.message")
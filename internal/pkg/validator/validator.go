@@ -1,9 +1,12 @@
+// Package validator checks user input. Its errors are *i18n.Error, so they
+// can be returned in the client's language.
 package validator
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/i18n"
 )
 
 var (
@@ -13,24 +16,24 @@ var (
 
 func ValidateUsername(username string) error {
 	if len(username) < 3 || len(username) > 50 {
-		return fmt.Errorf("username must be between 3 and 50 characters")
+		return i18n.Errorf("validation.username_length", 3, 50)
 	}
 	if !usernameRegex.MatchString(username) {
-		return fmt.Errorf("username can only contain letters, numbers, underscores, and hyphens")
+		return i18n.Errorf("validation.username_chars")
 	}
 	return nil
 }
 
 func ValidateEmail(email string) error {
 	if !emailRegex.MatchString(email) {
-		return fmt.Errorf("invalid email format")
+		return i18n.Errorf("validation.email")
 	}
 	return nil
 }
 
 func ValidatePassword(password string) error {
 	if len(password) < 8 {
-		return fmt.Errorf("password must be at least 8 characters long")
+		return i18n.Errorf("validation.password_length", 8)
 	}
 	return nil
 }
@@ -38,10 +41,10 @@ func ValidatePassword(password string) error {
 func ValidatePostContent(content string) error {
 	content = strings.TrimSpace(content)
 	if len(content) == 0 {
-		return fmt.Errorf("post content cannot be empty")
+		return i18n.Errorf("validation.post_empty")
 	}
 	if len(content) > 5000 {
-		return fmt.Errorf("post content cannot exceed 5000 characters")
+		return i18n.Errorf("validation.post_too_long", 5000)
 	}
 	return nil
 }
@@ -49,10 +52,10 @@ func ValidatePostContent(content string) error {
 func ValidateResponseContent(content string) error {
 	content = strings.TrimSpace(content)
 	if len(content) == 0 {
-		return fmt.Errorf("response content cannot be empty")
+		return i18n.Errorf("validation.response_empty")
 	}
 	if len(content) > 2000 {
-		return fmt.Errorf("response content cannot exceed 2000 characters")
+		return i18n.Errorf("validation.response_too_long", 2000)
 	}
 	return nil
 }
@@ -0,0 +1,245 @@
+// Package workqueue runs background jobs on a fixed pool of workers, highest
+// priority first, so SOS work never waits behind bulk jobs such as digests
+package workqueue
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	reporting "github.com/yourorg/anonymous-support/internal/pkg/errors"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// ErrQueueClosed is returned when submitting to a closed queue
+var ErrQueueClosed = errors.New("work queue is closed")
+
+// Priority orders queued jobs; higher runs first
+type Priority int
+
+const (
+	PriorityLow      Priority = iota // Digests, analytics refreshes
+	PriorityNormal                   // Routine reminders
+	PriorityHigh                     // Time-sensitive nudges
+	PriorityCritical                 // SOS routing and responder notification
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+type job struct {
+	name     string
+	priority Priority
+	run      func(ctx context.Context) error
+	seq      uint64
+	queuedAt time.Time
+}
+
+// jobHeap orders jobs by priority, then submission order
+type jobHeap []*job
+
+func (h jobHeap) Len() int { return len(h) }
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+func (h jobHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *jobHeap) Push(x interface{}) { *h = append(*h, x.(*job)) }
+func (h *jobHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return j
+}
+
+// Queue holds jobs until a worker is free. A job is not queued again while
+// one with the same name is still waiting, so periodic jobs don't pile up
+// behind urgent work.
+type Queue struct {
+	mu       sync.Mutex
+	jobs     jobHeap
+	queued   map[string]bool
+	seq      uint64
+	closed   bool
+	ready    chan struct{}
+	workers  int
+	wg       sync.WaitGroup
+	reporter reporting.Reporter
+	logger   *zap.Logger
+}
+
+// NewQueue creates a queue served by workers goroutines once started. Failed
+// and panicking jobs are sent to reporter, which may be nil.
+func NewQueue(workers int, reporter reporting.Reporter, logger *zap.Logger) *Queue {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &Queue{
+		queued:   make(map[string]bool),
+		ready:    make(chan struct{}, 1),
+		workers:  workers,
+		reporter: reporter,
+		logger:   logger,
+	}
+}
+
+// Submit queues run under name at priority
+func (q *Queue) Submit(name string, priority Priority, run func(ctx context.Context) error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if q.queued[name] {
+		return nil
+	}
+
+	q.seq++
+	heap.Push(&q.jobs, &job{name: name, priority: priority, run: run, seq: q.seq, queuedAt: time.Now()})
+	q.queued[name] = true
+	metrics.WorkQueueDepth.WithLabelValues(priority.String()).Inc()
+	q.signal()
+	return nil
+}
+
+// Len returns the number of jobs waiting for a worker
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.jobs.Len()
+}
+
+// Start starts the workers. Jobs get ctx's values but are not cancelled with
+// it; Close waits for them instead.
+func (q *Queue) Start(ctx context.Context) {
+	jobCtx := context.WithoutCancel(ctx)
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for {
+				j, ok := q.next()
+				if ok {
+					q.run(jobCtx, j)
+					continue
+				}
+				if q.isClosed() {
+					return
+				}
+				<-q.ready
+			}
+		}()
+	}
+}
+
+// Close stops accepting jobs, drops queued low-priority jobs, which run
+// again on their next schedule, and waits for the rest to finish or ctx to end
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		kept := q.jobs[:0]
+		for _, j := range q.jobs {
+			if j.priority == PriorityLow {
+				delete(q.queued, j.name)
+				metrics.WorkQueueDepth.WithLabelValues(j.priority.String()).Dec()
+				continue
+			}
+			kept = append(kept, j)
+		}
+		q.jobs = kept
+		heap.Init(&q.jobs)
+		close(q.ready)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.logger.Warn("Work queue closed with jobs remaining", zap.Int("queued", q.Len()))
+		return ctx.Err()
+	}
+}
+
+// next pops the highest priority job, waking another worker if more remain
+func (q *Queue) next() (*job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.jobs.Len() == 0 {
+		return nil, false
+	}
+
+	j := heap.Pop(&q.jobs).(*job)
+	delete(q.queued, j.name)
+	metrics.WorkQueueDepth.WithLabelValues(j.priority.String()).Dec()
+	if q.jobs.Len() > 0 {
+		q.signal()
+	}
+	return j, true
+}
+
+// signal wakes an idle worker; callers hold mu
+func (q *Queue) signal() {
+	if q.closed {
+		return // Closed ready wakes every worker
+	}
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) isClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+func (q *Queue) run(ctx context.Context, j *job) {
+	metrics.WorkQueueWaitSeconds.WithLabelValues(j.priority.String()).Observe(time.Since(j.queuedAt).Seconds())
+
+	defer func() {
+		if r := recover(); r != nil {
+			q.logger.Error("Job panicked", zap.String("job", j.name), zap.Any("panic", r))
+			q.report(ctx, j, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	if err := j.run(ctx); err != nil {
+		q.logger.Warn("Job failed", zap.String("job", j.name), zap.String("priority", j.priority.String()), zap.Error(err))
+		q.report(ctx, j, err)
+	}
+}
+
+func (q *Queue) report(ctx context.Context, j *job, err error) {
+	if q.reporter == nil {
+		return
+	}
+	q.reporter.ReportError(ctx, err, map[string]string{"job": j.name, "priority": j.priority.String()})
+}
@@ -0,0 +1,131 @@
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// recorder collects the names of jobs in the order they ran
+type recorder struct {
+	mu  sync.Mutex
+	ran []string
+}
+
+func (r *recorder) job(name string) func(context.Context) error {
+	return func(context.Context) error {
+		r.mu.Lock()
+		r.ran = append(r.ran, name)
+		r.mu.Unlock()
+		return nil
+	}
+}
+
+// blockWorker occupies the queue's single worker until the returned func is called
+func blockWorker(t *testing.T, q *Queue) func() {
+	t.Helper()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	if err := q.Submit("blocker", PriorityLow, func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("blocker never started")
+	}
+	return func() { close(release) }
+}
+
+func TestQueue_RunsHighestPriorityFirst(t *testing.T) {
+	q := NewQueue(1, nil, zap.NewNop())
+	q.Start(context.Background())
+	release := blockWorker(t, q)
+
+	rec := &recorder{}
+	_ = q.Submit("digest", PriorityLow, rec.job("digest"))
+	_ = q.Submit("reminder", PriorityNormal, rec.job("reminder"))
+	_ = q.Submit("sos-1", PriorityCritical, rec.job("sos-1"))
+	_ = q.Submit("nudge", PriorityHigh, rec.job("nudge"))
+	_ = q.Submit("sos-2", PriorityCritical, rec.job("sos-2"))
+
+	release()
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// The low-priority digest was still queued at Close, so it was dropped
+	want := []string{"sos-1", "sos-2", "nudge", "reminder"}
+	if !reflect.DeepEqual(rec.ran, want) {
+		t.Errorf("ran %v, want %v", rec.ran, want)
+	}
+}
+
+func TestQueue_SkipsDuplicateQueuedJob(t *testing.T) {
+	q := NewQueue(1, nil, zap.NewNop())
+	q.Start(context.Background())
+	release := blockWorker(t, q)
+
+	rec := &recorder{}
+	_ = q.Submit("reminders", PriorityNormal, rec.job("reminders"))
+	_ = q.Submit("reminders", PriorityNormal, rec.job("reminders"))
+	if got := q.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1", got)
+	}
+
+	release()
+	_ = q.Close(context.Background())
+	if want := []string{"reminders"}; !reflect.DeepEqual(rec.ran, want) {
+		t.Errorf("ran %v, want %v", rec.ran, want)
+	}
+}
+
+func TestQueue_SubmitAfterClose(t *testing.T) {
+	q := NewQueue(2, nil, zap.NewNop())
+	q.Start(context.Background())
+	_ = q.Close(context.Background())
+
+	if err := q.Submit("late", PriorityCritical, func(context.Context) error { return nil }); err != ErrQueueClosed {
+		t.Errorf("Submit() error = %v, want ErrQueueClosed", err)
+	}
+}
+
+// errorRecorder collects the errors reported for failed jobs
+type errorRecorder struct {
+	mu   sync.Mutex
+	tags []map[string]string
+}
+
+func (r *errorRecorder) ReportError(_ context.Context, _ error, tags map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tags = append(r.tags, tags)
+}
+
+func TestQueue_ReportsFailedJobs(t *testing.T) {
+	reporter := &errorRecorder{}
+	q := NewQueue(1, reporter, zap.NewNop())
+	_ = q.Submit("digest", PriorityNormal, func(context.Context) error { return errors.New("smtp down") })
+	_ = q.Submit("nudges", PriorityHigh, func(context.Context) error { panic("nil user") })
+	_ = q.Submit("metrics", PriorityNormal, func(context.Context) error { return nil })
+
+	q.Start(context.Background())
+	_ = q.Close(context.Background())
+
+	var jobs []string
+	for _, tags := range reporter.tags {
+		jobs = append(jobs, tags["job"])
+	}
+	if want := []string{"nudges", "digest"}; !reflect.DeepEqual(jobs, want) {
+		t.Errorf("reported %v, want %v", jobs, want)
+	}
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/retry"
+	"go.uber.org/zap"
+)
+
+// NewBreaker returns the circuit breaker guarding every call to a backing
+// store. Five consecutive failures open it, rejecting calls for 30 seconds
+// before a trial call. isFailure tells the store failing apart from errors it
+// answered with, such as not found or a constraint violation; calls the
+// caller cancelled never count.
+func NewBreaker(name string, isFailure func(error) bool, logger *zap.Logger) *retry.CircuitBreaker {
+	return retry.NewCircuitBreaker(retry.BreakerConfig{
+		Name:         name,
+		MaxFailures:  5,
+		ResetTimeout: 30 * time.Second,
+		IsFailure: func(err error) bool {
+			return !errors.Is(err, context.Canceled) && isFailure(err)
+		},
+	}, logger)
+}
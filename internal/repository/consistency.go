@@ -0,0 +1,18 @@
+package repository
+
+import "context"
+
+type primaryReadsKey struct{}
+
+// WithPrimaryReads marks ctx so repositories read from the primary database
+// instead of a possibly lagging replica. Flows that must see their own writes,
+// such as registering and then signing in, use it.
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// PrimaryReadsRequired reports whether ctx was marked by WithPrimaryReads
+func PrimaryReadsRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(primaryReadsKey{}).(bool)
+	return required
+}
@@ -13,23 +13,56 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	// GetByIDs returns the users with the given IDs in one query; unknown and banned users are omitted
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error)
 	GetByUsername(ctx context.Context, username string) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	UpdateLastActive(ctx context.Context, userID uuid.UUID) error
 	UpdateStrengthPoints(ctx context.Context, userID uuid.UUID, points int) error
-	UpdateProfile(ctx context.Context, userID uuid.UUID, username *string, avatarID *int) error
+	UpdateProfile(ctx context.Context, userID uuid.UUID, username *string, avatarID *int, shareMilestones *bool) error
 	UsernameExists(ctx context.Context, username string) (bool, error)
+	ListRegistrationsByWeek(ctx context.Context, since time.Time) (map[time.Time][]string, error)
+	CountMembers(ctx context.Context) (int, error)
+	// ListWithEmail returns up to limit users with a stored email and an ID after afterID, by ID
+	ListWithEmail(ctx context.Context, afterID uuid.UUID, limit int) ([]*domain.User, error)
+	// ReplaceEmail sets a user's stored email if it is still current, reporting whether it was
+	ReplaceEmail(ctx context.Context, userID uuid.UUID, current, replacement string) (bool, error)
+	// Search returns a page of users matching the filter, including banned users, and the total matched
+	Search(ctx context.Context, filter domain.UserSearch) ([]*domain.User, int, error)
+	// GetAnyByID returns the user even if banned
+	GetAnyByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	SetBanned(ctx context.Context, userID uuid.UUID, banned bool) error
+	SetRole(ctx context.Context, userID uuid.UUID, role domain.Role) error
+	CountUsers(ctx context.Context) (*domain.UserCounts, error)
 }
 
 // PostRepository defines the interface for post data persistence
 type PostRepository interface {
 	Create(ctx context.Context, post *domain.Post) error
 	GetByID(ctx context.Context, id string) (*domain.Post, error)
+	// GetByIDs returns the posts with the given IDs in one query; invalid and unknown IDs are skipped
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Post, error)
 	GetFeed(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) ([]*domain.Post, error)
+	// GetFeedPage is GetFeed with the total count and per-category and per-type counts
+	GetFeedPage(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) (*domain.FeedPage, error)
+	// GetFeedAfter returns up to limit feed posts older than the post with afterID created at afterCreatedAt, newest first; an empty afterID starts at the newest
+	GetFeedAfter(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, afterCreatedAt time.Time, afterID string, limit int) ([]*domain.Post, error)
 	Delete(ctx context.Context, id string) error
 	UpdateUrgency(ctx context.Context, id string, urgencyLevel int32) error
-	IncrementResponseCount(ctx context.Context, id string) error
-	IncrementSupportCount(ctx context.Context, id string) error
+	AddResponseCounts(ctx context.Context, increments map[string]int64) error
+	AddSupportCounts(ctx context.Context, increments map[string]int64) error
+	AddReactionCounts(ctx context.Context, postID string, increments map[domain.ReactionType]int64) error
+	SetModerationState(ctx context.Context, id string, state domain.ModerationState, flags []string) error
+}
+
+// ReactionRepository defines the interface for post reaction persistence
+type ReactionRepository interface {
+	// Set records a user's reaction to a post and returns the reaction it replaced, or "" when there was none
+	Set(ctx context.Context, reaction *domain.PostReaction) (domain.ReactionType, error)
+	// Delete removes a user's reaction to a post and returns it, or "" when there was none
+	Delete(ctx context.Context, postID, userID string) (domain.ReactionType, error)
+	// GetByUser returns the user's reactions to the given posts, keyed by post ID
+	GetByUser(ctx context.Context, userID string, postIDs []string) (map[string]domain.ReactionType, error)
 }
 
 // SupportRepository defines the interface for support response persistence
@@ -42,6 +75,7 @@ type SupportRepository interface {
 	CountByPostID(ctx context.Context, postID primitive.ObjectID) (int64, error)
 	GetResponseCount(ctx context.Context, postID string) (int64, error)
 	GetUserStats(ctx context.Context, userID string) (given, received int64, err error)
+	CountGivenPerDay(ctx context.Context, userID string, since time.Time) (map[time.Time]int, error)
 }
 
 // CircleRepository defines the interface for circle data persistence
@@ -52,20 +86,124 @@ type CircleRepository interface {
 	JoinCircle(ctx context.Context, circleID, userID uuid.UUID) error
 	LeaveCircle(ctx context.Context, circleID, userID uuid.UUID) error
 	GetMembers(ctx context.Context, circleID uuid.UUID, limit, offset int) ([]uuid.UUID, error)
+	GetUserCircleIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 	IsMember(ctx context.Context, circleID, userID uuid.UUID) (bool, error)
+	GetMemberRole(ctx context.Context, circleID, userID uuid.UUID) (domain.CircleRole, error)
 	GetMemberCount(ctx context.Context, circleID uuid.UUID) (int, error)
+	// Delete soft deletes the circle and returns the IDs of its members
+	Delete(ctx context.Context, circleID uuid.UUID) ([]uuid.UUID, error)
+	Count(ctx context.Context) (int, error)
+}
+
+// RuntimeSettingRepository persists admin overrides of runtime settings
+type RuntimeSettingRepository interface {
+	List(ctx context.Context) ([]*domain.RuntimeSetting, error)
+	Set(ctx context.Context, key, value string, updatedBy *uuid.UUID) (*domain.RuntimeSetting, error)
+	// Delete removes the override, returning the setting to its default
+	Delete(ctx context.Context, key string) error
+}
+
+// RuntimeSettingNotifier tells every instance when a runtime setting changes
+type RuntimeSettingNotifier interface {
+	PublishChange(ctx context.Context, key string) error
+	SubscribeChanges(ctx context.Context, handler func(key string)) error
+}
+
+// FeatureFlagRepository persists feature flags
+type FeatureFlagRepository interface {
+	List(ctx context.Context) ([]*domain.FeatureFlag, error)
+	Set(ctx context.Context, key string, enabled bool, updatedBy *uuid.UUID) (*domain.FeatureFlag, error)
+}
+
+// SubscriptionRepository persists users' plans
+type SubscriptionRepository interface {
+	// Get returns the user's subscription, or nil if they've never had one
+	Get(ctx context.Context, userID uuid.UUID) (*domain.Subscription, error)
+	// GetByExternalID returns the subscription with a Stripe subscription ID, or nil
+	GetByExternalID(ctx context.Context, externalID string) (*domain.Subscription, error)
+	// Save creates or replaces the user's subscription and sets users.is_premium
+	Save(ctx context.Context, subscription *domain.Subscription, isPremium bool) error
 }
 
 // ModerationRepository defines the interface for moderation data persistence
 type ModerationRepository interface {
+	// CreateReport opens a case, or sets report.ID to the case already pending
+	// for the same content
 	CreateReport(ctx context.Context, report *domain.ContentReport) error
 	GetReportByID(ctx context.Context, id uuid.UUID) (*domain.ContentReport, error)
 	GetReports(ctx context.Context, status *string, limit, offset int) ([]*domain.ContentReport, error)
 	ListReports(ctx context.Context, status *string, limit, offset int) ([]*domain.ContentReport, error)
 	UpdateReportStatus(ctx context.Context, id uuid.UUID, status string, reviewedBy uuid.UUID, notes string) error
+	GetOpenReportForContent(ctx context.Context, contentType, contentID string) (*domain.ContentReport, error)
+	AddReporter(ctx context.Context, reportID, reporterID uuid.UUID, reason string, weight float64) (bool, error)
+	GetReporterReputation(ctx context.Context, userID uuid.UUID) (*domain.ReporterReputation, error)
+	RecordReportOutcome(ctx context.Context, reportID uuid.UUID, upheld bool) error
+	GetReporterIDs(ctx context.Context, reportID uuid.UUID) ([]uuid.UUID, error)
+	// ListReportsCreatedBetween returns reports created in [since, until), oldest first
+	ListReportsCreatedBetween(ctx context.Context, since, until time.Time, limit, offset int) ([]*domain.ContentReport, error)
+	GetModerationStats(ctx context.Context, resolvedSince time.Time) ([]*domain.ModerationSeverityStats, error)
+	CountOverdueReports(ctx context.Context, severity string, createdBefore time.Time) (int, error)
+	CreateNote(ctx context.Context, note *domain.ModeratorNote) error
+	GetNoteByID(ctx context.Context, id uuid.UUID) (*domain.ModeratorNote, error)
+	GetNotesByReportID(ctx context.Context, reportID uuid.UUID) ([]*domain.ModeratorNote, error)
 	CreateBlock(ctx context.Context, blockerID, blockedID uuid.UUID) error
 	RemoveBlock(ctx context.Context, blockerID, blockedID uuid.UUID) error
 	IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error)
+	GetBlockedUserIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// FingerprintRepository stores hashed client fingerprints for ban evasion detection
+type FingerprintRepository interface {
+	RecordFingerprint(ctx context.Context, userID uuid.UUID, kind, hash string) error
+	FindBannedUsersByFingerprint(ctx context.Context, hashes []string, excludeUserID uuid.UUID, since time.Time) ([]uuid.UUID, error)
+}
+
+// LoginLocationRepository stores the countries users log in from
+type LoginLocationRepository interface {
+	RecordLoginLocation(ctx context.Context, location *domain.LoginLocation) error
+	GetLatestLoginLocation(ctx context.Context, userID uuid.UUID) (*domain.LoginLocation, error)
+	HasLoginCountry(ctx context.Context, userID uuid.UUID, country string) (bool, error)
+}
+
+// LoginRiskRepository tracks failed logins per IP and pending step-up challenges
+type LoginRiskRepository interface {
+	IncrementIPFailures(ctx context.Context, ipHash string, window time.Duration) (int64, error)
+	GetIPFailures(ctx context.Context, ipHash string) (int64, error)
+	CreateLoginChallenge(ctx context.Context, challenge *domain.LoginChallenge, ttl time.Duration) error
+	GetLoginChallenge(ctx context.Context, id string) (*domain.LoginChallenge, error)
+	IncrementLoginChallengeAttempts(ctx context.Context, id string) (int64, error)
+	DeleteLoginChallenge(ctx context.Context, id string) error
+}
+
+// NotificationRepository defines the interface for the in-app notification inbox
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *domain.Notification) error
+	ListByUser(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]*domain.Notification, error)
+	MarkRead(ctx context.Context, userID string, ids []string) (int64, error)
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+}
+
+// NotificationPreferencesRepository stores per-user notification preferences
+type NotificationPreferencesRepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error)
+	Upsert(ctx context.Context, prefs *domain.NotificationPreferences) error
+	ClaimDueCheckInReminders(ctx context.Context, limit int) ([]*domain.NotificationPreferences, error)
+}
+
+// CategorySubscriptionRepository stores the categories users volunteer to help with
+type CategorySubscriptionRepository interface {
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]string, error)
+	ReplaceForUser(ctx context.Context, userID uuid.UUID, categories []string) error
+	SampleSubscribers(ctx context.Context, categories []string, excludeUserID uuid.UUID, limit int) ([]uuid.UUID, error)
+}
+
+// DeviceTokenRepository stores push notification device tokens
+type DeviceTokenRepository interface {
+	RegisterToken(ctx context.Context, token *domain.DeviceToken) error
+	GetActiveTokens(ctx context.Context, userID uuid.UUID) ([]*domain.DeviceToken, error)
+	MarkTokenInvalid(ctx context.Context, token, reason string) error
+	DeleteInvalidTokens(ctx context.Context, before time.Time) (int64, error)
 }
 
 // SessionRepository defines the interface for session management
@@ -83,11 +221,23 @@ type SessionRepository interface {
 	// User online status
 	SetUserOnline(ctx context.Context, userID string, ttl time.Duration) error
 	IsUserOnline(ctx context.Context, userID string) (bool, error)
+	SetUserOffline(ctx context.Context, userID string) error
+	GetOnlineUsers(ctx context.Context, userIDs []string) (map[string]bool, error)
+}
+
+// ChangeStreamRepository streams posts and responses as they are inserted,
+// including writes that bypass the services such as migrations and admin tools
+type ChangeStreamRepository interface {
+	// WatchPosts calls handler for each visible post inserted until ctx is done or the stream fails
+	WatchPosts(ctx context.Context, handler func(*domain.Post)) error
+	// WatchResponses calls handler for each response inserted until ctx is done or the stream fails
+	WatchResponses(ctx context.Context, handler func(*domain.SupportResponse)) error
 }
 
 // RealtimeRepository defines the interface for real-time data management
 type RealtimeRepository interface {
-	IncrementViewCount(ctx context.Context, postID string) error
+	// IncrementViewCounts adds a batch of buffered views in one round trip
+	IncrementViewCounts(ctx context.Context, views map[string]int64) error
 	GetViewCount(ctx context.Context, postID string) (int64, error)
 	AddSupporter(ctx context.Context, postID, userID string) error
 	GetSupporters(ctx context.Context, postID string) ([]string, error)
@@ -95,8 +245,10 @@ type RealtimeRepository interface {
 	GetFeed(ctx context.Context, userID string, limit int) ([]string, error)
 	PublishNotification(ctx context.Context, channel string, message interface{}) error
 	SubscribeToChannel(ctx context.Context, channel string) error
-	PublishNewPost(ctx context.Context, postID, postType string, categories []string) error
-	PublishNewResponse(ctx context.Context, postID, responseID string) error
+	PublishNewPost(ctx context.Context, postID, authorID, postType string, categories []string) error
+	PublishNewResponse(ctx context.Context, postID, responseID, responderID string) error
+	PublishEvent(ctx context.Context, event *domain.RealtimeEvent) error
+	SubscribeEvents(ctx context.Context, handler func(*domain.RealtimeEvent)) error
 	AddSupporterToPost(ctx context.Context, postID, userID string) error
 	GetSupporterCount(ctx context.Context, postID string) (int64, error)
 	CheckRateLimit(ctx context.Context, userID, action string, limit int, window time.Duration) (bool, error)
@@ -110,20 +262,101 @@ type CacheRepository interface {
 	Exists(ctx context.Context, key string) (bool, error)
 }
 
+// BlockCacheRepository caches each user's block set (users blocked in either direction)
+type BlockCacheRepository interface {
+	GetBlockSet(ctx context.Context, userID string) ([]string, bool, error)
+	SetBlockSet(ctx context.Context, userID string, blockedIDs []string, ttl time.Duration) error
+	InvalidateBlockSet(ctx context.Context, userIDs ...string) error
+}
+
+// UserSummaryCacheRepository caches users' public identities for list views
+type UserSummaryCacheRepository interface {
+	// GetSummaries returns the cached summaries among ids; missing users are absent from the map
+	GetSummaries(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.UserSummary, error)
+	SetSummaries(ctx context.Context, summaries []*domain.UserSummary, ttl time.Duration) error
+	InvalidateSummary(ctx context.Context, userID uuid.UUID) error
+}
+
+// CircleMembershipCacheRepository caches the set of circles each user belongs to
+type CircleMembershipCacheRepository interface {
+	GetCircleSet(ctx context.Context, userID string) ([]string, bool, error)
+	SetCircleSet(ctx context.Context, userID string, circleIDs []string, ttl time.Duration) error
+	InvalidateCircleSet(ctx context.Context, userID string) error
+	GetMemberRole(ctx context.Context, circleID, userID string) (domain.CircleRole, bool, error)
+	SetMemberRole(ctx context.Context, circleID, userID string, role domain.CircleRole, ttl time.Duration) error
+	InvalidateMemberRole(ctx context.Context, circleID, userID string) error
+}
+
 // AnalyticsRepository defines the interface for analytics and user tracking
 type AnalyticsRepository interface {
 	CreateUserTracker(ctx context.Context, userID uuid.UUID) error
 	GetUserTracker(ctx context.Context, userID uuid.UUID) (*domain.UserTracker, error)
 	GetTracker(ctx context.Context, userID string) (*domain.UserTracker, error)
-	UpdateStreak(ctx context.Context, userID uuid.UUID, hasRelapsed bool) error
+	UpdateStreak(ctx context.Context, userID uuid.UUID, hasRelapsed bool, freezesPerMonth int) error
+	AddStreakFreeze(ctx context.Context, userID uuid.UUID, day time.Time) error
+	SetSavingsBaseline(ctx context.Context, userID string, baseline *domain.SavingsBaseline) error
+	SetTimezone(ctx context.Context, userID string, timezone string) error
 	IncrementCravings(ctx context.Context, userID uuid.UUID, resisted bool) error
-	AddMilestone(ctx context.Context, userID uuid.UUID, milestone string) error
+	AddMilestone(ctx context.Context, userID uuid.UUID, milestone *domain.Milestone) error
+	IncrementVulnerability(ctx context.Context, userID string, at time.Time, weight int) (map[string]int, error)
+	SetRiskWindows(ctx context.Context, userID string, windows []domain.RiskWindow) error
+	RecordCopingUse(ctx context.Context, userID, strategyID string, helped bool, at time.Time) error
+	ClaimRiskNudge(ctx context.Context, startHour int, nudgedBefore time.Time) (*domain.UserTracker, error)
+	UnlockAchievement(ctx context.Context, userID string, achievement domain.UnlockedAchievement) (bool, error)
+	ListTrackersDueForDigest(ctx context.Context, sentBefore time.Time, limit int) ([]*domain.UserTracker, error)
+	ClaimDigest(ctx context.Context, userID string, sentBefore time.Time, snapshot *domain.DigestSnapshot) (bool, error)
+	RecordMood(ctx context.Context, entry *domain.MoodEntry) error
+	GetMoodHistory(ctx context.Context, userID string, since time.Time) ([]*domain.MoodEntry, error)
+	RecordDailyCheckIn(ctx context.Context, userID string, day time.Time, hadRelapse bool, answers []domain.CheckInAnswer) error
+	IncrementDailyCravings(ctx context.Context, userID string, day time.Time, resisted bool) error
+	GetDailyCheckIns(ctx context.Context, userID string, since time.Time) ([]*domain.DailyCheckIn, error)
+	CreateRelapse(ctx context.Context, relapse *domain.RelapseRecord) error
+	ListRelapses(ctx context.Context, userID string, limit int) ([]*domain.RelapseRecord, error)
+}
+
+// MetricsRepository defines the interface for platform-wide activity aggregates
+type MetricsRepository interface {
+	CountActiveUsers(ctx context.Context, since time.Time) (int, error)
+	ActiveUsersByWeek(ctx context.Context, since time.Time) (map[time.Time][]string, error)
+	ContentVolumePerDay(ctx context.Context, since time.Time) ([]*domain.ContentVolume, error)
+	ListSOSResponses(ctx context.Context, since time.Time) ([]*domain.SOSResponse, error)
+	SumDaysClean(ctx context.Context) (int64, int, error)
+	CountSupportGiven(ctx context.Context, since time.Time) (int, int, error)
+}
+
+// JournalRepository defines the interface for private journal entries
+type JournalRepository interface {
+	Create(ctx context.Context, entry *domain.JournalEntry) error
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]*domain.JournalEntry, error)
+	Search(ctx context.Context, userID string, tokens []string, limit, offset int) ([]*domain.JournalEntry, error)
 }
 
 // AuditRepository defines the interface for audit logging
 type AuditRepository interface {
 	CreateAuditLog(ctx context.Context, log *domain.AuditLog) error
 	GetAuditLogs(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]*domain.AuditLog, error)
+	// ListAuditLogsCreatedBetween returns entries created in [since, until), oldest first
+	ListAuditLogsCreatedBetween(ctx context.Context, since, until time.Time, limit, offset int) ([]*domain.AuditLog, error)
+}
+
+// ExportRepository defines the interface for data export jobs
+type ExportRepository interface {
+	Create(ctx context.Context, export *domain.DataExport) error
+	// Get returns the export, or nil if there's none with the ID
+	Get(ctx context.Context, id uuid.UUID) (*domain.DataExport, error)
+	// List returns exports newest first
+	List(ctx context.Context, limit, offset int) ([]*domain.DataExport, error)
+	// Update saves the export's status, columns, object key, row count, error and completion time
+	Update(ctx context.Context, export *domain.DataExport) error
+}
+
+// RetentionRepository deletes or anonymizes records of a data class created
+// before a cutoff. Each call handles at most limit records and returns how
+// many it changed; CountExpired returns how many an action would change.
+type RetentionRepository interface {
+	CountExpired(ctx context.Context, class domain.DataClass, action domain.RetentionAction, cutoff time.Time) (int64, error)
+	DeleteExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error)
+	AnonymizeExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error)
 }
 
 // InviteRepository defines the interface for circle invites
@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure CircleRepository implements repository.CircleRepository
+var _ repository.CircleRepository = (*CircleRepository)(nil)
+
+type CircleRepository struct {
+	mu          sync.RWMutex
+	circles     map[uuid.UUID]*domain.Circle
+	deleted     map[uuid.UUID]bool
+	memberships []*circleMembership
+}
+
+type circleMembership struct {
+	circleID uuid.UUID
+	userID   uuid.UUID
+	role     domain.CircleRole
+	joinedAt time.Time
+}
+
+func NewCircleRepository() *CircleRepository {
+	return &CircleRepository{
+		circles: make(map[uuid.UUID]*domain.Circle),
+		deleted: make(map[uuid.UUID]bool),
+	}
+}
+
+func (r *CircleRepository) Create(ctx context.Context, circle *domain.Circle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.circles[circle.ID]; ok {
+		return fmt.Errorf("circle %s already exists", circle.ID)
+	}
+	circle.CreatedAt = time.Now()
+	c := *circle
+	c.MemberCount = 0 // The column defaults to zero; joining counts members
+	r.circles[circle.ID] = &c
+	return nil
+}
+
+func (r *CircleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Circle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	circle, ok := r.circles[id]
+	if !ok || r.deleted[id] {
+		return nil, fmt.Errorf("circle not found")
+	}
+	c := *circle
+	return &c, nil
+}
+
+func (r *CircleRepository) List(ctx context.Context, category *string, limit, offset int) ([]*domain.Circle, error) {
+	r.mu.RLock()
+	circles := []*domain.Circle{}
+	for id, circle := range r.circles {
+		if r.deleted[id] || (category != nil && circle.Category != *category) {
+			continue
+		}
+		c := *circle
+		circles = append(circles, &c)
+	}
+	r.mu.RUnlock()
+
+	newestFirst(circles, func(c *domain.Circle) time.Time { return c.CreatedAt }, func(c *domain.Circle) string { return c.ID.String() })
+	return page(circles, limit, offset), nil
+}
+
+// JoinCircle adds the user as a member. Joining twice fails, as the
+// memberships table is unique by circle and user.
+func (r *CircleRepository) JoinCircle(ctx context.Context, circleID, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	circle, ok := r.circles[circleID]
+	if !ok {
+		return fmt.Errorf("circle not found")
+	}
+	if r.membership(circleID, userID) != nil {
+		return fmt.Errorf("user %s is already a member of circle %s", userID, circleID)
+	}
+	r.memberships = append(r.memberships, &circleMembership{
+		circleID: circleID,
+		userID:   userID,
+		role:     domain.CircleRoleMember,
+		joinedAt: time.Now(),
+	})
+	circle.MemberCount++
+	return nil
+}
+
+func (r *CircleRepository) LeaveCircle(ctx context.Context, circleID, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, m := range r.memberships {
+		if m.circleID == circleID && m.userID == userID {
+			r.memberships = append(r.memberships[:i], r.memberships[i+1:]...)
+			break
+		}
+	}
+	// Like the PostgreSQL repository, the count drops even if the user wasn't a member
+	if circle, ok := r.circles[circleID]; ok {
+		circle.MemberCount--
+	}
+	return nil
+}
+
+// GetMembers returns the circle's members, most recently joined first
+func (r *CircleRepository) GetMembers(ctx context.Context, circleID uuid.UUID, limit, offset int) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	var memberships []*circleMembership
+	for _, m := range r.memberships {
+		if m.circleID == circleID {
+			memberships = append(memberships, m)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(memberships, func(i, j int) bool { return memberships[i].joinedAt.After(memberships[j].joinedAt) })
+	members := []uuid.UUID{}
+	for _, m := range page(memberships, limit, offset) {
+		members = append(members, m.userID)
+	}
+	return members, nil
+}
+
+// GetUserCircleIDs returns the circles the user belongs to, in the order they joined
+func (r *CircleRepository) GetUserCircleIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	circleIDs := []uuid.UUID{}
+	for _, m := range r.memberships {
+		if m.userID == userID && !r.deleted[m.circleID] {
+			circleIDs = append(circleIDs, m.circleID)
+		}
+	}
+	return circleIDs, nil
+}
+
+func (r *CircleRepository) IsMember(ctx context.Context, circleID, userID uuid.UUID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.membership(circleID, userID) != nil, nil
+}
+
+// GetMemberRole returns the user's role in the circle, or an empty role when
+// they are not a member or the circle is deleted
+func (r *CircleRepository) GetMemberRole(ctx context.Context, circleID, userID uuid.UUID) (domain.CircleRole, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m := r.membership(circleID, userID)
+	if m == nil || r.deleted[circleID] {
+		return "", nil
+	}
+	return m.role, nil
+}
+
+func (r *CircleRepository) GetMemberCount(ctx context.Context, circleID uuid.UUID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, m := range r.memberships {
+		if m.circleID == circleID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Delete soft deletes the circle and returns the IDs of its members, whose
+// memberships are kept
+func (r *CircleRepository) Delete(ctx context.Context, circleID uuid.UUID) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.circles[circleID]; !ok || r.deleted[circleID] {
+		return nil, fmt.Errorf("circle not found")
+	}
+	r.deleted[circleID] = true
+
+	memberIDs := []uuid.UUID{}
+	for _, m := range r.memberships {
+		if m.circleID == circleID {
+			memberIDs = append(memberIDs, m.userID)
+		}
+	}
+	return memberIDs, nil
+}
+
+func (r *CircleRepository) Count(ctx context.Context) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.circles) - len(r.deleted), nil
+}
+
+// AddMember adds the user to the circle with a role, as the circle service
+// does for a circle's creator and as owners do when appointing moderators
+func (r *CircleRepository) AddMember(circleID, userID uuid.UUID, role domain.CircleRole) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	circle, ok := r.circles[circleID]
+	if !ok {
+		return fmt.Errorf("circle not found")
+	}
+	if m := r.membership(circleID, userID); m != nil {
+		m.role = role
+		return nil
+	}
+	r.memberships = append(r.memberships, &circleMembership{circleID: circleID, userID: userID, role: role, joinedAt: time.Now()})
+	circle.MemberCount++
+	return nil
+}
+
+func (r *CircleRepository) membership(circleID, userID uuid.UUID) *circleMembership {
+	for _, m := range r.memberships {
+		if m.circleID == circleID && m.userID == userID {
+			return m
+		}
+	}
+	return nil
+}
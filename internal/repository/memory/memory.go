@@ -0,0 +1,35 @@
+// Package memory provides in-memory implementations of the repository
+// interfaces for unit tests. They follow the database implementations'
+// behavior, including their errors, filters and orderings, without needing
+// PostgreSQL, MongoDB or Redis. Every repository is safe for concurrent use
+// and returns copies, so callers can't change stored records by accident.
+package memory
+
+import (
+	"sort"
+	"time"
+)
+
+// page returns the items in [offset, offset+limit)
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// newestFirst sorts items by created time, newest first, breaking ties with
+// the larger key first
+func newestFirst[T any](items []T, createdAt func(T) time.Time, key func(T) string) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := createdAt(items[i]), createdAt(items[j])
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return key(items[i]) > key(items[j])
+	})
+}
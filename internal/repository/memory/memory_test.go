@@ -0,0 +1,162 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository/memory"
+	"github.com/yourorg/anonymous-support/internal/testutil/factory"
+)
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository()
+
+	user := factory.User(func(u *domain.User) {
+		u.Username = "quiet_river"
+		u.Role = ""
+	})
+	require.NoError(t, repo.Create(ctx, user))
+	assert.Equal(t, domain.RoleUser, user.Role)
+	assert.Error(t, repo.Create(ctx, factory.User(func(u *domain.User) { u.Username = "quiet_river" })), "usernames are unique")
+
+	require.NoError(t, repo.UpdateStrengthPoints(ctx, user.ID, 5))
+	got, err := repo.GetByUsername(ctx, "quiet_river")
+	require.NoError(t, err)
+	assert.Equal(t, 5, got.StrengthPoints)
+
+	got.StrengthPoints = 100
+	stored, err := repo.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, stored.StrengthPoints, "changing a returned user doesn't change the stored one")
+
+	require.NoError(t, repo.SetBanned(ctx, user.ID, true))
+	_, err = repo.GetByID(ctx, user.ID)
+	assert.Error(t, err, "banned users are hidden")
+	_, err = repo.GetAnyByID(ctx, user.ID)
+	assert.NoError(t, err)
+
+	banned := true
+	users, total, err := repo.Search(ctx, domain.UserSearch{Query: "RIVER", Banned: &banned, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, users, 1)
+}
+
+func TestPostRepository_Feed(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewPostRepository()
+	circleID := uuid.NewString()
+
+	create := func(postType domain.PostType, category, visibility string, state domain.ModerationState) *domain.Post {
+		post := factory.Post(func(p *domain.Post) {
+			p.Type, p.Categories, p.Visibility, p.ModerationState = postType, []string{category}, visibility, state
+		})
+		if visibility == "circle" {
+			post.CircleID = &circleID
+		}
+		require.NoError(t, repo.Create(ctx, post))
+		return post
+	}
+	sos := create(domain.PostTypeSOS, "alcohol", "public", domain.ModerationStateVisible)
+	checkIn := create(domain.PostTypeCheckIn, "alcohol", "public", domain.ModerationStateVisible)
+	victory := create(domain.PostTypeVictory, "nicotine", "public", domain.ModerationStateVisible)
+	create(domain.PostTypeSOS, "alcohol", "public", domain.ModerationStateQuarantined)
+	inCircle := create(domain.PostTypeCheckIn, "alcohol", "circle", domain.ModerationStateVisible)
+
+	ids := func(posts []*domain.Post) []string {
+		var ids []string
+		for _, post := range posts {
+			ids = append(ids, post.ID.Hex())
+		}
+		return ids
+	}
+
+	posts, err := repo.GetFeed(ctx, nil, nil, nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{victory.ID.Hex(), checkIn.ID.Hex(), sos.ID.Hex()}, ids(posts), "visible public posts, newest first")
+
+	posts, err = repo.GetFeed(ctx, nil, &circleID, nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{inCircle.ID.Hex()}, ids(posts))
+
+	sosType := domain.PostTypeSOS
+	feedPage, err := repo.GetFeedPage(ctx, []string{"alcohol"}, nil, &sosType, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), feedPage.TotalCount)
+	assert.Equal(t, map[string]int64{"alcohol": 1}, feedPage.CategoryCounts)
+	assert.Equal(t, map[domain.PostType]int64{domain.PostTypeSOS: 1, domain.PostTypeCheckIn: 1}, feedPage.TypeCounts)
+
+	posts, err = repo.GetFeedAfter(ctx, nil, nil, nil, time.Time{}, "", 2)
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	last := posts[1]
+	posts, err = repo.GetFeedAfter(ctx, nil, nil, nil, last.CreatedAt, last.ID.Hex(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{sos.ID.Hex()}, ids(posts))
+}
+
+func TestCircleRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewCircleRepository()
+
+	owner, member := uuid.New(), uuid.New()
+	circle := factory.Circle(func(c *domain.Circle) { c.CreatedBy = owner })
+	require.NoError(t, repo.Create(ctx, circle))
+	require.NoError(t, repo.AddMember(circle.ID, owner, domain.CircleRoleOwner))
+	require.NoError(t, repo.JoinCircle(ctx, circle.ID, member))
+	assert.Error(t, repo.JoinCircle(ctx, circle.ID, member), "memberships are unique")
+
+	role, err := repo.GetMemberRole(ctx, circle.ID, owner)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CircleRoleOwner, role)
+
+	got, err := repo.GetByID(ctx, circle.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.MemberCount)
+
+	members, err := repo.Delete(ctx, circle.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{owner, member}, members)
+	_, err = repo.GetByID(ctx, circle.ID)
+	assert.Error(t, err)
+	circleIDs, err := repo.GetUserCircleIDs(ctx, member)
+	require.NoError(t, err)
+	assert.Empty(t, circleIDs, "deleted circles are hidden")
+}
+
+func TestSessionRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewSessionRepository()
+
+	require.NoError(t, repo.StoreRefreshToken(ctx, "user", "token-1", time.Hour))
+	require.NoError(t, repo.StoreRefreshToken(ctx, "user", "token-2", time.Hour))
+	require.NoError(t, repo.StoreRefreshToken(ctx, "user", "expired", -time.Second))
+
+	valid, _ := repo.ValidateRefreshToken(ctx, "user", "token-1")
+	assert.True(t, valid)
+	valid, _ = repo.ValidateRefreshToken(ctx, "user", "expired")
+	assert.False(t, valid)
+	valid, _ = repo.ValidateRefreshToken(ctx, "other", "token-1")
+	assert.False(t, valid)
+
+	require.NoError(t, repo.RevokeRefreshToken(ctx, "user", "token-1"))
+	valid, _ = repo.ValidateRefreshToken(ctx, "user", "token-1")
+	assert.False(t, valid)
+	valid, _ = repo.ValidateRefreshToken(ctx, "user", "token-2")
+	assert.True(t, valid)
+
+	require.NoError(t, repo.RevokeAllRefreshTokens(ctx, "user"))
+	valid, _ = repo.ValidateRefreshToken(ctx, "user", "token-2")
+	assert.False(t, valid)
+
+	require.NoError(t, repo.SetUserOnline(ctx, "user", time.Minute))
+	online, err := repo.GetOnlineUsers(ctx, []string{"user", "other"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"user": true, "other": false}, online)
+}
@@ -0,0 +1,285 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Compile-time check to ensure PostRepository implements repository.PostRepository
+var _ repository.PostRepository = (*PostRepository)(nil)
+
+type PostRepository struct {
+	mu    sync.RWMutex
+	posts map[primitive.ObjectID]*domain.Post
+}
+
+func NewPostRepository() *PostRepository {
+	return &PostRepository{posts: make(map[primitive.ObjectID]*domain.Post)}
+}
+
+func (r *PostRepository) Create(ctx context.Context, post *domain.Post) error {
+	post.ID = primitive.NewObjectID()
+	post.CreatedAt = time.Now()
+	post.ResponseCount = 0
+	post.SupportCount = 0
+
+	if post.ExpiresAt == nil {
+		expiresAt := time.Now().Add(30 * 24 * time.Hour)
+		post.ExpiresAt = &expiresAt
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.posts[post.ID] = copyPost(post)
+	return nil
+}
+
+func (r *PostRepository) GetByID(ctx context.Context, id string) (*domain.Post, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post ID")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	post, ok := r.posts[objectID]
+	if !ok {
+		return nil, fmt.Errorf("post not found")
+	}
+	return copyPost(post), nil
+}
+
+func (r *PostRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	posts := []*domain.Post{}
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		if post, ok := r.posts[objectID]; ok {
+			posts = append(posts, copyPost(post))
+		}
+	}
+	return posts, nil
+}
+
+func (r *PostRepository) GetFeed(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) ([]*domain.Post, error) {
+	posts := r.feed(circleID, func(p *domain.Post) bool {
+		return inCategories(p, categories) && ofType(p, postType)
+	})
+	return page(posts, limit, offset), nil
+}
+
+// GetFeedPage returns a feed page along with its total count and per-category
+// and per-type counts. Each count ignores its own filter.
+func (r *PostRepository) GetFeedPage(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) (*domain.FeedPage, error) {
+	scoped := r.feed(circleID, func(*domain.Post) bool { return true })
+
+	result := &domain.FeedPage{
+		CategoryCounts: map[string]int64{},
+		TypeCounts:     map[domain.PostType]int64{},
+	}
+	matched := []*domain.Post{}
+	for _, post := range scoped {
+		byCategory, byType := inCategories(post, categories), ofType(post, postType)
+		if byCategory && byType {
+			matched = append(matched, post)
+		}
+		if byType {
+			for _, category := range post.Categories {
+				result.CategoryCounts[category]++
+			}
+		}
+		if byCategory {
+			result.TypeCounts[post.Type]++
+		}
+	}
+	result.TotalCount = int64(len(matched))
+	result.Posts = page(matched, limit, offset)
+	return result, nil
+}
+
+// GetFeedAfter pages through a feed by cursor, ordering posts created at the
+// same time by ID
+func (r *PostRepository) GetFeedAfter(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, afterCreatedAt time.Time, afterID string, limit int) ([]*domain.Post, error) {
+	var after primitive.ObjectID
+	if afterID != "" {
+		objectID, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		after = objectID
+	}
+
+	posts := r.feed(circleID, func(p *domain.Post) bool {
+		if !inCategories(p, categories) || !ofType(p, postType) {
+			return false
+		}
+		return afterID == "" ||
+			p.CreatedAt.Before(afterCreatedAt) ||
+			(p.CreatedAt.Equal(afterCreatedAt) && p.ID.Hex() < after.Hex())
+	})
+	return page(posts, limit, 0), nil
+}
+
+func (r *PostRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid post ID")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.posts[objectID]; !ok {
+		return fmt.Errorf("post not found")
+	}
+	delete(r.posts, objectID)
+	return nil
+}
+
+func (r *PostRepository) UpdateUrgency(ctx context.Context, id string, urgencyLevel int32) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid post ID")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	post, ok := r.posts[objectID]
+	if !ok {
+		return fmt.Errorf("post not found")
+	}
+	post.UrgencyLevel = int(urgencyLevel)
+	return nil
+}
+
+func (r *PostRepository) AddResponseCounts(ctx context.Context, increments map[string]int64) error {
+	r.addCounts(increments, func(p *domain.Post, delta int64) { p.ResponseCount += int(delta) })
+	return nil
+}
+
+func (r *PostRepository) AddSupportCounts(ctx context.Context, increments map[string]int64) error {
+	r.addCounts(increments, func(p *domain.Post, delta int64) { p.SupportCount += int(delta) })
+	return nil
+}
+
+func (r *PostRepository) AddReactionCounts(ctx context.Context, postID string, increments map[domain.ReactionType]int64) error {
+	objectID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		return fmt.Errorf("invalid post ID")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	post, ok := r.posts[objectID]
+	if !ok {
+		return nil
+	}
+	for reaction, delta := range increments {
+		if delta == 0 {
+			continue
+		}
+		if post.ReactionCounts == nil {
+			post.ReactionCounts = make(map[domain.ReactionType]int64)
+		}
+		post.ReactionCounts[reaction] += delta
+	}
+	return nil
+}
+
+func (r *PostRepository) SetModerationState(ctx context.Context, id string, state domain.ModerationState, flags []string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid post ID")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if post, ok := r.posts[objectID]; ok {
+		post.ModerationState = state
+		if flags != nil {
+			post.ModerationFlags = append([]string(nil), flags...)
+		}
+	}
+	return nil
+}
+
+// feed returns copies of the visible posts in a circle's feed, or the public
+// feed, that match, newest first
+func (r *PostRepository) feed(circleID *string, match func(*domain.Post) bool) []*domain.Post {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	posts := []*domain.Post{}
+	for _, post := range r.posts {
+		if post.ModerationState != domain.ModerationStateVisible {
+			continue
+		}
+		if circleID != nil && (post.CircleID == nil || *post.CircleID != *circleID) {
+			continue
+		}
+		if circleID == nil && post.Visibility != "public" {
+			continue
+		}
+		if match(post) {
+			posts = append(posts, copyPost(post))
+		}
+	}
+	newestFirst(posts, func(p *domain.Post) time.Time { return p.CreatedAt }, func(p *domain.Post) string { return p.ID.Hex() })
+	return posts
+}
+
+// addCounts applies each increment to its post, skipping invalid and unknown IDs
+func (r *PostRepository) addCounts(increments map[string]int64, add func(*domain.Post, int64)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, delta := range increments {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		if post, ok := r.posts[objectID]; ok {
+			add(post, delta)
+		}
+	}
+}
+
+func inCategories(post *domain.Post, categories []string) bool {
+	if len(categories) == 0 {
+		return true
+	}
+	for _, want := range categories {
+		for _, category := range post.Categories {
+			if category == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ofType(post *domain.Post, postType *domain.PostType) bool {
+	return postType == nil || post.Type == *postType
+}
+
+func copyPost(post *domain.Post) *domain.Post {
+	c := *post
+	c.Categories = append([]string(nil), post.Categories...)
+	c.ModerationFlags = append([]string(nil), post.ModerationFlags...)
+	if post.ReactionCounts != nil {
+		c.ReactionCounts = make(map[domain.ReactionType]int64, len(post.ReactionCounts))
+		for reaction, count := range post.ReactionCounts {
+			c.ReactionCounts[reaction] = count
+		}
+	}
+	return &c
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure ReactionRepository implements repository.ReactionRepository
+var _ repository.ReactionRepository = (*ReactionRepository)(nil)
+
+type ReactionRepository struct {
+	mu        sync.Mutex
+	reactions map[reactionKey]domain.ReactionType
+}
+
+type reactionKey struct {
+	postID, userID string
+}
+
+func NewReactionRepository() *ReactionRepository {
+	return &ReactionRepository{reactions: make(map[reactionKey]domain.ReactionType)}
+}
+
+func (r *ReactionRepository) Set(ctx context.Context, reaction *domain.PostReaction) (domain.ReactionType, error) {
+	reaction.CreatedAt = time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := reactionKey{reaction.PostID, reaction.UserID}
+	previous := r.reactions[key]
+	r.reactions[key] = reaction.Type
+	return previous, nil
+}
+
+func (r *ReactionRepository) Delete(ctx context.Context, postID, userID string) (domain.ReactionType, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := reactionKey{postID, userID}
+	removed := r.reactions[key]
+	delete(r.reactions, key)
+	return removed, nil
+}
+
+func (r *ReactionRepository) GetByUser(ctx context.Context, userID string, postIDs []string) (map[string]domain.ReactionType, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reactions := make(map[string]domain.ReactionType)
+	for _, postID := range postIDs {
+		if reaction, ok := r.reactions[reactionKey{postID, userID}]; ok {
+			reactions[postID] = reaction
+		}
+	}
+	return reactions, nil
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure RetentionRepository implements repository.RetentionRepository
+var _ repository.RetentionRepository = (*RetentionRepository)(nil)
+
+// RetentionRepository holds records of any data class by creation time.
+// Anonymized records stay, but no longer count as identifiable.
+type RetentionRepository struct {
+	mu      sync.Mutex
+	records map[domain.DataClass][]retentionRecord
+}
+
+type retentionRecord struct {
+	createdAt  time.Time
+	anonymized bool
+}
+
+func NewRetentionRepository() *RetentionRepository {
+	return &RetentionRepository{records: make(map[domain.DataClass][]retentionRecord)}
+}
+
+// Add stores count records of class created at createdAt
+func (r *RetentionRepository) Add(class domain.DataClass, createdAt time.Time, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for range count {
+		r.records[class] = append(r.records[class], retentionRecord{createdAt: createdAt})
+	}
+}
+
+// Count returns how many records of class remain and how many of them are anonymized
+func (r *RetentionRepository) Count(class domain.DataClass) (total, anonymized int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, record := range r.records[class] {
+		if record.anonymized {
+			anonymized++
+		}
+	}
+	return len(r.records[class]), anonymized
+}
+
+func (r *RetentionRepository) CountExpired(ctx context.Context, class domain.DataClass, action domain.RetentionAction, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, record := range r.records[class] {
+		if record.createdAt.Before(cutoff) && (action != domain.RetentionActionAnonymize || !record.anonymized) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *RetentionRepository) DeleteExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	kept := r.records[class][:0]
+	for _, record := range r.records[class] {
+		if record.createdAt.Before(cutoff) && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, record)
+	}
+	r.records[class] = kept
+	return deleted, nil
+}
+
+func (r *RetentionRepository) AnonymizeExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var anonymized int64
+	records := r.records[class]
+	for i := range records {
+		if anonymized == int64(limit) {
+			break
+		}
+		if records[i].createdAt.Before(cutoff) && !records[i].anonymized {
+			records[i].anonymized = true
+			anonymized++
+		}
+	}
+	return anonymized, nil
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure SessionRepository implements repository.SessionRepository
+var _ repository.SessionRepository = (*SessionRepository)(nil)
+
+// SessionRepository keeps refresh tokens and online markers until they
+// expire, like the Redis keys they stand in for
+type SessionRepository struct {
+	mu     sync.Mutex
+	tokens map[string]map[string]time.Time // User ID to token to expiry
+	online map[string]time.Time            // User ID to expiry
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		tokens: make(map[string]map[string]time.Time),
+		online: make(map[string]time.Time),
+	}
+}
+
+// StoreRefreshToken adds a token to the user's active tokens
+func (r *SessionRepository) StoreRefreshToken(ctx context.Context, userID, token string, expiry time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tokens[userID] == nil {
+		r.tokens[userID] = make(map[string]time.Time)
+	}
+	r.tokens[userID][token] = time.Now().Add(expiry)
+	return nil
+}
+
+// ValidateRefreshToken checks if a token is active and unexpired
+func (r *SessionRepository) ValidateRefreshToken(ctx context.Context, userID, token string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expiresAt, ok := r.tokens[userID][token]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+func (r *SessionRepository) RevokeRefreshToken(ctx context.Context, userID, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tokens[userID], token)
+	return nil
+}
+
+func (r *SessionRepository) RevokeAllRefreshTokens(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tokens, userID)
+	return nil
+}
+
+// GetRefreshToken fails, as the Redis repository only finds tokens stored
+// before token rotation
+func (r *SessionRepository) GetRefreshToken(ctx context.Context, userID string) (string, error) {
+	return "", fmt.Errorf("refresh token not found")
+}
+
+func (r *SessionRepository) DeleteRefreshToken(ctx context.Context, userID string) error {
+	return r.RevokeAllRefreshTokens(ctx, userID)
+}
+
+func (r *SessionRepository) SetUserOnline(ctx context.Context, userID string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.online[userID] = time.Now().Add(ttl)
+	return nil
+}
+
+func (r *SessionRepository) IsUserOnline(ctx context.Context, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.isOnline(userID), nil
+}
+
+func (r *SessionRepository) SetUserOffline(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.online, userID)
+	return nil
+}
+
+func (r *SessionRepository) GetOnlineUsers(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	online := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		online[userID] = r.isOnline(userID)
+	}
+	return online, nil
+}
+
+func (r *SessionRepository) isOnline(userID string) bool {
+	expiresAt, ok := r.online[userID]
+	return ok && time.Now().Before(expiresAt)
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Compile-time check to ensure SupportRepository implements repository.SupportRepository
+var _ repository.SupportRepository = (*SupportRepository)(nil)
+
+type SupportRepository struct {
+	mu        sync.RWMutex
+	responses []*domain.SupportResponse
+}
+
+func NewSupportRepository() *SupportRepository {
+	return &SupportRepository{}
+}
+
+func (r *SupportRepository) Create(ctx context.Context, response *domain.SupportResponse) error {
+	response.ID = primitive.NewObjectID()
+	response.CreatedAt = time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = append(r.responses, copyResponse(response))
+	return nil
+}
+
+func (r *SupportRepository) CreateResponse(ctx context.Context, response *domain.SupportResponse) error {
+	return r.Create(ctx, response)
+}
+
+func (r *SupportRepository) GetByPostID(ctx context.Context, postID primitive.ObjectID, limit, offset int) ([]*domain.SupportResponse, error) {
+	responses := r.filter(func(s *domain.SupportResponse) bool { return s.PostID == postID.Hex() })
+	newestFirst(responses, func(s *domain.SupportResponse) time.Time { return s.CreatedAt }, func(s *domain.SupportResponse) string { return s.ID.Hex() })
+	return page(responses, limit, offset), nil
+}
+
+func (r *SupportRepository) GetResponses(ctx context.Context, postID string, limit, offset int) ([]*domain.SupportResponse, error) {
+	objectID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByPostID(ctx, objectID, limit, offset)
+}
+
+func (r *SupportRepository) CountByPostID(ctx context.Context, postID primitive.ObjectID) (int64, error) {
+	return int64(len(r.filter(func(s *domain.SupportResponse) bool { return s.PostID == postID.Hex() }))), nil
+}
+
+func (r *SupportRepository) GetResponseCount(ctx context.Context, postID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		return 0, err
+	}
+	return r.CountByPostID(ctx, objectID)
+}
+
+// GetUserStats counts the responses the user has given. Like the MongoDB
+// repository, it doesn't count support received.
+func (r *SupportRepository) GetUserStats(ctx context.Context, userID string) (given, received int64, err error) {
+	return int64(len(r.filter(func(s *domain.SupportResponse) bool { return s.UserID == userID }))), 0, nil
+}
+
+// CountGivenPerDay counts the responses the user has given on each UTC day from since onwards
+func (r *SupportRepository) CountGivenPerDay(ctx context.Context, userID string, since time.Time) (map[time.Time]int, error) {
+	counts := make(map[time.Time]int)
+	for _, response := range r.filter(func(s *domain.SupportResponse) bool {
+		return s.UserID == userID && !s.CreatedAt.Before(since)
+	}) {
+		counts[response.CreatedAt.UTC().Truncate(24*time.Hour)]++
+	}
+	return counts, nil
+}
+
+// GetByUserID returns no responses, like the MongoDB repository
+func (r *SupportRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.SupportResponse, error) {
+	return []*domain.SupportResponse{}, nil
+}
+
+// filter returns copies of the responses matching match
+func (r *SupportRepository) filter(match func(*domain.SupportResponse) bool) []*domain.SupportResponse {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	responses := []*domain.SupportResponse{}
+	for _, response := range r.responses {
+		if match(response) {
+			responses = append(responses, copyResponse(response))
+		}
+	}
+	return responses
+}
+
+func copyResponse(response *domain.SupportResponse) *domain.SupportResponse {
+	c := *response
+	if response.VoiceNoteURL != nil {
+		url := *response.VoiceNoteURL
+		c.VoiceNoteURL = &url
+	}
+	return &c
+}
@@ -0,0 +1,262 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure UserRepository implements repository.UserRepository
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]*domain.User
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{users: make(map[uuid.UUID]*domain.User)}
+}
+
+// Create stores the user. Usernames and emails are unique, as in PostgreSQL.
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[user.ID]; ok {
+		return fmt.Errorf("user %s already exists", user.ID)
+	}
+	for _, existing := range r.users {
+		if existing.Username == user.Username {
+			return fmt.Errorf("username %q is taken", user.Username)
+		}
+		if user.Email != nil && existing.Email != nil && *existing.Email == *user.Email {
+			return fmt.Errorf("email is taken")
+		}
+	}
+
+	now := time.Now()
+	user.CreatedAt, user.LastActiveAt = now, now
+	if user.Role == "" {
+		user.Role = domain.RoleUser
+	}
+	r.users[user.ID] = copyUser(user)
+	return nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.ID == id && !u.IsBanned })
+}
+
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []*domain.User{}
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok && !user.IsBanned {
+			users = append(users, copyUser(user))
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.Username == username && !u.IsBanned })
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.Email != nil && *u.Email == email && !u.IsBanned })
+}
+
+func (r *UserRepository) UpdateLastActive(ctx context.Context, userID uuid.UUID) error {
+	r.update(userID, func(u *domain.User) { u.LastActiveAt = time.Now() })
+	return nil
+}
+
+func (r *UserRepository) UpdateStrengthPoints(ctx context.Context, userID uuid.UUID, points int) error {
+	r.update(userID, func(u *domain.User) { u.StrengthPoints += points })
+	return nil
+}
+
+func (r *UserRepository) UpdateProfile(ctx context.Context, userID uuid.UUID, username *string, avatarID *int, shareMilestones *bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return nil
+	}
+	if username != nil {
+		for _, existing := range r.users {
+			if existing.ID != userID && existing.Username == *username {
+				return fmt.Errorf("username %q is taken", *username)
+			}
+		}
+		user.Username = *username
+	}
+	if avatarID != nil {
+		user.AvatarID = *avatarID
+	}
+	if shareMilestones != nil {
+		user.ShareMilestones = *shareMilestones
+	}
+	return nil
+}
+
+func (r *UserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListWithEmail returns up to limit users with a stored email and an ID after
+// afterID, ordered by ID
+func (r *UserRepository) ListWithEmail(ctx context.Context, afterID uuid.UUID, limit int) ([]*domain.User, error) {
+	users := r.filter(func(u *domain.User) bool {
+		return u.Email != nil && u.ID.String() > afterID.String()
+	})
+	sort.Slice(users, func(i, j int) bool { return users[i].ID.String() < users[j].ID.String() })
+	return page(users, limit, 0), nil
+}
+
+// ReplaceEmail sets a user's stored email only if it still equals current
+func (r *UserRepository) ReplaceEmail(ctx context.Context, userID uuid.UUID, current, replacement string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok || user.Email == nil || *user.Email != current {
+		return false, nil
+	}
+	user.Email = &replacement
+	return true, nil
+}
+
+// Search returns a page of users matching the filter, newest first, and the
+// total number matched. Banned users are included.
+func (r *UserRepository) Search(ctx context.Context, filter domain.UserSearch) ([]*domain.User, int, error) {
+	id, parseErr := uuid.Parse(filter.Query)
+	query := strings.ToLower(filter.Query)
+	users := r.filter(func(u *domain.User) bool {
+		if filter.Query != "" {
+			if parseErr == nil && u.ID != id {
+				return false
+			}
+			if parseErr != nil && !strings.Contains(strings.ToLower(u.Username), query) {
+				return false
+			}
+		}
+		if filter.Role != nil && u.Role != *filter.Role {
+			return false
+		}
+		return filter.Banned == nil || u.IsBanned == *filter.Banned
+	})
+	newestFirst(users, func(u *domain.User) time.Time { return u.CreatedAt }, func(u *domain.User) string { return u.ID.String() })
+	return page(users, filter.Limit, filter.Offset), len(users), nil
+}
+
+// GetAnyByID returns the user whether or not they are banned
+func (r *UserRepository) GetAnyByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.ID == id })
+}
+
+func (r *UserRepository) SetBanned(ctx context.Context, userID uuid.UUID, banned bool) error {
+	r.update(userID, func(u *domain.User) { u.IsBanned = banned })
+	return nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, userID uuid.UUID, role domain.Role) error {
+	r.update(userID, func(u *domain.User) { u.Role = role })
+	return nil
+}
+
+// CountUsers counts accounts by status and role
+func (r *UserRepository) CountUsers(ctx context.Context) (*domain.UserCounts, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := &domain.UserCounts{Total: len(r.users)}
+	for _, user := range r.users {
+		if user.IsBanned {
+			counts.Banned++
+		}
+		switch user.Role {
+		case domain.RoleModerator:
+			counts.Moderators++
+		case domain.RoleAdmin:
+			counts.Admins++
+		}
+	}
+	return counts, nil
+}
+
+func (r *UserRepository) CountMembers(ctx context.Context) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.users), nil
+}
+
+// ListRegistrationsByWeek returns the IDs of users who registered in each week
+// (starting Monday, UTC) from since onwards
+func (r *UserRepository) ListRegistrationsByWeek(ctx context.Context, since time.Time) (map[time.Time][]string, error) {
+	weeks := make(map[time.Time][]string)
+	for _, user := range r.filter(func(u *domain.User) bool { return !u.CreatedAt.Before(since) }) {
+		day := user.CreatedAt.UTC().Truncate(24 * time.Hour)
+		week := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		weeks[week] = append(weeks[week], user.ID.String())
+	}
+	return weeks, nil
+}
+
+func (r *UserRepository) find(match func(*domain.User) bool) (*domain.User, error) {
+	if users := r.filter(match); len(users) > 0 {
+		return users[0], nil
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+// filter returns copies of the users matching match
+func (r *UserRepository) filter(match func(*domain.User) bool) []*domain.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := []*domain.User{}
+	for _, user := range r.users {
+		if match(user) {
+			users = append(users, copyUser(user))
+		}
+	}
+	return users
+}
+
+// update applies change to the stored user, if there is one
+func (r *UserRepository) update(userID uuid.UUID, change func(*domain.User)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, ok := r.users[userID]; ok {
+		change(user)
+	}
+}
+
+func copyUser(user *domain.User) *domain.User {
+	c := *user
+	if user.Email != nil {
+		email := *user.Email
+		c.Email = &email
+	}
+	return &c
+}
@@ -18,12 +18,18 @@ import (
 var _ repository.AnalyticsRepository = (*AnalyticsRepository)(nil)
 
 type AnalyticsRepository struct {
-	trackers *mongo.Collection
+	trackers *Collection
+	moods    *Collection
+	relapses *Collection
+	checkIns *Collection
 }
 
-func NewAnalyticsRepository(db *mongo.Database) *AnalyticsRepository {
+func NewAnalyticsRepository(db *DB) *AnalyticsRepository {
 	return &AnalyticsRepository{
 		trackers: db.Collection("user_trackers"),
+		moods:    db.Collection("mood_entries"),
+		relapses: db.Collection("relapse_events"),
+		checkIns: db.Collection("daily_checkins"),
 	}
 }
 
@@ -54,7 +60,8 @@ func (r *AnalyticsRepository) UpsertTracker(ctx context.Context, tracker *domain
 	return err
 }
 
-func (r *AnalyticsRepository) UpdateStreak(ctx context.Context, userID uuid.UUID, hadRelapse bool) error {
+// UpdateStreak applies a check-in to the user's streak, creating their tracker if needed
+func (r *AnalyticsRepository) UpdateStreak(ctx context.Context, userID uuid.UUID, hadRelapse bool, freezesPerMonth int) error {
 	tracker, err := r.GetTracker(ctx, userID.String())
 	if err != nil && err.Error() != "tracker not found" {
 		return err
@@ -73,17 +80,39 @@ func (r *AnalyticsRepository) UpdateStreak(ctx context.Context, userID uuid.UUID
 		}
 	}
 
-	if hadRelapse {
-		now := time.Now()
-		tracker.LastRelapseDate = &now
-		tracker.StreakDays = 0
-	} else {
-		tracker.StreakDays++
-	}
+	tracker.ApplyCheckIn(time.Now(), hadRelapse, freezesPerMonth)
 
 	return r.UpsertTracker(ctx, tracker)
 }
 
+// AddStreakFreeze covers day with a streak freeze
+func (r *AnalyticsRepository) AddStreakFreeze(ctx context.Context, userID uuid.UUID, day time.Time) error {
+	filter := bson.M{"user_id": userID.String()}
+	update := bson.M{"$addToSet": bson.M{"streak_freezes": day}}
+	_, err := r.trackers.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// SetSavingsBaseline replaces the user's savings baseline
+func (r *AnalyticsRepository) SetSavingsBaseline(ctx context.Context, userID string, baseline *domain.SavingsBaseline) error {
+	filter := bson.M{"user_id": userID}
+	update := bson.M{"$set": bson.M{"savings_baseline": baseline, "updated_at": time.Now()}}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.trackers.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+// SetTimezone sets the time zone the user's days are counted in
+func (r *AnalyticsRepository) SetTimezone(ctx context.Context, userID string, timezone string) error {
+	filter := bson.M{"user_id": userID}
+	update := bson.M{"$set": bson.M{"timezone": timezone, "updated_at": time.Now()}}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.trackers.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
 func (r *AnalyticsRepository) IncrementCravings(ctx context.Context, userID uuid.UUID, resisted bool) error {
 	filter := bson.M{"user_id": userID.String()}
 	incFields := bson.M{"total_cravings": 1}
@@ -101,9 +130,91 @@ func (r *AnalyticsRepository) IncrementCravings(ctx context.Context, userID uuid
 	return err
 }
 
-func (r *AnalyticsRepository) AddMilestone(ctx context.Context, userID uuid.UUID, name string) error {
-	return nil
+// IncrementVulnerability adds weight to the user's vulnerability pattern for
+// the UTC hour of at, returning the updated pattern
+func (r *AnalyticsRepository) IncrementVulnerability(ctx context.Context, userID string, at time.Time, weight int) (map[string]int, error) {
+	filter := bson.M{"user_id": userID}
+	update := bson.M{"$inc": bson.M{"vulnerability_pattern." + domain.VulnerabilityHour(at): weight}}
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"vulnerability_pattern": 1})
+
+	var tracker domain.UserTracker
+	if err := r.trackers.FindOneAndUpdate(ctx, filter, update, opts).Decode(&tracker); err != nil {
+		return nil, err
+	}
+	return tracker.VulnerabilityPattern, nil
 }
+
+// RecordCopingUse counts a use of a coping strategy, and whether it helped
+func (r *AnalyticsRepository) RecordCopingUse(ctx context.Context, userID, strategyID string, helped bool, at time.Time) error {
+	field := "coping_stats." + strategyID
+	inc := bson.M{field + ".uses": 1}
+	if helped {
+		inc[field+".helped"] = 1
+	}
+
+	filter := bson.M{"user_id": userID}
+	update := bson.M{"$inc": inc, "$set": bson.M{field + ".last_used_at": at}}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.trackers.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+// SetRiskWindows replaces the user's risk windows
+func (r *AnalyticsRepository) SetRiskWindows(ctx context.Context, userID string, windows []domain.RiskWindow) error {
+	filter := bson.M{"user_id": userID}
+	update := bson.M{"$set": bson.M{"risk_windows": windows}}
+	_, err := r.trackers.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// ClaimRiskNudge marks as nudged one user with a risk window starting at
+// startHour (UTC) who was last nudged before nudgedBefore, returning their
+// tracker, or nil when there are none left
+func (r *AnalyticsRepository) ClaimRiskNudge(ctx context.Context, startHour int, nudgedBefore time.Time) (*domain.UserTracker, error) {
+	filter := bson.M{
+		"risk_windows.start_hour": startHour,
+		"$or": []bson.M{
+			{"last_risk_nudge_at": bson.M{"$exists": false}},
+			{"last_risk_nudge_at": bson.M{"$lt": nudgedBefore}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"last_risk_nudge_at": time.Now()}}
+
+	var tracker domain.UserTracker
+	err := r.trackers.FindOneAndUpdate(ctx, filter, update).Decode(&tracker)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracker, nil
+}
+
+func (r *AnalyticsRepository) AddMilestone(ctx context.Context, userID uuid.UUID, milestone *domain.Milestone) error {
+	filter := bson.M{"user_id": userID.String()}
+	update := bson.M{"$push": bson.M{"milestones": milestone}}
+	_, err := r.trackers.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// UnlockAchievement records an achievement unless the user already has it,
+// reporting whether this call unlocked it
+func (r *AnalyticsRepository) UnlockAchievement(ctx context.Context, userID string, achievement domain.UnlockedAchievement) (bool, error) {
+	filter := bson.M{"user_id": userID, "achievements.id": bson.M{"$ne": achievement.ID}}
+	update := bson.M{"$push": bson.M{"achievements": achievement}}
+
+	result, err := r.trackers.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
 func (r *AnalyticsRepository) CreateUserTracker(ctx context.Context, userID uuid.UUID) error {
 	tracker := &domain.UserTracker{
 		UserID:               userID.String(),
@@ -117,3 +228,143 @@ func (r *AnalyticsRepository) CreateUserTracker(ctx context.Context, userID uuid
 	}
 	return r.UpsertTracker(ctx, tracker)
 }
+
+// digestDueFilter matches trackers that have never had a digest or whose last one was sent before sentBefore
+func digestDueFilter(sentBefore time.Time) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"last_digest": bson.M{"$exists": false}},
+		{"last_digest.sent_at": bson.M{"$lt": sentBefore}},
+	}}
+}
+
+// ListTrackersDueForDigest returns up to limit trackers whose last weekly digest was sent before sentBefore
+func (r *AnalyticsRepository) ListTrackersDueForDigest(ctx context.Context, sentBefore time.Time, limit int) ([]*domain.UserTracker, error) {
+	opts := options.Find().SetLimit(int64(limit))
+	cursor, err := r.trackers.Find(ctx, digestDueFilter(sentBefore), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var trackers []*domain.UserTracker
+	if err := cursor.All(ctx, &trackers); err != nil {
+		return nil, err
+	}
+	return trackers, nil
+}
+
+// ClaimDigest records snapshot as the user's last digest if it is still due,
+// reporting whether this caller claimed it. Concurrent workers racing on the
+// same user see false.
+func (r *AnalyticsRepository) ClaimDigest(ctx context.Context, userID string, sentBefore time.Time, snapshot *domain.DigestSnapshot) (bool, error) {
+	filter := digestDueFilter(sentBefore)
+	filter["user_id"] = userID
+
+	result, err := r.trackers.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"last_digest": snapshot}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// RecordMood stores the user's mood for entry.Day, replacing any earlier entry that day
+func (r *AnalyticsRepository) RecordMood(ctx context.Context, entry *domain.MoodEntry) error {
+	filter := bson.M{"user_id": entry.UserID, "day": entry.Day}
+	update := bson.M{"$set": bson.M{"score": entry.Score, "recorded_at": entry.RecordedAt}}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.moods.UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+// GetMoodHistory returns the user's mood entries from since onwards, oldest first
+func (r *AnalyticsRepository) GetMoodHistory(ctx context.Context, userID string, since time.Time) ([]*domain.MoodEntry, error) {
+	filter := bson.M{"user_id": userID, "day": bson.M{"$gte": since}}
+	opts := options.Find().SetSort(bson.D{{Key: "day", Value: 1}})
+
+	cursor, err := r.moods.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*domain.MoodEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RecordDailyCheckIn marks the user as checked in on day, noting a relapse if
+// they had one; answers, when given, replace any from an earlier check-in that day
+func (r *AnalyticsRepository) RecordDailyCheckIn(ctx context.Context, userID string, day time.Time, hadRelapse bool, answers []domain.CheckInAnswer) error {
+	set := bson.M{"checked_in": true, "updated_at": time.Now()}
+	if hadRelapse {
+		set["had_relapse"] = true
+	}
+	if len(answers) > 0 {
+		set["answers"] = answers
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.checkIns.UpdateOne(ctx, bson.M{"user_id": userID, "day": day}, bson.M{"$set": set}, opts)
+	return err
+}
+
+// IncrementDailyCravings counts a craving against the user's day
+func (r *AnalyticsRepository) IncrementDailyCravings(ctx context.Context, userID string, day time.Time, resisted bool) error {
+	inc := bson.M{"cravings": 1}
+	if resisted {
+		inc["cravings_resisted"] = 1
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.checkIns.UpdateOne(ctx, bson.M{"user_id": userID, "day": day},
+		bson.M{"$inc": inc, "$set": bson.M{"updated_at": time.Now()}}, opts)
+	return err
+}
+
+// GetDailyCheckIns returns the user's daily check-ins from since onwards, oldest first
+func (r *AnalyticsRepository) GetDailyCheckIns(ctx context.Context, userID string, since time.Time) ([]*domain.DailyCheckIn, error) {
+	filter := bson.M{"user_id": userID, "day": bson.M{"$gte": since}}
+	opts := options.Find().SetSort(bson.D{{Key: "day", Value: 1}})
+
+	cursor, err := r.checkIns.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	checkIns := []*domain.DailyCheckIn{}
+	if err := cursor.All(ctx, &checkIns); err != nil {
+		return nil, err
+	}
+	return checkIns, nil
+}
+
+func (r *AnalyticsRepository) CreateRelapse(ctx context.Context, relapse *domain.RelapseRecord) error {
+	if relapse.ID.IsZero() {
+		relapse.ID = primitive.NewObjectID()
+	}
+	_, err := r.relapses.InsertOne(ctx, relapse)
+	return err
+}
+
+// ListRelapses returns the user's most recent relapses, newest first
+func (r *AnalyticsRepository) ListRelapses(ctx context.Context, userID string, limit int) ([]*domain.RelapseRecord, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "occurred_at", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.relapses.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	relapses := []*domain.RelapseRecord{}
+	if err := cursor.All(ctx, &relapses); err != nil {
+		return nil, err
+	}
+	return relapses, nil
+}
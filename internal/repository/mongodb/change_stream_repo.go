@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Compile-time check to ensure ChangeStreamRepository implements repository.ChangeStreamRepository
+var _ repository.ChangeStreamRepository = (*ChangeStreamRepository)(nil)
+
+// ChangeStreamRepository watches the posts and support_responses collections.
+// Change streams need MongoDB to run as a replica set.
+type ChangeStreamRepository struct {
+	posts     *Collection
+	responses *Collection
+
+	// Where each watch resumes after the stream fails; each collection is
+	// watched by a single goroutine
+	postsResumeToken     bson.Raw
+	responsesResumeToken bson.Raw
+}
+
+func NewChangeStreamRepository(db *DB) *ChangeStreamRepository {
+	return &ChangeStreamRepository{
+		posts:     db.Collection("posts"),
+		responses: db.Collection("support_responses"),
+	}
+}
+
+func (r *ChangeStreamRepository) WatchPosts(ctx context.Context, handler func(*domain.Post)) error {
+	match := bson.M{
+		"operationType":                 "insert",
+		"fullDocument.moderation_state": domain.ModerationStateVisible,
+	}
+	return watchInserts(ctx, r.posts, match, &r.postsResumeToken, handler)
+}
+
+func (r *ChangeStreamRepository) WatchResponses(ctx context.Context, handler func(*domain.SupportResponse)) error {
+	return watchInserts(ctx, r.responses, bson.M{"operationType": "insert"}, &r.responsesResumeToken, handler)
+}
+
+// watchInserts calls handler with each change matching match, resuming after
+// *resumeToken when set and advancing it as changes are handled
+func watchInserts[T any](ctx context.Context, collection *Collection, match bson.M, resumeToken *bson.Raw, handler func(*T)) error {
+	opts := options.ChangeStream()
+	if *resumeToken != nil {
+		opts.SetResumeAfter(*resumeToken)
+	}
+
+	stream, err := collection.Watch(ctx, mongo.Pipeline{{{Key: "$match", Value: match}}}, opts)
+	if err != nil {
+		// The token may have fallen off the oplog; start from now next time
+		*resumeToken = nil
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var change struct {
+			FullDocument T `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&change); err == nil {
+			handler(&change.FullDocument)
+		}
+		*resumeToken = stream.ResumeToken()
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return stream.Err()
+}
@@ -0,0 +1,144 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yourorg/anonymous-support/internal/pkg/retry"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// DB is the MongoDB database the repositories share. Calls on its
+// collections go through one circuit breaker, so while MongoDB is down they
+// fail fast with a retry.OpenError instead of waiting on server selection.
+type DB struct {
+	*mongo.Database
+	breaker *retry.CircuitBreaker
+}
+
+// NewDB wraps db with the MongoDB circuit breaker
+func NewDB(db *mongo.Database, logger *zap.Logger) *DB {
+	return &DB{
+		Database: db,
+		breaker:  repository.NewBreaker("mongodb", isConnectionFailure, logger),
+	}
+}
+
+// Collection returns the named collection, guarded by the database's breaker
+func (d *DB) Collection(name string) *Collection {
+	return &Collection{Collection: d.Database.Collection(name), breaker: d.breaker}
+}
+
+// Collection is a mongo.Collection whose reads and writes go through the
+// breaker. Change streams from Watch are long-lived and are not guarded.
+type Collection struct {
+	*mongo.Collection
+	breaker *retry.CircuitBreaker
+}
+
+func (c *Collection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	return guard(ctx, c.breaker, func(ctx context.Context) (*mongo.InsertOneResult, error) {
+		return c.Collection.InsertOne(ctx, document, opts...)
+	})
+}
+
+func (c *Collection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return guard(ctx, c.breaker, func(ctx context.Context) (*mongo.Cursor, error) {
+		return c.Collection.Find(ctx, filter, opts...)
+	})
+}
+
+func (c *Collection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	return guard(ctx, c.breaker, func(ctx context.Context) (*mongo.Cursor, error) {
+		return c.Collection.Aggregate(ctx, pipeline, opts...)
+	})
+}
+
+func (c *Collection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	return guard(ctx, c.breaker, func(ctx context.Context) (int64, error) {
+		return c.Collection.CountDocuments(ctx, filter, opts...)
+	})
+}
+
+func (c *Collection) UpdateOne(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return guard(ctx, c.breaker, func(ctx context.Context) (*mongo.UpdateResult, error) {
+		return c.Collection.UpdateOne(ctx, filter, update, opts...)
+	})
+}
+
+func (c *Collection) UpdateMany(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return guard(ctx, c.breaker, func(ctx context.Context) (*mongo.UpdateResult, error) {
+		return c.Collection.UpdateMany(ctx, filter, update, opts...)
+	})
+}
+
+func (c *Collection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return guard(ctx, c.breaker, func(ctx context.Context) (*mongo.DeleteResult, error) {
+		return c.Collection.DeleteOne(ctx, filter, opts...)
+	})
+}
+
+func (c *Collection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return guard(ctx, c.breaker, func(ctx context.Context) (*mongo.DeleteResult, error) {
+		return c.Collection.DeleteMany(ctx, filter, opts...)
+	})
+}
+
+func (c *Collection) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	return guard(ctx, c.breaker, func(ctx context.Context) (*mongo.BulkWriteResult, error) {
+		return c.Collection.BulkWrite(ctx, models, opts...)
+	})
+}
+
+func (c *Collection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	return c.guardSingle(func() *mongo.SingleResult {
+		return c.Collection.FindOne(ctx, filter, opts...)
+	})
+}
+
+func (c *Collection) FindOneAndUpdate(ctx context.Context, filter, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	return c.guardSingle(func() *mongo.SingleResult {
+		return c.Collection.FindOneAndUpdate(ctx, filter, update, opts...)
+	})
+}
+
+func (c *Collection) FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult {
+	return c.guardSingle(func() *mongo.SingleResult {
+		return c.Collection.FindOneAndDelete(ctx, filter, opts...)
+	})
+}
+
+// guardSingle runs a single-document call through the breaker. A rejected
+// call returns a result whose Err and Decode report the rejection.
+func (c *Collection) guardSingle(call func() *mongo.SingleResult) *mongo.SingleResult {
+	if err := c.breaker.Allow(); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	result := call()
+	c.breaker.Record(result.Err())
+	return result
+}
+
+// guard runs call through breaker and returns its result
+func guard[T any](ctx context.Context, breaker *retry.CircuitBreaker, call func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := breaker.Execute(ctx, func(ctx context.Context) (err error) {
+		result, err = call(ctx)
+		return err
+	})
+	return result, err
+}
+
+// isConnectionFailure reports whether err means MongoDB couldn't be reached
+// or didn't answer in time, rather than answering with no documents or an error
+func isConnectionFailure(err error) bool {
+	if mongo.IsNetworkError(err) || mongo.IsTimeout(err) {
+		return true
+	}
+	var serverErr mongo.ServerError
+	return !errors.Is(err, mongo.ErrNoDocuments) && !errors.As(err, &serverErr)
+}
@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Compile-time check to ensure JournalRepository implements repository.JournalRepository
+var _ repository.JournalRepository = (*JournalRepository)(nil)
+
+type JournalRepository struct {
+	entries *Collection
+}
+
+func NewJournalRepository(db *DB) *JournalRepository {
+	return &JournalRepository{
+		entries: db.Collection("journal_entries"),
+	}
+}
+
+func (r *JournalRepository) Create(ctx context.Context, entry *domain.JournalEntry) error {
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+
+	_, err := r.entries.InsertOne(ctx, entry)
+	return err
+}
+
+// ListByUser returns a user's journal entries, newest first
+func (r *JournalRepository) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*domain.JournalEntry, error) {
+	return r.find(ctx, bson.M{"user_id": userID}, limit, offset)
+}
+
+// Search returns a user's journal entries containing every token, newest first
+func (r *JournalRepository) Search(ctx context.Context, userID string, tokens []string, limit, offset int) ([]*domain.JournalEntry, error) {
+	return r.find(ctx, bson.M{"user_id": userID, "search_tokens": bson.M{"$all": tokens}}, limit, offset)
+}
+
+func (r *JournalRepository) find(ctx context.Context, filter bson.M, limit, offset int) ([]*domain.JournalEntry, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset)).
+		SetProjection(bson.M{"search_tokens": 0})
+
+	cursor, err := r.entries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*domain.JournalEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
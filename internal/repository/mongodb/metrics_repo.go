@@ -0,0 +1,246 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Compile-time check to ensure MetricsRepository implements repository.MetricsRepository
+var _ repository.MetricsRepository = (*MetricsRepository)(nil)
+
+// MetricsRepository aggregates platform-wide activity for admin analytics
+type MetricsRepository struct {
+	posts     *Collection
+	responses *Collection
+	trackers  *Collection
+}
+
+func NewMetricsRepository(db *DB) *MetricsRepository {
+	return &MetricsRepository{
+		posts:     db.Collection("posts"),
+		responses: db.Collection("support_responses"),
+		trackers:  db.Collection("user_trackers"),
+	}
+}
+
+// activityPipeline yields one {user_id, at} document per post, response and
+// check-in from since onwards; a user is active when any of these happen
+func activityPipeline(since time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": since}}}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "user_id": 1, "at": "$created_at"}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": "support_responses",
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"created_at": bson.M{"$gte": since}}},
+				bson.M{"$project": bson.M{"_id": 0, "user_id": 1, "at": "$created_at"}},
+			},
+		}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": "daily_checkins",
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"checked_in": true, "updated_at": bson.M{"$gte": since}}},
+				bson.M{"$project": bson.M{"_id": 0, "user_id": 1, "at": "$updated_at"}},
+			},
+		}}},
+	}
+}
+
+// CountActiveUsers counts the distinct users who posted, responded or checked in from since onwards
+func (r *MetricsRepository) CountActiveUsers(ctx context.Context, since time.Time) (int, error) {
+	pipeline := append(activityPipeline(since),
+		bson.D{{Key: "$group", Value: bson.M{"_id": "$user_id"}}},
+		bson.D{{Key: "$count", Value: "users"}},
+	)
+
+	cursor, err := r.posts.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Users int `bson:"users"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Users, nil
+}
+
+// ActiveUsersByWeek returns the distinct users active in each week (starting
+// Monday, UTC) from since onwards
+func (r *MetricsRepository) ActiveUsersByWeek(ctx context.Context, since time.Time) (map[time.Time][]string, error) {
+	pipeline := append(activityPipeline(since),
+		bson.D{{Key: "$group", Value: bson.M{"_id": bson.M{
+			"week": bson.M{"$dateTrunc": bson.M{"date": "$at", "unit": "week", "startOfWeek": "monday"}},
+			"user": "$user_id",
+		}}}},
+		bson.D{{Key: "$group", Value: bson.M{"_id": "$_id.week", "users": bson.M{"$push": "$_id.user"}}}},
+	)
+
+	cursor, err := r.posts.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Week  time.Time `bson:"_id"`
+		Users []string  `bson:"users"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	weeks := make(map[time.Time][]string, len(rows))
+	for _, row := range rows {
+		weeks[row.Week.UTC()] = row.Users
+	}
+	return weeks, nil
+}
+
+// ContentVolumePerDay counts posts and responses created on each UTC day from since onwards, oldest first
+func (r *MetricsRepository) ContentVolumePerDay(ctx context.Context, since time.Time) ([]*domain.ContentVolume, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": since}}}},
+		{{Key: "$project", Value: bson.M{"at": "$created_at", "posts": bson.M{"$literal": 1}, "responses": bson.M{"$literal": 0}}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": "support_responses",
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"created_at": bson.M{"$gte": since}}},
+				bson.M{"$project": bson.M{"at": "$created_at", "posts": bson.M{"$literal": 0}, "responses": bson.M{"$literal": 1}}},
+			},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       bson.M{"$dateTrunc": bson.M{"date": "$at", "unit": "day"}},
+			"posts":     bson.M{"$sum": "$posts"},
+			"responses": bson.M{"$sum": "$responses"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := r.posts.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Day       time.Time `bson:"_id"`
+		Posts     int       `bson:"posts"`
+		Responses int       `bson:"responses"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	volumes := make([]*domain.ContentVolume, len(rows))
+	for i, row := range rows {
+		volumes[i] = &domain.ContentVolume{Day: row.Day.UTC(), Posts: row.Posts, Responses: row.Responses}
+	}
+	return volumes, nil
+}
+
+// SumDaysClean totals clean days across all users, along with how many users have any
+func (r *MetricsRepository) SumDaysClean(ctx context.Context) (int64, int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"total_days_clean": bson.M{"$gt": 0}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"days":  bson.M{"$sum": "$total_days_clean"},
+			"users": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.trackers.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Days  int64 `bson:"days"`
+		Users int   `bson:"users"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return 0, 0, err
+	}
+	if len(rows) == 0 {
+		return 0, 0, nil
+	}
+	return rows[0].Days, rows[0].Users, nil
+}
+
+// CountSupportGiven counts responses given from since onwards, along with how many distinct users gave them
+func (r *MetricsRepository) CountSupportGiven(ctx context.Context, since time.Time) (int, int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$user_id", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       nil,
+			"responses": bson.M{"$sum": "$count"},
+			"users":     bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.responses.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Responses int `bson:"responses"`
+		Users     int `bson:"users"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return 0, 0, err
+	}
+	if len(rows) == 0 {
+		return 0, 0, nil
+	}
+	return rows[0].Responses, rows[0].Users, nil
+}
+
+// ListSOSResponses returns each SOS post created from since onwards with the
+// time of its first response
+func (r *MetricsRepository) ListSOSResponses(ctx context.Context, since time.Time) ([]*domain.SOSResponse, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"type": domain.PostTypeSOS, "created_at": bson.M{"$gte": since}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "support_responses",
+			"let":  bson.M{"post_id": bson.M{"$toString": "$_id"}},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$post_id", "$$post_id"}}}},
+				bson.M{"$group": bson.M{"_id": nil, "first": bson.M{"$min": "$created_at"}}},
+			},
+			"as": "responses",
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":               bson.M{"$toString": "$_id"},
+			"created_at":        1,
+			"first_response_at": bson.M{"$first": "$responses.first"},
+		}}},
+	}
+
+	cursor, err := r.posts.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var responses []*domain.SOSResponse
+	if err := cursor.All(ctx, &responses); err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
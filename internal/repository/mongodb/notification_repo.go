@@ -0,0 +1,95 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Compile-time check to ensure NotificationRepository implements repository.NotificationRepository
+var _ repository.NotificationRepository = (*NotificationRepository)(nil)
+
+type NotificationRepository struct {
+	notifications *Collection
+}
+
+func NewNotificationRepository(db *DB) *NotificationRepository {
+	return &NotificationRepository{
+		notifications: db.Collection("notifications"),
+	}
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	notification.ID = primitive.NewObjectID()
+	notification.CreatedAt = time.Now()
+
+	_, err := r.notifications.InsertOne(ctx, notification)
+	return err
+}
+
+// ListByUser returns a user's notifications, newest first
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]*domain.Notification, error) {
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read_at"] = bson.M{"$exists": false}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+
+	cursor, err := r.notifications.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []*domain.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// MarkRead marks the given notifications as read. IDs belonging to other users are ignored.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID string, ids []string) (int64, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return 0, err
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	filter := bson.M{
+		"_id":     bson.M{"$in": objectIDs},
+		"user_id": userID,
+		"read_at": bson.M{"$exists": false},
+	}
+	result, err := r.notifications.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"read_at": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	filter := bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}}
+	result, err := r.notifications.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"read_at": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return r.notifications.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}})
+}
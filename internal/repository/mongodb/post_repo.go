@@ -17,10 +17,10 @@ import (
 var _ repository.PostRepository = (*PostRepository)(nil)
 
 type PostRepository struct {
-	collection *mongo.Collection
+	collection *Collection
 }
 
-func NewPostRepository(db *mongo.Database) *PostRepository {
+func NewPostRepository(db *DB) *PostRepository {
 	return &PostRepository{
 		collection: db.Collection("posts"),
 	}
@@ -55,18 +55,62 @@ func (r *PostRepository) GetByID(ctx context.Context, id string) (*domain.Post,
 	return &post, err
 }
 
-func (r *PostRepository) GetFeed(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) ([]*domain.Post, error) {
-	filter := bson.M{"is_moderated": false}
+func (r *PostRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Post, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
 
-	if len(categories) > 0 {
-		filter["categories"] = bson.M{"$in": categories}
+	posts := []*domain.Post{}
+	if len(objectIDs) == 0 {
+		return posts, nil
 	}
 
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// feedScope matches the visible posts of a circle's feed, or of the public feed
+func feedScope(circleID *string) bson.M {
+	filter := bson.M{"moderation_state": domain.ModerationStateVisible}
 	if circleID != nil {
 		filter["circle_id"] = *circleID
 	} else {
 		filter["visibility"] = "public"
 	}
+	return filter
+}
+
+func categoryFilter(categories []string) bson.M {
+	if len(categories) == 0 {
+		return bson.M{}
+	}
+	return bson.M{"categories": bson.M{"$in": categories}}
+}
+
+func typeFilter(postType *domain.PostType) bson.M {
+	if postType == nil {
+		return bson.M{}
+	}
+	return bson.M{"type": *postType}
+}
+
+func (r *PostRepository) GetFeed(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) ([]*domain.Post, error) {
+	filter := feedScope(circleID)
+
+	if len(categories) > 0 {
+		filter["categories"] = bson.M{"$in": categories}
+	}
 
 	if postType != nil {
 		filter["type"] = *postType
@@ -91,6 +135,116 @@ func (r *PostRepository) GetFeed(ctx context.Context, categories []string, circl
 	return posts, nil
 }
 
+// GetFeedPage returns a feed page along with its total count and per-category
+// and per-type counts, in a single aggregation
+func (r *PostRepository) GetFeedPage(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) (*domain.FeedPage, error) {
+	byCategory := categoryFilter(categories)
+	byType := typeFilter(postType)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: feedScope(circleID)}},
+		{{Key: "$facet", Value: bson.M{
+			"posts": bson.A{
+				bson.M{"$match": byCategory},
+				bson.M{"$match": byType},
+				bson.M{"$sort": bson.D{{Key: "created_at", Value: -1}}},
+				bson.M{"$skip": offset},
+				bson.M{"$limit": limit},
+			},
+			"total": bson.A{
+				bson.M{"$match": byCategory},
+				bson.M{"$match": byType},
+				bson.M{"$count": "count"},
+			},
+			"categories": bson.A{
+				bson.M{"$match": byType},
+				bson.M{"$unwind": "$categories"},
+				bson.M{"$group": bson.M{"_id": "$categories", "count": bson.M{"$sum": 1}}},
+			},
+			"types": bson.A{
+				bson.M{"$match": byCategory},
+				bson.M{"$group": bson.M{"_id": "$type", "count": bson.M{"$sum": 1}}},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	type bucket struct {
+		ID    string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	var rows []struct {
+		Posts      []*domain.Post `bson:"posts"`
+		Total      []bucket       `bson:"total"`
+		Categories []bucket       `bson:"categories"`
+		Types      []bucket       `bson:"types"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	page := &domain.FeedPage{
+		Posts:          []*domain.Post{},
+		CategoryCounts: map[string]int64{},
+		TypeCounts:     map[domain.PostType]int64{},
+	}
+	if len(rows) == 0 {
+		return page, nil
+	}
+
+	row := rows[0]
+	if row.Posts != nil {
+		page.Posts = row.Posts
+	}
+	if len(row.Total) > 0 {
+		page.TotalCount = row.Total[0].Count
+	}
+	for _, b := range row.Categories {
+		page.CategoryCounts[b.ID] = b.Count
+	}
+	for _, b := range row.Types {
+		page.TypeCounts[domain.PostType(b.ID)] = b.Count
+	}
+	return page, nil
+}
+
+// GetFeedAfter pages through a feed by cursor. Posts created at the same
+// time are ordered by ID, so pages neither skip nor repeat them.
+func (r *PostRepository) GetFeedAfter(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, afterCreatedAt time.Time, afterID string, limit int) ([]*domain.Post, error) {
+	filter := bson.M{"$and": bson.A{feedScope(circleID), categoryFilter(categories), typeFilter(postType)}}
+	if afterID != "" {
+		objectID, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": afterCreatedAt}},
+			bson.M{"created_at": afterCreatedAt, "_id": bson.M{"$lt": objectID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	posts := []*domain.Post{}
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
 func (r *PostRepository) Delete(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -124,40 +278,68 @@ func (r *PostRepository) UpdateUrgency(ctx context.Context, id string, urgencyLe
 	return nil
 }
 
-func (r *PostRepository) IncrementResponseCount(ctx context.Context, id string) error {
-	objectID, err := primitive.ObjectIDFromHex(id)
+// AddResponseCounts adds buffered response counts to posts in one bulk write
+func (r *PostRepository) AddResponseCounts(ctx context.Context, increments map[string]int64) error {
+	return r.addCounts(ctx, "response_count", increments)
+}
+
+// AddSupportCounts adds buffered support counts to posts in one bulk write
+func (r *PostRepository) AddSupportCounts(ctx context.Context, increments map[string]int64) error {
+	return r.addCounts(ctx, "support_count", increments)
+}
+
+// AddReactionCounts adjusts a post's per-type reaction counts
+func (r *PostRepository) AddReactionCounts(ctx context.Context, postID string, increments map[domain.ReactionType]int64) error {
+	objectID, err := primitive.ObjectIDFromHex(postID)
 	if err != nil {
 		return fmt.Errorf("invalid post ID")
 	}
 
-	update := bson.M{"$inc": bson.M{"response_count": 1}}
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	inc := bson.M{}
+	for reaction, delta := range increments {
+		if delta != 0 {
+			inc["reaction_counts."+string(reaction)] = delta
+		}
+	}
+	if len(inc) == 0 {
+		return nil
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$inc": inc})
 	return err
 }
 
-func (r *PostRepository) IncrementSupportCount(ctx context.Context, id string) error {
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return fmt.Errorf("invalid post ID")
+// addCounts increments field on each post. Invalid IDs are skipped so they
+// can't block the rest of the batch from ever being written.
+func (r *PostRepository) addCounts(ctx context.Context, field string, increments map[string]int64) error {
+	models := make([]mongo.WriteModel, 0, len(increments))
+	for id, delta := range increments {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": objectID}).
+			SetUpdate(bson.M{"$inc": bson.M{field: delta}}))
+	}
+	if len(models) == 0 {
+		return nil
 	}
 
-	update := bson.M{"$inc": bson.M{"support_count": 1}}
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	_, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
 	return err
 }
 
-func (r *PostRepository) FlagForModeration(ctx context.Context, id string, flags []string) error {
+func (r *PostRepository) SetModerationState(ctx context.Context, id string, state domain.ModerationState, flags []string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return fmt.Errorf("invalid post ID")
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"is_moderated":     true,
-			"moderation_flags": flags,
-		},
+	set := bson.M{"moderation_state": state}
+	if flags != nil {
+		set["moderation_flags"] = flags
 	}
+	update := bson.M{"$set": set}
 	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
 	return err
 }
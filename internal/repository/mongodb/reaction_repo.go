@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Compile-time check to ensure ReactionRepository implements repository.ReactionRepository
+var _ repository.ReactionRepository = (*ReactionRepository)(nil)
+
+type ReactionRepository struct {
+	reactions *Collection
+}
+
+func NewReactionRepository(db *DB) *ReactionRepository {
+	return &ReactionRepository{
+		reactions: db.Collection("post_reactions"),
+	}
+}
+
+func (r *ReactionRepository) Set(ctx context.Context, reaction *domain.PostReaction) (domain.ReactionType, error) {
+	reaction.CreatedAt = time.Now()
+
+	var previous domain.PostReaction
+	err := r.reactions.FindOneAndUpdate(ctx,
+		bson.M{"post_id": reaction.PostID, "user_id": reaction.UserID},
+		bson.M{"$set": bson.M{"type": reaction.Type, "created_at": reaction.CreatedAt}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	return previous.Type, err
+}
+
+func (r *ReactionRepository) Delete(ctx context.Context, postID, userID string) (domain.ReactionType, error) {
+	var removed domain.PostReaction
+	err := r.reactions.FindOneAndDelete(ctx, bson.M{"post_id": postID, "user_id": userID}).Decode(&removed)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	return removed.Type, err
+}
+
+func (r *ReactionRepository) GetByUser(ctx context.Context, userID string, postIDs []string) (map[string]domain.ReactionType, error) {
+	reactions := make(map[string]domain.ReactionType)
+	if len(postIDs) == 0 {
+		return reactions, nil
+	}
+
+	cursor, err := r.reactions.Find(ctx, bson.M{"user_id": userID, "post_id": bson.M{"$in": postIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []domain.PostReaction
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		reactions[row.PostID] = row.Type
+	}
+	return reactions, nil
+}
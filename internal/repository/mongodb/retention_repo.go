@@ -0,0 +1,154 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Compile-time check to ensure RetentionRepository implements repository.RetentionRepository
+var _ repository.RetentionRepository = (*RetentionRepository)(nil)
+
+// anonymizedUserPrefix marks user IDs replaced by retention. Each record gets
+// its own ID so per-user unique indexes still hold.
+const anonymizedUserPrefix = "anonymized:"
+
+// retentionCollection is a collection holding records of a data class, with
+// the field recording when each record was created
+type retentionCollection struct {
+	collection *Collection
+	timeField  string
+	hasAuthor  bool // Records also carry a username
+}
+
+// RetentionRepository enforces retention on posts, responses and analytics events
+type RetentionRepository struct {
+	classes map[domain.DataClass][]retentionCollection
+}
+
+func NewRetentionRepository(db *DB) *RetentionRepository {
+	return &RetentionRepository{
+		classes: map[domain.DataClass][]retentionCollection{
+			domain.DataClassPosts: {
+				{collection: db.Collection("posts"), timeField: "created_at", hasAuthor: true},
+			},
+			domain.DataClassResponses: {
+				{collection: db.Collection("support_responses"), timeField: "created_at", hasAuthor: true},
+			},
+			domain.DataClassAnalyticsEvents: {
+				{collection: db.Collection("mood_entries"), timeField: "recorded_at"},
+				{collection: db.Collection("relapse_events"), timeField: "occurred_at"},
+			},
+		},
+	}
+}
+
+func (r *RetentionRepository) CountExpired(ctx context.Context, class domain.DataClass, action domain.RetentionAction, cutoff time.Time) (int64, error) {
+	collections, err := r.collections(class)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, c := range collections {
+		count, err := c.collection.CountDocuments(ctx, c.filter(action, cutoff))
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+func (r *RetentionRepository) DeleteExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error) {
+	return r.apply(ctx, class, domain.RetentionActionDelete, cutoff, limit, func(c retentionCollection, filter bson.M) (int64, error) {
+		result, err := c.collection.DeleteMany(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		return result.DeletedCount, nil
+	})
+}
+
+// AnonymizeExpired replaces the user ID, and username where there is one,
+// of expired records
+func (r *RetentionRepository) AnonymizeExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error) {
+	return r.apply(ctx, class, domain.RetentionActionAnonymize, cutoff, limit, func(c retentionCollection, filter bson.M) (int64, error) {
+		set := bson.M{"user_id": bson.M{"$concat": bson.A{anonymizedUserPrefix, bson.M{"$toString": "$_id"}}}}
+		if c.hasAuthor {
+			set["username"] = ""
+		}
+		result, err := c.collection.UpdateMany(ctx, filter, mongo.Pipeline{{{Key: "$set", Value: set}}})
+		if err != nil {
+			return 0, err
+		}
+		return result.ModifiedCount, nil
+	})
+}
+
+// apply runs change on up to limit expired records across the class's collections
+func (r *RetentionRepository) apply(ctx context.Context, class domain.DataClass, action domain.RetentionAction, cutoff time.Time, limit int, change func(retentionCollection, bson.M) (int64, error)) (int64, error) {
+	collections, err := r.collections(class)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, c := range collections {
+		remaining := int64(limit) - total
+		if remaining <= 0 {
+			break
+		}
+
+		// Select a batch by ID so a single update never spans the whole collection
+		opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(remaining)
+		cursor, err := c.collection.Find(ctx, c.filter(action, cutoff), opts)
+		if err != nil {
+			return total, err
+		}
+		var docs []struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return total, err
+		}
+		if len(docs) == 0 {
+			continue
+		}
+
+		ids := make(bson.A, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.ID
+		}
+		changed, err := change(c, bson.M{"_id": bson.M{"$in": ids}})
+		total += changed
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (r *RetentionRepository) collections(class domain.DataClass) ([]retentionCollection, error) {
+	collections, ok := r.classes[class]
+	if !ok {
+		return nil, fmt.Errorf("retention is not supported for %s in MongoDB", class)
+	}
+	return collections, nil
+}
+
+// filter matches expired records the action would still change
+func (c retentionCollection) filter(action domain.RetentionAction, cutoff time.Time) bson.M {
+	filter := bson.M{c.timeField: bson.M{"$lt": cutoff}}
+	if action == domain.RetentionActionAnonymize {
+		filter["user_id"] = bson.M{"$not": primitive.Regex{Pattern: "^" + anonymizedUserPrefix}}
+	}
+	return filter
+}
@@ -17,10 +17,10 @@ import (
 var _ repository.SupportRepository = (*SupportRepository)(nil)
 
 type SupportRepository struct {
-	responses *mongo.Collection
+	responses *Collection
 }
 
-func NewSupportRepository(db *mongo.Database) *SupportRepository {
+func NewSupportRepository(db *DB) *SupportRepository {
 	return &SupportRepository{
 		responses: db.Collection("support_responses"),
 	}
@@ -89,6 +89,41 @@ func (r *SupportRepository) GetUserStats(ctx context.Context, userID string) (gi
 	return given, 0, nil
 }
 
+// CountGivenPerDay counts the responses the user has given on each UTC day from since onwards
+func (r *SupportRepository) CountGivenPerDay(ctx context.Context, userID string, since time.Time) (map[time.Time]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID, "created_at": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$created_at"}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.responses.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Day   string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[time.Time]int, len(rows))
+	for _, row := range rows {
+		day, err := time.Parse("2006-01-02", row.Day)
+		if err != nil {
+			return nil, err
+		}
+		counts[day] = row.Count
+	}
+	return counts, nil
+}
+
 func (r *SupportRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.SupportResponse, error) {
 	return []*domain.SupportResponse{}, nil
 }
@@ -3,9 +3,9 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
@@ -14,10 +14,10 @@ import (
 var _ repository.AuditRepository = (*AuditRepository)(nil)
 
 type AuditRepository struct {
-	db *sqlx.DB
+	db *DB
 }
 
-func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+func NewAuditRepository(db *DB) *AuditRepository {
 	return &AuditRepository{db: db}
 }
 
@@ -28,9 +28,22 @@ func (r *AuditRepository) CreateAuditLog(ctx context.Context, log *domain.AuditL
 
 // GetAuditLogs implements repository.AuditRepository interface
 func (r *AuditRepository) GetAuditLogs(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]*domain.AuditLog, error) {
-	query := `SELECT * FROM audit_logs ORDER BY timestamp DESC LIMIT $1 OFFSET $2`
+	query := `SELECT * FROM audit_logs ORDER BY created_at DESC LIMIT $1 OFFSET $2`
 	var logs []*domain.AuditLog
-	err := r.db.SelectContext(ctx, &logs, query, limit, offset)
+	err := r.db.SelectFromReplica(ctx, &logs, query, limit, offset)
+	return logs, err
+}
+
+// ListAuditLogsCreatedBetween implements repository.AuditRepository interface
+func (r *AuditRepository) ListAuditLogsCreatedBetween(ctx context.Context, since, until time.Time, limit, offset int) ([]*domain.AuditLog, error) {
+	logs := []*domain.AuditLog{}
+	query := `
+		SELECT * FROM audit_logs
+		WHERE created_at >= $1 AND created_at < $2
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3 OFFSET $4
+	`
+	err := r.db.SelectFromReplica(ctx, &logs, query, since, until, limit, offset)
 	return logs, err
 }
 
@@ -66,7 +79,7 @@ func (r *AuditRepository) Log(ctx context.Context, log *domain.AuditLog) error {
 func (r *AuditRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AuditLog, error) {
 	var log domain.AuditLog
 	query := `SELECT * FROM audit_logs WHERE id = $1`
-	err := r.db.GetContext(ctx, &log, query, id)
+	err := r.db.GetFromReplica(ctx, &log, query, id)
 	return &log, err
 }
 
@@ -79,7 +92,7 @@ func (r *AuditRepository) GetByActor(ctx context.Context, actorID uuid.UUID, lim
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	err := r.db.SelectContext(ctx, &logs, query, actorID, limit, offset)
+	err := r.db.SelectFromReplica(ctx, &logs, query, actorID, limit, offset)
 	return logs, err
 }
 
@@ -92,7 +105,7 @@ func (r *AuditRepository) GetByEventType(ctx context.Context, eventType domain.A
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	err := r.db.SelectContext(ctx, &logs, query, eventType, limit, offset)
+	err := r.db.SelectFromReplica(ctx, &logs, query, eventType, limit, offset)
 	return logs, err
 }
 
@@ -105,7 +118,7 @@ func (r *AuditRepository) GetFailedEvents(ctx context.Context, limit, offset int
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
-	err := r.db.SelectContext(ctx, &logs, query, limit, offset)
+	err := r.db.SelectFromReplica(ctx, &logs, query, limit, offset)
 	return logs, err
 }
 
@@ -118,7 +131,7 @@ func (r *AuditRepository) GetByTarget(ctx context.Context, targetID uuid.UUID, l
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	err := r.db.SelectContext(ctx, &logs, query, targetID, limit, offset)
+	err := r.db.SelectFromReplica(ctx, &logs, query, targetID, limit, offset)
 	return logs, err
 }
 
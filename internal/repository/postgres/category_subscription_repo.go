@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure CategorySubscriptionRepository implements repository.CategorySubscriptionRepository
+var _ repository.CategorySubscriptionRepository = (*CategorySubscriptionRepository)(nil)
+
+type CategorySubscriptionRepository struct {
+	db *DB
+}
+
+func NewCategorySubscriptionRepository(db *DB) *CategorySubscriptionRepository {
+	return &CategorySubscriptionRepository{db: db}
+}
+
+// ListByUser returns the categories a user is subscribed to
+func (r *CategorySubscriptionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	categories := []string{}
+	query := `SELECT category FROM category_subscriptions WHERE user_id = $1 ORDER BY category`
+	err := r.db.SelectContext(ctx, &categories, query, userID)
+	return categories, err
+}
+
+// ReplaceForUser replaces all of a user's subscriptions with categories
+func (r *CategorySubscriptionRepository) ReplaceForUser(ctx context.Context, userID uuid.UUID, categories []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM category_subscriptions WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO category_subscriptions (user_id, category)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, userID, pq.Array(categories)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SampleSubscribers returns up to limit distinct users subscribed to any of
+// the categories, in random order
+func (r *CategorySubscriptionRepository) SampleSubscribers(ctx context.Context, categories []string, excludeUserID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	userIDs := []uuid.UUID{}
+	query := `
+		SELECT user_id FROM (
+			SELECT DISTINCT user_id FROM category_subscriptions
+			WHERE category = ANY($1) AND user_id <> $2
+		) subscribers
+		ORDER BY random()
+		LIMIT $3
+	`
+	err := r.db.SelectFromReplica(ctx, &userIDs, query, pq.Array(categories), excludeUserID, limit)
+	return userIDs, err
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
@@ -15,10 +14,10 @@ import (
 var _ repository.CircleRepository = (*CircleRepository)(nil)
 
 type CircleRepository struct {
-	db *sqlx.DB
+	db *DB
 }
 
-func NewCircleRepository(db *sqlx.DB) *CircleRepository {
+func NewCircleRepository(db *DB) *CircleRepository {
 	return &CircleRepository{db: db}
 }
 
@@ -36,8 +35,8 @@ func (r *CircleRepository) Create(ctx context.Context, circle *domain.Circle) er
 
 func (r *CircleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Circle, error) {
 	var circle domain.Circle
-	query := `SELECT * FROM circles WHERE id = $1`
-	err := r.db.GetContext(ctx, &circle, query, id)
+	query := `SELECT * FROM circles WHERE id = $1 AND deleted_at IS NULL`
+	err := r.db.GetFromReplica(ctx, &circle, query, id)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("circle not found")
 	}
@@ -50,14 +49,14 @@ func (r *CircleRepository) List(ctx context.Context, category *string, limit, of
 	var args []interface{}
 
 	if category != nil {
-		query = `SELECT * FROM circles WHERE category = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+		query = `SELECT * FROM circles WHERE category = $1 AND deleted_at IS NULL ORDER BY created_at DESC LIMIT $2 OFFSET $3`
 		args = []interface{}{*category, limit, offset}
 	} else {
-		query = `SELECT * FROM circles ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+		query = `SELECT * FROM circles WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2`
 		args = []interface{}{limit, offset}
 	}
 
-	err := r.db.SelectContext(ctx, &circles, query, args...)
+	err := r.db.SelectFromReplica(ctx, &circles, query, args...)
 	return circles, err
 }
 
@@ -115,10 +114,23 @@ func (r *CircleRepository) GetMembers(ctx context.Context, circleID uuid.UUID, l
 		ORDER BY joined_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	err := r.db.SelectContext(ctx, &members, query, circleID, limit, offset)
+	err := r.db.SelectFromReplica(ctx, &members, query, circleID, limit, offset)
 	return members, err
 }
 
+// GetUserCircleIDs returns the circles the user belongs to
+func (r *CircleRepository) GetUserCircleIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	circleIDs := []uuid.UUID{}
+	query := `
+		SELECT m.circle_id FROM circle_memberships m
+		JOIN circles c ON c.id = m.circle_id
+		WHERE m.user_id = $1 AND c.deleted_at IS NULL
+		ORDER BY m.joined_at
+	`
+	err := r.db.SelectFromReplica(ctx, &circleIDs, query, userID)
+	return circleIDs, err
+}
+
 func (r *CircleRepository) IsMember(ctx context.Context, circleID, userID uuid.UUID) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM circle_memberships WHERE circle_id = $1 AND user_id = $2)`
@@ -126,9 +138,65 @@ func (r *CircleRepository) IsMember(ctx context.Context, circleID, userID uuid.U
 	return exists, err
 }
 
+// GetMemberRole returns the user's role in the circle, or an empty role when
+// they are not a member or the circle is deleted. It reads the primary so
+// promotions apply at once.
+func (r *CircleRepository) GetMemberRole(ctx context.Context, circleID, userID uuid.UUID) (domain.CircleRole, error) {
+	var role domain.CircleRole
+	query := `
+		SELECT m.role FROM circle_memberships m
+		JOIN circles c ON c.id = m.circle_id
+		WHERE m.circle_id = $1 AND m.user_id = $2 AND c.deleted_at IS NULL
+	`
+	err := r.db.GetContext(ctx, &role, query, circleID, userID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
 func (r *CircleRepository) GetMemberCount(ctx context.Context, circleID uuid.UUID) (int, error) {
 	var count int
 	query := `SELECT COUNT(*) FROM circle_memberships WHERE circle_id = $1`
-	err := r.db.GetContext(ctx, &count, query, circleID)
+	err := r.db.GetFromReplica(ctx, &count, query, circleID)
+	return count, err
+}
+
+// Delete soft deletes the circle and returns the IDs of its members, whose
+// memberships are kept so the circle can be restored
+func (r *CircleRepository) Delete(ctx context.Context, circleID uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	deleteQuery := `UPDATE circles SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	result, err := tx.ExecContext(ctx, deleteQuery, circleID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("circle not found")
+	}
+
+	memberIDs := []uuid.UUID{}
+	membersQuery := `SELECT user_id FROM circle_memberships WHERE circle_id = $1`
+	if err := tx.SelectContext(ctx, &memberIDs, membersQuery, circleID); err != nil {
+		return nil, err
+	}
+
+	return memberIDs, tx.Commit()
+}
+
+// Count counts circles that have not been deleted
+func (r *CircleRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM circles WHERE deleted_at IS NULL`
+	err := r.db.GetFromReplica(ctx, &count, query)
 	return count, err
 }
@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"github.com/yourorg/anonymous-support/internal/pkg/retry"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+// replicaLagQuery reports how far a replica's replay is behind the primary.
+// A replica that has replayed everything it received counts as current even
+// if the primary has been idle since its last transaction.
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// DB routes queries between the primary and its read replicas. Writes and
+// ordinary queries run on the embedded primary; reads that tolerate a little
+// staleness use GetFromReplica and SelectFromReplica, which pick a healthy
+// replica within the lag limit and fall back to the primary. Queries on the
+// primary go through a circuit breaker, so while PostgreSQL is down they fail
+// fast with a retry.OpenError instead of waiting on the pool.
+type DB struct {
+	*sqlx.DB
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+	breaker  *retry.CircuitBreaker
+	logger   *zap.Logger
+}
+
+type replica struct {
+	db      *sqlx.DB
+	healthy atomic.Bool
+}
+
+// NewDB creates a router over primary and replicas. Replicas start healthy
+// and are checked by MonitorReplicas; maxLag is the replication lag above
+// which a replica stops serving reads.
+func NewDB(primary *sqlx.DB, replicas []*sqlx.DB, maxLag time.Duration, logger *zap.Logger) *DB {
+	db := &DB{
+		DB:      primary,
+		maxLag:  maxLag,
+		breaker: repository.NewBreaker("postgres", isConnectionFailure, logger),
+		logger:  logger,
+	}
+	for _, r := range replicas {
+		rep := &replica{db: r}
+		rep.healthy.Store(true)
+		db.replicas = append(db.replicas, rep)
+	}
+	return db
+}
+
+// reader picks a healthy replica round-robin, or nil when reads must or can
+// only go to the primary
+func (d *DB) reader(ctx context.Context) *replica {
+	if len(d.replicas) == 0 || repository.PrimaryReadsRequired(ctx) {
+		return nil
+	}
+
+	start := d.next.Add(1)
+	for i := range d.replicas {
+		r := d.replicas[(start+uint64(i))%uint64(len(d.replicas))]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// GetFromReplica is GetContext on a replica, retried on the primary if the
+// replica fails
+func (d *DB) GetFromReplica(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if r := d.reader(ctx); r != nil {
+		err := r.db.GetContext(ctx, dest, query, args...)
+		if !d.replicaFailed(ctx, r, err) {
+			metrics.DBReadsTotal.WithLabelValues("replica").Inc()
+			return err
+		}
+	}
+	metrics.DBReadsTotal.WithLabelValues("primary").Inc()
+	return d.GetContext(ctx, dest, query, args...)
+}
+
+// SelectFromReplica is SelectContext on a replica, retried on the primary if
+// the replica fails
+func (d *DB) SelectFromReplica(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if r := d.reader(ctx); r != nil {
+		err := r.db.SelectContext(ctx, dest, query, args...)
+		if !d.replicaFailed(ctx, r, err) {
+			metrics.DBReadsTotal.WithLabelValues("replica").Inc()
+			return err
+		}
+	}
+	metrics.DBReadsTotal.WithLabelValues("primary").Inc()
+	return d.SelectContext(ctx, dest, query, args...)
+}
+
+// GetContext is sqlx's GetContext on the primary, through the breaker
+func (d *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.breaker.Execute(ctx, func(ctx context.Context) error {
+		return d.DB.GetContext(ctx, dest, query, args...)
+	})
+}
+
+// SelectContext is sqlx's SelectContext on the primary, through the breaker
+func (d *DB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.breaker.Execute(ctx, func(ctx context.Context) error {
+		return d.DB.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+// ExecContext is ExecContext on the primary, through the breaker
+func (d *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := d.breaker.Execute(ctx, func(ctx context.Context) (err error) {
+		result, err = d.DB.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// QueryRowContext is QueryRowContext on the primary, through the breaker. The
+// row's Scan reports to the breaker, since that is when the query's error is known.
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row {
+	if err := d.breaker.Allow(); err != nil {
+		return &Row{err: err}
+	}
+	return &Row{row: d.DB.QueryRowContext(ctx, query, args...), breaker: d.breaker}
+}
+
+// BeginTxx starts a transaction on the primary once the breaker allows it.
+// Statements in the transaction run on its connection without the breaker.
+func (d *DB) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	var tx *sqlx.Tx
+	err := d.breaker.Execute(ctx, func(ctx context.Context) (err error) {
+		tx, err = d.DB.BeginTxx(ctx, opts)
+		return err
+	})
+	return tx, err
+}
+
+// Row is the result of QueryRowContext
+type Row struct {
+	row     *sql.Row
+	breaker *retry.CircuitBreaker
+	err     error
+}
+
+// Scan copies the row's columns into dest, like sql.Row's Scan
+func (r *Row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	err := r.row.Scan(dest...)
+	r.breaker.Record(err)
+	return err
+}
+
+// isConnectionFailure reports whether err means PostgreSQL couldn't be reached
+// or didn't answer in time, rather than answering with no rows or an error
+func isConnectionFailure(err error) bool {
+	var pqErr *pq.Error
+	return !errors.Is(err, sql.ErrNoRows) && !errors.As(err, &pqErr)
+}
+
+// replicaFailed reports whether err means the replica, rather than the query,
+// failed, taking the replica out of rotation until its next health check
+func (d *DB) replicaFailed(ctx context.Context, r *replica, err error) bool {
+	if err == nil || errors.Is(err, sql.ErrNoRows) || ctx.Err() != nil {
+		return false
+	}
+	if r.healthy.CompareAndSwap(true, false) {
+		d.logger.Warn("Postgres replica failed; reading from primary", zap.Error(err))
+	}
+	return true
+}
+
+// MonitorReplicas checks each replica's connectivity and replication lag
+// every interval until ctx is cancelled
+func (d *DB) MonitorReplicas(ctx context.Context, interval time.Duration) {
+	if len(d.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		d.checkReplicas(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *DB) checkReplicas(ctx context.Context) {
+	for i, r := range d.replicas {
+		var lagSeconds float64
+		err := r.db.GetContext(ctx, &lagSeconds, replicaLagQuery)
+		if err == nil {
+			metrics.DBReplicaLagSeconds.WithLabelValues(replicaLabel(i)).Set(lagSeconds)
+		}
+
+		healthy := err == nil && time.Duration(lagSeconds*float64(time.Second)) <= d.maxLag
+		if r.healthy.Swap(healthy) != healthy {
+			d.logger.Info("Postgres replica availability changed",
+				zap.Int("replica", i), zap.Bool("healthy", healthy),
+				zap.Float64("lag_seconds", lagSeconds), zap.Error(err))
+		}
+	}
+}
+
+// CloseReplicas closes the replica connections; the primary is owned by the caller
+func (d *DB) CloseReplicas() error {
+	var errs []error
+	for _, r := range d.replicas {
+		errs = append(errs, r.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func replicaLabel(i int) string {
+	return "replica-" + strconv.Itoa(i)
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure DeviceTokenRepository implements repository.DeviceTokenRepository
+var _ repository.DeviceTokenRepository = (*DeviceTokenRepository)(nil)
+
+type DeviceTokenRepository struct {
+	db *DB
+}
+
+func NewDeviceTokenRepository(db *DB) *DeviceTokenRepository {
+	return &DeviceTokenRepository{db: db}
+}
+
+// RegisterToken stores a device token, reassigning it if another user registered it before
+func (r *DeviceTokenRepository) RegisterToken(ctx context.Context, token *domain.DeviceToken) error {
+	query := `
+		INSERT INTO device_tokens (token, user_id, platform)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (token) DO UPDATE
+		SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform, updated_at = NOW(),
+		    invalidated_at = NULL, invalid_reason = NULL
+		RETURNING created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query, token.Token, token.UserID, token.Platform).
+		Scan(&token.CreatedAt, &token.UpdatedAt)
+}
+
+func (r *DeviceTokenRepository) GetActiveTokens(ctx context.Context, userID uuid.UUID) ([]*domain.DeviceToken, error) {
+	tokens := []*domain.DeviceToken{}
+	query := `SELECT * FROM device_tokens WHERE user_id = $1 AND invalidated_at IS NULL ORDER BY updated_at DESC`
+	err := r.db.SelectContext(ctx, &tokens, query, userID)
+	return tokens, err
+}
+
+// MarkTokenInvalid flags a token the push provider reported as dead so it is no longer used
+func (r *DeviceTokenRepository) MarkTokenInvalid(ctx context.Context, token, reason string) error {
+	query := `
+		UPDATE device_tokens
+		SET invalidated_at = NOW(), invalid_reason = $2, updated_at = NOW()
+		WHERE token = $1 AND invalidated_at IS NULL
+	`
+	_, err := r.db.ExecContext(ctx, query, token, reason)
+	return err
+}
+
+// DeleteInvalidTokens removes tokens invalidated before the given time
+func (r *DeviceTokenRepository) DeleteInvalidTokens(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM device_tokens WHERE invalidated_at IS NOT NULL AND invalidated_at < $1`
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
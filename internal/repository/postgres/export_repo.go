@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure ExportRepository implements repository.ExportRepository
+var _ repository.ExportRepository = (*ExportRepository)(nil)
+
+type ExportRepository struct {
+	db *DB
+}
+
+func NewExportRepository(db *DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+const exportColumns = `id, dataset, format, since, until, columns, status, object_key, row_count, error, requested_by, created_at, completed_at`
+
+func (r *ExportRepository) Create(ctx context.Context, export *domain.DataExport) error {
+	query := `
+		INSERT INTO data_exports (id, dataset, format, since, until, columns, status, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		export.ID, export.Dataset, export.Format, export.Since, export.Until,
+		export.Columns, export.Status, export.RequestedBy,
+	).Scan(&export.CreatedAt)
+}
+
+func (r *ExportRepository) Get(ctx context.Context, id uuid.UUID) (*domain.DataExport, error) {
+	var export domain.DataExport
+	query := `SELECT ` + exportColumns + ` FROM data_exports WHERE id = $1`
+	err := r.db.GetContext(ctx, &export, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *ExportRepository) List(ctx context.Context, limit, offset int) ([]*domain.DataExport, error) {
+	exports := []*domain.DataExport{}
+	query := `SELECT ` + exportColumns + ` FROM data_exports ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	err := r.db.SelectContext(ctx, &exports, query, limit, offset)
+	return exports, err
+}
+
+func (r *ExportRepository) Update(ctx context.Context, export *domain.DataExport) error {
+	query := `
+		UPDATE data_exports
+		SET status = $1, columns = $2, object_key = $3, row_count = $4, error = $5, completed_at = $6
+		WHERE id = $7
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		export.Status, export.Columns, export.ObjectKey, export.Rows, export.Error, export.CompletedAt, export.ID,
+	)
+	return err
+}
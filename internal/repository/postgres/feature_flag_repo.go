@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure FeatureFlagRepository implements repository.FeatureFlagRepository
+var _ repository.FeatureFlagRepository = (*FeatureFlagRepository)(nil)
+
+type FeatureFlagRepository struct {
+	db *DB
+}
+
+func NewFeatureFlagRepository(db *DB) *FeatureFlagRepository {
+	return &FeatureFlagRepository{db: db}
+}
+
+func (r *FeatureFlagRepository) List(ctx context.Context) ([]*domain.FeatureFlag, error) {
+	flags := []*domain.FeatureFlag{}
+	query := `SELECT key, enabled, updated_by, updated_at FROM feature_flags ORDER BY key`
+	err := r.db.SelectContext(ctx, &flags, query)
+	return flags, err
+}
+
+// Set creates or updates the flag
+func (r *FeatureFlagRepository) Set(ctx context.Context, key string, enabled bool, updatedBy *uuid.UUID) (*domain.FeatureFlag, error) {
+	var flag domain.FeatureFlag
+	query := `
+		INSERT INTO feature_flags (key, enabled, updated_by, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING key, enabled, updated_by, updated_at
+	`
+	err := r.db.GetContext(ctx, &flag, query, key, enabled, updatedBy)
+	return &flag, err
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure FingerprintRepository implements repository.FingerprintRepository
+var _ repository.FingerprintRepository = (*FingerprintRepository)(nil)
+
+type FingerprintRepository struct {
+	db *DB
+}
+
+func NewFingerprintRepository(db *DB) *FingerprintRepository {
+	return &FingerprintRepository{db: db}
+}
+
+func (r *FingerprintRepository) RecordFingerprint(ctx context.Context, userID uuid.UUID, kind, hash string) error {
+	query := `
+		INSERT INTO user_fingerprints (user_id, kind, hash)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, kind, hash) DO UPDATE SET last_seen_at = NOW()
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, kind, hash)
+	return err
+}
+
+// FindBannedUsersByFingerprint returns banned users, other than excludeUserID, that
+// have used any of the given fingerprints since the given time
+func (r *FingerprintRepository) FindBannedUsersByFingerprint(ctx context.Context, hashes []string, excludeUserID uuid.UUID, since time.Time) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	query := `
+		SELECT DISTINCT f.user_id
+		FROM user_fingerprints f
+		JOIN users u ON u.id = f.user_id
+		WHERE f.hash = ANY($1) AND f.user_id <> $2 AND f.last_seen_at >= $3 AND u.is_banned
+	`
+	err := r.db.SelectContext(ctx, &ids, query, pq.Array(hashes), excludeUserID, since)
+	return ids, err
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure InviteRepository implements repository.InviteRepository
+var _ repository.InviteRepository = (*InviteRepository)(nil)
+
+type InviteRepository struct {
+	db *DB
+}
+
+func NewInviteRepository(db *DB) *InviteRepository {
+	return &InviteRepository{db: db}
+}
+
+func (r *InviteRepository) Create(ctx context.Context, invite *domain.Invite) error {
+	query := `
+		INSERT INTO circle_invites (id, circle_id, code, created_by, max_uses, used_count, expires_at, created_at, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.db.ExecContext(ctx, query, invite.ID, invite.CircleID, invite.Code, invite.CreatedBy,
+		invite.MaxUses, invite.UsedCount, invite.ExpiresAt, invite.CreatedAt, invite.IsActive)
+	return err
+}
+
+func (r *InviteRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Invite, error) {
+	var invite domain.Invite
+	err := r.db.GetContext(ctx, &invite, `SELECT * FROM circle_invites WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invite not found")
+	}
+	return &invite, err
+}
+
+func (r *InviteRepository) GetByCode(ctx context.Context, code string) (*domain.Invite, error) {
+	var invite domain.Invite
+	err := r.db.GetContext(ctx, &invite, `SELECT * FROM circle_invites WHERE code = $1`, code)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invite not found")
+	}
+	return &invite, err
+}
+
+func (r *InviteRepository) GetByCircleID(ctx context.Context, circleID uuid.UUID) ([]*domain.Invite, error) {
+	invites := []*domain.Invite{}
+	query := `SELECT * FROM circle_invites WHERE circle_id = $1 ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &invites, query, circleID)
+	return invites, err
+}
+
+func (r *InviteRepository) IncrementUsedCount(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE circle_invites SET used_count = used_count + 1 WHERE id = $1`, id)
+	return err
+}
+
+func (r *InviteRepository) Deactivate(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE circle_invites SET is_active = false WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure LoginLocationRepository implements repository.LoginLocationRepository
+var _ repository.LoginLocationRepository = (*LoginLocationRepository)(nil)
+
+type LoginLocationRepository struct {
+	db *DB
+}
+
+func NewLoginLocationRepository(db *DB) *LoginLocationRepository {
+	return &LoginLocationRepository{db: db}
+}
+
+// RecordLoginLocation stores a login from the location's country, keeping the
+// most recent coordinates seen there
+func (r *LoginLocationRepository) RecordLoginLocation(ctx context.Context, location *domain.LoginLocation) error {
+	query := `
+		INSERT INTO user_login_locations (user_id, country, latitude, longitude)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, country) DO UPDATE SET
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			last_seen_at = NOW()
+	`
+	_, err := r.db.ExecContext(ctx, query, location.UserID, location.Country, location.Latitude, location.Longitude)
+	return err
+}
+
+// GetLatestLoginLocation returns where the user last logged in, or nil if
+// no location has been recorded
+func (r *LoginLocationRepository) GetLatestLoginLocation(ctx context.Context, userID uuid.UUID) (*domain.LoginLocation, error) {
+	var location domain.LoginLocation
+	query := `
+		SELECT user_id, country, latitude, longitude, last_seen_at
+		FROM user_login_locations
+		WHERE user_id = $1
+		ORDER BY last_seen_at DESC
+		LIMIT 1
+	`
+	err := r.db.GetContext(ctx, &location, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *LoginLocationRepository) HasLoginCountry(ctx context.Context, userID uuid.UUID, country string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM user_login_locations WHERE user_id = $1 AND country = $2)`
+	err := r.db.GetContext(ctx, &exists, query, userID, country)
+	return exists, err
+}
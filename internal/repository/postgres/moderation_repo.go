@@ -4,9 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
@@ -15,23 +15,30 @@ import (
 var _ repository.ModerationRepository = (*ModerationRepository)(nil)
 
 type ModerationRepository struct {
-	db *sqlx.DB
+	db *DB
 }
 
-func NewModerationRepository(db *sqlx.DB) *ModerationRepository {
+func NewModerationRepository(db *DB) *ModerationRepository {
 	return &ModerationRepository{db: db}
 }
 
+// CreateReport opens a case. If one is already pending for the content, e.g.
+// opened by a concurrent report, report takes that case's ID instead.
 func (r *ModerationRepository) CreateReport(ctx context.Context, report *domain.ContentReport) error {
 	query := `
-		INSERT INTO content_reports (id, reporter_id, content_type, content_id, reason, description, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING created_at
+		INSERT INTO content_reports (id, reporter_id, source, content_type, content_id, reason, description, status, severity)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (content_type, content_id) WHERE status = 'pending'
+		DO UPDATE SET content_id = EXCLUDED.content_id
+		RETURNING id, created_at
 	`
+	if report.Severity == "" {
+		report.Severity = domain.ReportSeverity(report.Reason)
+	}
 	return r.db.QueryRowContext(ctx, query,
-		report.ID, report.ReporterID, report.ContentType, report.ContentID,
-		report.Reason, report.Description, report.Status,
-	).Scan(&report.CreatedAt)
+		report.ID, report.ReporterID, report.Source, report.ContentType, report.ContentID,
+		report.Reason, report.Description, report.Status, report.Severity,
+	).Scan(&report.ID, &report.CreatedAt)
 }
 
 func (r *ModerationRepository) GetReports(ctx context.Context, status *string, limit, offset int) ([]*domain.ContentReport, error) {
@@ -47,7 +54,7 @@ func (r *ModerationRepository) GetReports(ctx context.Context, status *string, l
 		args = []interface{}{limit, offset}
 	}
 
-	err := r.db.SelectContext(ctx, &reports, query, args...)
+	err := r.db.SelectFromReplica(ctx, &reports, query, args...)
 	return reports, err
 }
 
@@ -71,6 +78,163 @@ func (r *ModerationRepository) UpdateReportStatus(ctx context.Context, id uuid.U
 	return err
 }
 
+// GetOpenReportForContent returns the pending case for a piece of content, or nil if there is none
+func (r *ModerationRepository) GetOpenReportForContent(ctx context.Context, contentType, contentID string) (*domain.ContentReport, error) {
+	var report domain.ContentReport
+	query := `
+		SELECT * FROM content_reports
+		WHERE content_type = $1 AND content_id = $2 AND status = 'pending'
+		ORDER BY created_at ASC
+		LIMIT 1
+	`
+	err := r.db.GetContext(ctx, &report, query, contentType, contentID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// AddReporter attaches a reporter to an existing case. It returns false if the
+// user has already reported this case.
+func (r *ModerationRepository) AddReporter(ctx context.Context, reportID, reporterID uuid.UUID, reason string, weight float64) (bool, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO report_reporters (report_id, reporter_id, weight, reason)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (report_id, reporter_id) DO NOTHING
+			RETURNING weight
+		), bumped AS (
+			UPDATE content_reports
+			SET reporter_count = reporter_count + 1,
+			    report_score = report_score + (SELECT weight FROM inserted)
+			WHERE id = $1 AND EXISTS (SELECT 1 FROM inserted)
+		), reputation AS (
+			INSERT INTO reporter_reputation (user_id, reports_submitted)
+			SELECT $2, 1 FROM inserted
+			ON CONFLICT (user_id) DO UPDATE
+			SET reports_submitted = reporter_reputation.reports_submitted + 1, updated_at = NOW()
+		)
+		SELECT COUNT(*) FROM inserted
+	`
+	var added int
+	if err := r.db.GetContext(ctx, &added, query, reportID, reporterID, weight, reason); err != nil {
+		return false, err
+	}
+	return added > 0, nil
+}
+
+func (r *ModerationRepository) GetReporterReputation(ctx context.Context, userID uuid.UUID) (*domain.ReporterReputation, error) {
+	var reputation domain.ReporterReputation
+	query := `SELECT * FROM reporter_reputation WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &reputation, query, userID)
+	if err == sql.ErrNoRows {
+		return &domain.ReporterReputation{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reputation, nil
+}
+
+// RecordReportOutcome updates the reputation of everyone who reported a case once it is resolved
+func (r *ModerationRepository) RecordReportOutcome(ctx context.Context, reportID uuid.UUID, upheld bool) error {
+	upheldInc, rejectedInc := 0, 1
+	if upheld {
+		upheldInc, rejectedInc = 1, 0
+	}
+
+	query := `
+		INSERT INTO reporter_reputation (user_id, reports_upheld, reports_rejected)
+		SELECT reporter_id, $2, $3 FROM report_reporters WHERE report_id = $1
+		ON CONFLICT (user_id) DO UPDATE
+		SET reports_upheld = reporter_reputation.reports_upheld + EXCLUDED.reports_upheld,
+		    reports_rejected = reporter_reputation.reports_rejected + EXCLUDED.reports_rejected,
+		    updated_at = NOW()
+	`
+	_, err := r.db.ExecContext(ctx, query, reportID, upheldInc, rejectedInc)
+	return err
+}
+
+// GetReporterIDs returns every user who reported a case
+func (r *ModerationRepository) GetReporterIDs(ctx context.Context, reportID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	query := `SELECT reporter_id FROM report_reporters WHERE report_id = $1`
+	err := r.db.SelectContext(ctx, &ids, query, reportID)
+	return ids, err
+}
+
+// ListReportsCreatedBetween returns reports created in [since, until), oldest first
+func (r *ModerationRepository) ListReportsCreatedBetween(ctx context.Context, since, until time.Time, limit, offset int) ([]*domain.ContentReport, error) {
+	reports := []*domain.ContentReport{}
+	query := `
+		SELECT * FROM content_reports
+		WHERE created_at >= $1 AND created_at < $2
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3 OFFSET $4
+	`
+	err := r.db.SelectFromReplica(ctx, &reports, query, since, until, limit, offset)
+	return reports, err
+}
+
+// GetModerationStats returns queue depth for open reports and resolution
+// times for reports resolved since the given time, grouped by severity
+func (r *ModerationRepository) GetModerationStats(ctx context.Context, resolvedSince time.Time) ([]*domain.ModerationSeverityStats, error) {
+	stats := []*domain.ModerationSeverityStats{}
+	query := `
+		SELECT severity,
+		       COUNT(*) FILTER (WHERE status = 'pending') AS open_count,
+		       MIN(created_at) FILTER (WHERE status = 'pending') AS oldest_open_at,
+		       COUNT(*) FILTER (WHERE status <> 'pending' AND reviewed_at >= $1) AS resolved_count,
+		       COALESCE(AVG(EXTRACT(EPOCH FROM reviewed_at - created_at))
+		                FILTER (WHERE status <> 'pending' AND reviewed_at >= $1), 0) AS avg_resolution_seconds
+		FROM content_reports
+		WHERE status = 'pending' OR reviewed_at >= $1
+		GROUP BY severity
+	`
+	err := r.db.SelectFromReplica(ctx, &stats, query, resolvedSince)
+	return stats, err
+}
+
+// CountOverdueReports counts open reports of a severity created before the given time
+func (r *ModerationRepository) CountOverdueReports(ctx context.Context, severity string, createdBefore time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM content_reports WHERE status = 'pending' AND severity = $1 AND created_at < $2`
+	err := r.db.GetFromReplica(ctx, &count, query, severity, createdBefore)
+	return count, err
+}
+
+func (r *ModerationRepository) CreateNote(ctx context.Context, note *domain.ModeratorNote) error {
+	query := `
+		INSERT INTO moderator_notes (id, report_id, author_id, parent_id, body)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		note.ID, note.ReportID, note.AuthorID, note.ParentID, note.Body,
+	).Scan(&note.CreatedAt)
+}
+
+func (r *ModerationRepository) GetNoteByID(ctx context.Context, id uuid.UUID) (*domain.ModeratorNote, error) {
+	var note domain.ModeratorNote
+	query := `SELECT * FROM moderator_notes WHERE id = $1`
+	err := r.db.GetContext(ctx, &note, query, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("note not found")
+	}
+	return &note, err
+}
+
+// GetNotesByReportID returns all notes on a case, oldest first
+func (r *ModerationRepository) GetNotesByReportID(ctx context.Context, reportID uuid.UUID) ([]*domain.ModeratorNote, error) {
+	notes := []*domain.ModeratorNote{}
+	query := `SELECT * FROM moderator_notes WHERE report_id = $1 ORDER BY created_at ASC`
+	err := r.db.SelectContext(ctx, &notes, query, reportID)
+	return notes, err
+}
+
 func (r *ModerationRepository) ListReports(ctx context.Context, status *string, limit, offset int) ([]*domain.ContentReport, error) {
 	return r.GetReports(ctx, status, limit, offset)
 }
@@ -91,6 +255,18 @@ func (r *ModerationRepository) RemoveBlock(ctx context.Context, blockerID, block
 	return err
 }
 
+// GetBlockedUserIDs returns every user that userID has blocked or been blocked by
+func (r *ModerationRepository) GetBlockedUserIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	query := `
+		SELECT blocked_id FROM user_blocks WHERE blocker_id = $1
+		UNION
+		SELECT blocker_id FROM user_blocks WHERE blocked_id = $1
+	`
+	err := r.db.SelectContext(ctx, &ids, query, userID)
+	return ids, err
+}
+
 func (r *ModerationRepository) IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2)`
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure NotificationPreferencesRepository implements repository.NotificationPreferencesRepository
+var _ repository.NotificationPreferencesRepository = (*NotificationPreferencesRepository)(nil)
+
+type NotificationPreferencesRepository struct {
+	db *DB
+}
+
+func NewNotificationPreferencesRepository(db *DB) *NotificationPreferencesRepository {
+	return &NotificationPreferencesRepository{db: db}
+}
+
+// notificationPreferencesRow is the stored form of domain.NotificationPreferences
+type notificationPreferencesRow struct {
+	UserID            uuid.UUID `db:"user_id"`
+	Channels          string    `db:"channels"` // JSON object of event type to channel
+	QuietHoursEnabled bool      `db:"quiet_hours_enabled"`
+	QuietHoursStart   string    `db:"quiet_hours_start"`
+	QuietHoursEnd     string    `db:"quiet_hours_end"`
+	Timezone          string    `db:"timezone"`
+	UpdatedAt         time.Time `db:"updated_at"`
+
+	CheckInReminderEnabled  bool       `db:"checkin_reminder_enabled"`
+	CheckInReminderTime     string     `db:"checkin_reminder_time"`
+	CheckInReminderLastSent *time.Time `db:"checkin_reminder_last_sent"`
+}
+
+func (row *notificationPreferencesRow) toDomain() (*domain.NotificationPreferences, error) {
+	prefs := &domain.NotificationPreferences{
+		UserID:                 row.UserID,
+		Channels:               map[domain.NotificationType]domain.NotificationChannel{},
+		QuietHoursEnabled:      row.QuietHoursEnabled,
+		QuietHoursStart:        row.QuietHoursStart,
+		QuietHoursEnd:          row.QuietHoursEnd,
+		Timezone:               row.Timezone,
+		CheckInReminderEnabled: row.CheckInReminderEnabled,
+		CheckInReminderTime:    row.CheckInReminderTime,
+		UpdatedAt:              row.UpdatedAt,
+	}
+	if err := json.Unmarshal([]byte(row.Channels), &prefs.Channels); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// Get returns a user's preferences, or the defaults if they have never saved any
+func (r *NotificationPreferencesRepository) Get(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	var row notificationPreferencesRow
+	query := `SELECT * FROM notification_preferences WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &row, query, userID)
+	if err == sql.ErrNoRows {
+		return domain.DefaultNotificationPreferences(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toDomain()
+}
+
+func (r *NotificationPreferencesRepository) Upsert(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	channels, err := json.Marshal(prefs.Channels)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO notification_preferences (user_id, channels, quiet_hours_enabled, quiet_hours_start, quiet_hours_end, timezone,
+			checkin_reminder_enabled, checkin_reminder_time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id) DO UPDATE
+		SET channels = EXCLUDED.channels,
+		    quiet_hours_enabled = EXCLUDED.quiet_hours_enabled,
+		    quiet_hours_start = EXCLUDED.quiet_hours_start,
+		    quiet_hours_end = EXCLUDED.quiet_hours_end,
+		    timezone = EXCLUDED.timezone,
+		    checkin_reminder_enabled = EXCLUDED.checkin_reminder_enabled,
+		    checkin_reminder_time = EXCLUDED.checkin_reminder_time,
+		    updated_at = NOW()
+		RETURNING updated_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		prefs.UserID, string(channels), prefs.QuietHoursEnabled,
+		prefs.QuietHoursStart, prefs.QuietHoursEnd, prefs.Timezone,
+		prefs.CheckInReminderEnabled, prefs.CheckInReminderTime,
+	).Scan(&prefs.UpdatedAt)
+}
+
+// ClaimDueCheckInReminders marks up to limit users whose reminder time has
+// passed in their own time zone and who have not been reminded today as
+// reminded, returning their preferences. Rows are locked with SKIP LOCKED so
+// concurrent replicas never claim the same user.
+func (r *NotificationPreferencesRepository) ClaimDueCheckInReminders(ctx context.Context, limit int) ([]*domain.NotificationPreferences, error) {
+	var rows []notificationPreferencesRow
+	query := `
+		UPDATE notification_preferences
+		SET checkin_reminder_last_sent = (NOW() AT TIME ZONE timezone)::date
+		WHERE user_id IN (
+			SELECT user_id FROM notification_preferences
+			WHERE checkin_reminder_enabled
+			  AND (NOW() AT TIME ZONE timezone)::time >= checkin_reminder_time::time
+			  AND (checkin_reminder_last_sent IS NULL
+			       OR checkin_reminder_last_sent < (NOW() AT TIME ZONE timezone)::date)
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+	if err := r.db.SelectContext(ctx, &rows, query, limit); err != nil {
+		return nil, err
+	}
+
+	prefs := make([]*domain.NotificationPreferences, 0, len(rows))
+	for i := range rows {
+		p, err := rows[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure RetentionRepository implements repository.RetentionRepository
+var _ repository.RetentionRepository = (*RetentionRepository)(nil)
+
+// auditLogIdentifiable matches audit logs still holding something that
+// identifies a person
+const auditLogIdentifiable = `(actor_id IS NOT NULL OR actor_ip <> '' OR target_id IS NOT NULL OR metadata IS NOT NULL)`
+
+// RetentionRepository enforces retention on audit logs
+type RetentionRepository struct {
+	db *DB
+}
+
+func NewRetentionRepository(db *DB) *RetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+func (r *RetentionRepository) CountExpired(ctx context.Context, class domain.DataClass, action domain.RetentionAction, cutoff time.Time) (int64, error) {
+	if err := checkRetentionClass(class); err != nil {
+		return 0, err
+	}
+
+	query := `SELECT COUNT(*) FROM audit_logs WHERE created_at < $1`
+	if action == domain.RetentionActionAnonymize {
+		query += ` AND ` + auditLogIdentifiable
+	}
+	var count int64
+	err := r.db.GetContext(ctx, &count, query, cutoff)
+	return count, err
+}
+
+func (r *RetentionRepository) DeleteExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error) {
+	if err := checkRetentionClass(class); err != nil {
+		return 0, err
+	}
+
+	query := `
+		DELETE FROM audit_logs
+		WHERE id IN (SELECT id FROM audit_logs WHERE created_at < $1 LIMIT $2)
+	`
+	result, err := r.db.ExecContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// AnonymizeExpired clears the actor, target and metadata of expired audit
+// logs, keeping the event type, action, outcome and time
+func (r *RetentionRepository) AnonymizeExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error) {
+	if err := checkRetentionClass(class); err != nil {
+		return 0, err
+	}
+
+	query := `
+		UPDATE audit_logs
+		SET actor_id = NULL, actor_ip = '', target_id = NULL, metadata = NULL
+		WHERE id IN (
+			SELECT id FROM audit_logs
+			WHERE created_at < $1 AND ` + auditLogIdentifiable + `
+			LIMIT $2
+		)
+	`
+	result, err := r.db.ExecContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func checkRetentionClass(class domain.DataClass) error {
+	if class != domain.DataClassAuditLogs {
+		return fmt.Errorf("retention is not supported for %s in Postgres", class)
+	}
+	return nil
+}
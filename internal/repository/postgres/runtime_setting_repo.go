@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure RuntimeSettingRepository implements repository.RuntimeSettingRepository
+var _ repository.RuntimeSettingRepository = (*RuntimeSettingRepository)(nil)
+
+type RuntimeSettingRepository struct {
+	db *DB
+}
+
+func NewRuntimeSettingRepository(db *DB) *RuntimeSettingRepository {
+	return &RuntimeSettingRepository{db: db}
+}
+
+func (r *RuntimeSettingRepository) List(ctx context.Context) ([]*domain.RuntimeSetting, error) {
+	settings := []*domain.RuntimeSetting{}
+	query := `SELECT key, value, updated_by, updated_at FROM runtime_settings ORDER BY key`
+	err := r.db.SelectContext(ctx, &settings, query)
+	return settings, err
+}
+
+// Set creates or updates the setting
+func (r *RuntimeSettingRepository) Set(ctx context.Context, key, value string, updatedBy *uuid.UUID) (*domain.RuntimeSetting, error) {
+	var setting domain.RuntimeSetting
+	query := `
+		INSERT INTO runtime_settings (key, value, updated_by, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING key, value, updated_by, updated_at
+	`
+	err := r.db.GetContext(ctx, &setting, query, key, value, updatedBy)
+	return &setting, err
+}
+
+func (r *RuntimeSettingRepository) Delete(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM runtime_settings WHERE key = $1`, key)
+	return err
+}
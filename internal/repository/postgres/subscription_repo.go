@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure SubscriptionRepository implements repository.SubscriptionRepository
+var _ repository.SubscriptionRepository = (*SubscriptionRepository)(nil)
+
+type SubscriptionRepository struct {
+	db *DB
+}
+
+func NewSubscriptionRepository(db *DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+const subscriptionColumns = `user_id, plan, status, source, external_id, external_customer_id, current_period_end, created_at, updated_at`
+
+func (r *SubscriptionRepository) Get(ctx context.Context, userID uuid.UUID) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &subscription, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *SubscriptionRepository) GetByExternalID(ctx context.Context, externalID string) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE external_id = $1`
+	err := r.db.GetContext(ctx, &subscription, query, externalID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// Save upserts the subscription and mirrors isPremium onto the user in one
+// statement, so the two can't disagree
+func (r *SubscriptionRepository) Save(ctx context.Context, subscription *domain.Subscription, isPremium bool) error {
+	query := `
+		WITH saved AS (
+			INSERT INTO subscriptions (user_id, plan, status, source, external_id, external_customer_id, current_period_end, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+			ON CONFLICT (user_id) DO UPDATE SET
+				plan = EXCLUDED.plan,
+				status = EXCLUDED.status,
+				source = EXCLUDED.source,
+				external_id = EXCLUDED.external_id,
+				external_customer_id = EXCLUDED.external_customer_id,
+				current_period_end = EXCLUDED.current_period_end,
+				updated_at = EXCLUDED.updated_at
+			RETURNING user_id, created_at, updated_at
+		), premium AS (
+			UPDATE users SET is_premium = $8 WHERE id IN (SELECT user_id FROM saved)
+		)
+		SELECT created_at, updated_at FROM saved
+	`
+	return r.db.QueryRowContext(ctx, query,
+		subscription.UserID, subscription.Plan, subscription.Status, subscription.Source,
+		subscription.ExternalID, subscription.ExternalCustomerID, subscription.CurrentPeriodEnd, isPremium,
+	).Scan(&subscription.CreatedAt, &subscription.UpdatedAt)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
@@ -15,39 +17,49 @@ import (
 var _ repository.UserRepository = (*UserRepository)(nil)
 
 type UserRepository struct {
-	db *sqlx.DB
+	db *DB
 }
 
-func NewUserRepository(db *sqlx.DB) *UserRepository {
+func NewUserRepository(db *DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, username, email, password_hash, avatar_id, is_anonymous, strength_points)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING created_at, last_active_at
+		INSERT INTO users (id, username, email, password_hash, avatar_id, is_anonymous, strength_points, role)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE(NULLIF($8, ''), 'user'))
+		RETURNING created_at, last_active_at, role
 	`
 	return r.db.QueryRowContext(ctx, query,
 		user.ID, user.Username, user.Email, user.PasswordHash,
-		user.AvatarID, user.IsAnonymous, user.StrengthPoints,
-	).Scan(&user.CreatedAt, &user.LastActiveAt)
+		user.AvatarID, user.IsAnonymous, user.StrengthPoints, user.Role,
+	).Scan(&user.CreatedAt, &user.LastActiveAt, &user.Role)
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	var user domain.User
 	query := `SELECT * FROM users WHERE id = $1 AND is_banned = false`
-	err := r.db.GetContext(ctx, &user, query, id)
+	err := r.db.GetFromReplica(ctx, &user, query, id)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
 	return &user, err
 }
 
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error) {
+	users := []*domain.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	query := `SELECT * FROM users WHERE id = ANY($1) AND is_banned = false`
+	err := r.db.SelectFromReplica(ctx, &users, query, pq.Array(ids))
+	return users, err
+}
+
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	var user domain.User
 	query := `SELECT * FROM users WHERE username = $1 AND is_banned = false`
-	err := r.db.GetContext(ctx, &user, query, username)
+	err := r.db.GetFromReplica(ctx, &user, query, username)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
@@ -57,7 +69,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
 	query := `SELECT * FROM users WHERE email = $1 AND is_banned = false`
-	err := r.db.GetContext(ctx, &user, query, email)
+	err := r.db.GetFromReplica(ctx, &user, query, email)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
@@ -76,7 +88,7 @@ func (r *UserRepository) UpdateStrengthPoints(ctx context.Context, userID uuid.U
 	return err
 }
 
-func (r *UserRepository) UpdateProfile(ctx context.Context, userID uuid.UUID, username *string, avatarID *int) error {
+func (r *UserRepository) UpdateProfile(ctx context.Context, userID uuid.UUID, username *string, avatarID *int, shareMilestones *bool) error {
 	if username != nil {
 		query := `UPDATE users SET username = $1 WHERE id = $2`
 		if _, err := r.db.ExecContext(ctx, query, *username, userID); err != nil {
@@ -89,12 +101,146 @@ func (r *UserRepository) UpdateProfile(ctx context.Context, userID uuid.UUID, us
 			return err
 		}
 	}
+	if shareMilestones != nil {
+		query := `UPDATE users SET share_milestones = $1 WHERE id = $2`
+		if _, err := r.db.ExecContext(ctx, query, *shareMilestones, userID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (r *UserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)`
-	err := r.db.GetContext(ctx, &exists, query, username)
+	err := r.db.GetFromReplica(ctx, &exists, query, username)
 	return exists, err
 }
+
+// ListWithEmail returns up to limit users with a stored email and an ID after
+// afterID, ordered by ID so callers can page through every user
+func (r *UserRepository) ListWithEmail(ctx context.Context, afterID uuid.UUID, limit int) ([]*domain.User, error) {
+	var users []*domain.User
+	query := `SELECT * FROM users WHERE email IS NOT NULL AND id > $1 ORDER BY id LIMIT $2`
+	err := r.db.SelectContext(ctx, &users, query, afterID, limit)
+	return users, err
+}
+
+// ReplaceEmail sets a user's stored email only if it still equals current, so
+// a concurrent change is never overwritten
+func (r *UserRepository) ReplaceEmail(ctx context.Context, userID uuid.UUID, current, replacement string) (bool, error) {
+	query := `UPDATE users SET email = $1 WHERE id = $2 AND email = $3`
+	result, err := r.db.ExecContext(ctx, query, replacement, userID, current)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// likeEscaper escapes LIKE wildcards so search text matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search returns a page of users matching the filter, newest first, and the
+// total number matched. Banned users are included.
+func (r *UserRepository) Search(ctx context.Context, filter domain.UserSearch) ([]*domain.User, int, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	if filter.Query != "" {
+		if id, err := uuid.Parse(filter.Query); err == nil {
+			args = append(args, id)
+			conditions = append(conditions, fmt.Sprintf("id = $%d", len(args)))
+		} else {
+			args = append(args, "%"+likeEscaper.Replace(filter.Query)+"%")
+			conditions = append(conditions, fmt.Sprintf("username ILIKE $%d", len(args)))
+		}
+	}
+	if filter.Role != nil {
+		args = append(args, *filter.Role)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if filter.Banned != nil {
+		args = append(args, *filter.Banned)
+		conditions = append(conditions, fmt.Sprintf("is_banned = $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.GetFromReplica(ctx, &total, `SELECT COUNT(*) FROM users WHERE `+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	users := []*domain.User{}
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT * FROM users WHERE %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+	err := r.db.SelectFromReplica(ctx, &users, query, args...)
+	return users, total, err
+}
+
+// GetAnyByID returns the user whether or not they are banned. It reads the
+// primary, as admins act on the result.
+func (r *UserRepository) GetAnyByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	var user domain.User
+	query := `SELECT * FROM users WHERE id = $1 AND deleted_at IS NULL`
+	err := r.db.GetContext(ctx, &user, query, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	return &user, err
+}
+
+func (r *UserRepository) SetBanned(ctx context.Context, userID uuid.UUID, banned bool) error {
+	query := `UPDATE users SET is_banned = $1 WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, banned, userID)
+	return err
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, userID uuid.UUID, role domain.Role) error {
+	query := `UPDATE users SET role = $1 WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, role, userID)
+	return err
+}
+
+// CountUsers counts accounts that have not been deleted, by status and role
+func (r *UserRepository) CountUsers(ctx context.Context) (*domain.UserCounts, error) {
+	var counts domain.UserCounts
+	query := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE is_banned) AS banned,
+			COUNT(*) FILTER (WHERE role = 'moderator') AS moderators,
+			COUNT(*) FILTER (WHERE role = 'admin') AS admins
+		FROM users
+		WHERE deleted_at IS NULL
+	`
+	err := r.db.GetFromReplica(ctx, &counts, query)
+	return &counts, err
+}
+
+// CountMembers counts users who have not deleted their account
+func (r *UserRepository) CountMembers(ctx context.Context) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM users WHERE deleted_at IS NULL`
+	err := r.db.GetFromReplica(ctx, &count, query)
+	return count, err
+}
+
+// ListRegistrationsByWeek returns the IDs of users who registered in each week
+// (starting Monday) from since onwards
+func (r *UserRepository) ListRegistrationsByWeek(ctx context.Context, since time.Time) (map[time.Time][]string, error) {
+	var rows []struct {
+		ID   string    `db:"id"`
+		Week time.Time `db:"week"`
+	}
+	query := `SELECT id, date_trunc('week', created_at) AS week FROM users WHERE created_at >= $1`
+	if err := r.db.SelectFromReplica(ctx, &rows, query, since); err != nil {
+		return nil, err
+	}
+
+	weeks := make(map[time.Time][]string)
+	for _, row := range rows {
+		week := row.Week.UTC()
+		weeks[week] = append(weeks[week], row.ID)
+	}
+	return weeks, nil
+}
@@ -5,7 +5,6 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yourorg/anonymous-support/internal/domain"
@@ -15,7 +14,7 @@ import (
 // Integration test example using real database
 // Run with: go test -tags=integration ./internal/repository/postgres/...
 
-func setupTestDB(t *testing.T) *sqlx.DB {
+func setupTestDB(t *testing.T) *postgres.DB {
 	// This would typically use testcontainers or a test database
 	// For now, this is a template
 	t.Skip("Integration tests require database setup")
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure BlockCacheRepository implements repository.BlockCacheRepository
+var _ repository.BlockCacheRepository = (*BlockCacheRepository)(nil)
+
+// blockSetPlaceholder keeps the set key alive for users with no blocks, so
+// an empty block set is still a cache hit
+const blockSetPlaceholder = "-"
+
+type BlockCacheRepository struct {
+	client *redis.Client
+}
+
+func NewBlockCacheRepository(client *redis.Client) *BlockCacheRepository {
+	return &BlockCacheRepository{client: client}
+}
+
+func blockSetKey(userID string) string {
+	return fmt.Sprintf("user:blocks:%s", userID)
+}
+
+// GetBlockSet returns the cached block set and whether it was present in the cache
+func (r *BlockCacheRepository) GetBlockSet(ctx context.Context, userID string) ([]string, bool, error) {
+	members, err := r.client.SMembers(ctx, blockSetKey(userID)).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(members) == 0 {
+		return nil, false, nil
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		if member != blockSetPlaceholder {
+			ids = append(ids, member)
+		}
+	}
+	return ids, true, nil
+}
+
+func (r *BlockCacheRepository) SetBlockSet(ctx context.Context, userID string, blockedIDs []string, ttl time.Duration) error {
+	key := blockSetKey(userID)
+
+	members := make([]interface{}, 0, len(blockedIDs)+1)
+	members = append(members, blockSetPlaceholder)
+	for _, id := range blockedIDs {
+		members = append(members, id)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.SAdd(ctx, key, members...)
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *BlockCacheRepository) InvalidateBlockSet(ctx context.Context, userIDs ...string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = blockSetKey(id)
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/pkg/retry"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+// BreakerHook is a go-redis hook sending every command through the Redis
+// circuit breaker, so while Redis is down the repositories, caches and rate
+// limits on the client fail fast with a retry.OpenError. Add it to the client
+// with AddHook.
+type BreakerHook struct {
+	breaker *retry.CircuitBreaker
+}
+
+func NewBreakerHook(logger *zap.Logger) *BreakerHook {
+	return &BreakerHook{breaker: repository.NewBreaker("redis", isConnectionFailure, logger)}
+}
+
+func (h *BreakerHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *BreakerHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		err := next(ctx, cmd)
+		h.breaker.Record(err)
+		return err
+	}
+}
+
+func (h *BreakerHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		err := next(ctx, cmds)
+		h.breaker.Record(err)
+		return err
+	}
+}
+
+// isConnectionFailure reports whether err means Redis couldn't be reached or
+// didn't answer in time, rather than answering with a missing key or an error
+// reply
+func isConnectionFailure(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var replyErr redis.Error
+	return !errors.As(err, &replyErr)
+}
@@ -0,0 +1,106 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure CircleMembershipCacheRepository implements repository.CircleMembershipCacheRepository
+var _ repository.CircleMembershipCacheRepository = (*CircleMembershipCacheRepository)(nil)
+
+// circleSetPlaceholder keeps the set key alive for users in no circles, so
+// an empty circle set is still a cache hit
+const circleSetPlaceholder = "-"
+
+// notMemberRole is cached for users who are not in a circle, so repeated
+// checks by outsiders don't reach the database
+const notMemberRole = "-"
+
+type CircleMembershipCacheRepository struct {
+	client *redis.Client
+}
+
+func NewCircleMembershipCacheRepository(client *redis.Client) *CircleMembershipCacheRepository {
+	return &CircleMembershipCacheRepository{client: client}
+}
+
+func circleSetKey(userID string) string {
+	return fmt.Sprintf("user:circles:%s", userID)
+}
+
+// GetCircleSet returns the cached circle IDs and whether they were present in the cache
+func (r *CircleMembershipCacheRepository) GetCircleSet(ctx context.Context, userID string) ([]string, bool, error) {
+	members, err := r.client.SMembers(ctx, circleSetKey(userID)).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(members) == 0 {
+		return nil, false, nil
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		if member != circleSetPlaceholder {
+			ids = append(ids, member)
+		}
+	}
+	return ids, true, nil
+}
+
+func (r *CircleMembershipCacheRepository) SetCircleSet(ctx context.Context, userID string, circleIDs []string, ttl time.Duration) error {
+	key := circleSetKey(userID)
+
+	members := make([]interface{}, 0, len(circleIDs)+1)
+	members = append(members, circleSetPlaceholder)
+	for _, id := range circleIDs {
+		members = append(members, id)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.SAdd(ctx, key, members...)
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *CircleMembershipCacheRepository) InvalidateCircleSet(ctx context.Context, userID string) error {
+	return r.client.Del(ctx, circleSetKey(userID)).Err()
+}
+
+func memberRoleKey(circleID, userID string) string {
+	return fmt.Sprintf("circle:role:%s:%s", circleID, userID)
+}
+
+// GetMemberRole returns the cached role, empty for a non-member, and whether
+// it was present in the cache
+func (r *CircleMembershipCacheRepository) GetMemberRole(ctx context.Context, circleID, userID string) (domain.CircleRole, bool, error) {
+	role, err := r.client.Get(ctx, memberRoleKey(circleID, userID)).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if role == notMemberRole {
+		return "", true, nil
+	}
+	return domain.CircleRole(role), true, nil
+}
+
+func (r *CircleMembershipCacheRepository) SetMemberRole(ctx context.Context, circleID, userID string, role domain.CircleRole, ttl time.Duration) error {
+	value := string(role)
+	if value == "" {
+		value = notMemberRole
+	}
+	return r.client.Set(ctx, memberRoleKey(circleID, userID), value, ttl).Err()
+}
+
+func (r *CircleMembershipCacheRepository) InvalidateMemberRole(ctx context.Context, circleID, userID string) error {
+	return r.client.Del(ctx, memberRoleKey(circleID, userID)).Err()
+}
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure LoginRiskRepository implements repository.LoginRiskRepository
+var _ repository.LoginRiskRepository = (*LoginRiskRepository)(nil)
+
+type LoginRiskRepository struct {
+	client *redis.Client
+}
+
+// incrementAttemptsScript counts a verification attempt without recreating a
+// challenge that has expired; it returns -1 when the challenge is gone
+var incrementAttemptsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+return redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+`)
+
+func NewLoginRiskRepository(client *redis.Client) *LoginRiskRepository {
+	return &LoginRiskRepository{client: client}
+}
+
+func ipFailuresKey(ipHash string) string {
+	return fmt.Sprintf("login:ip_failures:%s", ipHash)
+}
+
+func loginChallengeKey(id string) string {
+	return fmt.Sprintf("login:challenge:%s", id)
+}
+
+// IncrementIPFailures counts a failed login from the IP and returns the count
+// within the window that started with the first failure
+func (r *LoginRiskRepository) IncrementIPFailures(ctx context.Context, ipHash string, window time.Duration) (int64, error) {
+	key := ipFailuresKey(ipHash)
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *LoginRiskRepository) GetIPFailures(ctx context.Context, ipHash string) (int64, error) {
+	count, err := r.client.Get(ctx, ipFailuresKey(ipHash)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+func (r *LoginRiskRepository) CreateLoginChallenge(ctx context.Context, challenge *domain.LoginChallenge, ttl time.Duration) error {
+	key := loginChallengeKey(challenge.ID)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key,
+		"user_id", challenge.UserID.String(),
+		"code_hash", challenge.CodeHash,
+		"reasons", strings.Join(challenge.Reasons, ","),
+		"attempts", 0,
+	)
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetLoginChallenge returns the pending challenge, or nil if it expired or
+// never existed
+func (r *LoginRiskRepository) GetLoginChallenge(ctx context.Context, id string) (*domain.LoginChallenge, error) {
+	fields, err := r.client.HGetAll(ctx, loginChallengeKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	userID, err := uuid.Parse(fields["user_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid login challenge: %w", err)
+	}
+	challenge := &domain.LoginChallenge{
+		ID:       id,
+		UserID:   userID,
+		CodeHash: fields["code_hash"],
+	}
+	if fields["reasons"] != "" {
+		challenge.Reasons = strings.Split(fields["reasons"], ",")
+	}
+	return challenge, nil
+}
+
+// IncrementLoginChallengeAttempts counts a verification attempt and returns
+// the total, or -1 if the challenge has expired
+func (r *LoginRiskRepository) IncrementLoginChallengeAttempts(ctx context.Context, id string) (int64, error) {
+	return incrementAttemptsScript.Run(ctx, r.client, []string{loginChallengeKey(id)}).Int64()
+}
+
+func (r *LoginRiskRepository) DeleteLoginChallenge(ctx context.Context, id string) error {
+	return r.client.Del(ctx, loginChallengeKey(id)).Err()
+}
@@ -7,9 +7,13 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
+// realtimeEventsChannel carries events for every WebSocket hub instance
+const realtimeEventsChannel = "channel:realtime:events"
+
 // Compile-time check to ensure RealtimeRepository implements repository.RealtimeRepository
 var _ repository.RealtimeRepository = (*RealtimeRepository)(nil)
 
@@ -21,32 +25,63 @@ func NewRealtimeRepository(client *redis.Client) *RealtimeRepository {
 	return &RealtimeRepository{client: client}
 }
 
-func (r *RealtimeRepository) PublishNewPost(ctx context.Context, postID, postType string, categories []string) error {
-	data := map[string]interface{}{
-		"post_id":    postID,
-		"type":       postType,
-		"categories": categories,
-	}
-	payload, err := json.Marshal(data)
+func (r *RealtimeRepository) PublishNewPost(ctx context.Context, postID, authorID, postType string, categories []string) error {
+	event, err := domain.NewPostRealtimeEvent(postID, authorID, postType, categories)
 	if err != nil {
 		return err
 	}
+	return r.PublishEvent(ctx, event)
+}
 
-	return r.client.Publish(ctx, "channel:post:new", payload).Err()
+func (r *RealtimeRepository) PublishNewResponse(ctx context.Context, postID, responseID, responderID string) error {
+	event, err := domain.NewResponseRealtimeEvent(postID, responseID, responderID)
+	if err != nil {
+		return err
+	}
+	return r.PublishEvent(ctx, event)
 }
 
-func (r *RealtimeRepository) PublishNewResponse(ctx context.Context, postID, responseID string) error {
-	data := map[string]interface{}{
-		"post_id":     postID,
-		"response_id": responseID,
+// PublishEvent fans an event out to every WebSocket hub instance
+func (r *RealtimeRepository) PublishEvent(ctx context.Context, event *domain.RealtimeEvent) error {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
 	}
-	payload, err := json.Marshal(data)
+
+	payload, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
 
-	channel := fmt.Sprintf("channel:post:%s:response", postID)
-	return r.client.Publish(ctx, channel, payload).Err()
+	return r.client.Publish(ctx, realtimeEventsChannel, payload).Err()
+}
+
+// SubscribeEvents calls handler for every published event until ctx is done.
+// The subscription reconnects on its own if the Redis connection drops.
+func (r *RealtimeRepository) SubscribeEvents(ctx context.Context, handler func(*domain.RealtimeEvent)) error {
+	sub := r.client.Subscribe(ctx, realtimeEventsChannel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to realtime events: %w", err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var event domain.RealtimeEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				continue
+			}
+			handler(&event)
+		}
+	}
 }
 
 func (r *RealtimeRepository) AddSupporterToPost(ctx context.Context, postID, userID string) error {
@@ -59,9 +94,17 @@ func (r *RealtimeRepository) GetSupporterCount(ctx context.Context, postID strin
 	return r.client.SCard(ctx, key).Result()
 }
 
-func (r *RealtimeRepository) IncrementViewCount(ctx context.Context, postID string) error {
-	key := fmt.Sprintf("post:view_count:%s", postID)
-	return r.client.Incr(ctx, key).Err()
+func (r *RealtimeRepository) IncrementViewCounts(ctx context.Context, views map[string]int64) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Pipeline()
+	for postID, count := range views {
+		pipe.IncrBy(ctx, fmt.Sprintf("post:view_count:%s", postID), count)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
 func (r *RealtimeRepository) GetViewCount(ctx context.Context, postID string) (int64, error) {
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// runtimeSettingsChannel tells every instance to reload runtime settings
+const runtimeSettingsChannel = "channel:runtime_settings:changed"
+
+// Compile-time check to ensure RuntimeSettingNotifier implements repository.RuntimeSettingNotifier
+var _ repository.RuntimeSettingNotifier = (*RuntimeSettingNotifier)(nil)
+
+type RuntimeSettingNotifier struct {
+	client *redis.Client
+}
+
+func NewRuntimeSettingNotifier(client *redis.Client) *RuntimeSettingNotifier {
+	return &RuntimeSettingNotifier{client: client}
+}
+
+func (n *RuntimeSettingNotifier) PublishChange(ctx context.Context, key string) error {
+	return n.client.Publish(ctx, runtimeSettingsChannel, key).Err()
+}
+
+// SubscribeChanges calls handler with the key of every published change until
+// ctx is done. The subscription reconnects on its own if the Redis connection drops.
+func (n *RuntimeSettingNotifier) SubscribeChanges(ctx context.Context, handler func(key string)) error {
+	sub := n.client.Subscribe(ctx, runtimeSettingsChannel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to runtime setting changes: %w", err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			handler(msg.Payload)
+		}
+	}
+}
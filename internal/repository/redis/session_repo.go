@@ -146,3 +146,31 @@ func (r *SessionRepository) IsUserOnline(ctx context.Context, userID string) (bo
 	}
 	return result > 0, nil
 }
+
+// SetUserOffline clears the user's online marker
+func (r *SessionRepository) SetUserOffline(ctx context.Context, userID string) error {
+	key := fmt.Sprintf("user:online:%s", userID)
+	return r.client.Del(ctx, key).Err()
+}
+
+// GetOnlineUsers returns which of the given users are online in a single round trip
+func (r *SessionRepository) GetOnlineUsers(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	online := make(map[string]bool, len(userIDs))
+	if len(userIDs) == 0 {
+		return online, nil
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = fmt.Sprintf("user:online:%s", userID)
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		online[userIDs[i]] = value != nil
+	}
+	return online, nil
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Compile-time check to ensure UserSummaryCacheRepository implements repository.UserSummaryCacheRepository
+var _ repository.UserSummaryCacheRepository = (*UserSummaryCacheRepository)(nil)
+
+type UserSummaryCacheRepository struct {
+	client *redis.Client
+}
+
+func NewUserSummaryCacheRepository(client *redis.Client) *UserSummaryCacheRepository {
+	return &UserSummaryCacheRepository{client: client}
+}
+
+func userSummaryKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user:summary:%s", userID)
+}
+
+// GetSummaries fetches every requested summary with a single MGET
+func (r *UserSummaryCacheRepository) GetSummaries(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.UserSummary, error) {
+	summaries := make(map[uuid.UUID]*domain.UserSummary, len(ids))
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = userSummaryKey(id)
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var summary domain.UserSummary
+		if err := json.Unmarshal([]byte(data), &summary); err != nil {
+			continue
+		}
+		summaries[summary.ID] = &summary
+	}
+	return summaries, nil
+}
+
+func (r *UserSummaryCacheRepository) SetSummaries(ctx context.Context, summaries []*domain.UserSummary, ttl time.Duration) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Pipeline()
+	for _, summary := range summaries {
+		data, err := json.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, userSummaryKey(summary.ID), data, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *UserSummaryCacheRepository) InvalidateSummary(ctx context.Context, userID uuid.UUID) error {
+	return r.client.Del(ctx, userSummaryKey(userID)).Err()
+}
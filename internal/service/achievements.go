@@ -0,0 +1,110 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+// Tracker metrics an achievement can be earned on
+const (
+	AchievementMetricStreakDays       = "streak_days"
+	AchievementMetricTotalDaysClean   = "total_days_clean"
+	AchievementMetricSupportGiven     = "support_given"
+	AchievementMetricSupportReceived  = "support_received"
+	AchievementMetricCravingsResisted = "cravings_resisted"
+)
+
+var achievementMetrics = map[string]bool{
+	AchievementMetricStreakDays:       true,
+	AchievementMetricTotalDaysClean:   true,
+	AchievementMetricSupportGiven:     true,
+	AchievementMetricSupportReceived:  true,
+	AchievementMetricCravingsResisted: true,
+}
+
+// AchievementDefinition describes an achievement unlocked once a tracker
+// metric reaches Threshold
+type AchievementDefinition struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Rarity      string `json:"rarity"` // common, rare, epic, legendary
+	Metric      string `json:"metric"`
+	Threshold   int    `json:"threshold"`
+}
+
+// DefaultAchievements are the built-in achievements; ACHIEVEMENTS_FILE may add to or override them
+var DefaultAchievements = []AchievementDefinition{
+	{ID: "first_week", Title: "First Week Strong", Description: "Maintained a 7-day streak", Icon: "🏆", Rarity: "common", Metric: AchievementMetricStreakDays, Threshold: 7},
+	{ID: "first_month", Title: "One Month Milestone", Description: "Completed 30 days clean", Icon: "🎖️", Rarity: "rare", Metric: AchievementMetricStreakDays, Threshold: 30},
+	{ID: "three_months", Title: "Three Months Strong", Description: "Completed 90 days clean", Icon: "🌟", Rarity: "epic", Metric: AchievementMetricStreakDays, Threshold: 90},
+	{ID: "one_year", Title: "One Year Anniversary", Description: "Completed a full year clean", Icon: "👑", Rarity: "legendary", Metric: AchievementMetricStreakDays, Threshold: 365},
+	{ID: "craving_warrior", Title: "Craving Warrior", Description: "Resisted 20 cravings", Icon: "🛡️", Rarity: "rare", Metric: AchievementMetricCravingsResisted, Threshold: 20},
+	{ID: "support_champion", Title: "Support Champion", Description: "Helped 50 community members", Icon: "🤝", Rarity: "epic", Metric: AchievementMetricSupportGiven, Threshold: 50},
+	{ID: "well_supported", Title: "Never Alone", Description: "Received support 25 times", Icon: "💛", Rarity: "common", Metric: AchievementMetricSupportReceived, Threshold: 25},
+}
+
+// Earned reports whether the tracker has reached the achievement's threshold
+func (d AchievementDefinition) Earned(tracker *domain.UserTracker) bool {
+	var value int
+	switch d.Metric {
+	case AchievementMetricStreakDays:
+		value = tracker.StreakDays
+	case AchievementMetricTotalDaysClean:
+		value = tracker.TotalDaysClean
+	case AchievementMetricSupportGiven:
+		value = tracker.SupportGiven
+	case AchievementMetricSupportReceived:
+		value = tracker.SupportReceived
+	case AchievementMetricCravingsResisted:
+		value = tracker.CravingsResisted
+	default:
+		return false
+	}
+	return value >= d.Threshold
+}
+
+// LoadAchievements returns the default achievements merged with the JSON list
+// of definitions in path, which override defaults with the same ID. An empty
+// path returns the defaults.
+func LoadAchievements(path string) ([]AchievementDefinition, error) {
+	achievements := append([]AchievementDefinition{}, DefaultAchievements...)
+	if path == "" {
+		return achievements, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read achievements: %w", err)
+	}
+
+	var custom []AchievementDefinition
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return nil, fmt.Errorf("failed to parse achievements: %w", err)
+	}
+
+	index := make(map[string]int, len(achievements))
+	for i, achievement := range achievements {
+		index[achievement.ID] = i
+	}
+	for _, achievement := range custom {
+		if achievement.ID == "" || achievement.Title == "" || achievement.Threshold <= 0 {
+			return nil, fmt.Errorf("achievement %q needs an id, title and positive threshold", achievement.ID)
+		}
+		if !achievementMetrics[achievement.Metric] {
+			return nil, fmt.Errorf("achievement %q has unknown metric %q", achievement.ID, achievement.Metric)
+		}
+
+		if i, ok := index[achievement.ID]; ok {
+			achievements[i] = achievement
+		} else {
+			index[achievement.ID] = len(achievements)
+			achievements = append(achievements, achievement)
+		}
+	}
+	return achievements, nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+const (
+	retentionCohortWeeks = 8  // Registration weeks reported, including the current one
+	contentVolumeDays    = 30 // Days of post and response volume reported
+	sosResponseDays      = 30 // Days of SOS posts whose response times are reported
+
+	platformMetricsCacheKey = "admin:platform_metrics"
+	// platformMetricsTTL outlives a missed hourly refresh so admins rarely wait on a recompute
+	platformMetricsTTL = 3 * time.Hour
+)
+
+// PlatformMetrics is a snapshot of community engagement for admins
+type PlatformMetrics struct {
+	GeneratedAt       time.Time               `json:"generated_at"`
+	DailyActiveUsers  int                     `json:"daily_active_users"`  // Posted, responded or checked in during the last 24 hours
+	WeeklyActiveUsers int                     `json:"weekly_active_users"` // Same, during the last 7 days
+	RetentionCohorts  []RetentionCohort       `json:"retention_cohorts"`
+	ContentVolume     []*domain.ContentVolume `json:"content_volume"`
+	SOSResponseTimes  SOSResponseTimes        `json:"sos_response_times"`
+}
+
+// RetentionCohort tracks the users who registered in one week
+type RetentionCohort struct {
+	WeekStart time.Time `json:"week_start"` // Monday, UTC
+	Users     int       `json:"users"`
+	Retained  []float64 `json:"retained"` // Retained[k] is the share active k weeks after registering
+}
+
+// SOSResponseTimes summarises how quickly SOS posts got their first response
+type SOSResponseTimes struct {
+	Posts         int     `json:"posts"`
+	Answered      int     `json:"answered"`
+	MedianSeconds float64 `json:"median_seconds"` // Over answered posts
+	P90Seconds    float64 `json:"p90_seconds"`
+}
+
+// AdminAnalyticsService computes platform-wide engagement metrics. Metrics are
+// refreshed on a schedule and cached, since the aggregations scan weeks of data.
+type AdminAnalyticsService struct {
+	userRepo    repository.UserRepository
+	metricsRepo repository.MetricsRepository
+	cacheRepo   repository.CacheRepository
+}
+
+func NewAdminAnalyticsService(
+	userRepo repository.UserRepository,
+	metricsRepo repository.MetricsRepository,
+	cacheRepo repository.CacheRepository,
+) *AdminAnalyticsService {
+	return &AdminAnalyticsService{
+		userRepo:    userRepo,
+		metricsRepo: metricsRepo,
+		cacheRepo:   cacheRepo,
+	}
+}
+
+// GetPlatformMetrics returns the cached metrics, computing them on a cache miss
+func (s *AdminAnalyticsService) GetPlatformMetrics(ctx context.Context) (*PlatformMetrics, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AdminAnalyticsService.GetPlatformMetrics")
+	defer span.End()
+
+	if cached, err := s.cacheRepo.Get(ctx, platformMetricsCacheKey); err == nil {
+		var metrics PlatformMetrics
+		if err := json.Unmarshal([]byte(cached), &metrics); err == nil {
+			return &metrics, nil
+		}
+	}
+	return s.RefreshPlatformMetrics(ctx)
+}
+
+// RefreshPlatformMetrics recomputes the metrics and replaces the cached copy
+func (s *AdminAnalyticsService) RefreshPlatformMetrics(ctx context.Context) (*PlatformMetrics, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AdminAnalyticsService.RefreshPlatformMetrics")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	dau, err := s.metricsRepo.CountActiveUsers(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	wau, err := s.metricsRepo.CountActiveUsers(ctx, now.AddDate(0, 0, -7))
+	if err != nil {
+		return nil, err
+	}
+
+	firstWeek := weekStart(now).AddDate(0, 0, -7*(retentionCohortWeeks-1))
+	registrations, err := s.userRepo.ListRegistrationsByWeek(ctx, firstWeek)
+	if err != nil {
+		return nil, err
+	}
+	activity, err := s.metricsRepo.ActiveUsersByWeek(ctx, firstWeek)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, err := s.metricsRepo.ContentVolumePerDay(ctx, domain.ProgressDay(now).AddDate(0, 0, -(contentVolumeDays-1)))
+	if err != nil {
+		return nil, err
+	}
+
+	sos, err := s.metricsRepo.ListSOSResponses(ctx, now.AddDate(0, 0, -sosResponseDays))
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &PlatformMetrics{
+		GeneratedAt:       now,
+		DailyActiveUsers:  dau,
+		WeeklyActiveUsers: wau,
+		RetentionCohorts:  buildRetentionCohorts(now, registrations, activity),
+		ContentVolume:     volume,
+		SOSResponseTimes:  summarizeSOSResponses(sos),
+	}
+
+	if err := s.cacheRepo.Set(ctx, platformMetricsCacheKey, metrics, platformMetricsTTL); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// weekStart returns midnight UTC on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	day := domain.ProgressDay(t)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// buildRetentionCohorts groups users by registration week, oldest first, and
+// finds the share of each cohort active in every week since, up to now's week
+func buildRetentionCohorts(now time.Time, registrations, activity map[time.Time][]string) []RetentionCohort {
+	current := weekStart(now)
+	cohorts := make([]RetentionCohort, 0, retentionCohortWeeks)
+	for i := retentionCohortWeeks - 1; i >= 0; i-- {
+		week := current.AddDate(0, 0, -7*i)
+		users := registrations[week]
+		cohort := RetentionCohort{WeekStart: week, Users: len(users), Retained: []float64{}}
+
+		members := make(map[string]bool, len(users))
+		for _, userID := range users {
+			members[userID] = true
+		}
+
+		for k := 0; k <= i; k++ {
+			rate := 0.0
+			if len(users) > 0 {
+				active := 0
+				for _, userID := range activity[week.AddDate(0, 0, 7*k)] {
+					if members[userID] {
+						active++
+					}
+				}
+				rate = float64(active) / float64(len(users))
+			}
+			cohort.Retained = append(cohort.Retained, rate)
+		}
+		cohorts = append(cohorts, cohort)
+	}
+	return cohorts
+}
+
+// summarizeSOSResponses finds the median and 90th percentile time to first response
+func summarizeSOSResponses(responses []*domain.SOSResponse) SOSResponseTimes {
+	summary := SOSResponseTimes{Posts: len(responses)}
+
+	var seconds []float64
+	for _, response := range responses {
+		if response.FirstResponseAt == nil {
+			continue
+		}
+		seconds = append(seconds, response.FirstResponseAt.Sub(response.CreatedAt).Seconds())
+	}
+	summary.Answered = len(seconds)
+	if len(seconds) == 0 {
+		return summary
+	}
+
+	sort.Float64s(seconds)
+	percentile := func(p float64) float64 {
+		return seconds[int(math.Ceil(p*float64(len(seconds))))-1]
+	}
+	summary.MedianSeconds = percentile(0.5)
+	summary.P90Seconds = percentile(0.9)
+	return summary
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+// TestBuildRetentionCohorts tests that cohorts report the share of their users active each week since registering
+func TestBuildRetentionCohorts(t *testing.T) {
+	now := time.Date(2026, 5, 20, 15, 0, 0, 0, time.UTC) // A Wednesday
+	current := time.Date(2026, 5, 18, 0, 0, 0, 0, time.UTC)
+	lastWeek := current.AddDate(0, 0, -7)
+
+	registrations := map[time.Time][]string{
+		lastWeek: {"a", "b", "c", "d"},
+		current:  {"e"},
+	}
+	activity := map[time.Time][]string{
+		lastWeek: {"a", "b", "c", "z"},
+		current:  {"a", "e", "z"},
+	}
+
+	cohorts := buildRetentionCohorts(now, registrations, activity)
+	assert.Len(t, cohorts, retentionCohortWeeks)
+	assert.Equal(t, current.AddDate(0, 0, -7*(retentionCohortWeeks-1)), cohorts[0].WeekStart)
+	assert.Equal(t, 0, cohorts[0].Users)
+	assert.Len(t, cohorts[0].Retained, retentionCohortWeeks)
+
+	assert.Equal(t, RetentionCohort{WeekStart: lastWeek, Users: 4, Retained: []float64{0.75, 0.25}}, cohorts[retentionCohortWeeks-2])
+	assert.Equal(t, RetentionCohort{WeekStart: current, Users: 1, Retained: []float64{1}}, cohorts[retentionCohortWeeks-1])
+}
+
+// TestSummarizeSOSResponses tests response time percentiles over answered SOS posts
+func TestSummarizeSOSResponses(t *testing.T) {
+	created := time.Date(2026, 5, 20, 15, 0, 0, 0, time.UTC)
+	answered := func(minutes int) *domain.SOSResponse {
+		at := created.Add(time.Duration(minutes) * time.Minute)
+		return &domain.SOSResponse{CreatedAt: created, FirstResponseAt: &at}
+	}
+
+	assert.Equal(t, SOSResponseTimes{}, summarizeSOSResponses(nil))
+
+	responses := []*domain.SOSResponse{{CreatedAt: created}}
+	for _, minutes := range []int{10, 1, 4, 2, 3, 6, 5, 8, 7, 9} {
+		responses = append(responses, answered(minutes))
+	}
+	assert.Equal(t, SOSResponseTimes{
+		Posts:         11,
+		Answered:      10,
+		MedianSeconds: 300,
+		P90Seconds:    540,
+	}, summarizeSOSResponses(responses))
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/audit"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+const (
+	defaultUserSearchLimit = 20
+	maxUserSearchLimit     = 100
+)
+
+// ErrAdminActionDenied is returned when an admin action targets the caller
+// themselves, or a user whose role the caller's role may not act on
+var ErrAdminActionDenied = errors.New("not permitted for this user")
+
+// AdminService backs the admin API: finding, banning and promoting users,
+// feature flags and platform stats. Every change is audit logged. Callers
+// check the caller's permissions first; actorID is empty for service clients.
+type AdminService struct {
+	userRepo       repository.UserRepository
+	sessionRepo    repository.SessionRepository
+	circleRepo     repository.CircleRepository
+	moderationRepo repository.ModerationRepository
+	flags          *FeatureFlagService
+	audit          *audit.Writer
+}
+
+func NewAdminService(
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	circleRepo repository.CircleRepository,
+	moderationRepo repository.ModerationRepository,
+	flags *FeatureFlagService,
+	auditWriter *audit.Writer,
+) *AdminService {
+	return &AdminService{
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		circleRepo:     circleRepo,
+		moderationRepo: moderationRepo,
+		flags:          flags,
+		audit:          auditWriter,
+	}
+}
+
+// SearchUsers returns a page of users matching the filter, including banned
+// users, and the total matched
+func (s *AdminService) SearchUsers(ctx context.Context, filter domain.UserSearch) ([]*domain.User, int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AdminService.SearchUsers")
+	defer span.End()
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUserSearchLimit
+	}
+	if filter.Limit > maxUserSearchLimit {
+		filter.Limit = maxUserSearchLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.userRepo.Search(ctx, filter)
+}
+
+// BanUser bans the user and ends their sessions. Access tokens already issued
+// stay valid until they expire.
+func (s *AdminService) BanUser(ctx context.Context, actorID string, actorRole domain.Role, userID, reason string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AdminService.BanUser")
+	defer span.End()
+
+	return s.setBanned(ctx, actorID, actorRole, userID, reason, true)
+}
+
+func (s *AdminService) UnbanUser(ctx context.Context, actorID string, actorRole domain.Role, userID, reason string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AdminService.UnbanUser")
+	defer span.End()
+
+	return s.setBanned(ctx, actorID, actorRole, userID, reason, false)
+}
+
+func (s *AdminService) setBanned(ctx context.Context, actorID string, actorRole domain.Role, userID, reason string, banned bool) error {
+	target, err := s.targetUser(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+	// Moderators may ban users, but only admins may ban staff
+	if target.Role != domain.RoleUser && actorRole != domain.RoleAdmin {
+		return fmt.Errorf("%w: only admins can ban or unban a %s", ErrAdminActionDenied, target.Role)
+	}
+	if target.IsBanned == banned {
+		return nil
+	}
+
+	if err := s.userRepo.SetBanned(ctx, target.ID, banned); err != nil {
+		return err
+	}
+
+	eventType, action := domain.AuditEventUserUnbanned, "unban"
+	if banned {
+		eventType, action = domain.AuditEventUserBanned, "ban"
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:       eventType,
+		ActorID:    actorID,
+		TargetID:   userID,
+		TargetType: "user",
+		Action:     action,
+		Reason:     reason,
+	})
+
+	if banned {
+		if err := s.sessionRepo.RevokeAllRefreshTokens(ctx, userID); err != nil {
+			return fmt.Errorf("user banned but sessions not revoked: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetUserRole changes the user's platform role. It applies once their access
+// token is refreshed.
+func (s *AdminService) SetUserRole(ctx context.Context, actorID, userID string, role domain.Role) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AdminService.SetUserRole")
+	defer span.End()
+
+	switch role {
+	case domain.RoleUser, domain.RoleModerator, domain.RoleAdmin:
+	default:
+		return fmt.Errorf("unknown role %q", role)
+	}
+
+	target, err := s.targetUser(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+	if target.Role == role {
+		return nil
+	}
+
+	if err := s.userRepo.SetRole(ctx, target.ID, role); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventRoleChanged,
+		ActorID:    actorID,
+		TargetID:   userID,
+		TargetType: "user",
+		Action:     "set_role",
+		Reason:     fmt.Sprintf("%s is now %s", target.Role, role),
+	})
+	return nil
+}
+
+// targetUser loads the user an admin action applies to. Admins can't act on
+// themselves, so nobody bans or demotes their own account by mistake.
+func (s *AdminService) targetUser(ctx context.Context, actorID, userID string) (*domain.User, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	if userID == actorID {
+		return nil, fmt.Errorf("%w: cannot act on your own account", ErrAdminActionDenied)
+	}
+	return s.userRepo.GetAnyByID(ctx, uid)
+}
+
+func (s *AdminService) ListFeatureFlags(ctx context.Context) ([]*domain.FeatureFlag, error) {
+	return s.flags.ListFlags(ctx)
+}
+
+func (s *AdminService) SetFeatureFlag(ctx context.Context, actorID, key string, enabled bool) (*domain.FeatureFlag, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AdminService.SetFeatureFlag")
+	defer span.End()
+
+	flag, err := s.flags.SetFlag(ctx, key, enabled, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventFeatureFlagSet,
+		ActorID:    actorID,
+		TargetType: "feature_flag",
+		Action:     action,
+		Reason:     key,
+	})
+	return flag, nil
+}
+
+// GetSystemStats counts users, circles and open reports
+func (s *AdminService) GetSystemStats(ctx context.Context) (*domain.SystemStats, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AdminService.GetSystemStats")
+	defer span.End()
+
+	now := time.Now().UTC()
+	users, err := s.userRepo.CountUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	circles, err := s.circleRepo.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reportStats, err := s.moderationRepo.GetModerationStats(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.SystemStats{GeneratedAt: now, Users: *users, Circles: circles}
+	for _, severity := range reportStats {
+		stats.OpenReports += severity.OpenCount
+	}
+	return stats, nil
+}
@@ -5,27 +5,35 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
 type AnalyticsService struct {
-	analyticsRepo repository.AnalyticsRepository
+	analyticsRepo   repository.AnalyticsRepository
+	freezesPerMonth int
 }
 
-func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository) *AnalyticsService {
-	return &AnalyticsService{analyticsRepo: analyticsRepo}
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, freezesPerMonth int) *AnalyticsService {
+	return &AnalyticsService{analyticsRepo: analyticsRepo, freezesPerMonth: freezesPerMonth}
 }
 
 func (s *AnalyticsService) GetTracker(ctx context.Context, userID string) (*domain.UserTracker, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AnalyticsService.GetTracker")
+	defer span.End()
+
 	return s.analyticsRepo.GetTracker(ctx, userID)
 }
 
 func (s *AnalyticsService) UpdateStreak(ctx context.Context, userID string, hadRelapse bool) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AnalyticsService.UpdateStreak")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return 0, err
 	}
-	if err := s.analyticsRepo.UpdateStreak(ctx, uid, hadRelapse); err != nil {
+	if err := s.analyticsRepo.UpdateStreak(ctx, uid, hadRelapse, s.freezesPerMonth); err != nil {
 		return 0, err
 	}
 	tracker, err := s.analyticsRepo.GetTracker(ctx, userID)
@@ -36,6 +44,9 @@ func (s *AnalyticsService) UpdateStreak(ctx context.Context, userID string, hadR
 }
 
 func (s *AnalyticsService) RecordCraving(ctx context.Context, userID string, resisted bool) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AnalyticsService.RecordCraving")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return err
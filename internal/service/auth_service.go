@@ -2,15 +2,20 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/dto"
+	"github.com/yourorg/anonymous-support/internal/pkg/audit"
 	"github.com/yourorg/anonymous-support/internal/pkg/encryption"
 	"github.com/yourorg/anonymous-support/internal/pkg/jwt"
 	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/pkg/validator"
 	"github.com/yourorg/anonymous-support/internal/repository"
 	"golang.org/x/crypto/bcrypt"
@@ -21,7 +26,10 @@ type AuthService struct {
 	sessionRepo repository.SessionRepository
 	jwtManager  *jwt.Manager
 	encManager  *encryption.Manager
-	auditRepo   repository.AuditRepository
+	audit       *audit.Writer
+	banEvasion  *BanEvasionService
+	loginRisk   *LoginRiskService
+	emailSender EmailSender // Nil when email isn't configured
 }
 
 func NewAuthService(
@@ -29,18 +37,30 @@ func NewAuthService(
 	sessionRepo repository.SessionRepository,
 	jwtManager *jwt.Manager,
 	encManager *encryption.Manager,
-	auditRepo repository.AuditRepository,
+	auditWriter *audit.Writer,
+	banEvasion *BanEvasionService,
+	loginRisk *LoginRiskService,
+	emailSender EmailSender,
 ) *AuthService {
 	return &AuthService{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
 		jwtManager:  jwtManager,
 		encManager:  encManager,
-		auditRepo:   auditRepo,
+		audit:       auditWriter,
+		banEvasion:  banEvasion,
+		loginRisk:   loginRisk,
+		emailSender: emailSender,
 	}
 }
 
 func (s *AuthService) RegisterAnonymous(ctx context.Context, username string) (*dto.AuthResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AuthService.RegisterAnonymous")
+	defer span.End()
+
+	// Auth flows read accounts they may have just written, so skip replicas
+	ctx = repository.WithPrimaryReads(ctx)
+
 	if err := validator.ValidateUsername(username); err != nil {
 		return nil, err
 	}
@@ -67,6 +87,10 @@ func (s *AuthService) RegisterAnonymous(ctx context.Context, username string) (*
 		return nil, err
 	}
 
+	// Best effort: a flagged account is reviewed by moderators, not blocked
+	_, _ = s.banEvasion.Check(ctx, user)
+	_ = s.loginRisk.RecordSuccess(ctx, user.ID)
+
 	accessToken, err := s.jwtManager.GenerateAccessToken(user)
 	if err != nil {
 		return nil, err
@@ -93,6 +117,11 @@ func (s *AuthService) RegisterAnonymous(ctx context.Context, username string) (*
 }
 
 func (s *AuthService) RegisterWithEmail(ctx context.Context, req *dto.RegisterWithEmailRequest) (*dto.AuthResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AuthService.RegisterWithEmail")
+	defer span.End()
+
+	ctx = repository.WithPrimaryReads(ctx)
+
 	if err := validator.ValidateUsername(req.Username); err != nil {
 		return nil, err
 	}
@@ -137,6 +166,10 @@ func (s *AuthService) RegisterWithEmail(ctx context.Context, req *dto.RegisterWi
 		return nil, err
 	}
 
+	// Best effort: a flagged account is reviewed by moderators, not blocked
+	_, _ = s.banEvasion.Check(ctx, user)
+	_ = s.loginRisk.RecordSuccess(ctx, user.ID)
+
 	accessToken, err := s.jwtManager.GenerateAccessToken(user)
 	if err != nil {
 		return nil, err
@@ -162,6 +195,11 @@ func (s *AuthService) RegisterWithEmail(ctx context.Context, req *dto.RegisterWi
 }
 
 func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AuthService.Login")
+	defer span.End()
+
+	ctx = repository.WithPrimaryReads(ctx)
+
 	// Login uses email, so we need to find user by email
 	// For now, check if email field contains @ (email) or not (username fallback)
 	var user *domain.User
@@ -179,17 +217,62 @@ func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.Au
 	}
 
 	if err != nil {
+		s.recordLoginFailure(ctx, "", "unknown account")
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
 	if user.IsAnonymous {
+		s.recordLoginFailure(ctx, user.ID.String(), "anonymous account")
 		return nil, fmt.Errorf("anonymous users cannot login with password")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		s.recordLoginFailure(ctx, user.ID.String(), "wrong password")
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
+	_, _ = s.banEvasion.Check(ctx, user)
+
+	// Logins from a new country, after impossible travel or from an IP with
+	// many failed logins must be confirmed with a code sent by email
+	if anomalies := s.loginAnomalies(ctx, user); len(anomalies) > 0 {
+		stepUp, err := s.startStepUp(ctx, user, anomalies)
+		if err != nil {
+			return nil, err
+		}
+		if stepUp != nil {
+			return stepUp, nil
+		}
+	}
+
+	return s.completePasswordLogin(ctx, user, "password")
+}
+
+// VerifyLoginChallenge completes a login that required step-up verification
+func (s *AuthService) VerifyLoginChallenge(ctx context.Context, challengeID, code string) (*dto.AuthResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AuthService.VerifyLoginChallenge")
+	defer span.End()
+
+	ctx = repository.WithPrimaryReads(ctx)
+
+	challenge, err := s.loginRisk.VerifyChallenge(ctx, challengeID, code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidLoginChallenge) {
+			s.recordLoginFailure(ctx, "", "invalid verification code")
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completePasswordLogin(ctx, user, "password+step_up")
+}
+
+// completePasswordLogin issues tokens for an authenticated password login
+func (s *AuthService) completePasswordLogin(ctx context.Context, user *domain.User, reason string) (*dto.AuthResponse, error) {
 	accessToken, err := s.jwtManager.GenerateAccessToken(user)
 	if err != nil {
 		return nil, err
@@ -204,6 +287,17 @@ func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.Au
 		return nil, err
 	}
 
+	_ = s.loginRisk.RecordSuccess(ctx, user.ID)
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventLogin,
+		ActorID:    user.ID.String(),
+		TargetID:   user.ID.String(),
+		TargetType: "user",
+		Action:     "login",
+		Reason:     reason,
+	})
+
 	// Decrypt email if exists
 	userDTO := dto.NewUserDTO(user)
 	if user.Email != nil {
@@ -219,10 +313,80 @@ func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.Au
 	}, nil
 }
 
+// startStepUp emails the user a one-time code for a risky login. It returns
+// nil when the user can't receive one, in which case the login is only
+// flagged rather than locking the user out.
+func (s *AuthService) startStepUp(ctx context.Context, user *domain.User, anomalies []string) (*dto.AuthResponse, error) {
+	if s.emailSender == nil || user.Email == nil {
+		s.recordSuspiciousLogin(ctx, user, anomalies, "flag")
+		return nil, nil
+	}
+	email, err := s.encManager.Decrypt(*user.Email)
+	if err != nil {
+		s.recordSuspiciousLogin(ctx, user, anomalies, "flag")
+		return nil, nil
+	}
+
+	challengeID, code, err := s.loginRisk.StartChallenge(ctx, user.ID, anomalies)
+	if err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf("We noticed a sign-in to your account from a new location or network.\n\n"+
+		"Your verification code is %s. It expires in %d minutes.\n\n"+
+		"If this wasn't you, change your password now.", code, int(loginChallengeTTL.Minutes()))
+	if err := s.emailSender.SendEmail(ctx, email, "Confirm your sign-in", body); err != nil {
+		return nil, fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	s.recordSuspiciousLogin(ctx, user, anomalies, "step_up")
+	return &dto.AuthResponse{
+		StepUpRequired: true,
+		ChallengeID:    challengeID,
+	}, nil
+}
+
+// loginAnomalies returns what is unusual about the user's login. Assessment
+// is best effort: logins aren't blocked when the risk stores are unavailable.
+func (s *AuthService) loginAnomalies(ctx context.Context, user *domain.User) []string {
+	anomalies, err := s.loginRisk.Assess(ctx, user.ID)
+	if err != nil {
+		return nil
+	}
+	return anomalies
+}
+
+// recordSuspiciousLogin audits an anomalous login and the action taken:
+// step_up, reauth or flag
+func (s *AuthService) recordSuspiciousLogin(ctx context.Context, user *domain.User, anomalies []string, action string) {
+	for _, anomaly := range anomalies {
+		metrics.AuthLoginAnomaliesTotal.WithLabelValues(anomaly, action).Inc()
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventSuspiciousLogin,
+		ActorID:    user.ID.String(),
+		TargetID:   user.ID.String(),
+		TargetType: "user",
+		Action:     action,
+		Reason:     strings.Join(anomalies, ","),
+	})
+}
+
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*dto.AuthResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AuthService.RefreshToken")
+	defer span.End()
+
+	ctx = repository.WithPrimaryReads(ctx)
+
 	// 1. Validate the refresh token JWT signature and expiry
 	userID, err := s.jwtManager.ValidateRefreshToken(refreshToken)
 	if err != nil {
+		s.audit.Record(ctx, audit.Event{
+			Type:   domain.AuditEventRefreshToken,
+			Action: "refresh_token",
+			Reason: "invalid refresh token",
+			Err:    err,
+		})
 		return nil, fmt.Errorf("invalid refresh token: %w", err)
 	}
 
@@ -236,7 +400,16 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		// Token reuse detected! This could be a token theft attempt.
 		// Revoke all refresh tokens for this user as a security measure.
 		_ = s.sessionRepo.RevokeAllRefreshTokens(ctx, userID)
-		return nil, fmt.Errorf("token reuse detected, all sessions revoked for security")
+		err := fmt.Errorf("token reuse detected, all sessions revoked for security")
+		s.audit.Record(ctx, audit.Event{
+			Type:       domain.AuditEventTokenRevoked,
+			TargetID:   userID,
+			TargetType: "user",
+			Action:     "revoke_all_sessions",
+			Reason:     "refresh token reuse",
+			Err:        err,
+		})
+		return nil, err
 	}
 
 	// 3. Get user details
@@ -255,6 +428,22 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, fmt.Errorf("user is banned")
 	}
 
+	// A session moving to a new country or travelling impossibly fast must log
+	// in again. A refresh token already proves a past login, so a busy IP alone
+	// doesn't end it, and anonymous users have no other credential to log in
+	// with, so they are only flagged.
+	if anomalies := s.loginAnomalies(ctx, user); len(anomalies) > 0 {
+		geoAnomaly := slices.ContainsFunc(anomalies, func(anomaly string) bool {
+			return anomaly != LoginAnomalyIPReputation
+		})
+		if geoAnomaly && !user.IsAnonymous {
+			_ = s.sessionRepo.RevokeRefreshToken(ctx, userID, refreshToken)
+			s.recordSuspiciousLogin(ctx, user, anomalies, "reauth")
+			return nil, fmt.Errorf("re-authentication required")
+		}
+		s.recordSuspiciousLogin(ctx, user, anomalies, "flag")
+	}
+
 	// 4. Generate new token pair (rotation)
 	newAccessToken, err := s.jwtManager.GenerateAccessToken(user)
 	if err != nil {
@@ -276,6 +465,16 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, fmt.Errorf("failed to store new token: %w", err)
 	}
 
+	_ = s.loginRisk.RecordSuccess(ctx, user.ID)
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventRefreshToken,
+		ActorID:    userID,
+		TargetID:   userID,
+		TargetType: "user",
+		Action:     "refresh_token",
+	})
+
 	// 7. Return the new token pair
 	userDTO := dto.NewUserDTO(user)
 	if user.Email != nil {
@@ -292,10 +491,43 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 }
 
 func (s *AuthService) Logout(ctx context.Context, userID uuid.UUID) error {
-	return s.sessionRepo.DeleteRefreshToken(ctx, userID.String())
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AuthService.Logout")
+	defer span.End()
+
+	if err := s.sessionRepo.DeleteRefreshToken(ctx, userID.String()); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventLogout,
+		ActorID:    userID.String(),
+		TargetID:   userID.String(),
+		TargetType: "user",
+		Action:     "logout",
+	})
+	return nil
+}
+
+// recordLoginFailure audits a failed password login and counts it against the
+// client IP's reputation. userID is empty when no account matched; the
+// submitted identifier is never recorded.
+func (s *AuthService) recordLoginFailure(ctx context.Context, userID, reason string) {
+	_ = s.loginRisk.RecordFailure(ctx)
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventLoginFailed,
+		TargetID:   userID,
+		TargetType: "user",
+		Action:     "login",
+		Err:        errors.New(reason),
+	})
 }
 
 func (s *AuthService) HandleOAuthLogin(ctx context.Context, provider, providerUserID, email, name string) (*dto.AuthResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AuthService.HandleOAuthLogin")
+	defer span.End()
+
+	ctx = repository.WithPrimaryReads(ctx)
+
 	// Try to find existing user by email (OAuth accounts have verified emails)
 	var user *domain.User
 	var err error
@@ -333,7 +565,14 @@ func (s *AuthService) HandleOAuthLogin(ctx context.Context, provider, providerUs
 		metrics.AuthAttemptsTotal.WithLabelValues("oauth_register", provider, "success").Inc()
 	} else {
 		metrics.AuthAttemptsTotal.WithLabelValues("oauth_login", provider, "success").Inc()
+
+		// The provider has already authenticated the user, so anomalies are
+		// only flagged
+		if anomalies := s.loginAnomalies(ctx, user); len(anomalies) > 0 {
+			s.recordSuspiciousLogin(ctx, user, anomalies, "flag")
+		}
 	}
+	_ = s.loginRisk.RecordSuccess(ctx, user.ID)
 
 	// Generate tokens
 	accessToken, err := s.jwtManager.GenerateAccessToken(user)
@@ -350,6 +589,15 @@ func (s *AuthService) HandleOAuthLogin(ctx context.Context, provider, providerUs
 		return nil, err
 	}
 
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventLogin,
+		ActorID:    user.ID.String(),
+		TargetID:   user.ID.String(),
+		TargetType: "user",
+		Action:     "login",
+		Reason:     "oauth:" + provider,
+	})
+
 	userDTO := dto.NewUserDTO(user)
 	userDTO.Email = email // Use plaintext email
 
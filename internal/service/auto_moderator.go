@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/abuse"
+	"github.com/yourorg/anonymous-support/internal/pkg/events"
+	"github.com/yourorg/anonymous-support/internal/pkg/moderator"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// AutoModerator applies auto-moderation decisions to content
+type AutoModerator struct {
+	engine        *moderator.RulesEngine
+	contentFilter *moderator.ContentFilter
+	abuseDetector *abuse.AbuseDetector
+	postRepo      repository.PostRepository
+	modRepo       repository.ModerationRepository
+	notifier      *NotificationService
+	bus           events.EventBus
+}
+
+func NewAutoModerator(
+	engine *moderator.RulesEngine,
+	contentFilter *moderator.ContentFilter,
+	postRepo repository.PostRepository,
+	modRepo repository.ModerationRepository,
+	notifier *NotificationService,
+	bus events.EventBus,
+) *AutoModerator {
+	return &AutoModerator{
+		engine:        engine,
+		contentFilter: contentFilter,
+		abuseDetector: abuse.NewAbuseDetector(),
+		postRepo:      postRepo,
+		modRepo:       modRepo,
+		notifier:      notifier,
+		bus:           bus,
+	}
+}
+
+// ModeratePost gathers signals for a post, evaluates them and applies the resulting actions
+func (m *AutoModerator) ModeratePost(ctx context.Context, post *domain.Post) (*moderator.Decision, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "AutoModerator.ModeratePost")
+	defer span.End()
+
+	if !m.engine.Enabled() {
+		return &moderator.Decision{}, nil
+	}
+
+	postID := post.ID.Hex()
+	openCase, err := m.modRepo.GetOpenReportForContent(ctx, "post", postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load open case: %w", err)
+	}
+
+	signals := moderator.Signals{
+		ToxicityScore: m.contentFilter.ToxicityScore(post.Content),
+	}
+	if openCase != nil {
+		signals.ReportScore = openCase.ReportScore
+	}
+	if result := m.abuseDetector.CheckPost(ctx, post, nil); result.IsAbuse {
+		signals.AbuseSeverity = result.Severity
+	}
+
+	decision := m.engine.Evaluate(signals)
+	if !decision.Triggered {
+		return decision, nil
+	}
+
+	reason := strings.Join(decision.Reasons, ", ")
+
+	// Re-evaluations, e.g. on every later report, must not hide or notify twice
+	hidden := false
+	if decision.Has(moderator.ActionHide) && post.ModerationState == domain.ModerationStateVisible {
+		flags := append(post.ModerationFlags, "auto_moderation")
+		if err := m.postRepo.SetModerationState(ctx, postID, domain.ModerationStateQuarantined, flags); err != nil {
+			return nil, fmt.Errorf("failed to quarantine post: %w", err)
+		}
+		post.ModerationState = domain.ModerationStateQuarantined
+		post.ModerationFlags = flags
+		hidden = true
+		_ = events.Publish(ctx, m.bus, domain.EventPostWithdrawn, domain.PostWithdrawnEvent{PostID: postID})
+	}
+
+	// Reports are merged into a single case, so only open one if none is pending
+	if decision.Has(moderator.ActionOpenCase) && openCase == nil {
+		report := &domain.ContentReport{
+			ID:          uuid.New(),
+			Source:      domain.ReportSourceAuto,
+			ContentType: "post",
+			ContentID:   postID,
+			Reason:      "auto_moderation",
+			Description: reason,
+			Status:      "pending",
+		}
+		if err := m.modRepo.CreateReport(ctx, report); err != nil {
+			return nil, fmt.Errorf("failed to open moderation case: %w", err)
+		}
+	}
+
+	if hidden && decision.Has(moderator.ActionNotifyAuthor) {
+		_ = m.notifier.SendNotification(ctx, post.UserID, "Post Under Review",
+			"Your post has been hidden while our moderators review it")
+	}
+
+	return decision, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/moderator"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"github.com/yourorg/anonymous-support/internal/repository/memory"
+	"github.com/yourorg/anonymous-support/internal/testutil/factory"
+)
+
+// openCaseRepo serves a single open case; other methods are unused here
+type openCaseRepo struct {
+	repository.ModerationRepository
+	openCase *domain.ContentReport
+}
+
+func (r *openCaseRepo) GetOpenReportForContent(ctx context.Context, contentType, contentID string) (*domain.ContentReport, error) {
+	return r.openCase, nil
+}
+
+type recordingNotifications struct {
+	repository.NotificationRepository
+	created []*domain.Notification
+}
+
+func (r *recordingNotifications) Create(ctx context.Context, notification *domain.Notification) error {
+	r.created = append(r.created, notification)
+	return nil
+}
+
+type defaultPreferences struct {
+	repository.NotificationPreferencesRepository
+}
+
+func (defaultPreferences) Get(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	return domain.DefaultNotificationPreferences(userID), nil
+}
+
+func TestAutoModerator_NotifiesAuthorOnceWhenReportsHidePost(t *testing.T) {
+	ctx := context.Background()
+	posts := memory.NewPostRepository()
+	post := factory.Post()
+	require.NoError(t, posts.Create(ctx, post))
+
+	modRepo := &openCaseRepo{openCase: &domain.ContentReport{ID: uuid.New(), Status: "pending"}}
+	notifications := &recordingNotifications{}
+	engine := moderator.NewRulesEngine(moderator.Rules{
+		Enabled:         true,
+		ReportThreshold: 3,
+		Actions:         []moderator.Action{moderator.ActionHide, moderator.ActionNotifyAuthor, moderator.ActionOpenCase},
+	})
+	m := NewAutoModerator(engine, moderator.NewContentFilter("low"), posts, modRepo,
+		NewNotificationService(notifications, defaultPreferences{}, nil, nil, nil, nil, nil, nil), nil)
+
+	// Every report past the threshold re-evaluates the post
+	for _, score := range []float64{3, 4} {
+		modRepo.openCase.ReportScore = score
+		decision, err := m.ModeratePost(ctx, post)
+		require.NoError(t, err)
+		require.True(t, decision.Triggered)
+	}
+
+	stored, err := posts.GetByID(ctx, post.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, domain.ModerationStateQuarantined, stored.ModerationState)
+	assert.Len(t, notifications.created, 1)
+}
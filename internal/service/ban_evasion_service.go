@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// BanEvasionService records hashed client fingerprints and flags new accounts
+// that share a fingerprint with a recently active banned account
+type BanEvasionService struct {
+	fingerprintRepo repository.FingerprintRepository
+	modRepo         repository.ModerationRepository
+	hasher          *fingerprint.Hasher
+	window          time.Duration
+}
+
+func NewBanEvasionService(
+	fingerprintRepo repository.FingerprintRepository,
+	modRepo repository.ModerationRepository,
+	hasher *fingerprint.Hasher,
+	window time.Duration,
+) *BanEvasionService {
+	return &BanEvasionService{
+		fingerprintRepo: fingerprintRepo,
+		modRepo:         modRepo,
+		hasher:          hasher,
+		window:          window,
+	}
+}
+
+// Check records the fingerprints on the request context for the user and, if the
+// account was created within the detection window, opens a moderation case when
+// it matches a banned account. Returns true if the account was flagged.
+func (s *BanEvasionService) Check(ctx context.Context, user *domain.User) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "BanEvasionService.Check")
+	defer span.End()
+
+	info := fingerprint.FromContext(ctx)
+
+	hashes := []string{}
+	for kind, value := range map[fingerprint.Kind]string{
+		fingerprint.KindIP:     info.IP,
+		fingerprint.KindDevice: info.DeviceID,
+	} {
+		hash := s.hasher.Hash(kind, value)
+		if hash == "" {
+			continue
+		}
+		if err := s.fingerprintRepo.RecordFingerprint(ctx, user.ID, string(kind), hash); err != nil {
+			return false, fmt.Errorf("failed to record fingerprint: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	// Only new accounts are candidates for evasion
+	if len(hashes) == 0 || (!user.CreatedAt.IsZero() && time.Since(user.CreatedAt) > s.window) {
+		return false, nil
+	}
+
+	bannedIDs, err := s.fingerprintRepo.FindBannedUsersByFingerprint(ctx, hashes, user.ID, time.Now().Add(-s.window))
+	if err != nil {
+		return false, err
+	}
+	if len(bannedIDs) == 0 {
+		return false, nil
+	}
+
+	openCase, err := s.modRepo.GetOpenReportForContent(ctx, "user", user.ID.String())
+	if err != nil {
+		return false, err
+	}
+	if openCase != nil {
+		return true, nil
+	}
+
+	matches := make([]string, len(bannedIDs))
+	for i, id := range bannedIDs {
+		matches[i] = id.String()
+	}
+
+	report := &domain.ContentReport{
+		ID:          uuid.New(),
+		Source:      domain.ReportSourceAuto,
+		ContentType: "user",
+		ContentID:   user.ID.String(),
+		Reason:      "ban_evasion",
+		Description: fmt.Sprintf("Shares a device or IP fingerprint with banned account(s): %s", strings.Join(matches, ", ")),
+		Status:      "pending",
+	}
+	if err := s.modRepo.CreateReport(ctx, report); err != nil {
+		return false, fmt.Errorf("failed to open ban evasion case: %w", err)
+	}
+
+	return true, nil
+}
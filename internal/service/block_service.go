@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+const blockSetTTL = 10 * time.Minute
+
+// BlockService manages user blocks and answers "should A see B" questions
+// from a Redis-cached block set
+type BlockService struct {
+	modRepo    repository.ModerationRepository
+	blockCache repository.BlockCacheRepository
+}
+
+func NewBlockService(modRepo repository.ModerationRepository, blockCache repository.BlockCacheRepository) *BlockService {
+	return &BlockService{
+		modRepo:    modRepo,
+		blockCache: blockCache,
+	}
+}
+
+func (s *BlockService) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "BlockService.BlockUser")
+	defer span.End()
+
+	if blockerID == blockedID {
+		return fmt.Errorf("cannot block yourself")
+	}
+
+	blocker, err := uuid.Parse(blockerID)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := uuid.Parse(blockedID)
+	if err != nil {
+		return err
+	}
+
+	exists, err := s.modRepo.IsBlocked(ctx, blocker, blocked)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := s.modRepo.CreateBlock(ctx, blocker, blocked); err != nil {
+			return err
+		}
+	}
+
+	return s.blockCache.InvalidateBlockSet(ctx, blockerID, blockedID)
+}
+
+func (s *BlockService) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "BlockService.UnblockUser")
+	defer span.End()
+
+	blocker, err := uuid.Parse(blockerID)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := uuid.Parse(blockedID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.modRepo.RemoveBlock(ctx, blocker, blocked); err != nil {
+		return err
+	}
+
+	return s.blockCache.InvalidateBlockSet(ctx, blockerID, blockedID)
+}
+
+// GetBlockSet returns the IDs of users that userID has blocked or been blocked by
+func (s *BlockService) GetBlockSet(ctx context.Context, userID string) (map[string]bool, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "BlockService.GetBlockSet")
+	defer span.End()
+
+	blockSet := make(map[string]bool)
+	if userID == "" {
+		return blockSet, nil
+	}
+
+	ids, found, err := s.blockCache.GetBlockSet(ctx, userID)
+	if err == nil && found {
+		for _, id := range ids {
+			blockSet[id] = true
+		}
+		return blockSet, nil
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedIDs, err := s.modRepo.GetBlockedUserIDs(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	ids = make([]string, len(blockedIDs))
+	for i, id := range blockedIDs {
+		ids[i] = id.String()
+		blockSet[ids[i]] = true
+	}
+	_ = s.blockCache.SetBlockSet(ctx, userID, ids, blockSetTTL)
+
+	return blockSet, nil
+}
+
+// IsBlockedEitherWay reports whether either user has blocked the other
+func (s *BlockService) IsBlockedEitherWay(ctx context.Context, userA, userB string) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "BlockService.IsBlockedEitherWay")
+	defer span.End()
+
+	blockSet, err := s.GetBlockSet(ctx, userA)
+	if err != nil {
+		return false, err
+	}
+	return blockSet[userB], nil
+}
+
+// FilterPosts drops posts written by users in the viewer's block set
+func (s *BlockService) FilterPosts(ctx context.Context, viewerID string, posts []*domain.Post) ([]*domain.Post, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "BlockService.FilterPosts")
+	defer span.End()
+
+	blockSet, err := s.GetBlockSet(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(blockSet) == 0 {
+		return posts, nil
+	}
+
+	filtered := make([]*domain.Post, 0, len(posts))
+	for _, post := range posts {
+		if !blockSet[post.UserID] {
+			filtered = append(filtered, post)
+		}
+	}
+	return filtered, nil
+}
+
+// FilterResponses drops responses written by users in the viewer's block set
+func (s *BlockService) FilterResponses(ctx context.Context, viewerID string, responses []*domain.SupportResponse) ([]*domain.SupportResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "BlockService.FilterResponses")
+	defer span.End()
+
+	blockSet, err := s.GetBlockSet(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(blockSet) == 0 {
+		return responses, nil
+	}
+
+	filtered := make([]*domain.SupportResponse, 0, len(responses))
+	for _, response := range responses {
+		if !blockSet[response.UserID] {
+			filtered = append(filtered, response)
+		}
+	}
+	return filtered, nil
+}
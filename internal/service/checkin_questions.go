@@ -0,0 +1,166 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+// Check-in question types
+const (
+	CheckInQuestionScale   = "scale"   // Whole number between Min and Max
+	CheckInQuestionBoolean = "boolean" // Yes or no
+	CheckInQuestionChoice  = "choice"  // One of Choices
+)
+
+// checkInTrendDays is how many days of answers the dashboard's trends cover
+const checkInTrendDays = 30
+
+// CheckInQuestion is an optional question asked at each daily check-in
+type CheckInQuestion struct {
+	ID      string   `json:"id"`
+	Prompt  string   `json:"prompt"`
+	Type    string   `json:"type"`
+	Min     int      `json:"min,omitempty"` // Scale only
+	Max     int      `json:"max,omitempty"` // Scale only
+	Choices []string `json:"choices,omitempty"`
+}
+
+// DefaultCheckInQuestions are asked unless CHECKIN_QUESTIONS_FILE replaces them
+var DefaultCheckInQuestions = []CheckInQuestion{
+	{ID: "sleep_quality", Prompt: "How well did you sleep last night?", Type: CheckInQuestionScale, Min: 1, Max: 5},
+	{ID: "exercised", Prompt: "Did you move your body today?", Type: CheckInQuestionBoolean},
+	{ID: "connected", Prompt: "Did you connect with someone supportive today?", Type: CheckInQuestionBoolean},
+	{ID: "main_stress", Prompt: "What's weighing on you most today?", Type: CheckInQuestionChoice,
+		Choices: []string{"work", "family", "relationships", "health", "money", "nothing"}},
+}
+
+// validate checks the question definition is usable
+func (q CheckInQuestion) validate() error {
+	if q.ID == "" || q.Prompt == "" {
+		return fmt.Errorf("check-in question %q needs an id and prompt", q.ID)
+	}
+	switch q.Type {
+	case CheckInQuestionScale:
+		if q.Min >= q.Max {
+			return fmt.Errorf("check-in question %q needs min below max", q.ID)
+		}
+	case CheckInQuestionBoolean:
+	case CheckInQuestionChoice:
+		if len(q.Choices) < 2 {
+			return fmt.Errorf("check-in question %q needs at least two choices", q.ID)
+		}
+	default:
+		return fmt.Errorf("check-in question %q has unknown type %q", q.ID, q.Type)
+	}
+	return nil
+}
+
+// validateAnswer checks an answer matches the question's type and range
+func (q CheckInQuestion) validateAnswer(answer domain.CheckInAnswer) error {
+	switch q.Type {
+	case CheckInQuestionScale:
+		if answer.Scale == nil || *answer.Scale < q.Min || *answer.Scale > q.Max {
+			return fmt.Errorf("answer to %q must be between %d and %d", q.ID, q.Min, q.Max)
+		}
+	case CheckInQuestionBoolean:
+		if answer.Boolean == nil {
+			return fmt.Errorf("answer to %q must be yes or no", q.ID)
+		}
+	case CheckInQuestionChoice:
+		if answer.Choice != nil {
+			for _, choice := range q.Choices {
+				if choice == *answer.Choice {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("answer to %q must be one of its choices", q.ID)
+	}
+	return nil
+}
+
+// LoadCheckInQuestions returns the JSON list of questions in path, or the
+// defaults when path is empty. A file replaces the defaults entirely.
+func LoadCheckInQuestions(path string) ([]CheckInQuestion, error) {
+	if path == "" {
+		return append([]CheckInQuestion{}, DefaultCheckInQuestions...), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read check-in questions: %w", err)
+	}
+
+	var questions []CheckInQuestion
+	if err := json.Unmarshal(data, &questions); err != nil {
+		return nil, fmt.Errorf("failed to parse check-in questions: %w", err)
+	}
+
+	seen := make(map[string]bool, len(questions))
+	for _, question := range questions {
+		if err := question.validate(); err != nil {
+			return nil, err
+		}
+		if seen[question.ID] {
+			return nil, fmt.Errorf("duplicate check-in question %q", question.ID)
+		}
+		seen[question.ID] = true
+	}
+	return questions, nil
+}
+
+// CheckInTrend summarises the answers to one check-in question
+type CheckInTrend struct {
+	QuestionID string         `json:"question_id"`
+	Prompt     string         `json:"prompt"`
+	Type       string         `json:"type"`
+	Responses  int            `json:"responses"`
+	Average    float64        `json:"average,omitempty"`   // Scale questions
+	YesShare   float64        `json:"yes_share,omitempty"` // Boolean questions
+	Choices    map[string]int `json:"choices,omitempty"`   // Choice questions; answers per choice
+}
+
+// calculateCheckInTrends summarises the answers in checkIns for each question
+func calculateCheckInTrends(questions []CheckInQuestion, checkIns []*domain.DailyCheckIn) []CheckInTrend {
+	trends := make([]CheckInTrend, 0, len(questions))
+	for _, question := range questions {
+		trend := CheckInTrend{QuestionID: question.ID, Prompt: question.Prompt, Type: question.Type}
+		if question.Type == CheckInQuestionChoice {
+			trend.Choices = make(map[string]int, len(question.Choices))
+		}
+
+		total := 0
+		for _, checkIn := range checkIns {
+			for _, answer := range checkIn.Answers {
+				if answer.QuestionID != question.ID || question.validateAnswer(answer) != nil {
+					continue
+				}
+				trend.Responses++
+				switch question.Type {
+				case CheckInQuestionScale:
+					total += *answer.Scale
+				case CheckInQuestionBoolean:
+					if *answer.Boolean {
+						total++
+					}
+				case CheckInQuestionChoice:
+					trend.Choices[*answer.Choice]++
+				}
+			}
+		}
+
+		if trend.Responses > 0 {
+			switch question.Type {
+			case CheckInQuestionScale:
+				trend.Average = float64(total) / float64(trend.Responses)
+			case CheckInQuestionBoolean:
+				trend.YesShare = float64(total) / float64(trend.Responses)
+			}
+		}
+		trends = append(trends, trend)
+	}
+	return trends
+}
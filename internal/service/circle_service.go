@@ -2,39 +2,87 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/audit"
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/pkg/transaction"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
+const (
+	circleSetTTL  = 10 * time.Minute
+	circleRoleTTL = 10 * time.Minute
+)
+
+// ErrCirclePermissionDenied is returned when the user's circle role, or
+// platform role, doesn't allow the action in that circle
+var ErrCirclePermissionDenied = errors.New("not permitted in this circle")
+
 type CircleService struct {
-	circleRepo repository.CircleRepository
-	postRepo   repository.PostRepository
-	txManager  *transaction.Manager
+	circleRepo      repository.CircleRepository
+	postRepo        repository.PostRepository
+	sensitive       *SensitiveContent
+	membershipCache repository.CircleMembershipCacheRepository
+	realtimeRepo    repository.RealtimeRepository
+	txManager       *transaction.Manager
+	presence        *PresenceService
+	authorizer      *authz.Authorizer
+	entitlements    EntitlementChecker
+	audit           *audit.Writer
 }
 
 func NewCircleService(
 	circleRepo repository.CircleRepository,
 	postRepo repository.PostRepository,
+	sensitive *SensitiveContent,
+	membershipCache repository.CircleMembershipCacheRepository,
+	realtimeRepo repository.RealtimeRepository,
 	txManager *transaction.Manager,
+	presence *PresenceService,
+	authorizer *authz.Authorizer,
+	entitlements EntitlementChecker,
+	auditWriter *audit.Writer,
 ) *CircleService {
 	return &CircleService{
-		circleRepo: circleRepo,
-		postRepo:   postRepo,
-		txManager:  txManager,
+		circleRepo:      circleRepo,
+		postRepo:        postRepo,
+		sensitive:       sensitive,
+		membershipCache: membershipCache,
+		realtimeRepo:    realtimeRepo,
+		txManager:       txManager,
+		presence:        presence,
+		authorizer:      authorizer,
+		entitlements:    entitlements,
+		audit:           auditWriter,
 	}
 }
 
 func (s *CircleService) CreateCircle(ctx context.Context, userID, name, description, category string, maxMembers int, isPrivate bool) (string, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.CreateCircle")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return "", err
 	}
 
+	if maxMembers > domain.FreeCircleMaxMembers {
+		allowed, err := s.entitlements.HasEntitlement(ctx, userID, domain.EntitlementLargeCircles)
+		if err != nil {
+			return "", err
+		}
+		if !allowed {
+			return "", fmt.Errorf("%w: circles over %d members", ErrEntitlementRequired, domain.FreeCircleMaxMembers)
+		}
+	}
+
 	circleID := uuid.New()
 
 	// Use transaction to ensure atomicity of circle creation and auto-join
@@ -48,10 +96,10 @@ func (s *CircleService) CreateCircle(ctx context.Context, userID, name, descript
 			return fmt.Errorf("failed to create circle: %w", err)
 		}
 
-		// Auto-join creator to circle
+		// Auto-join creator to circle as its owner
 		membershipQuery := `
-			INSERT INTO circle_memberships (circle_id, user_id, joined_at)
-			VALUES ($1, $2, NOW())
+			INSERT INTO circle_memberships (circle_id, user_id, role, joined_at)
+			VALUES ($1, $2, 'owner', NOW())
 		`
 		if _, err := tx.ExecContext(ctx, membershipQuery, circleID, uid); err != nil {
 			return fmt.Errorf("failed to join creator to circle: %w", err)
@@ -64,10 +112,15 @@ func (s *CircleService) CreateCircle(ctx context.Context, userID, name, descript
 		return "", err
 	}
 
+	_ = s.membershipCache.InvalidateCircleSet(ctx, userID)
+	_ = s.membershipCache.InvalidateMemberRole(ctx, circleID.String(), userID)
 	return circleID.String(), nil
 }
 
 func (s *CircleService) JoinCircle(ctx context.Context, userID, circleID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.JoinCircle")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return err
@@ -79,10 +132,10 @@ func (s *CircleService) JoinCircle(ctx context.Context, userID, circleID string)
 	}
 
 	// Use transaction with row locking to prevent race conditions
-	return s.txManager.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+	err = s.txManager.WithTransaction(ctx, func(tx *sqlx.Tx) error {
 		// Lock the circle row for update and check capacity
 		var memberCount, maxMembers int
-		lockQuery := `SELECT member_count, max_members FROM circles WHERE id = $1 FOR UPDATE`
+		lockQuery := `SELECT member_count, max_members FROM circles WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
 		if err := tx.QueryRowContext(ctx, lockQuery, cid).Scan(&memberCount, &maxMembers); err != nil {
 			return fmt.Errorf("circle not found: %w", err)
 		}
@@ -117,9 +170,19 @@ func (s *CircleService) JoinCircle(ctx context.Context, userID, circleID string)
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	_ = s.membershipCache.InvalidateCircleSet(ctx, userID)
+	_ = s.membershipCache.InvalidateMemberRole(ctx, circleID, userID)
+	return nil
 }
 
 func (s *CircleService) LeaveCircle(ctx context.Context, userID, circleID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.LeaveCircle")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return err
@@ -130,11 +193,170 @@ func (s *CircleService) LeaveCircle(ctx context.Context, userID, circleID string
 		return err
 	}
 
+	// The owner hands the circle over first, so it is never left without one
+	role, err := s.CircleRole(ctx, userID, circleID)
+	if err != nil {
+		return err
+	}
+	if role == domain.CircleRoleOwner {
+		return fmt.Errorf("the owner must hand ownership to another member before leaving")
+	}
+
+	if err := s.removeMembership(ctx, cid, uid); err != nil {
+		return err
+	}
+	s.membershipEnded(ctx, userID, circleID)
+	return nil
+}
+
+// RemoveMember removes a member from the circle. It needs circle moderation
+// rights and a higher circle role than the member, or a platform role that
+// manages circles.
+func (s *CircleService) RemoveMember(ctx context.Context, actorID string, actorRole domain.Role, circleID, targetID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.RemoveMember")
+	defer span.End()
+
+	cid, err := uuid.Parse(circleID)
+	if err != nil {
+		return err
+	}
+	tid, err := uuid.Parse(targetID)
+	if err != nil {
+		return err
+	}
+
+	targetRole, err := s.authorizeMemberAction(ctx, actorID, actorRole, cid, tid, authz.PermissionModerateCircle)
+	if err != nil {
+		return err
+	}
+
+	if err := s.removeMembership(ctx, cid, tid); err != nil {
+		return err
+	}
+	s.membershipEnded(ctx, targetID, circleID)
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventCircleMemberRemoved,
+		ActorID:    actorID,
+		TargetID:   circleID,
+		TargetType: "circle",
+		Action:     "remove_member",
+		Reason:     fmt.Sprintf("removed %s %s", targetRole, targetID),
+	})
+	return nil
+}
+
+// SetMemberRole changes a member's role in the circle. It needs circle
+// management rights. Making a member the owner hands over ownership, and the
+// previous owner becomes a moderator.
+func (s *CircleService) SetMemberRole(ctx context.Context, actorID string, actorRole domain.Role, circleID, targetID string, role domain.CircleRole) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.SetMemberRole")
+	defer span.End()
+
+	if !authz.IsCircleRole(role) {
+		return fmt.Errorf("unknown circle role %q", role)
+	}
+	cid, err := uuid.Parse(circleID)
+	if err != nil {
+		return err
+	}
+	tid, err := uuid.Parse(targetID)
+	if err != nil {
+		return err
+	}
+
+	targetRole, err := s.authorizeMemberAction(ctx, actorID, actorRole, cid, tid, authz.PermissionManageCircle)
+	if err != nil {
+		return err
+	}
+	if targetRole == role {
+		return nil
+	}
+
+	var previousOwners []string
+	err = s.txManager.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		if role == domain.CircleRoleOwner {
+			demoteQuery := `
+				UPDATE circle_memberships SET role = 'moderator'
+				WHERE circle_id = $1 AND role = 'owner'
+				RETURNING user_id
+			`
+			if err := tx.SelectContext(ctx, &previousOwners, demoteQuery, cid); err != nil {
+				return fmt.Errorf("failed to demote previous owner: %w", err)
+			}
+		}
+
+		// The owner check guards against a concurrent ownership transfer to the target
+		updateQuery := `UPDATE circle_memberships SET role = $3 WHERE circle_id = $1 AND user_id = $2 AND role <> 'owner'`
+		result, err := tx.ExecContext(ctx, updateQuery, cid, tid, role)
+		if err != nil {
+			return fmt.Errorf("failed to update member role: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("user is not a member of this circle")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = s.membershipCache.InvalidateMemberRole(ctx, circleID, targetID)
+	for _, ownerID := range previousOwners {
+		_ = s.membershipCache.InvalidateMemberRole(ctx, circleID, ownerID)
+	}
+
+	eventType := domain.AuditEventCircleRoleChanged
+	if role == domain.CircleRoleOwner {
+		eventType = domain.AuditEventCircleOwnerChanged
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:       eventType,
+		ActorID:    actorID,
+		TargetID:   circleID,
+		TargetType: "circle",
+		Action:     "set_member_role",
+		Reason:     fmt.Sprintf("%s %s is now %s", targetRole, targetID, role),
+	})
+	return nil
+}
+
+// authorizeMemberAction checks that the actor holds permission in the circle
+// and outranks the target member, returning the target's current role
+func (s *CircleService) authorizeMemberAction(ctx context.Context, actorID string, actorRole domain.Role, circleID, targetID uuid.UUID, permission authz.Permission) (domain.CircleRole, error) {
+	actorCircleRole, err := s.CircleRole(ctx, actorID, circleID.String())
+	if err != nil {
+		return "", err
+	}
+	if err := s.authorizer.RequireCirclePermission(actorRole, actorCircleRole, permission); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCirclePermissionDenied, err)
+	}
+
+	// Read the target's role fresh rather than from the cache, as it decides the outcome
+	targetRole, err := s.circleRepo.GetMemberRole(ctx, circleID, targetID)
+	if err != nil {
+		return "", err
+	}
+	if targetRole == "" {
+		return "", fmt.Errorf("user is not a member of this circle")
+	}
+	if !s.authorizer.CanActOnMember(actorRole, actorCircleRole, targetRole) {
+		return "", fmt.Errorf("%w: cannot act on a %s", ErrCirclePermissionDenied, targetRole)
+	}
+	return targetRole, nil
+}
+
+// removeMembership deletes the membership and decrements the member count
+func (s *CircleService) removeMembership(ctx context.Context, circleID, userID uuid.UUID) error {
 	// Use transaction to ensure atomicity of membership removal and count update
 	return s.txManager.WithTransaction(ctx, func(tx *sqlx.Tx) error {
 		// Delete membership
 		deleteQuery := `DELETE FROM circle_memberships WHERE circle_id = $1 AND user_id = $2`
-		result, err := tx.ExecContext(ctx, deleteQuery, cid, uid)
+		result, err := tx.ExecContext(ctx, deleteQuery, circleID, userID)
 		if err != nil {
 			return fmt.Errorf("failed to delete membership: %w", err)
 		}
@@ -150,7 +372,7 @@ func (s *CircleService) LeaveCircle(ctx context.Context, userID, circleID string
 
 		// Decrement member count
 		updateQuery := `UPDATE circles SET member_count = member_count - 1, updated_at = NOW() WHERE id = $1`
-		if _, err := tx.ExecContext(ctx, updateQuery, cid); err != nil {
+		if _, err := tx.ExecContext(ctx, updateQuery, circleID); err != nil {
 			return fmt.Errorf("failed to update member count: %w", err)
 		}
 
@@ -158,7 +380,140 @@ func (s *CircleService) LeaveCircle(ctx context.Context, userID, circleID string
 	})
 }
 
+// membershipEnded drops the cached membership and any live subscription to
+// the circle's channel
+func (s *CircleService) membershipEnded(ctx context.Context, userID, circleID string) {
+	_ = s.membershipCache.InvalidateCircleSet(ctx, userID)
+	_ = s.membershipCache.InvalidateMemberRole(ctx, circleID, userID)
+	_ = s.realtimeRepo.PublishEvent(ctx, &domain.RealtimeEvent{
+		Type:    domain.RealtimeEventSubscriptionRevoked,
+		UserID:  userID,
+		Channel: "circle:" + circleID,
+	})
+}
+
+// DeleteCircle soft deletes the circle. It needs circle management rights, so
+// the owner or platform staff, and members lose access at once.
+func (s *CircleService) DeleteCircle(ctx context.Context, actorID string, actorRole domain.Role, circleID, reason string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.DeleteCircle")
+	defer span.End()
+
+	cid, err := uuid.Parse(circleID)
+	if err != nil {
+		return err
+	}
+	if err := s.AuthorizeCircle(ctx, actorID, actorRole, circleID, authz.PermissionManageCircle); err != nil {
+		return err
+	}
+
+	memberIDs, err := s.circleRepo.Delete(ctx, cid)
+	if err != nil {
+		return err
+	}
+	for _, memberID := range memberIDs {
+		s.membershipEnded(ctx, memberID.String(), circleID)
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventCircleDeleted,
+		ActorID:    actorID,
+		TargetID:   circleID,
+		TargetType: "circle",
+		Action:     "delete_circle",
+		Reason:     reason,
+	})
+	return nil
+}
+
+// CircleRole returns the user's role in the circle, or an empty role when
+// they are not a member, using the Redis-cached role. Service clients, with
+// no user ID, are never members.
+func (s *CircleService) CircleRole(ctx context.Context, userID, circleID string) (domain.CircleRole, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.CircleRole")
+	defer span.End()
+
+	if userID == "" {
+		return "", nil
+	}
+
+	role, found, err := s.membershipCache.GetMemberRole(ctx, circleID, userID)
+	if err == nil && found {
+		return role, nil
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return "", err
+	}
+	cid, err := uuid.Parse(circleID)
+	if err != nil {
+		return "", err
+	}
+
+	role, err = s.circleRepo.GetMemberRole(ctx, cid, uid)
+	if err != nil {
+		return "", err
+	}
+	_ = s.membershipCache.SetMemberRole(ctx, circleID, userID, role, circleRoleTTL)
+	return role, nil
+}
+
+// AuthorizeCircle returns ErrCirclePermissionDenied unless the user's role in
+// the circle, or their platform role, grants the circle-scoped permission
+func (s *CircleService) AuthorizeCircle(ctx context.Context, userID string, role domain.Role, circleID string, permission authz.Permission) error {
+	circleRole, err := s.CircleRole(ctx, userID, circleID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizer.RequireCirclePermission(role, circleRole, permission); err != nil {
+		return fmt.Errorf("%w: %v", ErrCirclePermissionDenied, err)
+	}
+	return nil
+}
+
+// IsCircleMember reports whether the user belongs to the circle, using the
+// Redis-cached circle set
+func (s *CircleService) IsCircleMember(ctx context.Context, userID, circleID string) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.IsCircleMember")
+	defer span.End()
+
+	ids, found, err := s.membershipCache.GetCircleSet(ctx, userID)
+	if err == nil && found {
+		for _, id := range ids {
+			if id == circleID {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return false, err
+	}
+
+	circleIDs, err := s.circleRepo.GetUserCircleIDs(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+
+	ids = make([]string, len(circleIDs))
+	member := false
+	for i, id := range circleIDs {
+		ids[i] = id.String()
+		if ids[i] == circleID {
+			member = true
+		}
+	}
+	_ = s.membershipCache.SetCircleSet(ctx, userID, ids, circleSetTTL)
+
+	return member, nil
+}
+
 func (s *CircleService) GetCircleMembers(ctx context.Context, circleID string, limit, offset int) ([]*domain.CircleMembership, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.GetCircleMembers")
+	defer span.End()
+
 	cid, err := uuid.Parse(circleID)
 	if err != nil {
 		return nil, err
@@ -168,17 +523,36 @@ func (s *CircleService) GetCircleMembers(ctx context.Context, circleID string, l
 	if err != nil {
 		return nil, err
 	}
+	// Presence is decoration on the member list, so a lookup failure shows everyone offline
+	online, err := s.presence.GetOnlineStatus(ctx, memberIDs)
+	if err != nil {
+		online = map[uuid.UUID]bool{}
+	}
+
 	memberships := make([]*domain.CircleMembership, len(memberIDs))
 	for i, uid := range memberIDs {
-		memberships[i] = &domain.CircleMembership{UserID: uid, CircleID: cid}
+		memberships[i] = &domain.CircleMembership{UserID: uid, CircleID: cid, Online: online[uid]}
 	}
 	return memberships, nil
 }
 
 func (s *CircleService) GetCircleFeed(ctx context.Context, circleID string, limit, offset int) ([]*domain.Post, error) {
-	return s.postRepo.GetFeed(ctx, nil, &circleID, nil, limit, offset)
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.GetCircleFeed")
+	defer span.End()
+
+	posts, err := s.postRepo.GetFeed(ctx, nil, &circleID, nil, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sensitive.OpenPosts(posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
 }
 
 func (s *CircleService) GetCircles(ctx context.Context, category *string, limit, offset int) ([]*domain.Circle, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CircleService.GetCircles")
+	defer span.End()
+
 	return s.circleRepo.List(ctx, category, limit, offset)
 }
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+const (
+	// communityStatsMinUsers is the k-anonymity threshold: a stat is only
+	// published when at least this many users contribute to it, so no figure
+	// can be traced back to an individual
+	communityStatsMinUsers = 10
+
+	communityStatsCacheKey = "community:stats"
+	communityStatsTTL      = 5 * time.Minute
+)
+
+// CommunityStats are aggregate, anonymous figures shown on the landing page.
+// A nil figure is withheld because too few users contribute to it.
+type CommunityStats struct {
+	TotalMembers       *int      `json:"total_members"`
+	TotalDaysClean     *int64    `json:"total_days_clean"`
+	SupportsGivenToday *int      `json:"supports_given_today"` // Since midnight UTC
+	GeneratedAt        time.Time `json:"generated_at"`
+}
+
+// CommunityStatsService computes public community-wide stats
+type CommunityStatsService struct {
+	userRepo    repository.UserRepository
+	metricsRepo repository.MetricsRepository
+	cacheRepo   repository.CacheRepository
+}
+
+func NewCommunityStatsService(
+	userRepo repository.UserRepository,
+	metricsRepo repository.MetricsRepository,
+	cacheRepo repository.CacheRepository,
+) *CommunityStatsService {
+	return &CommunityStatsService{
+		userRepo:    userRepo,
+		metricsRepo: metricsRepo,
+		cacheRepo:   cacheRepo,
+	}
+}
+
+// GetCommunityStats returns the community stats, recomputing them at most every five minutes
+func (s *CommunityStatsService) GetCommunityStats(ctx context.Context) (*CommunityStats, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CommunityStatsService.GetCommunityStats")
+	defer span.End()
+
+	if cached, err := s.cacheRepo.Get(ctx, communityStatsCacheKey); err == nil {
+		var stats CommunityStats
+		if err := json.Unmarshal([]byte(cached), &stats); err == nil {
+			return &stats, nil
+		}
+	}
+
+	now := time.Now().UTC()
+	members, err := s.userRepo.CountMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	daysClean, cleanUsers, err := s.metricsRepo.SumDaysClean(ctx)
+	if err != nil {
+		return nil, err
+	}
+	supports, supporters, err := s.metricsRepo.CountSupportGiven(ctx, domain.ProgressDay(now))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CommunityStats{GeneratedAt: now}
+	if members >= communityStatsMinUsers {
+		stats.TotalMembers = &members
+	}
+	if cleanUsers >= communityStatsMinUsers {
+		stats.TotalDaysClean = &daysClean
+	}
+	if supporters >= communityStatsMinUsers {
+		stats.SupportsGivenToday = &supports
+	}
+
+	// Caching is an optimisation; serve the fresh stats even if it fails
+	_ = s.cacheRepo.Set(ctx, communityStatsCacheKey, stats, communityStatsTTL)
+	return stats, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+// CopingStrategy is an exercise a user can try to ride out a craving
+type CopingStrategy struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"` // breathing, mindfulness, connection, movement
+	Minutes     int      `json:"minutes"`  // Rough time the strategy takes
+	Steps       []string `json:"steps"`
+}
+
+// CopingStrategies is the coping toolbox offered during a craving
+var CopingStrategies = []CopingStrategy{
+	{
+		ID: "box_breathing", Title: "Box Breathing", Category: "breathing", Minutes: 4,
+		Description: "Slow, even breaths to calm your body while the urge passes.",
+		Steps: []string{
+			"Breathe in through your nose for a count of four",
+			"Hold your breath for a count of four",
+			"Breathe out through your mouth for a count of four",
+			"Hold again for a count of four, then repeat for a few minutes",
+		},
+	},
+	{
+		ID: "urge_surfing", Title: "Urge Surfing", Category: "mindfulness", Minutes: 10,
+		Description: "Notice the craving like a wave that rises, peaks and falls without acting on it.",
+		Steps: []string{
+			"Sit comfortably and notice where you feel the urge in your body",
+			"Describe the sensations to yourself without judging them",
+			"Breathe into those sensations and watch them change",
+			"Keep noticing until the wave crests and starts to fade",
+		},
+	},
+	{
+		ID: "call_a_buddy", Title: "Call a Buddy", Category: "connection", Minutes: 10,
+		Description: "Reach out to someone you trust and talk until the urge eases.",
+		Steps: []string{
+			"Message or call a friend, sponsor or someone from your circle",
+			"Tell them you're having a craving right now",
+			"Talk about anything until the moment passes",
+		},
+	},
+	{
+		ID: "grounding", Title: "5-4-3-2-1 Grounding", Category: "mindfulness", Minutes: 5,
+		Description: "Bring your attention back to the present through your senses.",
+		Steps: []string{
+			"Name five things you can see",
+			"Name four things you can touch",
+			"Name three things you can hear",
+			"Name two things you can smell",
+			"Name one thing you can taste",
+		},
+	},
+	{
+		ID: "take_a_walk", Title: "Take a Walk", Category: "movement", Minutes: 15,
+		Description: "Change your surroundings and let movement take the edge off the urge.",
+		Steps: []string{
+			"Step outside or into another room",
+			"Walk at a comfortable pace for at least ten minutes",
+			"Notice your breathing and your surroundings as you go",
+		},
+	},
+}
+
+// copingStrategy returns the strategy with the given ID
+func copingStrategy(id string) (CopingStrategy, bool) {
+	for _, strategy := range CopingStrategies {
+		if strategy.ID == id {
+			return strategy, true
+		}
+	}
+	return CopingStrategy{}, false
+}
+
+// CopingEffectiveness is how often a strategy has helped a user through a craving
+type CopingEffectiveness struct {
+	StrategyID    string  `json:"strategy_id"`
+	Title         string  `json:"title"`
+	Uses          int     `json:"uses"`
+	Helped        int     `json:"helped"`
+	Effectiveness float64 `json:"effectiveness"` // Share of uses that helped
+}
+
+// calculateCopingEffectiveness lists the strategies the user has tried, most
+// effective first, breaking ties by uses
+func calculateCopingEffectiveness(stats map[string]domain.CopingStat) []CopingEffectiveness {
+	effectiveness := []CopingEffectiveness{}
+	for _, strategy := range CopingStrategies {
+		stat, ok := stats[strategy.ID]
+		if !ok || stat.Uses == 0 {
+			continue
+		}
+		effectiveness = append(effectiveness, CopingEffectiveness{
+			StrategyID:    strategy.ID,
+			Title:         strategy.Title,
+			Uses:          stat.Uses,
+			Helped:        stat.Helped,
+			Effectiveness: float64(stat.Helped) / float64(stat.Uses),
+		})
+	}
+
+	sort.SliceStable(effectiveness, func(i, j int) bool {
+		if effectiveness[i].Effectiveness != effectiveness[j].Effectiveness {
+			return effectiveness[i].Effectiveness > effectiveness[j].Effectiveness
+		}
+		return effectiveness[i].Uses > effectiveness[j].Uses
+	})
+	return effectiveness
+}
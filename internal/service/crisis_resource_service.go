@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// DefaultCrisisResources are the built-in crisis resources; CRISIS_RESOURCES_FILE
+// may add to or override them
+var DefaultCrisisResources = []domain.CrisisResource{
+	{ID: "us_988", Name: "988 Suicide & Crisis Lifeline", Type: domain.CrisisResourceTypeHotline, Country: "US", Phone: "988", URL: "https://988lifeline.org", Hours: "24/7", Languages: []string{"en", "es"}},
+	{ID: "us_crisis_text_line", Name: "Crisis Text Line", Type: domain.CrisisResourceTypeTextLine, Country: "US", SMS: "741741", Description: "Text HOME to 741741", URL: "https://www.crisistextline.org", Hours: "24/7", Languages: []string{"en", "es"}},
+	{ID: "ca_988", Name: "9-8-8 Suicide Crisis Helpline", Type: domain.CrisisResourceTypeHotline, Country: "CA", Phone: "988", SMS: "988", URL: "https://988.ca", Hours: "24/7", Languages: []string{"en", "fr"}},
+	{ID: "gb_samaritans", Name: "Samaritans", Type: domain.CrisisResourceTypeHotline, Country: "GB", Phone: "116 123", URL: "https://www.samaritans.org", Hours: "24/7", Languages: []string{"en"}},
+	{ID: "gb_shout", Name: "Shout", Type: domain.CrisisResourceTypeTextLine, Country: "GB", SMS: "85258", Description: "Text SHOUT to 85258", URL: "https://giveusashout.org", Hours: "24/7", Languages: []string{"en"}},
+	{ID: "ie_samaritans", Name: "Samaritans Ireland", Type: domain.CrisisResourceTypeHotline, Country: "IE", Phone: "116 123", URL: "https://www.samaritans.org/ireland", Hours: "24/7", Languages: []string{"en"}},
+	{ID: "au_lifeline", Name: "Lifeline Australia", Type: domain.CrisisResourceTypeHotline, Country: "AU", Phone: "13 11 14", SMS: "0477 13 11 14", URL: "https://www.lifeline.org.au", Hours: "24/7", Languages: []string{"en"}},
+	{ID: "findahelpline", Name: "Find A Helpline", Type: domain.CrisisResourceTypeLocalService, URL: "https://findahelpline.com", Description: "Free, confidential helplines in over 130 countries"},
+}
+
+// LoadCrisisResources returns the default crisis resources merged with the
+// JSON list in path, which override defaults with the same ID. An empty path
+// returns the defaults.
+func LoadCrisisResources(path string) ([]domain.CrisisResource, error) {
+	resources := append([]domain.CrisisResource{}, DefaultCrisisResources...)
+	if path == "" {
+		return resources, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crisis resources: %w", err)
+	}
+
+	var custom []domain.CrisisResource
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return nil, fmt.Errorf("failed to parse crisis resources: %w", err)
+	}
+
+	index := make(map[string]int, len(resources))
+	for i, resource := range resources {
+		index[resource.ID] = i
+	}
+	for _, resource := range custom {
+		if resource.ID == "" || resource.Name == "" {
+			return nil, fmt.Errorf("crisis resource %q needs an id and name", resource.ID)
+		}
+		switch resource.Type {
+		case domain.CrisisResourceTypeHotline, domain.CrisisResourceTypeTextLine, domain.CrisisResourceTypeLocalService:
+		default:
+			return nil, fmt.Errorf("crisis resource %q has unknown type %q", resource.ID, resource.Type)
+		}
+		if resource.Phone == "" && resource.SMS == "" && resource.URL == "" {
+			return nil, fmt.Errorf("crisis resource %q needs a phone, sms or url", resource.ID)
+		}
+		if resource.Region != "" && resource.Country == "" {
+			return nil, fmt.Errorf("crisis resource %q has a region but no country", resource.ID)
+		}
+		resource.Country = strings.ToUpper(resource.Country)
+
+		if i, ok := index[resource.ID]; ok {
+			resources[i] = resource
+		} else {
+			index[resource.ID] = len(resources)
+			resources = append(resources, resource)
+		}
+	}
+	return resources, nil
+}
+
+// CrisisResourceService tells people in crisis where they can get help, using
+// the resources for where they are
+type CrisisResourceService struct {
+	resources      []domain.CrisisResource
+	loginLocations repository.LoginLocationRepository
+	notifier       *NotificationService
+}
+
+func NewCrisisResourceService(
+	resources []domain.CrisisResource,
+	loginLocations repository.LoginLocationRepository,
+	notifier *NotificationService,
+) *CrisisResourceService {
+	return &CrisisResourceService{
+		resources:      resources,
+		loginLocations: loginLocations,
+		notifier:       notifier,
+	}
+}
+
+// ListCrisisResources returns the resources for country and region along with
+// the country used. Without a country, it's where the request came from, or
+// else where the user last logged in. Types, if given, filter the resources.
+func (s *CrisisResourceService) ListCrisisResources(ctx context.Context, userID, country, region string, types []domain.CrisisResourceType) (string, []domain.CrisisResource, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CrisisResourceService.ListCrisisResources")
+	defer span.End()
+
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		country = fingerprint.FromContext(ctx).Location.Country
+	}
+	if country == "" && userID != "" {
+		var err error
+		if country, err = s.lastLoginCountry(ctx, userID); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return country, filterCrisisResources(s.resources, country, region, types), nil
+}
+
+// SendToUser sends a user the crisis resources for where they last logged in,
+// e.g. when they ask for help in an SOS post
+func (s *CrisisResourceService) SendToUser(ctx context.Context, userID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "CrisisResourceService.SendToUser")
+	defer span.End()
+
+	country, err := s.lastLoginCountry(ctx, userID)
+	if err != nil {
+		return err
+	}
+	resources := filterCrisisResources(s.resources, country, "", nil)
+	if len(resources) == 0 {
+		return nil
+	}
+	return s.notifier.NotifyCrisisResources(ctx, userID, resources)
+}
+
+// lastLoginCountry returns the country the user last logged in from, or ""
+// if none was recorded
+func (s *CrisisResourceService) lastLoginCountry(ctx context.Context, userID string) (string, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return "", fmt.Errorf("invalid user ID: %w", err)
+	}
+	location, err := s.loginLocations.GetLatestLoginLocation(ctx, uid)
+	if err != nil || location == nil {
+		return "", err
+	}
+	return location.Country, nil
+}
+
+// filterCrisisResources returns the resources serving country and region,
+// those for the region first and those shown everywhere last
+func filterCrisisResources(resources []domain.CrisisResource, country, region string, types []domain.CrisisResourceType) []domain.CrisisResource {
+	var regional, national, global []domain.CrisisResource
+	for _, resource := range resources {
+		if len(types) > 0 && !slices.Contains(types, resource.Type) {
+			continue
+		}
+		if !resource.Serves(country, region) {
+			continue
+		}
+		switch {
+		case resource.Country == "":
+			global = append(global, resource)
+		case resource.Region == "":
+			national = append(national, resource)
+		default:
+			regional = append(regional, resource)
+		}
+	}
+	return append(append(regional, national...), global...)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+func crisisResourceIDs(resources []domain.CrisisResource) []string {
+	ids := make([]string, len(resources))
+	for i, resource := range resources {
+		ids[i] = resource.ID
+	}
+	return ids
+}
+
+// TestFilterCrisisResources tests that resources are matched to a location
+// and ordered from the most to the least local
+func TestFilterCrisisResources(t *testing.T) {
+	resources := []domain.CrisisResource{
+		{ID: "global", Type: domain.CrisisResourceTypeLocalService},
+		{ID: "us", Type: domain.CrisisResourceTypeHotline, Country: "US"},
+		{ID: "us_text", Type: domain.CrisisResourceTypeTextLine, Country: "US"},
+		{ID: "us_ca", Type: domain.CrisisResourceTypeLocalService, Country: "US", Region: "CA"},
+		{ID: "gb", Type: domain.CrisisResourceTypeHotline, Country: "GB"},
+	}
+
+	assert.Equal(t, []string{"us_ca", "us", "us_text", "global"},
+		crisisResourceIDs(filterCrisisResources(resources, "US", "ca", nil)))
+	assert.Equal(t, []string{"us", "us_text", "global"},
+		crisisResourceIDs(filterCrisisResources(resources, "us", "", nil)))
+	assert.Equal(t, []string{"gb"},
+		crisisResourceIDs(filterCrisisResources(resources, "GB", "", []domain.CrisisResourceType{domain.CrisisResourceTypeHotline})))
+
+	// An unknown location gets the resources available everywhere
+	assert.Equal(t, []string{"global"}, crisisResourceIDs(filterCrisisResources(resources, "", "", nil)))
+}
+
+// TestLoadCrisisResources tests merging a resources file into the defaults
+func TestLoadCrisisResources(t *testing.T) {
+	resources, err := LoadCrisisResources("")
+	require.NoError(t, err)
+	assert.Equal(t, len(DefaultCrisisResources), len(resources))
+
+	path := filepath.Join(t.TempDir(), "resources.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"id": "us_988", "name": "988 Lifeline", "type": "hotline", "country": "US", "phone": "988"},
+		{"id": "de_telefonseelsorge", "name": "TelefonSeelsorge", "type": "hotline", "country": "de", "phone": "0800 111 0 111"}
+	]`), 0o600))
+
+	resources, err = LoadCrisisResources(path)
+	require.NoError(t, err)
+	assert.Equal(t, len(DefaultCrisisResources)+1, len(resources))
+	assert.Equal(t, "988 Lifeline", resources[0].Name)
+	assert.Equal(t, "DE", resources[len(resources)-1].Country)
+
+	for _, invalid := range []string{
+		`[{"id": "x", "name": "X", "type": "pigeon", "phone": "1"}]`,
+		`[{"id": "x", "name": "X", "type": "hotline"}]`,
+		`[{"id": "x", "name": "X", "type": "hotline", "region": "CA", "phone": "1"}]`,
+		`[{"name": "X", "type": "hotline", "phone": "1"}]`,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(invalid), 0o600))
+		_, err := LoadCrisisResources(path)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/audit"
+	"github.com/yourorg/anonymous-support/internal/pkg/stripe"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+// ErrEntitlementRequired is returned when a feature isn't included in the
+// user's plan
+var ErrEntitlementRequired = errors.New("not included in your plan")
+
+// EntitlementChecker reports whether a user's plan includes an entitlement
+type EntitlementChecker interface {
+	HasEntitlement(ctx context.Context, userID string, entitlement domain.Entitlement) (bool, error)
+}
+
+// Entitlements are what a user's plan currently unlocks
+type Entitlements struct {
+	Plan         domain.Plan
+	Entitlements []domain.Entitlement
+	Subscription *domain.Subscription // Nil for users who never had one
+}
+
+// EntitlementService manages users' plans, set by admins or synced from
+// Stripe webhooks, and answers entitlement checks for other services. When
+// entitlements aren't enforced, everyone has every entitlement.
+type EntitlementService struct {
+	subscriptionRepo repository.SubscriptionRepository
+	enforced         bool
+	stripePlans      map[string]domain.Plan // Stripe price ID to plan
+	audit            *audit.Writer
+	logger           *zap.Logger
+}
+
+func NewEntitlementService(
+	subscriptionRepo repository.SubscriptionRepository,
+	enforced bool,
+	stripePlans map[string]domain.Plan,
+	auditWriter *audit.Writer,
+	logger *zap.Logger,
+) *EntitlementService {
+	return &EntitlementService{
+		subscriptionRepo: subscriptionRepo,
+		enforced:         enforced,
+		stripePlans:      stripePlans,
+		audit:            auditWriter,
+		logger:           logger,
+	}
+}
+
+// ParseStripePricePlans parses "price_id:plan" entries mapping Stripe prices
+// to the plans they buy
+func ParseStripePricePlans(entries []string) (map[string]domain.Plan, error) {
+	plans := make(map[string]domain.Plan, len(entries))
+	for _, entry := range entries {
+		priceID, plan, ok := strings.Cut(entry, ":")
+		if !ok || priceID == "" {
+			return nil, fmt.Errorf("stripe price plan %q must be price_id:plan", entry)
+		}
+		if _, known := domain.PlanEntitlements[domain.Plan(plan)]; !known {
+			return nil, fmt.Errorf("stripe price plan %q has unknown plan %q", entry, plan)
+		}
+		plans[priceID] = domain.Plan(plan)
+	}
+	return plans, nil
+}
+
+// GetEntitlements returns the user's current plan and what it unlocks
+func (s *EntitlementService) GetEntitlements(ctx context.Context, userID string) (*Entitlements, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "EntitlementService.GetEntitlements")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	subscription, err := s.subscriptionRepo.Get(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := subscription.EffectivePlan(time.Now())
+	entitlements := domain.PlanEntitlements[plan]
+	if !s.enforced {
+		entitlements = domain.AllEntitlements
+	}
+	return &Entitlements{Plan: plan, Entitlements: entitlements, Subscription: subscription}, nil
+}
+
+// HasEntitlement reports whether the user's plan includes entitlement
+func (s *EntitlementService) HasEntitlement(ctx context.Context, userID string, entitlement domain.Entitlement) (bool, error) {
+	if !s.enforced {
+		return true, nil
+	}
+	entitlements, err := s.GetEntitlements(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return entitlements.Plan.Grants(entitlement), nil
+}
+
+// SetPlan puts a user on plan until expiresAt, or indefinitely when nil. It
+// replaces any Stripe subscription until Stripe next sends an update for it.
+func (s *EntitlementService) SetPlan(ctx context.Context, actorID, userID string, plan domain.Plan, expiresAt *time.Time) (*domain.Subscription, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "EntitlementService.SetPlan")
+	defer span.End()
+
+	if _, known := domain.PlanEntitlements[plan]; !known {
+		return nil, fmt.Errorf("unknown plan %q", plan)
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	subscription := &domain.Subscription{
+		UserID:           uid,
+		Plan:             plan,
+		Status:           domain.SubscriptionStatusActive,
+		Source:           domain.SubscriptionSourceManual,
+		CurrentPeriodEnd: expiresAt,
+	}
+	if err := s.save(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventSubscriptionChanged,
+		ActorID:    actorID,
+		TargetID:   userID,
+		TargetType: "user",
+		Action:     "set_plan",
+		Reason:     string(plan),
+	})
+	return subscription, nil
+}
+
+// HandleStripeEvent applies a verified Stripe webhook event. Events other
+// than subscription changes, and subscriptions that can't be matched to a
+// user or plan, are ignored so Stripe doesn't retry them.
+func (s *EntitlementService) HandleStripeEvent(ctx context.Context, event *stripe.Event) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "EntitlementService.HandleStripeEvent")
+	defer span.End()
+
+	switch event.Type {
+	case stripe.EventSubscriptionCreated, stripe.EventSubscriptionUpdated, stripe.EventSubscriptionDeleted:
+	default:
+		return nil
+	}
+
+	var stripeSub stripe.Subscription
+	if err := json.Unmarshal(event.Data.Object, &stripeSub); err != nil {
+		return fmt.Errorf("invalid subscription in event %s: %w", event.ID, err)
+	}
+
+	existing, err := s.subscriptionRepo.GetByExternalID(ctx, stripeSub.ID)
+	if err != nil {
+		return err
+	}
+	userID := stripeSub.Metadata["user_id"]
+	if userID == "" && existing != nil {
+		userID = existing.UserID.String()
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		s.logger.Warn("Ignoring Stripe subscription without a user", zap.String("event_id", event.ID), zap.String("subscription_id", stripeSub.ID))
+		return nil
+	}
+
+	plan, ok := s.planFor(stripeSub.PriceIDs())
+	if !ok {
+		s.logger.Warn("Ignoring Stripe subscription for unknown prices", zap.String("event_id", event.ID), zap.Strings("price_ids", stripeSub.PriceIDs()))
+		return nil
+	}
+
+	status := stripeStatus(stripeSub.Status)
+	if event.Type == stripe.EventSubscriptionDeleted {
+		status = domain.SubscriptionStatusCanceled
+	}
+
+	current, err := s.subscriptionRepo.Get(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !supersedes(current, stripeSub.ID, status, time.Now()) {
+		return nil
+	}
+
+	subscription := &domain.Subscription{
+		UserID:             uid,
+		Plan:               plan,
+		Status:             status,
+		Source:             domain.SubscriptionSourceStripe,
+		ExternalID:         &stripeSub.ID,
+		ExternalCustomerID: &stripeSub.Customer,
+	}
+	if stripeSub.CurrentPeriodEnd > 0 {
+		periodEnd := time.Unix(stripeSub.CurrentPeriodEnd, 0).UTC()
+		subscription.CurrentPeriodEnd = &periodEnd
+	}
+	if err := s.save(ctx, subscription); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventSubscriptionChanged,
+		TargetID:   userID,
+		TargetType: "user",
+		Action:     event.Type,
+		Reason:     fmt.Sprintf("%s %s", plan, status),
+	})
+	return nil
+}
+
+// save stores the subscription, marking the user premium while it grants a
+// paid plan
+func (s *EntitlementService) save(ctx context.Context, subscription *domain.Subscription) error {
+	isPremium := subscription.EffectivePlan(time.Now()) != domain.PlanFree
+	return s.subscriptionRepo.Save(ctx, subscription, isPremium)
+}
+
+// planFor returns the plan bought by the first of priceIDs with a known plan
+func (s *EntitlementService) planFor(priceIDs []string) (domain.Plan, bool) {
+	for _, priceID := range priceIDs {
+		if plan, ok := s.stripePlans[priceID]; ok {
+			return plan, true
+		}
+	}
+	return "", false
+}
+
+// stripeStatus maps a Stripe subscription status onto ours. Subscriptions
+// waiting on a first payment, unpaid or paused are inactive until they're
+// paid for; canceled and expired ones have ended.
+func stripeStatus(status string) domain.SubscriptionStatus {
+	switch status {
+	case "active":
+		return domain.SubscriptionStatusActive
+	case "trialing":
+		return domain.SubscriptionStatusTrialing
+	case "past_due":
+		return domain.SubscriptionStatusPastDue
+	case "canceled", "incomplete_expired":
+		return domain.SubscriptionStatusCanceled
+	default:
+		return domain.SubscriptionStatusInactive
+	}
+}
+
+// supersedes reports whether an update to the Stripe subscription externalID
+// should replace the user's current subscription. Stripe doesn't guarantee
+// delivery order, so a canceled subscription is never revived by a late
+// update. Another subscription, or a plan set by an admin, is only replaced
+// by one that grants something or once it has lapsed itself.
+func supersedes(current *domain.Subscription, externalID string, status domain.SubscriptionStatus, now time.Time) bool {
+	if current == nil {
+		return true
+	}
+	if current.ExternalID != nil && *current.ExternalID == externalID {
+		return current.Status != domain.SubscriptionStatusCanceled
+	}
+	granting := status == domain.SubscriptionStatusActive || status == domain.SubscriptionStatusTrialing || status == domain.SubscriptionStatusPastDue
+	return granting || current.EffectivePlan(now) == domain.PlanFree
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+// TestParseStripePricePlans tests parsing price_id:plan entries
+func TestParseStripePricePlans(t *testing.T) {
+	plans, err := ParseStripePricePlans([]string{"price_monthly:premium", "price_yearly:premium"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]domain.Plan{"price_monthly": domain.PlanPremium, "price_yearly": domain.PlanPremium}, plans)
+
+	for _, invalid := range []string{"price_monthly", ":premium", "price_monthly:gold"} {
+		_, err := ParseStripePricePlans([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+// TestSubscriptionEffectivePlan tests when a subscription grants its plan
+func TestSubscriptionEffectivePlan(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+
+	var none *domain.Subscription
+	assert.Equal(t, domain.PlanFree, none.EffectivePlan(now))
+
+	tests := []struct {
+		status    domain.SubscriptionStatus
+		periodEnd *time.Time
+		want      domain.Plan
+	}{
+		{domain.SubscriptionStatusActive, nil, domain.PlanPremium},
+		{domain.SubscriptionStatusActive, &later, domain.PlanPremium},
+		{domain.SubscriptionStatusActive, &earlier, domain.PlanFree},
+		{domain.SubscriptionStatusTrialing, &later, domain.PlanPremium},
+		{domain.SubscriptionStatusPastDue, &later, domain.PlanPremium},
+		{domain.SubscriptionStatusInactive, &later, domain.PlanFree},
+		{domain.SubscriptionStatusCanceled, &later, domain.PlanFree},
+	}
+	for _, tt := range tests {
+		subscription := &domain.Subscription{Plan: domain.PlanPremium, Status: tt.status, CurrentPeriodEnd: tt.periodEnd}
+		assert.Equal(t, tt.want, subscription.EffectivePlan(now), tt.status)
+	}
+}
+
+// TestSupersedes tests that out-of-order Stripe updates don't undo newer state
+func TestSupersedes(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	subA, subB := "sub_a", "sub_b"
+
+	assert.True(t, supersedes(nil, subA, domain.SubscriptionStatusInactive, now), "first subscription")
+
+	active := &domain.Subscription{Plan: domain.PlanPremium, Status: domain.SubscriptionStatusActive, ExternalID: &subA}
+	assert.True(t, supersedes(active, subA, domain.SubscriptionStatusCanceled, now), "same subscription canceled")
+	assert.False(t, supersedes(active, subB, domain.SubscriptionStatusCanceled, now), "old subscription ending")
+	assert.True(t, supersedes(active, subB, domain.SubscriptionStatusActive, now), "new subscription")
+
+	canceled := &domain.Subscription{Plan: domain.PlanPremium, Status: domain.SubscriptionStatusCanceled, ExternalID: &subA}
+	assert.False(t, supersedes(canceled, subA, domain.SubscriptionStatusActive, now), "late update after cancellation")
+	assert.True(t, supersedes(canceled, subB, domain.SubscriptionStatusInactive, now), "new subscription after cancellation")
+
+	manual := &domain.Subscription{Plan: domain.PlanPremium, Status: domain.SubscriptionStatusActive, Source: domain.SubscriptionSourceManual}
+	assert.False(t, supersedes(manual, subA, domain.SubscriptionStatusCanceled, now), "admin grant outlives a canceled subscription")
+
+	incomplete := &domain.Subscription{Plan: domain.PlanPremium, Status: domain.SubscriptionStatusInactive, ExternalID: &subA}
+	assert.True(t, supersedes(incomplete, subA, domain.SubscriptionStatusActive, now), "first payment")
+}
+
+// TestStripeStatus tests mapping Stripe subscription statuses
+func TestStripeStatus(t *testing.T) {
+	assert.Equal(t, domain.SubscriptionStatusActive, stripeStatus("active"))
+	assert.Equal(t, domain.SubscriptionStatusPastDue, stripeStatus("past_due"))
+	assert.Equal(t, domain.SubscriptionStatusInactive, stripeStatus("incomplete"))
+	assert.Equal(t, domain.SubscriptionStatusInactive, stripeStatus("unpaid"))
+	assert.Equal(t, domain.SubscriptionStatusCanceled, stripeStatus("incomplete_expired"))
+}
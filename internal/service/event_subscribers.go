@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/events"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"github.com/yourorg/anonymous-support/internal/pkg/workqueue"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Event subscriber groups; each receives every event it subscribes to once
+// across all instances
+const (
+	subscriberRealtime      = "realtime"
+	subscriberNotifications = "notifications"
+	subscriberSOS           = "sos"
+	subscriberMilestones    = "milestones"
+	subscriberFeedCache     = "feed_cache"
+)
+
+// EventSubscribers carries out the side effects of domain events: realtime
+// fan-out to WebSocket clients, notifications, SOS alerts and crisis
+// resources, milestone celebrations and feed cache invalidation. Handlers that notify people log failures instead of returning
+// them, since a retry would notify again everyone already reached.
+type EventSubscribers struct {
+	realtimeRepo  repository.RealtimeRepository
+	relayRealtime bool
+	posts         *PostService
+	sensitive     *SensitiveContent
+	notifier      *NotificationService
+	sos           *SOSService
+	crisis        *CrisisResourceService
+	milestones    *MilestoneService
+	jobs          *workqueue.Queue
+	logger        *zap.Logger
+}
+
+// NewEventSubscribers creates the subscribers. relayRealtime is false when
+// new posts and responses reach clients another way, e.g. change streams.
+func NewEventSubscribers(
+	realtimeRepo repository.RealtimeRepository,
+	relayRealtime bool,
+	posts *PostService,
+	sensitive *SensitiveContent,
+	notifier *NotificationService,
+	sos *SOSService,
+	crisis *CrisisResourceService,
+	milestones *MilestoneService,
+	jobs *workqueue.Queue,
+	logger *zap.Logger,
+) *EventSubscribers {
+	return &EventSubscribers{
+		realtimeRepo:  realtimeRepo,
+		relayRealtime: relayRealtime,
+		posts:         posts,
+		sensitive:     sensitive,
+		notifier:      notifier,
+		sos:           sos,
+		crisis:        crisis,
+		milestones:    milestones,
+		jobs:          jobs,
+		logger:        logger,
+	}
+}
+
+// Subscribe registers every subscriber on bus; they run until ctx is cancelled
+func (s *EventSubscribers) Subscribe(ctx context.Context, bus events.EventBus) error {
+	subscriptions := []struct {
+		eventType string
+		group     string
+		handler   events.Handler
+	}{
+		{domain.EventPostPublished, subscriberRealtime, s.relayNewPost},
+		{domain.EventPostPublished, subscriberNotifications, s.notifyPostMentions},
+		{domain.EventPostPublished, subscriberSOS, s.alertSOSHelpers},
+		{domain.EventPostPublished, subscriberFeedCache, s.invalidateFeedCache},
+		{domain.EventPostWithdrawn, subscriberFeedCache, s.dropWithdrawnPost},
+		{domain.EventResponseCreated, subscriberRealtime, s.relayNewResponse},
+		{domain.EventResponseCreated, subscriberNotifications, s.notifyResponse},
+		{domain.EventMilestoneReached, subscriberMilestones, s.celebrateMilestone},
+		{domain.EventAchievementUnlocked, subscriberMilestones, s.celebrateAchievement},
+	}
+
+	for _, sub := range subscriptions {
+		if sub.group == subscriberRealtime && !s.relayRealtime {
+			continue
+		}
+		if err := bus.Subscribe(ctx, sub.eventType, sub.group, sub.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relayNewPost announces a new post to connected clients
+func (s *EventSubscribers) relayNewPost(ctx context.Context, event events.Event) error {
+	var payload domain.PostPublishedEvent
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	post := payload.Post
+	return s.realtimeRepo.PublishNewPost(ctx, post.ID.Hex(), post.UserID, string(post.Type), post.Categories)
+}
+
+// invalidateFeedCache drops cached feed pages so a new post shows up without
+// waiting for them to expire
+func (s *EventSubscribers) invalidateFeedCache(ctx context.Context, event events.Event) error {
+	var payload domain.PostPublishedEvent
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	return s.posts.InvalidateFeedCache(ctx, &payload.Post)
+}
+
+// dropWithdrawnPost drops cached feed pages, including the copies served while
+// MongoDB is down, so a post moderation hid or removed stops being shown
+func (s *EventSubscribers) dropWithdrawnPost(ctx context.Context, event events.Event) error {
+	return s.posts.InvalidateAllFeedCaches(ctx)
+}
+
+// notifyPostMentions notifies users @mentioned in a new post
+func (s *EventSubscribers) notifyPostMentions(ctx context.Context, event events.Event) error {
+	var payload domain.PostPublishedEvent
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	post := payload.Post
+	if err := s.sensitive.OpenPost(&post); err != nil {
+		s.logger.Warn("Failed to decrypt post for mentions", zap.String("post_id", post.ID.Hex()), zap.Error(err))
+		return nil
+	}
+	if err := s.notifier.NotifyMentions(ctx, post.UserID, post.Username, post.Content, post.ID.Hex()); err != nil {
+		s.logger.Warn("Failed to notify post mentions", zap.String("post_id", post.ID.Hex()), zap.Error(err))
+	}
+	return nil
+}
+
+// alertSOSHelpers alerts subscribed helpers to a new SOS post and sends its
+// author crisis resources. The alert is a critical job so it runs ahead of
+// queued reminders and digests.
+func (s *EventSubscribers) alertSOSHelpers(ctx context.Context, event events.Event) error {
+	var payload domain.PostPublishedEvent
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	post := payload.Post
+	if post.Type != domain.PostTypeSOS {
+		return nil
+	}
+
+	alert := func(ctx context.Context) error {
+		if err := s.crisis.SendToUser(ctx, post.UserID); err != nil {
+			s.logger.Warn("Failed to send crisis resources", zap.String("post_id", post.ID.Hex()), zap.Error(err))
+		}
+
+		notified, err := s.sos.NotifyHelpers(ctx, &post)
+		if err != nil {
+			s.logger.Warn("Failed to alert SOS helpers", zap.String("post_id", post.ID.Hex()), zap.Error(err))
+			return nil
+		}
+		metrics.SOSHelpersNotified.Observe(float64(notified))
+		return nil
+	}
+
+	if err := s.jobs.Submit("sos_alert:"+post.ID.Hex(), workqueue.PriorityCritical, alert); err != nil {
+		// Shutting down; alert before the event is acknowledged
+		return alert(ctx)
+	}
+	return nil
+}
+
+// relayNewResponse announces a new response to connected clients
+func (s *EventSubscribers) relayNewResponse(ctx context.Context, event events.Event) error {
+	var payload domain.ResponseCreatedEvent
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	return s.realtimeRepo.PublishNewResponse(ctx, payload.PostID, payload.ResponseID, payload.UserID)
+}
+
+// notifyResponse tells the post's author about a response and notifies users
+// @mentioned in it
+func (s *EventSubscribers) notifyResponse(ctx context.Context, event events.Event) error {
+	var payload domain.ResponseCreatedEvent
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	if payload.PostAuthorID != payload.UserID {
+		if err := s.notifier.NotifyNewResponse(ctx, payload.PostAuthorID, payload.Username, payload.PostID); err != nil {
+			s.logger.Warn("Failed to notify post author of response", zap.String("post_id", payload.PostID), zap.Error(err))
+		}
+	}
+	if payload.Type == domain.ResponseTypeText {
+		content, err := s.sensitive.OpenText(payload.Content, payload.ContentEncrypted)
+		if err != nil {
+			s.logger.Warn("Failed to decrypt response for mentions", zap.String("post_id", payload.PostID), zap.Error(err))
+			return nil
+		}
+		if err := s.notifier.NotifyMentions(ctx, payload.UserID, payload.Username, content, payload.PostID); err != nil {
+			s.logger.Warn("Failed to notify response mentions", zap.String("post_id", payload.PostID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *EventSubscribers) celebrateMilestone(ctx context.Context, event events.Event) error {
+	var payload MilestoneEvent
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	s.milestones.Celebrate(ctx, payload)
+	return nil
+}
+
+func (s *EventSubscribers) celebrateAchievement(ctx context.Context, event events.Event) error {
+	var payload AchievementEvent
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+	s.milestones.CelebrateAchievement(ctx, payload)
+	return nil
+}
@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/audit"
+	"github.com/yourorg/anonymous-support/internal/pkg/objectstore"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/pkg/workqueue"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	exportPageSize = 1000
+	maxExportRange = 366 * 24 * time.Hour
+)
+
+var (
+	// ErrExportNotFound is returned for an unknown export ID
+	ErrExportNotFound = errors.New("export not found")
+	// ErrExportFormatUnsupported is returned for formats this build can't write.
+	// Parquet needs a Parquet writer dependency, which isn't included yet.
+	ErrExportFormatUnsupported = errors.New("export format is not supported")
+)
+
+// exportColumn is one column of an exported dataset. PII columns are never
+// written, whatever the exclusions.
+type exportColumn struct {
+	name string
+	pii  bool
+}
+
+// exportDatasetColumns lists each dataset's columns in file order
+var exportDatasetColumns = map[domain.ExportDataset][]exportColumn{
+	domain.ExportDatasetReports: {
+		{name: "id"}, {name: "source"}, {name: "content_type"}, {name: "content_id"}, {name: "reason"},
+		{name: "description", pii: true}, {name: "reporter_id", pii: true},
+		{name: "status"}, {name: "severity"}, {name: "reporter_count"}, {name: "report_score"},
+		{name: "reviewed_by"}, {name: "reviewed_at"}, {name: "created_at"},
+	},
+	domain.ExportDatasetAuditLogs: {
+		{name: "id"}, {name: "event_type"},
+		{name: "actor_id", pii: true}, {name: "actor_ip", pii: true}, {name: "target_id", pii: true},
+		{name: "target_type"}, {name: "action"},
+		{name: "metadata", pii: true}, {name: "success"}, {name: "error_message", pii: true},
+		{name: "created_at"},
+	},
+	domain.ExportDatasetContentVolume: {
+		{name: "day"}, {name: "posts"}, {name: "responses"},
+	},
+}
+
+// ExportRequest describes an export to start
+type ExportRequest struct {
+	Dataset        domain.ExportDataset
+	Format         domain.ExportFormat // Defaults to CSV
+	Since          time.Time
+	Until          time.Time
+	ExcludeColumns []string // Further columns to leave out, besides PII and configured exclusions
+}
+
+// ExportService writes moderation and analytics datasets to files in object
+// storage for offline analysis. Exports run as background jobs; PII columns
+// and configured exclusions are never written.
+type ExportService struct {
+	exportRepo     repository.ExportRepository
+	moderationRepo repository.ModerationRepository
+	auditRepo      repository.AuditRepository
+	metricsRepo    repository.MetricsRepository
+	store          objectstore.Store
+	excluded       map[domain.ExportDataset][]string
+	daily          []domain.ExportDataset // Exported each day by ExportDaily
+	jobs           *workqueue.Queue
+	audit          *audit.Writer
+	logger         *zap.Logger
+}
+
+func NewExportService(
+	exportRepo repository.ExportRepository,
+	moderationRepo repository.ModerationRepository,
+	auditRepo repository.AuditRepository,
+	metricsRepo repository.MetricsRepository,
+	store objectstore.Store,
+	excluded map[domain.ExportDataset][]string,
+	daily []domain.ExportDataset,
+	jobs *workqueue.Queue,
+	auditWriter *audit.Writer,
+	logger *zap.Logger,
+) *ExportService {
+	return &ExportService{
+		exportRepo:     exportRepo,
+		moderationRepo: moderationRepo,
+		auditRepo:      auditRepo,
+		metricsRepo:    metricsRepo,
+		store:          store,
+		excluded:       excluded,
+		daily:          daily,
+		jobs:           jobs,
+		audit:          auditWriter,
+		logger:         logger,
+	}
+}
+
+// ParseExportExclusions parses "dataset.column" entries naming columns to
+// leave out of every export
+func ParseExportExclusions(entries []string) (map[domain.ExportDataset][]string, error) {
+	excluded := make(map[domain.ExportDataset][]string)
+	for _, entry := range entries {
+		dataset, column, ok := strings.Cut(entry, ".")
+		if !ok {
+			return nil, fmt.Errorf("export exclusion %q must be dataset.column", entry)
+		}
+		if err := checkExportColumns(domain.ExportDataset(dataset), []string{column}); err != nil {
+			return nil, fmt.Errorf("export exclusion %q: %w", entry, err)
+		}
+		excluded[domain.ExportDataset(dataset)] = append(excluded[domain.ExportDataset(dataset)], column)
+	}
+	return excluded, nil
+}
+
+// ParseExportDatasets parses dataset names such as "reports"
+func ParseExportDatasets(names []string) ([]domain.ExportDataset, error) {
+	datasets := make([]domain.ExportDataset, 0, len(names))
+	for _, name := range names {
+		if err := checkExportColumns(domain.ExportDataset(name), nil); err != nil {
+			return nil, err
+		}
+		datasets = append(datasets, domain.ExportDataset(name))
+	}
+	return datasets, nil
+}
+
+// StartExport validates the request, records the export and queues the job
+// writing it
+func (s *ExportService) StartExport(ctx context.Context, actorID string, req ExportRequest) (*domain.DataExport, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ExportService.StartExport")
+	defer span.End()
+
+	export, err := s.create(ctx, actorID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventDataExportStarted,
+		ActorID:    actorID,
+		TargetID:   export.ID.String(),
+		TargetType: "data_export",
+		Action:     fmt.Sprintf("export %s from %s to %s", export.Dataset, export.Since.Format(time.RFC3339), export.Until.Format(time.RFC3339)),
+	})
+
+	job := *export
+	if err := s.jobs.Submit("data_export:"+export.ID.String(), workqueue.PriorityLow, func(ctx context.Context) error {
+		return s.run(ctx, &job)
+	}); err != nil {
+		s.fail(ctx, export, err)
+		return nil, fmt.Errorf("failed to queue export: %w", err)
+	}
+	return export, nil
+}
+
+// ExportDaily writes the daily datasets' rows from the previous UTC day. It
+// runs inside a background job, so the exports are written before it returns.
+func (s *ExportService) ExportDaily(ctx context.Context, now time.Time) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ExportService.ExportDaily")
+	defer span.End()
+
+	until := now.UTC().Truncate(24 * time.Hour)
+	var errs []error
+	for _, dataset := range s.daily {
+		export, err := s.create(ctx, "", ExportRequest{Dataset: dataset, Since: until.AddDate(0, 0, -1), Until: until})
+		if err == nil {
+			err = s.run(ctx, export)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("daily %s export failed: %w", dataset, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetExport returns an export's status
+func (s *ExportService) GetExport(ctx context.Context, exportID string) (*domain.DataExport, error) {
+	id, err := uuid.Parse(exportID)
+	if err != nil {
+		return nil, ErrExportNotFound
+	}
+	export, err := s.exportRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil {
+		return nil, ErrExportNotFound
+	}
+	return export, nil
+}
+
+// ListExports returns exports newest first
+func (s *ExportService) ListExports(ctx context.Context, limit, offset int) ([]*domain.DataExport, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.exportRepo.List(ctx, limit, offset)
+}
+
+func (s *ExportService) create(ctx context.Context, actorID string, req ExportRequest) (*domain.DataExport, error) {
+	if req.Format == "" {
+		req.Format = domain.ExportFormatCSV
+	}
+	switch req.Format {
+	case domain.ExportFormatCSV:
+	case domain.ExportFormatParquet:
+		return nil, ErrExportFormatUnsupported
+	default:
+		return nil, fmt.Errorf("unknown export format %q", req.Format)
+	}
+	if err := checkExportColumns(req.Dataset, req.ExcludeColumns); err != nil {
+		return nil, err
+	}
+
+	// Rows created from now on could shift the pages of a running export
+	if now := time.Now(); req.Until.After(now) {
+		req.Until = now
+	}
+	if !req.Since.Before(req.Until) {
+		return nil, errors.New("since must be before until")
+	}
+	if req.Until.Sub(req.Since) > maxExportRange {
+		return nil, fmt.Errorf("exports cover at most %d days", int(maxExportRange.Hours()/24))
+	}
+
+	columns := exportColumns(req.Dataset, append(slices.Clone(s.excluded[req.Dataset]), req.ExcludeColumns...))
+	if len(columns) == 0 {
+		return nil, errors.New("every column is excluded")
+	}
+
+	export := &domain.DataExport{
+		ID:      uuid.New(),
+		Dataset: req.Dataset,
+		Format:  req.Format,
+		Since:   req.Since.UTC(),
+		Until:   req.Until.UTC(),
+		Columns: strings.Join(columns, ","),
+		Status:  domain.ExportStatusPending,
+	}
+	if requestedBy, err := uuid.Parse(actorID); err == nil {
+		export.RequestedBy = &requestedBy
+	}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// run writes the export to a temporary file, then uploads it, so a failed
+// query never leaves a partial object in storage
+func (s *ExportService) run(ctx context.Context, export *domain.DataExport) error {
+	export.Status = domain.ExportStatusRunning
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "export-*.csv")
+	if err != nil {
+		s.fail(ctx, export, err)
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	rows, err := s.write(ctx, export, file)
+	if err == nil {
+		err = s.upload(ctx, export, file)
+	}
+	if err != nil {
+		s.fail(ctx, export, err)
+		return err
+	}
+
+	completedAt := time.Now()
+	export.Status = domain.ExportStatusCompleted
+	export.Rows = rows
+	export.CompletedAt = &completedAt
+	return s.exportRepo.Update(ctx, export)
+}
+
+func (s *ExportService) write(ctx context.Context, export *domain.DataExport, w io.Writer) (int64, error) {
+	columns := strings.Split(export.Columns, ",")
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err := s.eachRow(ctx, export, func(values map[string]string) error {
+		rows++
+		return writer.Write(selectExportValues(values, columns))
+	})
+	if err != nil {
+		return rows, err
+	}
+	writer.Flush()
+	return rows, writer.Error()
+}
+
+func (s *ExportService) upload(ctx context.Context, export *domain.DataExport, file *os.File) error {
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	export.ObjectKey = exportObjectKey(export)
+	return s.store.Put(ctx, export.ObjectKey, file, size, "text/csv")
+}
+
+func (s *ExportService) fail(ctx context.Context, export *domain.DataExport, cause error) {
+	message := cause.Error()
+	completedAt := time.Now()
+	export.Status = domain.ExportStatusFailed
+	export.ObjectKey = ""
+	export.Error = &message
+	export.CompletedAt = &completedAt
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		s.logger.Error("Failed to mark export as failed", zap.String("export_id", export.ID.String()), zap.Error(err))
+	}
+}
+
+// eachRow calls fn with every row of the export's dataset, keyed by column
+func (s *ExportService) eachRow(ctx context.Context, export *domain.DataExport, fn func(map[string]string) error) error {
+	switch export.Dataset {
+	case domain.ExportDatasetReports:
+		for offset := 0; ; offset += exportPageSize {
+			reports, err := s.moderationRepo.ListReportsCreatedBetween(ctx, export.Since, export.Until, exportPageSize, offset)
+			if err != nil {
+				return err
+			}
+			for _, report := range reports {
+				if err := fn(reportExportValues(report)); err != nil {
+					return err
+				}
+			}
+			if len(reports) < exportPageSize {
+				return nil
+			}
+		}
+
+	case domain.ExportDatasetAuditLogs:
+		for offset := 0; ; offset += exportPageSize {
+			logs, err := s.auditRepo.ListAuditLogsCreatedBetween(ctx, export.Since, export.Until, exportPageSize, offset)
+			if err != nil {
+				return err
+			}
+			for _, log := range logs {
+				if err := fn(auditLogExportValues(log)); err != nil {
+					return err
+				}
+			}
+			if len(logs) < exportPageSize {
+				return nil
+			}
+		}
+
+	case domain.ExportDatasetContentVolume:
+		volumes, err := s.metricsRepo.ContentVolumePerDay(ctx, export.Since)
+		if err != nil {
+			return err
+		}
+		for _, volume := range volumes {
+			if !volume.Day.Before(export.Until) {
+				continue
+			}
+			if err := fn(map[string]string{
+				"day":       volume.Day.UTC().Format(time.DateOnly),
+				"posts":     strconv.Itoa(volume.Posts),
+				"responses": strconv.Itoa(volume.Responses),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown export dataset %q", export.Dataset)
+}
+
+// checkExportColumns returns an error unless dataset is known and has every column
+func checkExportColumns(dataset domain.ExportDataset, columns []string) error {
+	known, ok := exportDatasetColumns[dataset]
+	if !ok {
+		return fmt.Errorf("unknown export dataset %q", dataset)
+	}
+	for _, column := range columns {
+		if !slices.ContainsFunc(known, func(c exportColumn) bool { return c.name == column }) {
+			return fmt.Errorf("dataset %s has no column %q", dataset, column)
+		}
+	}
+	return nil
+}
+
+// exportColumns returns the dataset's columns in file order, leaving out PII
+// and excluded columns
+func exportColumns(dataset domain.ExportDataset, excluded []string) []string {
+	var columns []string
+	for _, column := range exportDatasetColumns[dataset] {
+		if column.pii || slices.Contains(excluded, column.name) {
+			continue
+		}
+		columns = append(columns, column.name)
+	}
+	return columns
+}
+
+func selectExportValues(values map[string]string, columns []string) []string {
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = values[column]
+	}
+	return record
+}
+
+func exportObjectKey(export *domain.DataExport) string {
+	return fmt.Sprintf("exports/%s/%s_%s_%s.%s",
+		export.Dataset,
+		export.Since.Format("20060102T150405Z"),
+		export.Until.Format("20060102T150405Z"),
+		export.ID,
+		export.Format,
+	)
+}
+
+func reportExportValues(report *domain.ContentReport) map[string]string {
+	return map[string]string{
+		"id":             report.ID.String(),
+		"source":         report.Source,
+		"content_type":   report.ContentType,
+		"content_id":     report.ContentID,
+		"reason":         report.Reason,
+		"description":    report.Description,
+		"reporter_id":    formatExportID(report.ReporterID),
+		"status":         report.Status,
+		"severity":       report.Severity,
+		"reporter_count": strconv.Itoa(report.ReporterCount),
+		"report_score":   strconv.FormatFloat(report.ReportScore, 'f', -1, 64),
+		"reviewed_by":    formatExportID(report.ReviewedBy),
+		"reviewed_at":    formatExportTime(report.ReviewedAt),
+		"created_at":     formatExportTime(&report.CreatedAt),
+	}
+}
+
+func auditLogExportValues(log *domain.AuditLog) map[string]string {
+	values := map[string]string{
+		"id":          log.ID.String(),
+		"event_type":  string(log.EventType),
+		"actor_id":    formatExportID(log.ActorID),
+		"actor_ip":    log.ActorIP,
+		"target_id":   formatExportID(log.TargetID),
+		"target_type": log.TargetType,
+		"action":      log.Action,
+		"metadata":    log.Metadata,
+		"success":     strconv.FormatBool(log.Success),
+		"created_at":  formatExportTime(&log.CreatedAt),
+	}
+	if log.ErrorMessage != nil {
+		values["error_message"] = *log.ErrorMessage
+	}
+	return values
+}
+
+func formatExportID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+// TestParseExportExclusions tests that exclusions are parsed and unknown columns rejected
+func TestParseExportExclusions(t *testing.T) {
+	excluded, err := ParseExportExclusions([]string{"reports.reviewed_by", "audit_logs.action", "reports.content_id"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[domain.ExportDataset][]string{
+		domain.ExportDatasetReports:   {"reviewed_by", "content_id"},
+		domain.ExportDatasetAuditLogs: {"action"},
+	}, excluded)
+
+	for _, entry := range []string{"reports", "journal.body", "reports.username"} {
+		_, err := ParseExportExclusions([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
+
+// TestExportColumns tests that PII columns are always left out
+func TestExportColumns(t *testing.T) {
+	assert.Equal(t, []string{"id", "event_type", "target_type", "action", "success", "created_at"},
+		exportColumns(domain.ExportDatasetAuditLogs, nil))
+	assert.Equal(t, []string{"id", "event_type", "target_type", "success", "created_at"},
+		exportColumns(domain.ExportDatasetAuditLogs, []string{"action", "actor_ip"}))
+	assert.Equal(t, []string{"day", "posts", "responses"},
+		exportColumns(domain.ExportDatasetContentVolume, nil))
+}
+
+// TestReportExportValues tests that a report row carries no reporter or description
+func TestReportExportValues(t *testing.T) {
+	reporterID := uuid.New()
+	report := &domain.ContentReport{
+		ID:          uuid.New(),
+		ReporterID:  &reporterID,
+		Source:      "user",
+		ContentType: "post",
+		ContentID:   "abc",
+		Reason:      "spam",
+		Description: "my phone number is 555-0100",
+		Status:      "pending",
+		Severity:    "low",
+		ReportScore: 1.5,
+		CreatedAt:   time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC),
+	}
+
+	columns := exportColumns(domain.ExportDatasetReports, nil)
+	record := selectExportValues(reportExportValues(report), columns)
+	assert.Len(t, record, len(columns))
+	assert.NotContains(t, record, reporterID.String())
+	assert.NotContains(t, record, report.Description)
+	assert.Contains(t, record, "1.5")
+	assert.Contains(t, record, "2026-10-15T09:30:00Z")
+}
+
+// TestExportObjectKey tests that keys name the dataset and range
+func TestExportObjectKey(t *testing.T) {
+	export := &domain.DataExport{
+		ID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		Dataset: domain.ExportDatasetReports,
+		Format:  domain.ExportFormatCSV,
+		Since:   time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+		Until:   time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, "exports/reports/20261014T000000Z_20261015T000000Z_00000000-0000-0000-0000-000000000001.csv", exportObjectKey(export))
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+const (
+	featureFlagsCacheKey = "feature_flags"
+	// featureFlagsTTL bounds how long other instances take to see a toggle
+	featureFlagsTTL = time.Minute
+)
+
+// featureFlagKeyPattern keeps flag keys to short snake_case names
+var featureFlagKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// FeatureFlagService stores feature flags and answers whether one is on.
+// Unknown flags are off.
+type FeatureFlagService struct {
+	flagRepo  repository.FeatureFlagRepository
+	cacheRepo repository.CacheRepository
+}
+
+func NewFeatureFlagService(flagRepo repository.FeatureFlagRepository, cacheRepo repository.CacheRepository) *FeatureFlagService {
+	return &FeatureFlagService{
+		flagRepo:  flagRepo,
+		cacheRepo: cacheRepo,
+	}
+}
+
+// ListFlags returns every stored flag, read fresh from the database
+func (s *FeatureFlagService) ListFlags(ctx context.Context) ([]*domain.FeatureFlag, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "FeatureFlagService.ListFlags")
+	defer span.End()
+
+	return s.flagRepo.List(ctx)
+}
+
+// SetFlag turns a flag on or off, creating it if needed. actorID is empty
+// when a service client sets it.
+func (s *FeatureFlagService) SetFlag(ctx context.Context, key string, enabled bool, actorID string) (*domain.FeatureFlag, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "FeatureFlagService.SetFlag")
+	defer span.End()
+
+	if !validFeatureFlagKey(key) {
+		return nil, fmt.Errorf("feature flag key %q must be lowercase snake_case of at most 64 characters", key)
+	}
+
+	var updatedBy *uuid.UUID
+	if id, err := uuid.Parse(actorID); err == nil {
+		updatedBy = &id
+	}
+
+	flag, err := s.flagRepo.Set(ctx, key, enabled, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.cacheRepo.Delete(ctx, featureFlagsCacheKey)
+	return flag, nil
+}
+
+// IsEnabled reports whether the flag is on. Flags are cached for a minute,
+// and read as off if they can't be loaded.
+func (s *FeatureFlagService) IsEnabled(ctx context.Context, key string) bool {
+	if cached, err := s.cacheRepo.Get(ctx, featureFlagsCacheKey); err == nil {
+		var enabled map[string]bool
+		if err := json.Unmarshal([]byte(cached), &enabled); err == nil {
+			return enabled[key]
+		}
+	}
+
+	flags, err := s.flagRepo.List(ctx)
+	if err != nil {
+		return false
+	}
+	enabled := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		enabled[flag.Key] = flag.Enabled
+	}
+	_ = s.cacheRepo.Set(ctx, featureFlagsCacheKey, enabled, featureFlagsTTL)
+	return enabled[key]
+}
+
+// validFeatureFlagKey reports whether key is a valid flag name
+func validFeatureFlagKey(key string) bool {
+	return featureFlagKeyPattern.MatchString(key)
+}
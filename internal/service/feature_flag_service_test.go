@@ -0,0 +1,21 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidFeatureFlagKey tests that flag keys must be short snake_case names
+func TestValidFeatureFlagKey(t *testing.T) {
+	assert.True(t, validFeatureFlagKey("voice_notes"))
+	assert.True(t, validFeatureFlagKey("feed_v2"))
+	assert.True(t, validFeatureFlagKey("a"+strings.Repeat("b", 63)))
+
+	assert.False(t, validFeatureFlagKey(""))
+	assert.False(t, validFeatureFlagKey("Voice_Notes"))
+	assert.False(t, validFeatureFlagKey("2fa"))
+	assert.False(t, validFeatureFlagKey("voice-notes"))
+	assert.False(t, validFeatureFlagKey("a"+strings.Repeat("b", 64)))
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/cache"
+)
+
+// feedFallbackTTL is how long the last page read for each feed is kept to be
+// served while MongoDB's circuit breaker is open
+const feedFallbackTTL = time.Hour
+
+// maxFallbackDigests bounds the pages whose last written copy is remembered
+const maxFallbackDigests = 10000
+
+// feedFallbacks keeps a copy of the last page read for each feed. Copies are
+// stored under the feed's own cache key, so invalidating a feed, on a new
+// post or a withdrawn one, drops its copy too. A copy is only rewritten when
+// its page changed or it is halfway to expiring.
+type feedFallbacks struct {
+	cache *cache.Cache
+
+	mu      sync.Mutex
+	written map[string]fallbackDigest
+}
+
+type fallbackDigest struct {
+	sum       [sha256.Size]byte
+	writtenAt time.Time
+}
+
+func newFeedFallbacks(c *cache.Cache) *feedFallbacks {
+	return &feedFallbacks{cache: c, written: make(map[string]fallbackDigest)}
+}
+
+func fallbackKey(feedKey string) string {
+	return feedKey + ":fallback"
+}
+
+// save stores page as the fallback for the feed at feedKey
+func (f *feedFallbacks) save(ctx context.Context, feedKey string, page *domain.FeedPage) {
+	data, err := json.Marshal(page)
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(data)
+
+	f.mu.Lock()
+	last, ok := f.written[feedKey]
+	f.mu.Unlock()
+	if ok && last.sum == sum && time.Since(last.writtenAt) < feedFallbackTTL/2 {
+		return
+	}
+
+	if err := f.cache.Set(ctx, fallbackKey(feedKey), json.RawMessage(data), feedFallbackTTL); err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	if len(f.written) >= maxFallbackDigests {
+		clear(f.written)
+	}
+	f.written[feedKey] = fallbackDigest{sum: sum, writtenAt: time.Now()}
+	f.mu.Unlock()
+}
+
+// load reads the fallback for the feed at feedKey into page
+func (f *feedFallbacks) load(ctx context.Context, feedKey string, page *domain.FeedPage) bool {
+	hit, _ := f.cache.Get(ctx, fallbackKey(feedKey), page)
+	return hit
+}
+
+// forget drops the remembered copies after their keys were deleted, so the
+// next read of each feed writes its copy again
+func (f *feedFallbacks) forget() {
+	f.mu.Lock()
+	clear(f.written)
+	f.mu.Unlock()
+}
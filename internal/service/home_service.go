@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxBatchGetSize bounds the IDs one batch get may ask for
+const maxBatchGetSize = 100
+
+// Home screen section sizes
+const (
+	defaultHomeFeedLimit          = 20
+	maxHomeFeedLimit              = 50
+	defaultHomeNotificationsLimit = 5
+	maxHomeNotificationsLimit     = 20
+)
+
+// HomeScreen is what the app's home screen shows on launch
+type HomeScreen struct {
+	Feed          *domain.FeedPage
+	Notifications []*domain.Notification // Unread only, newest first
+	UnreadCount   int64
+	Streak        *domain.UserTracker
+}
+
+// HomeService loads the home screen in one call, so the app needs a single
+// round trip on a cold start
+type HomeService struct {
+	postService         PostServiceInterface
+	notificationService NotificationServiceInterface
+	userService         UserServiceInterface
+}
+
+func NewHomeService(postService PostServiceInterface, notificationService NotificationServiceInterface, userService UserServiceInterface) *HomeService {
+	return &HomeService{
+		postService:         postService,
+		notificationService: notificationService,
+		userService:         userService,
+	}
+}
+
+// GetHomeScreen loads the public feed, unread notifications and streak
+// concurrently. A limit of zero or less picks the default.
+func (s *HomeService) GetHomeScreen(ctx context.Context, userID string, feedLimit, notificationLimit int) (*HomeScreen, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "HomeService.GetHomeScreen")
+	defer span.End()
+
+	feedLimit = clampLimit(feedLimit, defaultHomeFeedLimit, maxHomeFeedLimit)
+	notificationLimit = clampLimit(notificationLimit, defaultHomeNotificationsLimit, maxHomeNotificationsLimit)
+
+	var home HomeScreen
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		page, err := s.postService.GetFeed(ctx, userID, nil, nil, nil, feedLimit, 0)
+		home.Feed = page
+		return err
+	})
+	g.Go(func() error {
+		notifications, unread, err := s.notificationService.ListNotifications(ctx, userID, true, notificationLimit, 0)
+		home.Notifications, home.UnreadCount = notifications, unread
+		return err
+	})
+	g.Go(func() error {
+		streak, err := s.userService.GetStreak(ctx, userID)
+		if err != nil && err.Error() == "tracker not found" {
+			// Users who haven't checked in yet have no streak
+			streak, err = &domain.UserTracker{}, nil
+		}
+		home.Streak = streak
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return &home, nil
+}
+
+func clampLimit(limit, defaultLimit, maxLimit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	if limit > maxLimit {
+		return maxLimit
+	}
+	return limit
+}
+
+// batchIDs drops repeated IDs, keeping the first of each, and rejects
+// batches that are empty or too large
+func batchIDs(ids []string) ([]string, error) {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, fmt.Errorf("at least one ID is required")
+	}
+	if len(unique) > maxBatchGetSize {
+		return nil, fmt.Errorf("at most %d IDs can be fetched at once", maxBatchGetSize)
+	}
+	return unique, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBatchIDs tests that batches are de-duplicated in order and bounded
+func TestBatchIDs(t *testing.T) {
+	ids, err := batchIDs([]string{"b", "a", "b", "c", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a", "c"}, ids)
+
+	_, err = batchIDs(nil)
+	assert.Error(t, err)
+
+	tooMany := make([]string, maxBatchGetSize+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprint(i)
+	}
+	_, err = batchIDs(tooMany)
+	assert.Error(t, err)
+
+	// Repeats don't count toward the limit
+	_, err = batchIDs(append(tooMany[:maxBatchGetSize], "0"))
+	assert.NoError(t, err)
+}
+
+// TestClampLimit tests home screen section limits
+func TestClampLimit(t *testing.T) {
+	assert.Equal(t, defaultHomeFeedLimit, clampLimit(0, defaultHomeFeedLimit, maxHomeFeedLimit))
+	assert.Equal(t, defaultHomeFeedLimit, clampLimit(-5, defaultHomeFeedLimit, maxHomeFeedLimit))
+	assert.Equal(t, 10, clampLimit(10, defaultHomeFeedLimit, maxHomeFeedLimit))
+	assert.Equal(t, maxHomeFeedLimit, clampLimit(500, defaultHomeFeedLimit, maxHomeFeedLimit))
+}
@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/dto"
+	"github.com/yourorg/anonymous-support/internal/pkg/authz"
 	"github.com/yourorg/anonymous-support/internal/pkg/feed"
 )
 
@@ -14,6 +16,7 @@ type AuthServiceInterface interface {
 	RegisterAnonymous(ctx context.Context, username string) (*dto.AuthResponse, error)
 	RegisterWithEmail(ctx context.Context, req *dto.RegisterWithEmailRequest) (*dto.AuthResponse, error)
 	Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error)
+	VerifyLoginChallenge(ctx context.Context, challengeID, code string) (*dto.AuthResponse, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*dto.AuthResponse, error)
 	Logout(ctx context.Context, userID uuid.UUID) error
 }
@@ -21,25 +24,67 @@ type AuthServiceInterface interface {
 // UserServiceInterface defines the user service interface
 type UserServiceInterface interface {
 	GetProfile(ctx context.Context, userID string) (*domain.User, error)
-	UpdateProfile(ctx context.Context, userID string, username *string, avatarID *int) error
+	UpdateProfile(ctx context.Context, userID string, username *string, avatarID *int, shareMilestones *bool, timezone *string) error
+	GetUserSummaries(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*domain.UserSummary, error)
+	BatchGetUsers(ctx context.Context, userIDs []string) ([]*domain.User, error)
 	GetStreak(ctx context.Context, userID string) (*domain.UserTracker, error)
-	UpdateStreak(ctx context.Context, userID string, hadRelapse bool) (int, error)
+	UpdateStreak(ctx context.Context, userID string, hadRelapse bool, moodScore int) (int, error)
+	GetMoodHistory(ctx context.Context, userID string, days int) ([]*domain.MoodEntry, []MoodTrend, error)
+	RecordRelapse(ctx context.Context, userID string, trigger domain.RelapseTrigger, occurredAt time.Time, timezone, note string) (int, error)
+	GetStreakFreezes(ctx context.Context, userID string) (*StreakFreezeStatus, error)
+	UseStreakFreeze(ctx context.Context, userID string) (*StreakFreezeStatus, error)
+	SetSavingsBaseline(ctx context.Context, userID string, dailySpendCents int64, currency string, dailyMinutes int) (*Savings, error)
+}
+
+// ProgressServiceInterface defines the recovery progress service interface
+type ProgressServiceInterface interface {
+	GetDashboard(ctx context.Context, userID string) (*ProgressDashboard, error)
+	RecordCheckIn(ctx context.Context, userID string, hadRelapse bool, moodScore int, answers []domain.CheckInAnswer) error
+	ListCheckInQuestions() []CheckInQuestion
+	RecordCraving(ctx context.Context, userID string, resisted bool) error
+	RecordRelapse(ctx context.Context, userID string, trigger domain.RelapseTrigger, occurredAt time.Time, loc *time.Location, note string) (int, error)
+	GetAchievements(ctx context.Context, userID string) ([]Achievement, error)
+	ListCopingStrategies() []CopingStrategy
+	RecordCopingUse(ctx context.Context, userID, strategyID string, helped bool) (*CopingEffectiveness, error)
+}
+
+// JournalServiceInterface defines the private journal service interface
+type JournalServiceInterface interface {
+	CreateEntry(ctx context.Context, userID, content string, moodScore *int, triggers []domain.RelapseTrigger) (*domain.JournalEntry, error)
+	ListEntries(ctx context.Context, userID, query string, limit, offset int) ([]*domain.JournalEntry, error)
+}
+
+// BlockServiceInterface defines the user blocking service interface
+type BlockServiceInterface interface {
+	BlockUser(ctx context.Context, blockerID, blockedID string) error
+	UnblockUser(ctx context.Context, blockerID, blockedID string) error
+	IsBlockedEitherWay(ctx context.Context, userA, userB string) (bool, error)
 }
 
 // PostServiceInterface defines the post service interface
 type PostServiceInterface interface {
-	CreatePost(ctx context.Context, userID, username string, postType domain.PostType, content string, categories []string, urgencyLevel int, timeContext string, daysSinceRelapse int, tags []string, visibility string, circleID *string) (*domain.Post, error)
-	GetPost(ctx context.Context, postID string) (*domain.Post, error)
-	GetFeed(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) ([]*domain.Post, error)
+	CreatePost(ctx context.Context, userID, username string, postType domain.PostType, content string, categories []string, urgencyLevel int, timeContext string, daysSinceRelapse int, tags []string, visibility string, circleID *string, attachments []domain.Attachment) (*domain.Post, error)
+	GetPost(ctx context.Context, postID, viewerID string) (*domain.Post, error)
+	BatchGetPosts(ctx context.Context, postIDs []string, viewerID string) ([]*domain.Post, error)
+	GetFeed(ctx context.Context, viewerID string, categories []string, circleID *string, postType *domain.PostType, limit, offset int) (*domain.FeedPage, error)
+	GetFeedAfter(ctx context.Context, viewerID string, categories []string, circleID *string, postType *domain.PostType, cursor string, limit int) (*domain.FeedCursorPage, error)
+	StreamFeed(ctx context.Context, viewerID string, filter FeedFilter, send func(*domain.Post) error) error
 	DeletePost(ctx context.Context, postID, userID string) error
 	UpdatePostUrgency(ctx context.Context, postID string, urgencyLevel int) error
 	GetPersonalizedFeed(ctx context.Context, userPrefs *feed.UserPreferences, limit, offset int) ([]*domain.Post, error)
 }
 
+// ReactionServiceInterface defines the post reaction service interface
+type ReactionServiceInterface interface {
+	React(ctx context.Context, userID, postID string, reaction domain.ReactionType) error
+	Unreact(ctx context.Context, userID, postID string) error
+	ViewerReactions(ctx context.Context, viewerID string, postIDs []string) (map[string]domain.ReactionType, error)
+}
+
 // SupportServiceInterface defines the support service interface
 type SupportServiceInterface interface {
 	CreateResponse(ctx context.Context, userID, username, postID string, responseType domain.ResponseType, content string, voiceNoteURL *string) (string, int, error)
-	GetResponses(ctx context.Context, postID string, limit, offset int) ([]*domain.SupportResponse, error)
+	GetResponses(ctx context.Context, postID, viewerID string, limit, offset int) ([]*domain.SupportResponse, error)
 	QuickSupport(ctx context.Context, userID, postID, messageType string) (int, error)
 	GetSupportStats(ctx context.Context, userID string) (given, received int64, strengthPoints, peopleHelped int, error error)
 }
@@ -52,6 +97,12 @@ type CircleServiceInterface interface {
 	GetCircleMembers(ctx context.Context, circleID string, limit, offset int) ([]*domain.CircleMembership, error)
 	GetCircleFeed(ctx context.Context, circleID string, limit, offset int) ([]*domain.Post, error)
 	GetCircles(ctx context.Context, category *string, limit, offset int) ([]*domain.Circle, error)
+	IsCircleMember(ctx context.Context, userID, circleID string) (bool, error)
+	CircleRole(ctx context.Context, userID, circleID string) (domain.CircleRole, error)
+	AuthorizeCircle(ctx context.Context, userID string, role domain.Role, circleID string, permission authz.Permission) error
+	SetMemberRole(ctx context.Context, actorID string, actorRole domain.Role, circleID, targetID string, role domain.CircleRole) error
+	RemoveMember(ctx context.Context, actorID string, actorRole domain.Role, circleID, targetID string) error
+	DeleteCircle(ctx context.Context, actorID string, actorRole domain.Role, circleID, reason string) error
 }
 
 // ModerationServiceInterface defines the moderation service interface
@@ -59,6 +110,75 @@ type ModerationServiceInterface interface {
 	ReportContent(ctx context.Context, reporterID, contentType, contentID, reason, description string) (string, error)
 	GetReports(ctx context.Context, status *string, limit, offset int) ([]*domain.ContentReport, error)
 	ModerateContent(ctx context.Context, reportID, reviewerID, action string) error
+	GetReport(ctx context.Context, reportID string) (*domain.ContentReport, []*domain.ModeratorNote, error)
+	AddNote(ctx context.Context, reportID, authorID, body string, parentID *string) (*domain.ModeratorNote, error)
+	GetStats(ctx context.Context) ([]*domain.ModerationSeverityStats, error)
+}
+
+// NotificationServiceInterface defines the in-app notification inbox interface
+type NotificationServiceInterface interface {
+	ListNotifications(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]*domain.Notification, int64, error)
+	MarkRead(ctx context.Context, userID string, notificationIDs []string) (int64, error)
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	GetUnreadCount(ctx context.Context, userID string) (int64, error)
+	GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, prefs *domain.NotificationPreferences) error
+}
+
+// HomeServiceInterface defines the app home screen interface
+type HomeServiceInterface interface {
+	GetHomeScreen(ctx context.Context, userID string, feedLimit, notificationLimit int) (*HomeScreen, error)
+}
+
+// SOSServiceInterface defines the SOS helper subscription interface
+type SOSServiceInterface interface {
+	GetSubscriptions(ctx context.Context, userID string) ([]string, error)
+	UpdateSubscriptions(ctx context.Context, userID string, categories []string) ([]string, error)
+}
+
+// EntitlementServiceInterface defines the plans and entitlements interface
+type EntitlementServiceInterface interface {
+	GetEntitlements(ctx context.Context, userID string) (*Entitlements, error)
+	SetPlan(ctx context.Context, actorID, userID string, plan domain.Plan, expiresAt *time.Time) (*domain.Subscription, error)
+}
+
+// CrisisResourceServiceInterface defines the crisis resources interface
+type CrisisResourceServiceInterface interface {
+	ListCrisisResources(ctx context.Context, userID, country, region string, types []domain.CrisisResourceType) (string, []domain.CrisisResource, error)
+}
+
+// AdminAnalyticsServiceInterface defines the admin platform metrics interface
+type AdminAnalyticsServiceInterface interface {
+	GetPlatformMetrics(ctx context.Context) (*PlatformMetrics, error)
+}
+
+// AdminServiceInterface defines the admin user, feature flag and stats interface
+type AdminServiceInterface interface {
+	SearchUsers(ctx context.Context, filter domain.UserSearch) ([]*domain.User, int, error)
+	BanUser(ctx context.Context, actorID string, actorRole domain.Role, userID, reason string) error
+	UnbanUser(ctx context.Context, actorID string, actorRole domain.Role, userID, reason string) error
+	SetUserRole(ctx context.Context, actorID, userID string, role domain.Role) error
+	ListFeatureFlags(ctx context.Context) ([]*domain.FeatureFlag, error)
+	SetFeatureFlag(ctx context.Context, actorID, key string, enabled bool) (*domain.FeatureFlag, error)
+	GetSystemStats(ctx context.Context) (*domain.SystemStats, error)
+}
+
+// ExportServiceInterface defines the admin data export interface
+type ExportServiceInterface interface {
+	StartExport(ctx context.Context, actorID string, req ExportRequest) (*domain.DataExport, error)
+	GetExport(ctx context.Context, exportID string) (*domain.DataExport, error)
+	ListExports(ctx context.Context, limit, offset int) ([]*domain.DataExport, error)
+}
+
+// RuntimeSettingsServiceInterface defines the admin runtime settings interface
+type RuntimeSettingsServiceInterface interface {
+	List(ctx context.Context) ([]*RuntimeSettingInfo, error)
+	Set(ctx context.Context, actorID, key, value string) (*RuntimeSettingInfo, error)
+}
+
+// CommunityStatsServiceInterface defines the public community stats interface
+type CommunityStatsServiceInterface interface {
+	GetCommunityStats(ctx context.Context) (*CommunityStats, error)
 }
 
 // AnalyticsServiceInterface defines the analytics service interface
@@ -9,23 +9,29 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
 type InviteService struct {
 	inviteRepo repository.InviteRepository
 	circleRepo repository.CircleRepository
+	notifier   *NotificationService
 }
 
-func NewInviteService(inviteRepo repository.InviteRepository, circleRepo repository.CircleRepository) *InviteService {
+func NewInviteService(inviteRepo repository.InviteRepository, circleRepo repository.CircleRepository, notifier *NotificationService) *InviteService {
 	return &InviteService{
 		inviteRepo: inviteRepo,
 		circleRepo: circleRepo,
+		notifier:   notifier,
 	}
 }
 
 // CreateInvite generates a new invite for a circle
 func (s *InviteService) CreateInvite(ctx context.Context, circleID, createdBy string, maxUses int, expiresIn time.Duration) (*domain.Invite, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "InviteService.CreateInvite")
+	defer span.End()
+
 	// Verify user has permission to create invites (is circle owner/admin)
 	circleUUID, err := uuid.Parse(circleID)
 	if err != nil {
@@ -71,6 +77,9 @@ func (s *InviteService) CreateInvite(ctx context.Context, circleID, createdBy st
 
 // AcceptInvite joins a circle using an invite code
 func (s *InviteService) AcceptInvite(ctx context.Context, code, userID string) (*domain.Circle, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "InviteService.AcceptInvite")
+	defer span.End()
+
 	// Get invite
 	invite, err := s.inviteRepo.GetByCode(ctx, code)
 	if err != nil {
@@ -102,11 +111,18 @@ func (s *InviteService) AcceptInvite(ctx context.Context, code, userID string) (
 		return nil, err
 	}
 
+	if invite.CreatedBy.String() != userID {
+		_ = s.notifier.NotifyCircleInviteAccepted(ctx, invite.CreatedBy.String(), circle.ID.String(), circle.Name)
+	}
+
 	return circle, nil
 }
 
 // RevokeInvite deactivates an invite
 func (s *InviteService) RevokeInvite(ctx context.Context, inviteID, userID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "InviteService.RevokeInvite")
+	defer span.End()
+
 	inviteUUID, err := uuid.Parse(inviteID)
 	if err != nil {
 		return fmt.Errorf("invalid invite ID")
@@ -133,6 +149,9 @@ func (s *InviteService) RevokeInvite(ctx context.Context, inviteID, userID strin
 
 // GetCircleInvites returns all active invites for a circle
 func (s *InviteService) GetCircleInvites(ctx context.Context, circleID, userID string) ([]*domain.Invite, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "InviteService.GetCircleInvites")
+	defer span.End()
+
 	circleUUID, err := uuid.Parse(circleID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid circle ID")
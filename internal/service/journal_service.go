@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/encryption"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+const (
+	maxJournalEntryLength = 10000
+	maxJournalSearchTerms = 10
+)
+
+// JournalService manages private journal entries. Entry content is encrypted
+// at rest and searched through blind-indexed words, and entries are only
+// ever returned to their author.
+type JournalService struct {
+	journalRepo repository.JournalRepository
+	encManager  *encryption.Manager
+}
+
+func NewJournalService(journalRepo repository.JournalRepository, encManager *encryption.Manager) *JournalService {
+	return &JournalService{
+		journalRepo: journalRepo,
+		encManager:  encManager,
+	}
+}
+
+// CreateEntry encrypts and stores a journal entry with optional mood and trigger tags
+func (s *JournalService) CreateEntry(ctx context.Context, userID, content string, moodScore *int, triggers []domain.RelapseTrigger) (*domain.JournalEntry, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "JournalService.CreateEntry")
+	defer span.End()
+
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("journal entry cannot be empty")
+	}
+	if utf8.RuneCountInString(content) > maxJournalEntryLength {
+		return nil, fmt.Errorf("journal entry must be at most %d characters", maxJournalEntryLength)
+	}
+	if moodScore != nil && (*moodScore < domain.MinMoodScore || *moodScore > domain.MaxMoodScore) {
+		return nil, fmt.Errorf("mood score must be between %d and %d", domain.MinMoodScore, domain.MaxMoodScore)
+	}
+	for _, trigger := range triggers {
+		if !domain.ValidRelapseTriggers[trigger] {
+			return nil, fmt.Errorf("unknown trigger %q", trigger)
+		}
+	}
+
+	encrypted, err := s.encManager.Encrypt(content)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &domain.JournalEntry{
+		UserID:       userID,
+		Content:      encrypted,
+		MoodScore:    moodScore,
+		Triggers:     triggers,
+		SearchTokens: s.blindIndex(JournalTokens(content)),
+	}
+	if err := s.journalRepo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	entry.Content = content
+	entry.SearchTokens = nil
+	return entry, nil
+}
+
+// ListEntries returns the user's journal entries, newest first. When query is
+// set, only entries containing every word of it are returned.
+func (s *JournalService) ListEntries(ctx context.Context, userID, query string, limit, offset int) ([]*domain.JournalEntry, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "JournalService.ListEntries")
+	defer span.End()
+
+	var entries []*domain.JournalEntry
+	var err error
+
+	if strings.TrimSpace(query) == "" {
+		entries, err = s.journalRepo.ListByUser(ctx, userID, limit, offset)
+	} else {
+		terms := JournalTokens(query)
+		if len(terms) == 0 {
+			return []*domain.JournalEntry{}, nil
+		}
+		if len(terms) > maxJournalSearchTerms {
+			return nil, fmt.Errorf("search is limited to %d words", maxJournalSearchTerms)
+		}
+		entries, err = s.journalRepo.Search(ctx, userID, s.blindIndex(terms), limit, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		content, err := s.encManager.Decrypt(entry.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt journal entry %s: %w", entry.ID.Hex(), err)
+		}
+		entry.Content = content
+	}
+	return entries, nil
+}
+
+func (s *JournalService) blindIndex(words []string) []string {
+	tokens := make([]string, len(words))
+	for i, word := range words {
+		tokens[i] = s.encManager.BlindIndex(word)
+	}
+	return tokens
+}
+
+// JournalTokens splits text into the distinct lowercase words used for search,
+// ignoring punctuation and single characters
+func JournalTokens(text string) []string {
+	seen := make(map[string]bool)
+	words := []string{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if utf8.RuneCountInString(word) < 2 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	return words
+}
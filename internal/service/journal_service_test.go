@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestJournalTokens tests that entries are split into distinct searchable words
+func TestJournalTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "punctuation and case", text: "Rough day. Work stress, STRESS!", want: []string{"rough", "day", "work", "stress"}},
+		{name: "single characters dropped", text: "a I ok", want: []string{"ok"}},
+		{name: "unicode letters", text: "día difícil", want: []string{"día", "difícil"}},
+		{name: "empty", text: "  ...  ", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, JournalTokens(tt.text))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/pkg/encryption"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+const keyRotationBatchSize = 500
+
+// KeyRotationService re-encrypts stored values with the current encryption
+// key, so previous keys can be retired once nothing depends on them
+type KeyRotationService struct {
+	userRepo   repository.UserRepository
+	encManager *encryption.Manager
+	logger     *zap.Logger
+}
+
+func NewKeyRotationService(userRepo repository.UserRepository, encManager *encryption.Manager, logger *zap.Logger) *KeyRotationService {
+	return &KeyRotationService{
+		userRepo:   userRepo,
+		encManager: encManager,
+		logger:     logger,
+	}
+}
+
+// ReencryptEmails re-encrypts every stored email not written with the primary
+// key and returns how many were rewritten. Emails that fail to decrypt are
+// logged and skipped so one bad row doesn't stall the rotation.
+func (s *KeyRotationService) ReencryptEmails(ctx context.Context) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "KeyRotationService.ReencryptEmails")
+	defer span.End()
+
+	rotated := 0
+	after := uuid.Nil
+	for {
+		users, err := s.userRepo.ListWithEmail(ctx, after, keyRotationBatchSize)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to list users: %w", err)
+		}
+
+		for _, user := range users {
+			if user.Email == nil || !s.encManager.NeedsRotation(*user.Email) {
+				continue
+			}
+
+			reencrypted, err := s.encManager.Reencrypt(*user.Email)
+			if err != nil {
+				s.logger.Warn("Failed to re-encrypt email", zap.String("user_id", user.ID.String()), zap.Error(err))
+				continue
+			}
+
+			// A user who changed their email meanwhile already has it under the primary key
+			replaced, err := s.userRepo.ReplaceEmail(ctx, user.ID, *user.Email, reencrypted)
+			if err != nil {
+				return rotated, fmt.Errorf("failed to store re-encrypted email: %w", err)
+			}
+			if replaced {
+				rotated++
+			}
+		}
+
+		if len(users) < keyRotationBatchSize {
+			return rotated, nil
+		}
+		after = users[len(users)-1].ID
+	}
+}
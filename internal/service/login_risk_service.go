@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"github.com/yourorg/anonymous-support/internal/pkg/geo"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// Login anomalies reported by LoginRiskService.Assess
+const (
+	LoginAnomalyNewCountry       = "new_country"
+	LoginAnomalyImpossibleTravel = "impossible_travel"
+	LoginAnomalyIPReputation     = "ip_reputation"
+)
+
+const (
+	loginChallengeTTL         = 10 * time.Minute
+	loginChallengeMaxAttempts = 5
+)
+
+// ErrInvalidLoginChallenge is returned for a wrong, expired or exhausted
+// step-up code
+var ErrInvalidLoginChallenge = errors.New("invalid or expired verification code")
+
+// minTravelDistanceKM is the distance below which travel is never flagged;
+// geo-IP results for the same client commonly differ by hundreds of km
+const minTravelDistanceKM = 500
+
+// LoginRiskService records where users log in from and flags logins from new
+// countries, impossible travel and IPs with many recent failed logins
+type LoginRiskService struct {
+	locationRepo       repository.LoginLocationRepository
+	riskRepo           repository.LoginRiskRepository
+	hasher             *fingerprint.Hasher
+	maxTravelSpeedKMH  float64
+	ipFailureThreshold int
+	ipFailureWindow    time.Duration
+}
+
+func NewLoginRiskService(
+	locationRepo repository.LoginLocationRepository,
+	riskRepo repository.LoginRiskRepository,
+	hasher *fingerprint.Hasher,
+	maxTravelSpeedKMH float64,
+	ipFailureThreshold int,
+	ipFailureWindow time.Duration,
+) *LoginRiskService {
+	return &LoginRiskService{
+		locationRepo:       locationRepo,
+		riskRepo:           riskRepo,
+		hasher:             hasher,
+		maxTravelSpeedKMH:  maxTravelSpeedKMH,
+		ipFailureThreshold: ipFailureThreshold,
+		ipFailureWindow:    ipFailureWindow,
+	}
+}
+
+// Assess returns the anomalies of a login by the user from the client on the
+// request context, or none. Countries are only compared against an existing
+// history, so a user's first login is never flagged as a new country.
+func (s *LoginRiskService) Assess(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "LoginRiskService.Assess")
+	defer span.End()
+
+	info := fingerprint.FromContext(ctx)
+	anomalies := []string{}
+
+	if ipHash := s.hasher.Hash(fingerprint.KindIP, info.IP); ipHash != "" && s.ipFailureThreshold > 0 {
+		failures, err := s.riskRepo.GetIPFailures(ctx, ipHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get IP failures: %w", err)
+		}
+		if failures >= int64(s.ipFailureThreshold) {
+			anomalies = append(anomalies, LoginAnomalyIPReputation)
+		}
+	}
+
+	if info.Location.Country == "" {
+		return anomalies, nil
+	}
+
+	latest, err := s.locationRepo.GetLatestLoginLocation(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login location: %w", err)
+	}
+	if latest == nil {
+		return anomalies, nil
+	}
+
+	if latest.Country != info.Location.Country {
+		known, err := s.locationRepo.HasLoginCountry(ctx, userID, info.Location.Country)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check login country: %w", err)
+		}
+		if !known {
+			anomalies = append(anomalies, LoginAnomalyNewCountry)
+		}
+	}
+
+	if impossibleTravel(latest, info.Location, time.Now(), s.maxTravelSpeedKMH) {
+		anomalies = append(anomalies, LoginAnomalyImpossibleTravel)
+	}
+
+	return anomalies, nil
+}
+
+// RecordSuccess remembers the location of a successful login. Coordinates are
+// rounded to about 11 km before they are stored.
+func (s *LoginRiskService) RecordSuccess(ctx context.Context, userID uuid.UUID) error {
+	loc := fingerprint.FromContext(ctx).Location
+	if loc.Country == "" {
+		return nil
+	}
+
+	location := &domain.LoginLocation{UserID: userID, Country: loc.Country}
+	if loc.HasCoordinates {
+		lat, lon := math.Round(loc.Latitude*10)/10, math.Round(loc.Longitude*10)/10
+		location.Latitude, location.Longitude = &lat, &lon
+	}
+	return s.locationRepo.RecordLoginLocation(ctx, location)
+}
+
+// RecordFailure counts a failed login against the client IP's reputation
+func (s *LoginRiskService) RecordFailure(ctx context.Context) error {
+	ipHash := s.hasher.Hash(fingerprint.KindIP, fingerprint.FromContext(ctx).IP)
+	if ipHash == "" || s.ipFailureThreshold <= 0 {
+		return nil
+	}
+	_, err := s.riskRepo.IncrementIPFailures(ctx, ipHash, s.ipFailureWindow)
+	return err
+}
+
+// StartChallenge creates a step-up challenge for the user and returns its ID
+// and the one-time code to deliver out of band
+func (s *LoginRiskService) StartChallenge(ctx context.Context, userID uuid.UUID, anomalies []string) (string, string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", "", err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	challenge := &domain.LoginChallenge{
+		ID:      uuid.New().String(),
+		UserID:  userID,
+		Reasons: anomalies,
+	}
+	challenge.CodeHash = hashChallengeCode(challenge.ID, code)
+	if err := s.riskRepo.CreateLoginChallenge(ctx, challenge, loginChallengeTTL); err != nil {
+		return "", "", fmt.Errorf("failed to store login challenge: %w", err)
+	}
+	return challenge.ID, code, nil
+}
+
+// VerifyChallenge checks a step-up code and consumes the challenge on
+// success. A challenge is discarded after too many wrong codes.
+func (s *LoginRiskService) VerifyChallenge(ctx context.Context, id, code string) (*domain.LoginChallenge, error) {
+	challenge, err := s.riskRepo.GetLoginChallenge(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, ErrInvalidLoginChallenge
+	}
+
+	attempts, err := s.riskRepo.IncrementLoginChallengeAttempts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attempts < 0 || attempts > loginChallengeMaxAttempts {
+		_ = s.riskRepo.DeleteLoginChallenge(ctx, id)
+		return nil, ErrInvalidLoginChallenge
+	}
+
+	if !hmac.Equal([]byte(hashChallengeCode(id, code)), []byte(challenge.CodeHash)) {
+		return nil, ErrInvalidLoginChallenge
+	}
+	if err := s.riskRepo.DeleteLoginChallenge(ctx, id); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+func hashChallengeCode(id, code string) string {
+	hash := sha256.Sum256([]byte(id + ":" + code))
+	return hex.EncodeToString(hash[:])
+}
+
+// impossibleTravel reports whether getting from the previous login location
+// to the current one by now needs a speed above maxSpeedKMH
+func impossibleTravel(prev *domain.LoginLocation, cur geo.Location, now time.Time, maxSpeedKMH float64) bool {
+	if maxSpeedKMH <= 0 || !cur.HasCoordinates || prev.Latitude == nil || prev.Longitude == nil {
+		return false
+	}
+
+	distance := geo.Distance(*prev.Latitude, *prev.Longitude, cur.Latitude, cur.Longitude)
+	if distance < minTravelDistanceKM {
+		return false
+	}
+
+	hours := now.Sub(prev.LastSeenAt).Hours()
+	if hours <= 0 {
+		return true
+	}
+	return distance/hours > maxSpeedKMH
+}
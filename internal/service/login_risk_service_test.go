@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/geo"
+)
+
+// TestImpossibleTravel tests that only journeys faster than the maximum speed are flagged
+func TestImpossibleTravel(t *testing.T) {
+	now := time.Date(2026, 5, 20, 15, 0, 0, 0, time.UTC)
+	lat, lon := 51.5, -0.1 // London
+	london := func(ago time.Duration) *domain.LoginLocation {
+		return &domain.LoginLocation{Country: "GB", Latitude: &lat, Longitude: &lon, LastSeenAt: now.Add(-ago)}
+	}
+	newYork := geo.Location{Country: "US", Latitude: 40.7, Longitude: -74.0, HasCoordinates: true}
+	paris := geo.Location{Country: "FR", Latitude: 48.9, Longitude: 2.4, HasCoordinates: true}
+
+	// About 5570 km: an hour is impossible, a day is a flight
+	assert.True(t, impossibleTravel(london(time.Hour), newYork, now, 1000))
+	assert.False(t, impossibleTravel(london(24*time.Hour), newYork, now, 1000))
+
+	// Nearby locations are within geo-IP error, however quick
+	assert.False(t, impossibleTravel(london(time.Minute), paris, now, 1000))
+
+	// Missing coordinates and a disabled check never flag
+	assert.False(t, impossibleTravel(london(time.Hour), geo.Location{Country: "US"}, now, 1000))
+	assert.False(t, impossibleTravel(&domain.LoginLocation{Country: "GB", LastSeenAt: now}, newYork, now, 1000))
+	assert.False(t, impossibleTravel(london(time.Hour), newYork, now, 0))
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+// MilestoneService celebrates streak milestones by notifying the user and, if
+// they opted in, posting a Victory in each of their circles
+type MilestoneService struct {
+	userRepo    repository.UserRepository
+	circleRepo  repository.CircleRepository
+	postService *PostService
+	notifier    *NotificationService
+	logger      *zap.Logger
+}
+
+func NewMilestoneService(
+	userRepo repository.UserRepository,
+	circleRepo repository.CircleRepository,
+	postService *PostService,
+	notifier *NotificationService,
+	logger *zap.Logger,
+) *MilestoneService {
+	return &MilestoneService{
+		userRepo:    userRepo,
+		circleRepo:  circleRepo,
+		postService: postService,
+		notifier:    notifier,
+		logger:      logger,
+	}
+}
+
+// Celebrate handles a milestone event published by ProgressService
+func (s *MilestoneService) Celebrate(ctx context.Context, event MilestoneEvent) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "MilestoneService.Celebrate")
+	defer span.End()
+
+	if err := s.notifier.NotifyMilestone(ctx, event.UserID, event.Days, event.Name); err != nil {
+		s.logger.Warn("Failed to send milestone notification", zap.String("user_id", event.UserID), zap.Error(err))
+	}
+
+	if err := s.shareVictory(ctx, event); err != nil {
+		s.logger.Warn("Failed to share milestone victory", zap.String("user_id", event.UserID), zap.Error(err))
+	}
+}
+
+// CelebrateAchievement notifies the user of a newly unlocked achievement
+// published by ProgressService
+func (s *MilestoneService) CelebrateAchievement(ctx context.Context, event AchievementEvent) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "MilestoneService.CelebrateAchievement")
+	defer span.End()
+
+	if err := s.notifier.NotifyAchievement(ctx, event.UserID, event.Achievement.ID, event.Achievement.Title, event.Achievement.Description); err != nil {
+		s.logger.Warn("Failed to send achievement notification", zap.String("user_id", event.UserID), zap.Error(err))
+	}
+}
+
+// shareVictory posts an auto-generated Victory in each of the user's circles
+// when they have opted in to sharing milestones
+func (s *MilestoneService) shareVictory(ctx context.Context, event MilestoneEvent) error {
+	uid, err := uuid.Parse(event.UserID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if !user.ShareMilestones {
+		return nil
+	}
+
+	circleIDs, err := s.circleRepo.GetUserCircleIDs(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("%s! I just reached %d days. Thank you all for the support along the way.", event.Name, event.Days)
+	for _, circleID := range circleIDs {
+		id := circleID.String()
+		if _, err := s.postService.CreatePost(ctx, event.UserID, user.Username, domain.PostTypeVictory, content,
+			nil, 0, "", event.Days, []string{"milestone"}, "circle", &id, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
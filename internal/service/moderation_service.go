@@ -2,48 +2,130 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/audit"
+	"github.com/yourorg/anonymous-support/internal/pkg/events"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
+// statsWindow is how far back resolved reports are included in moderation stats
+const statsWindow = 24 * time.Hour
+
 type ModerationService struct {
-	modRepo repository.ModerationRepository
+	modRepo       repository.ModerationRepository
+	postRepo      repository.PostRepository
+	sensitive     *SensitiveContent
+	autoModerator *AutoModerator
+	notifier      *NotificationService
+	audit         *audit.Writer
+	bus           events.EventBus
+	sla           map[string]time.Duration // Target resolution time by severity
 }
 
-func NewModerationService(modRepo repository.ModerationRepository) *ModerationService {
-	return &ModerationService{modRepo: modRepo}
+func NewModerationService(
+	modRepo repository.ModerationRepository,
+	postRepo repository.PostRepository,
+	sensitive *SensitiveContent,
+	autoModerator *AutoModerator,
+	notifier *NotificationService,
+	auditWriter *audit.Writer,
+	bus events.EventBus,
+	sla map[string]time.Duration,
+) *ModerationService {
+	return &ModerationService{
+		modRepo:       modRepo,
+		postRepo:      postRepo,
+		sensitive:     sensitive,
+		autoModerator: autoModerator,
+		notifier:      notifier,
+		audit:         auditWriter,
+		bus:           bus,
+		sla:           sla,
+	}
 }
 
 func (s *ModerationService) ReportContent(ctx context.Context, reporterID, contentType, contentID, reason, description string) (string, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ModerationService.ReportContent")
+	defer span.End()
+
 	uid, err := uuid.Parse(reporterID)
 	if err != nil {
 		return "", err
 	}
 
-	report := &domain.ContentReport{
-		ID:          uuid.New(),
-		ReporterID:  uid,
-		ContentType: contentType,
-		ContentID:   contentID,
-		Reason:      reason,
-		Description: description,
-		Status:      "pending",
+	reputation, err := s.modRepo.GetReporterReputation(ctx, uid)
+	if err != nil {
+		return "", err
+	}
+
+	// Merge into the existing case for this content, if any
+	report, err := s.modRepo.GetOpenReportForContent(ctx, contentType, contentID)
+	if err != nil {
+		return "", err
+	}
+
+	if report == nil {
+		report = &domain.ContentReport{
+			ID:          uuid.New(),
+			ReporterID:  &uid,
+			Source:      domain.ReportSourceUser,
+			ContentType: contentType,
+			ContentID:   contentID,
+			Reason:      reason,
+			Description: description,
+			Status:      "pending",
+		}
+
+		// A concurrent report may have opened the case first; then this one
+		// joins it and report takes its ID
+		openedID := report.ID
+		if err := s.modRepo.CreateReport(ctx, report); err != nil {
+			return "", err
+		}
+		if report.ID == openedID {
+			metrics.ContentReportsTotal.WithLabelValues(contentType).Inc()
+		}
 	}
 
-	if err := s.modRepo.CreateReport(ctx, report); err != nil {
+	if _, err := s.modRepo.AddReporter(ctx, report.ID, uid, reason, reputation.Weight()); err != nil {
 		return "", err
 	}
 
+	// Re-evaluate auto-moderation rules now that the report count has changed
+	if s.autoModerator != nil && contentType == "post" {
+		post, err := s.postRepo.GetByID(ctx, contentID)
+		if err == nil {
+			// Toxicity scoring needs the plaintext
+			if err := s.sensitive.OpenPost(post); err != nil {
+				return "", err
+			}
+			if _, err := s.autoModerator.ModeratePost(ctx, post); err != nil {
+				return "", err
+			}
+		}
+	}
+
 	return report.ID.String(), nil
 }
 
 func (s *ModerationService) GetReports(ctx context.Context, status *string, limit, offset int) ([]*domain.ContentReport, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ModerationService.GetReports")
+	defer span.End()
+
 	return s.modRepo.GetReports(ctx, status, limit, offset)
 }
 
 func (s *ModerationService) ModerateContent(ctx context.Context, reportID, reviewerID, action string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ModerationService.ModerateContent")
+	defer span.End()
+
 	rid, err := uuid.Parse(reportID)
 	if err != nil {
 		return err
@@ -64,5 +146,219 @@ func (s *ModerationService) ModerateContent(ctx context.Context, reportID, revie
 		status = "reviewed"
 	}
 
-	return s.modRepo.UpdateReportStatus(ctx, rid, status, uid, "")
+	if err := s.modRepo.UpdateReportStatus(ctx, rid, status, uid, ""); err != nil {
+		return err
+	}
+
+	report, err := s.modRepo.GetReportByID(ctx, rid)
+	if err != nil {
+		return err
+	}
+
+	metrics.ModerationActionsTotal.WithLabelValues(action).Inc()
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventReportReviewed,
+		ActorID:    reviewerID,
+		TargetID:   reportID,
+		TargetType: "report",
+		Action:     action,
+		Reason:     status,
+	})
+	if report.ReviewedAt != nil {
+		metrics.ModerationResolutionDuration.WithLabelValues(report.Severity, status).
+			Observe(report.ReviewedAt.Sub(report.CreatedAt).Seconds())
+	}
+
+	// Notify before resolving the quarantine so the author's notice reflects what they saw
+	s.notifyOutcome(ctx, report)
+
+	// Resolve the quarantine on reported posts
+	if report.ContentType == "post" {
+		switch status {
+		case "actioned":
+			if err := s.postRepo.SetModerationState(ctx, report.ContentID, domain.ModerationStateRemoved, nil); err != nil {
+				return err
+			}
+			_ = events.Publish(ctx, s.bus, domain.EventPostWithdrawn, domain.PostWithdrawnEvent{PostID: report.ContentID})
+		case "dismissed":
+			if err := s.postRepo.SetModerationState(ctx, report.ContentID, domain.ModerationStateVisible, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Feed the outcome back into reporter reputation
+	switch status {
+	case "actioned":
+		return s.modRepo.RecordReportOutcome(ctx, rid, true)
+	case "dismissed":
+		return s.modRepo.RecordReportOutcome(ctx, rid, false)
+	}
+	return nil
+}
+
+// notifyOutcome tells reporters and, for posts, the author how a case was resolved.
+// Notifications are best-effort and never fail the moderation action.
+func (s *ModerationService) notifyOutcome(ctx context.Context, report *domain.ContentReport) {
+	var reporterBody string
+	switch report.Status {
+	case "actioned":
+		reporterBody = "Thanks for your report. We've taken action on the content you reported."
+	case "dismissed":
+		reporterBody = "We reviewed your report and found the content doesn't break our community guidelines."
+	default:
+		return
+	}
+
+	reportID := report.ID.String()
+	if reporterIDs, err := s.modRepo.GetReporterIDs(ctx, report.ID); err == nil {
+		for _, reporterID := range reporterIDs {
+			_ = s.notifier.NotifyModerationOutcome(ctx, reporterID.String(), reportID, "Report Reviewed", reporterBody)
+		}
+	}
+
+	if report.ContentType != "post" {
+		return
+	}
+	post, err := s.postRepo.GetByID(ctx, report.ContentID)
+	if err != nil {
+		return
+	}
+	switch report.Status {
+	case "actioned":
+		_ = s.notifier.NotifyModerationOutcome(ctx, post.UserID, reportID, "Post Removed",
+			"Your post was removed because it doesn't follow our community guidelines")
+	case "dismissed":
+		if !post.IsQuarantined() {
+			return
+		}
+		_ = s.notifier.NotifyModerationOutcome(ctx, post.UserID, reportID, "Post Restored",
+			"Your post was reviewed and is visible again")
+	}
+}
+
+// GetReport returns a report together with its internal moderator notes
+func (s *ModerationService) GetReport(ctx context.Context, reportID string) (*domain.ContentReport, []*domain.ModeratorNote, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ModerationService.GetReport")
+	defer span.End()
+
+	rid, err := uuid.Parse(reportID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := s.modRepo.GetReportByID(ctx, rid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notes, err := s.modRepo.GetNotesByReportID(ctx, rid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return report, notes, nil
+}
+
+// AddNote attaches an internal note to a report, optionally as a reply to another note
+func (s *ModerationService) AddNote(ctx context.Context, reportID, authorID, body string, parentID *string) (*domain.ModeratorNote, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ModerationService.AddNote")
+	defer span.End()
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("note body cannot be empty")
+	}
+	if len(body) > 2000 {
+		return nil, fmt.Errorf("note body cannot exceed 2000 characters")
+	}
+
+	rid, err := uuid.Parse(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, err := uuid.Parse(authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.modRepo.GetReportByID(ctx, rid); err != nil {
+		return nil, err
+	}
+
+	note := &domain.ModeratorNote{
+		ID:       uuid.New(),
+		ReportID: rid,
+		AuthorID: uid,
+		Body:     body,
+	}
+
+	if parentID != nil {
+		pid, err := uuid.Parse(*parentID)
+		if err != nil {
+			return nil, err
+		}
+
+		parent, err := s.modRepo.GetNoteByID(ctx, pid)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ReportID != rid {
+			return nil, fmt.Errorf("parent note belongs to a different report")
+		}
+		note.ParentID = &pid
+	}
+
+	if err := s.modRepo.CreateNote(ctx, note); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// GetStats returns queue depth, oldest open report age, resolution time and
+// SLA breaches for each severity, and refreshes the corresponding gauges.
+func (s *ModerationService) GetStats(ctx context.Context) ([]*domain.ModerationSeverityStats, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ModerationService.GetStats")
+	defer span.End()
+
+	now := time.Now()
+
+	rows, err := s.modRepo.GetModerationStats(ctx, now.Add(-statsWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	bySeverity := make(map[string]*domain.ModerationSeverityStats, len(rows))
+	for _, row := range rows {
+		bySeverity[row.Severity] = row
+	}
+
+	// Always report every severity so gauges drop back to zero once a queue is cleared
+	stats := make([]*domain.ModerationSeverityStats, 0, len(domain.Severities))
+	for _, severity := range domain.Severities {
+		stat, ok := bySeverity[severity]
+		if !ok {
+			stat = &domain.ModerationSeverityStats{Severity: severity}
+		}
+		stat.SLA = s.sla[severity]
+
+		if stat.SLA > 0 && stat.OpenCount > 0 {
+			breaches, err := s.modRepo.CountOverdueReports(ctx, severity, now.Add(-stat.SLA))
+			if err != nil {
+				return nil, err
+			}
+			stat.SLABreaches = breaches
+		}
+
+		metrics.ModerationQueueDepth.WithLabelValues(severity).Set(float64(stat.OpenCount))
+		metrics.ModerationOldestOpenReportAge.WithLabelValues(severity).Set(stat.OldestOpenAge(now).Seconds())
+		metrics.ModerationSLASeconds.WithLabelValues(severity).Set(stat.SLA.Seconds())
+		metrics.ModerationSLABreaches.WithLabelValues(severity).Set(float64(stat.SLABreaches))
+
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
 }
@@ -3,24 +3,412 @@ package service
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/encryption"
+	"github.com/yourorg/anonymous-support/internal/pkg/notifications"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
+// maxMentionsPerMessage caps how many users a single post or response can notify
+const maxMentionsPerMessage = 10
+
+var mentionRegex = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_-])@([a-zA-Z0-9_-]{3,50})`)
+
+// PushSender delivers push notifications through a named provider (fcm, apns)
+type PushSender interface {
+	SendNotification(ctx context.Context, providerName string, notification *notifications.PushNotification) error
+}
+
+// EmailSender delivers plain-text email
+type EmailSender interface {
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
+
 type NotificationService struct {
+	notificationRepo repository.NotificationRepository
+	prefsRepo        repository.NotificationPreferencesRepository
+	deviceTokenRepo  repository.DeviceTokenRepository
+	userRepo         repository.UserRepository
+	blockService     *BlockService
+	pushSender       PushSender
+	emailSender      EmailSender
+	encManager       *encryption.Manager
 }
 
-func NewNotificationService() *NotificationService {
-	return &NotificationService{}
+func NewNotificationService(
+	notificationRepo repository.NotificationRepository,
+	prefsRepo repository.NotificationPreferencesRepository,
+	deviceTokenRepo repository.DeviceTokenRepository,
+	userRepo repository.UserRepository,
+	blockService *BlockService,
+	pushSender PushSender,
+	emailSender EmailSender,
+	encManager *encryption.Manager,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		prefsRepo:        prefsRepo,
+		deviceTokenRepo:  deviceTokenRepo,
+		userRepo:         userRepo,
+		blockService:     blockService,
+		pushSender:       pushSender,
+		emailSender:      emailSender,
+		encManager:       encManager,
+	}
 }
 
-func (s *NotificationService) SendNotification(ctx context.Context, userID, title, body string) error {
-	fmt.Printf("Sending notification to %s: %s - %s\n", userID, title, body)
+// Notify delivers a notification according to the user's preferences: it is
+// dropped when the type is switched off, otherwise added to the in-app inbox
+// and, for push, sent to the user's devices outside their quiet hours or, for
+// email, sent to their address if they have one.
+func (s *NotificationService) Notify(ctx context.Context, userID string, notificationType domain.NotificationType, title, body string, data map[string]string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.Notify")
+	defer span.End()
+
+	return s.notify(ctx, userID, notificationType, title, body, data, "", "")
+}
+
+// notify is Notify with an optional push collapse key; pushes sharing a key
+// are aggregated by the dispatcher and summarised with collapseTemplate
+func (s *NotificationService) notify(ctx context.Context, userID string, notificationType domain.NotificationType, title, body string, data map[string]string, collapseKey, collapseTemplate string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	prefs, err := s.prefsRepo.Get(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	channel := prefs.ChannelFor(notificationType)
+	if channel == domain.NotificationChannelOff {
+		return nil
+	}
+
+	if err := s.notificationRepo.Create(ctx, &domain.Notification{
+		UserID: userID,
+		Type:   notificationType,
+		Title:  title,
+		Body:   body,
+		Data:   data,
+	}); err != nil {
+		return err
+	}
+
+	switch channel {
+	case domain.NotificationChannelPush:
+		if !prefs.InQuietHours(time.Now()) {
+			s.push(ctx, uid, title, body, data, collapseKey, collapseTemplate)
+		}
+	case domain.NotificationChannelEmail:
+		s.email(ctx, uid, title, body)
+	}
 	return nil
 }
 
-func (s *NotificationService) NotifyNewResponse(ctx context.Context, postAuthorID, responderUsername string) error {
-	return s.SendNotification(ctx, postAuthorID, "New Response", fmt.Sprintf("%s responded to your post", responderUsername))
+// email sends a notification to the user's address. Delivery is best-effort
+// and skipped for users without an email address.
+func (s *NotificationService) email(ctx context.Context, userID uuid.UUID, subject, body string) {
+	if s.emailSender == nil {
+		return
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user.Email == nil || *user.Email == "" {
+		return
+	}
+
+	// Addresses are stored encrypted
+	address, err := s.encManager.Decrypt(*user.Email)
+	if err != nil {
+		return
+	}
+
+	_ = s.emailSender.SendEmail(ctx, address, subject, body)
+}
+
+// push queues a notification for every active device of the user. Retries and
+// dead tokens are handled by the dispatcher and providers.
+func (s *NotificationService) push(ctx context.Context, userID uuid.UUID, title, body string, data map[string]string, collapseKey, collapseTemplate string) {
+	if s.pushSender == nil {
+		return
+	}
+
+	tokens, err := s.deviceTokenRepo.GetActiveTokens(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	for _, token := range tokens {
+		provider := "fcm"
+		if token.Platform == domain.PlatformIOS {
+			provider = "apns"
+		}
+
+		builder := notifications.NewNotification().
+			WithToken(token.Token).
+			WithTitle(title).
+			WithBody(body).
+			WithCollapseKey(collapseKey, collapseTemplate)
+		for k, v := range data {
+			builder.WithData(k, v)
+		}
+		_ = s.pushSender.SendNotification(ctx, provider, builder.Build())
+	}
+}
+
+// GetPreferences returns the user's notification preferences
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.GetPreferences")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.prefsRepo.Get(ctx, uid)
+}
+
+// UpdatePreferences validates and saves the user's notification preferences
+func (s *NotificationService) UpdatePreferences(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.UpdatePreferences")
+	defer span.End()
+
+	for notificationType, channel := range prefs.Channels {
+		if _, ok := domain.DefaultNotificationChannels[notificationType]; !ok {
+			return fmt.Errorf("unknown notification type %q", notificationType)
+		}
+		switch channel {
+		case domain.NotificationChannelPush, domain.NotificationChannelInApp, domain.NotificationChannelOff:
+		case domain.NotificationChannelEmail:
+			if !domain.EmailNotificationTypes[notificationType] {
+				return fmt.Errorf("email is not available for %q notifications", notificationType)
+			}
+		default:
+			return fmt.Errorf("unknown notification channel %q", channel)
+		}
+	}
+
+	if _, err := time.Parse("15:04", prefs.QuietHoursStart); err != nil {
+		return fmt.Errorf("quiet hours start must be HH:MM")
+	}
+	if _, err := time.Parse("15:04", prefs.QuietHoursEnd); err != nil {
+		return fmt.Errorf("quiet hours end must be HH:MM")
+	}
+	if _, err := time.Parse("15:04", prefs.CheckInReminderTime); err != nil {
+		return fmt.Errorf("check-in reminder time must be HH:MM")
+	}
+	if _, err := time.LoadLocation(prefs.Timezone); err != nil || prefs.Timezone == "" {
+		return fmt.Errorf("invalid timezone %q", prefs.Timezone)
+	}
+
+	return s.prefsRepo.Upsert(ctx, prefs)
+}
+
+func (s *NotificationService) SendNotification(ctx context.Context, userID, title, body string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.SendNotification")
+	defer span.End()
+
+	return s.Notify(ctx, userID, domain.NotificationTypeSystem, title, body, nil)
+}
+
+func (s *NotificationService) NotifyNewResponse(ctx context.Context, postAuthorID, responderUsername, postID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.NotifyNewResponse")
+	defer span.End()
+
+	// Responses arriving close together are batched into one "N new responses" push
+	return s.notify(ctx, postAuthorID, domain.NotificationTypeResponse, "New Response",
+		fmt.Sprintf("%s responded to your post", responderUsername),
+		map[string]string{"post_id": postID},
+		"responses:"+postID, "%d new responses to your post")
 }
 
 func (s *NotificationService) NotifyNewSupport(ctx context.Context, postAuthorID string, supportCount int) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.NotifyNewSupport")
+	defer span.End()
+
 	return s.SendNotification(ctx, postAuthorID, "New Support", fmt.Sprintf("%d people are supporting you", supportCount))
 }
+
+// NotifyMentions notifies every user @mentioned in content, skipping the
+// author, unknown usernames, and users blocked in either direction
+func (s *NotificationService) NotifyMentions(ctx context.Context, authorID, authorUsername, content, postID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.NotifyMentions")
+	defer span.End()
+
+	for _, username := range ParseMentions(content) {
+		if username == authorUsername {
+			continue
+		}
+
+		user, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil || user == nil {
+			continue
+		}
+
+		mentionedID := user.ID.String()
+		if mentionedID == authorID {
+			continue
+		}
+
+		blocked, err := s.blockService.IsBlockedEitherWay(ctx, authorID, mentionedID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			continue
+		}
+
+		if err := s.Notify(ctx, mentionedID, domain.NotificationTypeMention, "You Were Mentioned",
+			fmt.Sprintf("%s mentioned you", authorUsername),
+			map[string]string{"post_id": postID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyCircleInviteAccepted tells a circle owner that someone joined through their invite
+func (s *NotificationService) NotifyCircleInviteAccepted(ctx context.Context, ownerID, circleID, circleName string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.NotifyCircleInviteAccepted")
+	defer span.End()
+
+	return s.Notify(ctx, ownerID, domain.NotificationTypeCircleInvite, "Invite Accepted",
+		fmt.Sprintf("Someone joined %s using your invite", circleName),
+		map[string]string{"circle_id": circleID})
+}
+
+// NotifyModerationOutcome tells a reporter or content author how a report was resolved
+func (s *NotificationService) NotifyModerationOutcome(ctx context.Context, userID, reportID, title, body string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.NotifyModerationOutcome")
+	defer span.End()
+
+	return s.Notify(ctx, userID, domain.NotificationTypeModerationOutcome, title, body,
+		map[string]string{"report_id": reportID})
+}
+
+// NotifyMilestone congratulates a user on reaching a streak milestone
+func (s *NotificationService) NotifyMilestone(ctx context.Context, userID string, days int, name string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.NotifyMilestone")
+	defer span.End()
+
+	return s.Notify(ctx, userID, domain.NotificationTypeMilestone, name,
+		fmt.Sprintf("You've reached %d days. Be proud of how far you've come!", days),
+		map[string]string{"milestone_days": strconv.Itoa(days)})
+}
+
+// NotifyAchievement tells a user they unlocked an achievement
+func (s *NotificationService) NotifyAchievement(ctx context.Context, userID, achievementID, title, description string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.NotifyAchievement")
+	defer span.End()
+
+	return s.Notify(ctx, userID, domain.NotificationTypeAchievement, "Achievement Unlocked: "+title, description,
+		map[string]string{"achievement_id": achievementID})
+}
+
+// NotifySOS asks a subscribed helper to respond to an SOS post
+func (s *NotificationService) NotifySOS(ctx context.Context, helperID, postID string, categories []string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.NotifySOS")
+	defer span.End()
+
+	return s.Notify(ctx, helperID, domain.NotificationTypeSOS, "Someone Needs Support",
+		fmt.Sprintf("Someone is reaching out for help with %s right now", strings.Join(categories, ", ")),
+		map[string]string{"post_id": postID})
+}
+
+// NotifyCrisisResources sends a user in crisis the first few resources they
+// can contact right now
+func (s *NotificationService) NotifyCrisisResources(ctx context.Context, userID string, resources []domain.CrisisResource) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.NotifyCrisisResources")
+	defer span.End()
+
+	contacts := make([]string, 0, 2)
+	ids := make([]string, len(resources))
+	for i, resource := range resources {
+		ids[i] = resource.ID
+		if len(contacts) == cap(contacts) {
+			continue
+		}
+		switch {
+		case resource.Phone != "":
+			contacts = append(contacts, fmt.Sprintf("%s (call %s)", resource.Name, resource.Phone))
+		case resource.SMS != "":
+			contacts = append(contacts, fmt.Sprintf("%s (text %s)", resource.Name, resource.SMS))
+		}
+	}
+
+	body := "You don't have to go through this alone. Help is available right now."
+	if len(contacts) > 0 {
+		body = "You don't have to go through this alone. You can reach " + strings.Join(contacts, " or ") + " right now."
+	}
+	return s.Notify(ctx, userID, domain.NotificationTypeCrisisResources, "Help Is Available", body,
+		map[string]string{"resource_ids": strings.Join(ids, ",")})
+}
+
+// ListNotifications returns a page of the user's inbox along with their unread count
+func (s *NotificationService) ListNotifications(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]*domain.Notification, int64, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.ListNotifications")
+	defer span.End()
+
+	inbox, err := s.notificationRepo.ListByUser(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return inbox, unread, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID string, notificationIDs []string) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.MarkRead")
+	defer span.End()
+
+	if len(notificationIDs) == 0 {
+		return 0, fmt.Errorf("at least one notification ID is required")
+	}
+	return s.notificationRepo.MarkRead(ctx, userID, notificationIDs)
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.MarkAllRead")
+	defer span.End()
+
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+func (s *NotificationService) GetUnreadCount(ctx context.Context, userID string) (int64, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "NotificationService.GetUnreadCount")
+	defer span.End()
+
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+// ParseMentions returns the distinct usernames @mentioned in content, in order
+func ParseMentions(content string) []string {
+	seen := make(map[string]bool)
+	usernames := []string{}
+	for _, match := range mentionRegex.FindAllStringSubmatch(content, -1) {
+		username := match[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentionsPerMessage {
+			break
+		}
+	}
+	return usernames
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+// TestParseMentions tests extraction of @mentions from content
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "no mentions",
+			content: "Day 12 and still going",
+			want:    []string{},
+		},
+		{
+			name:    "single mention",
+			content: "Thanks @hopeful_one for the support",
+			want:    []string{"hopeful_one"},
+		},
+		{
+			name:    "duplicates collapsed",
+			content: "@sam-k you rock @sam-k",
+			want:    []string{"sam-k"},
+		},
+		{
+			name:    "email addresses ignored",
+			content: "mail me at someone@example.com",
+			want:    []string{},
+		},
+		{
+			name:    "too short",
+			content: "hi @ab",
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseMentions(tt.content))
+		})
+	}
+}
+
+// TestNotificationPreferences_InQuietHours tests quiet hour windows across time zones and midnight
+func TestNotificationPreferences_InQuietHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		start    string
+		end      string
+		timezone string
+		now      time.Time
+		want     bool
+	}{
+		{
+			name:     "disabled",
+			enabled:  false,
+			start:    "00:00",
+			end:      "23:59",
+			timezone: "UTC",
+			now:      time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "inside same-day window",
+			enabled:  true,
+			start:    "13:00",
+			end:      "15:00",
+			timezone: "UTC",
+			now:      time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "end is exclusive",
+			enabled:  true,
+			start:    "13:00",
+			end:      "15:00",
+			timezone: "UTC",
+			now:      time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "late night in window spanning midnight",
+			enabled:  true,
+			start:    "22:00",
+			end:      "07:00",
+			timezone: "UTC",
+			now:      time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "early morning in window spanning midnight",
+			enabled:  true,
+			start:    "22:00",
+			end:      "07:00",
+			timezone: "UTC",
+			now:      time.Date(2024, 1, 1, 6, 59, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "daytime outside window spanning midnight",
+			enabled:  true,
+			start:    "22:00",
+			end:      "07:00",
+			timezone: "UTC",
+			now:      time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "uses user time zone",
+			enabled:  true,
+			start:    "22:00",
+			end:      "07:00",
+			timezone: "Asia/Tokyo",
+			now:      time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC), // 23:00 in Tokyo
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := domain.DefaultNotificationPreferences(uuid.New())
+			prefs.QuietHoursEnabled = tt.enabled
+			prefs.QuietHoursStart = tt.start
+			prefs.QuietHoursEnd = tt.end
+			prefs.Timezone = tt.timezone
+
+			assert.Equal(t, tt.want, prefs.InQuietHours(tt.now))
+		})
+	}
+}
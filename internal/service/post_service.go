@@ -2,45 +2,102 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/yourorg/anonymous-support/internal/domain"
 	"github.com/yourorg/anonymous-support/internal/pkg/cache"
+	"github.com/yourorg/anonymous-support/internal/pkg/counters"
+	"github.com/yourorg/anonymous-support/internal/pkg/events"
 	"github.com/yourorg/anonymous-support/internal/pkg/feed"
 	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
 	"github.com/yourorg/anonymous-support/internal/pkg/moderator"
+	"github.com/yourorg/anonymous-support/internal/pkg/pagination"
+	"github.com/yourorg/anonymous-support/internal/pkg/retry"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/pkg/validator"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
+// Kinds of post counters buffered in the counters.Buffer
+const (
+	counterPostViews     = "post_views"
+	counterPostResponses = "post_responses"
+	counterPostSupports  = "post_supports"
+)
+
+// maxAttachmentTitleLength bounds the title shown with an attachment
+const maxAttachmentTitleLength = 200
+
+// postCacheTTL is how long GetPost serves a post from Redis. It is kept short
+// because moderation changes the post behind the service's back.
+const postCacheTTL = 5 * time.Second
+
+// Public feed pages are fresh for feedCacheTTL and then served stale for up to
+// feedCacheStaleTTL more while they are refreshed
+const (
+	feedCacheTTL      = 2 * time.Minute
+	feedCacheStaleTTL = 3 * time.Minute
+)
+
 type PostService struct {
 	postRepo      repository.PostRepository
 	realtimeRepo  repository.RealtimeRepository
 	contentFilter *moderator.ContentFilter
+	sensitive     *SensitiveContent
 	cache         *cache.Cache
 	feedRanker    *feed.FeedRanker
+	autoModerator *AutoModerator
+	blockService  *BlockService
+	bus           events.EventBus
+	counters      *counters.Buffer
+	fallbacks     *feedFallbacks
 }
 
 func NewPostService(
 	postRepo repository.PostRepository,
 	realtimeRepo repository.RealtimeRepository,
 	contentFilter *moderator.ContentFilter,
+	sensitive *SensitiveContent,
 	cache *cache.Cache,
+	feedRanker *feed.FeedRanker,
+	autoModerator *AutoModerator,
+	blockService *BlockService,
+	bus events.EventBus,
+	counterBuffer *counters.Buffer,
 ) *PostService {
+	counterBuffer.Register(counterPostViews, realtimeRepo.IncrementViewCounts)
+
 	return &PostService{
 		postRepo:      postRepo,
 		realtimeRepo:  realtimeRepo,
 		contentFilter: contentFilter,
+		sensitive:     sensitive,
 		cache:         cache,
-		feedRanker:    feed.NewFeedRanker(),
+		feedRanker:    feedRanker,
+		autoModerator: autoModerator,
+		blockService:  blockService,
+		bus:           bus,
+		counters:      counterBuffer,
+		fallbacks:     newFeedFallbacks(cache),
 	}
 }
 
-func (s *PostService) CreatePost(ctx context.Context, userID, username string, postType domain.PostType, content string, categories []string, urgencyLevel int, timeContext string, daysSinceRelapse int, tags []string, visibility string, circleID *string) (*domain.Post, error) {
+func (s *PostService) CreatePost(ctx context.Context, userID, username string, postType domain.PostType, content string, categories []string, urgencyLevel int, timeContext string, daysSinceRelapse int, tags []string, visibility string, circleID *string, attachments []domain.Attachment) (*domain.Post, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.CreatePost")
+	defer span.End()
+
 	if err := validator.ValidatePostContent(content); err != nil {
 		return nil, err
 	}
+	if err := validateAttachments(attachments); err != nil {
+		return nil, err
+	}
 
 	post := &domain.Post{
 		UserID:       userID,
@@ -51,28 +108,48 @@ func (s *PostService) CreatePost(ctx context.Context, userID, username string, p
 		UrgencyLevel: urgencyLevel,
 		Visibility:   visibility,
 		CircleID:     circleID,
+		Attachments:  attachments,
 		Context: domain.PostContext{
 			DaysSinceRelapse: daysSinceRelapse,
 			TimeContext:      timeContext,
 			Tags:             tags,
 		},
-		IsModerated: false,
+		ModerationState: domain.ModerationStateVisible,
 	}
 
 	flags := s.contentFilter.CheckContent(content)
 	if len(flags) > 0 {
-		post.IsModerated = true
+		post.ModerationState = domain.ModerationStateQuarantined
 		post.ModerationFlags = flags
 	}
 
+	// Sensitive content is stored encrypted; moderation and the caller work
+	// with the plaintext
+	if err := s.sensitive.SealPost(post); err != nil {
+		return nil, err
+	}
 	if err := s.postRepo.Create(ctx, post); err != nil {
 		return nil, err
 	}
+	post.Content, post.ContentEncrypted = content, false
 
-	if !post.IsModerated {
-		_ = s.realtimeRepo.PublishNewPost(ctx, post.ID.Hex(), string(postType), categories)
+	// Run auto-moderation rules; this may hide the post before it is published
+	if s.autoModerator != nil {
+		if _, err := s.autoModerator.ModeratePost(ctx, post); err != nil {
+			return nil, err
+		}
+	}
+
+	if post.ModerationState == domain.ModerationStateVisible {
 		feedScore := float64(time.Now().Unix())
 		_ = s.realtimeRepo.AddToFeed(ctx, "feed:global:latest", post.ID.Hex(), feedScore)
+		// Realtime fan-out, mention notifications and SOS alerts are event
+		// subscribers. Brokers may persist events, so the post crosses them as stored.
+		published := *post
+		if err := s.sensitive.SealPost(&published); err != nil {
+			return nil, err
+		}
+		_ = events.Publish(ctx, s.bus, domain.EventPostPublished, domain.PostPublishedEvent{Post: published})
 	}
 
 	// Emit metrics
@@ -81,37 +158,325 @@ func (s *PostService) CreatePost(ctx context.Context, userID, username string, p
 	return post, nil
 }
 
-func (s *PostService) GetPost(ctx context.Context, postID string) (*domain.Post, error) {
-	_ = s.realtimeRepo.IncrementViewCount(ctx, postID)
-	return s.postRepo.GetByID(ctx, postID)
-}
+// GetPost returns a post if the viewer may see it. Quarantined posts are only
+// returned to their author; everyone else gets not found.
+func (s *PostService) GetPost(ctx context.Context, postID, viewerID string) (*domain.Post, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.GetPost")
+	defer span.End()
 
-func (s *PostService) GetFeed(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) ([]*domain.Post, error) {
-	// Build cache key
-	cacheKey := fmt.Sprintf("feed:%v:%v:%v:%d:%d", categories, circleID, postType, limit, offset)
+	// Viral posts are read by many viewers at once; concurrent misses share one
+	// query and later reads are served from the cache for a few seconds. The
+	// cache holds the post as stored, so sensitive content stays encrypted there.
+	var post domain.Post
+	err := s.cache.GetOrSet(ctx, postCacheKey(postID), &post, postCacheTTL, func() (interface{}, error) {
+		return s.postRepo.GetByID(ctx, postID)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	// Try cache first
-	var cachedPosts []*domain.Post
-	found, err := s.cache.Get(ctx, cacheKey, &cachedPosts)
-	if err == nil && found {
-		metrics.CacheHitsTotal.WithLabelValues("feed").Inc()
-		return cachedPosts, nil
+	if !post.VisibleTo(viewerID) {
+		return nil, fmt.Errorf("post not found")
 	}
-	metrics.CacheMissesTotal.WithLabelValues("feed").Inc()
+	if err := s.sensitive.OpenPost(&post); err != nil {
+		return nil, err
+	}
+
+	s.counters.Add(counterPostViews, postID, 1)
+	return &post, nil
+}
 
-	// Cache miss - fetch from DB
-	posts, err := s.postRepo.GetFeed(ctx, categories, circleID, postType, limit, offset)
+// BatchGetPosts returns the posts the viewer may see, in the order asked
+// for. Unknown and hidden posts are left out. Unlike GetPost, it doesn't count
+// views, since apps call it to sync posts rather than to show them.
+func (s *PostService) BatchGetPosts(ctx context.Context, postIDs []string, viewerID string) ([]*domain.Post, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.BatchGetPosts")
+	defer span.End()
+
+	ids, err := batchIDs(postIDs)
+	if err != nil {
+		return nil, err
+	}
+	found, err := s.postRepo.GetByIDs(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store in cache (5 min TTL)
-	_ = s.cache.Set(ctx, cacheKey, posts, 5*time.Minute)
+	byID := make(map[string]*domain.Post, len(found))
+	for _, post := range found {
+		byID[post.ID.Hex()] = post
+	}
+	posts := make([]*domain.Post, 0, len(found))
+	for _, id := range ids {
+		if post, ok := byID[id]; ok && post.VisibleTo(viewerID) {
+			posts = append(posts, post)
+		}
+	}
 
+	if err := s.sensitive.OpenPosts(posts); err != nil {
+		return nil, err
+	}
 	return posts, nil
 }
 
+// validateAttachments checks a new post's attachments. Only https URLs are
+// accepted, so clients never load attachments over plain HTTP.
+func validateAttachments(attachments []domain.Attachment) error {
+	if len(attachments) > domain.MaxPostAttachments {
+		return fmt.Errorf("a post can have at most %d attachments", domain.MaxPostAttachments)
+	}
+	for _, attachment := range attachments {
+		if attachment.Type != domain.AttachmentTypeImage && attachment.Type != domain.AttachmentTypeLink {
+			return fmt.Errorf("invalid attachment type %q", attachment.Type)
+		}
+		u, err := url.Parse(attachment.URL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("attachment URLs must be https URLs")
+		}
+		if len(attachment.Title) > maxAttachmentTitleLength {
+			return fmt.Errorf("attachment titles can be at most %d characters", maxAttachmentTitleLength)
+		}
+	}
+	return nil
+}
+
+func postCacheKey(postID string) string {
+	return "post:" + postID
+}
+
+// GetFeed returns a feed page with posts from users blocked by (or blocking)
+// the viewer removed. Counts cover the whole feed before block filtering.
+func (s *PostService) GetFeed(ctx context.Context, viewerID string, categories []string, circleID *string, postType *domain.PostType, limit, offset int) (*domain.FeedPage, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.GetFeed")
+	defer span.End()
+
+	page, err := s.getFeed(ctx, categories, circleID, postType, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Block filtering happens after the shared cache so cached pages stay viewer-independent
+	page.Posts, err = s.blockService.FilterPosts(ctx, viewerID, page.Posts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sensitive.OpenPosts(page.Posts); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetFeedAfter returns the feed page after cursor, newest first, with posts
+// from users blocked by (or blocking) the viewer removed. An empty cursor
+// starts at the newest post. Unlike GetFeed, pages aren't cached and carry no
+// counts, and new posts don't shift later pages.
+func (s *PostService) GetFeedAfter(ctx context.Context, viewerID string, categories []string, circleID *string, postType *domain.PostType, cursor string, limit int) (*domain.FeedCursorPage, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.GetFeedAfter")
+	defer span.End()
+
+	var after pagination.Cursor
+	if cursor != "" {
+		var err error
+		if after, err = pagination.DecodeCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+	limit = clampLimit(limit, pagination.DefaultLimit, pagination.MaxLimit)
+
+	// One extra post tells whether there's another page
+	posts, err := s.postRepo.GetFeedAfter(ctx, categories, circleID, postType, after.CreatedAt, after.ID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.FeedCursorPage{Posts: posts}
+	if len(posts) > limit {
+		page.Posts = posts[:limit]
+		last := page.Posts[limit-1]
+		page.NextCursor = pagination.EncodeCursor(pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID.Hex()})
+	}
+
+	page.Posts, err = s.blockService.FilterPosts(ctx, viewerID, page.Posts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sensitive.OpenPosts(page.Posts); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (s *PostService) getFeed(ctx context.Context, categories []string, circleID *string, postType *domain.PostType, limit, offset int) (*domain.FeedPage, error) {
+	cacheKey := feedCacheKey(categories, circleID, postType, limit, offset)
+
+	// Feed pages are hot, so they are served stale while one request refreshes
+	// them rather than every reader hitting the DB when a page expires
+	var fetched atomic.Bool
+	var page domain.FeedPage
+	err := s.cache.GetOrSetStale(ctx, cacheKey, &page, feedCacheTTL, feedCacheStaleTTL, func(ctx context.Context) (interface{}, error) {
+		fetched.Store(true)
+		fetchedPage, err := s.postRepo.GetFeedPage(ctx, categories, circleID, postType, limit, offset)
+		if err == nil {
+			s.fallbacks.save(ctx, cacheKey, fetchedPage)
+		}
+		return fetchedPage, err
+	})
+	if errors.Is(err, retry.ErrCircuitOpen) {
+		// Serve the last page read rather than nothing while MongoDB is down
+		if s.fallbacks.load(ctx, cacheKey, &page) {
+			metrics.CacheHitsTotal.WithLabelValues("feed_fallback").Inc()
+			return &page, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if fetched.Load() {
+		metrics.CacheMissesTotal.WithLabelValues("feed").Inc()
+	} else {
+		metrics.CacheHitsTotal.WithLabelValues("feed").Inc()
+	}
+	return &page, nil
+}
+
+// feedCacheKey keys a feed page by its filters. Circle and public pages use
+// separate prefixes so a new post only invalidates the feeds it can appear in.
+func feedCacheKey(categories []string, circleID *string, postType *domain.PostType, limit, offset int) string {
+	scope := "public"
+	if circleID != nil {
+		scope = "circle:" + *circleID
+	}
+	typeFilter := ""
+	if postType != nil {
+		typeFilter = string(*postType)
+	}
+	return fmt.Sprintf("feed:%s:%s:%s:%d:%d", scope, strings.Join(categories, ","), typeFilter, limit, offset)
+}
+
+// InvalidateFeedCache drops the cached feed pages a new or deleted post
+// belongs in: its circle's feed, or the public and personalized feeds
+func (s *PostService) InvalidateFeedCache(ctx context.Context, post *domain.Post) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.InvalidateFeedCache")
+	defer span.End()
+
+	// Deleting the pages also deletes their fallback copies
+	defer s.fallbacks.forget()
+
+	if post.CircleID != nil {
+		metrics.CacheInvalidationsTotal.WithLabelValues("feed").Inc()
+		return s.cache.DeletePattern(ctx, "feed:circle:"+*post.CircleID+":*")
+	}
+
+	metrics.CacheInvalidationsTotal.WithLabelValues("feed").Inc()
+	if err := s.cache.DeletePattern(ctx, "feed:public:*"); err != nil {
+		return err
+	}
+	metrics.CacheInvalidationsTotal.WithLabelValues("personalized_feed").Inc()
+	return s.cache.DeletePattern(ctx, "feed:personalized:*")
+}
+
+// InvalidateAllFeedCaches drops every cached feed page and fallback copy, so a
+// post moderation hid or removed stops being served. Withdrawals are rare, and
+// whoever withdraws the post may not know which feeds it is in.
+func (s *PostService) InvalidateAllFeedCaches(ctx context.Context) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.InvalidateAllFeedCaches")
+	defer span.End()
+
+	defer s.fallbacks.forget()
+	metrics.CacheInvalidationsTotal.WithLabelValues("feed").Inc()
+	return s.cache.DeletePattern(ctx, "feed:*")
+}
+
+// FeedFilter selects the posts a live feed stream delivers, with the same
+// semantics as GetFeed: circle posts only when a circle is requested,
+// otherwise public posts only
+type FeedFilter struct {
+	Categories []string
+	CircleID   *string
+	PostType   *domain.PostType
+}
+
+// Matches reports whether a post passes the filter
+func (f FeedFilter) Matches(post *domain.Post) bool {
+	if post.ModerationState != domain.ModerationStateVisible {
+		return false
+	}
+	if f.PostType != nil && post.Type != *f.PostType {
+		return false
+	}
+
+	if f.CircleID != nil {
+		if post.CircleID == nil || *post.CircleID != *f.CircleID {
+			return false
+		}
+	} else if post.Visibility != "public" {
+		return false
+	}
+
+	if len(f.Categories) == 0 {
+		return true
+	}
+	for _, want := range f.Categories {
+		for _, category := range post.Categories {
+			if category == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StreamFeed calls send for every new post matching the filter until ctx is
+// done or send fails, skipping authors blocked by (or blocking) the viewer
+func (s *PostService) StreamFeed(ctx context.Context, viewerID string, filter FeedFilter, send func(*domain.Post) error) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.StreamFeed")
+	defer span.End()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var sendErr error
+	err := s.realtimeRepo.SubscribeEvents(ctx, func(event *domain.RealtimeEvent) {
+		if event.Type != domain.RealtimeEventNewPost {
+			return
+		}
+		if viewerID != "" && event.SenderID != "" {
+			if blocked, err := s.blockService.IsBlockedEitherWay(ctx, viewerID, event.SenderID); err != nil || blocked {
+				return
+			}
+		}
+
+		var data struct {
+			PostID string `json:"post_id"`
+		}
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return
+		}
+
+		post, err := s.postRepo.GetByID(ctx, data.PostID)
+		if err != nil || !filter.Matches(post) {
+			return
+		}
+		if err := s.sensitive.OpenPost(post); err != nil {
+			return
+		}
+
+		if err := send(post); err != nil {
+			sendErr = err
+			cancel()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	return err
+}
+
 func (s *PostService) DeletePost(ctx context.Context, postID, userID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.DeletePost")
+	defer span.End()
+
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return err
@@ -121,15 +486,30 @@ func (s *PostService) DeletePost(ctx context.Context, postID, userID string) err
 		return nil
 	}
 
-	return s.postRepo.Delete(ctx, postID)
+	if err := s.postRepo.Delete(ctx, postID); err != nil {
+		return err
+	}
+	if err := s.cache.Delete(ctx, postCacheKey(postID)); err != nil {
+		return err
+	}
+	return s.InvalidateFeedCache(ctx, post)
 }
 
 func (s *PostService) UpdatePostUrgency(ctx context.Context, postID string, urgencyLevel int) error {
-	return s.postRepo.UpdateUrgency(ctx, postID, int32(urgencyLevel)) //nolint:gosec // Urgency level 1-10
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.UpdatePostUrgency")
+	defer span.End()
+
+	if err := s.postRepo.UpdateUrgency(ctx, postID, int32(urgencyLevel)); err != nil { //nolint:gosec // Urgency level 1-10
+		return err
+	}
+	return s.cache.Delete(ctx, postCacheKey(postID))
 }
 
 // GetPersonalizedFeed returns a feed ranked by relevance to the user
 func (s *PostService) GetPersonalizedFeed(ctx context.Context, userPrefs *feed.UserPreferences, limit, offset int) ([]*domain.Post, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PostService.GetPersonalizedFeed")
+	defer span.End()
+
 	// Build cache key with user preferences hash
 	cacheKey := fmt.Sprintf("feed:personalized:%v:%d:%d", userPrefs.PreferredCategories, limit, offset)
 
@@ -138,6 +518,9 @@ func (s *PostService) GetPersonalizedFeed(ctx context.Context, userPrefs *feed.U
 	found, err := s.cache.Get(ctx, cacheKey, &cachedPosts)
 	if err == nil && found {
 		metrics.CacheHitsTotal.WithLabelValues("personalized_feed").Inc()
+		if err := s.sensitive.OpenPosts(cachedPosts); err != nil {
+			return nil, err
+		}
 		return cachedPosts, nil
 	}
 	metrics.CacheMissesTotal.WithLabelValues("personalized_feed").Inc()
@@ -173,5 +556,8 @@ func (s *PostService) GetPersonalizedFeed(ctx context.Context, userPrefs *feed.U
 	// Cache for shorter TTL since it's personalized (2 min)
 	_ = s.cache.Set(ctx, cacheKey, result, 2*time.Minute)
 
+	if err := s.sensitive.OpenPosts(result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
@@ -68,3 +68,92 @@ func TestPostTypeValidation(t *testing.T) {
 // 3. Testing at the repository level with real database connections
 //
 // The integration tests in tests/integration/ provide end-to-end coverage.
+
+func TestFeedFilter_Matches(t *testing.T) {
+	circleID := "circle-1"
+	otherCircle := "circle-2"
+	sos := domain.PostTypeSOS
+
+	publicPost := &domain.Post{
+		Type:            domain.PostTypeSOS,
+		Categories:      []string{"alcohol", "anxiety"},
+		Visibility:      "public",
+		ModerationState: domain.ModerationStateVisible,
+	}
+	circlePost := &domain.Post{
+		Type:            domain.PostTypeVictory,
+		Categories:      []string{"alcohol"},
+		Visibility:      "circle",
+		CircleID:        &circleID,
+		ModerationState: domain.ModerationStateVisible,
+	}
+	quarantinedPost := &domain.Post{
+		Type:            domain.PostTypeSOS,
+		Visibility:      "public",
+		ModerationState: domain.ModerationStateQuarantined,
+	}
+
+	tests := []struct {
+		name   string
+		filter FeedFilter
+		post   *domain.Post
+		want   bool
+	}{
+		{name: "no filter matches public post", filter: FeedFilter{}, post: publicPost, want: true},
+		{name: "no filter excludes circle post", filter: FeedFilter{}, post: circlePost, want: false},
+		{name: "circle filter matches circle post", filter: FeedFilter{CircleID: &circleID}, post: circlePost, want: true},
+		{name: "circle filter excludes other circle", filter: FeedFilter{CircleID: &otherCircle}, post: circlePost, want: false},
+		{name: "category overlap", filter: FeedFilter{Categories: []string{"anxiety", "grief"}}, post: publicPost, want: true},
+		{name: "no category overlap", filter: FeedFilter{Categories: []string{"grief"}}, post: publicPost, want: false},
+		{name: "type filter", filter: FeedFilter{PostType: &sos}, post: publicPost, want: true},
+		{name: "quarantined excluded", filter: FeedFilter{}, post: quarantinedPost, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(tt.post))
+		})
+	}
+}
+
+func TestFeedCacheKey(t *testing.T) {
+	circleID := "c1"
+	otherCircleID := "c1"
+	sos := domain.PostTypeSOS
+
+	// Keys depend on filter values, not pointer identity
+	assert.Equal(t,
+		feedCacheKey([]string{"alcohol"}, &circleID, &sos, 20, 0),
+		feedCacheKey([]string{"alcohol"}, &otherCircleID, &sos, 20, 0))
+
+	assert.Equal(t, "feed:public:alcohol,gambling::20:40", feedCacheKey([]string{"alcohol", "gambling"}, nil, nil, 20, 40))
+	assert.Equal(t, "feed:circle:c1::sos:10:0", feedCacheKey(nil, &circleID, &sos, 10, 0))
+}
+
+func TestValidateAttachments(t *testing.T) {
+	image := domain.Attachment{Type: domain.AttachmentTypeImage, URL: "https://cdn.example.com/a.jpg"}
+
+	tests := []struct {
+		name        string
+		attachments []domain.Attachment
+		wantErr     bool
+	}{
+		{name: "none", attachments: nil, wantErr: false},
+		{name: "image and link", attachments: []domain.Attachment{image, {Type: domain.AttachmentTypeLink, URL: "https://example.org/help", Title: "Help line"}}, wantErr: false},
+		{name: "too many", attachments: []domain.Attachment{image, image, image, image, image}, wantErr: true},
+		{name: "unknown type", attachments: []domain.Attachment{{Type: "video", URL: "https://example.com/v.mp4"}}, wantErr: true},
+		{name: "plain http", attachments: []domain.Attachment{{Type: domain.AttachmentTypeLink, URL: "http://example.com"}}, wantErr: true},
+		{name: "no host", attachments: []domain.Attachment{{Type: domain.AttachmentTypeImage, URL: "https:///a.jpg"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAttachments(tt.attachments)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
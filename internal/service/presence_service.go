@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+// presenceTTL is how long a user stays online without a heartbeat. It must
+// outlast the WebSocket ping period so connected users don't flap offline.
+const presenceTTL = 2 * time.Minute
+
+// PresenceService tracks which users are connected and announces presence
+// changes to the circles they belong to
+type PresenceService struct {
+	sessionRepo  repository.SessionRepository
+	circleRepo   repository.CircleRepository
+	realtimeRepo repository.RealtimeRepository
+	logger       *zap.Logger
+}
+
+func NewPresenceService(
+	sessionRepo repository.SessionRepository,
+	circleRepo repository.CircleRepository,
+	realtimeRepo repository.RealtimeRepository,
+	logger *zap.Logger,
+) *PresenceService {
+	return &PresenceService{
+		sessionRepo:  sessionRepo,
+		circleRepo:   circleRepo,
+		realtimeRepo: realtimeRepo,
+		logger:       logger,
+	}
+}
+
+// Connected marks the user online and announces it on their circle channels
+func (s *PresenceService) Connected(ctx context.Context, userID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PresenceService.Connected")
+	defer span.End()
+
+	if err := s.sessionRepo.SetUserOnline(ctx, userID, presenceTTL); err != nil {
+		return err
+	}
+	s.announce(ctx, userID, true)
+	return nil
+}
+
+// Heartbeat keeps a connected user online
+func (s *PresenceService) Heartbeat(ctx context.Context, userID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PresenceService.Heartbeat")
+	defer span.End()
+
+	return s.sessionRepo.SetUserOnline(ctx, userID, presenceTTL)
+}
+
+// Disconnected marks the user offline and announces it on their circle channels.
+// A user still connected to another instance is restored on its next heartbeat.
+func (s *PresenceService) Disconnected(ctx context.Context, userID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PresenceService.Disconnected")
+	defer span.End()
+
+	if err := s.sessionRepo.SetUserOffline(ctx, userID); err != nil {
+		return err
+	}
+	s.announce(ctx, userID, false)
+	return nil
+}
+
+// IsUserOnline reports whether the user is connected to any instance
+func (s *PresenceService) IsUserOnline(ctx context.Context, userID string) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PresenceService.IsUserOnline")
+	defer span.End()
+
+	return s.sessionRepo.IsUserOnline(ctx, userID)
+}
+
+// GetOnlineStatus returns which of the given users are online
+func (s *PresenceService) GetOnlineStatus(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "PresenceService.GetOnlineStatus")
+	defer span.End()
+
+	ids := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		ids[i] = userID.String()
+	}
+
+	online, err := s.sessionRepo.GetOnlineUsers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make(map[uuid.UUID]bool, len(userIDs))
+	for _, userID := range userIDs {
+		status[userID] = online[userID.String()]
+	}
+	return status, nil
+}
+
+// announce publishes a presence event on each circle channel the user belongs to.
+// Presence is best-effort, so failures are logged rather than returned.
+func (s *PresenceService) announce(ctx context.Context, userID string, online bool) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return
+	}
+
+	circleIDs, err := s.circleRepo.GetUserCircleIDs(ctx, uid)
+	if err != nil {
+		s.logger.Warn("Failed to load circles for presence", zap.String("user_id", userID), zap.Error(err))
+		return
+	}
+
+	for _, circleID := range circleIDs {
+		data, err := json.Marshal(domain.PresenceEvent{
+			UserID:   userID,
+			CircleID: circleID.String(),
+			Online:   online,
+		})
+		if err != nil {
+			continue
+		}
+
+		if err := s.realtimeRepo.PublishEvent(ctx, &domain.RealtimeEvent{
+			Type:     domain.RealtimeEventPresence,
+			Channel:  "circle:" + circleID.String(),
+			SenderID: userID,
+			Data:     data,
+		}); err != nil {
+			s.logger.Warn("Failed to publish presence", zap.String("user_id", userID), zap.Error(err))
+		}
+	}
+}
@@ -2,23 +2,220 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/events"
+	"github.com/yourorg/anonymous-support/internal/pkg/i18n"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
+// MoodTrendWindows are the trailing windows, in days, summarised on the dashboard
+var MoodTrendWindows = []int{7, 30}
+
+// weeklyProgressDays is how many days the dashboard's weekly progress covers
+const weeklyProgressDays = 7
+
+// maxMoodHistoryDays bounds how far back GetMoodHistory reads
+const maxMoodHistoryDays = 365
+
+const (
+	relapsePatternSample = 100 // Most recent relapses analysed for patterns
+	recentRelapsesShown  = 5
+	commonTriggersShown  = 3
+	maxRelapseNoteLength = 500
+)
+
+// Bounds on a savings baseline
+const (
+	maxDailySpendCents = 10_000_000 // 100,000 in the user's currency
+	maxDailyMinutes    = 24 * 60
+)
+
+var currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// CelebratedMilestones are the streak lengths, in days, that trigger a celebration
+var CelebratedMilestones = []int{7, 30, 90}
+
+// MilestoneEvent is published when a user's streak reaches a celebrated milestone
+type MilestoneEvent struct {
+	UserID    string    `json:"user_id"`
+	Days      int       `json:"days"`
+	Name      string    `json:"name"`
+	ReachedAt time.Time `json:"reached_at"`
+}
+
+// AchievementEvent is published when a user first unlocks an achievement
+type AchievementEvent struct {
+	UserID      string      `json:"user_id"`
+	Achievement Achievement `json:"achievement"`
+}
+
 type ProgressService struct {
-	analyticsRepo repository.AnalyticsRepository
-	postRepo      repository.PostRepository
+	analyticsRepo    repository.AnalyticsRepository
+	postRepo         repository.PostRepository
+	supportRepo      repository.SupportRepository
+	achievements     []AchievementDefinition
+	freezesPerMonth  int
+	checkInQuestions []CheckInQuestion
+	entitlements     EntitlementChecker
+	bus              events.EventBus
 }
 
-func NewProgressService(analyticsRepo repository.AnalyticsRepository, postRepo repository.PostRepository) *ProgressService {
+// NewProgressService creates a progress service; nil achievements uses
+// DefaultAchievements and nil checkInQuestions uses DefaultCheckInQuestions.
+// freezesPerMonth is how many missed days a month a streak may survive.
+// Risk and coping insights need EntitlementAdvancedInsights. Milestone and
+// achievement events are published on bus.
+func NewProgressService(
+	analyticsRepo repository.AnalyticsRepository,
+	postRepo repository.PostRepository,
+	supportRepo repository.SupportRepository,
+	achievements []AchievementDefinition,
+	freezesPerMonth int,
+	checkInQuestions []CheckInQuestion,
+	entitlements EntitlementChecker,
+	bus events.EventBus,
+) *ProgressService {
+	if checkInQuestions == nil {
+		checkInQuestions = DefaultCheckInQuestions
+	}
 	return &ProgressService{
-		analyticsRepo: analyticsRepo,
-		postRepo:      postRepo,
+		analyticsRepo:    analyticsRepo,
+		postRepo:         postRepo,
+		supportRepo:      supportRepo,
+		achievements:     achievements,
+		freezesPerMonth:  freezesPerMonth,
+		checkInQuestions: checkInQuestions,
+		entitlements:     entitlements,
+		bus:              bus,
+	}
+}
+
+// ListCheckInQuestions returns the questions asked at each daily check-in
+func (s *ProgressService) ListCheckInQuestions() []CheckInQuestion {
+	return s.checkInQuestions
+}
+
+// validateCheckInAnswers checks each answer is to a known question, at most once
+func (s *ProgressService) validateCheckInAnswers(answers []domain.CheckInAnswer) error {
+	questions := make(map[string]CheckInQuestion, len(s.checkInQuestions))
+	for _, question := range s.checkInQuestions {
+		questions[question.ID] = question
 	}
+
+	answered := make(map[string]bool, len(answers))
+	for _, answer := range answers {
+		question, ok := questions[answer.QuestionID]
+		if !ok {
+			return fmt.Errorf("unknown check-in question %q", answer.QuestionID)
+		}
+		if answered[answer.QuestionID] {
+			return fmt.Errorf("check-in question %q answered more than once", answer.QuestionID)
+		}
+		answered[answer.QuestionID] = true
+
+		if err := question.validateAnswer(answer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreakFreezeStatus describes a user's streak freezes for the current month
+type StreakFreezeStatus struct {
+	PerMonth      int         `json:"per_month"`
+	Available     int         `json:"available"`
+	UsedThisMonth []time.Time `json:"used_this_month"` // Frozen days, as dates at midnight UTC
+	ResetsAt      time.Time   `json:"resets_at"`
+}
+
+// freezeStatus reports freezes for the user's current month, which resets at
+// their local midnight
+func (s *ProgressService) freezeStatus(tracker *domain.UserTracker, now time.Time) *StreakFreezeStatus {
+	today := tracker.Today(now)
+	year, month, _ := today.Date()
+	return &StreakFreezeStatus{
+		PerMonth:      s.freezesPerMonth,
+		Available:     tracker.FreezesAvailable(today, s.freezesPerMonth),
+		UsedThisMonth: tracker.FreezesUsed(today),
+		ResetsAt:      time.Date(year, month+1, 1, 0, 0, 0, 0, tracker.Location()),
+	}
+}
+
+// GetStreakFreezes returns the user's streak freezes for the current month
+func (s *ProgressService) GetStreakFreezes(ctx context.Context, userID string) (*StreakFreezeStatus, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.GetStreakFreezes")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.freezeStatus(tracker, time.Now()), nil
+}
+
+// UseStreakFreeze freezes today so the user's streak survives not checking in.
+// A single missed day is also frozen automatically at the next check-in.
+func (s *ProgressService) UseStreakFreeze(ctx context.Context, userID string) (*StreakFreezeStatus, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.UseStreakFreeze")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := tracker.Today(now)
+	switch {
+	case tracker.StreakDays == 0:
+		return nil, fmt.Errorf("there is no streak to freeze")
+	case tracker.CheckedInOn(now, tracker.Location()):
+		return nil, fmt.Errorf("you have already checked in today")
+	case tracker.IsFrozen(today):
+		return nil, fmt.Errorf("today is already frozen")
+	case tracker.FreezesAvailable(today, s.freezesPerMonth) == 0:
+		return nil, fmt.Errorf("no streak freezes left this month")
+	}
+
+	if err := s.analyticsRepo.AddStreakFreeze(ctx, uid, today); err != nil {
+		return nil, err
+	}
+	tracker.StreakFreezes = append(tracker.StreakFreezes, today)
+	return s.freezeStatus(tracker, now), nil
+}
+
+// SetTimezone sets the IANA time zone the user's streak and check-in days are
+// counted in. An empty timezone counts them in UTC.
+func (s *ProgressService) SetTimezone(ctx context.Context, userID, timezone string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.SetTimezone")
+	defer span.End()
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return err
+	}
+	// LoadLocation accepts "Local", which would mean the server's zone
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+		return fmt.Errorf("invalid timezone %q", timezone)
+	}
+	return s.analyticsRepo.SetTimezone(ctx, userID, timezone)
 }
 
 // ProgressDashboard represents a user's progress dashboard
@@ -34,7 +231,72 @@ type ProgressDashboard struct {
 	SupportReceived  int             `json:"support_received"`
 	RelapsePattern   *RelapsePattern `json:"relapse_pattern"`
 	WeeklyProgress   []DayProgress   `json:"weekly_progress"`
+	MoodTrends       []MoodTrend     `json:"mood_trends"`
 	Achievements     []Achievement   `json:"achievements"`
+	Savings          *Savings        `json:"savings,omitempty"` // Nil until the user sets a savings baseline
+	// RiskInsights and CopingStrategies are empty without EntitlementAdvancedInsights
+	RiskInsights []RiskInsight `json:"risk_insights"`
+	// CopingStrategies are the strategies the user has tried, most effective first
+	CopingStrategies []CopingEffectiveness `json:"coping_strategies"`
+	// CheckInTrends summarise the last 30 days of answers to each check-in question
+	CheckInTrends []CheckInTrend `json:"check_in_trends"`
+}
+
+// riskInsightSuggestion is shown with every risk window on the dashboard
+const riskInsightSuggestion = "This time of day has been hard for you. Plan a check-in or a coping exercise shortly before it starts."
+
+// RiskInsight is one of the user's high-risk windows with a suggestion for getting through it
+type RiskInsight struct {
+	Window       domain.RiskWindow `json:"window"`
+	NextStartsAt time.Time         `json:"next_starts_at"`
+	Suggestion   string            `json:"suggestion"`
+}
+
+// riskInsights pairs each risk window with its next start after now
+func riskInsights(windows []domain.RiskWindow, now time.Time) []RiskInsight {
+	insights := make([]RiskInsight, 0, len(windows))
+	for _, window := range windows {
+		next := domain.ProgressDay(now).Add(time.Duration(window.StartHour) * time.Hour)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		insights = append(insights, RiskInsight{Window: window, NextStartsAt: next, Suggestion: riskInsightSuggestion})
+	}
+	return insights
+}
+
+// Savings estimates what a user has reclaimed over their clean days
+type Savings struct {
+	Currency             string `json:"currency"`
+	DailySpendCents      int64  `json:"daily_spend_cents"`
+	DailyMinutes         int    `json:"daily_minutes"`
+	MoneySavedCents      int64  `json:"money_saved_cents"`
+	TimeReclaimedMinutes int64  `json:"time_reclaimed_minutes"`
+}
+
+// CalculateSavings returns the money and time reclaimed over the tracker's
+// total clean days, or nil when no baseline is set
+func CalculateSavings(tracker *domain.UserTracker) *Savings {
+	baseline := tracker.SavingsBaseline
+	if baseline == nil {
+		return nil
+	}
+	days := int64(tracker.TotalDaysClean)
+	return &Savings{
+		Currency:             baseline.Currency,
+		DailySpendCents:      baseline.DailySpendCents,
+		DailyMinutes:         baseline.DailyMinutes,
+		MoneySavedCents:      days * baseline.DailySpendCents,
+		TimeReclaimedMinutes: days * int64(baseline.DailyMinutes),
+	}
+}
+
+// MoodTrend summarises a user's mood over a trailing window of days
+type MoodTrend struct {
+	Days    int     `json:"days"`
+	Entries int     `json:"entries"`
+	Average float64 `json:"average"` // 0 when there are no entries
+	Change  float64 `json:"change"`  // Average minus the preceding window's; 0 when either is empty
 }
 
 // RelapsePattern analyzes user's relapse patterns
@@ -61,7 +323,7 @@ type DayProgress struct {
 	CheckedIn     bool      `json:"checked_in"`
 	CravingsCount int       `json:"cravings_count"`
 	SupportGiven  int       `json:"support_given"`
-	MoodScore     int       `json:"mood_score"` // 1-10
+	MoodScore     int       `json:"mood_score"` // 1-10, or 0 when not recorded
 }
 
 // Achievement represents a milestone achievement
@@ -76,6 +338,9 @@ type Achievement struct {
 
 // GetDashboard retrieves comprehensive progress dashboard for a user
 func (s *ProgressService) GetDashboard(ctx context.Context, userID string) (*ProgressDashboard, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.GetDashboard")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, err
@@ -87,17 +352,51 @@ func (s *ProgressService) GetDashboard(ctx context.Context, userID string) (*Pro
 		return nil, err
 	}
 
-	// Calculate milestones
-	milestones := s.calculateMilestones(tracker)
+	// Milestones and achievements are shown in the caller's language
+	localizer := i18n.FromContext(ctx)
+	milestones := calculateMilestones(localizer, tracker)
 
 	// Get relapse pattern
-	relapsePattern := s.analyzeRelapsePattern(tracker)
+	relapses, err := s.analyticsRepo.ListRelapses(ctx, userID, relapsePatternSample)
+	if err != nil {
+		return nil, err
+	}
+	relapsePattern := s.analyzeRelapsePattern(tracker, relapses)
 
-	// Get weekly progress
-	weeklyProgress := s.getWeeklyProgress(ctx, userID)
+	// Daily progress is bucketed by the user's local day; as a midnight UTC
+	// date, today works wherever the helpers take a time to bucket
+	now := time.Now()
+	today := tracker.Today(now)
 
-	// Calculate achievements
-	achievements := s.calculateAchievements(tracker)
+	// Mood history covers the longest trend window and the one before it
+	longest := MoodTrendWindows[len(MoodTrendWindows)-1]
+	moods, err := s.analyticsRepo.GetMoodHistory(ctx, userID, today.AddDate(0, 0, -2*longest+1))
+	if err != nil {
+		return nil, err
+	}
+
+	// Check-ins cover the weekly progress and the check-in answer trends
+	weekStart := today.AddDate(0, 0, -(weeklyProgressDays - 1))
+	checkIns, err := s.analyticsRepo.GetDailyCheckIns(ctx, userID, today.AddDate(0, 0, -(checkInTrendDays-1)))
+	if err != nil {
+		return nil, err
+	}
+	supportGiven, err := s.supportRepo.CountGivenPerDay(ctx, userID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	weeklyProgress := s.getWeeklyProgress(today, checkIns, supportGiven, moods)
+
+	moodTrends := make([]MoodTrend, 0, len(MoodTrendWindows))
+	for _, days := range MoodTrendWindows {
+		moodTrends = append(moodTrends, CalculateMoodTrend(moods, today, days))
+	}
+
+	// Unlock any newly earned achievements
+	achievements, err := s.unlockAchievements(ctx, userID, tracker)
+	if err != nil {
+		return nil, err
+	}
 
 	dashboard := &ProgressDashboard{
 		UserID:           userID,
@@ -111,151 +410,617 @@ func (s *ProgressService) GetDashboard(ctx context.Context, userID string) (*Pro
 		SupportReceived:  tracker.SupportReceived,
 		RelapsePattern:   relapsePattern,
 		WeeklyProgress:   weeklyProgress,
-		Achievements:     achievements,
+		MoodTrends:       moodTrends,
+		Achievements:     localizeAchievements(localizer, achievements),
+		Savings:          CalculateSavings(tracker),
+		CheckInTrends:    calculateCheckInTrends(s.checkInQuestions, checkIns),
+	}
+
+	advanced, err := s.entitlements.HasEntitlement(ctx, userID, domain.EntitlementAdvancedInsights)
+	if err != nil {
+		return nil, err
+	}
+	if advanced {
+		dashboard.RiskInsights = riskInsights(tracker.RiskWindows, now)
+		dashboard.CopingStrategies = calculateCopingEffectiveness(tracker.CopingStats)
 	}
 
 	return dashboard, nil
 }
 
 // calculateMilestones generates milestone badges based on tracker data
-func (s *ProgressService) calculateMilestones(tracker *domain.UserTracker) []string {
+func calculateMilestones(l *i18n.Localizer, tracker *domain.UserTracker) []string {
 	milestones := []string{}
 
 	dayMilestones := []int{1, 7, 14, 30, 60, 90, 180, 365}
 	for _, days := range dayMilestones {
 		if tracker.StreakDays >= days {
-			milestones = append(milestones, formatDayMilestone(days))
+			milestones = append(milestones, formatDayMilestone(l, days))
 		}
 	}
 
 	if tracker.SupportGiven >= 10 {
-		milestones = append(milestones, "Helpful Friend - 10 supports given")
+		milestones = append(milestones, l.T("milestone.helpful_friend", 10))
 	}
 	if tracker.SupportGiven >= 50 {
-		milestones = append(milestones, "Support Champion - 50 supports given")
+		milestones = append(milestones, l.T("milestone.support_champion", 50))
 	}
 
 	if tracker.CravingsResisted >= 20 {
-		milestones = append(milestones, "Craving Warrior - 20 cravings resisted")
+		milestones = append(milestones, l.T("milestone.craving_warrior", 20))
 	}
 
 	return milestones
 }
 
-func formatDayMilestone(days int) string {
+func formatDayMilestone(l *i18n.Localizer, days int) string {
 	switch days {
-	case 1:
-		return "First Day Clean"
-	case 7:
-		return "One Week Strong"
-	case 14:
-		return "Two Weeks Clean"
-	case 30:
-		return "One Month Milestone"
-	case 60:
-		return "Two Months Clean"
-	case 90:
-		return "Three Months Strong"
-	case 180:
-		return "Six Months Clean"
-	case 365:
-		return "One Year Anniversary"
+	case 1, 7, 14, 30, 60, 90, 180, 365:
+		return l.T(fmt.Sprintf("milestone.days_%d", days))
 	default:
-		return "Milestone Achieved"
+		return l.T("milestone.achieved")
 	}
 }
 
-// analyzeRelapsePattern analyzes relapse patterns
-func (s *ProgressService) analyzeRelapsePattern(tracker *domain.UserTracker) *RelapsePattern {
+// analyzeRelapsePattern finds when and why the user tends to relapse from
+// their logged relapses, newest first. High-risk times are left empty until
+// there is a relapse to learn from.
+func (s *ProgressService) analyzeRelapsePattern(tracker *domain.UserTracker, relapses []*domain.RelapseRecord) *RelapsePattern {
 	avgTimeClean := float64(0)
 	if tracker.TotalRelapses > 0 {
 		avgTimeClean = float64(tracker.TotalDaysClean) / float64(tracker.TotalRelapses+1)
 	}
 
-	return &RelapsePattern{
-		TotalRelapses:     tracker.TotalRelapses,
-		AverageTimeClean:  avgTimeClean,
-		HighRiskTimeOfDay: "evening",        // TODO: Calculate from actual data
-		HighRiskDayOfWeek: "weekend",        // TODO: Calculate from actual data
-		CommonTriggers:    []string{},       // TODO: Extract from posts/check-ins
-		RecentRelapses:    []RelapseEvent{}, // TODO: Load from relapse history
+	pattern := &RelapsePattern{
+		TotalRelapses:    max(tracker.TotalRelapses, len(relapses)),
+		AverageTimeClean: avgTimeClean,
+		CommonTriggers:   []string{},
+		RecentRelapses:   []RelapseEvent{},
+	}
+	if len(relapses) == 0 {
+		return pattern
+	}
+
+	timesOfDay := make(map[string]int)
+	daysOfWeek := make(map[string]int)
+	triggers := make(map[string]int)
+	daysClean := 0
+	for i, relapse := range relapses {
+		timesOfDay[relapse.TimeOfDay]++
+		daysOfWeek[relapse.DayOfWeek]++
+		triggers[string(relapse.Trigger)]++
+		daysClean += relapse.DaysClean
+
+		if i < recentRelapsesShown {
+			pattern.RecentRelapses = append(pattern.RecentRelapses, RelapseEvent{
+				Date:      relapse.OccurredAt,
+				DaysClean: relapse.DaysClean,
+				Trigger:   string(relapse.Trigger),
+				TimeOfDay: relapse.TimeOfDay,
+			})
+		}
+	}
+
+	pattern.AverageTimeClean = float64(daysClean) / float64(len(relapses))
+	pattern.HighRiskTimeOfDay = rankByCount(timesOfDay)[0]
+	pattern.HighRiskDayOfWeek = rankByCount(daysOfWeek)[0]
+	pattern.CommonTriggers = rankByCount(triggers)
+	if len(pattern.CommonTriggers) > commonTriggersShown {
+		pattern.CommonTriggers = pattern.CommonTriggers[:commonTriggersShown]
 	}
+	return pattern
+}
+
+// rankByCount returns the keys of counts from most to least frequent, breaking ties alphabetically
+func rankByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
 }
 
-// getWeeklyProgress fetches progress data for the last 7 days
-func (s *ProgressService) getWeeklyProgress(ctx context.Context, userID string) []DayProgress {
-	// TODO: Implement actual data fetching
-	// For now, return mock data structure
+// getWeeklyProgress lays out the user's check-ins, cravings, support given and
+// mood for each of the last 7 days, oldest first
+func (s *ProgressService) getWeeklyProgress(now time.Time, checkIns []*domain.DailyCheckIn, supportGiven map[time.Time]int, moods []*domain.MoodEntry) []DayProgress {
+	byDay := make(map[time.Time]*domain.DailyCheckIn, len(checkIns))
+	for _, checkIn := range checkIns {
+		byDay[checkIn.Day] = checkIn
+	}
+	scores := make(map[time.Time]int, len(moods))
+	for _, entry := range moods {
+		scores[entry.Day] = entry.Score
+	}
+
 	progress := []DayProgress{}
-	for i := 6; i >= 0; i-- {
-		date := time.Now().AddDate(0, 0, -i)
-		progress = append(progress, DayProgress{
-			Date:          date,
-			CheckedIn:     true,
-			CravingsCount: 0,
-			SupportGiven:  0,
-			MoodScore:     7,
-		})
+	for i := weeklyProgressDays - 1; i >= 0; i-- {
+		date := domain.ProgressDay(now).AddDate(0, 0, -i)
+		day := DayProgress{
+			Date:         date,
+			SupportGiven: supportGiven[date],
+			MoodScore:    scores[date],
+		}
+		if checkIn, ok := byDay[date]; ok {
+			day.CheckedIn = checkIn.CheckedIn
+			day.CravingsCount = checkIn.Cravings
+		}
+		progress = append(progress, day)
 	}
 	return progress
 }
 
-// calculateAchievements generates achievement list
+// CalculateMoodTrend summarises the entries in the days-long window ending
+// today and compares it with the window before it
+func CalculateMoodTrend(moods []*domain.MoodEntry, now time.Time, days int) MoodTrend {
+	start := domain.ProgressDay(now).AddDate(0, 0, -days+1)
+	previousStart := start.AddDate(0, 0, -days)
+
+	var sum, previousSum, previousCount int
+	trend := MoodTrend{Days: days}
+	for _, entry := range moods {
+		switch {
+		case !entry.Day.Before(start):
+			sum += entry.Score
+			trend.Entries++
+		case !entry.Day.Before(previousStart):
+			previousSum += entry.Score
+			previousCount++
+		}
+	}
+
+	if trend.Entries > 0 {
+		trend.Average = float64(sum) / float64(trend.Entries)
+		if previousCount > 0 {
+			trend.Change = trend.Average - float64(previousSum)/float64(previousCount)
+		}
+	}
+	return trend
+}
+
+// GetMoodHistory returns the user's mood entries for the last days days, oldest first
+func (s *ProgressService) GetMoodHistory(ctx context.Context, userID string, days int) ([]*domain.MoodEntry, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.GetMoodHistory")
+	defer span.End()
+
+	if days <= 0 || days > maxMoodHistoryDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", maxMoodHistoryDays)
+	}
+	return s.analyticsRepo.GetMoodHistory(ctx, userID, domain.ProgressDay(time.Now()).AddDate(0, 0, -days+1))
+}
+
+// calculateAchievements lists the achievements the user has unlocked or now
+// qualifies for. Stored achievements keep their original unlock time, even if
+// the metric that earned them has since dropped, e.g. after a relapse; newly
+// earned ones are stamped with the current time.
 func (s *ProgressService) calculateAchievements(tracker *domain.UserTracker) []Achievement {
+	unlockedAt := make(map[string]time.Time, len(tracker.Achievements))
+	for _, unlocked := range tracker.Achievements {
+		unlockedAt[unlocked.ID] = unlocked.UnlockedAt
+	}
+
+	definitions := s.achievements
+	if definitions == nil {
+		definitions = DefaultAchievements
+	}
+
 	achievements := []Achievement{}
+	now := time.Now()
+	for _, definition := range definitions {
+		at, unlocked := unlockedAt[definition.ID]
+		if !unlocked {
+			if !definition.Earned(tracker) {
+				continue
+			}
+			at = now
+		}
 
-	if tracker.StreakDays >= 7 {
 		achievements = append(achievements, Achievement{
-			ID:          "first_week",
-			Title:       "First Week Strong",
-			Description: "Maintained a 7-day streak",
-			UnlockedAt:  time.Now(),
-			Icon:        "🏆",
-			Rarity:      "common",
+			ID:          definition.ID,
+			Title:       definition.Title,
+			Description: definition.Description,
+			UnlockedAt:  at,
+			Icon:        definition.Icon,
+			Rarity:      definition.Rarity,
 		})
 	}
+	return achievements
+}
 
-	if tracker.StreakDays >= 30 {
-		achievements = append(achievements, Achievement{
-			ID:          "first_month",
-			Title:       "One Month Milestone",
-			Description: "Completed 30 days clean",
-			UnlockedAt:  time.Now(),
-			Icon:        "🎖️",
-			Rarity:      "rare",
-		})
+// unlockAchievements stores achievements the user has newly earned and
+// publishes an event for each, returning all of the user's achievements
+func (s *ProgressService) unlockAchievements(ctx context.Context, userID string, tracker *domain.UserTracker) ([]Achievement, error) {
+	stored := make(map[string]bool, len(tracker.Achievements))
+	for _, unlocked := range tracker.Achievements {
+		stored[unlocked.ID] = true
 	}
 
-	if tracker.SupportGiven >= 50 {
-		achievements = append(achievements, Achievement{
-			ID:          "support_champion",
-			Title:       "Support Champion",
-			Description: "Helped 50 community members",
-			UnlockedAt:  time.Now(),
-			Icon:        "🤝",
-			Rarity:      "epic",
+	achievements := s.calculateAchievements(tracker)
+	for _, achievement := range achievements {
+		if stored[achievement.ID] {
+			continue
+		}
+
+		// Concurrent requests may race to unlock; only the winner publishes
+		unlocked, err := s.analyticsRepo.UnlockAchievement(ctx, userID, domain.UnlockedAchievement{
+			ID:         achievement.ID,
+			UnlockedAt: achievement.UnlockedAt,
 		})
+		if err != nil {
+			return nil, err
+		}
+		if unlocked {
+			s.publishAchievement(ctx, AchievementEvent{UserID: userID, Achievement: achievement})
+		}
 	}
+	return achievements, nil
+}
 
-	return achievements
+// GetAchievements returns the user's achievements, unlocking any they have newly earned
+func (s *ProgressService) GetAchievements(ctx context.Context, userID string) ([]Achievement, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.GetAchievements")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	achievements, err := s.unlockAchievements(ctx, userID, tracker)
+	if err != nil {
+		return nil, err
+	}
+	return localizeAchievements(i18n.FromContext(ctx), achievements), nil
+}
+
+// localizeAchievements translates the titles and descriptions of built-in
+// achievements. Events and digests use the stored English text.
+func localizeAchievements(l *i18n.Localizer, achievements []Achievement) []Achievement {
+	localized := make([]Achievement, len(achievements))
+	for i, achievement := range achievements {
+		achievement.Title = l.Localize("achievement."+achievement.ID+".title", achievement.Title)
+		achievement.Description = l.Localize("achievement."+achievement.ID+".description", achievement.Description)
+		localized[i] = achievement
+	}
+	return localized
+}
+
+// WeeklyDigest summarises a user's progress since their previous digest
+type WeeklyDigest struct {
+	StreakDays      int
+	SupportGiven    int // Since the previous digest
+	SupportReceived int // Since the previous digest
+	NewAchievements []Achievement
+	Snapshot        *domain.DigestSnapshot // Counters to store for the next digest
+}
+
+// IsEmpty reports whether there is nothing worth telling the user
+func (d *WeeklyDigest) IsEmpty() bool {
+	return d.StreakDays == 0 && d.SupportGiven == 0 && d.SupportReceived == 0 && len(d.NewAchievements) == 0
 }
 
-// RecordCheckIn records a daily check-in
-func (s *ProgressService) RecordCheckIn(ctx context.Context, userID string, hadRelapse bool, moodScore int) error {
+// Message renders the digest as a notification title and body
+func (d *WeeklyDigest) Message() (string, string) {
+	lines := []string{fmt.Sprintf("Current streak: %d days", d.StreakDays)}
+	if d.SupportGiven > 0 {
+		lines = append(lines, fmt.Sprintf("You supported others %d times", d.SupportGiven))
+	}
+	if d.SupportReceived > 0 {
+		lines = append(lines, fmt.Sprintf("You received support %d times", d.SupportReceived))
+	}
+	for _, achievement := range d.NewAchievements {
+		lines = append(lines, fmt.Sprintf("Achievement unlocked: %s", achievement.Title))
+	}
+	return "Your Week in Review", strings.Join(lines, "\n")
+}
+
+// BuildWeeklyDigest compares a tracker with the snapshot from its previous digest
+func (s *ProgressService) BuildWeeklyDigest(tracker *domain.UserTracker, now time.Time) *WeeklyDigest {
+	previous := tracker.LastDigest
+	if previous == nil {
+		previous = &domain.DigestSnapshot{}
+	}
+
+	unlocked := make(map[string]bool, len(previous.Achievements))
+	for _, id := range previous.Achievements {
+		unlocked[id] = true
+	}
+
+	digest := &WeeklyDigest{
+		StreakDays:      tracker.StreakDays,
+		SupportGiven:    max(tracker.SupportGiven-previous.SupportGiven, 0),
+		SupportReceived: max(tracker.SupportReceived-previous.SupportReceived, 0),
+		NewAchievements: []Achievement{},
+		Snapshot: &domain.DigestSnapshot{
+			SentAt:          now,
+			StreakDays:      tracker.StreakDays,
+			SupportGiven:    tracker.SupportGiven,
+			SupportReceived: tracker.SupportReceived,
+			Achievements:    []string{},
+		},
+	}
+
+	for _, achievement := range s.calculateAchievements(tracker) {
+		digest.Snapshot.Achievements = append(digest.Snapshot.Achievements, achievement.ID)
+		if !unlocked[achievement.ID] {
+			digest.NewAchievements = append(digest.NewAchievements, achievement)
+		}
+	}
+
+	return digest
+}
+
+// RecordCheckIn records a daily check-in, along with the day's mood when
+// moodScore is non-zero and answers to any check-in questions, and publishes
+// a milestone event when the new streak reaches a celebrated milestone
+func (s *ProgressService) RecordCheckIn(ctx context.Context, userID string, hadRelapse bool, moodScore int, answers []domain.CheckInAnswer) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.RecordCheckIn")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return err
 	}
+	if moodScore != 0 && (moodScore < domain.MinMoodScore || moodScore > domain.MaxMoodScore) {
+		return fmt.Errorf("mood score must be between %d and %d", domain.MinMoodScore, domain.MaxMoodScore)
+	}
+	if err := s.validateCheckInAnswers(answers); err != nil {
+		return err
+	}
+
+	// A repeat check-in on the same day leaves the streak unchanged and must not re-celebrate it
+	previousStreak := 0
+	loc := time.UTC
+	if tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid); err == nil {
+		previousStreak = tracker.StreakDays
+		loc = tracker.Location()
+	}
+
+	if err := s.analyticsRepo.UpdateStreak(ctx, uid, hadRelapse, s.freezesPerMonth); err != nil {
+		return err
+	}
+
+	// The check-in and mood are recorded on the user's local day, as the streak is
+	now := time.Now()
+	today := domain.LocalDay(now, loc)
+	if err := s.analyticsRepo.RecordDailyCheckIn(ctx, userID, today, hadRelapse, answers); err != nil {
+		return err
+	}
+
+	if moodScore != 0 {
+		if err := s.analyticsRepo.RecordMood(ctx, &domain.MoodEntry{
+			UserID:     userID,
+			Day:        today,
+			Score:      moodScore,
+			RecordedAt: now,
+		}); err != nil {
+			return err
+		}
+	}
+	if hadRelapse {
+		return nil
+	}
 
-	return s.analyticsRepo.UpdateStreak(ctx, uid, hadRelapse)
+	tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	for _, days := range CelebratedMilestones {
+		if tracker.StreakDays != days || tracker.StreakDays == previousStreak {
+			continue
+		}
+
+		// Stored milestones and their events are in English, like other records
+		english := i18n.Default()
+		milestone := &domain.Milestone{
+			Name:        formatDayMilestone(english, days),
+			Days:        days,
+			AchievedAt:  time.Now(),
+			Description: english.T("milestone.streak_description", days),
+		}
+		if err := s.analyticsRepo.AddMilestone(ctx, uid, milestone); err != nil {
+			return err
+		}
+		s.publishMilestone(ctx, MilestoneEvent{
+			UserID:    userID,
+			Days:      days,
+			Name:      milestone.Name,
+			ReachedAt: milestone.AchievedAt,
+		})
+	}
+
+	_, err = s.unlockAchievements(ctx, userID, tracker)
+	return err
+}
+
+// publishAchievement publishes an achievement event; celebrating it is best-effort
+func (s *ProgressService) publishAchievement(ctx context.Context, event AchievementEvent) {
+	_ = events.Publish(ctx, s.bus, domain.EventAchievementUnlocked, event)
+}
+
+// publishMilestone publishes a milestone event; celebrating it is best-effort
+func (s *ProgressService) publishMilestone(ctx context.Context, event MilestoneEvent) {
+	_ = events.Publish(ctx, s.bus, domain.EventMilestoneReached, event)
+}
+
+// SetSavingsBaseline records what the tracked behavior used to cost the user
+// each day in money and time, and returns their savings so far
+func (s *ProgressService) SetSavingsBaseline(ctx context.Context, userID string, dailySpendCents int64, currency string, dailyMinutes int) (*Savings, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.SetSavingsBaseline")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	if dailySpendCents < 0 || dailySpendCents > maxDailySpendCents {
+		return nil, fmt.Errorf("daily spend must be between 0 and %d cents", maxDailySpendCents)
+	}
+	if dailyMinutes < 0 || dailyMinutes > maxDailyMinutes {
+		return nil, fmt.Errorf("daily minutes must be between 0 and %d", maxDailyMinutes)
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !currencyRegex.MatchString(currency) {
+		return nil, fmt.Errorf("currency must be a three-letter ISO 4217 code")
+	}
+
+	baseline := &domain.SavingsBaseline{
+		DailySpendCents: dailySpendCents,
+		Currency:        currency,
+		DailyMinutes:    dailyMinutes,
+	}
+	if err := s.analyticsRepo.SetSavingsBaseline(ctx, userID, baseline); err != nil {
+		return nil, err
+	}
+
+	tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return CalculateSavings(tracker), nil
+}
+
+// RecordRelapse logs a relapse with its trigger, ends the user's streak, and
+// returns the streak length it ended. occurredAt defaults to now; loc is the
+// user's time zone, used to find the local time of day and day of week.
+func (s *ProgressService) RecordRelapse(ctx context.Context, userID string, trigger domain.RelapseTrigger, occurredAt time.Time, loc *time.Location, note string) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.RecordRelapse")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, err
+	}
+	if !domain.ValidRelapseTriggers[trigger] {
+		return 0, fmt.Errorf("unknown relapse trigger %q", trigger)
+	}
+	if len(note) > maxRelapseNoteLength {
+		return 0, fmt.Errorf("note must be at most %d characters", maxRelapseNoteLength)
+	}
+
+	now := time.Now()
+	if occurredAt.IsZero() {
+		occurredAt = now
+	}
+	if occurredAt.After(now) {
+		return 0, fmt.Errorf("relapse time cannot be in the future")
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	daysClean := 0
+	if tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid); err == nil {
+		daysClean = tracker.StreakDays
+	}
+
+	local := occurredAt.In(loc)
+	if err := s.analyticsRepo.CreateRelapse(ctx, &domain.RelapseRecord{
+		UserID:     userID,
+		OccurredAt: occurredAt,
+		TimeOfDay:  domain.TimeOfDay(local),
+		DayOfWeek:  local.Weekday().String(),
+		Trigger:    trigger,
+		Note:       note,
+		DaysClean:  daysClean,
+		RecordedAt: now,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := s.analyticsRepo.UpdateStreak(ctx, uid, true, s.freezesPerMonth); err != nil {
+		return 0, err
+	}
+	if err := s.recordRiskEvent(ctx, userID, occurredAt, domain.RelapseRiskWeight); err != nil {
+		return 0, err
+	}
+	return daysClean, nil
+}
+
+// ListCopingStrategies returns the coping toolbox
+func (s *ProgressService) ListCopingStrategies() []CopingStrategy {
+	return CopingStrategies
+}
+
+// RecordCopingUse records that the user tried a coping strategy during a
+// craving and whether it helped, returning the strategy's updated effectiveness
+func (s *ProgressService) RecordCopingUse(ctx context.Context, userID, strategyID string, helped bool) (*CopingEffectiveness, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.RecordCopingUse")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := copingStrategy(strategyID); !ok {
+		return nil, fmt.Errorf("unknown coping strategy %q", strategyID)
+	}
+
+	if err := s.analyticsRepo.RecordCopingUse(ctx, userID, strategyID, helped, time.Now()); err != nil {
+		return nil, err
+	}
+
+	tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, effectiveness := range calculateCopingEffectiveness(tracker.CopingStats) {
+		if effectiveness.StrategyID == strategyID {
+			return &effectiveness, nil
+		}
+	}
+	return nil, fmt.Errorf("coping strategy %q was not recorded", strategyID)
+}
+
+// recordRiskEvent adds a craving or relapse at the given time to the user's
+// vulnerability pattern and recomputes their risk windows
+func (s *ProgressService) recordRiskEvent(ctx context.Context, userID string, at time.Time, weight int) error {
+	pattern, err := s.analyticsRepo.IncrementVulnerability(ctx, userID, at, weight)
+	if err != nil {
+		return err
+	}
+	return s.analyticsRepo.SetRiskWindows(ctx, userID, domain.RiskWindowsFromPattern(pattern))
 }
 
 // RecordCraving records a craving event
 func (s *ProgressService) RecordCraving(ctx context.Context, userID string, resisted bool) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ProgressService.RecordCraving")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return err
 	}
 
-	return s.analyticsRepo.IncrementCravings(ctx, uid, resisted)
+	if err := s.analyticsRepo.IncrementCravings(ctx, uid, resisted); err != nil {
+		return err
+	}
+	tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.analyticsRepo.IncrementDailyCravings(ctx, userID, tracker.Today(now), resisted); err != nil {
+		return err
+	}
+	if err := s.recordRiskEvent(ctx, userID, now, domain.CravingRiskWeight); err != nil {
+		return err
+	}
+	if !resisted {
+		return nil
+	}
+	_, err = s.unlockAchievements(ctx, userID, tracker)
+	return err
 }
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/i18n"
+	"golang.org/x/text/language"
+)
+
+// TestCalculateMoodTrend tests that trends average the window and compare it with the one before
+func TestCalculateMoodTrend(t *testing.T) {
+	now := time.Date(2026, 5, 20, 15, 0, 0, 0, time.UTC)
+	entry := func(daysAgo, score int) *domain.MoodEntry {
+		return &domain.MoodEntry{Day: domain.ProgressDay(now).AddDate(0, 0, -daysAgo), Score: score}
+	}
+
+	tests := []struct {
+		name  string
+		moods []*domain.MoodEntry
+		days  int
+		want  MoodTrend
+	}{
+		{
+			name: "no entries",
+			days: 7,
+			want: MoodTrend{Days: 7},
+		},
+		{
+			name:  "improving week",
+			moods: []*domain.MoodEntry{entry(10, 3), entry(8, 5), entry(6, 6), entry(0, 8)},
+			days:  7,
+			want:  MoodTrend{Days: 7, Entries: 2, Average: 7, Change: 3},
+		},
+		{
+			name:  "no previous window",
+			moods: []*domain.MoodEntry{entry(3, 4), entry(1, 6)},
+			days:  7,
+			want:  MoodTrend{Days: 7, Entries: 2, Average: 5},
+		},
+		{
+			name:  "entries older than both windows ignored",
+			moods: []*domain.MoodEntry{entry(20, 1), entry(2, 9)},
+			days:  7,
+			want:  MoodTrend{Days: 7, Entries: 1, Average: 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CalculateMoodTrend(tt.moods, now, tt.days))
+		})
+	}
+}
+
+// TestUserTracker_ApplyCheckIn tests that streak freezes bridge a single missed day
+func TestUserTracker_ApplyCheckIn(t *testing.T) {
+	now := time.Date(2026, 5, 20, 15, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return domain.ProgressDay(now).AddDate(0, 0, -days) }
+	lastCheckIn := func(days int) *time.Time {
+		at := daysAgo(days).Add(9 * time.Hour)
+		return &at
+	}
+
+	tests := []struct {
+		name       string
+		tracker    domain.UserTracker
+		hadRelapse bool
+		wantStreak int
+		wantFroze  bool
+	}{
+		{"first check-in", domain.UserTracker{}, false, 1, false},
+		{"same day", domain.UserTracker{StreakDays: 10, LastCheckInAt: lastCheckIn(0)}, false, 10, false},
+		{"consecutive day", domain.UserTracker{StreakDays: 10, LastCheckInAt: lastCheckIn(1)}, false, 11, false},
+		{"missed day uses freeze", domain.UserTracker{StreakDays: 10, LastCheckInAt: lastCheckIn(2)}, false, 11, true},
+		{"missed day without freezes", domain.UserTracker{StreakDays: 10, LastCheckInAt: lastCheckIn(2), StreakFreezes: []time.Time{daysAgo(10)}}, false, 1, false},
+		{"missed day already frozen", domain.UserTracker{StreakDays: 10, LastCheckInAt: lastCheckIn(2), StreakFreezes: []time.Time{daysAgo(1)}}, false, 11, false},
+		{"two missed days", domain.UserTracker{StreakDays: 10, LastCheckInAt: lastCheckIn(3)}, false, 1, false},
+		{"relapse", domain.UserTracker{StreakDays: 10, LastCheckInAt: lastCheckIn(1)}, true, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := tt.tracker
+			froze := tracker.ApplyCheckIn(now, tt.hadRelapse, 1)
+			assert.Equal(t, tt.wantStreak, tracker.StreakDays)
+			assert.Equal(t, tt.wantFroze, froze)
+			assert.Equal(t, now, *tracker.LastCheckInAt)
+		})
+	}
+}
+
+// TestUserTracker_ApplyCheckIn_Timezone tests that days roll over at the user's local midnight, across DST changes
+func TestUserTracker_ApplyCheckIn_Timezone(t *testing.T) {
+	at := func(value string) *time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		assert.NoError(t, err)
+		return &parsed
+	}
+
+	tests := []struct {
+		name        string
+		timezone    string
+		lastCheckIn *time.Time
+		now         *time.Time
+		wantStreak  int
+	}{
+		// 23:00 and 08:00 in Los Angeles are on consecutive days, but the same UTC day
+		{"next local day", "America/Los_Angeles", at("2026-05-20T06:00:00Z"), at("2026-05-20T15:00:00Z"), 11},
+		{"same day in UTC", "", at("2026-05-20T06:00:00Z"), at("2026-05-20T15:00:00Z"), 10},
+		// 01:00 and 23:00 in Tokyo are the same day, but on different UTC days
+		{"same local day", "Asia/Tokyo", at("2026-05-19T16:00:00Z"), at("2026-05-20T14:00:00Z"), 10},
+		// The days clocks change on are 23 and 25 hours long, and still one day each
+		{"across DST start", "America/New_York", at("2026-03-08T04:30:00Z"), at("2026-03-09T03:45:00Z"), 11},
+		{"across DST end", "America/New_York", at("2026-11-01T03:59:00Z"), at("2026-11-02T04:30:00Z"), 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := domain.UserTracker{StreakDays: 10, LastCheckInAt: tt.lastCheckIn, Timezone: tt.timezone}
+			froze := tracker.ApplyCheckIn(*tt.now, false, 0)
+			assert.Equal(t, tt.wantStreak, tracker.StreakDays)
+			assert.False(t, froze)
+		})
+	}
+}
+
+// TestCalculateSavings tests that savings scale the baseline by total clean days
+func TestCalculateSavings(t *testing.T) {
+	assert.Nil(t, CalculateSavings(&domain.UserTracker{TotalDaysClean: 30}))
+
+	savings := CalculateSavings(&domain.UserTracker{
+		StreakDays:     3,
+		TotalDaysClean: 45,
+		SavingsBaseline: &domain.SavingsBaseline{
+			DailySpendCents: 1250,
+			Currency:        "USD",
+			DailyMinutes:    90,
+		},
+	})
+	assert.Equal(t, &Savings{
+		Currency:             "USD",
+		DailySpendCents:      1250,
+		DailyMinutes:         90,
+		MoneySavedCents:      56250,
+		TimeReclaimedMinutes: 4050,
+	}, savings)
+}
+
+// TestRiskWindowsFromPattern tests that adjacent risky hours merge, including across midnight
+func TestRiskWindowsFromPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern map[string]int
+		want    []domain.RiskWindow
+	}{
+		{"no events", nil, []domain.RiskWindow{}},
+		{"too few events", map[string]int{"21": 2}, []domain.RiskWindow{}},
+		{
+			"evening window",
+			map[string]int{"08": 1, "20": 4, "21": 6, "22": 3},
+			[]domain.RiskWindow{{StartHour: 20, EndHour: 23, Events: 13}},
+		},
+		{
+			"spans midnight",
+			map[string]int{"23": 5, "00": 4, "01": 3, "14": 3},
+			[]domain.RiskWindow{{StartHour: 23, EndHour: 2, Events: 12}, {StartHour: 14, EndHour: 15, Events: 3}},
+		},
+		{
+			"background noise",
+			map[string]int{"00": 3, "01": 3, "02": 3, "03": 3, "04": 3, "05": 3, "06": 3, "07": 3, "08": 3, "09": 3, "10": 3, "11": 3, "12": 3, "13": 3, "14": 3, "15": 3, "16": 3, "17": 3, "18": 3, "19": 3, "20": 3, "21": 3, "22": 3, "23": 3},
+			[]domain.RiskWindow{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.RiskWindowsFromPattern(tt.pattern))
+		})
+	}
+}
+
+// TestCalculateCopingEffectiveness tests that strategies rank by the share of uses that helped
+func TestCalculateCopingEffectiveness(t *testing.T) {
+	effectiveness := calculateCopingEffectiveness(map[string]domain.CopingStat{
+		"box_breathing": {Uses: 4, Helped: 2},
+		"urge_surfing":  {Uses: 2, Helped: 2},
+		"call_a_buddy":  {Uses: 8, Helped: 4},
+		"retired":       {Uses: 5, Helped: 5},
+	})
+
+	assert.Equal(t, []CopingEffectiveness{
+		{StrategyID: "urge_surfing", Title: "Urge Surfing", Uses: 2, Helped: 2, Effectiveness: 1},
+		{StrategyID: "call_a_buddy", Title: "Call a Buddy", Uses: 8, Helped: 4, Effectiveness: 0.5},
+		{StrategyID: "box_breathing", Title: "Box Breathing", Uses: 4, Helped: 2, Effectiveness: 0.5},
+	}, effectiveness)
+	assert.Empty(t, calculateCopingEffectiveness(nil))
+}
+
+// TestCalculateCheckInTrends tests that answers are summarised per question type, ignoring invalid ones
+func TestCalculateCheckInTrends(t *testing.T) {
+	scale := func(v int) *int { return &v }
+	yes, no := true, false
+	work, other := "work", "other"
+
+	checkIns := []*domain.DailyCheckIn{
+		{Answers: []domain.CheckInAnswer{
+			{QuestionID: "sleep_quality", Scale: scale(2)},
+			{QuestionID: "exercised", Boolean: &yes},
+			{QuestionID: "main_stress", Choice: &work},
+		}},
+		{Answers: []domain.CheckInAnswer{
+			{QuestionID: "sleep_quality", Scale: scale(5)},
+			{QuestionID: "exercised", Boolean: &no},
+			{QuestionID: "main_stress", Choice: &other},
+		}},
+		{Answers: []domain.CheckInAnswer{
+			{QuestionID: "sleep_quality", Scale: scale(9)},
+			{QuestionID: "exercised", Boolean: &yes},
+			{QuestionID: "removed_question", Boolean: &yes},
+		}},
+		{},
+	}
+
+	trends := calculateCheckInTrends(DefaultCheckInQuestions, checkIns)
+
+	assert.Len(t, trends, len(DefaultCheckInQuestions))
+	assert.Equal(t, 2, trends[0].Responses)
+	assert.InDelta(t, 3.5, trends[0].Average, 0.001)
+	assert.Equal(t, 3, trends[1].Responses)
+	assert.InDelta(t, 2.0/3, trends[1].YesShare, 0.001)
+	assert.Equal(t, 0, trends[2].Responses)
+	assert.Equal(t, map[string]int{"work": 1}, trends[3].Choices)
+}
+
+// TestAnalyzeRelapsePattern tests that high-risk times and triggers come from logged relapses
+func TestAnalyzeRelapsePattern(t *testing.T) {
+	s := &ProgressService{}
+	tracker := &domain.UserTracker{TotalRelapses: 4}
+
+	relapse := func(timeOfDay, day string, trigger domain.RelapseTrigger, daysClean int) *domain.RelapseRecord {
+		return &domain.RelapseRecord{TimeOfDay: timeOfDay, DayOfWeek: day, Trigger: trigger, DaysClean: daysClean}
+	}
+
+	pattern := s.analyzeRelapsePattern(tracker, []*domain.RelapseRecord{
+		relapse("night", "Friday", domain.RelapseTriggerStress, 10),
+		relapse("night", "Saturday", domain.RelapseTriggerLoneliness, 4),
+		relapse("evening", "Friday", domain.RelapseTriggerStress, 7),
+		relapse("morning", "Monday", domain.RelapseTriggerBoredom, 3),
+	})
+
+	assert.Equal(t, 4, pattern.TotalRelapses)
+	assert.Equal(t, 6.0, pattern.AverageTimeClean)
+	assert.Equal(t, "night", pattern.HighRiskTimeOfDay)
+	assert.Equal(t, "Friday", pattern.HighRiskDayOfWeek)
+	assert.Equal(t, []string{"stress", "boredom", "loneliness"}, pattern.CommonTriggers)
+	assert.Len(t, pattern.RecentRelapses, 4)
+
+	empty := s.analyzeRelapsePattern(&domain.UserTracker{}, nil)
+	assert.Empty(t, empty.HighRiskTimeOfDay)
+	assert.Empty(t, empty.CommonTriggers)
+}
+
+// TestProgressService_CalculateAchievements tests that stored unlock times are kept
+func TestProgressService_CalculateAchievements(t *testing.T) {
+	s := &ProgressService{}
+	unlockedAt := time.Date(2025, 1, 7, 8, 0, 0, 0, time.UTC)
+
+	// The streak has since reset, but first_week stays unlocked with its original time
+	achievements := s.calculateAchievements(&domain.UserTracker{
+		StreakDays:       2,
+		CravingsResisted: 20,
+		Achievements:     []domain.UnlockedAchievement{{ID: "first_week", UnlockedAt: unlockedAt}},
+	})
+
+	assert.Len(t, achievements, 2)
+	assert.Equal(t, "first_week", achievements[0].ID)
+	assert.Equal(t, unlockedAt, achievements[0].UnlockedAt)
+	assert.Equal(t, "craving_warrior", achievements[1].ID)
+}
+
+// TestLocalizeAchievements tests that built-in achievements are translated and custom text is kept
+func TestLocalizeAchievements(t *testing.T) {
+	spanish := i18n.For(language.Spanish)
+
+	var defaults []Achievement
+	for _, definition := range DefaultAchievements {
+		defaults = append(defaults, Achievement{ID: definition.ID, Title: definition.Title, Description: definition.Description})
+	}
+	for i, achievement := range localizeAchievements(spanish, defaults) {
+		assert.NotEqual(t, defaults[i].Title, achievement.Title, achievement.ID)
+		assert.NotEqual(t, defaults[i].Description, achievement.Description, achievement.ID)
+	}
+
+	custom := localizeAchievements(spanish, []Achievement{{ID: "first_week", Title: "Week One", Description: "Seven days"}})
+	assert.Equal(t, "Week One", custom[0].Title)
+	assert.Equal(t, "Seven days", custom[0].Description)
+
+	assert.Equal(t, []string{"Primer día limpio", "Una semana fuerte"}, calculateMilestones(spanish, &domain.UserTracker{StreakDays: 7}))
+}
+
+// TestLoadAchievements tests that custom definitions extend and override the defaults
+func TestLoadAchievements(t *testing.T) {
+	path := t.TempDir() + "/achievements.json"
+	assert.NoError(t, os.WriteFile(path, []byte(`[
+		{"id": "first_week", "title": "Week One", "metric": "streak_days", "threshold": 7},
+		{"id": "helper", "title": "Helper", "metric": "support_given", "threshold": 5}
+	]`), 0o600))
+
+	achievements, err := LoadAchievements(path)
+	assert.NoError(t, err)
+	assert.Len(t, achievements, len(DefaultAchievements)+1)
+	assert.Equal(t, "Week One", achievements[0].Title)
+	assert.Equal(t, "helper", achievements[len(achievements)-1].ID)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`[{"id": "x", "title": "X", "metric": "posts", "threshold": 1}]`), 0o600))
+	_, err = LoadAchievements(path)
+	assert.Error(t, err)
+}
+
+// TestProgressService_GetWeeklyProgress tests that each day reflects that day's activity
+func TestProgressService_GetWeeklyProgress(t *testing.T) {
+	s := &ProgressService{}
+	now := time.Date(2026, 5, 20, 15, 0, 0, 0, time.UTC)
+	today := domain.ProgressDay(now)
+	yesterday := today.AddDate(0, 0, -1)
+
+	progress := s.getWeeklyProgress(now,
+		[]*domain.DailyCheckIn{{Day: yesterday, CheckedIn: true, Cravings: 2}, {Day: today, Cravings: 1}},
+		map[time.Time]int{today: 3},
+		[]*domain.MoodEntry{{Day: yesterday, Score: 6}},
+	)
+
+	assert.Len(t, progress, 7)
+	assert.Equal(t, today.AddDate(0, 0, -6), progress[0].Date)
+	assert.Equal(t, DayProgress{Date: today.AddDate(0, 0, -6)}, progress[0])
+	assert.Equal(t, DayProgress{Date: yesterday, CheckedIn: true, CravingsCount: 2, MoodScore: 6}, progress[5])
+	assert.Equal(t, DayProgress{Date: today, CravingsCount: 1, SupportGiven: 3}, progress[6])
+}
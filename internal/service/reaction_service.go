@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+// ReactionService records one-tap reactions to posts and keeps each post's
+// per-type counts in step
+type ReactionService struct {
+	postRepo     repository.PostRepository
+	reactionRepo repository.ReactionRepository
+}
+
+func NewReactionService(postRepo repository.PostRepository, reactionRepo repository.ReactionRepository) *ReactionService {
+	return &ReactionService{
+		postRepo:     postRepo,
+		reactionRepo: reactionRepo,
+	}
+}
+
+// React sets the user's reaction to a post they can see, replacing any
+// earlier one
+func (s *ReactionService) React(ctx context.Context, userID, postID string, reaction domain.ReactionType) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ReactionService.React")
+	defer span.End()
+
+	if !reaction.Valid() {
+		return fmt.Errorf("invalid reaction type %q", reaction)
+	}
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if !post.VisibleTo(userID) {
+		return fmt.Errorf("post not found")
+	}
+
+	previous, err := s.reactionRepo.Set(ctx, &domain.PostReaction{PostID: postID, UserID: userID, Type: reaction})
+	if err != nil {
+		return err
+	}
+	return s.postRepo.AddReactionCounts(ctx, postID, reactionIncrements(previous, reaction))
+}
+
+// Unreact removes the user's reaction to a post, if any
+func (s *ReactionService) Unreact(ctx context.Context, userID, postID string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ReactionService.Unreact")
+	defer span.End()
+
+	removed, err := s.reactionRepo.Delete(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+	return s.postRepo.AddReactionCounts(ctx, postID, reactionIncrements(removed, ""))
+}
+
+// ViewerReactions returns the viewer's reactions to the posts, keyed by post
+// ID. Anonymous viewers have none.
+func (s *ReactionService) ViewerReactions(ctx context.Context, viewerID string, postIDs []string) (map[string]domain.ReactionType, error) {
+	if viewerID == "" {
+		return map[string]domain.ReactionType{}, nil
+	}
+	return s.reactionRepo.GetByUser(ctx, viewerID, postIDs)
+}
+
+// reactionIncrements returns the count changes for replacing a user's
+// reaction previous with next, either of which may be empty
+func reactionIncrements(previous, next domain.ReactionType) map[domain.ReactionType]int64 {
+	increments := map[domain.ReactionType]int64{}
+	if previous == next {
+		return increments
+	}
+	if previous != "" {
+		increments[previous]--
+	}
+	if next != "" {
+		increments[next]++
+	}
+	return increments
+}
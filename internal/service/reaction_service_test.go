@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository/memory"
+	"github.com/yourorg/anonymous-support/internal/testutil/factory"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReactionIncrements(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous domain.ReactionType
+		next     domain.ReactionType
+		want     map[domain.ReactionType]int64
+	}{
+		{"first reaction", "", domain.ReactionTypeHug, map[domain.ReactionType]int64{domain.ReactionTypeHug: 1}},
+		{"same reaction again", domain.ReactionTypeHug, domain.ReactionTypeHug, map[domain.ReactionType]int64{}},
+		{"changed reaction", domain.ReactionTypeHug, domain.ReactionTypeHeart, map[domain.ReactionType]int64{domain.ReactionTypeHug: -1, domain.ReactionTypeHeart: 1}},
+		{"removed reaction", domain.ReactionTypeStrength, "", map[domain.ReactionType]int64{domain.ReactionTypeStrength: -1}},
+		{"nothing to remove", "", "", map[domain.ReactionType]int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, reactionIncrements(tt.previous, tt.next))
+		})
+	}
+}
+
+func TestReactionService_React(t *testing.T) {
+	ctx := context.Background()
+	posts := memory.NewPostRepository()
+	s := NewReactionService(posts, memory.NewReactionRepository())
+
+	post := factory.Post()
+	require.NoError(t, posts.Create(ctx, post))
+	postID := post.ID.Hex()
+
+	require.NoError(t, s.React(ctx, "viewer", postID, domain.ReactionTypeHug))
+	require.NoError(t, s.React(ctx, "viewer", postID, domain.ReactionTypeHeart))
+	require.NoError(t, s.React(ctx, "other", postID, domain.ReactionTypeHeart))
+
+	stored, err := posts.GetByID(ctx, postID)
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.ReactionType]int64{domain.ReactionTypeHug: 0, domain.ReactionTypeHeart: 2}, stored.ReactionCounts)
+
+	reactions, err := s.ViewerReactions(ctx, "viewer", []string{postID})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]domain.ReactionType{postID: domain.ReactionTypeHeart}, reactions)
+
+	require.NoError(t, s.Unreact(ctx, "viewer", postID))
+	require.NoError(t, s.Unreact(ctx, "viewer", postID))
+	stored, err = posts.GetByID(ctx, postID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stored.ReactionCounts[domain.ReactionTypeHeart])
+
+	assert.Error(t, s.React(ctx, "viewer", postID, "shrug"))
+	assert.Error(t, s.React(ctx, "viewer", primitive.NewObjectID().Hex(), domain.ReactionTypeHug))
+}
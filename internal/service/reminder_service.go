@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	// reminderBatchSize bounds how many users are claimed per query
+	reminderBatchSize = 500
+
+	// digestInterval is the time between a user's weekly digests
+	digestInterval = 7 * 24 * time.Hour
+
+	// riskNudgeInterval is the minimum time between a user's risk window
+	// nudges, so users with several windows are nudged at most once a day
+	riskNudgeInterval = 20 * time.Hour
+)
+
+// ReminderService sends scheduled reminder notifications
+type ReminderService struct {
+	prefsRepo     repository.NotificationPreferencesRepository
+	analyticsRepo repository.AnalyticsRepository
+	progress      *ProgressService
+	notifier      *NotificationService
+	logger        *zap.Logger
+}
+
+func NewReminderService(
+	prefsRepo repository.NotificationPreferencesRepository,
+	analyticsRepo repository.AnalyticsRepository,
+	progress *ProgressService,
+	notifier *NotificationService,
+	logger *zap.Logger,
+) *ReminderService {
+	return &ReminderService{
+		prefsRepo:     prefsRepo,
+		analyticsRepo: analyticsRepo,
+		progress:      progress,
+		notifier:      notifier,
+		logger:        logger,
+	}
+}
+
+// SendCheckInReminders reminds every user whose reminder time has passed
+// today in their time zone, skipping those who already checked in today.
+// It returns the number of reminders sent.
+func (s *ReminderService) SendCheckInReminders(ctx context.Context) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ReminderService.SendCheckInReminders")
+	defer span.End()
+
+	sent := 0
+	for {
+		due, err := s.prefsRepo.ClaimDueCheckInReminders(ctx, reminderBatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		now := time.Now()
+		for _, prefs := range due {
+			userID := prefs.UserID.String()
+
+			tracker, err := s.analyticsRepo.GetTracker(ctx, userID)
+			if err == nil && tracker.CheckedInOn(now, prefs.Location()) {
+				continue
+			}
+
+			if err := s.notifier.Notify(ctx, userID, domain.NotificationTypeCheckInReminder,
+				"Daily Check-In", "How are you doing today? Take a moment to check in.", nil); err != nil {
+				s.logger.Warn("Failed to send check-in reminder", zap.String("user_id", userID), zap.Error(err))
+				continue
+			}
+			sent++
+		}
+
+		if len(due) < reminderBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// SendRiskWindowNudges suggests a check-in or coping exercise to users whose
+// risk window starts within the next hour. It returns the number of nudges sent.
+func (s *ReminderService) SendRiskWindowNudges(ctx context.Context) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ReminderService.SendRiskWindowNudges")
+	defer span.End()
+
+	now := time.Now()
+	startHour := now.UTC().Add(time.Hour).Hour()
+	nudgedBefore := now.Add(-riskNudgeInterval)
+
+	sent := 0
+	for {
+		tracker, err := s.analyticsRepo.ClaimRiskNudge(ctx, startHour, nudgedBefore)
+		if err != nil {
+			return sent, err
+		}
+		if tracker == nil {
+			return sent, nil
+		}
+
+		if err := s.notifier.Notify(ctx, tracker.UserID, domain.NotificationTypeRiskWindow, "Heads Up",
+			"The next few hours have often been hard for you. A quick check-in or a coping exercise now can help you through them.",
+			map[string]string{"window_start_hour": strconv.Itoa(startHour)}); err != nil {
+			s.logger.Warn("Failed to send risk window nudge", zap.String("user_id", tracker.UserID), zap.Error(err))
+			continue
+		}
+		sent++
+	}
+}
+
+// SendWeeklyDigests sends a progress digest to every user whose last digest is
+// at least a week old. Users with nothing to report are skipped until next
+// week. It returns the number of digests sent.
+func (s *ReminderService) SendWeeklyDigests(ctx context.Context) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "ReminderService.SendWeeklyDigests")
+	defer span.End()
+
+	now := time.Now()
+	sentBefore := now.Add(-digestInterval)
+
+	sent := 0
+	for {
+		trackers, err := s.analyticsRepo.ListTrackersDueForDigest(ctx, sentBefore, reminderBatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, tracker := range trackers {
+			digest := s.progress.BuildWeeklyDigest(tracker, now)
+
+			claimed, err := s.analyticsRepo.ClaimDigest(ctx, tracker.UserID, sentBefore, digest.Snapshot)
+			if err != nil {
+				return sent, err
+			}
+			if !claimed || digest.IsEmpty() {
+				continue
+			}
+
+			title, body := digest.Message()
+			if err := s.notifier.Notify(ctx, tracker.UserID, domain.NotificationTypeWeeklyDigest, title, body, map[string]string{
+				"streak_days":      strconv.Itoa(digest.StreakDays),
+				"support_given":    strconv.Itoa(digest.SupportGiven),
+				"support_received": strconv.Itoa(digest.SupportReceived),
+			}); err != nil {
+				s.logger.Warn("Failed to send weekly digest", zap.String("user_id", tracker.UserID), zap.Error(err))
+				continue
+			}
+			sent++
+		}
+
+		if len(trackers) < reminderBatchSize {
+			return sent, nil
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+// TestUserTracker_CheckedInOn tests that check-ins are compared by the user's local day
+func TestUserTracker_CheckedInOn(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+
+	checkIn := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name      string
+		lastCheck *time.Time
+		now       time.Time
+		loc       *time.Location
+		want      bool
+	}{
+		{
+			name: "never checked in",
+			now:  time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			want: false,
+		},
+		{
+			name:      "earlier the same day",
+			lastCheck: checkIn(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)),
+			now:       time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC),
+			loc:       time.UTC,
+			want:      true,
+		},
+		{
+			name:      "yesterday",
+			lastCheck: checkIn(time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC)),
+			now:       time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC),
+			loc:       time.UTC,
+			want:      false,
+		},
+		{
+			name:      "same UTC day but previous local day",
+			lastCheck: checkIn(time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)), // 23:00 in Tokyo
+			now:       time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC),          // 01:00 next day in Tokyo
+			loc:       tokyo,
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := &domain.UserTracker{LastCheckInAt: tt.lastCheck}
+			assert.Equal(t, tt.want, tracker.CheckedInOn(tt.now, tt.loc))
+		})
+	}
+}
+
+// TestProgressService_BuildWeeklyDigest tests that digests report changes since the previous one
+func TestProgressService_BuildWeeklyDigest(t *testing.T) {
+	s := &ProgressService{}
+	now := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+
+	t.Run("first digest reports totals", func(t *testing.T) {
+		digest := s.BuildWeeklyDigest(&domain.UserTracker{StreakDays: 8, SupportGiven: 3, SupportReceived: 5}, now)
+
+		assert.Equal(t, 3, digest.SupportGiven)
+		assert.Equal(t, 5, digest.SupportReceived)
+		assert.Len(t, digest.NewAchievements, 1)
+		assert.Equal(t, []string{"first_week"}, digest.Snapshot.Achievements)
+		assert.Equal(t, now, digest.Snapshot.SentAt)
+	})
+
+	t.Run("later digest reports deltas", func(t *testing.T) {
+		digest := s.BuildWeeklyDigest(&domain.UserTracker{
+			StreakDays:      15,
+			SupportGiven:    10,
+			SupportReceived: 5,
+			LastDigest: &domain.DigestSnapshot{
+				StreakDays:      8,
+				SupportGiven:    3,
+				SupportReceived: 5,
+				Achievements:    []string{"first_week"},
+			},
+		}, now)
+
+		assert.Equal(t, 7, digest.SupportGiven)
+		assert.Equal(t, 0, digest.SupportReceived)
+		assert.Empty(t, digest.NewAchievements)
+		assert.False(t, digest.IsEmpty())
+	})
+
+	t.Run("inactive user has nothing to report", func(t *testing.T) {
+		digest := s.BuildWeeklyDigest(&domain.UserTracker{}, now)
+		assert.True(t, digest.IsEmpty())
+	})
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/metrics"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+)
+
+const retentionBatchSize = 1000
+
+// RetentionService deletes or anonymizes records once they are older than
+// their data class's retention period. In dry-run mode it only reports how
+// many records each rule would change, so rules can be reviewed before
+// anything is removed.
+type RetentionService struct {
+	stores map[domain.DataClass]repository.RetentionRepository
+	rules  []domain.RetentionRule
+	dryRun bool
+}
+
+func NewRetentionService(stores map[domain.DataClass]repository.RetentionRepository, rules []domain.RetentionRule, dryRun bool) *RetentionService {
+	return &RetentionService{
+		stores: stores,
+		rules:  rules,
+		dryRun: dryRun,
+	}
+}
+
+// Enforce applies every rule, or in dry-run mode counts the records each would
+// change. A failing rule doesn't stop the others; its error is returned once
+// they have run.
+func (s *RetentionService) Enforce(ctx context.Context) ([]domain.RetentionResult, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "RetentionService.Enforce")
+	defer span.End()
+
+	now := time.Now()
+	results := make([]domain.RetentionResult, 0, len(s.rules))
+	var errs []error
+	for _, rule := range s.rules {
+		result, err := s.enforce(ctx, rule, now.Add(-rule.MaxAge))
+		results = append(results, result)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s retention failed: %w", rule.Class, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+func (s *RetentionService) enforce(ctx context.Context, rule domain.RetentionRule, cutoff time.Time) (domain.RetentionResult, error) {
+	result := domain.RetentionResult{Class: rule.Class, Action: rule.Action, Cutoff: cutoff, DryRun: s.dryRun}
+	store, ok := s.stores[rule.Class]
+	if !ok {
+		return result, fmt.Errorf("no store for data class %s", rule.Class)
+	}
+
+	expired, err := store.CountExpired(ctx, rule.Class, rule.Action, cutoff)
+	if err != nil {
+		return result, err
+	}
+	metrics.RetentionExpiredRecords.WithLabelValues(string(rule.Class), string(rule.Action)).Set(float64(expired))
+	if s.dryRun || expired == 0 {
+		result.Records = expired
+		return result, nil
+	}
+
+	// Work in batches so no single statement locks or rewrites a whole collection
+	for ctx.Err() == nil {
+		var changed int64
+		if rule.Action == domain.RetentionActionAnonymize {
+			changed, err = store.AnonymizeExpired(ctx, rule.Class, cutoff, retentionBatchSize)
+		} else {
+			changed, err = store.DeleteExpired(ctx, rule.Class, cutoff, retentionBatchSize)
+		}
+		result.Records += changed
+		metrics.RetentionRecordsTotal.WithLabelValues(string(rule.Class), string(rule.Action)).Add(float64(changed))
+		if err != nil || changed < retentionBatchSize {
+			return result, err
+		}
+	}
+	return result, ctx.Err()
+}
+
+// ParseRetentionRules parses "class:max_age:action" entries such as
+// "audit_logs:8760h:delete". Each data class may have one rule.
+func ParseRetentionRules(entries []string) ([]domain.RetentionRule, error) {
+	rules := make([]domain.RetentionRule, 0, len(entries))
+	seen := make(map[domain.DataClass]bool, len(entries))
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("retention rule %q must be class:max_age:action", entry)
+		}
+
+		class := domain.DataClass(parts[0])
+		switch class {
+		case domain.DataClassPosts, domain.DataClassResponses, domain.DataClassAuditLogs, domain.DataClassAnalyticsEvents:
+		default:
+			return nil, fmt.Errorf("retention rule %q has unknown data class %q", entry, parts[0])
+		}
+		if seen[class] {
+			return nil, fmt.Errorf("more than one retention rule for %s", class)
+		}
+		seen[class] = true
+
+		maxAge, err := time.ParseDuration(parts[1])
+		if err != nil || maxAge <= 0 {
+			return nil, fmt.Errorf("retention rule %q has invalid max age %q", entry, parts[1])
+		}
+
+		action := domain.RetentionAction(parts[2])
+		if action != domain.RetentionActionDelete && action != domain.RetentionActionAnonymize {
+			return nil, fmt.Errorf("retention rule %q has unknown action %q", entry, parts[2])
+		}
+
+		rules = append(rules, domain.RetentionRule{Class: class, MaxAge: maxAge, Action: action})
+	}
+	return rules, nil
+}
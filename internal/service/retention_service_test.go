@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"github.com/yourorg/anonymous-support/internal/repository/memory"
+)
+
+// TestParseRetentionRules tests that rule entries are parsed and invalid ones rejected
+func TestParseRetentionRules(t *testing.T) {
+	rules, err := ParseRetentionRules([]string{"audit_logs:8760h:delete", "posts:4320h:anonymize"})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.RetentionRule{
+		{Class: domain.DataClassAuditLogs, MaxAge: 8760 * time.Hour, Action: domain.RetentionActionDelete},
+		{Class: domain.DataClassPosts, MaxAge: 4320 * time.Hour, Action: domain.RetentionActionAnonymize},
+	}, rules)
+
+	invalid := []struct {
+		name    string
+		entries []string
+	}{
+		{name: "missing action", entries: []string{"posts:720h"}},
+		{name: "unknown class", entries: []string{"journal:720h:delete"}},
+		{name: "unknown action", entries: []string{"posts:720h:archive"}},
+		{name: "bad max age", entries: []string{"posts:30d:delete"}},
+		{name: "zero max age", entries: []string{"posts:0s:delete"}},
+		{name: "duplicate class", entries: []string{"posts:720h:delete", "posts:8760h:anonymize"}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRetentionRules(tt.entries)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// recordingRetentionStore counts the calls made to a store and can fail them
+type recordingRetentionStore struct {
+	repository.RetentionRepository
+	counts, deletes, anonymizes int
+	err                         error
+}
+
+func (s *recordingRetentionStore) CountExpired(ctx context.Context, class domain.DataClass, action domain.RetentionAction, cutoff time.Time) (int64, error) {
+	s.counts++
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.RetentionRepository.CountExpired(ctx, class, action, cutoff)
+}
+
+func (s *recordingRetentionStore) DeleteExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error) {
+	s.deletes++
+	return s.RetentionRepository.DeleteExpired(ctx, class, cutoff, limit)
+}
+
+func (s *recordingRetentionStore) AnonymizeExpired(ctx context.Context, class domain.DataClass, cutoff time.Time, limit int) (int64, error) {
+	s.anonymizes++
+	return s.RetentionRepository.AnonymizeExpired(ctx, class, cutoff, limit)
+}
+
+// TestRetentionService_Enforce tests dry runs, batching, per-rule errors and cancellation
+func TestRetentionService_Enforce(t *testing.T) {
+	ctx := context.Background()
+	expired := time.Now().Add(-48 * time.Hour)
+	rules := []domain.RetentionRule{
+		{Class: domain.DataClassPosts, MaxAge: 24 * time.Hour, Action: domain.RetentionActionDelete},
+		{Class: domain.DataClassAuditLogs, MaxAge: 24 * time.Hour, Action: domain.RetentionActionAnonymize},
+	}
+	newStores := func() (*memory.RetentionRepository, map[domain.DataClass]*recordingRetentionStore) {
+		repo := memory.NewRetentionRepository()
+		repo.Add(domain.DataClassPosts, expired, 2*retentionBatchSize+500)
+		repo.Add(domain.DataClassPosts, time.Now(), 10)
+		repo.Add(domain.DataClassAuditLogs, expired, retentionBatchSize)
+		return repo, map[domain.DataClass]*recordingRetentionStore{
+			domain.DataClassPosts:     {RetentionRepository: repo},
+			domain.DataClassAuditLogs: {RetentionRepository: repo},
+		}
+	}
+	asStores := func(stores map[domain.DataClass]*recordingRetentionStore) map[domain.DataClass]repository.RetentionRepository {
+		out := make(map[domain.DataClass]repository.RetentionRepository, len(stores))
+		for class, store := range stores {
+			out[class] = store
+		}
+		return out
+	}
+
+	t.Run("dry run only counts", func(t *testing.T) {
+		repo, stores := newStores()
+		results, err := NewRetentionService(asStores(stores), rules, true).Enforce(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2*retentionBatchSize+500), results[0].Records)
+		assert.Equal(t, int64(retentionBatchSize), results[1].Records)
+		assert.True(t, results[0].DryRun)
+		for _, store := range stores {
+			assert.Equal(t, 1, store.counts)
+			assert.Zero(t, store.deletes+store.anonymizes)
+		}
+		total, _ := repo.Count(domain.DataClassPosts)
+		assert.Equal(t, 2*retentionBatchSize+510, total)
+		_, anonymized := repo.Count(domain.DataClassAuditLogs)
+		assert.Zero(t, anonymized)
+	})
+
+	t.Run("batches until a short batch", func(t *testing.T) {
+		repo, stores := newStores()
+		results, err := NewRetentionService(asStores(stores), rules, false).Enforce(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2*retentionBatchSize+500), results[0].Records)
+		assert.Equal(t, 3, stores[domain.DataClassPosts].deletes)
+		total, _ := repo.Count(domain.DataClassPosts)
+		assert.Equal(t, 10, total)
+
+		// A full last batch takes one more, empty, batch to notice the end
+		assert.Equal(t, int64(retentionBatchSize), results[1].Records)
+		assert.Equal(t, 2, stores[domain.DataClassAuditLogs].anonymizes)
+		_, anonymized := repo.Count(domain.DataClassAuditLogs)
+		assert.Equal(t, retentionBatchSize, anonymized)
+	})
+
+	t.Run("failing rules don't stop the others", func(t *testing.T) {
+		repo, stores := newStores()
+		failure := errors.New("connection refused")
+		stores[domain.DataClassPosts].err = failure
+		failing := []domain.RetentionRule{rules[0], rules[1], {Class: domain.DataClassResponses, MaxAge: time.Hour, Action: domain.RetentionActionDelete}}
+
+		results, err := NewRetentionService(asStores(stores), failing, false).Enforce(ctx)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, failure)
+		assert.ErrorContains(t, err, "posts retention failed")
+		assert.ErrorContains(t, err, "no store for data class responses")
+
+		require.Len(t, results, 3)
+		assert.Equal(t, int64(retentionBatchSize), results[1].Records)
+		_, anonymized := repo.Count(domain.DataClassAuditLogs)
+		assert.Equal(t, retentionBatchSize, anonymized)
+	})
+
+	t.Run("cancellation stops before changing records", func(t *testing.T) {
+		repo, stores := newStores()
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := NewRetentionService(asStores(stores), rules, false).Enforce(cancelled)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, stores[domain.DataClassPosts].deletes)
+		total, _ := repo.Count(domain.DataClassPosts)
+		assert.Equal(t, 2*retentionBatchSize+510, total)
+	})
+}
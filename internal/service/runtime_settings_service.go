@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/audit"
+	"github.com/yourorg/anonymous-support/internal/pkg/feed"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrUnknownRuntimeSetting is returned for a key that isn't a runtime setting
+	ErrUnknownRuntimeSetting = errors.New("unknown runtime setting")
+	// ErrInvalidRuntimeSetting is returned for a value out of a setting's range
+	ErrInvalidRuntimeSetting = errors.New("invalid runtime setting")
+)
+
+// RuntimeSettings are tunables admins can change without a restart. Values
+// not overridden come from the static configuration.
+type RuntimeSettings struct {
+	RequestsPerMinute     int
+	AuthRequestsPerMinute int
+	PostsPerHour          int
+	ResponsesPerHour      int
+	ToxicityThreshold     float64
+	ReportThreshold       int
+	FeedWeights           feed.RankingWeights
+}
+
+// runtimeSetting describes one overridable field of RuntimeSettings
+type runtimeSetting struct {
+	key         string
+	description string
+	integer     bool
+	min, max    float64
+	get         func(RuntimeSettings) float64
+	set         func(*RuntimeSettings, float64)
+}
+
+var runtimeSettings = []runtimeSetting{
+	{
+		key: "rate_limit.requests_per_minute", description: "Requests per minute per caller, across all procedures",
+		integer: true, min: 1, max: 1_000_000,
+		get: func(s RuntimeSettings) float64 { return float64(s.RequestsPerMinute) },
+		set: func(s *RuntimeSettings, v float64) { s.RequestsPerMinute = int(v) },
+	},
+	{
+		key: "rate_limit.auth_requests_per_minute", description: "Auth service requests per minute per caller",
+		integer: true, min: 1, max: 1_000_000,
+		get: func(s RuntimeSettings) float64 { return float64(s.AuthRequestsPerMinute) },
+		set: func(s *RuntimeSettings, v float64) { s.AuthRequestsPerMinute = int(v) },
+	},
+	{
+		key: "rate_limit.posts_per_hour", description: "Posts per hour per user",
+		integer: true, min: 1, max: 1_000_000,
+		get: func(s RuntimeSettings) float64 { return float64(s.PostsPerHour) },
+		set: func(s *RuntimeSettings, v float64) { s.PostsPerHour = int(v) },
+	},
+	{
+		key: "rate_limit.responses_per_hour", description: "Support responses per hour per user",
+		integer: true, min: 1, max: 1_000_000,
+		get: func(s RuntimeSettings) float64 { return float64(s.ResponsesPerHour) },
+		set: func(s *RuntimeSettings, v float64) { s.ResponsesPerHour = int(v) },
+	},
+	{
+		key: "moderation.toxicity_threshold", description: "Toxicity score at or above which auto-moderation acts",
+		min: 0, max: 1,
+		get: func(s RuntimeSettings) float64 { return s.ToxicityThreshold },
+		set: func(s *RuntimeSettings, v float64) { s.ToxicityThreshold = v },
+	},
+	{
+		key: "moderation.report_threshold", description: "Weighted reports on one piece of content at which auto-moderation acts",
+		integer: true, min: 1, max: 1000,
+		get: func(s RuntimeSettings) float64 { return float64(s.ReportThreshold) },
+		set: func(s *RuntimeSettings, v float64) { s.ReportThreshold = int(v) },
+	},
+	{
+		key: "feed.recency_weight", description: "Feed ranking weight of post age",
+		min: 0, max: 1,
+		get: func(s RuntimeSettings) float64 { return s.FeedWeights.RecencyWeight },
+		set: func(s *RuntimeSettings, v float64) { s.FeedWeights.RecencyWeight = v },
+	},
+	{
+		key: "feed.urgency_weight", description: "Feed ranking weight of post urgency",
+		min: 0, max: 1,
+		get: func(s RuntimeSettings) float64 { return s.FeedWeights.UrgencyWeight },
+		set: func(s *RuntimeSettings, v float64) { s.FeedWeights.UrgencyWeight = v },
+	},
+	{
+		key: "feed.engagement_weight", description: "Feed ranking weight of responses and support",
+		min: 0, max: 1,
+		get: func(s RuntimeSettings) float64 { return s.FeedWeights.EngagementWeight },
+		set: func(s *RuntimeSettings, v float64) { s.FeedWeights.EngagementWeight = v },
+	},
+	{
+		key: "feed.category_weight", description: "Feed ranking weight of matching the reader's categories",
+		min: 0, max: 1,
+		get: func(s RuntimeSettings) float64 { return s.FeedWeights.CategoryWeight },
+		set: func(s *RuntimeSettings, v float64) { s.FeedWeights.CategoryWeight = v },
+	},
+	{
+		key: "feed.circle_weight", description: "Feed ranking weight of posts from the reader's circles",
+		min: 0, max: 1,
+		get: func(s RuntimeSettings) float64 { return s.FeedWeights.CircleWeight },
+		set: func(s *RuntimeSettings, v float64) { s.FeedWeights.CircleWeight = v },
+	},
+	{
+		key: "feed.diversity_penalty", description: "Feed score penalty for consecutive posts by the same author",
+		min: 0, max: 1,
+		get: func(s RuntimeSettings) float64 { return s.FeedWeights.DiversityPenalty },
+		set: func(s *RuntimeSettings, v float64) { s.FeedWeights.DiversityPenalty = v },
+	},
+}
+
+// RuntimeSettingInfo is a runtime setting's current value and default
+type RuntimeSettingInfo struct {
+	Key         string
+	Description string
+	Value       string
+	Default     string
+	Override    *domain.RuntimeSetting // Nil when the default applies
+}
+
+// RuntimeSettingsService stores admin overrides of runtime settings and keeps
+// every instance's copy current. A change is applied locally at once and
+// announced so other instances reload; a periodic reload catches up on any
+// announcement they miss.
+type RuntimeSettingsService struct {
+	repo     repository.RuntimeSettingRepository
+	notifier repository.RuntimeSettingNotifier
+	defaults RuntimeSettings
+	audit    *audit.Writer
+	logger   *zap.Logger
+
+	mu        sync.RWMutex
+	current   RuntimeSettings
+	listeners []func(RuntimeSettings)
+}
+
+func NewRuntimeSettingsService(
+	repo repository.RuntimeSettingRepository,
+	notifier repository.RuntimeSettingNotifier,
+	defaults RuntimeSettings,
+	auditWriter *audit.Writer,
+	logger *zap.Logger,
+) *RuntimeSettingsService {
+	return &RuntimeSettingsService{
+		repo:     repo,
+		notifier: notifier,
+		defaults: defaults,
+		audit:    auditWriter,
+		logger:   logger,
+		current:  defaults,
+	}
+}
+
+// Current returns the settings in effect on this instance
+func (s *RuntimeSettingsService) Current() RuntimeSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// OnChange registers fn to be called with the new settings whenever they change
+func (s *RuntimeSettingsService) OnChange(fn func(RuntimeSettings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reload reads the overrides and applies them over the defaults. Stored
+// values that are no longer valid are logged and skipped.
+func (s *RuntimeSettingsService) Reload(ctx context.Context) error {
+	overrides, err := s.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load runtime settings: %w", err)
+	}
+
+	settings, invalid := applyRuntimeOverrides(s.defaults, overrides)
+	for _, err := range invalid {
+		s.logger.Warn("Ignoring invalid runtime setting", zap.Error(err))
+	}
+
+	s.mu.Lock()
+	changed := settings != s.current
+	s.current = settings
+	listeners := s.listeners
+	s.mu.Unlock()
+
+	if changed {
+		for _, fn := range listeners {
+			fn(settings)
+		}
+	}
+	return nil
+}
+
+// Watch reloads the settings whenever any instance changes one, until ctx is done
+func (s *RuntimeSettingsService) Watch(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := s.notifier.SubscribeChanges(ctx, func(key string) {
+			if err := s.Reload(ctx); err != nil {
+				s.logger.Warn("Failed to reload runtime settings", zap.String("key", key), zap.Error(err))
+			}
+		})
+		if err != nil {
+			s.logger.Warn("Runtime setting subscription failed", zap.Error(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// List returns every runtime setting, with overrides read fresh from the database
+func (s *RuntimeSettingsService) List(ctx context.Context) ([]*RuntimeSettingInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "RuntimeSettingsService.List")
+	defer span.End()
+
+	overrides, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*domain.RuntimeSetting, len(overrides))
+	for _, override := range overrides {
+		byKey[override.Key] = override
+	}
+
+	current := s.Current()
+	infos := make([]*RuntimeSettingInfo, len(runtimeSettings))
+	for i, setting := range runtimeSettings {
+		infos[i] = &RuntimeSettingInfo{
+			Key:         setting.key,
+			Description: setting.description,
+			Value:       formatRuntimeValue(setting.get(current)),
+			Default:     formatRuntimeValue(setting.get(s.defaults)),
+			Override:    byKey[setting.key],
+		}
+	}
+	return infos, nil
+}
+
+// Set overrides a setting, or returns it to its default when value is empty.
+// actorID is empty when a service client sets it.
+func (s *RuntimeSettingsService) Set(ctx context.Context, actorID, key, value string) (*RuntimeSettingInfo, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "RuntimeSettingsService.Set")
+	defer span.End()
+
+	setting, ok := findRuntimeSetting(key)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownRuntimeSetting, key)
+	}
+	previous := formatRuntimeValue(setting.get(s.Current()))
+
+	info := &RuntimeSettingInfo{
+		Key:         key,
+		Description: setting.description,
+		Default:     formatRuntimeValue(setting.get(s.defaults)),
+	}
+	if value == "" {
+		if err := s.repo.Delete(ctx, key); err != nil {
+			return nil, err
+		}
+		info.Value = info.Default
+	} else {
+		parsed, err := parseRuntimeValue(setting, value)
+		if err != nil {
+			return nil, err
+		}
+		var updatedBy *uuid.UUID
+		if id, err := uuid.Parse(actorID); err == nil {
+			updatedBy = &id
+		}
+		override, err := s.repo.Set(ctx, key, formatRuntimeValue(parsed), updatedBy)
+		if err != nil {
+			return nil, err
+		}
+		info.Value = override.Value
+		info.Override = override
+	}
+
+	s.audit.Record(ctx, audit.Event{
+		Type:       domain.AuditEventRuntimeSettingSet,
+		ActorID:    actorID,
+		TargetType: "runtime_setting",
+		Action:     fmt.Sprintf("set %s from %s to %s", key, previous, info.Value),
+	})
+
+	if err := s.Reload(ctx); err != nil {
+		s.logger.Warn("Failed to reload runtime settings", zap.String("key", key), zap.Error(err))
+	}
+	if err := s.notifier.PublishChange(ctx, key); err != nil {
+		s.logger.Warn("Failed to announce runtime setting change; other instances pick it up on their next reload",
+			zap.String("key", key), zap.Error(err))
+	}
+	return info, nil
+}
+
+// applyRuntimeOverrides returns defaults with every valid override applied,
+// and an error for each override that isn't
+func applyRuntimeOverrides(defaults RuntimeSettings, overrides []*domain.RuntimeSetting) (RuntimeSettings, []error) {
+	settings := defaults
+	var invalid []error
+	for _, override := range overrides {
+		setting, ok := findRuntimeSetting(override.Key)
+		if !ok {
+			invalid = append(invalid, fmt.Errorf("%w %q", ErrUnknownRuntimeSetting, override.Key))
+			continue
+		}
+		value, err := parseRuntimeValue(setting, override.Value)
+		if err != nil {
+			invalid = append(invalid, err)
+			continue
+		}
+		setting.set(&settings, value)
+	}
+	return settings, invalid
+}
+
+func findRuntimeSetting(key string) (runtimeSetting, bool) {
+	for _, setting := range runtimeSettings {
+		if setting.key == key {
+			return setting, true
+		}
+	}
+	return runtimeSetting{}, false
+}
+
+// parseRuntimeValue parses a value and checks it's within the setting's range
+func parseRuntimeValue(setting runtimeSetting, value string) (float64, error) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(parsed) {
+		return 0, fmt.Errorf("%w: %s must be a number, got %q", ErrInvalidRuntimeSetting, setting.key, value)
+	}
+	if setting.integer && parsed != math.Trunc(parsed) {
+		return 0, fmt.Errorf("%w: %s must be a whole number, got %q", ErrInvalidRuntimeSetting, setting.key, value)
+	}
+	if parsed < setting.min || parsed > setting.max {
+		return 0, fmt.Errorf("%w: %s must be between %s and %s, got %q", ErrInvalidRuntimeSetting,
+			setting.key, formatRuntimeValue(setting.min), formatRuntimeValue(setting.max), value)
+	}
+	return parsed, nil
+}
+
+func formatRuntimeValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
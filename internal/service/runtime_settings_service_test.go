@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/feed"
+)
+
+// TestRuntimeSettingKeysAreUnique tests that no two settings share a key
+func TestRuntimeSettingKeysAreUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, setting := range runtimeSettings {
+		assert.False(t, seen[setting.key], setting.key)
+		seen[setting.key] = true
+	}
+}
+
+// TestApplyRuntimeOverrides tests that valid overrides replace defaults and invalid ones are skipped
+func TestApplyRuntimeOverrides(t *testing.T) {
+	defaults := RuntimeSettings{
+		RequestsPerMinute: 100,
+		PostsPerHour:      10,
+		ToxicityThreshold: 0.8,
+		ReportThreshold:   3,
+		FeedWeights:       feed.DefaultWeights(),
+	}
+
+	settings, invalid := applyRuntimeOverrides(defaults, []*domain.RuntimeSetting{
+		{Key: "rate_limit.posts_per_hour", Value: "20"},
+		{Key: "moderation.toxicity_threshold", Value: "0.65"},
+		{Key: "feed.circle_weight", Value: "0.4"},
+		{Key: "moderation.report_threshold", Value: "2.5"}, // Not a whole number
+		{Key: "feed.recency_weight", Value: "3"},           // Out of range
+		{Key: "rate_limit.removed", Value: "1"},            // Unknown
+	})
+
+	assert.Len(t, invalid, 3)
+	assert.Equal(t, 100, settings.RequestsPerMinute)
+	assert.Equal(t, 20, settings.PostsPerHour)
+	assert.Equal(t, 0.65, settings.ToxicityThreshold)
+	assert.Equal(t, 3, settings.ReportThreshold)
+	assert.Equal(t, 0.4, settings.FeedWeights.CircleWeight)
+	assert.Equal(t, feed.DefaultWeights().RecencyWeight, settings.FeedWeights.RecencyWeight)
+	assert.Equal(t, 10, defaults.PostsPerHour, "defaults must not change")
+}
+
+// TestParseRuntimeValue tests value validation
+func TestParseRuntimeValue(t *testing.T) {
+	setting, ok := findRuntimeSetting("rate_limit.requests_per_minute")
+	assert.True(t, ok)
+
+	value, err := parseRuntimeValue(setting, "250")
+	assert.NoError(t, err)
+	assert.Equal(t, 250.0, value)
+
+	for _, invalid := range []string{"", "abc", "NaN", "0", "-5", "1.5", "2000000"} {
+		_, err := parseRuntimeValue(setting, invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -5,22 +5,26 @@ import (
 	"strings"
 
 	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
 type SearchService struct {
 	postRepo   repository.PostRepository
+	sensitive  *SensitiveContent
 	circleRepo repository.CircleRepository
 	userRepo   repository.UserRepository
 }
 
 func NewSearchService(
 	postRepo repository.PostRepository,
+	sensitive *SensitiveContent,
 	circleRepo repository.CircleRepository,
 	userRepo repository.UserRepository,
 ) *SearchService {
 	return &SearchService{
 		postRepo:   postRepo,
+		sensitive:  sensitive,
 		circleRepo: circleRepo,
 		userRepo:   userRepo,
 	}
@@ -36,6 +40,9 @@ type SearchResults struct {
 
 // Search performs a full-text search across posts and circles
 func (s *SearchService) Search(ctx context.Context, query string, filters *SearchFilters) (*SearchResults, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "SearchService.Search")
+	defer span.End()
+
 	query = strings.TrimSpace(query)
 	if query == "" {
 		return &SearchResults{
@@ -91,6 +98,9 @@ func (s *SearchService) searchPosts(ctx context.Context, query string, filters *
 	if err != nil {
 		return nil, err
 	}
+	if err := s.sensitive.OpenPosts(posts); err != nil {
+		return nil, err
+	}
 
 	// Filter by query in content (basic text matching)
 	filtered := []*domain.Post{}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/encryption"
+)
+
+// SensitiveContent encrypts the content of posts in sensitive categories, and
+// of responses to them, before it is stored. Stored content is decrypted only
+// when the service layer reads it back, so ciphertext is what ends up in
+// MongoDB, the Redis caches and the event bus. Changing the category list
+// leaves content already stored as it was written.
+type SensitiveContent struct {
+	encManager *encryption.Manager
+	categories map[string]bool
+}
+
+func NewSensitiveContent(encManager *encryption.Manager, categories []string) *SensitiveContent {
+	set := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		set[category] = true
+	}
+	return &SensitiveContent{
+		encManager: encManager,
+		categories: set,
+	}
+}
+
+// IsSensitive reports whether any of categories is marked sensitive
+func (s *SensitiveContent) IsSensitive(categories []string) bool {
+	for _, category := range categories {
+		if s.categories[category] {
+			return true
+		}
+	}
+	return false
+}
+
+// SealPost encrypts the post's content if it is in a sensitive category
+func (s *SensitiveContent) SealPost(post *domain.Post) error {
+	if post.ContentEncrypted || !s.IsSensitive(post.Categories) {
+		return nil
+	}
+	sealed, err := s.encManager.Encrypt(post.Content)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt post content: %w", err)
+	}
+	post.Content, post.ContentEncrypted = sealed, true
+	return nil
+}
+
+// OpenPost decrypts the post's content if it was stored encrypted
+func (s *SensitiveContent) OpenPost(post *domain.Post) error {
+	if !post.ContentEncrypted {
+		return nil
+	}
+	content, err := s.encManager.Decrypt(post.Content)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt post content: %w", err)
+	}
+	post.Content, post.ContentEncrypted = content, false
+	return nil
+}
+
+// OpenPosts decrypts the content of every encrypted post
+func (s *SensitiveContent) OpenPosts(posts []*domain.Post) error {
+	for _, post := range posts {
+		if err := s.OpenPost(post); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SealResponse encrypts a response's content if the post it answers is in a
+// sensitive category
+func (s *SensitiveContent) SealResponse(response *domain.SupportResponse, post *domain.Post) error {
+	if response.ContentEncrypted || response.Content == "" || !s.IsSensitive(post.Categories) {
+		return nil
+	}
+	sealed, err := s.encManager.Encrypt(response.Content)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt response content: %w", err)
+	}
+	response.Content, response.ContentEncrypted = sealed, true
+	return nil
+}
+
+// OpenResponses decrypts the content of every encrypted response
+func (s *SensitiveContent) OpenResponses(responses []*domain.SupportResponse) error {
+	for _, response := range responses {
+		if !response.ContentEncrypted {
+			continue
+		}
+		content, err := s.encManager.Decrypt(response.Content)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt response content: %w", err)
+		}
+		response.Content, response.ContentEncrypted = content, false
+	}
+	return nil
+}
+
+// OpenText decrypts content carried by an event when encrypted is set
+func (s *SensitiveContent) OpenText(content string, encrypted bool) (string, error) {
+	if !encrypted {
+		return content, nil
+	}
+	return s.encManager.Decrypt(content)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/encryption"
+)
+
+// TestSensitiveContent tests that only posts in sensitive categories, and
+// responses to them, are encrypted and that they decrypt back
+func TestSensitiveContent(t *testing.T) {
+	encManager, err := encryption.NewManager("0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	sensitive := NewSensitiveContent(encManager, []string{"abuse", "legal"})
+
+	post := &domain.Post{Content: "what happened", Categories: []string{"recovery", "abuse"}}
+	require.NoError(t, sensitive.SealPost(post))
+	assert.True(t, post.ContentEncrypted)
+	assert.NotEqual(t, "what happened", post.Content)
+
+	// Sealing twice must not encrypt the ciphertext
+	sealed := post.Content
+	require.NoError(t, sensitive.SealPost(post))
+	assert.Equal(t, sealed, post.Content)
+
+	response := &domain.SupportResponse{Content: "you're not alone"}
+	require.NoError(t, sensitive.SealResponse(response, post))
+	assert.True(t, response.ContentEncrypted)
+
+	other := &domain.Post{Content: "day 10", Categories: []string{"recovery"}}
+	require.NoError(t, sensitive.SealPost(other))
+	assert.False(t, other.ContentEncrypted)
+	assert.Equal(t, "day 10", other.Content)
+
+	require.NoError(t, sensitive.OpenPosts([]*domain.Post{post, other}))
+	assert.Equal(t, "what happened", post.Content)
+	assert.False(t, post.ContentEncrypted)
+	assert.Equal(t, "day 10", other.Content)
+
+	require.NoError(t, sensitive.OpenResponses([]*domain.SupportResponse{response}))
+	assert.Equal(t, "you're not alone", response.Content)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
+	"github.com/yourorg/anonymous-support/internal/repository"
+	"go.uber.org/zap"
+)
+
+// maxHelpCategories caps how many categories a user can subscribe to
+const maxHelpCategories = 20
+
+// PresenceChecker reports whether a user is currently connected
+type PresenceChecker interface {
+	IsUserOnline(ctx context.Context, userID string) (bool, error)
+}
+
+// SOSService routes SOS posts to a small random set of online users who
+// volunteered to help with the post's categories, instead of everyone
+type SOSService struct {
+	subscriptionRepo repository.CategorySubscriptionRepository
+	presence         PresenceChecker
+	blockService     *BlockService
+	notifier         *NotificationService
+	maxRecipients    int
+	candidatePool    int
+	logger           *zap.Logger
+}
+
+func NewSOSService(
+	subscriptionRepo repository.CategorySubscriptionRepository,
+	presence PresenceChecker,
+	blockService *BlockService,
+	notifier *NotificationService,
+	maxRecipients, candidatePool int,
+	logger *zap.Logger,
+) *SOSService {
+	return &SOSService{
+		subscriptionRepo: subscriptionRepo,
+		presence:         presence,
+		blockService:     blockService,
+		notifier:         notifier,
+		maxRecipients:    maxRecipients,
+		candidatePool:    candidatePool,
+		logger:           logger,
+	}
+}
+
+// GetSubscriptions returns the categories the user has volunteered to help with
+func (s *SOSService) GetSubscriptions(ctx context.Context, userID string) ([]string, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "SOSService.GetSubscriptions")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.subscriptionRepo.ListByUser(ctx, uid)
+}
+
+// UpdateSubscriptions replaces the categories the user has volunteered to help with
+func (s *SOSService) UpdateSubscriptions(ctx context.Context, userID string, categories []string) ([]string, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "SOSService.UpdateSubscriptions")
+	defer span.End()
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, 0, len(categories))
+	seen := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if category == "" || seen[category] {
+			continue
+		}
+		if len(category) > 50 {
+			return nil, fmt.Errorf("category %q is too long", category)
+		}
+		seen[category] = true
+		normalized = append(normalized, category)
+	}
+	if len(normalized) > maxHelpCategories {
+		return nil, fmt.Errorf("at most %d categories can be subscribed to", maxHelpCategories)
+	}
+
+	if err := s.subscriptionRepo.ReplaceForUser(ctx, uid, normalized); err != nil {
+		return nil, err
+	}
+	return s.subscriptionRepo.ListByUser(ctx, uid)
+}
+
+// NotifyHelpers notifies up to maxRecipients randomly chosen online users
+// subscribed to the SOS post's categories, skipping anyone blocked either way
+// with the author. It returns the number of users notified.
+func (s *SOSService) NotifyHelpers(ctx context.Context, post *domain.Post) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "SOSService.NotifyHelpers")
+	defer span.End()
+
+	if post.Type != domain.PostTypeSOS || len(post.Categories) == 0 {
+		return 0, nil
+	}
+
+	authorID, err := uuid.Parse(post.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	candidates, err := s.subscriptionRepo.SampleSubscribers(ctx, post.Categories, authorID, s.candidatePool)
+	if err != nil {
+		return 0, err
+	}
+
+	notified := 0
+	for _, candidate := range candidates {
+		if notified >= s.maxRecipients {
+			break
+		}
+
+		helperID := candidate.String()
+		online, err := s.presence.IsUserOnline(ctx, helperID)
+		if err != nil || !online {
+			continue
+		}
+
+		blocked, err := s.blockService.IsBlockedEitherWay(ctx, post.UserID, helperID)
+		if err != nil || blocked {
+			continue
+		}
+
+		if err := s.notifier.NotifySOS(ctx, helperID, post.ID.Hex(), post.Categories); err != nil {
+			s.logger.Warn("Failed to notify SOS helper", zap.String("user_id", helperID), zap.Error(err))
+			continue
+		}
+		notified++
+	}
+
+	return notified, nil
+}
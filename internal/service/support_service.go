@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/counters"
+	"github.com/yourorg/anonymous-support/internal/pkg/events"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/pkg/validator"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
@@ -14,6 +18,10 @@ type SupportService struct {
 	postRepo     repository.PostRepository
 	userRepo     repository.UserRepository
 	realtimeRepo repository.RealtimeRepository
+	sensitive    *SensitiveContent
+	blockService *BlockService
+	bus          events.EventBus
+	counters     *counters.Buffer
 }
 
 func NewSupportService(
@@ -21,22 +29,49 @@ func NewSupportService(
 	postRepo repository.PostRepository,
 	userRepo repository.UserRepository,
 	realtimeRepo repository.RealtimeRepository,
+	sensitive *SensitiveContent,
+	blockService *BlockService,
+	bus events.EventBus,
+	counterBuffer *counters.Buffer,
 ) *SupportService {
+	counterBuffer.Register(counterPostResponses, postRepo.AddResponseCounts)
+	counterBuffer.Register(counterPostSupports, postRepo.AddSupportCounts)
+
 	return &SupportService{
 		supportRepo:  supportRepo,
 		postRepo:     postRepo,
 		userRepo:     userRepo,
 		realtimeRepo: realtimeRepo,
+		sensitive:    sensitive,
+		blockService: blockService,
+		bus:          bus,
+		counters:     counterBuffer,
 	}
 }
 
 func (s *SupportService) CreateResponse(ctx context.Context, userID, username, postID string, responseType domain.ResponseType, content string, voiceNoteURL *string) (string, int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "SupportService.CreateResponse")
+	defer span.End()
+
 	if responseType == domain.ResponseTypeText {
 		if err := validator.ValidateResponseContent(content); err != nil {
 			return "", 0, err
 		}
 	}
 
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	blocked, err := s.blockService.IsBlockedEitherWay(ctx, userID, post.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+	if blocked {
+		return "", 0, fmt.Errorf("cannot respond to this post")
+	}
+
 	strengthPoints := s.calculateStrengthPoints(responseType, content)
 
 	response := &domain.SupportResponse{
@@ -49,27 +84,60 @@ func (s *SupportService) CreateResponse(ctx context.Context, userID, username, p
 		StrengthPoints: strengthPoints,
 	}
 
+	// Responses to sensitive posts are stored and published encrypted
+	if err := s.sensitive.SealResponse(response, post); err != nil {
+		return "", 0, err
+	}
 	if err := s.supportRepo.CreateResponse(ctx, response); err != nil {
 		return "", 0, err
 	}
 
-	_ = s.postRepo.IncrementResponseCount(ctx, postID)
+	s.counters.Add(counterPostResponses, postID, 1)
 
 	uid, _ := uuid.Parse(userID)
 	_ = s.userRepo.UpdateStrengthPoints(ctx, uid, strengthPoints)
 
-	_ = s.realtimeRepo.PublishNewResponse(ctx, postID, response.ID.Hex())
+	// Realtime fan-out and notifications are event subscribers
+	_ = events.Publish(ctx, s.bus, domain.EventResponseCreated, domain.ResponseCreatedEvent{
+		PostID:           postID,
+		PostAuthorID:     post.UserID,
+		ResponseID:       response.ID.Hex(),
+		UserID:           userID,
+		Username:         username,
+		Type:             responseType,
+		Content:          response.Content,
+		ContentEncrypted: response.ContentEncrypted,
+	})
 
 	return response.ID.Hex(), strengthPoints, nil
 }
 
-func (s *SupportService) GetResponses(ctx context.Context, postID string, limit, offset int) ([]*domain.SupportResponse, error) {
-	return s.supportRepo.GetResponses(ctx, postID, limit, offset)
+// GetResponses returns responses on a post, hiding those from users in the viewer's block set
+func (s *SupportService) GetResponses(ctx context.Context, postID, viewerID string, limit, offset int) ([]*domain.SupportResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "SupportService.GetResponses")
+	defer span.End()
+
+	responses, err := s.supportRepo.GetResponses(ctx, postID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err = s.blockService.FilterResponses(ctx, viewerID, responses)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sensitive.OpenResponses(responses); err != nil {
+		return nil, err
+	}
+	return responses, nil
 }
 
 func (s *SupportService) QuickSupport(ctx context.Context, userID, postID, messageType string) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "SupportService.QuickSupport")
+	defer span.End()
+
 	_ = s.realtimeRepo.AddSupporterToPost(ctx, postID, userID)
-	_ = s.postRepo.IncrementSupportCount(ctx, postID)
+	s.counters.Add(counterPostSupports, postID, 1)
 
 	count, err := s.realtimeRepo.GetSupporterCount(ctx, postID)
 	if err != nil {
@@ -80,6 +148,9 @@ func (s *SupportService) QuickSupport(ctx context.Context, userID, postID, messa
 }
 
 func (s *SupportService) GetSupportStats(ctx context.Context, userID string) (given, received int64, strengthPoints, peopleHelped int, error error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "SupportService.GetSupportStats")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return 0, 0, 0, 0, err
@@ -0,0 +1,4 @@
+package service
+
+// tracerName names the tracer of the spans service methods start
+const tracerName = "github.com/yourorg/anonymous-support/internal/service"
@@ -2,28 +2,44 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourorg/anonymous-support/internal/domain"
+	"github.com/yourorg/anonymous-support/internal/pkg/tracing"
 	"github.com/yourorg/anonymous-support/internal/repository"
 )
 
+// userSummaryTTL bounds how long a renamed user's old name can show in lists
+// served by another instance; this instance invalidates on update
+const userSummaryTTL = 15 * time.Minute
+
 type UserService struct {
 	userRepo      repository.UserRepository
 	analyticsRepo repository.AnalyticsRepository
+	progress      *ProgressService
+	summaryCache  repository.UserSummaryCacheRepository
 }
 
 func NewUserService(
 	userRepo repository.UserRepository,
 	analyticsRepo repository.AnalyticsRepository,
+	progress *ProgressService,
+	summaryCache repository.UserSummaryCacheRepository,
 ) *UserService {
 	return &UserService{
 		userRepo:      userRepo,
 		analyticsRepo: analyticsRepo,
+		progress:      progress,
+		summaryCache:  summaryCache,
 	}
 }
 
 func (s *UserService) GetProfile(ctx context.Context, userID string) (*domain.User, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.GetProfile")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, err
@@ -31,15 +47,103 @@ func (s *UserService) GetProfile(ctx context.Context, userID string) (*domain.Us
 	return s.userRepo.GetByID(ctx, uid)
 }
 
-func (s *UserService) UpdateProfile(ctx context.Context, userID string, username *string, avatarID *int) error {
+// UpdateProfile changes the given profile fields. timezone is the IANA time
+// zone streak days roll over in, kept with the user's progress.
+func (s *UserService) UpdateProfile(ctx context.Context, userID string, username *string, avatarID *int, shareMilestones *bool, timezone *string) error {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.UpdateProfile")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return err
 	}
-	return s.userRepo.UpdateProfile(ctx, uid, username, avatarID)
+	if timezone != nil {
+		if err := s.progress.SetTimezone(ctx, userID, *timezone); err != nil {
+			return err
+		}
+	}
+	if err := s.userRepo.UpdateProfile(ctx, uid, username, avatarID, shareMilestones); err != nil {
+		return err
+	}
+	return s.summaryCache.InvalidateSummary(ctx, uid)
+}
+
+// BatchGetUsers returns the profiles of the given users in the order asked
+// for. Unknown and banned users are left out.
+func (s *UserService) BatchGetUsers(ctx context.Context, userIDs []string) ([]*domain.User, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.BatchGetUsers")
+	defer span.End()
+
+	ids, err := batchIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	uids := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		if uids[i], err = uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid user ID %q", id)
+		}
+	}
+
+	found, err := s.userRepo.GetByIDs(ctx, uids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*domain.User, len(found))
+	for _, user := range found {
+		byID[user.ID] = user
+	}
+	users := make([]*domain.User, 0, len(found))
+	for _, uid := range uids {
+		if user, ok := byID[uid]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// GetUserSummaries returns the public identities of the given users, from the
+// cache where possible and otherwise with one batched query. Unknown and
+// banned users are absent from the result.
+func (s *UserService) GetUserSummaries(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*domain.UserSummary, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.GetUserSummaries")
+	defer span.End()
+
+	// The cache only saves queries, so a cache failure falls back to the database
+	summaries, err := s.summaryCache.GetSummaries(ctx, userIDs)
+	if err != nil {
+		summaries = map[uuid.UUID]*domain.UserSummary{}
+	}
+
+	missing := make([]uuid.UUID, 0, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := summaries[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return summaries, nil
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := make([]*domain.UserSummary, len(users))
+	for i, user := range users {
+		fetched[i] = user.Summary()
+		summaries[user.ID] = fetched[i]
+	}
+	_ = s.summaryCache.SetSummaries(ctx, fetched, userSummaryTTL)
+
+	return summaries, nil
 }
 
 func (s *UserService) GetStreak(ctx context.Context, userID string) (*domain.UserTracker, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.GetStreak")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, err
@@ -47,12 +151,17 @@ func (s *UserService) GetStreak(ctx context.Context, userID string) (*domain.Use
 	return s.analyticsRepo.GetUserTracker(ctx, uid)
 }
 
-func (s *UserService) UpdateStreak(ctx context.Context, userID string, hadRelapse bool) (int, error) {
+// UpdateStreak records a check-in and returns the new streak. moodScore is
+// optional; pass 0 to check in without one.
+func (s *UserService) UpdateStreak(ctx context.Context, userID string, hadRelapse bool, moodScore int) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.UpdateStreak")
+	defer span.End()
+
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return 0, err
 	}
-	if err := s.analyticsRepo.UpdateStreak(ctx, uid, hadRelapse); err != nil {
+	if err := s.progress.RecordCheckIn(ctx, userID, hadRelapse, moodScore, nil); err != nil {
 		return 0, err
 	}
 	tracker, err := s.analyticsRepo.GetUserTracker(ctx, uid)
@@ -61,3 +170,73 @@ func (s *UserService) UpdateStreak(ctx context.Context, userID string, hadRelaps
 	}
 	return tracker.StreakDays, nil
 }
+
+// RecordRelapse logs a relapse and returns the streak it ended. timezone is the
+// user's IANA time zone; empty means UTC.
+func (s *UserService) RecordRelapse(ctx context.Context, userID string, trigger domain.RelapseTrigger, occurredAt time.Time, timezone, note string) (int, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.RecordRelapse")
+	defer span.End()
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timezone %q", timezone)
+	}
+	return s.progress.RecordRelapse(ctx, userID, trigger, occurredAt, loc, note)
+}
+
+// GetStreakFreezes returns the user's streak freezes for the current month
+func (s *UserService) GetStreakFreezes(ctx context.Context, userID string) (*StreakFreezeStatus, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.GetStreakFreezes")
+	defer span.End()
+
+	return s.progress.GetStreakFreezes(ctx, userID)
+}
+
+// UseStreakFreeze spends one of the user's streak freezes on today
+func (s *UserService) UseStreakFreeze(ctx context.Context, userID string) (*StreakFreezeStatus, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.UseStreakFreeze")
+	defer span.End()
+
+	return s.progress.UseStreakFreeze(ctx, userID)
+}
+
+// SetSavingsBaseline records the user's former daily spend and time on the
+// tracked behavior and returns their savings so far
+func (s *UserService) SetSavingsBaseline(ctx context.Context, userID string, dailySpendCents int64, currency string, dailyMinutes int) (*Savings, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.SetSavingsBaseline")
+	defer span.End()
+
+	return s.progress.SetSavingsBaseline(ctx, userID, dailySpendCents, currency, dailyMinutes)
+}
+
+// GetMoodHistory returns the user's mood entries for the last days days along
+// with their trend over each dashboard window
+func (s *UserService) GetMoodHistory(ctx context.Context, userID string, days int) ([]*domain.MoodEntry, []MoodTrend, error) {
+	ctx, span := tracing.StartSpan(ctx, tracerName, "UserService.GetMoodHistory")
+	defer span.End()
+
+	if days <= 0 || days > maxMoodHistoryDays {
+		return nil, nil, fmt.Errorf("days must be between 1 and %d", maxMoodHistoryDays)
+	}
+
+	// Trends compare each window with the one before it, so read at least two of the longest
+	entries, err := s.progress.GetMoodHistory(ctx, userID, max(days, 2*MoodTrendWindows[len(MoodTrendWindows)-1]))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	trends := make([]MoodTrend, 0, len(MoodTrendWindows))
+	for _, window := range MoodTrendWindows {
+		trends = append(trends, CalculateMoodTrend(entries, now, window))
+	}
+
+	since := domain.ProgressDay(now).AddDate(0, 0, -days+1)
+	history := []*domain.MoodEntry{}
+	for _, entry := range entries {
+		if !entry.Day.Before(since) {
+			history = append(history, entry)
+		}
+	}
+	return history, trends, nil
+}
@@ -0,0 +1,184 @@
+// Package factory builds domain records for tests. Each builder returns a
+// valid record with fresh IDs and unique names, then applies overrides in
+// order, so a test only spells out the fields it cares about:
+//
+//	author := factory.User(factory.WithRole(domain.RoleModerator))
+//	post := factory.Post(factory.PostBy(author), func(p *domain.Post) {
+//		p.Type = domain.PostTypeSOS
+//	})
+package factory
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourorg/anonymous-support/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sequence numbers records so names stay unique across a test run
+var sequence atomic.Int64
+
+func next() int64 {
+	return sequence.Add(1)
+}
+
+// User returns an anonymous member with a unique username
+func User(overrides ...func(*domain.User)) *domain.User {
+	now := time.Now().UTC()
+	user := &domain.User{
+		ID:           uuid.New(),
+		Username:     fmt.Sprintf("user_%d", next()),
+		AvatarID:     1,
+		Role:         domain.RoleUser,
+		IsAnonymous:  true,
+		CreatedAt:    now,
+		LastActiveAt: now,
+	}
+	for _, override := range overrides {
+		override(user)
+	}
+	return user
+}
+
+// WithRole sets a user's platform role
+func WithRole(role domain.Role) func(*domain.User) {
+	return func(u *domain.User) { u.Role = role }
+}
+
+// WithEmail makes the user a registered, non-anonymous account
+func WithEmail(email string) func(*domain.User) {
+	return func(u *domain.User) {
+		u.Email = &email
+		u.IsAnonymous = false
+	}
+}
+
+// Post returns a visible public check-in in the general category
+func Post(overrides ...func(*domain.Post)) *domain.Post {
+	now := time.Now().UTC()
+	expiresAt := now.Add(30 * 24 * time.Hour)
+	n := next()
+	post := &domain.Post{
+		ID:              primitive.NewObjectID(),
+		UserID:          uuid.NewString(),
+		Username:        fmt.Sprintf("user_%d", n),
+		Type:            domain.PostTypeCheckIn,
+		Content:         fmt.Sprintf("Check-in %d: one more day.", n),
+		Categories:      []string{"general"},
+		UrgencyLevel:    1,
+		Context:         domain.PostContext{Tags: []string{}},
+		Visibility:      "public",
+		CreatedAt:       now,
+		ExpiresAt:       &expiresAt,
+		ModerationState: domain.ModerationStateVisible,
+	}
+	for _, override := range overrides {
+		override(post)
+	}
+	return post
+}
+
+// PostBy makes the user the post's author
+func PostBy(user *domain.User) func(*domain.Post) {
+	return func(p *domain.Post) {
+		p.UserID = user.ID.String()
+		p.Username = user.Username
+	}
+}
+
+// InCircle posts to the circle, visible only to its members
+func InCircle(circle *domain.Circle) func(*domain.Post) {
+	return func(p *domain.Post) {
+		circleID := circle.ID.String()
+		p.CircleID = &circleID
+		p.Visibility = "circle"
+	}
+}
+
+// Circle returns a public general circle with room for 100 members
+func Circle(overrides ...func(*domain.Circle)) *domain.Circle {
+	circle := &domain.Circle{
+		ID:          uuid.New(),
+		Name:        fmt.Sprintf("Circle %d", next()),
+		Description: "Daily accountability and support",
+		Category:    "general",
+		MaxMembers:  100,
+		CreatedBy:   uuid.New(),
+		CreatedAt:   time.Now().UTC(),
+	}
+	for _, override := range overrides {
+		override(circle)
+	}
+	return circle
+}
+
+// CreatedBy makes the user the circle's creator
+func CreatedBy(user *domain.User) func(*domain.Circle) {
+	return func(c *domain.Circle) { c.CreatedBy = user.ID }
+}
+
+// SupportResponse returns a quick response worth one strength point
+func SupportResponse(overrides ...func(*domain.SupportResponse)) *domain.SupportResponse {
+	response := &domain.SupportResponse{
+		ID:             primitive.NewObjectID(),
+		PostID:         primitive.NewObjectID().Hex(),
+		UserID:         uuid.NewString(),
+		Username:       fmt.Sprintf("user_%d", next()),
+		Type:           domain.ResponseTypeQuick,
+		Content:        "You've got this",
+		CreatedAt:      time.Now().UTC(),
+		StrengthPoints: 1,
+	}
+	for _, override := range overrides {
+		override(response)
+	}
+	return response
+}
+
+// ResponseTo makes the response answer the post
+func ResponseTo(post *domain.Post) func(*domain.SupportResponse) {
+	return func(r *domain.SupportResponse) { r.PostID = post.ID.Hex() }
+}
+
+// ResponseBy makes the user the response's author
+func ResponseBy(user *domain.User) func(*domain.SupportResponse) {
+	return func(r *domain.SupportResponse) {
+		r.UserID = user.ID.String()
+		r.Username = user.Username
+	}
+}
+
+// UserTracker returns the tracker of a new user with no history
+func UserTracker(overrides ...func(*domain.UserTracker)) *domain.UserTracker {
+	tracker := &domain.UserTracker{
+		ID:                   primitive.NewObjectID(),
+		UserID:               uuid.NewString(),
+		VulnerabilityPattern: map[string]int{},
+		Categories:           []string{"general"},
+		Goals:                []domain.Goal{},
+		Milestones:           []domain.Milestone{},
+		UpdatedAt:            time.Now().UTC(),
+	}
+	for _, override := range overrides {
+		override(tracker)
+	}
+	return tracker
+}
+
+// TrackerFor makes the tracker the user's
+func TrackerFor(user *domain.User) func(*domain.UserTracker) {
+	return func(t *domain.UserTracker) { t.UserID = user.ID.String() }
+}
+
+// WithStreak sets the current streak, raising the longest streak and days
+// clean to match
+func WithStreak(days int) func(*domain.UserTracker) {
+	return func(t *domain.UserTracker) {
+		t.StreakDays = days
+		t.LongestStreak = max(t.LongestStreak, days)
+		t.TotalDaysClean = max(t.TotalDaysClean, days)
+	}
+}
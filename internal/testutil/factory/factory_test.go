@@ -0,0 +1,50 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+)
+
+func TestUsersAreUnique(t *testing.T) {
+	a, b := User(), User()
+	if a.ID == b.ID || a.Username == b.Username {
+		t.Fatalf("two users share an ID or username: %+v, %+v", a, b)
+	}
+}
+
+func TestOverridesApplyInOrder(t *testing.T) {
+	user := User(WithRole(domain.RoleModerator), WithEmail("a@example.com"), func(u *domain.User) {
+		u.Role = domain.RoleAdmin
+	})
+	if user.Role != domain.RoleAdmin {
+		t.Errorf("role = %s, want the later override's admin", user.Role)
+	}
+	if user.Email == nil || *user.Email != "a@example.com" || user.IsAnonymous {
+		t.Errorf("WithEmail didn't make a registered account: %+v", user)
+	}
+}
+
+func TestRelations(t *testing.T) {
+	author := User()
+	circle := Circle(CreatedBy(author))
+	post := Post(PostBy(author), InCircle(circle))
+	response := SupportResponse(ResponseTo(post), ResponseBy(User()))
+	tracker := UserTracker(TrackerFor(author), WithStreak(12))
+
+	if circle.CreatedBy != author.ID {
+		t.Error("circle isn't created by the author")
+	}
+	if post.UserID != author.ID.String() || post.Username != author.Username {
+		t.Error("post isn't by the author")
+	}
+	if post.CircleID == nil || *post.CircleID != circle.ID.String() || post.Visibility != "circle" {
+		t.Error("post isn't in the circle")
+	}
+	if response.PostID != post.ID.Hex() {
+		t.Error("response doesn't answer the post")
+	}
+	if tracker.UserID != author.ID.String() || tracker.StreakDays != 12 || tracker.LongestStreak != 12 {
+		t.Errorf("tracker = %+v", tracker)
+	}
+}
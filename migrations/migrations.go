@@ -0,0 +1,22 @@
+// Package migrations embeds the SQL migration files so binaries can apply
+// them without the source tree.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+// Postgres holds the PostgreSQL migrations, NNN_name.up.sql and NNN_name.down.sql
+var Postgres fs.FS = mustSub(postgresFiles, "postgres")
+
+func mustSub(files fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(files, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,5 @@
+-- Remove auto-moderation report source
+DROP INDEX IF EXISTS idx_reports_source;
+DELETE FROM content_reports WHERE reporter_id IS NULL;
+ALTER TABLE content_reports DROP COLUMN IF EXISTS source;
+ALTER TABLE content_reports ALTER COLUMN reporter_id SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Allow moderation cases opened by the auto-moderation engine (no human reporter)
+ALTER TABLE content_reports ALTER COLUMN reporter_id DROP NOT NULL;
+ALTER TABLE content_reports ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'user';
+
+CREATE INDEX IF NOT EXISTS idx_reports_source ON content_reports(source);
+
+COMMENT ON COLUMN content_reports.source IS 'Who opened the report: user or auto (auto-moderation engine)';
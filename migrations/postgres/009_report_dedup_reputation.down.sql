@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS reporter_reputation;
+DROP TABLE IF EXISTS report_reporters;
+ALTER TABLE content_reports DROP COLUMN IF EXISTS report_score;
+ALTER TABLE content_reports DROP COLUMN IF EXISTS reporter_count;
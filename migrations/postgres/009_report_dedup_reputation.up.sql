@@ -0,0 +1,28 @@
+-- Merge duplicate reports into a single case
+ALTER TABLE content_reports ADD COLUMN IF NOT EXISTS reporter_count INT NOT NULL DEFAULT 0;
+ALTER TABLE content_reports ADD COLUMN IF NOT EXISTS report_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE TABLE report_reporters (
+    report_id UUID NOT NULL REFERENCES content_reports(id) ON DELETE CASCADE,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    weight DOUBLE PRECISION NOT NULL DEFAULT 1,
+    reason VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (report_id, reporter_id)
+);
+
+CREATE INDEX idx_report_reporters_reporter ON report_reporters(reporter_id);
+
+-- Backfill existing user reports as single-reporter cases
+INSERT INTO report_reporters (report_id, reporter_id, reason, created_at)
+SELECT id, reporter_id, reason, created_at FROM content_reports WHERE reporter_id IS NOT NULL;
+UPDATE content_reports SET reporter_count = 1, report_score = 1 WHERE reporter_id IS NOT NULL;
+
+-- Track how often each reporter's reports are upheld
+CREATE TABLE reporter_reputation (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reports_submitted INT NOT NULL DEFAULT 0,
+    reports_upheld INT NOT NULL DEFAULT 0,
+    reports_rejected INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS moderator_notes;
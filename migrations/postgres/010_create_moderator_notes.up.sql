@@ -0,0 +1,11 @@
+-- Internal moderator notes on report cases (never shown to users)
+CREATE TABLE moderator_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    report_id UUID NOT NULL REFERENCES content_reports(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES moderator_notes(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_moderator_notes_report ON moderator_notes(report_id, created_at);
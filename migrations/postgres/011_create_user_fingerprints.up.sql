@@ -0,0 +1,13 @@
+-- Hashed IP and device fingerprints used for ban evasion detection
+CREATE TABLE user_fingerprints (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    hash VARCHAR(64) NOT NULL,
+    first_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, kind, hash)
+);
+
+CREATE INDEX idx_user_fingerprints_hash ON user_fingerprints(hash, last_seen_at DESC);
+
+COMMENT ON COLUMN user_fingerprints.hash IS 'HMAC-SHA256 of the IP address or device fingerprint; raw values are never stored';
@@ -0,0 +1,4 @@
+-- Remove report severity
+DROP INDEX IF EXISTS idx_reports_status_severity;
+ALTER TABLE content_reports DROP CONSTRAINT IF EXISTS chk_reports_severity;
+ALTER TABLE content_reports DROP COLUMN IF EXISTS severity;
@@ -0,0 +1,13 @@
+-- Severity drives moderation SLA targets and queue ordering
+ALTER TABLE content_reports ADD COLUMN IF NOT EXISTS severity VARCHAR(20) NOT NULL DEFAULT 'medium';
+ALTER TABLE content_reports ADD CONSTRAINT chk_reports_severity
+    CHECK (severity IN ('low', 'medium', 'high', 'critical'));
+
+-- Backfill from the report reason
+UPDATE content_reports SET severity = 'critical' WHERE reason IN ('self_harm', 'harmful_content', 'threat');
+UPDATE content_reports SET severity = 'high' WHERE reason IN ('harassment', 'hate_speech', 'ban_evasion', 'auto_moderation');
+UPDATE content_reports SET severity = 'low' WHERE reason = 'spam';
+
+CREATE INDEX IF NOT EXISTS idx_reports_status_severity ON content_reports(status, severity, created_at);
+
+COMMENT ON COLUMN content_reports.severity IS 'Report severity: low, medium, high or critical';
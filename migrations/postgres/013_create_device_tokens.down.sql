@@ -0,0 +1,2 @@
+-- Remove push notification device tokens
+DROP TABLE IF EXISTS device_tokens;
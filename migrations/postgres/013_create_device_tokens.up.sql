@@ -0,0 +1,15 @@
+-- Push notification device tokens (FCM and APNS)
+CREATE TABLE device_tokens (
+    token VARCHAR(512) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform VARCHAR(20) NOT NULL CHECK (platform IN ('android', 'ios', 'web')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    invalidated_at TIMESTAMP,
+    invalid_reason VARCHAR(100)
+);
+
+CREATE INDEX idx_device_tokens_user ON device_tokens(user_id) WHERE invalidated_at IS NULL;
+CREATE INDEX idx_device_tokens_invalidated ON device_tokens(invalidated_at) WHERE invalidated_at IS NOT NULL;
+
+COMMENT ON COLUMN device_tokens.invalidated_at IS 'Set when the push provider reports the token as dead; rows are removed by cleanup';
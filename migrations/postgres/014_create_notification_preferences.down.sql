@@ -0,0 +1,2 @@
+-- Remove notification preferences
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,13 @@
+-- Per-user notification preferences
+CREATE TABLE notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    channels JSONB NOT NULL DEFAULT '{}',
+    quiet_hours_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    quiet_hours_start VARCHAR(5) NOT NULL DEFAULT '22:00',
+    quiet_hours_end VARCHAR(5) NOT NULL DEFAULT '07:00',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN notification_preferences.channels IS 'Delivery channel per event type: push, in_app or off. Missing types use the defaults';
+COMMENT ON COLUMN notification_preferences.quiet_hours_start IS 'Local time (HH:MM) in the user timezone when push is suppressed';
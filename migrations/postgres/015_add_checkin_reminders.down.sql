@@ -0,0 +1,7 @@
+-- Remove daily check-in reminder settings
+DROP INDEX IF EXISTS idx_notification_preferences_checkin_reminder;
+
+ALTER TABLE notification_preferences
+    DROP COLUMN IF EXISTS checkin_reminder_last_sent,
+    DROP COLUMN IF EXISTS checkin_reminder_time,
+    DROP COLUMN IF EXISTS checkin_reminder_enabled;
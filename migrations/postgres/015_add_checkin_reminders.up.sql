@@ -0,0 +1,12 @@
+-- Daily check-in reminder settings
+ALTER TABLE notification_preferences
+    ADD COLUMN checkin_reminder_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN checkin_reminder_time VARCHAR(5) NOT NULL DEFAULT '20:00',
+    ADD COLUMN checkin_reminder_last_sent DATE;
+
+CREATE INDEX idx_notification_preferences_checkin_reminder
+    ON notification_preferences (checkin_reminder_time)
+    WHERE checkin_reminder_enabled;
+
+COMMENT ON COLUMN notification_preferences.checkin_reminder_time IS 'Local time (HH:MM) in the user timezone to send the daily check-in reminder';
+COMMENT ON COLUMN notification_preferences.checkin_reminder_last_sent IS 'Local date the last reminder was sent, so each user gets at most one per day';
@@ -0,0 +1,2 @@
+-- Remove milestone sharing opt-in
+ALTER TABLE users DROP COLUMN IF EXISTS share_milestones;
@@ -0,0 +1,2 @@
+-- Opt-in to auto-post a Victory in the user's circles when they reach a streak milestone
+ALTER TABLE users ADD COLUMN share_milestones BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Remove category subscriptions
+DROP TABLE IF EXISTS category_subscriptions;
@@ -0,0 +1,9 @@
+-- Categories a user has volunteered to help with; used to target SOS notifications
+CREATE TABLE category_subscriptions (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, category)
+);
+
+CREATE INDEX idx_category_subscriptions_category ON category_subscriptions(category);
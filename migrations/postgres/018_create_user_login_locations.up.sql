@@ -0,0 +1,15 @@
+-- Countries each user has logged in from, used to flag logins from new
+-- countries and impossible travel
+CREATE TABLE user_login_locations (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    country CHAR(2) NOT NULL,
+    latitude NUMERIC(4, 1),
+    longitude NUMERIC(4, 1),
+    first_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, country)
+);
+
+CREATE INDEX idx_user_login_locations_last_seen ON user_login_locations(user_id, last_seen_at DESC);
+
+COMMENT ON COLUMN user_login_locations.latitude IS 'Rounded to one decimal (about 11 km) so precise locations are never stored';
@@ -0,0 +1,3 @@
+-- Role carried in access tokens and checked by RBAC; moderators and admins are promoted by hand
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'moderator', 'admin'));
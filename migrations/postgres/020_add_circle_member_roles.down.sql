@@ -0,0 +1,3 @@
+-- Remove circle-scoped roles
+ALTER TABLE circle_memberships DROP CONSTRAINT IF EXISTS circle_memberships_role_check;
+UPDATE circle_memberships SET role = 'member';
@@ -0,0 +1,8 @@
+-- Circle-scoped roles: creators own their circles, owners appoint moderators
+UPDATE circle_memberships m
+SET role = 'owner'
+FROM circles c
+WHERE m.circle_id = c.id AND m.user_id = c.created_by;
+
+ALTER TABLE circle_memberships ADD CONSTRAINT circle_memberships_role_check
+    CHECK (role IN ('member', 'moderator', 'owner'));
@@ -0,0 +1,2 @@
+-- Remove feature flags
+DROP TABLE IF EXISTS feature_flags;
@@ -0,0 +1,7 @@
+-- Feature flags toggled by admins; a flag with no row is off
+CREATE TABLE feature_flags (
+    key VARCHAR(64) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
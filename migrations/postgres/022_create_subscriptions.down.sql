@@ -0,0 +1,2 @@
+-- Remove subscriptions
+DROP TABLE IF EXISTS subscriptions;
@@ -0,0 +1,13 @@
+-- A user's plan, granted by an admin or synced from Stripe. users.is_premium
+-- mirrors whether it's a paid plan; users without a row are on the free plan.
+CREATE TABLE subscriptions (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    plan VARCHAR(32) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    source VARCHAR(32) NOT NULL,
+    external_id VARCHAR(255) UNIQUE,
+    external_customer_id VARCHAR(255),
+    current_period_end TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
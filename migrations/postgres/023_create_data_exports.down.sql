@@ -0,0 +1,2 @@
+-- Remove data exports
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,18 @@
+-- Admin exports of moderation and analytics data to object storage
+CREATE TABLE data_exports (
+    id UUID PRIMARY KEY,
+    dataset VARCHAR(32) NOT NULL,
+    format VARCHAR(16) NOT NULL,
+    since TIMESTAMP NOT NULL,
+    until TIMESTAMP NOT NULL,
+    columns TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    object_key TEXT NOT NULL DEFAULT '',
+    row_count BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX idx_data_exports_created_at ON data_exports(created_at DESC);
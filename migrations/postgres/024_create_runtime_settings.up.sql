@@ -0,0 +1,8 @@
+-- Admin overrides of tunables such as rate limits; a setting with no row
+-- uses its configured default
+CREATE TABLE runtime_settings (
+    key VARCHAR(64) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+-- Remove circle invites
+DROP TABLE IF EXISTS circle_invites;
@@ -0,0 +1,14 @@
+-- Codes that let users join private circles
+CREATE TABLE circle_invites (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    circle_id UUID NOT NULL REFERENCES circles(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    max_uses INT NOT NULL DEFAULT 0, -- 0 for unlimited
+    used_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    is_active BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE INDEX idx_circle_invites_circle ON circle_invites(circle_id, created_at DESC);
@@ -0,0 +1,2 @@
+-- Allow several pending cases per piece of content again
+DROP INDEX IF EXISTS idx_content_reports_one_pending;
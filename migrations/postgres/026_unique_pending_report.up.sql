@@ -0,0 +1,34 @@
+-- Allow one pending case per piece of content, so concurrent reports merge
+-- into the same case. Existing duplicates are merged into the oldest case.
+CREATE TEMP TABLE duplicate_reports AS
+SELECT id, keep_id FROM (
+    SELECT id, FIRST_VALUE(id) OVER (PARTITION BY content_type, content_id ORDER BY created_at, id) AS keep_id
+    FROM content_reports
+    WHERE status = 'pending'
+) ranked
+WHERE id <> keep_id;
+
+INSERT INTO report_reporters (report_id, reporter_id, weight, reason, created_at)
+SELECT d.keep_id, rr.reporter_id, rr.weight, rr.reason, rr.created_at
+FROM report_reporters rr
+JOIN duplicate_reports d ON rr.report_id = d.id
+ON CONFLICT (report_id, reporter_id) DO NOTHING;
+
+UPDATE moderator_notes n SET report_id = d.keep_id
+FROM duplicate_reports d
+WHERE n.report_id = d.id;
+
+DELETE FROM content_reports c USING duplicate_reports d WHERE c.id = d.id;
+
+UPDATE content_reports c
+SET reporter_count = totals.reporter_count, report_score = totals.report_score
+FROM (
+    SELECT report_id, COUNT(*) AS reporter_count, SUM(weight) AS report_score
+    FROM report_reporters
+    GROUP BY report_id
+) totals
+WHERE c.id = totals.report_id AND c.id IN (SELECT keep_id FROM duplicate_reports);
+
+DROP TABLE duplicate_reports;
+
+CREATE UNIQUE INDEX idx_content_reports_one_pending ON content_reports(content_type, content_id) WHERE status = 'pending';
@@ -36,7 +36,7 @@ echo "✓ Services ready"
 echo
 
 echo "6. Running database migrations..."
-make migrate-up
+task migrate-up
 echo
 
 echo "7. Initializing MongoDB..."
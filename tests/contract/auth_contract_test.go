@@ -137,12 +137,11 @@ func TestAuthService_Logout_Contract(t *testing.T) {
 	require.NoError(t, err)
 
 	// Logout
-	logoutReq := &authv1.LogoutRequest{}
-
-	// Add access token to context (normally done by middleware)
-	ctx = context.WithValue(ctx, "access_token", registerResp.Msg.AccessToken)
+	logoutReq := &authv1.LogoutRequest{
+		UserId: registerResp.Msg.UserId,
+	}
 
-	resp, err := client.Logout(ctx, connect.NewRequest(logoutReq))
+	resp, err := client.Logout(ctx, withToken(connect.NewRequest(logoutReq), registerResp.Msg.AccessToken))
 	require.NoError(t, err)
 
 	// Contract assertions - logout returns empty response
@@ -199,8 +198,5 @@ func TestAuthService_Login_InvalidCredentials_Contract(t *testing.T) {
 
 // Helper function to setup auth client
 func setupAuthClient(t *testing.T) authv1connect.AuthServiceClient {
-	// In real tests, this would connect to a test server
-	// For now, return nil as a placeholder
-	t.Skip("Test server setup required")
-	return nil
+	return newTestServer(t).AuthClient()
 }
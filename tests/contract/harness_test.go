@@ -0,0 +1,91 @@
+package contract_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourorg/anonymous-support/gen/auth/v1/authv1connect"
+	"github.com/yourorg/anonymous-support/internal/handler/rpc"
+	"github.com/yourorg/anonymous-support/internal/middleware"
+	"github.com/yourorg/anonymous-support/internal/pkg/encryption"
+	"github.com/yourorg/anonymous-support/internal/pkg/fingerprint"
+	"github.com/yourorg/anonymous-support/internal/pkg/jwt"
+	"github.com/yourorg/anonymous-support/internal/repository/memory"
+	"github.com/yourorg/anonymous-support/internal/service"
+)
+
+// testServer runs the API's RPC handlers in process over HTTP, with the real
+// services and interceptors on in-memory repositories, so contract tests run
+// anywhere go test does. Each test gets its own server and data.
+type testServer struct {
+	*httptest.Server
+	Users    *memory.UserRepository
+	Sessions *memory.SessionRepository
+}
+
+// newTestServer starts a server that is closed when the test ends
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	logger := zap.NewNop()
+	jwtManager := jwt.NewJWTManager("contract-test-secret-at-least-32-bytes", 15*time.Minute, 7*24*time.Hour)
+	encManager, err := encryption.NewKeyring(encryption.KeyringConfig{
+		Primary: encryption.Key{ID: "test", Secret: "0123456789abcdef0123456789abcdef"},
+	})
+	require.NoError(t, err)
+
+	s := &testServer{
+		Users:    memory.NewUserRepository(),
+		Sessions: memory.NewSessionRepository(),
+	}
+
+	// Requests carry no client fingerprint, so ban evasion and login risk
+	// checks never reach their repositories
+	hasher := fingerprint.NewHasher("contract-test")
+	authService := service.NewAuthService(
+		s.Users,
+		s.Sessions,
+		jwtManager,
+		encManager,
+		nil,
+		service.NewBanEvasionService(nil, nil, hasher, 7*24*time.Hour),
+		service.NewLoginRiskService(nil, nil, hasher, 900, 0, time.Hour),
+		nil,
+	)
+
+	interceptors := connect.WithInterceptors(
+		middleware.NewRPCErrorInterceptor(nil, logger),
+		middleware.NewRPCRecoveryInterceptor(nil, logger),
+		middleware.NewRPCAuthInterceptor(jwtManager,
+			authv1connect.AuthServiceRegisterAnonymousProcedure,
+			authv1connect.AuthServiceRegisterWithEmailProcedure,
+			authv1connect.AuthServiceLoginProcedure,
+			authv1connect.AuthServiceVerifyLoginChallengeProcedure,
+			authv1connect.AuthServiceRefreshTokenProcedure,
+		),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(authv1connect.NewAuthServiceHandler(rpc.NewAuthHandler(authService), interceptors))
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// AuthClient returns a client for the server's auth service
+func (s *testServer) AuthClient() authv1connect.AuthServiceClient {
+	return authv1connect.NewAuthServiceClient(s.Client(), s.URL)
+}
+
+// withToken sends the request as the user the access token was issued to
+func withToken[T any](req *connect.Request[T], accessToken string) *connect.Request[T] {
+	req.Header().Set("Authorization", "Bearer "+accessToken)
+	return req
+}
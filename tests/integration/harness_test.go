@@ -0,0 +1,169 @@
+//go:build integration
+
+// Package integration_test runs two API replicas in one process against a
+// shared Redis to verify the guarantees horizontal scaling depends on.
+//
+// Start Redis (docker compose up -d redis) and run:
+//
+//	go test -v -tags=integration ./tests/integration/...
+//
+// INTEGRATION_REDIS_ADDR overrides the default localhost:6379.
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/yourorg/anonymous-support/internal/domain"
+	wsHandler "github.com/yourorg/anonymous-support/internal/handler/websocket"
+	"github.com/yourorg/anonymous-support/internal/pkg/jwt"
+	"github.com/yourorg/anonymous-support/internal/pkg/ratelimit"
+	redisrepo "github.com/yourorg/anonymous-support/internal/repository/redis"
+	"github.com/yourorg/anonymous-support/internal/testutil/factory"
+)
+
+// jwtSecret is shared by both replicas, as JWT_SECRET is in a deployment
+const jwtSecret = "integration-test-secret-at-least-32-bytes"
+
+// replica is the Redis-backed slice of an API instance: its WebSocket hub
+// and relay, rate limiter and session store, each with its own connection
+type replica struct {
+	hub      *wsHandler.Hub
+	limiter  *ratelimit.Limiter
+	sessions *redisrepo.SessionRepository
+	server   *httptest.Server
+}
+
+func redisAddr() string {
+	if addr := os.Getenv("INTEGRATION_REDIS_ADDR"); addr != "" {
+		return addr
+	}
+	return "localhost:6379"
+}
+
+// startReplica wires a replica the way app.New does and serves /ws on a test server
+func startReplica(t *testing.T, jwtManager *jwt.JWTManager) *replica {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: redisAddr()})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		_ = client.Close()
+		t.Skipf("Integration tests require Redis at %s: %v", redisAddr(), err)
+	}
+
+	hub := wsHandler.NewHub(jwtManager, nil, nil, nil, redisrepo.NewRealtimeRepository(client),
+		5*time.Second, wsHandler.BackpressureConfig{}, zap.NewNop())
+	upgrader := wsHandler.NewUpgrader(wsHandler.UpgraderConfig{ReadBufferSize: 1024, WriteBufferSize: 1024})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run()
+	go hub.RunRelay(ctx)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		hub.ServeConn(conn, wsHandler.RequestToken(r), wsHandler.NegotiateVersion(r))
+	}))
+
+	t.Cleanup(func() {
+		server.Close()
+		cancel()
+		hub.Stop()
+		_ = client.Close()
+	})
+
+	return &replica{
+		hub:      hub,
+		limiter:  ratelimit.NewLimiter(client),
+		sessions: redisrepo.NewSessionRepository(client),
+		server:   server,
+	}
+}
+
+// startReplicas starts two replicas sharing Redis and a JWT secret
+func startReplicas(t *testing.T) (*replica, *replica, *jwt.JWTManager) {
+	t.Helper()
+	jwtManager := jwt.NewJWTManager(jwtSecret, 15*time.Minute, time.Hour)
+	return startReplica(t, jwtManager), startReplica(t, jwtManager), jwtManager
+}
+
+// newUser returns a fresh user and an access token for it
+func newUser(t *testing.T, jwtManager *jwt.JWTManager) (*domain.User, string) {
+	t.Helper()
+	user := factory.User()
+	token, err := jwtManager.GenerateAccessToken(user)
+	require.NoError(t, err)
+	return user, token
+}
+
+// connect opens a WebSocket to the replica as the token's user and returns
+// the messages it receives
+func (r *replica) connect(t *testing.T, token string) <-chan wsHandler.WSMessage {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(r.server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Authorization": {"Bearer " + token}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	messages := make(chan wsHandler.WSMessage, 64)
+	go func() {
+		defer close(messages)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var msg wsHandler.WSMessage
+			if json.Unmarshal(data, &msg) == nil {
+				messages <- msg
+			}
+		}
+	}()
+	return messages
+}
+
+// waitOnline waits until the replica has registered the user's connection
+func (r *replica) waitOnline(t *testing.T, userID string) {
+	t.Helper()
+	require.Eventually(t, func() bool { return r.hub.IsUserOnline(userID) },
+		5*time.Second, 20*time.Millisecond, "user never came online")
+}
+
+// awaitDelivery repeats send until a message of msgType arrives, since the
+// relays' Redis subscriptions are established asynchronously
+func awaitDelivery(t *testing.T, messages <-chan wsHandler.WSMessage, msgType wsHandler.WSMessageType, send func()) wsHandler.WSMessage {
+	t.Helper()
+
+	deadline := time.After(10 * time.Second)
+	retry := time.NewTicker(250 * time.Millisecond)
+	defer retry.Stop()
+
+	send()
+	for {
+		select {
+		case msg, ok := <-messages:
+			require.True(t, ok, "connection closed before delivery")
+			if msg.Type == msgType {
+				return msg
+			}
+		case <-retry.C:
+			send()
+		case <-deadline:
+			t.Fatalf("no %s message delivered", msgType)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	wsHandler "github.com/yourorg/anonymous-support/internal/handler/websocket"
+	"github.com/yourorg/anonymous-support/internal/pkg/ratelimit"
+)
+
+func TestScaleOut_DirectMessageReachesOtherReplica(t *testing.T) {
+	replicaA, replicaB, jwtManager := startReplicas(t)
+
+	user, token := newUser(t, jwtManager)
+	messages := replicaB.connect(t, token)
+	replicaB.waitOnline(t, user.ID.String())
+	assert.False(t, replicaA.hub.IsUserOnline(user.ID.String()))
+
+	msg := awaitDelivery(t, messages, wsHandler.WSMessageTypeNotification, func() {
+		replicaA.hub.SendToUser(user.ID.String(), wsHandler.WSMessage{
+			Type:      wsHandler.WSMessageTypeNotification,
+			Data:      json.RawMessage(`{"title":"cross-replica"}`),
+			Timestamp: time.Now(),
+		})
+	})
+	assert.JSONEq(t, `{"title":"cross-replica"}`, string(msg.Data))
+}
+
+func TestScaleOut_BroadcastReachesEveryReplica(t *testing.T) {
+	replicaA, replicaB, jwtManager := startReplicas(t)
+
+	userA, tokenA := newUser(t, jwtManager)
+	userB, tokenB := newUser(t, jwtManager)
+	messagesA := replicaA.connect(t, tokenA)
+	messagesB := replicaB.connect(t, tokenB)
+	replicaA.waitOnline(t, userA.ID.String())
+	replicaB.waitOnline(t, userB.ID.String())
+
+	broadcast := func() {
+		replicaA.hub.Broadcast(wsHandler.WSMessage{
+			Type:      wsHandler.WSMessageTypeNewPost,
+			Data:      json.RawMessage(`{"post_id":"p1"}`),
+			Timestamp: time.Now(),
+		})
+	}
+	awaitDelivery(t, messagesB, wsHandler.WSMessageTypeNewPost, broadcast)
+	awaitDelivery(t, messagesA, wsHandler.WSMessageTypeNewPost, broadcast)
+}
+
+func TestScaleOut_RateLimitSharedAcrossReplicas(t *testing.T) {
+	replicaA, replicaB, _ := startReplicas(t)
+
+	ctx := context.Background()
+	rule := ratelimit.Rule{Name: "posts", Limit: 4, Window: time.Minute}
+	key := "user:" + uuid.NewString()
+
+	// Alternate replicas: the limit counts requests from both
+	for i := 0; i < rule.Limit; i++ {
+		limiter := replicaA.limiter
+		if i%2 == 1 {
+			limiter = replicaB.limiter
+		}
+		result, err := limiter.Allow(ctx, rule, key)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed, "request %d", i+1)
+	}
+
+	for _, r := range []*replica{replicaA, replicaB} {
+		result, err := r.limiter.Allow(ctx, rule, key)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Positive(t, result.RetryAfter)
+	}
+}
+
+func TestScaleOut_SessionRevocationSeenByOtherReplica(t *testing.T) {
+	replicaA, replicaB, jwtManager := startReplicas(t)
+
+	ctx := context.Background()
+	user, _ := newUser(t, jwtManager)
+	userID := user.ID.String()
+
+	refreshToken, err := jwtManager.GenerateRefreshToken(userID)
+	require.NoError(t, err)
+	require.NoError(t, replicaA.sessions.StoreRefreshToken(ctx, userID, refreshToken, time.Hour))
+
+	valid, err := replicaB.sessions.ValidateRefreshToken(ctx, userID, refreshToken)
+	require.NoError(t, err)
+	assert.True(t, valid, "session issued on one replica must be valid on the other")
+
+	require.NoError(t, replicaB.sessions.RevokeAllRefreshTokens(ctx, userID))
+
+	valid, err = replicaA.sessions.ValidateRefreshToken(ctx, userID, refreshToken)
+	require.NoError(t, err)
+	assert.False(t, valid, "session revoked on one replica must be rejected by the other")
+}